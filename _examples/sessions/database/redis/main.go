@@ -37,7 +37,11 @@ func main() {
 	// driver := redis.GoRedis()
 	// driver.ClientOptions = redis.Options{...}
 	// driver.ClusterOptions = redis.ClusterOptions{...}
+	// driver.FailoverOptions = redis.FailoverOptions{...}
 	// redis.New(redis.Config{Driver: driver, ...})
+	//
+	// Redis Sentinel:
+	// redis.New(redis.Config{MasterName: "mymaster", Sentinels: []string{":26379", ":26380"}, ...})
 
 	defer db.Close() // close the database connection if application errored.
 
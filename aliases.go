@@ -11,6 +11,7 @@ import (
 	"github.com/kataras/iris/v12/core/host"
 	"github.com/kataras/iris/v12/core/router"
 	"github.com/kataras/iris/v12/hero"
+	"github.com/kataras/iris/v12/middleware/tus"
 	"github.com/kataras/iris/v12/view"
 )
 
@@ -81,6 +82,54 @@ type (
 	//
 	// It is an alias of the `context#Problem` type.
 	Problem = context.Problem
+	// Clock is an interface which contains a single `Now` method,
+	// see `Application.SetClock` and `Context.Now` methods.
+	//
+	// It is an alias of the `context#Clock` type.
+	Clock = context.Clock
+	// HijackOptions holds the optional settings of the `Context.Hijack` method.
+	//
+	// It is an alias of the `context#HijackOptions` type.
+	HijackOptions = context.HijackOptions
+	// StreamJSONOptions holds the optional settings of the `Context.StreamJSON` method.
+	//
+	// It is an alias of the `context#StreamJSONOptions` type.
+	StreamJSONOptions = context.StreamJSONOptions
+	// ProblemErrors holds the options to translate error responses and recovered panics
+	// to RFC 7807 problem documents, see `Application.UseProblemErrors`.
+	//
+	// It is an alias of the `context#ProblemErrors` type.
+	ProblemErrors = context.ProblemErrors
+	// Fingerprint holds the identification information and the bot score of a client request,
+	// see `Context.Fingerprint` method and the "middleware/botdetect" package.
+	//
+	// It is an alias of the `context#Fingerprint` type.
+	Fingerprint = context.Fingerprint
+	// Baggage holds the W3C baggage (cross-service metadata) of a request,
+	// see `Context.Baggage` method and the "middleware/baggage" package.
+	//
+	// It is an alias of the `context#Baggage` type.
+	Baggage = context.Baggage
+	// ValidationErrors is a list of structured field validation errors,
+	// see `Context.StopWithReadError` method.
+	//
+	// It is an alias of the `context#ValidationErrors` type.
+	ValidationErrors = context.ValidationErrors
+	// ErrorCode describes a stable, application-level, error code,
+	// see `RegisterErrorCode` and `Context.StopWithErrorCode` method.
+	//
+	// It is an alias of the `context#ErrorCode` type.
+	ErrorCode = context.ErrorCode
+	// SecurityRequirement declares how the clients authenticate to a route,
+	// see `Route.Secure` and `Party.Secure` methods.
+	//
+	// It is an alias of the `context#SecurityRequirement` type.
+	SecurityRequirement = context.SecurityRequirement
+	// ErrorCatalog is the registry of the application's error codes,
+	// see `DefaultErrorCatalog`.
+	//
+	// It is an alias of the `context#ErrorCatalog` type.
+	ErrorCatalog = context.ErrorCatalog
 	// ProblemOptions the optional settings when server replies with a Problem.
 	// See `Context.Problem` method and `Problem` type for more details.
 	//
@@ -115,6 +164,9 @@ type (
 	// Used to add supervisor configurators on common Runners
 	// without the need of importing the `core/host` package.
 	Supervisor = host.Supervisor
+	// ReadyInfo describes a host which is ready to accept connections.
+	// A shortcut of the `host#ReadyInfo`, see `Application.OnReady` method.
+	ReadyInfo = host.ReadyInfo
 
 	// Party is just a group joiner of routes which have the same prefix and share same middleware(s) also.
 	// Party could also be named as 'Join' or 'Node' or 'Group' , Party chosen because it is fun.
@@ -146,6 +198,29 @@ type (
 	// Attachments options for files to be downloaded and saved locally by the client.
 	// See `DirOptions`.
 	Attachments = router.Attachments
+	// HybridOptions holds the optional settings of the `Party#HandleHybrid` method.
+	// A shortcut for the `router.HybridOptions`.
+	HybridOptions = router.HybridOptions
+	// AssetsOptions holds the optional settings of the `Party#HandleAssets` method.
+	// A shortcut for the `router.AssetsOptions`.
+	AssetsOptions = router.AssetsOptions
+	// RouteEvent holds the information of a route which went online or offline,
+	// see `Application.OnRouteChange` method.
+	// A shortcut for the `router.RouteEvent`.
+	RouteEvent = router.RouteEvent
+	// PolicyReport is a per-route effective policy matrix,
+	// see `Application.PolicyReport` method.
+	// A shortcut for the `router.PolicyReport`.
+	PolicyReport = router.PolicyReport
+	// TusOptions holds the optional settings of the `Party#Tus` method.
+	// A shortcut for the `router.TusOptions`.
+	TusOptions = router.TusOptions
+	// TusUpload holds the information of a resumable upload.
+	// A shortcut for the `router.TusUpload`.
+	TusUpload = router.TusUpload
+	// TusStore is the storage of the `Party#Tus` resumable uploads.
+	// A shortcut for the `router.TusStore`.
+	TusStore = router.TusStore
 	// Dir implements FileSystem using the native file system restricted to a
 	// specific directory tree, can be passed to the `FileServer` function
 	// and `HandleDir` method. It's an alias of `http.Dir`.
@@ -164,6 +239,11 @@ type (
 	//
 	// See `ExecutionRules` and `core/router/Party#SetExecutionRules` for more.
 	ExecutionOptions = router.ExecutionOptions
+	// FeatureFlag reports whether a group of routes should be part of the route table.
+	// See `Party.PartyIf` and `Party.HandleIf` methods.
+	//
+	// A shortcut for the `router.FeatureFlag`.
+	FeatureFlag = router.FeatureFlag
 
 	// CookieOption is the type of function that is accepted on
 	// context's methods like `SetCookieKV`, `RemoveCookie` and `SetCookie`
@@ -174,6 +254,15 @@ type (
 	//
 	// An alias for the `context.CookieOption`.
 	CookieOption = context.CookieOption
+	// CookieKey holds the signing and the optional encryption keys of a `CookieCodec` generation.
+	//
+	// It is an alias of the `context#CookieKey` type.
+	CookieKey = context.CookieKey
+	// CookieCodec signs and, optionally, encrypts cookie values with key rotation,
+	// see `NewCookieCodec`, `Application.SetCookieCodec` and `Context.SetSecureCookie`.
+	//
+	// It is an alias of the `context#CookieCodec` type.
+	CookieCodec = context.CookieCodec
 	// Cookie is a type alias for the standard net/http Cookie struct type.
 	// See `Context.SetCookie`.
 	Cookie = http.Cookie
@@ -224,6 +313,31 @@ const (
 	ReferrerGoogleAdwords       = context.ReferrerGoogleAdwords
 )
 
+// Contains the enum values of the `Fingerprint.Class` field,
+// shortcuts of the context subpackage.
+const (
+	BotUnknown = context.BotUnknown
+	BotHuman   = context.BotHuman
+	BotGood    = context.BotGood
+	BotBad     = context.BotBad
+)
+
+// Contains the names of the builtin HTML sanitization policies,
+// see `Context.SanitizeHTML`, shortcuts of the context subpackage.
+const (
+	SanitizeStrict   = context.SanitizeStrict
+	SanitizeComments = context.SanitizeComments
+	SanitizeRichText = context.SanitizeRichText
+)
+
+// Contains the schemes of a `SecurityRequirement`,
+// see `Route.Secure`, shortcuts of the context subpackage.
+const (
+	SecurityBearer = context.SecurityBearer
+	SecurityBasic  = context.SecurityBasic
+	SecurityAPIKey = context.SecurityAPIKey
+)
+
 // NoLayout to disable layout for a particular template file
 // A shortcut for the `view#NoLayout`.
 const NoLayout = view.NoLayout
@@ -254,6 +368,10 @@ var (
 	// Ace view engine.
 	// Shortcut of the view.Ace.
 	Ace = view.Ace
+	// MarkdownView is the Markdown view engine,
+	// the `Markdown` name is used by the Markdown renderer's options.
+	// Shortcut of the view.Markdown.
+	MarkdownView = view.Markdown
 )
 
 type (
@@ -304,11 +422,7 @@ var (
 	// Usage:
 	// app.Use (for matched routes)
 	// app.UseRouter (for both matched and 404s or other HTTP errors).
-	Compression = func(ctx Context) {
-		ctx.CompressWriter(true)
-		ctx.CompressReader(true)
-		ctx.Next()
-	}
+	Compression = compression
 
 	// MatchImagesAssets is a simple regex expression
 	// that can be passed to the DirOptions.Cache.CompressIgnore field
@@ -323,6 +437,16 @@ var (
 	MatchCommonAssets = regexp.MustCompile("((.*).js|(.*).css|(.*).ico|(.*).png|(.*).ttf|(.*).svg|(.*).webp|(.*).gif)$")
 )
 
+func compression(ctx Context) {
+	ctx.CompressWriter(true)
+	ctx.CompressReader(true)
+	ctx.Next()
+}
+
+func init() {
+	context.SetHandlerPolicy(context.HandlerName(Compression), context.PolicyCompression)
+}
+
 var (
 	// RegisterOnInterrupt registers a global function to call when CTRL+C/CMD+C pressed or a unix kill command received.
 	//
@@ -334,6 +458,16 @@ var (
 	//
 	// A shortcut for the `context#LimitRequestBodySize`.
 	LimitRequestBodySize = context.LimitRequestBodySize
+	// DecompressRequest is a middleware which transparently decompresses
+	// the request bodies of all next handlers in the chain, with a max decompressed size guard.
+	//
+	// A shortcut for the `context#DecompressRequest`.
+	DecompressRequest = context.DecompressRequest
+	// ExpectContinue is a middleware which rejects requests that expect a 100 Continue response
+	// before their body is transmitted, when the given "check" function returns a status code.
+	//
+	// A shortcut for the `context#ExpectContinue`.
+	ExpectContinue = context.ExpectContinue
 	// NewConditionalHandler returns a single Handler which can be registered
 	// as a middleware.
 	// Filter is just a type of Handler which returns a boolean.
@@ -380,6 +514,26 @@ var (
 	// app.Get("/static/{file:path}", h)
 	// app.Head("/static/{file:path}", h)
 	StripPrefix = router.StripPrefix
+	// NewTusDirStore returns a new `TusStore` which stores
+	// the `Party#Tus` resumable uploads to a directory.
+	// A shortcut for the `tus.NewDirStore` of the middleware/tus package.
+	NewTusDirStore = tus.NewDirStore
+	// Feature returns a FeatureFlag which reports the given "enabled" value.
+	// See `Party.PartyIf` and `Party.HandleIf` methods.
+	//
+	// A shortcut for the `router.Feature`.
+	Feature = router.Feature
+	// FeatureEnv returns a FeatureFlag which reports whether
+	// the "key" environment variable holds a true value.
+	// See `Party.PartyIf` and `Party.HandleIf` methods.
+	//
+	// A shortcut for the `router.FeatureEnv`.
+	FeatureEnv = router.FeatureEnv
+	// FeatureAll returns a FeatureFlag which reports true
+	// only when all of the given flags report true.
+	//
+	// A shortcut for the `router.FeatureAll`.
+	FeatureAll = router.FeatureAll
 	// FromStd converts native http.Handler, http.HandlerFunc & func(w, r, next) to context.Handler.
 	//
 	// Supported form types:
@@ -439,6 +593,14 @@ var (
 	//
 	// A shortcut of the `cache#Cache304`.
 	Cache304 = cache.Cache304
+	// AutoETag is a middleware which sets the "ETag" response header
+	// to a strong (or weak if "weak" is true) entity tag of the response body
+	// and sends a `StatusNotModified` (304) when the client's cached copy is still fresh.
+	//
+	// Usage: `app.Party("/api", iris.AutoETag(false))`.
+	//
+	// A shortcut of the `cache#AutoETag`.
+	AutoETag = cache.AutoETag
 
 	// CookieAllowReclaim accepts the Context itself.
 	// If set it will add the cookie to (on `CookieSet`, `CookieSetKV`, `CookieUpsert`)
@@ -463,6 +625,18 @@ var (
 	//
 	// A shortcut for the `context#CookieSameSite`.
 	CookieSameSite = context.CookieSameSite
+	// CookieSameSiteNone is a `CookieOption`.
+	// Use it to allow the cookie to be sent on cross-site requests,
+	// it sets the Secure field too.
+	//
+	// A shortcut for the `context#CookieSameSiteNone`.
+	CookieSameSiteNone = context.CookieSameSiteNone
+	// CookiePartitioned is a `CookieOption`.
+	// Use it to add the Partitioned attribute (CHIPS) to the cookie,
+	// it sets the Secure field too.
+	//
+	// A shortcut for the `context#CookiePartitioned`.
+	CookiePartitioned = context.CookiePartitioned
 	// CookieSecure sets the cookie's Secure option if the current request's
 	// connection is using TLS. See `CookieHTTPOnly` too.
 	//
@@ -495,6 +669,11 @@ var (
 	//
 	// A shortcut for the `context#CookieEncoding`.
 	CookieEncoding = context.CookieEncoding
+	// NewCookieCodec returns a new cookie codec which signs and, optionally, encrypts cookie values.
+	// The first key generation encodes the values, all of them are accepted to decode.
+	//
+	// A shortcut for the `context#NewCookieCodec`.
+	NewCookieCodec = context.NewCookieCodec
 
 	// IsErrEmptyJSON reports whether the given "err" is caused by a
 	// Context.ReadJSON call when the request body
@@ -518,6 +697,29 @@ var (
 	// on post data, versioning feature and others.
 	// An alias of `context.ErrNotFound`.
 	ErrNotFound = context.ErrNotFound
+	// NewMockClock returns a new Clock frozen at the given time,
+	// it can be advanced manually. See `Application.SetClock` method.
+	//
+	// A shortcut for the `context#NewMockClock`.
+	NewMockClock = context.NewMockClock
+	// AsValidationErrors reports whether an error is a validation failure
+	// and returns its structured field errors.
+	//
+	// A shortcut for the `context#AsValidationErrors`.
+	AsValidationErrors = context.AsValidationErrors
+	// RegisterErrorCode registers an error code to the `DefaultErrorCatalog`.
+	//
+	// A shortcut for the `context#RegisterErrorCode`.
+	RegisterErrorCode = context.RegisterErrorCode
+	// AsErrorCode returns the error code of an error, if any.
+	//
+	// A shortcut for the `context#AsErrorCode`.
+	AsErrorCode = context.AsErrorCode
+	// DefaultErrorCatalog is the application-wide error code catalog,
+	// serve it through its `Handler` method.
+	//
+	// A shortcut for the `context#DefaultErrorCatalog`.
+	DefaultErrorCatalog = context.DefaultErrorCatalog
 	// NewProblem returns a new Problem.
 	// Head over to the `Problem` type godoc for more.
 	//
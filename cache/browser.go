@@ -1,6 +1,7 @@
 package cache
 
 import (
+	"net/http"
 	"strconv"
 	"time"
 
@@ -8,6 +9,12 @@ import (
 	"github.com/kataras/iris/v12/context"
 )
 
+func init() {
+	for _, h := range []context.Handler{NoCache, StaticCache(0), ETag, AutoETag(false), Cache304(0)} {
+		context.SetHandlerPolicy(context.HandlerName(h), context.PolicyCache)
+	}
+}
+
 // CacheControlHeaderValue is the header value of the
 // "Cache-Control": "private, no-cache, max-age=0, must-revalidate, no-store, proxy-revalidate, s-maxage=0".
 //
@@ -60,7 +67,7 @@ var StaticCache = func(cacheDur time.Duration) context.Handler {
 
 	cacheControlHeaderValue := "public, max-age=" + strconv.Itoa(int(cacheDur.Seconds()))
 	return func(ctx *context.Context) {
-		cacheUntil := time.Now().Add(cacheDur).Format(ctx.Application().ConfigurationReadOnly().GetTimeFormat())
+		cacheUntil := ctx.Now().Add(cacheDur).Format(ctx.Application().ConfigurationReadOnly().GetTimeFormat())
 		ctx.Header(ExpiresHeaderKey, cacheUntil)
 		ctx.Header(context.CacheControlHeaderKey, cacheControlHeaderValue)
 
@@ -108,6 +115,46 @@ var ETag = func(ctx *context.Context) {
 	ctx.Next()
 }
 
+// AutoETag is a middleware which records the responses of the next handlers
+// and sets their "ETag" header to the `context#ComputeETag` of the body,
+// a weak one if "weak" is true, unless a handler has already set it.
+// It sends a `StatusNotModified` (304) without the body
+// when the "If-None-Match" or the "If-Modified-Since" request headers
+// match the response, see `context#IsNotModified`.
+//
+// Only "200 OK" responses of "GET" and "HEAD" requests are processed.
+// It can be registered per Party, usually on read-heavy APIs:
+// api := app.Party("/api", cache.AutoETag(false))
+//
+// See `context#WriteWithETag` to process a single response instead.
+var AutoETag = func(weak bool) context.Handler {
+	return func(ctx *context.Context) {
+		if method := ctx.Method(); method != http.MethodGet && method != http.MethodHead {
+			ctx.Next()
+			return
+		}
+
+		ctx.Record()
+		ctx.Next()
+
+		rec, ok := ctx.IsRecording()
+		if !ok || rec.StatusCode() != http.StatusOK {
+			return
+		}
+
+		etag := rec.Header().Get(context.ETagHeaderKey)
+		if etag == "" {
+			etag = context.ComputeETag(rec.Body(), weak)
+			rec.Header().Set(context.ETagHeaderKey, etag)
+		}
+
+		if ctx.IsNotModified(etag) {
+			rec.ResetBody()
+			ctx.WriteNotModified()
+		}
+	}
+}
+
 // Cache304 sends a `StatusNotModified` (304) whenever
 // the "If-Modified-Since" request header (time) is before the
 // time.Now() + expiresEvery (always compared to their UTC values).
@@ -127,7 +174,7 @@ var ETag = func(ctx *context.Context) {
 // i.e `HandleDir`.
 var Cache304 = func(expiresEvery time.Duration) context.Handler {
 	return func(ctx *context.Context) {
-		now := time.Now()
+		now := ctx.Now()
 		if modified, err := ctx.CheckIfModifiedSince(now.Add(-expiresEvery)); !modified && err == nil {
 			ctx.WriteNotModified()
 			return
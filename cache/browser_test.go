@@ -75,6 +75,32 @@ func TestCache304(t *testing.T) {
 	r.Body().Equal("send")
 }
 
+func TestCache304MockClock(t *testing.T) {
+	app := iris.New()
+	clock := iris.NewMockClock(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC))
+	app.SetClock(clock)
+
+	expiresEvery := time.Hour
+	app.Get("/", cache.Cache304(expiresEvery), func(ctx iris.Context) {
+		ctx.WriteString(ctx.Now().Format(time.RFC3339))
+	})
+
+	e := httptest.New(t, app)
+	timeFormat := app.ConfigurationReadOnly().GetTimeFormat()
+
+	r := e.GET("/").Expect().Status(httptest.StatusOK)
+	r.Body().Equal("2021-01-01T00:00:00Z")
+	lastModified := r.Header(context.LastModifiedHeaderKey).Equal(clock.Now().Format(timeFormat)).Raw()
+
+	clock.Advance(30 * time.Minute)
+	e.GET("/").WithHeader(context.IfModifiedSinceHeaderKey, lastModified).Expect().
+		Status(httptest.StatusNotModified)
+
+	clock.Advance(31 * time.Minute)
+	e.GET("/").WithHeader(context.IfModifiedSinceHeaderKey, lastModified).Expect().
+		Status(httptest.StatusOK).Body().Equal("2021-01-01T01:01:00Z")
+}
+
 func TestETag(t *testing.T) {
 	// t.Parallel()
 
@@ -100,3 +126,47 @@ func TestETag(t *testing.T) {
 	r.Header("ETag").Equal("/") // test if header set.
 	r.Body().Equal("__")
 }
+
+func TestAutoETag(t *testing.T) {
+	app := iris.New()
+	api := app.Party("/api", cache.AutoETag(false))
+	api.Get("/", func(ctx iris.Context) {
+		ctx.WriteString("data")
+	})
+	api.Get("/modified", func(ctx iris.Context) {
+		ctx.SetLastModified(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC))
+		ctx.WriteString("data")
+	})
+	api.Post("/", func(ctx iris.Context) {
+		ctx.WriteString("created")
+	})
+	app.Get("/single", func(ctx iris.Context) {
+		ctx.WriteWithETag([]byte("single"))
+	})
+
+	e := httptest.New(t, app)
+	etag := context.ComputeETag([]byte("data"), false)
+
+	e.GET("/api").Expect().Status(httptest.StatusOK).Header(context.ETagHeaderKey).Equal(etag)
+	r := e.GET("/api").WithHeader(context.IfNoneMatchHeaderKey, etag).Expect().Status(httptest.StatusNotModified)
+	r.Header(context.ETagHeaderKey).Equal(etag)
+	r.Body().Empty()
+	// Weak comparison and a list of entity tags.
+	e.GET("/api").WithHeader(context.IfNoneMatchHeaderKey, `"other", W/`+etag).Expect().Status(httptest.StatusNotModified)
+	e.GET("/api").WithHeader(context.IfNoneMatchHeaderKey, `"other"`).Expect().Status(httptest.StatusOK).Body().Equal("data")
+	e.POST("/api").WithHeader(context.IfNoneMatchHeaderKey, "*").Expect().Status(httptest.StatusOK).
+		Body().Equal("created")
+
+	e.GET("/api/modified").WithHeader(context.IfModifiedSinceHeaderKey, "Fri, 01 Jan 2021 00:00:00 GMT").
+		Expect().Status(httptest.StatusNotModified)
+	// If-None-Match takes precedence.
+	e.GET("/api/modified").WithHeader(context.IfModifiedSinceHeaderKey, "Fri, 01 Jan 2021 00:00:00 GMT").
+		WithHeader(context.IfNoneMatchHeaderKey, `"other"`).Expect().Status(httptest.StatusOK).Body().Equal("data")
+
+	singleETag := e.GET("/single").Expect().Status(httptest.StatusOK).Header(context.ETagHeaderKey).Raw()
+	e.GET("/single").WithHeader(context.IfNoneMatchHeaderKey, singleETag).Expect().Status(httptest.StatusNotModified)
+
+	if weak := context.ComputeETag([]byte("data"), true); weak != "W/"+etag {
+		t.Fatalf("expected weak etag: W/%s but got: %s", etag, weak)
+	}
+}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/kataras/iris/v12"
 	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/kv"
 
 	"github.com/iris-contrib/httpexpect/v2"
 	"github.com/kataras/iris/v12/httptest"
@@ -145,6 +146,39 @@ func TestCache(t *testing.T) {
 	}
 }
 
+func TestCacheStore(t *testing.T) {
+	app := iris.New()
+	var n uint32
+
+	store := kv.NewMemory()
+	h := cache.Cache(cacheDuration).Store(store)
+	app.Get("/", h.ServeHTTP, func(ctx *context.Context) {
+		atomic.AddUint32(&n, 1)
+		ctx.Write([]byte(expectedBodyStr))
+	})
+
+	e := httptest.New(t, app)
+	if err := runTest(e, "/", &n, expectedBodyStr, ""); err != nil {
+		t.Fatalf(t.Name()+": %v", err)
+	}
+
+	stored := 0
+	store.Iterate(client.StoreKeyPrefix, func(string, []byte) bool {
+		stored++
+		return true
+	})
+	if stored != 1 {
+		t.Fatalf("expected one stored response but got %d", stored)
+	}
+
+	expected := atomic.LoadUint32(&n) + 1
+	h.InvalidateFunc(nil)
+	e.GET("/").Expect().Status(http.StatusOK).Body().Equal(expectedBodyStr)
+	if counter := atomic.LoadUint32(&n); counter != expected {
+		t.Fatalf("expected the main handler to be executed after the invalidation, %d times instead of %d", expected, counter)
+	}
+}
+
 // This works but we have issue on golog.SetLevel and get golog.Level on httptest.New
 // when tests are running in parallel and the loggers are used.
 // // TODO: Fix it on golog repository or here, we'll see.
@@ -1,6 +1,8 @@
 package client
 
 import (
+	"encoding/json"
+	"net/http"
 	"strings"
 	"sync"
 	"time"
@@ -8,10 +10,12 @@ import (
 	"github.com/kataras/iris/v12/cache/client/rule"
 	"github.com/kataras/iris/v12/cache/entry"
 	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/kv"
 )
 
 func init() {
 	context.SetHandlerName("iris/cache/client.(*Handler).ServeHTTP-fm", "iris.cache")
+	context.SetHandlerPolicy("iris.cache", context.PolicyCache)
 }
 
 // Handler the local cache service handler contains
@@ -27,6 +31,8 @@ type Handler struct {
 	// entries the memory cache stored responses.
 	entries map[string]*entry.Entry
 	mu      sync.RWMutex
+	// store keeps the responses instead of the entries, see `Store`.
+	store kv.Store
 }
 
 // NewHandler returns a new cached handler for the "bodyHandler"
@@ -52,6 +58,18 @@ func (h *Handler) Rule(r rule.Rule) *Handler {
 	return h
 }
 
+// StoreKeyPrefix is the prefix of the response keys on a `Handler.Store`.
+var StoreKeyPrefix = "cache:"
+
+// Store sets a key-value store, e.g. a kv/boltdb one, to keep the cached responses
+// instead of the memory, so they survive a restart.
+//
+// returns itself.
+func (h *Handler) Store(store kv.Store) *Handler {
+	h.store = store
+	return h
+}
+
 // AddRule adds a rule in the chain, the default rules are executed first.
 //
 // returns itself.
@@ -116,6 +134,56 @@ func getOrSetKey(ctx *context.Context) string {
 	return key
 }
 
+// Invalidate removes the cached entries of the given keys,
+// see `SetKey` and `InvalidateFunc` too.
+func (h *Handler) Invalidate(keys ...string) {
+	if h.store != nil {
+		for _, key := range keys {
+			h.store.Delete(StoreKeyPrefix + key) // nolint:errcheck
+		}
+		return
+	}
+
+	h.mu.Lock()
+	for _, key := range keys {
+		delete(h.entries, key)
+	}
+	h.mu.Unlock()
+}
+
+// InvalidateFunc removes the cached entries that their keys pass the "match" function.
+// If "match" is nil then all entries are removed.
+func (h *Handler) InvalidateFunc(match func(key string) bool) {
+	if h.store != nil {
+		var keys []string
+		h.store.Iterate(StoreKeyPrefix, func(key string, _ []byte) bool { // nolint:errcheck
+			if key = strings.TrimPrefix(key, StoreKeyPrefix); match == nil || match(key) {
+				keys = append(keys, key)
+			}
+			return true
+		})
+
+		h.Invalidate(keys...)
+		return
+	}
+
+	h.mu.Lock()
+	for key := range h.entries {
+		if match == nil || match(key) {
+			delete(h.entries, key)
+		}
+	}
+	h.mu.Unlock()
+}
+
+// Wrap returns a handler which caches the responses of the given "bodyHandler",
+// instead of the next handler in the chain like `ServeHTTP` does.
+func (h *Handler) Wrap(bodyHandler context.Handler) context.Handler {
+	return func(ctx *context.Context) {
+		h.serve(ctx, bodyHandler)
+	}
+}
+
 func (h *Handler) ServeHTTP(ctx *context.Context) {
 	// check for pre-cache validators, if at least one of them return false
 	// for this specific request, then skip the whole cache
@@ -128,6 +196,10 @@ func (h *Handler) ServeHTTP(ctx *context.Context) {
 	// even if it's not executed because it's cached.
 	ctx.Skip()
 
+	h.serve(ctx, bodyHandler)
+}
+
+func (h *Handler) serve(ctx *context.Context, bodyHandler context.Handler) {
 	if !h.rule.Claim(ctx) {
 		bodyHandler(ctx)
 		return
@@ -140,6 +212,11 @@ func (h *Handler) ServeHTTP(ctx *context.Context) {
 		key = getOrSetKey(ctx)
 	)
 
+	if h.store != nil {
+		h.serveStore(ctx, key, bodyHandler)
+		return
+	}
+
 	h.mu.RLock()
 	e, found := h.entries[key]
 	h.mu.RUnlock()
@@ -209,3 +286,56 @@ func (h *Handler) ServeHTTP(ctx *context.Context) {
 	// fmt.Printf("write content type: %s\n", response.Headers()["ContentType"])
 	// fmt.Printf("write body len: %d\n", len(response.Body()))
 }
+
+// storedResponse is the response kept on a `Handler.Store`.
+type storedResponse struct {
+	StatusCode   int         `json:"statusCode"`
+	Headers      http.Header `json:"headers,omitempty"`
+	Body         []byte      `json:"body"`
+	LastModified time.Time   `json:"lastModified"`
+}
+
+func (h *Handler) serveStore(ctx *context.Context, key string, bodyHandler context.Handler) {
+	if b, err := h.store.Get(StoreKeyPrefix + key); err == nil {
+		var response storedResponse
+		if err = json.Unmarshal(b, &response); err == nil {
+			entry.CopyHeaders(ctx.ResponseWriter().Header(), response.Headers)
+			ctx.SetLastModified(response.LastModified)
+			ctx.StatusCode(response.StatusCode)
+			ctx.Write(response.Body)
+			return
+		}
+	}
+
+	recorder := ctx.Recorder()
+	bodyHandler(ctx)
+
+	if !h.rule.Valid(ctx) {
+		return
+	}
+
+	body := recorder.Body()
+	if len(body) == 0 {
+		return
+	}
+
+	// the entry computes the lifetime of the response, see `parseLifeChanger`.
+	e := entry.NewEntry(h.expiration)
+	e.Reset(recorder.StatusCode(), recorder.Header(), body, parseLifeChanger(ctx))
+
+	response, _ := e.Response()
+	b, err := json.Marshal(storedResponse{
+		StatusCode:   response.StatusCode(),
+		Headers:      response.Headers(),
+		Body:         response.Body(),
+		LastModified: e.LastModified,
+	})
+	if err != nil {
+		ctx.Application().Logger().Errorf("cache: %s: %v", key, err)
+		return
+	}
+
+	if err = h.store.Set(StoreKeyPrefix+key, b, time.Until(e.ExpiresAt())); err != nil {
+		ctx.Application().Logger().Errorf("cache: %s: %v", key, err)
+	}
+}
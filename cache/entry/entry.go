@@ -52,6 +52,12 @@ func (e *Entry) Response() (*Response, bool) {
 	return e.response, true
 }
 
+// ExpiresAt returns the time which this entry's response expires,
+// it's set on `Reset`.
+func (e *Entry) ExpiresAt() time.Time {
+	return e.expiresAt
+}
+
 // valid returns true if this entry's response is still valid
 // or false if the expiration time passed
 func (e *Entry) valid() bool {
@@ -1,8 +1,10 @@
 package iris
 
 import (
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"os"
 	"os/user"
 	"path/filepath"
@@ -238,6 +240,14 @@ var WithoutStartupLog = func(app *Application) {
 	app.config.DisableStartupLog = true
 }
 
+// WithStartupLogJSON writes a machine-readable (JSON) startup summary,
+// including the actual bound address, instead of the human-format one.
+//
+// See `Configuration.StartupLogJSON` and `Application.OnReady` too.
+var WithStartupLogJSON = func(app *Application) {
+	app.config.StartupLogJSON = true
+}
+
 // WithoutBanner is a conversion for the `WithoutStartupLog` option.
 //
 // Turns off the information send, once, to the terminal when the main server is open.
@@ -314,6 +324,21 @@ var WithFireMethodNotAllowed = func(app *Application) {
 	app.config.FireMethodNotAllowed = true
 }
 
+// WithFailOnRouteConflicts enables the FailOnRouteConflicts setting.
+//
+// See `Configuration`.
+var WithFailOnRouteConflicts = func(app *Application) {
+	app.config.FailOnRouteConflicts = true
+}
+
+// WithStrictMode enables the StrictMode setting.
+// Use it on development only.
+//
+// See `Configuration`.
+var WithStrictMode = func(app *Application) {
+	app.config.StrictMode = true
+}
+
 // WithoutAutoFireStatusCode sets the DisableAutoFireStatusCode setting to true.
 //
 // See `Configuration`.
@@ -404,6 +429,15 @@ func WithRemoteAddrPrivateSubnet(startIP, endIP string) Configurator {
 	}
 }
 
+// WithTrustedProxies appends the given CIDRs (or single IPs)
+// to the TrustedProxies list.
+// Look `Context.RealIP()` for more.
+func WithTrustedProxies(proxies ...string) Configurator {
+	return func(app *Application) {
+		app.config.TrustedProxies = append(app.config.TrustedProxies, proxies...)
+	}
+}
+
 // WithSSLProxyHeader sets a SSLProxyHeaders key value pair.
 // Example: WithSSLProxyHeader("X-Forwarded-Proto", "https").
 // See `Context.IsSSL` for more.
@@ -593,6 +627,8 @@ type Configuration struct {
 	// vhost is private and set only with .Run/Listen methods, it cannot be changed after the first set.
 	// It can be retrieved by the context if needed (i.e router for subdomains)
 	vhost string
+	// trustedProxyNets are the parsed TrustedProxies, set on the Application's Build.
+	trustedProxyNets []*net.IPNet
 
 	// LogLevel is the log level the application should use to output messages.
 	// Logger, by default, is mostly used on Build state but it is also possible
@@ -647,6 +683,12 @@ type Configuration struct {
 	//
 	// Defaults to false.
 	DisableStartupLog bool `ini:"disable_startup_log" json:"disableStartupLog,omitempty" yaml:"DisableStartupLog" toml:"DisableStartupLog"`
+	// StartupLogJSON if set to true then the startup log is a single JSON line,
+	// containing the actual bound address of the server, the listening URL and the process id,
+	// instead of the human-format banner. Useful for orchestration tools.
+	//
+	// Defaults to false.
+	StartupLogJSON bool `ini:"startup_log_json" json:"startupLogJSON,omitempty" yaml:"StartupLogJSON" toml:"StartupLogJSON"`
 	// DisableInterruptHandler if set to true then it disables the automatic graceful server shutdown
 	// when control/cmd+C pressed.
 	// Turn this to true if you're planning to handle this by your own via a custom host.Task.
@@ -699,6 +741,27 @@ type Configuration struct {
 	//  fires the 405 error instead of 404
 	// Defaults to false.
 	FireMethodNotAllowed bool `ini:"fire_method_not_allowed" json:"fireMethodNotAllowed,omitempty" yaml:"FireMethodNotAllowed" toml:"FireMethodNotAllowed"`
+	// FailOnRouteConflicts if it's true the router fails to build
+	// when routes conflict with each other, i.e. duplicate registrations of the same
+	// method, host and path (including trailing slash variants), routes which are unreachable
+	// because of a later one with the same path and equal or wider path parameters
+	// and shadowed wildcard routes. Otherwise these conflicts are logged as warnings.
+	// The errors point to the source file and line of both routes.
+	//
+	// Defaults to false.
+	FailOnRouteConflicts bool `ini:"fail_on_route_conflicts" json:"failOnRouteConflicts,omitempty" yaml:"FailOnRouteConflicts" toml:"FailOnRouteConflicts"`
+	// StrictMode if it's true the common misuses of the Context are detected
+	// at serve-time and logged as warnings, once per route, with a hint of the fix:
+	// writing after the request's deadline exceeded,
+	// using the Context (e.g. from a goroutine) after the handler returned,
+	// modifying the response headers after they were sent to the client and
+	// a handler which did not call ctx.Next() so the route's done handlers were not executed.
+	//
+	// The Contexts and their response writers are not re-used on strict mode,
+	// so it should be enabled on development only.
+	//
+	// Defaults to false.
+	StrictMode bool `ini:"strict_mode" json:"strictMode,omitempty" yaml:"StrictMode" toml:"StrictMode"`
 	// DisableAutoFireStatusCode if true then it turns off the http error status code
 	// handler automatic execution on error code from a `Context.StatusCode` call.
 	// By-default a custom http error handler will be fired when "Context.StatusCode(errorCode)" called.
@@ -874,6 +937,17 @@ type Configuration struct {
 	//
 	// Look `Context.RemoteAddr()` for more.
 	RemoteAddrPrivateSubnets []netutil.IPRange `ini:"remote_addr_private_subnets" json:"remoteAddrPrivateSubnets" yaml:"RemoteAddrPrivateSubnets" toml:"RemoteAddrPrivateSubnets"`
+	// TrustedProxies is a list of CIDRs (or single IPs) of the proxies,
+	// e.g. load balancers, which are trusted to report the client's IP.
+	// When not empty, the `Context.RemoteAddr()` resolves the client's IP
+	// through the `Context.RealIP()` instead of the RemoteAddrHeaders,
+	// so the "Forwarded", "X-Forwarded-For" and "X-Real-Ip" headers
+	// are only read when the request comes from a trusted peer.
+	//
+	// Example: []string{"10.0.0.0/8", "192.168.1.10"}.
+	//
+	// Defaults to empty slice.
+	TrustedProxies []string `ini:"trusted_proxies" json:"trustedProxies,omitempty" yaml:"TrustedProxies" toml:"TrustedProxies"`
 	// SSLProxyHeaders defines the set of header key values
 	// that would indicate a valid https Request (look `Context.IsSSL()`).
 	// Example: `map[string]string{"X-Forwarded-Proto": "https"}`.
@@ -943,6 +1017,16 @@ func (c Configuration) GetFireMethodNotAllowed() bool {
 	return c.FireMethodNotAllowed
 }
 
+// GetFailOnRouteConflicts returns the FailOnRouteConflicts field.
+func (c Configuration) GetFailOnRouteConflicts() bool {
+	return c.FailOnRouteConflicts
+}
+
+// GetStrictMode returns the StrictMode field.
+func (c Configuration) GetStrictMode() bool {
+	return c.StrictMode
+}
+
 // GetEnableOptimizations returns the EnableOptimizations.
 func (c Configuration) GetEnableOptimizations() bool {
 	return c.EnableOptimizations
@@ -1048,6 +1132,17 @@ func (c Configuration) GetRemoteAddrPrivateSubnets() []netutil.IPRange {
 	return c.RemoteAddrPrivateSubnets
 }
 
+// GetTrustedProxies returns the TrustedProxies field.
+func (c Configuration) GetTrustedProxies() []string {
+	return c.TrustedProxies
+}
+
+// GetTrustedProxyNets returns the parsed TrustedProxies,
+// it's nil before the Application's Build.
+func (c Configuration) GetTrustedProxyNets() []*net.IPNet {
+	return c.trustedProxyNets
+}
+
 // GetHostProxyHeaders returns the HostProxyHeaders field.
 func (c Configuration) GetHostProxyHeaders() map[string]bool {
 	return c.HostProxyHeaders
@@ -1099,6 +1194,10 @@ func WithConfiguration(c Configuration) Configurator {
 			main.DisableStartupLog = v
 		}
 
+		if v := c.StartupLogJSON; v {
+			main.StartupLogJSON = v
+		}
+
 		if v := c.DisableInterruptHandler; v {
 			main.DisableInterruptHandler = v
 		}
@@ -1131,6 +1230,14 @@ func WithConfiguration(c Configuration) Configurator {
 			main.FireMethodNotAllowed = v
 		}
 
+		if v := c.FailOnRouteConflicts; v {
+			main.FailOnRouteConflicts = v
+		}
+
+		if v := c.StrictMode; v {
+			main.StrictMode = v
+		}
+
 		if v := c.DisableAutoFireStatusCode; v {
 			main.DisableAutoFireStatusCode = v
 		}
@@ -1204,6 +1311,10 @@ func WithConfiguration(c Configuration) Configurator {
 			main.RemoteAddrPrivateSubnets = v
 		}
 
+		if v := c.TrustedProxies; len(v) > 0 {
+			main.TrustedProxies = v
+		}
+
 		if v := c.SSLProxyHeaders; len(v) > 0 {
 			if main.SSLProxyHeaders == nil {
 				main.SSLProxyHeaders = make(map[string]string, len(v))
@@ -1240,11 +1351,14 @@ func DefaultConfiguration() Configuration {
 		SocketSharding:                    false,
 		KeepAlive:                         0,
 		DisableStartupLog:                 false,
+		StartupLogJSON:                    false,
 		DisableInterruptHandler:           false,
 		DisablePathCorrection:             false,
 		EnablePathEscape:                  false,
 		ForceLowercaseRouting:             false,
 		FireMethodNotAllowed:              false,
+		FailOnRouteConflicts:              false,
+		StrictMode:                        false,
 		DisableBodyConsumptionOnUnmarshal: false,
 		FireEmptyFormError:                false,
 		DisableAutoFireStatusCode:         false,
@@ -1300,3 +1414,106 @@ func DefaultConfiguration() Configuration {
 		Other:               make(map[string]interface{}),
 	}
 }
+
+// Describable can be completed by middleware (or any other value)
+// to describe their effective options on the `Application.ExportConfig` method.
+type Describable interface {
+	// Describe returns the options, a value which can be encoded to YAML and JSON.
+	Describe() interface{}
+}
+
+type describable struct {
+	name  string
+	value Describable
+}
+
+// AddDescribable registers a value (e.g. a middleware's instance)
+// which describes its options on the `ExportConfig` method under the given "name".
+// Configuration's Other values and dependencies (see `ConfigureContainer`)
+// which complete the `Describable` interface are described automatically.
+//
+// Usage:
+//  ac := accesslog.File("./access.log")
+//  app.AddDescribable("accesslog", ac)
+//  app.UseRouter(ac.Handler)
+//
+// Returns itself.
+func (app *Application) AddDescribable(name string, d Describable) *Application {
+	app.mu.Lock()
+	app.describables = append(app.describables, describable{name: name, value: d})
+	app.mu.Unlock()
+
+	return app
+}
+
+// ExportedConfig is the result of the `Application.ExportConfig` method.
+type ExportedConfig struct {
+	Configuration Configuration `json:"configuration" yaml:"Configuration"`
+	// Middleware holds the options of the `Describable` values, by name.
+	Middleware map[string]interface{} `json:"middleware,omitempty" yaml:"Middleware,omitempty"`
+}
+
+// marshalYAML is the yaml.Marshal but it returns an error
+// instead of panicking on values that cannot be encoded, e.g. functions.
+func marshalYAML(v interface{}) (b []byte, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("export config: yaml: %v", r)
+		}
+	}()
+
+	return yaml.Marshal(v)
+}
+
+// exportConfigRedactMask replaces the Configuration's Other values
+// which are not `Describable` on the `Application.ExportConfig` method.
+const exportConfigRedactMask = "[REDACTED]"
+
+// ExportConfig returns the full effective configuration of the Application,
+// including the options of the registered `Describable` values (see `AddDescribable`),
+// encoded in the given "format", "yaml" (or "yml") or "json".
+// Useful for documentation and for drift comparison across environments.
+// The Configuration's Other values which are not `Describable`
+// are exported as "[REDACTED]", as they may hold secrets (e.g. keys and tokens).
+//
+// Usage:
+//  b, err := app.ExportConfig("yaml")
+func (app *Application) ExportConfig(format string) ([]byte, error) {
+	c := *app.config
+	middleware := make(map[string]interface{})
+
+	if len(c.Other) > 0 {
+		other := make(map[string]interface{}, len(c.Other))
+		for k, v := range c.Other {
+			if d, ok := v.(Describable); ok {
+				other[k] = d.Describe()
+			} else {
+				other[k] = exportConfigRedactMask
+			}
+		}
+		c.Other = other
+	}
+
+	for _, dep := range app.APIBuilder.ConfigureContainer().Container.Dependencies {
+		if d, ok := dep.OriginalValue.(Describable); ok {
+			middleware[fmt.Sprintf("%T", dep.OriginalValue)] = d.Describe()
+		}
+	}
+
+	app.mu.Lock()
+	for _, d := range app.describables {
+		middleware[d.name] = d.value.Describe()
+	}
+	app.mu.Unlock()
+
+	exported := ExportedConfig{Configuration: c, Middleware: middleware}
+
+	switch strings.ToLower(format) {
+	case "yaml", "yml":
+		return marshalYAML(exported)
+	case "json":
+		return json.MarshalIndent(exported, "", "  ")
+	default:
+		return nil, fmt.Errorf("export config: unsupported format: %q", format)
+	}
+}
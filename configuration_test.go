@@ -358,3 +358,61 @@ RemoteAddrHeaders = ["X-Real-Ip", "X-Forwarded-For", "CF-Connecting-IP"]
 		t.Fatalf("error on TestConfigurationTOML: Expected Other['MyServerName'] %s but got %s", expected, got)
 	}
 }
+
+type testDescribable struct {
+	limit int
+}
+
+func (d testDescribable) Describe() interface{} {
+	return map[string]int{"Limit": d.limit}
+}
+
+func TestConfigurationExport(t *testing.T) {
+	app := New().Configure(WithCharset("UTF-16"), WithOtherValue("quota", testDescribable{limit: 5}),
+		WithOtherValue("jwt_secret", []byte("secret")))
+	app.AddDescribable("limiter", testDescribable{limit: 10})
+
+	b, err := app.ExportConfig("yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var exported struct {
+		Configuration Configuration             `yaml:"Configuration"`
+		Middleware    map[string]map[string]int `yaml:"Middleware"`
+	}
+	if err = yaml.Unmarshal(b, &exported); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := "UTF-16", exported.Configuration.Charset; expected != got {
+		t.Fatalf("expected charset: %s but got: %s", expected, got)
+	}
+	if expected, got := 10, exported.Middleware["limiter"]["Limit"]; expected != got {
+		t.Fatalf("expected limiter's limit: %d but got: %d", expected, got)
+	}
+	if expected, got := map[string]interface{}{"Limit": 5}, exported.Configuration.Other["quota"]; !reflect.DeepEqual(expected, got) {
+		t.Fatalf("expected described other value: %#v but got: %#v", expected, got)
+	}
+	if expected, got := "[REDACTED]", exported.Configuration.Other["jwt_secret"]; expected != got {
+		t.Fatalf("expected redacted other value: %s but got: %#v", expected, got)
+	}
+
+	if _, err = app.ExportConfig("json"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = app.ExportConfig("ini"); err == nil {
+		t.Fatal("expected unsupported format error")
+	}
+
+	app.AddDescribable("hook", testFuncDescribable{})
+	if _, err = app.ExportConfig("yaml"); err == nil {
+		t.Fatal("expected an error on a value that cannot be encoded")
+	}
+}
+
+type testFuncDescribable struct{}
+
+func (testFuncDescribable) Describe() interface{} {
+	return map[string]interface{}{"OnError": func() {}}
+}
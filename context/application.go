@@ -31,6 +31,10 @@ type Application interface {
 	// Validate validates a value and returns nil if passed or
 	// the failure reason if not.
 	Validate(interface{}) error
+	// ValidateBody validates a value read by the `Context.ReadBody` method.
+	// It's a no-op if the application has a Validator, as the value is already validated by `Validate`,
+	// otherwise it validates the value through the github.com/go-playground/validator.
+	ValidateBody(interface{}) error
 
 	// Minifier returns the minifier instance.
 	// By default it can minifies:
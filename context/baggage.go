@@ -0,0 +1,353 @@
+package context
+
+import (
+	stdContext "context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// BaggageHeaderKey is the header key of the W3C "baggage".
+	BaggageHeaderKey = "baggage"
+	// BaggageMaxMembers is the maximum number of baggage members
+	// a propagator should send, as defined in the W3C Baggage specification.
+	BaggageMaxMembers = 64
+	// BaggageMaxBytes is the maximum size of the baggage header value
+	// a propagator should send, as defined in the W3C Baggage specification.
+	BaggageMaxBytes = 8192
+)
+
+// ErrBaggageMemberNotFound is returned by the `Baggage` typed accessors
+// when the requested key does not exist.
+var ErrBaggageMemberNotFound = errors.New("baggage member not found")
+
+type (
+	// BaggageMember is a single key-value entry of a `Baggage`,
+	// with its optional, raw, properties (e.g. "ttl=60").
+	BaggageMember struct {
+		Key        string
+		Value      string
+		Properties []string
+	}
+
+	// Baggage holds the cross-service metadata of the W3C Baggage specification,
+	// e.g. the tenant or the experiment of the request.
+	// See `Context.Baggage` and the "middleware/baggage" package.
+	Baggage struct {
+		members []BaggageMember
+	}
+)
+
+// ParseBaggage parses a "baggage" header value.
+// Malformed list members are skipped and reported through the returned error,
+// the well-formed ones are kept.
+func ParseBaggage(header string) (*Baggage, error) {
+	b := new(Baggage)
+
+	var errs []string
+	for _, raw := range strings.Split(header, ",") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+
+		parts := strings.Split(raw, ";")
+		kv := strings.SplitN(parts[0], "=", 2)
+		if len(kv) != 2 {
+			errs = append(errs, raw)
+			continue
+		}
+
+		key := strings.TrimSpace(kv[0])
+		value, err := decodeBaggageValue(strings.TrimSpace(kv[1]))
+		if err != nil || !isBaggageToken(key) {
+			errs = append(errs, raw)
+			continue
+		}
+
+		m := BaggageMember{Key: key, Value: value}
+		for _, prop := range parts[1:] {
+			if prop = strings.TrimSpace(prop); prop != "" {
+				m.Properties = append(m.Properties, prop)
+			}
+		}
+
+		b.members = append(b.members, m)
+	}
+
+	if len(errs) > 0 {
+		return b, fmt.Errorf("baggage: malformed members: %s", strings.Join(errs, ", "))
+	}
+
+	return b, nil
+}
+
+// Len returns the number of the baggage members.
+func (b *Baggage) Len() int {
+	if b == nil {
+		return 0
+	}
+
+	return len(b.members)
+}
+
+// Members returns the baggage members.
+func (b *Baggage) Members() []BaggageMember {
+	if b == nil {
+		return nil
+	}
+
+	return b.members
+}
+
+// Member returns the member of the given "key".
+func (b *Baggage) Member(key string) (BaggageMember, bool) {
+	if b != nil {
+		for _, m := range b.members {
+			if m.Key == key {
+				return m, true
+			}
+		}
+	}
+
+	return BaggageMember{}, false
+}
+
+// Set adds or replaces the "key" member's value (and properties).
+func (b *Baggage) Set(key, value string, properties ...string) {
+	m := BaggageMember{Key: key, Value: value, Properties: properties}
+	for i := range b.members {
+		if b.members[i].Key == key {
+			b.members[i] = m
+			return
+		}
+	}
+
+	b.members = append(b.members, m)
+}
+
+// Delete removes the "key" member.
+func (b *Baggage) Delete(key string) {
+	for i, m := range b.members {
+		if m.Key == key {
+			b.members = append(b.members[:i], b.members[i+1:]...)
+			return
+		}
+	}
+}
+
+// Filter keeps only the members which "keep" returns true.
+func (b *Baggage) Filter(keep func(m BaggageMember) bool) {
+	members := b.members[:0]
+	for _, m := range b.members {
+		if keep(m) {
+			members = append(members, m)
+		}
+	}
+
+	b.members = members
+}
+
+// Get returns the "key" member's value or empty string.
+func (b *Baggage) Get(key string) string {
+	m, _ := b.Member(key)
+	return m.Value
+}
+
+// GetInt returns the "key" member's value as int.
+func (b *Baggage) GetInt(key string) (int, error) {
+	m, ok := b.Member(key)
+	if !ok {
+		return 0, ErrBaggageMemberNotFound
+	}
+
+	return strconv.Atoi(m.Value)
+}
+
+// GetIntDefault returns the "key" member's value as int.
+// If not found or it's not a valid int then it returns the "def".
+func (b *Baggage) GetIntDefault(key string, def int) int {
+	if v, err := b.GetInt(key); err == nil {
+		return v
+	}
+
+	return def
+}
+
+// GetInt64 returns the "key" member's value as int64.
+func (b *Baggage) GetInt64(key string) (int64, error) {
+	m, ok := b.Member(key)
+	if !ok {
+		return 0, ErrBaggageMemberNotFound
+	}
+
+	return strconv.ParseInt(m.Value, 10, 64)
+}
+
+// GetInt64Default returns the "key" member's value as int64.
+// If not found or it's not a valid int64 then it returns the "def".
+func (b *Baggage) GetInt64Default(key string, def int64) int64 {
+	if v, err := b.GetInt64(key); err == nil {
+		return v
+	}
+
+	return def
+}
+
+// GetFloat64 returns the "key" member's value as float64.
+func (b *Baggage) GetFloat64(key string) (float64, error) {
+	m, ok := b.Member(key)
+	if !ok {
+		return 0, ErrBaggageMemberNotFound
+	}
+
+	return strconv.ParseFloat(m.Value, 64)
+}
+
+// GetFloat64Default returns the "key" member's value as float64.
+// If not found or it's not a valid float64 then it returns the "def".
+func (b *Baggage) GetFloat64Default(key string, def float64) float64 {
+	if v, err := b.GetFloat64(key); err == nil {
+		return v
+	}
+
+	return def
+}
+
+// GetBool returns the "key" member's value as bool,
+// see `strconv.ParseBool` for the accepted values.
+func (b *Baggage) GetBool(key string) (bool, error) {
+	m, ok := b.Member(key)
+	if !ok {
+		return false, ErrBaggageMemberNotFound
+	}
+
+	return strconv.ParseBool(m.Value)
+}
+
+// GetBoolDefault returns the "key" member's value as bool.
+// If not found or it's not a valid bool then it returns the "def".
+func (b *Baggage) GetBoolDefault(key string, def bool) bool {
+	if v, err := b.GetBool(key); err == nil {
+		return v
+	}
+
+	return def
+}
+
+// String returns the "baggage" header value, values are percent-encoded.
+func (b *Baggage) String() string {
+	if b.Len() == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	for i, m := range b.members {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+
+		sb.WriteString(m.String())
+	}
+
+	return sb.String()
+}
+
+// String returns the encoded list member.
+func (m BaggageMember) String() string {
+	s := m.Key + "=" + encodeBaggageValue(m.Value)
+	for _, prop := range m.Properties {
+		s += ";" + prop
+	}
+
+	return s
+}
+
+func isBaggageToken(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c <= ' ' || c >= 0x7f || strings.IndexByte(`"(),/:;<=>?@[\]{}`, c) != -1 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isBaggageOctet reports whether "c" can be sent as it's,
+// see the "baggage-octet" of the specification.
+func isBaggageOctet(c byte) bool {
+	return c == 0x21 || (c >= 0x23 && c <= 0x2B) || (c >= 0x2D && c <= 0x3A) ||
+		(c >= 0x3C && c <= 0x5B) || (c >= 0x5D && c <= 0x7E)
+}
+
+func encodeBaggageValue(s string) string {
+	const hex = "0123456789ABCDEF"
+
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if isBaggageOctet(c) && c != '%' {
+			sb.WriteByte(c)
+			continue
+		}
+
+		sb.WriteByte('%')
+		sb.WriteByte(hex[c>>4])
+		sb.WriteByte(hex[c&15])
+	}
+
+	return sb.String()
+}
+
+func decodeBaggageValue(s string) (string, error) {
+	if strings.IndexByte(s, '%') == -1 {
+		return s, nil
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '%' {
+			sb.WriteByte(s[i])
+			continue
+		}
+
+		if i+2 >= len(s) {
+			return "", fmt.Errorf("baggage: invalid percent-encoding: %s", s)
+		}
+
+		v, err := strconv.ParseUint(s[i+1:i+3], 16, 8)
+		if err != nil {
+			return "", fmt.Errorf("baggage: invalid percent-encoding: %s", s)
+		}
+
+		sb.WriteByte(byte(v))
+		i += 2
+	}
+
+	return sb.String(), nil
+}
+
+type baggageContextKey struct{}
+
+// WithBaggage returns a copy of the standard "parent" context which holds the "baggage".
+// Outgoing requests made with that context propagate the baggage,
+// see the "middleware/baggage" package.
+func WithBaggage(parent stdContext.Context, baggage *Baggage) stdContext.Context {
+	return stdContext.WithValue(parent, baggageContextKey{}, baggage)
+}
+
+// BaggageFromContext returns the baggage of the standard context "c", if any.
+func BaggageFromContext(c stdContext.Context) *Baggage {
+	if b, ok := c.Value(baggageContextKey{}).(*Baggage); ok {
+		return b
+	}
+
+	return nil
+}
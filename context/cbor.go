@@ -0,0 +1,54 @@
+package context
+
+import (
+	"github.com/fxamacker/cbor/v2"
+)
+
+// CBORMarshal and CBORUnmarshal are the functions which encode and decode
+// the CBOR (RFC 8949) data of the `Context.CBOR` and `Context.ReadCBOR` methods.
+//
+// They use the github.com/fxamacker/cbor/v2 package.
+// Struct fields are named by their "cbor" or, as a fallback, "json" tags,
+// time.Time values are encoded as RFC 3339 date/time strings (tag 0) and
+// the decoder limits the nesting levels, array elements and map pairs
+// of a (malicious) request body.
+var (
+	CBORMarshal = func(v interface{}) ([]byte, error) {
+		return cborEncMode.Marshal(v)
+	}
+
+	CBORUnmarshal = func(data []byte, v interface{}) error {
+		return cborDecMode.Unmarshal(data, v)
+	}
+)
+
+var (
+	cborEncMode = mustCBOREncMode(cbor.EncOptions{
+		Time:    cbor.TimeRFC3339Nano,
+		TimeTag: cbor.EncTagRequired,
+	})
+
+	cborDecMode = mustCBORDecMode(cbor.DecOptions{
+		MaxNestedLevels:  32,
+		MaxArrayElements: 128 * 1024,
+		MaxMapPairs:      128 * 1024,
+	})
+)
+
+func mustCBOREncMode(opts cbor.EncOptions) cbor.EncMode {
+	em, err := opts.EncMode()
+	if err != nil {
+		panic(err)
+	}
+
+	return em
+}
+
+func mustCBORDecMode(opts cbor.DecOptions) cbor.DecMode {
+	dm, err := opts.DecMode()
+	if err != nil {
+		panic(err)
+	}
+
+	return dm
+}
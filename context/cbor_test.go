@@ -0,0 +1,132 @@
+package context_test
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/httptest"
+)
+
+type sensorReading struct {
+	Device  string            `cbor:"device"`
+	Values  []float64         `cbor:"values"`
+	Battery uint8             `cbor:"battery,omitempty"`
+	Offset  int               `json:"offset"`
+	Raw     []byte            `cbor:"raw,omitempty"`
+	Labels  map[string]string `cbor:"labels,omitempty"`
+	At      time.Time         `cbor:"at"`
+	Ignored string            `cbor:"-"`
+}
+
+func TestCBORRoundTrip(t *testing.T) {
+	expected := sensorReading{
+		Device:  "sensor-1",
+		Values:  []float64{21.5, -3.25},
+		Battery: 87,
+		Offset:  -300,
+		Raw:     []byte{0x01, 0x02},
+		Labels:  map[string]string{"room": "kitchen", "floor": "1"},
+		At:      time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+
+	app := iris.New()
+	app.Post("/", func(ctx iris.Context) {
+		var r sensorReading
+		if err := ctx.ReadBody(&r); err != nil {
+			ctx.StopWithError(iris.StatusBadRequest, err)
+			return
+		}
+
+		ctx.CBOR(r)
+	})
+	app.Get("/", func(ctx iris.Context) {
+		ctx.Negotiation().JSON().CBOR()
+		ctx.Negotiate(expected)
+	})
+
+	body, err := context.CBORMarshal(expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := httptest.New(t, app)
+	got := e.POST("/").WithBytes(body).WithHeader("Content-Type", context.ContentCBORHeaderValue).Expect().
+		Status(httptest.StatusOK).ContentType(context.ContentCBORHeaderValue).Body().Raw()
+
+	var r sensorReading
+	if err = context.CBORUnmarshal([]byte(got), &r); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(r, expected) {
+		t.Fatalf("expected:\n%#+v\nbut got:\n%#+v", expected, r)
+	}
+
+	got = e.GET("/").WithHeader("Accept", context.ContentCBORHeaderValue).Expect().
+		Status(httptest.StatusOK).ContentType(context.ContentCBORHeaderValue).Body().Raw()
+	if !bytes.Equal([]byte(got), body) {
+		t.Fatalf("expected negotiated body:\n%x\nbut got:\n%x", body, got)
+	}
+
+	e.POST("/").WithBytes([]byte{0xa1, 0x66}).WithHeader("Content-Type", context.ContentCBORHeaderValue).Expect().
+		Status(httptest.StatusBadRequest)
+}
+
+// Examples of the RFC 8949, Appendix A.
+func TestCBORUnmarshal(t *testing.T) {
+	tests := []struct {
+		data     []byte
+		expected interface{}
+	}{
+		{[]byte{0x00}, uint64(0)},
+		{[]byte{0x18, 0x64}, uint64(100)},
+		{[]byte{0x1b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, uint64(math.MaxUint64)},
+		{[]byte{0x39, 0x03, 0xe7}, int64(-1000)},
+		{[]byte{0xf9, 0x3c, 0x00}, float64(1)},
+		{[]byte{0xf9, 0xc4, 0x00}, float64(-4)},
+		{[]byte{0xfb, 0x3f, 0xf1, 0x99, 0x99, 0x99, 0x99, 0x99, 0x9a}, 1.1},
+		{[]byte{0xf5}, true},
+		{[]byte{0xf6}, nil},
+		{[]byte{0x64, 0x49, 0x45, 0x54, 0x46}, "IETF"},
+		{[]byte{0x44, 0x01, 0x02, 0x03, 0x04}, []byte{1, 2, 3, 4}},
+		{[]byte{0x83, 0x01, 0x82, 0x02, 0x03, 0x82, 0x04, 0x05}, []interface{}{uint64(1), []interface{}{uint64(2), uint64(3)}, []interface{}{uint64(4), uint64(5)}}},
+		{[]byte{0x9f, 0x01, 0x02, 0xff}, []interface{}{uint64(1), uint64(2)}},
+		{[]byte{0x7f, 0x65, 0x73, 0x74, 0x72, 0x65, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x67, 0xff}, "streaming"},
+		{[]byte{0xa2, 0x61, 0x61, 0x01, 0x61, 0x62, 0x82, 0x02, 0x03}, map[interface{}]interface{}{"a": uint64(1), "b": []interface{}{uint64(2), uint64(3)}}},
+		{[]byte{0xa2, 0x01, 0x02, 0x03, 0x04}, map[interface{}]interface{}{uint64(1): uint64(2), uint64(3): uint64(4)}},
+		{[]byte{0xc1, 0x1a, 0x51, 0x4b, 0x67, 0xb0}, time.Unix(1363896240, 0)},
+	}
+
+	for i, tt := range tests {
+		var got interface{}
+		if err := context.CBORUnmarshal(tt.data, &got); err != nil {
+			t.Fatalf("[%d] %v", i, err)
+		}
+
+		if !reflect.DeepEqual(got, tt.expected) {
+			t.Fatalf("[%d] expected: %#v but got: %#v", i, tt.expected, got)
+		}
+	}
+
+	var at time.Time
+	if err := context.CBORUnmarshal([]byte{0xc1, 0x1a, 0x51, 0x4b, 0x67, 0xb0}, &at); err != nil {
+		t.Fatal(err)
+	}
+	if expected := time.Unix(1363896240, 0); !at.Equal(expected) {
+		t.Fatalf("expected time: %s but got: %s", expected, at)
+	}
+
+	var small int8
+	if err := context.CBORUnmarshal([]byte{0x19, 0x03, 0xe8}, &small); err == nil {
+		t.Fatalf("expected an overflow error")
+	}
+
+	var huge []interface{}
+	if err := context.CBORUnmarshal([]byte{0x9b, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00}, &huge); err == nil {
+		t.Fatalf("expected a max array elements error")
+	}
+}
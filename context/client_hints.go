@@ -0,0 +1,261 @@
+package context
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// The Client Hints request header keys,
+// see https://developer.mozilla.org/en-US/docs/Web/HTTP/Client_hints.
+const (
+	ClientHintUA                   = "Sec-CH-UA"
+	ClientHintUAMobile             = "Sec-CH-UA-Mobile"
+	ClientHintUAPlatform           = "Sec-CH-UA-Platform"
+	ClientHintUAPlatformVersion    = "Sec-CH-UA-Platform-Version"
+	ClientHintUAModel              = "Sec-CH-UA-Model"
+	ClientHintUAArch               = "Sec-CH-UA-Arch"
+	ClientHintUAFullVersionList    = "Sec-CH-UA-Full-Version-List"
+	ClientHintViewportWidth        = "Sec-CH-Viewport-Width"
+	ClientHintViewportHeight       = "Sec-CH-Viewport-Height"
+	ClientHintWidth                = "Sec-CH-Width"
+	ClientHintDPR                  = "Sec-CH-DPR"
+	ClientHintDeviceMemory         = "Sec-CH-Device-Memory"
+	ClientHintPrefersColorScheme   = "Sec-CH-Prefers-Color-Scheme"
+	ClientHintPrefersReducedMotion = "Sec-CH-Prefers-Reduced-Motion"
+	ClientHintSaveData             = "Save-Data"
+	ClientHintECT                  = "ECT"
+	ClientHintRTT                  = "RTT"
+	ClientHintDownlink             = "Downlink"
+
+	// AcceptCHHeaderKey is the response header key which advertises the Client Hints
+	// the server is interested in, see the "middleware/clienthints" package.
+	AcceptCHHeaderKey = "Accept-CH"
+	// CriticalCHHeaderKey is the response header key of the Client Hints
+	// which the client should send, by retrying the request, if they are missing.
+	CriticalCHHeaderKey = "Critical-CH"
+)
+
+// legacyClientHints maps the Client Hints to their old, non-prefixed, header keys
+// which are still sent by some clients.
+var legacyClientHints = map[string]string{
+	ClientHintViewportWidth: "Viewport-Width",
+	ClientHintWidth:         "Width",
+	ClientHintDPR:           "DPR",
+	ClientHintDeviceMemory:  "Device-Memory",
+}
+
+type (
+	// ClientHints holds the Client Hints of a request.
+	// It can be retrieved through the `Context.ClientHints` method.
+	// A client sends most of the hints only when the server advertised them
+	// through the "Accept-CH" response header, see the "middleware/clienthints" package.
+	// Missing hints have their zero value.
+	ClientHints struct {
+		// Brands holds the browser brands and their significant versions (Sec-CH-UA).
+		Brands []ClientHintBrand `json:"brands,omitempty"`
+		// FullVersionList holds the browser brands and their full versions (Sec-CH-UA-Full-Version-List).
+		FullVersionList []ClientHintBrand `json:"fullVersionList,omitempty"`
+		// Mobile reports whether the client is a mobile device (Sec-CH-UA-Mobile).
+		Mobile bool `json:"mobile"`
+		// Platform is the operating system, e.g. "Android", "Windows" (Sec-CH-UA-Platform).
+		Platform string `json:"platform,omitempty"`
+		// PlatformVersion is the operating system's version (Sec-CH-UA-Platform-Version).
+		PlatformVersion string `json:"platformVersion,omitempty"`
+		// Model is the device model (Sec-CH-UA-Model).
+		Model string `json:"model,omitempty"`
+		// Arch is the CPU architecture, e.g. "x86", "arm" (Sec-CH-UA-Arch).
+		Arch string `json:"arch,omitempty"`
+
+		// ViewportWidth is the layout viewport width in CSS pixels (Sec-CH-Viewport-Width).
+		ViewportWidth int `json:"viewportWidth,omitempty"`
+		// ViewportHeight is the layout viewport height in CSS pixels (Sec-CH-Viewport-Height).
+		ViewportHeight int `json:"viewportHeight,omitempty"`
+		// Width is the intended display width of the requested image in physical pixels (Sec-CH-Width).
+		Width int `json:"width,omitempty"`
+		// DPR is the device pixel ratio (Sec-CH-DPR).
+		DPR float64 `json:"dpr,omitempty"`
+		// DeviceMemory is the approximate amount of the device's RAM in GiB (Sec-CH-Device-Memory).
+		DeviceMemory float64 `json:"deviceMemory,omitempty"`
+
+		// SaveData reports whether the client prefers reduced data usage (Save-Data: on).
+		SaveData bool `json:"saveData"`
+		// ECT is the effective connection type: "slow-2g", "2g", "3g" or "4g" (ECT).
+		ECT string `json:"ect,omitempty"`
+		// RTT is the approximate round trip time in milliseconds (RTT).
+		RTT int `json:"rtt,omitempty"`
+		// Downlink is the approximate bandwidth in Mbps (Downlink).
+		Downlink float64 `json:"downlink,omitempty"`
+
+		// PrefersColorScheme is the preferred color scheme: "light" or "dark" (Sec-CH-Prefers-Color-Scheme).
+		PrefersColorScheme string `json:"prefersColorScheme,omitempty"`
+		// PrefersReducedMotion reports whether the client prefers reduced motion (Sec-CH-Prefers-Reduced-Motion).
+		PrefersReducedMotion bool `json:"prefersReducedMotion"`
+	}
+
+	// ClientHintBrand is a browser brand and its version, see `ClientHints.Brands`.
+	ClientHintBrand struct {
+		Brand   string `json:"brand"`
+		Version string `json:"version"`
+	}
+)
+
+// ParseClientHints parses the Client Hints of the "header" request headers.
+// Malformed hints are skipped.
+func ParseClientHints(header http.Header) *ClientHints {
+	get := func(key string) string {
+		if v := header.Get(key); v != "" {
+			return v
+		}
+
+		return header.Get(legacyClientHints[key])
+	}
+
+	str := func(key string) string {
+		if item, err := ParseStructuredItem(get(key)); err == nil {
+			if s, ok := item.Value.(string); ok {
+				return s
+			}
+		}
+
+		return ""
+	}
+
+	num := func(key string) float64 {
+		v := get(key)
+		if item, err := ParseStructuredItem(v); err == nil {
+			switch n := item.Value.(type) {
+			case int64:
+				return float64(n)
+			case float64:
+				return n
+			}
+		}
+
+		// the network hints and the legacy ones are not structured fields.
+		n, _ := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return n
+	}
+
+	h := &ClientHints{
+		Brands:               parseClientHintBrands(get(ClientHintUA)),
+		FullVersionList:      parseClientHintBrands(get(ClientHintUAFullVersionList)),
+		Platform:             str(ClientHintUAPlatform),
+		PlatformVersion:      str(ClientHintUAPlatformVersion),
+		Model:                str(ClientHintUAModel),
+		Arch:                 str(ClientHintUAArch),
+		ViewportWidth:        int(num(ClientHintViewportWidth)),
+		ViewportHeight:       int(num(ClientHintViewportHeight)),
+		Width:                int(num(ClientHintWidth)),
+		DPR:                  num(ClientHintDPR),
+		DeviceMemory:         num(ClientHintDeviceMemory),
+		SaveData:             strings.EqualFold(strings.TrimSpace(get(ClientHintSaveData)), "on"),
+		ECT:                  strings.TrimSpace(get(ClientHintECT)),
+		RTT:                  int(num(ClientHintRTT)),
+		Downlink:             num(ClientHintDownlink),
+		PrefersColorScheme:   str(ClientHintPrefersColorScheme),
+		PrefersReducedMotion: str(ClientHintPrefersReducedMotion) == "reduce",
+	}
+
+	if item, err := ParseStructuredItem(get(ClientHintUAMobile)); err == nil {
+		h.Mobile, _ = item.Value.(bool)
+	}
+
+	return h
+}
+
+func parseClientHintBrands(header string) []ClientHintBrand {
+	if header == "" {
+		return nil
+	}
+
+	list, err := ParseStructuredList(header)
+	if err != nil {
+		return nil
+	}
+
+	brands := make([]ClientHintBrand, 0, len(list))
+	for _, member := range list {
+		item, ok := member.(StructuredItem)
+		if !ok {
+			continue
+		}
+
+		brand, ok := item.Value.(string)
+		if !ok {
+			continue
+		}
+
+		version, _ := item.Params.Get("v")
+		v, _ := version.(string)
+		brands = append(brands, ClientHintBrand{Brand: brand, Version: v})
+	}
+
+	return brands
+}
+
+// Brand returns the version of the given browser "brand", e.g. "Chromium",
+// and reports whether it's listed in the Brands (or the FullVersionList if not empty).
+func (h *ClientHints) Brand(brand string) (string, bool) {
+	brands := h.FullVersionList
+	if len(brands) == 0 {
+		brands = h.Brands
+	}
+
+	for _, b := range brands {
+		if b.Brand == brand {
+			return b.Version, true
+		}
+	}
+
+	return "", false
+}
+
+// PrefersReducedData reports whether the client prefers reduced data usage,
+// either explicitly (Save-Data) or because of a slow connection (ECT).
+func (h *ClientHints) PrefersReducedData() bool {
+	return h.SaveData || h.ECT == "slow-2g" || h.ECT == "2g"
+}
+
+// ImageWidth returns the smallest of the "candidates" widths (in physical pixels)
+// which covers the display width of an image for this client,
+// e.g. to select the image to serve or to render in a template.
+// The display width is the Width hint or the ViewportWidth multiplied by the DPR
+// (the DPR is ignored when the client prefers reduced data).
+// It returns the largest candidate if the display width is unknown or larger than all candidates.
+//
+// Example Code:
+//  width := ctx.ClientHints().ImageWidth(320, 640, 1280)
+//  ctx.ServeFile(fmt.Sprintf("./images/hero-%d.webp", width))
+//
+// Template Code:
+//  <img src="/images/hero-{{ .ClientHints.ImageWidth 320 640 1280 }}.webp">
+func (h *ClientHints) ImageWidth(candidates ...int) int {
+	if len(candidates) == 0 {
+		return 0
+	}
+
+	widths := append([]int(nil), candidates...)
+	sort.Ints(widths)
+
+	display := h.Width
+	if display <= 0 && h.ViewportWidth > 0 {
+		dpr := h.DPR
+		if dpr <= 0 || h.PrefersReducedData() {
+			dpr = 1
+		}
+
+		display = int(float64(h.ViewportWidth)*dpr + 0.5)
+	}
+
+	if display > 0 {
+		for _, w := range widths {
+			if w >= display {
+				return w
+			}
+		}
+	}
+
+	return widths[len(widths)-1]
+}
@@ -0,0 +1,61 @@
+package context_test
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/kataras/iris/v12/context"
+)
+
+func TestParseClientHints(t *testing.T) {
+	header := http.Header{}
+	header.Set(context.ClientHintUA, `"Chromium";v="118", "Not=A?Brand";v="99"`)
+	header.Set(context.ClientHintUAFullVersionList, `"Chromium";v="118.0.5993.70"`)
+	header.Set(context.ClientHintUAMobile, "?1")
+	header.Set(context.ClientHintUAPlatform, `"Android"`)
+	header.Set(context.ClientHintViewportWidth, "412")
+	header.Set("DPR", "2.625") // legacy.
+	header.Set(context.ClientHintSaveData, "on")
+	header.Set(context.ClientHintECT, "4g")
+	header.Set(context.ClientHintRTT, "50")
+	header.Set(context.ClientHintPrefersReducedMotion, `"reduce"`)
+	header.Set(context.ClientHintDeviceMemory, "invalid")
+
+	got := context.ParseClientHints(header)
+	expected := &context.ClientHints{
+		Brands:               []context.ClientHintBrand{{Brand: "Chromium", Version: "118"}, {Brand: "Not=A?Brand", Version: "99"}},
+		FullVersionList:      []context.ClientHintBrand{{Brand: "Chromium", Version: "118.0.5993.70"}},
+		Mobile:               true,
+		Platform:             "Android",
+		ViewportWidth:        412,
+		DPR:                  2.625,
+		SaveData:             true,
+		ECT:                  "4g",
+		RTT:                  50,
+		PrefersReducedMotion: true,
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected: %#v but got: %#v", expected, got)
+	}
+
+	if version, ok := got.Brand("Chromium"); !ok || version != "118.0.5993.70" {
+		t.Fatalf("expected the full version of Chromium but got: %q", version)
+	}
+
+	tests := []struct {
+		hints    context.ClientHints
+		expected int
+	}{
+		{context.ClientHints{}, 1280},                                     // unknown display width.
+		{context.ClientHints{Width: 500}, 640},                            // Width hint.
+		{context.ClientHints{ViewportWidth: 300, DPR: 2}, 640},            // 600 physical pixels.
+		{context.ClientHints{ViewportWidth: 300, DPR: 2, ECT: "2g"}, 320}, // reduced data.
+		{context.ClientHints{ViewportWidth: 1920}, 1280},                  // larger than all.
+	}
+	for i, tt := range tests {
+		if got := tt.hints.ImageWidth(1280, 320, 640); got != tt.expected {
+			t.Fatalf("[%d] expected image width: %d but got: %d", i, tt.expected, got)
+		}
+	}
+}
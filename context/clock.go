@@ -0,0 +1,67 @@
+package context
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is an interface which contains a single `Now` method.
+// It's used by the `Context.Now` method and the framework's time-dependent
+// features (e.g. cache, rate limits, sessions and access logs),
+// so they can be tested deterministically. See `MockClock`.
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc is a function shortcut for a Clock.
+type ClockFunc func() time.Time
+
+// Now completes the Clock interface.
+func (fn ClockFunc) Now() time.Time {
+	return fn()
+}
+
+// SystemClock is the default Clock, it returns the current local time.
+var SystemClock Clock = ClockFunc(time.Now)
+
+// MockClock is a Clock which can be frozen and advanced manually, useful for testing.
+// It's safe for concurrent use.
+//
+// Usage:
+//  clock := context.NewMockClock(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC))
+//  app.SetClock(clock)
+//  // [...make requests]
+//  clock.Advance(time.Hour)
+type MockClock struct {
+	mu  sync.RWMutex
+	now time.Time
+}
+
+var _ Clock = (*MockClock)(nil)
+
+// NewMockClock returns a new MockClock frozen at the given time "t".
+func NewMockClock(t time.Time) *MockClock {
+	return &MockClock{now: t}
+}
+
+// Now returns the frozen time.
+func (c *MockClock) Now() time.Time {
+	c.mu.RLock()
+	t := c.now
+	c.mu.RUnlock()
+	return t
+}
+
+// Set freezes the clock to the given time "t".
+func (c *MockClock) Set(t time.Time) {
+	c.mu.Lock()
+	c.now = t
+	c.mu.Unlock()
+}
+
+// Advance moves the clock forward by the given duration "d".
+func (c *MockClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	c.mu.Unlock()
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/klauspost/compress/flate"
 	"github.com/klauspost/compress/gzip"
 	"github.com/klauspost/compress/s2" // snappy output but likely faster decompression.
+	"github.com/klauspost/compress/zstd"
 )
 
 // The available builtin compression algorithms.
@@ -21,6 +22,9 @@ const (
 	BROTLI  = "br"
 	SNAPPY  = "snappy"
 	S2      = "s2"
+	// ZSTD is supported only for reading request bodies,
+	// see `NewCompressReader` and `Context.DecompressBody`.
+	ZSTD = "zstd"
 )
 
 // IDENTITY no transformation whatsoever.
@@ -123,6 +127,47 @@ type CompressReader struct {
 	Src io.ReadCloser
 	// Encoding is the compression alogirthm is used to decompress and read the data.
 	Encoding string
+
+	maxSize int64 // the maximum decompressed size, see `SetMaxSize`.
+	read    int64
+}
+
+// SetMaxSize sets a limit to the decompressed data size,
+// reads over that limit fail with `ErrRequestBodyTooLarge`.
+// It guards the server against decompression bombs,
+// a small compressed body can hold gigabytes of data.
+// Zero or negative value means no limit.
+func (r *CompressReader) SetMaxSize(n int64) {
+	r.maxSize = n
+}
+
+// Read reads up to len(p) decompressed bytes into p.
+func (r *CompressReader) Read(p []byte) (int, error) {
+	if r.maxSize <= 0 {
+		return r.ReadCloser.Read(p)
+	}
+
+	// Read one byte over the limit to detect the overflow.
+	if remaining := r.maxSize - r.read + 1; int64(len(p)) > remaining {
+		p = p[:remaining]
+	}
+
+	n, err := r.ReadCloser.Read(p)
+	r.read += int64(n)
+	if r.read > r.maxSize {
+		return n - int(r.read-r.maxSize), ErrRequestBodyTooLarge
+	}
+
+	return n, err
+}
+
+type zstdReadCloser struct {
+	*zstd.Decoder
+}
+
+func (r *zstdReadCloser) Close() error {
+	r.Decoder.Close()
+	return nil
 }
 
 // NewCompressReader returns a new "compressReader" wrapper of "src".
@@ -130,6 +175,16 @@ type CompressReader struct {
 // or `ErrNotSupportedCompression` if server missing the decompression algorithm.
 // Note: on server-side the request body (src) will be closed automaticaly.
 func NewCompressReader(src io.Reader, encoding string) (*CompressReader, error) {
+	return newCompressReader(src, encoding, 0)
+}
+
+// zstdMaxWindowSize is the maximum zstd window size of a `CompressReader` with a max size,
+// it's the recommended maximum window size for decoders by the RFC 8878.
+const zstdMaxWindowSize = 8 << 20
+
+// newCompressReader is the `NewCompressReader` with a `SetMaxSize` call,
+// the "maxSize" limits the zstd decoder's memory too.
+func newCompressReader(src io.Reader, encoding string, maxSize int64) (*CompressReader, error) {
 	if encoding == "" || src == nil {
 		return nil, ErrRequestNotCompressed
 	}
@@ -150,6 +205,28 @@ func NewCompressReader(src io.Reader, encoding string) (*CompressReader, error)
 		rc = &noOpReadCloser{snappy.NewReader(src)}
 	case S2:
 		rc = &noOpReadCloser{s2.NewReader(src)}
+	case ZSTD:
+		opts := []zstd.DOption{zstd.WithDecoderConcurrency(1)}
+		if maxSize > 0 {
+			// The decoder requires at least the minimum window,
+			// the decompressed size is limited by the CompressReader itself.
+			maxMemory := uint64(maxSize)
+			if maxMemory < zstd.MinWindowSize {
+				maxMemory = zstd.MinWindowSize
+			}
+
+			window := maxMemory
+			if window > zstdMaxWindowSize {
+				window = zstdMaxWindowSize
+			}
+
+			opts = append(opts, zstd.WithDecoderMaxMemory(maxMemory), zstd.WithDecoderMaxWindow(window))
+		}
+
+		var d *zstd.Decoder
+		if d, err = zstd.NewReader(src, opts...); err == nil {
+			rc = &zstdReadCloser{d}
+		}
 	default:
 		err = ErrNotSupportedCompression
 	}
@@ -167,6 +244,7 @@ func NewCompressReader(src io.Reader, encoding string) (*CompressReader, error)
 		ReadCloser: rc,
 		Src:        srcReadCloser,
 		Encoding:   encoding,
+		maxSize:    maxSize,
 	}, nil
 }
 
@@ -1,6 +1,7 @@
 package context
 
 import (
+	"net"
 	"time"
 
 	"github.com/kataras/iris/v12/core/netutil"
@@ -32,6 +33,10 @@ type ConfigurationReadOnly interface {
 	GetForceLowercaseRouting() bool
 	// GetFireMethodNotAllowed returns the FireMethodNotAllowed field.
 	GetFireMethodNotAllowed() bool
+	// GetFailOnRouteConflicts returns the FailOnRouteConflicts field.
+	GetFailOnRouteConflicts() bool
+	// GetStrictMode returns the StrictMode field.
+	GetStrictMode() bool
 	// GetDisableAutoFireStatusCode returns the DisableAutoFireStatusCode field.
 	GetDisableAutoFireStatusCode() bool
 	// ResetOnFireErrorCode retruns the ResetOnFireErrorCode field.
@@ -77,6 +82,10 @@ type ConfigurationReadOnly interface {
 	GetRemoteAddrHeadersForce() bool
 	// GetRemoteAddrPrivateSubnets returns the RemoteAddrPrivateSubnets field.
 	GetRemoteAddrPrivateSubnets() []netutil.IPRange
+	// GetTrustedProxies returns the TrustedProxies field.
+	GetTrustedProxies() []string
+	// GetTrustedProxyNets returns the parsed TrustedProxies.
+	GetTrustedProxyNets() []*net.IPNet
 	// GetSSLProxyHeaders returns the SSLProxyHeaders field.
 	GetSSLProxyHeaders() map[string]string
 	// GetHostProxyHeaders returns the HostProxyHeaders field.
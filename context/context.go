@@ -3,12 +3,15 @@ package context
 import (
 	"bytes"
 	stdContext "context"
+	"crypto/sha1"
+	"encoding/base64"
 	"encoding/json"
 	"encoding/xml"
 	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"mime"
 	"mime/multipart"
 	"net"
 	"net/http"
@@ -93,15 +96,78 @@ func (u UnmarshalerFunc) Unmarshal(data []byte, v interface{}) error {
 	return u(data, v)
 }
 
+// DecompressRequest is a middleware which transparently decompresses
+// the request bodies of all next handlers in the chain,
+// based on their "Content-Encoding" header, see `Context.DecompressBody`.
+// Request bodies which decompress to more than "maxDecompressedSize" bytes
+// fail to be read with `ErrRequestBodyTooLarge`.
+// Requests with an unsupported "Content-Encoding" are rejected
+// with 415 Unsupported Media Type and requests with a malformed
+// compressed stream (e.g. a corrupt gzip header) with 400 Bad Request.
+//
+// Usage:
+//  app.Use(iris.DecompressRequest(10 * iris.MB))
+var DecompressRequest = func(maxDecompressedSize int64) Handler {
+	return func(ctx *Context) {
+		if err := ctx.DecompressBody(maxDecompressedSize); err != nil {
+			if errors.Is(err, ErrNotSupportedCompression) {
+				ctx.StopWithError(http.StatusUnsupportedMediaType, err)
+			} else {
+				ctx.StopWithError(http.StatusBadRequest, err)
+			}
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
 // LimitRequestBodySize is a middleware which sets a request body size limit
 // for all next handlers in the chain.
+// Requests which expect a 100 Continue response (see `Context.ExpectsContinue`)
+// and declare a larger Content-Length are rejected
+// with 413 Request Entity Too Large before their body is transmitted.
 var LimitRequestBodySize = func(maxRequestBodySizeBytes int64) Handler {
 	return func(ctx *Context) {
+		if maxRequestBodySizeBytes > 0 && ctx.request.ContentLength > maxRequestBodySizeBytes && ctx.ExpectsContinue() {
+			ctx.RejectContinue(http.StatusRequestEntityTooLarge)
+			return
+		}
+
 		ctx.SetMaxRequestBodySize(maxRequestBodySizeBytes)
 		ctx.Next()
 	}
 }
 
+// ExpectContinue is a middleware which runs the "check" function
+// for requests that expect a 100 Continue response (see `Context.ExpectsContinue`),
+// before their body is transmitted.
+// If the "check" returns a status code greater than zero (e.g. 401 or 413)
+// then the request is rejected with that status code, see `Context.RejectContinue`,
+// otherwise the next handlers are executed and the client is
+// told to continue on the first request body read.
+// Requests without the "Expect: 100-continue" header are not checked.
+//
+// Usage:
+//  app.Post("/upload", iris.ExpectContinue(func(ctx iris.Context) int {
+//   if !quotas.Allow(ctx.GetHeader("X-API-Key"), ctx.GetContentLength()) {
+//    return iris.StatusTooManyRequests
+//   }
+//   return 0
+//  }), upload)
+var ExpectContinue = func(check func(ctx *Context) int) Handler {
+	return func(ctx *Context) {
+		if ctx.ExpectsContinue() {
+			if statusCode := check(ctx); statusCode > 0 {
+				ctx.RejectContinue(statusCode)
+				return
+			}
+		}
+
+		ctx.Next()
+	}
+}
+
 // Map is just a type alias of the map[string]interface{} type.
 type Map = map[string]interface{}
 
@@ -139,6 +205,12 @@ type Context struct {
 	// Also it's responsible to keep the old value of the last known handler index
 	// before StopExecution. See ResumeExecution.
 	proceeded int
+	// the per-request clock, see `SetClock` and `Now` methods.
+	clock Clock
+	// the functions to run after the response was sent, see `Defer` method.
+	deferred []func()
+	// the strict mode state, nil if the strict mode is disabled.
+	strict *strictMode
 }
 
 // NewContext returns a new Context instance.
@@ -183,6 +255,7 @@ func (ctx *Context) Clone() *Context {
 		currentHandlerIndex: stopExecutionIndex,
 		proceeded:           ctx.proceeded,
 		currentRoute:        ctx.currentRoute,
+		clock:               ctx.clock,
 	}
 }
 
@@ -205,6 +278,13 @@ func (ctx *Context) BeginRequest(w http.ResponseWriter, r *http.Request) {
 	ctx.request = r
 	ctx.currentHandlerIndex = 0
 	ctx.proceeded = 0
+	ctx.clock = nil
+	ctx.deferred = nil
+	ctx.strict = nil
+	if ctx.app != nil && ctx.app.ConfigurationReadOnly().GetStrictMode() {
+		ctx.strict = &strictMode{ctx: ctx, w: w}
+		w = &strictResponseWriter{ResponseWriter: w, strict: ctx.strict}
+	}
 	ctx.writer = AcquireResponseWriter()
 	ctx.writer.BeginResponse(w)
 }
@@ -215,14 +295,72 @@ func (ctx *Context) BeginRequest(w http.ResponseWriter, r *http.Request) {
 // 1. executes the OnClose function (if any).
 // 2. flushes the response writer's result or fire any error handler.
 // 3. releases the response writer.
+// 4. fires the functions registered through `Defer` (if any) on their own goroutine.
+//
+// On strict mode the response writer is not released,
+// so a late use of the Context is reported instead.
 func (ctx *Context) EndRequest() {
+	if ctx.strict != nil {
+		ctx.strict.checkNext() // before the error handlers replace the chain.
+	}
+
 	if !ctx.app.ConfigurationReadOnly().GetDisableAutoFireStatusCode() &&
 		StatusCodeNotSuccessful(ctx.GetStatusCode()) {
 		ctx.app.FireErrorCode(ctx)
 	}
 
 	ctx.writer.FlushResponse()
-	ctx.writer.EndResponse()
+	if ctx.strict != nil {
+		ctx.strict.end()
+	} else {
+		ctx.writer.EndResponse()
+	}
+
+	if deferred := ctx.deferred; len(deferred) > 0 {
+		ctx.deferred = nil
+		go runDeferred(ctx.app.Logger(), deferred)
+	}
+}
+
+// Defer registers the "fn" function which will be fired
+// after the response has been sent and the Context was released,
+// on its own goroutine, so it does not add any latency to the response.
+// Useful for audit logging and metrics.
+//
+// The functions are fired in last-in-first-out order (like the Go's defer statement),
+// a panic of one function is logged and it does not stop the rest.
+//
+// The Context is released and re-used by the next requests by then,
+// so the "fn" should NOT access it; keep the required request values
+// in local variables instead.
+//
+// Example Code:
+//  id, path := ctx.GetID(), ctx.Path()
+//  ctx.Defer(func() {
+//      audit.Log(id, path)
+//  })
+func (ctx *Context) Defer(fn func()) {
+	if fn == nil {
+		return
+	}
+
+	ctx.deferred = append(ctx.deferred, fn)
+}
+
+func runDeferred(logger *golog.Logger, deferred []func()) {
+	for i := len(deferred) - 1; i >= 0; i-- {
+		runDeferredFunc(logger, deferred[i])
+	}
+}
+
+func runDeferredFunc(logger *golog.Logger, fn func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Errorf("deferred function panic: %v", r)
+		}
+	}()
+
+	fn()
 }
 
 // IsCanceled reports whether the client canceled the request
@@ -441,6 +579,75 @@ func (ctx *Context) ResetResponseWriter(newResponseWriter ResponseWriter) {
 	ctx.writer = newResponseWriter
 }
 
+const hijackedContextKey = "iris.hijacked"
+
+// Hijack lets the caller take over the underlying connection,
+// e.g. for custom protocols upgraded from HTTP.
+// The connection is detached from the framework: the next (and done) handlers
+// are not executed, the response compression is skipped and
+// the accesslog middleware does not account the request and response bodies.
+//
+// The returned HijackedConn has its server deadlines cleared,
+// its reads include any data buffered by the server,
+// it applies the optional read and write timeouts of the "opts"
+// and it's closed automatically on server's shutdown.
+// It's the caller's responsibility to close the connection.
+//
+// Usage:
+//  app.Get("/upgrade", func(ctx iris.Context) {
+//   conn, err := ctx.Hijack(iris.HijackOptions{ReadTimeout: time.Minute})
+//   if err != nil {
+//    ctx.StopWithError(iris.StatusInternalServerError, err)
+//    return
+//   }
+//   go serveCustomProtocol(conn)
+//  })
+//
+// Returns ErrHijackNotSupported if the response writer cannot be hijacked (e.g. HTTP/2).
+func (ctx *Context) Hijack(opts ...HijackOptions) (*HijackedConn, error) {
+	h := hijackerOf(ctx.writer)
+	if h == nil {
+		return nil, ErrHijackNotSupported
+	}
+
+	conn, brw, err := h.Hijack()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx.values.Set(hijackedContextKey, struct{}{})
+	ctx.StopExecution()
+
+	// clear the server's read and write deadlines.
+	_ = conn.SetDeadline(time.Time{})
+
+	c := &HijackedConn{
+		Conn:   conn,
+		reader: brw.Reader,
+	}
+	if len(opts) > 0 {
+		c.opts = opts[0]
+	}
+
+	if brw.Writer.Buffered() > 0 {
+		if err = brw.Writer.Flush(); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+
+	if c.tracker = getHijackTracker(ctx.request); c.tracker != nil {
+		c.tracker.add(c)
+	}
+
+	return c, nil
+}
+
+// IsHijacked reports whether the connection was hijacked through the `Hijack` method.
+func (ctx *Context) IsHijacked() bool {
+	return ctx.values.Get(hijackedContextKey) != nil
+}
+
 // Request returns the original *http.Request, as expected.
 func (ctx *Context) Request() *http.Request {
 	return ctx.request
@@ -807,6 +1014,42 @@ func (ctx *Context) StopWithProblem(statusCode int, problem Problem) {
 	ctx.Problem(problem)
 }
 
+// StopWithReadError stops the handlers chain and sends the error
+// of a `ReadBody` (or any other Read* method) call.
+// Validation failures (see `AsValidationErrors`) are sent as a
+// 422 Unprocessable Entity problem with an "errors" field
+// which contains the structured field errors, the `ErrRequestBodyTooLarge`
+// (including the `SetMaxRequestBodySize` ones) with a 413 Request Entity Too Large status code,
+// the `ErrContentNotSupported` with a 415 Unsupported Media Type status code,
+// otherwise the error is sent with a 400 Bad Request status code.
+//
+// Usage:
+//  if err := ctx.ReadBody(&user); err != nil {
+//   ctx.StopWithReadError(err)
+//   return
+//  }
+func (ctx *Context) StopWithReadError(err error) {
+	if errs, ok := AsValidationErrors(err); ok {
+		ctx.StopWithProblem(http.StatusUnprocessableEntity, NewProblem().
+			Title("Validation error").
+			Detail("One or more fields failed to be validated").
+			Key("errors", errs))
+		return
+	}
+
+	if errors.Is(err, ErrRequestBodyTooLarge) {
+		ctx.StopWithError(http.StatusRequestEntityTooLarge, err)
+		return
+	}
+
+	if errors.Is(err, ErrContentNotSupported) {
+		ctx.StopWithError(http.StatusUnsupportedMediaType, err)
+		return
+	}
+
+	ctx.StopWithError(http.StatusBadRequest, err)
+}
+
 //  +------------------------------------------------------------+
 //  | Current "user/request" storage                             |
 //  | and share information between the handlers - Values().     |
@@ -1063,7 +1306,14 @@ func (ctx *Context) FullRequestURI() string {
 //      `Configuration.WithRemoteAddrHeader(...)`,
 //      `Configuration.WithoutRemoteAddrHeader(...)` and
 //      `Configuration.RemoteAddrPrivateSubnets` for more.
+//
+// When the Configuration.TrustedProxies is not empty
+// it returns the result of the `RealIP` method instead.
 func (ctx *Context) RemoteAddr() string {
+	if len(ctx.app.ConfigurationReadOnly().GetTrustedProxies()) > 0 {
+		return ctx.RealIP()
+	}
+
 	if remoteHeaders := ctx.app.ConfigurationReadOnly().GetRemoteAddrHeaders(); len(remoteHeaders) > 0 {
 		privateSubnets := ctx.app.ConfigurationReadOnly().GetRemoteAddrPrivateSubnets()
 
@@ -1099,6 +1349,89 @@ func (ctx *Context) RemoteAddr() string {
 	return addr
 }
 
+const (
+	// ForwardedHeaderKey is the "Forwarded" (RFC 7239) header key.
+	ForwardedHeaderKey = "Forwarded"
+	// XForwardedForHeaderKey is the "X-Forwarded-For" header key.
+	XForwardedForHeaderKey = "X-Forwarded-For"
+	// XRealIPHeaderKey is the "X-Real-Ip" header key.
+	XRealIPHeaderKey = "X-Real-Ip"
+)
+
+// RealIP returns the client's IP based on the Configuration.TrustedProxies.
+//
+// The proxy headers are read only when the peer (the Request's `RemoteAddr` field)
+// is a trusted proxy, otherwise the peer's IP is returned as it is.
+// The "Forwarded", "X-Forwarded-For" and "X-Real-Ip" headers are checked, in that order.
+// The addresses of the first two are walked from right to left,
+// skipping the trusted proxies, so the first untrusted address is the client's one,
+// a client cannot spoof its IP by sending these headers itself.
+//
+// Look `Configuration.TrustedProxies` and `iris.WithTrustedProxies` for more.
+func (ctx *Context) RealIP() string {
+	peer := strings.TrimSpace(ctx.request.RemoteAddr)
+	if ip, _, err := net.SplitHostPort(peer); err == nil {
+		peer = ip
+	}
+
+	trusted := ctx.app.ConfigurationReadOnly().GetTrustedProxyNets()
+	if trusted == nil { // the application is not built.
+		trusted, _ = netutil.ParseIPNets(ctx.app.ConfigurationReadOnly().GetTrustedProxies())
+	}
+
+	if !netutil.IPInNets(net.ParseIP(peer), trusted) {
+		return peer
+	}
+
+	// A client may send these headers itself and the proxy can append
+	// a new header line instead of merging the values into the existing one,
+	// so all lines are walked, the proxy's line is the last one.
+	if v := ctx.getHeaderValues(ForwardedHeaderKey); v != "" {
+		if ip, ok := walkTrustedChain(netutil.ParseForwarded(v), trusted); ok {
+			return ip
+		}
+	}
+
+	if v := ctx.getHeaderValues(XForwardedForHeaderKey); v != "" {
+		if ip, ok := walkTrustedChain(strings.Split(v, ","), trusted); ok {
+			return ip
+		}
+	}
+
+	if v := strings.TrimSpace(ctx.GetHeader(XRealIPHeaderKey)); net.ParseIP(v) != nil {
+		return v
+	}
+
+	return peer
+}
+
+// getHeaderValues returns all the values of the "name" request header joined by commas.
+func (ctx *Context) getHeaderValues(name string) string {
+	return strings.Join(ctx.request.Header.Values(name), ",")
+}
+
+// walkTrustedChain returns the right-most address of the "chain"
+// which is not a trusted proxy, or the left-most one if all are trusted.
+func walkTrustedChain(chain []string, trusted []*net.IPNet) (string, bool) {
+	var first string
+	for i := len(chain) - 1; i >= 0; i-- {
+		addr := strings.TrimSpace(chain[i])
+		ip := net.ParseIP(addr)
+		if ip == nil {
+			// The chain is malformed, stop walking,
+			// anything before that is not trustworthy.
+			break
+		}
+
+		first = addr
+		if !netutil.IPInNets(ip, trusted) {
+			return addr, true
+		}
+	}
+
+	return first, first != ""
+}
+
 // TrimHeaderValue returns the "v[0:first space or semicolon]".
 func TrimHeaderValue(v string) string {
 	for i, char := range v {
@@ -1409,7 +1742,14 @@ func (ctx *Context) GetContentLength() int64 {
 // Look .GetStatusCode & .FireStatusCode too.
 //
 // Remember, the last one before .Write matters except recorder and transactions.
+//
+// A 401 Unauthorized or a 403 Forbidden status code sets the WWW-Authenticate
+// challenges of the request's security requirements, see `SetSecurityRequirements`.
 func (ctx *Context) StatusCode(statusCode int) {
+	if statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden {
+		ctx.writeSecurityChallenges(statusCode)
+	}
+
 	ctx.writer.WriteHeader(statusCode)
 }
 
@@ -2206,8 +2546,123 @@ func (ctx *Context) Redirect(urlToRedirect string, statusHeader ...int) {
 
 // SetMaxRequestBodySize sets a limit to the request body size
 // should be called before reading the request body from the client.
+// Reads over that limit fail with `ErrRequestBodyTooLarge`
+// and the connection is closed after the response.
 func (ctx *Context) SetMaxRequestBodySize(limitOverBytes int64) {
-	ctx.request.Body = http.MaxBytesReader(ctx.writer, ctx.request.Body, limitOverBytes)
+	if limitOverBytes < 0 {
+		limitOverBytes = 0
+	}
+
+	ctx.request.Body = &maxBytesReader{
+		ReadCloser: ctx.request.Body,
+		header:     ctx.writer.Header(),
+		remaining:  limitOverBytes,
+	}
+}
+
+// maxBytesReader is the request body of the `SetMaxRequestBodySize`.
+// Like the http.MaxBytesReader but it fails with the `ErrRequestBodyTooLarge`.
+type maxBytesReader struct {
+	io.ReadCloser
+	header    http.Header
+	remaining int64
+	err       error
+}
+
+func (r *maxBytesReader) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	// Read one byte over the limit to detect the overflow.
+	if int64(len(p)) > r.remaining+1 {
+		p = p[:r.remaining+1]
+	}
+
+	n, err := r.ReadCloser.Read(p)
+	if int64(n) <= r.remaining {
+		r.remaining -= int64(n)
+		r.err = err
+		return n, err
+	}
+
+	n = int(r.remaining)
+	r.remaining = 0
+	r.err = ErrRequestBodyTooLarge
+	// The rest of the body is not read, the connection cannot be reused.
+	r.header.Set("Connection", "close")
+	return n, r.err
+}
+
+// ExpectsContinue reports whether the client sent the "Expect: 100-continue" header
+// and waits for a 100 Continue response before transmitting the request body.
+// It returns false after an `AcceptContinue` or `RejectContinue` call.
+// The 100 Continue response is sent automatically on the first request body read,
+// so middlewares can reject the request (e.g. authentication, quota
+// and Content-Length checks) before the body is transmitted, see `ExpectContinue` middleware.
+func (ctx *Context) ExpectsContinue() bool {
+	if ctx.request.ContentLength == 0 || !ctx.request.ProtoAtLeast(1, 1) {
+		return false
+	}
+
+	if _, answered := ctx.values.GetEntry(continueContextKey); answered {
+		return false
+	}
+
+	return strings.EqualFold(ctx.request.Header.Get("Expect"), "100-continue")
+}
+
+const continueContextKey = "iris.continue"
+
+// AcceptContinue sends the 100 Continue response to a client which expects it,
+// see `ExpectsContinue`. It blocks until the first byte of the body is received.
+// It's not required to call it, the response is sent automatically
+// on the first request body read, use it to tell the client to start
+// transmitting the body while the handler is still processing the request.
+// It does nothing if the client does not expect a 100 Continue response.
+func (ctx *Context) AcceptContinue() error {
+	if !ctx.ExpectsContinue() {
+		return nil
+	}
+	ctx.values.Set(continueContextKey, true)
+
+	// The net/http server sends the 100 Continue response on the first read,
+	// read a single byte (zero length reads do not pass through all body readers)
+	// and put it back.
+	var b [1]byte
+	body := ctx.request.Body
+	n, err := body.Read(b[:])
+	if err != nil && err != io.EOF {
+		return err
+	}
+
+	ctx.request.Body = &continueBody{Reader: io.MultiReader(bytes.NewReader(b[:n]), body), Closer: body}
+	return nil
+}
+
+type continueBody struct {
+	io.Reader
+	io.Closer
+}
+
+// RejectContinue rejects a request which expects a 100 Continue response, see `ExpectsContinue`,
+// with the given "statusCode" (e.g. 401, 413 or 417 Expectation Failed)
+// and stops the handlers chain. The request body is never transmitted
+// and the connection is closed after the response,
+// as the client may still send the body.
+// It fires the error code handlers, if the request does not expect
+// a 100 Continue response it just stops the execution with the given status code.
+func (ctx *Context) RejectContinue(statusCode int) {
+	if ctx.ExpectsContinue() {
+		ctx.values.Set(continueContextKey, false)
+		ctx.Header("Connection", "close")
+	}
+
+	ctx.StopWithStatus(statusCode)
 }
 
 // GetBody reads and returns the request body.
@@ -2561,9 +3016,25 @@ func (ctx *Context) ReadForm(formObject interface{}) error {
 }
 
 // ReadQuery binds URL Query to "ptr". The struct field tag is "url".
+// Fields with a "default" tag are filled with that value
+// when the URL Query does not contain them, see `DefaultTag`.
+//
+// Example Code:
+//  type Search struct {
+//      Term  string `url:"q"`
+//      Page  int    `url:"page" default:"1"`
+//      Limit int    `url:"limit" default:"25"`
+//  }
+//
+//  var s Search
+//  err := ctx.ReadQuery(&s)
 //
 // Example: https://github.com/kataras/iris/blob/master/_examples/request-body/read-query/main.go
 func (ctx *Context) ReadQuery(ptr interface{}) error {
+	if err := setDefaults(ptr); err != nil {
+		return err
+	}
+
 	values := ctx.getQuery()
 	if len(values) == 0 {
 		if ctx.app.ConfigurationReadOnly().GetFireEmptyFormError() {
@@ -2581,9 +3052,15 @@ func (ctx *Context) ReadQuery(ptr interface{}) error {
 }
 
 // ReadHeaders binds request headers to "ptr". The struct field tag is "header".
+// Fields with a "default" tag are filled with that value
+// when the request does not contain them, see `DefaultTag`.
 //
 // Example: https://github.com/kataras/iris/blob/master/_examples/request-body/read-headers/main.go
 func (ctx *Context) ReadHeaders(ptr interface{}) error {
+	if err := setDefaults(ptr); err != nil {
+		return err
+	}
+
 	err := schema.DecodeHeaders(ctx.request.Header, ptr)
 	if err != nil {
 		return err
@@ -2593,9 +3070,15 @@ func (ctx *Context) ReadHeaders(ptr interface{}) error {
 }
 
 // ReadParams binds URI Dynamic Path Parameters to "ptr". The struct field tag is "param".
+// Fields with a "default" tag are filled with that value
+// when the route does not contain them, see `DefaultTag`.
 //
 // Example: https://github.com/kataras/iris/blob/master/_examples/request-body/read-params/main.go
 func (ctx *Context) ReadParams(ptr interface{}) error {
+	if err := setDefaults(ptr); err != nil {
+		return err
+	}
+
 	n := ctx.params.Len()
 	if n == 0 {
 		return nil
@@ -2620,9 +3103,14 @@ func (ctx *Context) ReadParams(ptr interface{}) error {
 // ReadURL is a shortcut of ReadParams and ReadQuery.
 // It binds dynamic path parameters and URL query parameters
 // to the "ptr" pointer struct value.
-// The struct fields may contain "url" or "param" binding tags.
+// The struct fields may contain "url" or "param" binding tags
+// and a "default" tag, see `DefaultTag`.
 // If a validator exists then it validates the result too.
 func (ctx *Context) ReadURL(ptr interface{}) error {
+	if err := setDefaults(ptr); err != nil {
+		return err
+	}
+
 	values := make(map[string][]string, ctx.params.Len())
 	ctx.params.Visit(func(key string, value string) {
 		values[key] = strings.Split(value, "/")
@@ -2641,10 +3129,50 @@ func (ctx *Context) ReadURL(ptr interface{}) error {
 	return ctx.app.Validate(ptr)
 }
 
+// ProtobufMaxSize is the maximum size, in bytes, of the request body
+// read by the `ReadProtobuf` and `ReadJSONProtobuf` methods,
+// larger bodies fail with the `ErrRequestBodyTooLarge` error.
+// Zero or negative value means no limit (other than the `SetMaxRequestBodySize` one).
+// Defaults to 4MB, the default one of gRPC.
+var ProtobufMaxSize int64 = 4 << 20
+
+// ErrRequestBodyTooLarge is returned by the body readers which limit
+// the request body's size, e.g. see `ProtobufMaxSize`.
+// The `StopWithReadError` method sends it as 413 Request Entity Too Large.
+var ErrRequestBodyTooLarge = errors.New("request body too large")
+
+// getBodyLimit same as `GetBody` but it fails with `ErrRequestBodyTooLarge`
+// when the body is larger than "limit" bytes, without reading more than that.
+func (ctx *Context) getBodyLimit(limit int64) ([]byte, error) {
+	if limit <= 0 {
+		return ctx.GetBody()
+	}
+
+	if ctx.request.ContentLength > limit {
+		return nil, ErrRequestBodyTooLarge
+	}
+
+	data, err := ioutil.ReadAll(io.LimitReader(ctx.request.Body, limit+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(data)) > limit {
+		return nil, ErrRequestBodyTooLarge
+	}
+
+	if ctx.IsRecordingBody() {
+		ctx.request.Body = ioutil.NopCloser(bytes.NewBuffer(data))
+	}
+
+	return data, nil
+}
+
 // ReadProtobuf binds the body to the "ptr" of a proto Message and returns any error.
+// The body's size is limited by the `ProtobufMaxSize`.
 // Look `ReadJSONProtobuf` too.
 func (ctx *Context) ReadProtobuf(ptr proto.Message) error {
-	rawData, err := ctx.GetBody()
+	rawData, err := ctx.getBodyLimit(ProtobufMaxSize)
 	if err != nil {
 		return err
 	}
@@ -2658,9 +3186,10 @@ type ProtoUnmarshalOptions = protojson.UnmarshalOptions
 var defaultProtobufUnmarshalOptions ProtoUnmarshalOptions
 
 // ReadJSONProtobuf reads a JSON body request into the given "ptr" proto.Message.
+// The body's size is limited by the `ProtobufMaxSize`.
 // Look `ReadProtobuf` too.
 func (ctx *Context) ReadJSONProtobuf(ptr proto.Message, opts ...ProtoUnmarshalOptions) error {
-	rawData, err := ctx.GetBody()
+	rawData, err := ctx.getBodyLimit(ProtobufMaxSize)
 	if err != nil {
 		return err
 	}
@@ -2688,12 +3217,34 @@ func (ctx *Context) ReadMsgPack(ptr interface{}) error {
 	return ctx.app.Validate(ptr)
 }
 
+// ReadCBOR binds the request body of CBOR (RFC 8949) format to the "ptr" and returns any error.
+// See the `CBORUnmarshal` package-level variable too.
+func (ctx *Context) ReadCBOR(ptr interface{}) error {
+	rawData, err := ctx.GetBody()
+	if err != nil {
+		return err
+	}
+
+	err = CBORUnmarshal(rawData, ptr)
+	if err != nil {
+		return err
+	}
+
+	return ctx.app.Validate(ptr)
+}
+
 // ReadBody binds the request body to the "ptr" depending on the HTTP Method and the Request's Content-Type.
 // If a GET method request then it reads from a form (or URL Query), otherwise
 // it tries to match (depending on the request content-type) the data format e.g.
-// JSON, Protobuf, MsgPack, XML, YAML, MultipartForm and binds the result to the "ptr".
+// JSON, Protobuf, MsgPack, CBOR, XML, YAML, MultipartForm and binds the result to the "ptr".
 // As a special case if the "ptr" was a pointer to string or []byte
 // then it will bind it to the request body as it is.
+//
+// The result is validated by the Application's Validator
+// or, if it's nil, by the github.com/go-playground/validator one,
+// use the `StopWithReadError` method to send a structured 422 response
+// on validation failures. The rest of the Read methods
+// are validated only by the Application's Validator, see `Application.ValidateBody`.
 func (ctx *Context) ReadBody(ptr interface{}) error {
 
 	// If the ptr is string or byte, read the body as it's.
@@ -2705,15 +3256,27 @@ func (ctx *Context) ReadBody(ptr interface{}) error {
 		}
 
 		*v = string(b)
+		return nil
 	case *[]byte:
 		b, err := ctx.GetBody()
 		if err != nil {
 			return err
 		}
 
-		copy(*v, b)
+		*v = b
+		return nil
+	}
+
+	if err := ctx.readBody(ptr); err != nil {
+		return err
 	}
 
+	return ctx.app.ValidateBody(ptr)
+}
+
+// readBody is the `ReadBody` without the string and []byte cases
+// and the default validation.
+func (ctx *Context) readBody(ptr interface{}) error {
 	if ctx.Method() == http.MethodGet {
 		if ctx.Request().URL.RawQuery != "" {
 			// try read from query.
@@ -2737,6 +3300,10 @@ func (ctx *Context) ReadBody(ptr interface{}) error {
 	case ContentFormHeaderValue, ContentFormMultipartHeaderValue:
 		return ctx.ReadForm(ptr)
 	case ContentJSONHeaderValue:
+		if msg, ok := ptr.(proto.Message); ok {
+			return ctx.ReadJSONProtobuf(msg)
+		}
+
 		return ctx.ReadJSON(ptr)
 	case ContentProtobufHeaderValue:
 		msg, ok := ptr.(proto.Message)
@@ -2747,6 +3314,8 @@ func (ctx *Context) ReadBody(ptr interface{}) error {
 		return ctx.ReadProtobuf(msg)
 	case ContentMsgPackHeaderValue, ContentMsgPack2HeaderValue:
 		return ctx.ReadMsgPack(ptr)
+	case ContentCBORHeaderValue:
+		return ctx.ReadCBOR(ptr)
 	default:
 		if ctx.Request().URL.RawQuery != "" {
 			// try read from query.
@@ -2817,6 +3386,10 @@ const (
 	CacheControlHeaderKey = "Cache-Control"
 	// ETagHeaderKey is the header key of "ETag".
 	ETagHeaderKey = "ETag"
+	// RangeHeaderKey is the header key of "Range".
+	RangeHeaderKey = "Range"
+	// IfNoneMatchHeaderKey is the header key of "If-None-Match".
+	IfNoneMatchHeaderKey = "If-None-Match"
 
 	// ContentDispositionHeaderKey is the header key of "Content-Disposition".
 	ContentDispositionHeaderKey = "Content-Disposition"
@@ -2830,6 +3403,8 @@ const (
 	AcceptEncodingHeaderKey = "Accept-Encoding"
 	// VaryHeaderKey is the header key of "Vary".
 	VaryHeaderKey = "Vary"
+	// WWWAuthenticateHeaderKey is the header key of "WWW-Authenticate".
+	WWWAuthenticateHeaderKey = "WWW-Authenticate"
 )
 
 var unixEpochTime = time.Unix(0, 0)
@@ -2954,32 +3529,116 @@ func (ctx *Context) WriteWithExpiration(body []byte, modtime time.Time) (int, er
 	return ctx.writer.Write(body)
 }
 
-// StreamWriter registers the given stream writer for populating
-// response body.
-//
-// Access to context's and/or its' members is forbidden from writer.
-//
-// This function may be used in the following cases:
-//
-//     * if response body is too big (more than iris.LimitRequestBodySize(if set)).
-//     * if response body is streamed from slow external sources.
-//     * if response body must be streamed to the client in chunks.
-//     (aka `http server push`).
-func (ctx *Context) StreamWriter(writer func(w io.Writer) error) error {
-	cancelCtx := ctx.Request().Context()
-	notifyClosed := cancelCtx.Done()
-
-	for {
-		select {
-		// response writer forced to close, exit.
-		case <-notifyClosed:
-			return cancelCtx.Err()
-		default:
-			if err := writer(ctx.writer); err != nil {
-				return err
-			}
-			ctx.writer.Flush()
-		}
+// ComputeETag returns a strong entity tag of the "body",
+// or a weak one (prefixed with "W/") when "weak" is true.
+// See `WriteWithETag` method too.
+func ComputeETag(body []byte, weak bool) string {
+	sum := sha1.Sum(body)
+	etag := `"` + strconv.FormatInt(int64(len(body)), 16) + "-" + base64.RawURLEncoding.EncodeToString(sum[:15]) + `"`
+	if weak {
+		etag = "W/" + etag
+	}
+
+	return etag
+}
+
+// CheckIfNoneMatch checks if the response is modified based on the "etag"
+// and the "If-None-Match" request header sent by the client.
+// The weak comparison function is used, as defined in RFC 7232 section 2.3.2.
+//
+// Like `CheckIfModifiedSince`, a check for !modified && err == nil is necessary
+// to make sure that it's not modified, it returns an `ErrPreconditionFailed`
+// if the HTTP Method is not "GET" or "HEAD" or the request header is missing.
+func (ctx *Context) CheckIfNoneMatch(etag string) (bool, error) {
+	if method := ctx.Method(); method != http.MethodGet && method != http.MethodHead {
+		return false, fmt.Errorf("method: %w", ErrPreconditionFailed)
+	}
+	inm := ctx.GetHeader(IfNoneMatchHeaderKey)
+	if inm == "" || etag == "" {
+		return false, fmt.Errorf("missing etag: %w", ErrPreconditionFailed)
+	}
+
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, tag := range strings.Split(inm, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// IsNotModified reports whether the client's cached copy of the response is still fresh,
+// based on the "etag" and the "Last-Modified" response header (if any).
+// The "If-None-Match" request header takes precedence over the "If-Modified-Since" one,
+// as defined in RFC 7232 section 6.
+func (ctx *Context) IsNotModified(etag string) bool {
+	if ctx.GetHeader(IfNoneMatchHeaderKey) != "" {
+		modified, err := ctx.CheckIfNoneMatch(etag)
+		return !modified && err == nil
+	}
+
+	if lastModified := ctx.ResponseWriter().Header().Get(LastModifiedHeaderKey); lastModified != "" {
+		modtime, err := ParseTime(ctx, lastModified)
+		if err != nil {
+			return false
+		}
+
+		modified, err := ctx.CheckIfModifiedSince(modtime)
+		return !modified && err == nil
+	}
+
+	return false
+}
+
+// WriteWithETag works like `Write` but it sets the "ETag" response header
+// to the `ComputeETag` of the "body", unless it's already set by a previous handler,
+// and sends a 304 status code when the client's cached copy is still fresh,
+// see `IsNotModified` method for the supported conditional request headers.
+//
+// See the `cache#AutoETag` middleware to compute the entity tags of all responses.
+func (ctx *Context) WriteWithETag(body []byte) (int, error) {
+	etag := ctx.ResponseWriter().Header().Get(ETagHeaderKey)
+	if etag == "" {
+		etag = ComputeETag(body, false)
+		ctx.Header(ETagHeaderKey, etag)
+	}
+
+	if ctx.IsNotModified(etag) {
+		ctx.WriteNotModified()
+		return 0, nil
+	}
+
+	return ctx.writer.Write(body)
+}
+
+// StreamWriter registers the given stream writer for populating
+// response body.
+//
+// Access to context's and/or its' members is forbidden from writer.
+//
+// This function may be used in the following cases:
+//
+//     * if response body is too big (more than iris.LimitRequestBodySize(if set)).
+//     * if response body is streamed from slow external sources.
+//     * if response body must be streamed to the client in chunks.
+//     (aka `http server push`).
+func (ctx *Context) StreamWriter(writer func(w io.Writer) error) error {
+	cancelCtx := ctx.Request().Context()
+	notifyClosed := cancelCtx.Done()
+
+	for {
+		select {
+		// response writer forced to close, exit.
+		case <-notifyClosed:
+			return cancelCtx.Err()
+		default:
+			if err := writer(ctx.writer); err != nil {
+				return err
+			}
+			ctx.writer.Flush()
+		}
 	}
 }
 
@@ -3093,27 +3752,68 @@ func (ctx *Context) CompressWriter(enable bool) error {
 // (or empty)
 // or `ErrNotSupportedCompression` if server missing the decompression algorithm.
 func (ctx *Context) CompressReader(enable bool) error {
-	cr, ok := ctx.request.Body.(*CompressReader)
 	if enable {
-		if ok {
-			// already called.
-			return nil
+		return ctx.compressReader(0)
+	}
+
+	if cr, ok := ctx.request.Body.(*CompressReader); ok {
+		ctx.request.Body = cr.Src
+	}
+
+	return nil
+}
+
+// compressReader is the `CompressReader(true)` with a maximum decompressed size,
+// see `DecompressBody`.
+func (ctx *Context) compressReader(maxSize int64) error {
+	if cr, ok := ctx.request.Body.(*CompressReader); ok {
+		// already called.
+		if maxSize > 0 {
+			cr.SetMaxSize(maxSize)
 		}
+		return nil
+	}
+
+	encoding := ctx.GetHeader(ContentEncodingHeaderKey)
+	if encoding == IDENTITY {
+		// no transformation whatsoever, return nil error and
+		// don't wrap the body reader.
+		return nil
+	}
+
+	r, err := newCompressReader(ctx.request.Body, encoding, maxSize)
+	if err != nil {
+		return err
+	}
+	ctx.request.Body = r
 
-		encoding := ctx.GetHeader(ContentEncodingHeaderKey)
-		if encoding == IDENTITY {
-			// no transformation whatsoever, return nil error and
-			// don't wrap the body reader.
+	return nil
+}
+
+// DecompressBody wraps the request body reader with a reader which decompresses
+// the request data, based on the "Content-Encoding" header
+// ("gzip", "deflate", "br", "snappy", "s2" and "zstd"), before the
+// `GetBody/ReadJSON/ReadForm/ReadXXX` methods read them.
+// The "maxDecompressedSize" guards the server against decompression bombs,
+// reads over that limit fail with `ErrRequestBodyTooLarge`
+// (see `StopWithReadError`), zero or negative value means no limit.
+//
+// It returns nil if the request data are not compressed
+// or `ErrNotSupportedCompression` if server missing the decompression algorithm.
+// See the `DecompressRequest` middleware too.
+func (ctx *Context) DecompressBody(maxDecompressedSize int64) error {
+	if err := ctx.compressReader(maxDecompressedSize); err != nil {
+		if errors.Is(err, ErrRequestNotCompressed) {
 			return nil
 		}
 
-		r, err := NewCompressReader(ctx.request.Body, encoding)
-		if err != nil {
-			return err
-		}
-		ctx.request.Body = r
-	} else if ok {
-		ctx.request.Body = cr.Src
+		return err
+	}
+
+	if _, ok := ctx.request.Body.(*CompressReader); ok {
+		// The length of the decompressed data is unknown.
+		ctx.request.ContentLength = -1
+		ctx.request.Header.Del(ContentLengthHeaderKey)
 	}
 
 	return nil
@@ -3397,7 +4097,56 @@ func (ctx *Context) View(filename string, optionalViewModel ...interface{}) erro
 	return err
 }
 
+// ViewStream works like `View` but it writes the rendered template directly
+// to the (compressed) response writer and flushes it to the client in chunks
+// (see `ViewStreamChunkSize`), instead of buffering the whole output,
+// reducing the memory spikes of very large pages, e.g. reports.
+//
+// The output is flushed on the flush boundaries too, reducing the time to first byte:
+// the html engine flushes the layout's head (the output before the {{ yield }})
+// before it renders the page and the "flush" template function
+// flushes the sections rendered so far, e.g.
+//  <header>...</header>{{ flush }}<main>{{ .ExpensiveReport }}</main>
+//
+// Note that the response status code and headers are sent with the first chunk,
+// so a render error after that can not change the response, it's just logged.
+func (ctx *Context) ViewStream(filename string, optionalViewModel ...interface{}) error {
+	ctx.ContentType(ContentHTMLHeaderValue)
+
+	w := &ViewStreamWriter{ctx: ctx, chunkSize: ViewStreamChunkSize}
+	ctx.values.Set(viewStreamWriterContextKey, w)
+	err := ctx.renderViewTo(w, filename, optionalViewModel...)
+	ctx.values.Remove(viewStreamWriterContextKey)
+	if err == nil {
+		return nil
+	}
+
+	if w.Written() > 0 {
+		ctx.app.Logger().Errorf("view stream: %s: %v", filename, err)
+		ctx.StopExecution()
+		return err
+	}
+
+	if errNotExists, ok := err.(ErrViewNotExist); ok {
+		err = ctx.fireFallbackViewOnce(errNotExists)
+	}
+
+	if err != nil {
+		if ctx.app.Logger().Level == golog.DebugLevel {
+			ctx.StopWithError(http.StatusInternalServerError, err)
+		} else {
+			ctx.StopWithStatus(http.StatusInternalServerError)
+		}
+	}
+
+	return err
+}
+
 func (ctx *Context) renderView(filename string, optionalViewModel ...interface{}) error {
+	return ctx.renderViewTo(ctx, filename, optionalViewModel...)
+}
+
+func (ctx *Context) renderViewTo(w io.Writer, filename string, optionalViewModel ...interface{}) error {
 	cfg := ctx.app.ConfigurationReadOnly()
 	layout := ctx.values.GetString(cfg.GetViewLayoutContextKey())
 
@@ -3412,12 +4161,12 @@ func (ctx *Context) renderView(filename string, optionalViewModel ...interface{}
 	if key := cfg.GetViewEngineContextKey(); key != "" {
 		if engineV := ctx.values.Get(key); engineV != nil {
 			if engine, ok := engineV.(ViewEngine); ok {
-				return engine.ExecuteWriter(ctx, filename, layout, bindingData)
+				return engine.ExecuteWriter(w, filename, layout, bindingData)
 			}
 		}
 	}
 
-	return ctx.app.View(ctx, filename, layout, bindingData)
+	return ctx.app.View(w, filename, layout, bindingData)
 }
 
 const (
@@ -3435,6 +4184,8 @@ const (
 	// ContentXMLProblemHeaderValue header value for XML API problem error.
 	// Read more at: https://tools.ietf.org/html/rfc7807
 	ContentXMLProblemHeaderValue = "application/problem+xml"
+	// ContentNDJSONHeaderValue header value for newline-delimited JSON streams.
+	ContentNDJSONHeaderValue = "application/x-ndjson"
 	// ContentJavascriptHeaderValue header value for JSONP & Javascript data.
 	ContentJavascriptHeaderValue = "text/javascript"
 	// ContentTextHeaderValue header value for Text data.
@@ -3455,6 +4206,8 @@ const (
 	ContentMsgPackHeaderValue = "application/msgpack"
 	// ContentMsgPack2HeaderValue alternative header value for MsgPack data.
 	ContentMsgPack2HeaderValue = "application/x-msgpack"
+	// ContentCBORHeaderValue header value for CBOR (RFC 8949) data.
+	ContentCBORHeaderValue = "application/cbor"
 	// ContentFormHeaderValue header value for post form data.
 	ContentFormHeaderValue = "application/x-www-form-urlencoded"
 	// ContentFormMultipartHeaderValue header value for post multipart form data.
@@ -3681,6 +4434,149 @@ func (ctx *Context) JSON(v interface{}, opts ...JSON) (n int, err error) {
 	return n, err
 }
 
+// StreamJSONOptions holds the optional settings of the `Context.StreamJSON` method.
+type StreamJSONOptions struct {
+	// FlushInterval is the maximum duration the encoded values
+	// are kept in the response writer's buffer before they are flushed to the client.
+	// Defaults to 1 second.
+	FlushInterval time.Duration
+	// FlushCount is the maximum number of encoded values
+	// which are kept in the response writer's buffer before they are flushed to the client.
+	// Defaults to 100.
+	FlushCount int
+}
+
+// StreamJSON encodes the values of the "iter" as newline-delimited JSON (NDJSON)
+// and streams them to the client, without buffering the whole response in memory.
+// The "iter" can be a receive channel of any type, read until it's closed,
+// or an iterator function of form: func(yield func(v interface{}) bool).
+// If a value is an error then the streaming stops and that error is returned.
+// The encoded values are flushed periodically, see `StreamJSONOptions`.
+// It stops and returns the request context's error when the client disconnects.
+//
+// Usage:
+//  ctx.StreamJSON(func(yield func(v interface{}) bool) {
+//   rows, _ := db.Query("SELECT * FROM orders")
+//   defer rows.Close()
+//   for rows.Next() {
+//    var o Order
+//    rows.Scan(&o.ID, &o.Total)
+//    if !yield(o) {
+//     return
+//    }
+//   }
+//  })
+func (ctx *Context) StreamJSON(iter interface{}, opts ...StreamJSONOptions) error {
+	var options StreamJSONOptions
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+	if options.FlushInterval <= 0 {
+		options.FlushInterval = time.Second
+	}
+	if options.FlushCount <= 0 {
+		options.FlushCount = 100
+	}
+
+	ctx.ContentType(ContentNDJSONHeaderValue)
+
+	reqCtx := ctx.request.Context()
+	s := &jsonStreamer{
+		writer:    ctx.writer,
+		enc:       json.NewEncoder(ctx.writer),
+		options:   options,
+		lastFlush: time.Now(),
+	}
+
+	switch it := iter.(type) {
+	case func(yield func(v interface{}) bool):
+		var err error
+		it(func(v interface{}) bool {
+			if err = reqCtx.Err(); err != nil {
+				return false
+			}
+
+			err = s.encode(v)
+			return err == nil
+		})
+
+		if err != nil {
+			return err
+		}
+	default:
+		ch := reflect.ValueOf(iter)
+		if ch.Kind() != reflect.Chan || ch.Type().ChanDir()&reflect.RecvDir == 0 {
+			return fmt.Errorf("stream json: unsupported iterator type: %T", iter)
+		}
+
+		ticker := time.NewTicker(options.FlushInterval)
+		defer ticker.Stop()
+
+		cases := []reflect.SelectCase{
+			{Dir: reflect.SelectRecv, Chan: ch},
+			{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(reqCtx.Done())},
+			{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ticker.C)},
+		}
+
+	loop:
+		for {
+			chosen, v, ok := reflect.Select(cases)
+			switch chosen {
+			case 0:
+				if !ok {
+					break loop
+				}
+
+				if err := s.encode(v.Interface()); err != nil {
+					return err
+				}
+			case 1:
+				return reqCtx.Err()
+			default:
+				s.flush()
+			}
+		}
+	}
+
+	s.flush()
+	return nil
+}
+
+type jsonStreamer struct {
+	writer    ResponseWriter
+	enc       *json.Encoder
+	options   StreamJSONOptions
+	pending   int
+	lastFlush time.Time
+}
+
+func (s *jsonStreamer) encode(v interface{}) error {
+	if err, ok := v.(error); ok {
+		return err
+	}
+
+	if err := s.enc.Encode(v); err != nil {
+		return err
+	}
+
+	s.pending++
+	if s.pending >= s.options.FlushCount || time.Since(s.lastFlush) >= s.options.FlushInterval {
+		s.flush()
+	}
+
+	return nil
+}
+
+func (s *jsonStreamer) flush() {
+	if s.pending == 0 {
+		return
+	}
+
+	s.writer.Flush()
+	s.pending = 0
+	s.lastFlush = time.Now()
+}
+
 var finishCallbackB = []byte(");")
 
 // WriteJSONP marshals the given interface object and writes the JSON response to the writer.
@@ -3967,6 +4863,18 @@ func (ctx *Context) MsgPack(v interface{}) (int, error) {
 	return ctx.Write(out)
 }
 
+// CBOR parses the "v" of CBOR (RFC 8949) format and renders its result to the client.
+// See the `CBORMarshal` package-level variable too.
+func (ctx *Context) CBOR(v interface{}) (int, error) {
+	out, err := CBORMarshal(v)
+	if err != nil {
+		return 0, err
+	}
+
+	ctx.ContentType(ContentCBORHeaderValue)
+	return ctx.Write(out)
+}
+
 //  +-----------------------------------------------------------------------+
 //  | Content Νegotiation                                                   |
 //  | https://developer.mozilla.org/en-US/docs/Web/HTTP/Content_negotiation |                                       |
@@ -4019,6 +4927,7 @@ type N struct {
 	YAML     interface{}
 	Protobuf interface{}
 	MsgPack  interface{}
+	CBOR     interface{}
 
 	Other []byte // custom content types.
 }
@@ -4050,6 +4959,8 @@ func (n N) SelectContent(mime string) interface{} {
 		return n.Protobuf
 	case ContentMsgPackHeaderValue, ContentMsgPack2HeaderValue:
 		return n.MsgPack
+	case ContentCBORHeaderValue:
+		return n.CBOR
 	default:
 		return n.Other
 	}
@@ -4194,6 +5105,8 @@ func (ctx *Context) Negotiate(v interface{}) (int, error) {
 		return ctx.Protobuf(msg)
 	case ContentMsgPackHeaderValue, ContentMsgPack2HeaderValue:
 		return ctx.MsgPack(v)
+	case ContentCBORHeaderValue:
+		return ctx.CBOR(v)
 	default:
 		// maybe "Other" or v is []byte or string but not a built-in framework mime,
 		// for custom content types,
@@ -4407,6 +5320,19 @@ func (n *NegotiationBuilder) MsgPack(v ...interface{}) *NegotiationBuilder {
 	return n.MIME(ContentMsgPackHeaderValue+","+ContentMsgPack2HeaderValue, content)
 }
 
+// CBOR registers the "application/cbor" content type and, optionally,
+// a value that `Context.Negotiate` will render
+// when a client accepts the "application/cbor" content type.
+//
+// Returns itself for recursive calls.
+func (n *NegotiationBuilder) CBOR(v ...interface{}) *NegotiationBuilder {
+	var content interface{}
+	if len(v) > 0 {
+		content = v[0]
+	}
+	return n.MIME(ContentCBORHeaderValue, content)
+}
+
 // Any registers a wildcard that can match any client's accept content type.
 //
 // Returns itself for recursive calls.
@@ -4609,7 +5535,13 @@ func (n *NegotiationAcceptBuilder) Protobuf() *NegotiationAcceptBuilder {
 // MsgPack adds the "application/msgpack" and "application/x-msgpack" as accepted client content types.
 // Returns itself.
 func (n *NegotiationAcceptBuilder) MsgPack() *NegotiationAcceptBuilder {
-	return n.MIME(ContentYAMLHeaderValue)
+	return n.MIME(ContentMsgPackHeaderValue, ContentMsgPack2HeaderValue)
+}
+
+// CBOR adds the "application/cbor" as accepted client content type.
+// Returns itself.
+func (n *NegotiationAcceptBuilder) CBOR() *NegotiationAcceptBuilder {
+	return n.MIME(ContentCBORHeaderValue)
 }
 
 // Charset adds one or more client accepted charsets.
@@ -4663,9 +5595,14 @@ func (n *NegotiationAcceptBuilder) EncodingGzip() *NegotiationAcceptBuilder {
 // If the caller has set w's ETag header formatted per RFC 7232, section 2.3,
 // ServeContent uses it to handle requests using If-Match, If-None-Match, or If-Range.
 //
+// Range requests are answered with a 206 Partial Content response,
+// multiple ranges are sent as a "multipart/byteranges" body,
+// see `Context.SendFile` for resumable downloads.
+//
 // Note that *os.File implements the io.ReadSeeker interface.
 // Note that compression can be registered
-// through `ctx.CompressWriter(true)` or `app.Use(iris.Compression)`.
+// through `ctx.CompressWriter(true)` or `app.Use(iris.Compression)`,
+// it is disabled on range requests because the ranges refer to the original content.
 func (ctx *Context) ServeContent(content io.ReadSeeker, filename string, modtime time.Time) {
 	ctx.ServeContentWithRate(content, filename, modtime, 0, 0)
 }
@@ -4700,12 +5637,35 @@ func (ctx *Context) ServeContentWithRate(content io.ReadSeeker, filename string,
 	}
 
 	if ctx.GetContentType() == "" {
-		ctx.ContentType(filename)
+		// Let the http.ServeContent sniff the content type if the extension is unknown.
+		if cType := mime.TypeByExtension(filepath.Ext(filename)); cType != "" {
+			ctx.ContentType(cType)
+		}
+	}
+
+	if ctx.GetHeader(RangeHeaderKey) != "" {
+		ctx.disableCompressWriter()
 	}
 
 	http.ServeContent(ctx.writer, ctx.request, filename, modtime, content)
 }
 
+// disableCompressWriter disables the on-the-fly compression (if any)
+// and removes its headers before the response is written.
+func (ctx *Context) disableCompressWriter() {
+	w := ctx.writer
+	if rec, ok := w.(*ResponseRecorder); ok {
+		w = rec.ResponseWriter
+	}
+
+	if cw, ok := w.(*CompressResponseWriter); ok && !cw.Disabled {
+		cw.Disabled = true
+		cw.FlushHeaders()
+		ctx.writer.Header().Del(ContentEncodingHeaderKey)
+		ctx.writer.Header().Del(VaryHeaderKey)
+	}
+}
+
 // ServeFile replies to the request with the contents of the named
 // file or directory.
 //
@@ -4756,6 +5716,7 @@ func (ctx *Context) ServeFileWithRate(filename string, limit float64, burst int)
 }
 
 // SendFile sends a file as an attachment, that is downloaded and saved locally from client.
+// It supports the "Range" and "If-Range" request headers, so interrupted downloads can be resumed.
 // Note that compression can be registered
 // through `ctx.CompressWriter(true)` or `app.Use(iris.Compression)`.
 // Use `ServeFile` if a file should be served as a page asset instead.
@@ -4900,6 +5861,54 @@ func CookieSameSite(sameSite http.SameSite) CookieOption {
 	}
 }
 
+// CookieSameSiteNone is a `CookieOption`.
+// Use it to allow the cookie to be sent on cross-site requests,
+// browsers require such cookies to be Secure, so it sets the Secure field too.
+func CookieSameSiteNone(_ *Context, c *http.Cookie, op uint8) {
+	if op == OpCookieSet {
+		c.SameSite = http.SameSiteNoneMode
+		c.Secure = true
+	}
+}
+
+const cookiePartitionedAttr = "Partitioned"
+
+// CookiePartitioned is a `CookieOption`.
+// Use it to add the Partitioned attribute (CHIPS) to the cookie,
+// so it's stored in a separate cookie jar per top-level site
+// when the application is embedded in a third-party context (e.g. iframes).
+// Partitioned cookies should be Secure, so it sets the Secure field too.
+// See `CookieSameSiteNone` too.
+func CookiePartitioned(_ *Context, c *http.Cookie, op uint8) {
+	if op == OpCookieGet || isCookiePartitioned(c) {
+		return
+	}
+
+	c.Secure = true
+	c.Unparsed = append(c.Unparsed, cookiePartitionedAttr)
+}
+
+func isCookiePartitioned(c *http.Cookie) bool {
+	for _, attr := range c.Unparsed {
+		if attr == cookiePartitionedAttr {
+			return true
+		}
+	}
+
+	return false
+}
+
+// cookieString returns the Set-Cookie header value of the cookie,
+// including the attributes that the net/http package does not support.
+func cookieString(c *http.Cookie) string {
+	s := c.String()
+	if s != "" && isCookiePartitioned(c) {
+		s += "; " + cookiePartitionedAttr
+	}
+
+	return s
+}
+
 // CookieSecure sets the cookie's Secure option if the current request's
 // connection is using TLS. See `CookieHTTPOnly` too.
 func CookieSecure(ctx *Context, c *http.Cookie, op uint8) {
@@ -5031,6 +6040,8 @@ const cookieOptionsContextKey = "iris.cookie.options"
 //  * CookieSecure
 //  * CookieHTTPOnly
 //  * CookieSameSite
+//  * CookieSameSiteNone
+//  * CookiePartitioned
 //  * CookiePath
 //  * CookieCleanPath
 //  * CookieExpires
@@ -5079,7 +6090,9 @@ func (ctx *Context) ClearCookieOptions() {
 // Example: https://github.com/kataras/iris/tree/master/_examples/cookies/basic
 func (ctx *Context) SetCookie(cookie *http.Cookie, options ...CookieOption) {
 	ctx.applyCookieOptions(cookie, OpCookieSet, options)
-	http.SetCookie(ctx.writer, cookie)
+	if v := cookieString(cookie); v != "" {
+		ctx.writer.Header().Add(setCookieHeaderKey, v)
+	}
 }
 
 const setCookieHeaderKey = "Set-Cookie"
@@ -5100,14 +6113,14 @@ func (ctx *Context) UpsertCookie(cookie *http.Cookie, options ...CookieOption) b
 				// We need to update the Set-Cookie (to update the expiration or any other cookie's properties).
 				// Probably the cookie is set and then updated in the first session creation
 				// (e.g. UpdateExpiration, see https://github.com/kataras/iris/issues/1485).
-				cookies[i] = cookie.String()
+				cookies[i] = cookieString(cookie)
 				header[setCookieHeaderKey] = cookies
 				return false
 			}
 		}
 	}
 
-	header.Add(setCookieHeaderKey, cookie.String())
+	header.Add(setCookieHeaderKey, cookieString(cookie))
 	return true
 }
 
@@ -5169,6 +6182,80 @@ func (ctx *Context) GetCookie(name string, options ...CookieOption) string {
 	return value
 }
 
+// ErrCookieCodecMissing is returned by the `SetSecureCookie` and `GetSecureCookie` methods
+// when the Application has no cookie codec, see `Application.SetCookieCodec`.
+var ErrCookieCodecMissing = errors.New("cookie codec is missing")
+
+func (ctx *Context) cookieCodec() SecureCookie {
+	if app, ok := ctx.app.(interface{ CookieCodec() SecureCookie }); ok {
+		return app.CookieCodec()
+	}
+
+	return nil
+}
+
+// SetSecureCookie adds a signed and, optionally, encrypted cookie
+// using the Application's cookie codec, see `Application.SetCookieCodec` and `NewCookieCodec`.
+// The "value" can be a string, a byte slice or any JSON-encoded value.
+//
+// By default, like `SetCookieKV`, it is HttpOnly, it expires after `SetCookieKVExpiration`,
+// it is added to the root URL path and its SameSite is Lax,
+// use the `CookieOption` "options" to modify them
+// (e.g. `CookieSecure`, `CookieSameSiteNone` and `CookiePartitioned`).
+//
+// It returns `ErrCookieCodecMissing` if the Application has no cookie codec
+// and `ErrCookieTooLarge` if the encoded value exceeds the browser limits.
+//
+// See `GetSecureCookie` too.
+func (ctx *Context) SetSecureCookie(name string, value interface{}, options ...CookieOption) error {
+	codec := ctx.cookieCodec()
+	if codec == nil {
+		return ErrCookieCodecMissing
+	}
+
+	encoded, err := codec.Encode(name, value)
+	if err != nil {
+		return err
+	}
+
+	c := &http.Cookie{
+		Name:     name,
+		Value:    encoded,
+		Path:     "/",
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+		Expires:  time.Now().Add(SetCookieKVExpiration),
+		MaxAge:   int(SetCookieKVExpiration.Seconds()),
+	}
+
+	ctx.SetCookie(c, options...)
+	return nil
+}
+
+// GetSecureCookie verifies and decodes the value of a cookie
+// which was set by `SetSecureCookie` and binds it to the "ptr".
+// The "ptr" should be a string or a byte slice pointer
+// or any JSON-decoded value pointer, the same type used to set the cookie.
+//
+// It returns `http.ErrNoCookie` if the cookie is missing,
+// `ErrCookieCodecMissing` if the Application has no cookie codec
+// and `ErrInvalidCookie` (or `ErrExpiredCookie`) if the value was modified,
+// encoded by an unknown key or expired.
+func (ctx *Context) GetSecureCookie(name string, ptr interface{}, options ...CookieOption) error {
+	codec := ctx.cookieCodec()
+	if codec == nil {
+		return ErrCookieCodecMissing
+	}
+
+	c, err := ctx.request.Cookie(name)
+	if err != nil {
+		return err
+	}
+
+	ctx.applyCookieOptions(c, OpCookieGet, options)
+	return codec.Decode(name, c.Value, ptr)
+}
+
 var (
 	// CookieExpireDelete may be set on Cookie.Expire for expiring the given cookie.
 	CookieExpireDelete = time.Date(2009, time.November, 10, 23, 0, 0, 0, time.UTC)
@@ -5193,7 +6280,9 @@ func (ctx *Context) RemoveCookie(name string, options ...CookieOption) {
 	c.MaxAge = -1
 
 	ctx.applyCookieOptions(c, OpCookieDel, options)
-	http.SetCookie(ctx.writer, c)
+	if v := cookieString(c); v != "" {
+		ctx.writer.Header().Add(setCookieHeaderKey, v)
+	}
 }
 
 // VisitAllCookies takes a visitor function which is called
@@ -5247,6 +6336,36 @@ func (ctx *Context) Recorder() *ResponseRecorder {
 	return ctx.writer.(*ResponseRecorder)
 }
 
+// ErrNotRecording is returned by the `Rollback` method
+// when the response is not recorded, see `Record`.
+var ErrNotRecording = errors.New("response is not recorded")
+
+// Rollback discards the response (status code, headers and body)
+// written since the `Record` call, so a later middleware
+// or an error handler can substitute a different response,
+// e.g. for "all-or-nothing" handlers.
+// It returns `ErrNotRecording` if the response is not recorded
+// and `ErrResponseHeadersWritten` if it was already flushed to the client.
+//
+// Usage:
+//  app.Use(func(ctx iris.Context) {
+//      ctx.Record()
+//      ctx.Next()
+//
+//      if err := ctx.GetErr(); err != nil {
+//          ctx.Rollback()
+//          ctx.StopWithJSON(iris.StatusInternalServerError, iris.Map{"error": "try again later"})
+//      }
+//  })
+func (ctx *Context) Rollback() error {
+	rec, ok := ctx.IsRecording()
+	if !ok {
+		return ErrNotRecording
+	}
+
+	return rec.Rollback()
+}
+
 // IsRecording returns the response recorder and a true value
 // when the response writer is recording the status code, body, headers and so on,
 // else returns nil and false.
@@ -5500,6 +6619,31 @@ func (ctx *Context) IsDebug() bool {
 	return ctx.app.IsDebug()
 }
 
+// SetClock overrides the Clock of this request,
+// e.g. a middleware can freeze the time of a request on tests.
+// See `Now` method and `MockClock` type.
+func (ctx *Context) SetClock(clock Clock) {
+	ctx.clock = clock
+}
+
+// Now returns the current time based on the request's Clock,
+// if not set by `SetClock` then the Application's one is used,
+// if the Application has no a `Clock() Clock` method then the `SystemClock` is used.
+//
+// Framework's time-dependent features use that method instead of the time.Now,
+// so their behavior can be tested deterministically.
+func (ctx *Context) Now() time.Time {
+	if ctx.clock != nil {
+		return ctx.clock.Now()
+	}
+
+	if app, ok := ctx.app.(interface{ Clock() Clock }); ok {
+		return app.Clock().Now()
+	}
+
+	return SystemClock.Now()
+}
+
 // SetErr is just a helper that sets an error value
 // as a context value, it does nothing more.
 // Also, by-default this error's value is written to the client
@@ -5782,6 +6926,31 @@ func (ctx *Context) User() User {
 	return nil
 }
 
+const featuresContextKey = "iris.features"
+
+// SetFeature sets the state of the "name" feature flag for this request,
+// e.g. from a flags provider middleware based on the current user.
+// Unlike the route-level feature flags (see `Party.PartyIf`),
+// these are resolved per request.
+//
+// See `FeatureEnabled` too.
+func (ctx *Context) SetFeature(name string, enabled bool) {
+	features, ok := ctx.values.Get(featuresContextKey).(map[string]bool)
+	if !ok {
+		features = make(map[string]bool)
+		ctx.values.Set(featuresContextKey, features)
+	}
+
+	features[name] = enabled
+}
+
+// FeatureEnabled reports whether the "name" feature flag is enabled for this request.
+// It returns false if not set by a prior `SetFeature` call.
+func (ctx *Context) FeatureEnabled(name string) bool {
+	features, _ := ctx.values.Get(featuresContextKey).(map[string]bool)
+	return features[name]
+}
+
 const idContextKey = "iris.context.id"
 
 // SetID sets an ID, any value, to the Request Context.
@@ -5800,6 +6969,92 @@ func (ctx *Context) GetID() interface{} {
 	return ctx.values.Get(idContextKey)
 }
 
+const fingerprintContextKey = "iris.fingerprint"
+
+// Fingerprint returns the client's request fingerprint.
+// If not filled by a prior `SetFingerprint` call (e.g. from the "middleware/botdetect")
+// it computes and stores a baseline one: an ID based on the request headers
+// and the client's IP, without a score or class.
+//
+// See `SetFingerprint` too.
+func (ctx *Context) Fingerprint() *Fingerprint {
+	if v := ctx.values.Get(fingerprintContextKey); v != nil {
+		if fp, ok := v.(*Fingerprint); ok {
+			return fp
+		}
+	}
+
+	fp := newFingerprint(ctx)
+	ctx.values.Set(fingerprintContextKey, fp)
+	return fp
+}
+
+// SetFingerprint runs the given fingerprinters against the request's `Fingerprint`
+// and returns the result. The fingerprinters are executed in order,
+// each one can modify the score, class and signals of the previous ones.
+//
+// See `Fingerprint` and "middleware/botdetect" package too.
+func (ctx *Context) SetFingerprint(fingerprinters ...Fingerprinter) *Fingerprint {
+	fp := ctx.Fingerprint()
+	for _, f := range fingerprinters {
+		f.Fingerprint(ctx, fp)
+	}
+
+	return fp
+}
+
+// Baggage returns the W3C baggage of the request.
+// If not set by a prior `SetBaggage` call (e.g. from the "middleware/baggage")
+// it parses the "baggage" request header, malformed members are skipped.
+// The result is never nil.
+//
+// Example Code:
+//  tenant := ctx.Baggage().Get("tenant")
+//  variant := ctx.Baggage().GetIntDefault("experiment.variant", 0)
+//
+// See `SetBaggage` too.
+func (ctx *Context) Baggage() *Baggage {
+	if b := BaggageFromContext(ctx.request.Context()); b != nil {
+		return b
+	}
+
+	b, _ := ParseBaggage(ctx.GetHeader(BaggageHeaderKey))
+	ctx.SetBaggage(b)
+	return b
+}
+
+// SetBaggage sets the W3C baggage of the request.
+// The baggage is stored in the request's context, so outgoing requests
+// made with that context propagate it (see the "middleware/baggage" package).
+func (ctx *Context) SetBaggage(baggage *Baggage) {
+	ctx.request = ctx.request.WithContext(WithBaggage(ctx.request.Context(), baggage))
+}
+
+const clientHintsContextKey = "iris.clienthints"
+
+// ClientHints returns the Client Hints of the request,
+// parsed once from the request headers. The result is never nil.
+// Most of the hints are sent by the client only when the server asks for them,
+// see the "middleware/clienthints" package.
+//
+// Example Code:
+//  hints := ctx.ClientHints()
+//  if hints.Mobile || hints.PrefersReducedData() {
+//      [...]
+//  }
+//  width := hints.ImageWidth(320, 640, 1280)
+func (ctx *Context) ClientHints() *ClientHints {
+	if v := ctx.values.Get(clientHintsContextKey); v != nil {
+		if hints, ok := v.(*ClientHints); ok {
+			return hints
+		}
+	}
+
+	hints := ParseClientHints(ctx.request.Header)
+	ctx.values.Set(clientHintsContextKey, hints)
+	return hints
+}
+
 // String returns the string representation of this request.
 //
 // It returns the Context's ID given by a `SetID`call,
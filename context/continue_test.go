@@ -0,0 +1,114 @@
+package context_test
+
+import (
+	"bufio"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12"
+)
+
+func TestExpectContinue(t *testing.T) {
+	app := iris.New()
+	app.Use(iris.LimitRequestBodySize(10))
+	app.Post("/upload", iris.ExpectContinue(func(ctx iris.Context) int {
+		if ctx.GetHeader("Authorization") == "" {
+			return iris.StatusUnauthorized
+		}
+
+		return 0
+	}), func(ctx iris.Context) {
+		if err := ctx.AcceptContinue(); err != nil {
+			ctx.StopWithError(iris.StatusBadRequest, err)
+			return
+		}
+
+		if ctx.ExpectsContinue() {
+			t.Error("expected continue to be answered")
+		}
+
+		body, err := ctx.GetBody()
+		if err != nil {
+			ctx.StopWithError(iris.StatusBadRequest, err)
+			return
+		}
+
+		ctx.Write(body)
+	})
+
+	if err := app.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := &http.Server{Handler: app}
+	go srv.Serve(ln)
+	defer srv.Close()
+
+	tests := []struct {
+		headers        string
+		expectedStatus string
+		sendBody       bool
+	}{
+		{"Content-Length: 5\r\n", "401", false},
+		{"Content-Length: 100\r\nAuthorization: Bearer x\r\n", "413", false},
+		{"Content-Length: 5\r\nAuthorization: Bearer x\r\n", "100", true},
+	}
+
+	for i, tt := range tests {
+		client, err := net.Dial("tcp", ln.Addr().String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		client.SetDeadline(time.Now().Add(5 * time.Second))
+
+		_, err = client.Write([]byte("POST /upload HTTP/1.1\r\nHost: localhost\r\nExpect: 100-continue\r\n" + tt.headers + "\r\n"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		r := bufio.NewReader(client)
+		status, err := r.ReadString('\n')
+		if err != nil || !strings.Contains(status, tt.expectedStatus) {
+			t.Fatalf("[%d] expected status %s but got: %q: %v", i, tt.expectedStatus, status, err)
+		}
+
+		if tt.sendBody {
+			r.ReadString('\n') // empty line after 100 Continue.
+			if _, err = client.Write([]byte("hello")); err != nil {
+				t.Fatal(err)
+			}
+
+			resp, err := http.ReadResponse(r, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if body := readAll(t, resp); resp.StatusCode != iris.StatusOK || body != "hello" {
+				t.Fatalf("[%d] expected 200 hello but got: %d %s", i, resp.StatusCode, body)
+			}
+		}
+
+		client.Close()
+	}
+}
+
+func readAll(t *testing.T, resp *http.Response) string {
+	t.Helper()
+
+	defer resp.Body.Close()
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(b)
+}
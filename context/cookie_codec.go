@@ -0,0 +1,225 @@
+package context
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+var (
+	// ErrInvalidCookie is returned when a secure cookie's signature
+	// does not match any of the keys or it can not be decrypted.
+	ErrInvalidCookie = errors.New("securecookie: invalid cookie value")
+	// ErrExpiredCookie is returned when a secure cookie's timestamp
+	// is older than the `CookieCodec.MaxAge`.
+	ErrExpiredCookie = errors.New("securecookie: expired cookie")
+	// ErrCookieTooLarge is returned when an encoded cookie value
+	// exceeds the 4096 bytes that browsers accept.
+	ErrCookieTooLarge = errors.New("securecookie: encoded value is too large")
+)
+
+// CookieKey holds the keys of a `CookieCodec` generation.
+type CookieKey struct {
+	// Hash is the HMAC-SHA256 signing key, it's required.
+	// A key of 32 or 64 random bytes is recommended.
+	Hash []byte
+	// Block is the optional AES-GCM encryption key,
+	// it should be 16, 24 or 32 bytes long to select AES-128, AES-192 or AES-256.
+	// If empty then the cookie values are signed but not encrypted.
+	Block []byte
+}
+
+type cookieKey struct {
+	hash []byte
+	aead cipher.AEAD
+}
+
+// CookieCodec signs and, optionally, encrypts cookie values.
+// It completes the `SecureCookie` interface, so it can be used
+// as an Application-level codec for the `Context.SetSecureCookie`
+// and `Context.GetSecureCookie` methods or through the `CookieEncoding` option.
+//
+// The first key generation is used to encode new values and all of them
+// are accepted on decode, so keys can be rotated by prepending a new
+// generation and removing the oldest one after the cookies' lifetime.
+//
+// Create a new CookieCodec through the `NewCookieCodec` package-level function.
+type CookieCodec struct {
+	keys []cookieKey
+
+	// MaxAge, if greater than zero, rejects values that
+	// were encoded before that duration, even if the browser still sends them.
+	//
+	// Defaults to zero.
+	MaxAge time.Duration
+	// Clock is used to timestamp and validate the values.
+	//
+	// Defaults to the `SystemClock`.
+	Clock Clock
+}
+
+var _ SecureCookie = (*CookieCodec)(nil)
+
+// NewCookieCodec returns a new CookieCodec. The first key generation
+// encodes the values, all of them are accepted to decode.
+//
+// Example Code:
+//  codec, err := context.NewCookieCodec(
+//      context.CookieKey{Hash: newHashKey, Block: newBlockKey},
+//      context.CookieKey{Hash: oldHashKey, Block: oldBlockKey},
+//  )
+//  app.SetCookieCodec(codec)
+func NewCookieCodec(keys ...CookieKey) (*CookieCodec, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("securecookie: at least one key is required")
+	}
+
+	c := &CookieCodec{Clock: SystemClock}
+	for i, k := range keys {
+		if len(k.Hash) == 0 {
+			return nil, fmt.Errorf("securecookie: key[%d]: hash key is required", i)
+		}
+
+		key := cookieKey{hash: k.Hash}
+		if len(k.Block) > 0 {
+			block, err := aes.NewCipher(k.Block)
+			if err != nil {
+				return nil, fmt.Errorf("securecookie: key[%d]: %w", i, err)
+			}
+
+			if key.aead, err = cipher.NewGCM(block); err != nil {
+				return nil, fmt.Errorf("securecookie: key[%d]: %w", i, err)
+			}
+		}
+
+		c.keys = append(c.keys, key)
+	}
+
+	return c, nil
+}
+
+const (
+	cookieTimestampLen = 8
+	maxCookieValueLen  = 4096
+)
+
+// Encode signs and, if the current key has a block key, encrypts the "value".
+// The "value" can be a string, a byte slice or any JSON-encoded value.
+// The cookie name takes part on the signature,
+// so a value of one cookie can not be used as the value of another.
+func (c *CookieCodec) Encode(cookieName string, value interface{}) (string, error) {
+	var data []byte
+	switch v := value.(type) {
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		b, err := json.Marshal(value)
+		if err != nil {
+			return "", err
+		}
+		data = b
+	}
+
+	key := c.keys[0]
+
+	b := make([]byte, cookieTimestampLen, cookieTimestampLen+len(data)+sha256.Size+32)
+	binary.BigEndian.PutUint64(b, uint64(c.now().Unix()))
+
+	if key.aead != nil {
+		nonce := make([]byte, key.aead.NonceSize())
+		if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+			return "", err
+		}
+
+		b = append(b, nonce...)
+		b = key.aead.Seal(b, nonce, data, []byte(cookieName))
+	} else {
+		b = append(b, data...)
+	}
+
+	b = append(b, cookieMAC(key.hash, cookieName, b)...)
+
+	encoded := base64.RawURLEncoding.EncodeToString(b)
+	if len(encoded) > maxCookieValueLen {
+		return "", ErrCookieTooLarge
+	}
+
+	return encoded, nil
+}
+
+// Decode verifies and decrypts the "value" of the "cookieName" cookie
+// and binds the result to the "ptr", which should be a string or a byte slice pointer
+// or any JSON-decoded value pointer.
+// It returns `ErrInvalidCookie` or `ErrExpiredCookie` on failures.
+func (c *CookieCodec) Decode(cookieName string, value string, ptr interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil || len(b) < cookieTimestampLen+sha256.Size {
+		return ErrInvalidCookie
+	}
+
+	msg, mac := b[:len(b)-sha256.Size], b[len(b)-sha256.Size:]
+	for _, key := range c.keys {
+		if !hmac.Equal(mac, cookieMAC(key.hash, cookieName, msg)) {
+			continue
+		}
+
+		if c.MaxAge > 0 {
+			ts := time.Unix(int64(binary.BigEndian.Uint64(msg)), 0)
+			if c.now().Sub(ts) > c.MaxAge {
+				return ErrExpiredCookie
+			}
+		}
+
+		data := msg[cookieTimestampLen:]
+		if key.aead != nil {
+			nonceSize := key.aead.NonceSize()
+			if len(data) < nonceSize {
+				return ErrInvalidCookie
+			}
+
+			if data, err = key.aead.Open(nil, data[:nonceSize], data[nonceSize:], []byte(cookieName)); err != nil {
+				return ErrInvalidCookie
+			}
+		}
+
+		switch v := ptr.(type) {
+		case *string:
+			*v = string(data)
+		case *[]byte:
+			*v = append((*v)[:0], data...)
+		default:
+			return json.Unmarshal(data, ptr)
+		}
+
+		return nil
+	}
+
+	return ErrInvalidCookie
+}
+
+func (c *CookieCodec) now() time.Time {
+	if c.Clock == nil {
+		return SystemClock.Now()
+	}
+
+	return c.Clock.Now()
+}
+
+func cookieMAC(key []byte, cookieName string, msg []byte) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(cookieName))
+	h.Write([]byte{'|'})
+	h.Write(msg)
+	return h.Sum(nil)
+}
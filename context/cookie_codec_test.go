@@ -0,0 +1,111 @@
+package context_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/httptest"
+)
+
+type cookiePrefs struct {
+	Theme string `json:"theme"`
+	Admin bool   `json:"admin"`
+}
+
+func TestSecureCookie(t *testing.T) {
+	oldKey := context.CookieKey{Hash: []byte("old-hash-key-old-hash-key-old-ha"), Block: []byte("old-block-key-16")}
+	newKey := context.CookieKey{Hash: []byte("new-hash-key-new-hash-key-new-ha"), Block: []byte("new-block-key-new-block-key-32by")}
+
+	oldCodec, err := context.NewCookieCodec(oldKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	codec, err := context.NewCookieCodec(newKey, oldKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	app := iris.New().SetCookieCodec(codec)
+	app.Get("/set", func(ctx iris.Context) {
+		if err := ctx.SetSecureCookie("prefs", cookiePrefs{Theme: "dark"}, iris.CookiePartitioned); err != nil {
+			ctx.StopWithError(iris.StatusInternalServerError, err)
+		}
+	})
+	app.Get("/get", func(ctx iris.Context) {
+		var prefs cookiePrefs
+		if err := ctx.GetSecureCookie("prefs", &prefs); err != nil {
+			ctx.StopWithError(iris.StatusBadRequest, err)
+			return
+		}
+
+		ctx.JSON(prefs)
+	})
+
+	e := httptest.New(t, app)
+	setCookie := e.GET("/set").Expect().Status(httptest.StatusOK).Header("Set-Cookie").Raw()
+	if !strings.HasSuffix(setCookie, "; Secure; SameSite=Lax; Partitioned") {
+		t.Fatalf("expected a secure, partitioned cookie but got: %s", setCookie)
+	}
+	value := setCookie[len("prefs="):strings.IndexByte(setCookie, ';')]
+	if strings.Contains(value, "dark") {
+		t.Fatalf("expected an encrypted value but got: %s", value)
+	}
+
+	e.GET("/get").WithCookie("prefs", value).Expect().Status(httptest.StatusOK).
+		JSON().Equal(cookiePrefs{Theme: "dark"})
+
+	// Values encoded by an old key are still accepted.
+	oldValue, err := oldCodec.Encode("prefs", cookiePrefs{Theme: "light"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.GET("/get").WithCookie("prefs", oldValue).Expect().Status(httptest.StatusOK).
+		JSON().Equal(cookiePrefs{Theme: "light"})
+
+	tampered := []byte(value)
+	tampered[len(tampered)/2] ^= 1
+	e.GET("/get").WithCookie("prefs", string(tampered)).Expect().Status(httptest.StatusBadRequest).
+		Body().Equal(context.ErrInvalidCookie.Error())
+	e.GET("/get").Expect().Status(httptest.StatusBadRequest)
+
+	// Values of another cookie are rejected.
+	other, _ := codec.Encode("session", cookiePrefs{Admin: true})
+	e.GET("/get").WithCookie("prefs", other).Expect().Status(httptest.StatusBadRequest)
+}
+
+func TestCookieCodec(t *testing.T) {
+	codec, err := context.NewCookieCodec(context.CookieKey{Hash: []byte("hash-key")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clock := context.NewMockClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	codec.Clock = clock
+	codec.MaxAge = time.Hour
+
+	encoded, err := codec.Encode("name", "kataras")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got string
+	if err = codec.Decode("name", encoded, &got); err != nil || got != "kataras" {
+		t.Fatalf("expected kataras but got: %q: %v", got, err)
+	}
+
+	clock.Advance(2 * time.Hour)
+	if err = codec.Decode("name", encoded, &got); err != context.ErrExpiredCookie {
+		t.Fatalf("expected expired cookie error but got: %v", err)
+	}
+
+	if _, err = codec.Encode("name", strings.Repeat("a", 4096)); err != context.ErrCookieTooLarge {
+		t.Fatalf("expected too large error but got: %v", err)
+	}
+
+	if _, err = context.NewCookieCodec(context.CookieKey{Hash: []byte("hash-key"), Block: []byte("short")}); err == nil {
+		t.Fatalf("expected an invalid block key size error")
+	}
+}
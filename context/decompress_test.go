@@ -0,0 +1,76 @@
+package context_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/httptest"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+func compressTestBody(t *testing.T, encoding string, body string) []byte {
+	t.Helper()
+
+	buf := new(bytes.Buffer)
+	w, err := context.NewCompressWriter(buf, encoding, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write([]byte(body))
+	w.Close()
+
+	return buf.Bytes()
+}
+
+func TestDecompressRequest(t *testing.T) {
+	type payload struct {
+		Name string `json:"name"`
+	}
+
+	app := iris.New()
+	app.Use(iris.DecompressRequest(64))
+	app.Post("/", func(ctx iris.Context) {
+		var p payload
+		if err := ctx.ReadJSON(&p); err != nil {
+			ctx.StopWithReadError(err)
+			return
+		}
+
+		ctx.WriteString(p.Name)
+	})
+
+	e := httptest.New(t, app)
+	for _, encoding := range []string{context.GZIP, context.DEFLATE, context.BROTLI} {
+		e.POST("/").WithHeader("Content-Type", "application/json").WithHeader("Content-Encoding", encoding).
+			WithBytes(compressTestBody(t, encoding, `{"name":"kataras"}`)).Expect().
+			Status(httptest.StatusOK).Body().Equal("kataras")
+	}
+
+	enc, _ := zstd.NewWriter(nil)
+	zstdBody := enc.EncodeAll([]byte(`{"name":"kataras"}`), nil)
+	e.POST("/").WithHeader("Content-Type", "application/json").WithHeader("Content-Encoding", context.ZSTD).
+		WithBytes(zstdBody).Expect().Status(httptest.StatusOK).Body().Equal("kataras")
+
+	// not compressed.
+	e.POST("/").WithJSON(payload{Name: "makis"}).Expect().Status(httptest.StatusOK).Body().Equal("makis")
+
+	// decompression bomb.
+	bomb := `{"name":"` + strings.Repeat("a", 1024) + `"}`
+	e.POST("/").WithHeader("Content-Type", "application/json").WithHeader("Content-Encoding", context.GZIP).
+		WithBytes(compressTestBody(t, context.GZIP, bomb)).Expect().Status(httptest.StatusRequestEntityTooLarge)
+
+	e.POST("/").WithHeader("Content-Encoding", "lzma").WithBytes([]byte("data")).Expect().
+		Status(httptest.StatusUnsupportedMediaType)
+
+	// malformed streams.
+	e.POST("/").WithHeader("Content-Type", "application/json").WithHeader("Content-Encoding", context.GZIP).
+		WithBytes([]byte("not gzip data")).Expect().Status(httptest.StatusBadRequest)
+	// a zstd frame header which requires a 512MB window.
+	zstdHugeWindow := []byte{0x28, 0xb5, 0x2f, 0xfd, 0x00, 19 << 3, 0x01, 0x00, 0x00}
+	e.POST("/").WithHeader("Content-Type", "application/json").WithHeader("Content-Encoding", context.ZSTD).
+		WithBytes(zstdHugeWindow).Expect().Status(httptest.StatusBadRequest).Body().Contains("window size exceeded")
+}
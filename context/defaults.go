@@ -0,0 +1,164 @@
+package context
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultTag is the struct field tag which holds the default value
+// of a field when the request does not provide one,
+// see `ReadQuery`, `ReadHeaders`, `ReadParams` and `ReadURL` methods.
+//
+// Slices accept comma separated values, time.Duration fields
+// accept values like "5s" and time.Time fields accept RFC3339 formatted values.
+//
+// Example Code:
+//  type Filter struct {
+//      Page  int      `url:"page" default:"1"`
+//      Limit int      `url:"limit" default:"25"`
+//      Sort  []string `url:"sort" default:"-created_at,name"`
+//  }
+var DefaultTag = "default"
+
+var (
+	durationType      = reflect.TypeOf(time.Duration(0))
+	timeType          = reflect.TypeOf(time.Time{})
+	textUnmarshalType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// setDefaults fills the zero-value fields of the "ptr" struct
+// with the values of their "default" tags, nested structs are visited too.
+// It does nothing if "ptr" is not a pointer to a struct.
+func setDefaults(ptr interface{}) error {
+	v := reflect.ValueOf(ptr)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return nil
+	}
+
+	v = v.Elem()
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	return setStructDefaults(v)
+}
+
+func setStructDefaults(v reflect.Value) error {
+	typ := v.Type()
+	for i, n := 0, v.NumField(); i < n; i++ {
+		field := typ.Field(i)
+		if field.PkgPath != "" && !field.Anonymous { // unexported.
+			continue
+		}
+
+		f := v.Field(i)
+		if !f.CanSet() {
+			continue
+		}
+
+		if def, ok := field.Tag.Lookup(DefaultTag); ok {
+			if !f.IsZero() {
+				continue
+			}
+
+			if err := setDefault(f, def); err != nil {
+				return fmt.Errorf("%s: field %s: %w", DefaultTag, field.Name, err)
+			}
+
+			continue
+		}
+
+		if f.Kind() == reflect.Struct && f.Type() != timeType {
+			if err := setStructDefaults(f); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func setDefault(f reflect.Value, value string) error {
+	if f.Kind() == reflect.Ptr {
+		elem := reflect.New(f.Type().Elem())
+		if err := setDefault(elem.Elem(), value); err != nil {
+			return err
+		}
+
+		f.Set(elem)
+		return nil
+	}
+
+	if f.CanAddr() && f.Addr().Type().Implements(textUnmarshalType) {
+		return f.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value))
+	}
+
+	switch f.Type() {
+	case durationType:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+
+		f.SetInt(int64(d))
+		return nil
+	case timeType:
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return err
+		}
+
+		f.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	switch f.Kind() {
+	case reflect.String:
+		f.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		f.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, f.Type().Bits())
+		if err != nil {
+			return err
+		}
+		f.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, f.Type().Bits())
+		if err != nil {
+			return err
+		}
+		f.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(value, f.Type().Bits())
+		if err != nil {
+			return err
+		}
+		f.SetFloat(n)
+	case reflect.Slice:
+		if value == "" {
+			return nil
+		}
+
+		parts := strings.Split(value, ",")
+		s := reflect.MakeSlice(f.Type(), len(parts), len(parts))
+		for i, part := range parts {
+			if err := setDefault(s.Index(i), strings.TrimSpace(part)); err != nil {
+				return err
+			}
+		}
+		f.Set(s)
+	default:
+		return fmt.Errorf("unsupported type: %s", f.Type())
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package context_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/httptest"
+)
+
+type searchFilter struct {
+	Term    string        `url:"q"`
+	Page    int           `url:"page" default:"1"`
+	Limit   uint8         `url:"limit" default:"25"`
+	Sort    []string      `url:"sort" default:"-created_at,name"`
+	Exact   bool          `url:"exact" default:"true"`
+	Timeout time.Duration `url:"timeout" default:"5s"`
+	Lang    *string       `url:"lang" default:"en"`
+	Client  searchClient
+}
+
+type searchClient struct {
+	Version string `header:"X-Client-Version" default:"v1"`
+}
+
+type searchParams struct {
+	Category string `param:"category"`
+	Page     int    `param:"page" default:"1"`
+}
+
+func TestReadDefaults(t *testing.T) {
+	app := iris.New()
+	app.Get("/search", func(ctx iris.Context) {
+		var f searchFilter
+		if err := ctx.ReadQuery(&f); err != nil {
+			ctx.StopWithError(iris.StatusBadRequest, err)
+			return
+		}
+
+		ctx.Writef("%s|%d|%d|%v|%v|%s|%s|%s", f.Term, f.Page, f.Limit, f.Sort, f.Exact, f.Timeout, *f.Lang, f.Client.Version)
+	})
+	app.Get("/headers", func(ctx iris.Context) {
+		var c searchClient
+		if err := ctx.ReadHeaders(&c); err != nil {
+			ctx.StopWithError(iris.StatusBadRequest, err)
+			return
+		}
+
+		ctx.WriteString(c.Version)
+	})
+	app.Get("/categories/{category}", func(ctx iris.Context) {
+		var p searchParams
+		if err := ctx.ReadParams(&p); err != nil {
+			ctx.StopWithError(iris.StatusBadRequest, err)
+			return
+		}
+
+		ctx.Writef("%s:%d", p.Category, p.Page)
+	})
+
+	e := httptest.New(t, app)
+	e.GET("/search").Expect().Status(httptest.StatusOK).
+		Body().Equal("|1|25|[-created_at name]|true|5s|en|v1")
+	e.GET("/search").WithQuery("q", "iris").WithQuery("page", 3).WithQuery("sort", "name").
+		WithQuery("exact", false).WithQuery("lang", "el").Expect().Status(httptest.StatusOK).
+		Body().Equal("iris|3|25|[name]|false|5s|el|v1")
+	e.GET("/search").WithQuery("page", "invalid").Expect().Status(httptest.StatusBadRequest)
+
+	e.GET("/headers").Expect().Status(httptest.StatusOK).Body().Equal("v1")
+	e.GET("/headers").WithHeader("X-Client-Version", "v2").Expect().Status(httptest.StatusOK).Body().Equal("v2")
+
+	e.GET("/categories/books").Expect().Status(httptest.StatusOK).Body().Equal("books:1")
+}
@@ -0,0 +1,42 @@
+package context_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/httptest"
+)
+
+func TestContextDefer(t *testing.T) {
+	fired := make(chan string, 3)
+
+	app := iris.New()
+	app.Logger().SetLevel("disable")
+	app.Get("/", func(ctx iris.Context) {
+		path := ctx.Path()
+		ctx.Defer(func() { fired <- "first:" + path })
+		ctx.Defer(func() { panic("isolated") })
+		ctx.Defer(func() { fired <- "second:" + path })
+
+		ctx.WriteString("response")
+	})
+
+	e := httptest.New(t, app)
+	e.GET("/").Expect().Status(httptest.StatusOK).Body().Equal("response")
+
+	var got []string
+	for len(got) < 2 {
+		select {
+		case s := <-fired:
+			got = append(got, s)
+		case <-time.After(3 * time.Second):
+			t.Fatalf("expected deferred functions to be fired but got: %v", got)
+		}
+	}
+
+	if expected := "second:/,first:/"; strings.Join(got, ",") != expected {
+		t.Fatalf("expected LIFO order: %s but got: %v", expected, got)
+	}
+}
@@ -0,0 +1,133 @@
+package context
+
+import (
+	"errors"
+	"net/http"
+	"path"
+	"strings"
+)
+
+const (
+	// TrailerHeaderKey is the header key of "Trailer".
+	TrailerHeaderKey = "Trailer"
+	// LinkHeaderKey is the header key of "Link".
+	LinkHeaderKey = "Link"
+	// StatusEarlyHints is the 103 Early Hints status code (RFC 8297).
+	StatusEarlyHints = 103
+)
+
+// ErrEarlyHintsNotSupported is returned by the `Context.EarlyHints` method
+// when the program is compiled with a Go version which cannot send informational responses (before Go 1.19).
+var ErrEarlyHintsNotSupported = errors.New("early hints: not supported, requires go1.19 or newer")
+
+// ErrResponseHeadersWritten is returned by methods which should run before
+// the response headers are sent to the client, e.g. `Context.EarlyHints` and `Context.Rollback`.
+var ErrResponseHeadersWritten = errors.New("response headers already written")
+
+// SetTrailer sets a response trailer, a header which is sent after the response body,
+// e.g. a checksum or the status of a streamed response.
+// If the response headers are not written yet, the trailer is announced
+// through the "Trailer" header too, as clients and proxies may require it.
+// It can be called at any time before the handler returns,
+// including after the response body is written.
+// Note that trailers are only sent on chunked (HTTP/1.1)
+// and HTTP/2 responses, e.g. not when the Content-Length is known.
+//
+// Usage:
+//  ctx.SetTrailer("Server-Timing", "db;dur=53")
+func (ctx *Context) SetTrailer(key, value string) {
+	key = http.CanonicalHeaderKey(key)
+	h := ctx.writer.Header()
+
+	if ctx.writer.Written() == NoWritten && !isTrailerAnnounced(h, key) {
+		h.Add(TrailerHeaderKey, key)
+	}
+
+	// The prefixed keys are sent as trailers by net/http
+	// even if they were not announced.
+	h.Set(http.TrailerPrefix+key, value)
+}
+
+func isTrailerAnnounced(h http.Header, key string) bool {
+	for _, v := range h.Values(TrailerHeaderKey) {
+		for _, k := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(k), key) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// EarlyHints sends a 103 Early Hints informational response (RFC 8297)
+// with the given "links" as "Link" headers, before the final response,
+// so the browser can start preloading the page's assets
+// while the server is still processing the request (e.g. querying the database).
+// The links are kept on the final response's headers too.
+//
+// A link can be a complete "Link" header value,
+// e.g. `</app.css>; rel=preload; as=style`, or just a URL path,
+// e.g. "/app.css", its preload destination is resolved by its extension.
+//
+// It returns `ErrResponseHeadersWritten` if the response headers were already sent.
+// HTTP/1.0 clients are skipped, as they do not support informational responses.
+//
+// Usage:
+//  ctx.EarlyHints("/app.css", "/app.js", "</fonts/main.woff2>; rel=preload; as=font; crossorigin")
+func (ctx *Context) EarlyHints(links ...string) error {
+	if !earlyHintsSupported {
+		return ErrEarlyHintsNotSupported
+	}
+
+	if ctx.writer.Written() != NoWritten {
+		return ErrResponseHeadersWritten
+	}
+
+	h := ctx.writer.Header()
+	for _, link := range links {
+		h.Add(LinkHeaderKey, PreloadLink(link))
+	}
+
+	if !ctx.request.ProtoAtLeast(1, 1) {
+		return nil
+	}
+
+	// Send it through the underline net/http response writer,
+	// Iris writers would treat it as the final status code.
+	ctx.writer.Naive().WriteHeader(StatusEarlyHints)
+	return nil
+}
+
+// PreloadLink returns a "Link" header value which preloads the "target".
+// If the "target" is already a link value (starts with "<") then it's returned as it is,
+// otherwise its preload destination ("as" parameter) is resolved by its extension.
+//
+// Example: PreloadLink("/app.css") returns `</app.css>; rel=preload; as=style`.
+func PreloadLink(target string) string {
+	if strings.HasPrefix(target, "<") {
+		return target
+	}
+
+	link := "<" + target + ">; rel=preload"
+
+	ext := path.Ext(target)
+	if idx := strings.IndexAny(ext, "?#"); idx != -1 {
+		ext = ext[:idx]
+	}
+
+	switch strings.ToLower(ext) {
+	case ".css":
+		link += "; as=style"
+	case ".js", ".mjs":
+		link += "; as=script"
+	case ".woff2", ".woff", ".ttf", ".otf":
+		link += "; as=font; crossorigin"
+	case ".png", ".jpg", ".jpeg", ".gif", ".svg", ".webp", ".avif", ".ico":
+		link += "; as=image"
+	case ".json":
+		link += "; as=fetch; crossorigin"
+	}
+
+	return link
+}
@@ -0,0 +1,7 @@
+//go:build go1.19
+// +build go1.19
+
+package context
+
+// net/http supports informational (1xx) responses since go1.19.
+const earlyHintsSupported = true
@@ -0,0 +1,6 @@
+//go:build !go1.19
+// +build !go1.19
+
+package context
+
+const earlyHintsSupported = false
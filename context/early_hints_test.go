@@ -0,0 +1,78 @@
+package context_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	stdhttptest "net/http/httptest"
+	"net/http/httptrace"
+	"net/textproto"
+	"testing"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/context"
+)
+
+func TestContextTrailersAndEarlyHints(t *testing.T) {
+	app := iris.New()
+	app.Get("/", func(ctx iris.Context) {
+		if err := ctx.EarlyHints("/app.css", "</app.js>; rel=preload; as=script"); err != nil {
+			ctx.StopWithError(iris.StatusInternalServerError, err)
+			return
+		}
+
+		ctx.SetTrailer("X-Checksum", "pending")
+		ctx.WriteString("body")
+		ctx.ResponseWriter().Flush()
+		ctx.SetTrailer("X-Checksum", "abc")
+		ctx.SetTrailer("X-Status", "ok") // not announced.
+	})
+	if err := app.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := stdhttptest.NewServer(app)
+	defer srv.Close()
+
+	var hints []textproto.MIMEHeader
+	trace := &httptrace.ClientTrace{
+		Got1xxResponse: func(code int, header textproto.MIMEHeader) error {
+			if code == context.StatusEarlyHints {
+				hints = append(hints, header)
+			}
+			return nil
+		},
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	// The client moves the announced trailers to the Trailer field.
+	if _, announced := resp.Trailer["X-Checksum"]; !announced {
+		t.Fatalf("expected the X-Checksum trailer to be announced")
+	}
+
+	body, _ := ioutil.ReadAll(resp.Body)
+	if expected, got := "body", string(body); expected != got {
+		t.Fatalf("expected body: %s but got: %s", expected, got)
+	}
+
+	if expected, got := 1, len(hints); expected != got {
+		t.Fatalf("expected %d early hints responses but got %d", expected, got)
+	}
+	links := hints[0]["Link"]
+	if expected := []string{"</app.css>; rel=preload; as=style", "</app.js>; rel=preload; as=script"}; len(links) != 2 || links[0] != expected[0] || links[1] != expected[1] {
+		t.Fatalf("expected links: %v but got: %v", expected, links)
+	}
+
+	if expected, got := "abc", resp.Trailer.Get("X-Checksum"); expected != got {
+		t.Fatalf("expected trailer value: %s but got: %s", expected, got)
+	}
+	if expected, got := "ok", resp.Trailer.Get("X-Status"); expected != got {
+		t.Fatalf("expected trailer value: %s but got: %s", expected, got)
+	}
+}
@@ -0,0 +1,322 @@
+package context
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// ErrorCode describes a stable, application-level, error code,
+// so the clients can program against the code instead of the status or the message.
+// Register the codes to an `ErrorCatalog` (see `RegisterErrorCode`)
+// and send them through the `Context.StopWithErrorCode` method.
+//
+// An ErrorCode is an error too, use its `Wrap` method to attach the cause.
+type ErrorCode struct {
+	// Code is the unique code, e.g. "USER_NOT_FOUND". Required.
+	Code string `json:"code" yaml:"Code" toml:"Code"`
+	// Status is the HTTP status code of the error response, e.g. 404. Required.
+	Status int `json:"status" yaml:"Status" toml:"Status"`
+	// MessageKey is the i18n key of the message, the message is localized
+	// based on the request's locale, see `Context.Tr`.
+	MessageKey string `json:"messageKey,omitempty" yaml:"MessageKey" toml:"MessageKey"`
+	// Message is the default message, when the MessageKey is empty or not translated.
+	// Defaults to the status code's text.
+	Message string `json:"message,omitempty" yaml:"Message" toml:"Message"`
+	// DocsURL is the documentation URL of the error code,
+	// sent as the problem's "type" member.
+	DocsURL string `json:"docsURL,omitempty" yaml:"DocsURL" toml:"DocsURL"`
+	// ExitCode is the process exit code of the error, for commands and jobs
+	// which share the codes with the HTTP API, see `ErrorCatalog.ExitCode`.
+	ExitCode int `json:"exitCode,omitempty" yaml:"ExitCode" toml:"ExitCode"`
+}
+
+// Error completes the error interface, it returns the code and its default message.
+func (c ErrorCode) Error() string {
+	return c.Code + ": " + c.message()
+}
+
+func (c ErrorCode) message() string {
+	if c.Message != "" {
+		return c.Message
+	}
+
+	return http.StatusText(c.Status)
+}
+
+// Is reports whether the "target" error is an error of the same code.
+func (c ErrorCode) Is(target error) bool {
+	code, ok := AsErrorCode(target)
+	return ok && code.Code == c.Code
+}
+
+// Wrap returns a new error of this code, with the "err" as its cause.
+func (c ErrorCode) Wrap(err error) error {
+	if err == nil {
+		return c
+	}
+
+	return &CodeError{ErrorCode: c, Err: err}
+}
+
+// LocalizedMessage returns the localized message of the code, see `MessageKey`.
+func (c ErrorCode) LocalizedMessage(ctx *Context) string {
+	if c.MessageKey != "" && ctx != nil && ctx.app != nil {
+		if i18n, ok := ctx.app.I18nReadOnly().(interface{ Loaded() bool }); ok && i18n.Loaded() {
+			if msg := ctx.Tr(c.MessageKey); msg != "" {
+				return msg
+			}
+		}
+	}
+
+	return c.message()
+}
+
+// Problem returns the problem document of the code, the "code" extension member
+// is the `Code` field and the "type" member is the `DocsURL` one.
+func (c ErrorCode) Problem(ctx *Context) Problem {
+	typ := c.DocsURL
+	if typ == "" {
+		typ = "about:blank"
+	}
+
+	return NewProblem().
+		Type(typ).
+		Title(c.LocalizedMessage(ctx)).
+		Status(c.Status).
+		Key("code", c.Code)
+}
+
+// CodeError is an error of an `ErrorCode`, see `ErrorCode.Wrap`.
+type CodeError struct {
+	ErrorCode
+	// Err is the cause of the error.
+	Err error
+}
+
+// Error completes the error interface.
+func (e *CodeError) Error() string {
+	return e.ErrorCode.Error() + ": " + e.Err.Error()
+}
+
+// Unwrap returns the cause of the error.
+func (e *CodeError) Unwrap() error {
+	return e.Err
+}
+
+// AsErrorCode returns the `ErrorCode` of the "err" error,
+// an `ErrorCode` value or a `CodeError` (see `ErrorCode.Wrap`) in its chain.
+func AsErrorCode(err error) (ErrorCode, bool) {
+	var codeErr *CodeError
+	if errors.As(err, &codeErr) {
+		return codeErr.ErrorCode, true
+	}
+
+	var code ErrorCode
+	if errors.As(err, &code) {
+		return code, true
+	}
+
+	return ErrorCode{}, false
+}
+
+// ErrorCatalog is the registry of the application's error codes.
+// It is safe for concurrent use.
+//
+// See the `DefaultErrorCatalog` and the `RegisterErrorCode` package-level function.
+type ErrorCatalog struct {
+	mu    sync.RWMutex
+	codes map[string]ErrorCode
+	order []string
+}
+
+// NewErrorCatalog returns a new empty error code catalog.
+func NewErrorCatalog() *ErrorCatalog {
+	return &ErrorCatalog{codes: make(map[string]ErrorCode)}
+}
+
+// DefaultErrorCatalog is the application-wide error code catalog,
+// see `RegisterErrorCode`.
+var DefaultErrorCatalog = NewErrorCatalog()
+
+// RegisterErrorCode registers the "code" to the `DefaultErrorCatalog`.
+// See `ErrorCatalog.Register` for more.
+func RegisterErrorCode(code ErrorCode) ErrorCode {
+	return DefaultErrorCatalog.Register(code)
+}
+
+// Register adds the "code" to the catalog and returns it,
+// so the codes can be declared as package-level variables.
+// It panics on an empty or an already registered code
+// and on a status code which is not an error one.
+//
+// Usage:
+//  var ErrUserNotFound = iris.RegisterErrorCode(iris.ErrorCode{
+//      Code:       "USER_NOT_FOUND",
+//      Status:     iris.StatusNotFound,
+//      MessageKey: "errors.user_not_found",
+//      DocsURL:    "https://example.com/docs/errors#USER_NOT_FOUND",
+//  })
+func (c *ErrorCatalog) Register(code ErrorCode) ErrorCode {
+	if code.Code == "" {
+		panic("iris: error code: empty code")
+	}
+
+	if !StatusCodeNotSuccessful(code.Status) {
+		panic(fmt.Sprintf("iris: error code: %s: invalid status code %d", code.Code, code.Status))
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, exists := c.codes[code.Code]; exists {
+		panic(fmt.Sprintf("iris: error code: %s: already registered", code.Code))
+	}
+
+	c.codes[code.Code] = code
+	c.order = append(c.order, code.Code)
+	return code
+}
+
+// Get returns the registered error code of the given "code".
+func (c *ErrorCatalog) Get(code string) (ErrorCode, bool) {
+	c.mu.RLock()
+	errorCode, ok := c.codes[code]
+	c.mu.RUnlock()
+	return errorCode, ok
+}
+
+// Codes returns the registered error codes, by registration order.
+func (c *ErrorCatalog) Codes() []ErrorCode {
+	c.mu.RLock()
+	codes := make([]ErrorCode, 0, len(c.order))
+	for _, code := range c.order {
+		codes = append(codes, c.codes[code])
+	}
+	c.mu.RUnlock()
+
+	return codes
+}
+
+// ExitCode returns the process exit code of the "err" error:
+// 0 for a nil error, the `ErrorCode.ExitCode` of a registered code,
+// if it's not zero, otherwise 1.
+//
+// Usage:
+//  if err := job.Run(); err != nil {
+//      log.Println(err)
+//      os.Exit(iris.DefaultErrorCatalog.ExitCode(err))
+//  }
+func (c *ErrorCatalog) ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	if code, ok := AsErrorCode(err); ok {
+		if registered, ok := c.Get(code.Code); ok && registered.ExitCode != 0 {
+			return registered.ExitCode
+		}
+	}
+
+	return 1
+}
+
+// Handler sends the catalog as JSON, so the client teams
+// can generate their error handling code from it.
+//
+// Usage:
+//  app.Get("/errors", iris.DefaultErrorCatalog.Handler)
+func (c *ErrorCatalog) Handler(ctx *Context) {
+	ctx.JSON(Map{"codes": c.Codes()}) // nolint:errcheck
+}
+
+// OpenAPIResponses returns the OpenAPI (v3) "responses" object of the given error "codes",
+// a problem document response per status code, its "code" member lists the codes
+// of that status and each code is described by an example.
+func OpenAPIResponses(codes []ErrorCode) map[string]interface{} {
+	byStatus := make(map[int][]ErrorCode)
+	for _, code := range codes {
+		byStatus[code.Status] = append(byStatus[code.Status], code)
+	}
+
+	responses := make(map[string]interface{}, len(byStatus))
+	for status, codes := range byStatus {
+		sort.Slice(codes, func(i, j int) bool {
+			return codes[i].Code < codes[j].Code
+		})
+
+		enum := make([]string, 0, len(codes))
+		examples := make(map[string]interface{}, len(codes))
+		for _, code := range codes {
+			enum = append(enum, code.Code)
+
+			example := map[string]interface{}{
+				"summary": code.message(),
+				"value":   code.Problem(nil),
+			}
+			if code.DocsURL != "" {
+				example["externalValue"] = code.DocsURL
+			}
+			examples[code.Code] = example
+		}
+
+		responses[strconv.Itoa(status)] = map[string]interface{}{
+			"description": http.StatusText(status),
+			"content": map[string]interface{}{
+				ContentJSONProblemHeaderValue: map[string]interface{}{
+					"schema": map[string]interface{}{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"type":   map[string]interface{}{"type": "string", "format": "uri"},
+							"title":  map[string]interface{}{"type": "string"},
+							"status": map[string]interface{}{"type": "integer"},
+							"detail": map[string]interface{}{"type": "string"},
+							"code":   map[string]interface{}{"type": "string", "enum": enum},
+						},
+					},
+					"examples": examples,
+				},
+			},
+		}
+	}
+
+	return responses
+}
+
+// StopWithErrorCode stops the handlers chain and sends the error code of the "err"
+// (see `ErrorCode` and `AsErrorCode`) as a problem document.
+// The cause of the error (see `ErrorCode.Wrap`) is sent as the "detail" member,
+// unless it's a private one, see `PrivateError`.
+// Errors without a code are sent with a 500 Internal Server Error status code.
+//
+// Usage:
+//  user, err := repo.Get(id)
+//  if err != nil {
+//      ctx.StopWithErrorCode(ErrUserNotFound.Wrap(err))
+//      return
+//  }
+func (ctx *Context) StopWithErrorCode(err error) {
+	if err == nil {
+		return
+	}
+
+	ctx.SetErr(err)
+
+	code, ok := AsErrorCode(err)
+	if !ok {
+		ctx.StopWithStatus(http.StatusInternalServerError)
+		return
+	}
+
+	p := code.Problem(ctx)
+	if codeErr, ok := err.(*CodeError); ok && codeErr.Err != nil {
+		if _, private := codeErr.Err.(ErrPrivate); !private {
+			p.DetailErr(codeErr.Err)
+		}
+	}
+
+	ctx.StopWithProblem(code.Status, p)
+}
@@ -0,0 +1,145 @@
+package context
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+)
+
+// BotClass describes the classification of a client
+// based on its request `Fingerprint`.
+type BotClass uint8
+
+const (
+	// BotUnknown is the zero value, the client was not classified yet.
+	BotUnknown BotClass = iota
+	// BotHuman indicates that the client looks like a real browser.
+	BotHuman
+	// BotGood indicates a well-known, verified or allowed crawler (e.g. search engines).
+	BotGood
+	// BotBad indicates an automated client (scripts, headless browsers, scrapers).
+	BotBad
+)
+
+// String returns the text representation of the bot class.
+func (c BotClass) String() string {
+	switch c {
+	case BotHuman:
+		return "human"
+	case BotGood:
+		return "good-bot"
+	case BotBad:
+		return "bot"
+	default:
+		return "unknown"
+	}
+}
+
+type (
+	// Fingerprint holds the identification information of a client request.
+	// It can be retrieved through the `Context.Fingerprint` method
+	// and it's filled by one or more `Fingerprinter` implementations,
+	// see the "middleware/botdetect" package.
+	Fingerprint struct {
+		// ID is a hash of the User-Agent, the Accept headers,
+		// the order-independent request header names and the TLS fingerprint (if any).
+		// Requests sent by the same client software share the same ID.
+		ID string `json:"id"`
+		// TLS is the TLS ClientHello fingerprint (JA3-like hash), when available.
+		TLS string `json:"tls,omitempty"`
+		// IP is the client's IP address, see `Context.RemoteAddr`.
+		IP string `json:"ip"`
+		// Score is the bot score, from 0 (human) to 100 (definitely a bot).
+		Score int `json:"score"`
+		// Class is the classification of the client.
+		Class BotClass `json:"class"`
+		// Signals holds the reasons that affected the Score and Class,
+		// e.g. "missing-user-agent", "headless", "ip-reputation".
+		Signals []string `json:"signals,omitempty"`
+	}
+
+	// Fingerprinter is the interface which fingerprint providers should implement
+	// in order to fill or modify the request's `Fingerprint`,
+	// e.g. header heuristics, TLS ClientHello capture and IP reputation services.
+	Fingerprinter interface {
+		Fingerprint(ctx *Context, fp *Fingerprint)
+	}
+
+	// FingerprinterFunc is the function type of a `Fingerprinter`.
+	FingerprinterFunc func(ctx *Context, fp *Fingerprint)
+)
+
+// Fingerprint completes the `Fingerprinter` interface.
+func (fn FingerprinterFunc) Fingerprint(ctx *Context, fp *Fingerprint) {
+	fn(ctx, fp)
+}
+
+// AddScore adds the "score" to the fingerprint's Score, limited to 0-100,
+// and appends the "signal" to its Signals.
+func (fp *Fingerprint) AddScore(score int, signal string) {
+	fp.Score += score
+	if fp.Score > 100 {
+		fp.Score = 100
+	} else if fp.Score < 0 {
+		fp.Score = 0
+	}
+
+	if signal != "" {
+		fp.Signals = append(fp.Signals, signal)
+	}
+}
+
+// HasSignal reports whether the fingerprint contains the given signal.
+func (fp *Fingerprint) HasSignal(signal string) bool {
+	for _, s := range fp.Signals {
+		if s == signal {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SetTLS sets the TLS ClientHello fingerprint and updates the ID to include it.
+func (fp *Fingerprint) SetTLS(tlsHash string) {
+	fp.TLS = tlsHash
+	fp.ID = fingerprintHash(fp.ID, tlsHash)
+}
+
+// fingerprintHeaders are the request headers that take part on the fingerprint ID
+// with their values, all other headers take part only with their names.
+var fingerprintHeaders = []string{"User-Agent", "Accept", "Accept-Language", "Accept-Encoding"}
+
+func newFingerprint(ctx *Context) *Fingerprint {
+	parts := make([]string, 0, len(fingerprintHeaders)+1)
+	for _, key := range fingerprintHeaders {
+		parts = append(parts, ctx.request.Header.Get(key))
+	}
+
+	names := make([]string, 0, len(ctx.request.Header))
+	for name := range ctx.request.Header {
+		switch name = strings.ToLower(name); name {
+		case "cookie", "authorization": // per-session headers.
+		default:
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	parts = append(parts, strings.Join(names, ","))
+
+	return &Fingerprint{
+		ID: fingerprintHash(parts...),
+		IP: ctx.RemoteAddr(),
+	}
+}
+
+func fingerprintHash(parts ...string) string {
+	h := sha256.New()
+	for _, part := range parts {
+		h.Write([]byte(part))
+		h.Write([]byte{'\n'})
+	}
+
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
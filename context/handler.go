@@ -51,6 +51,50 @@ func SetHandlerName(original string, replacement string) {
 	handlerNamesMu.Unlock()
 }
 
+// Builtin handler policies, see `SetHandlerPolicy`.
+const (
+	PolicyCompression = "compression"
+	PolicyCache       = "cache"
+	PolicyAuth        = "auth"
+	PolicyRateLimit   = "ratelimit"
+	PolicyBodyLimit   = "bodylimit"
+)
+
+var (
+	handlerPolicies   = make(map[string]string) // key = handler name, value = policy.
+	handlerPoliciesMu sync.RWMutex
+)
+
+func init() {
+	SetHandlerPolicy(HandlerName(LimitRequestBodySize(0)), PolicyBodyLimit)
+}
+
+// SetHandlerPolicy marks the handlers of the given "handlerName" (see `HandlerName`)
+// as enforcers of the "policy", e.g. `PolicyAuth`.
+// It's used to compute the effective policies of each route,
+// see the `router.APIBuilder.PolicyReport` method.
+//
+// Builtin middlewares (e.g. basicauth, jwt, rate, cache) are registered automatically.
+// Custom middlewares can be registered through:
+//  context.SetHandlerPolicy(context.HandlerName(myAuth), context.PolicyAuth)
+func SetHandlerPolicy(handlerName string, policy string) {
+	handlerPoliciesMu.Lock()
+	handlerPolicies[handlerName] = policy
+	handlerPoliciesMu.Unlock()
+}
+
+// HandlerPolicy returns the policy of a handler, if any.
+// See `SetHandlerPolicy` too.
+func HandlerPolicy(h interface{}) (string, bool) {
+	name := HandlerName(h)
+
+	handlerPoliciesMu.RLock()
+	policy, ok := handlerPolicies[name]
+	handlerPoliciesMu.RUnlock()
+
+	return policy, ok
+}
+
 // NameExpr regex or literal comparison through `MatchString`.
 type NameExpr struct {
 	regex   *regexp.Regexp
@@ -0,0 +1,160 @@
+package context
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// HijackOptions holds the optional settings of the `Context.Hijack` method.
+type HijackOptions struct {
+	// ReadTimeout, if positive, sets the connection's read deadline before each Read.
+	ReadTimeout time.Duration
+	// WriteTimeout, if positive, sets the connection's write deadline before each Write.
+	WriteTimeout time.Duration
+	// OnShutdown, if not nil, is called when the server shuts down,
+	// right before the connection is closed, e.g. to send a "goodbye" message
+	// of the custom protocol.
+	OnShutdown func(conn *HijackedConn)
+}
+
+// HijackedConn is a managed net.Conn returned by the `Context.Hijack` method.
+// Reads include any data buffered by the server before the hijack,
+// the deadlines are controlled by its `HijackOptions`
+// and it's closed automatically on server's shutdown.
+type HijackedConn struct {
+	net.Conn
+
+	reader  *bufio.Reader
+	opts    HijackOptions
+	tracker *hijackTracker
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+var _ net.Conn = (*HijackedConn)(nil)
+
+// Read reads data from the connection, including any buffered data.
+func (c *HijackedConn) Read(p []byte) (int, error) {
+	if c.opts.ReadTimeout > 0 {
+		if err := c.Conn.SetReadDeadline(time.Now().Add(c.opts.ReadTimeout)); err != nil {
+			return 0, err
+		}
+	}
+
+	if c.reader != nil && c.reader.Buffered() > 0 {
+		return c.reader.Read(p)
+	}
+
+	return c.Conn.Read(p)
+}
+
+// Write writes data to the connection.
+func (c *HijackedConn) Write(p []byte) (int, error) {
+	if c.opts.WriteTimeout > 0 {
+		if err := c.Conn.SetWriteDeadline(time.Now().Add(c.opts.WriteTimeout)); err != nil {
+			return 0, err
+		}
+	}
+
+	return c.Conn.Write(p)
+}
+
+// Close closes the connection and removes it from the shutdown manager.
+// It's safe to be called more than once.
+func (c *HijackedConn) Close() error {
+	c.closeOnce.Do(func() {
+		if c.tracker != nil {
+			c.tracker.remove(c)
+		}
+
+		c.closeErr = c.Conn.Close()
+	})
+
+	return c.closeErr
+}
+
+// hijackTracker keeps the hijacked connections of a server
+// in order to close them on its shutdown, the net/http server does not track them.
+type hijackTracker struct {
+	mu    sync.Mutex
+	conns map[*HijackedConn]struct{}
+}
+
+// key = *http.Server, value = *hijackTracker.
+var hijackTrackers sync.Map
+
+func getHijackTracker(r *http.Request) *hijackTracker {
+	srv, ok := r.Context().Value(http.ServerContextKey).(*http.Server)
+	if !ok || srv == nil {
+		return nil
+	}
+
+	if v, ok := hijackTrackers.Load(srv); ok {
+		return v.(*hijackTracker)
+	}
+
+	t := &hijackTracker{conns: make(map[*HijackedConn]struct{})}
+	v, loaded := hijackTrackers.LoadOrStore(srv, t)
+	if !loaded {
+		srv.RegisterOnShutdown(func() {
+			hijackTrackers.Delete(srv)
+			t.closeAll()
+		})
+	}
+
+	return v.(*hijackTracker)
+}
+
+func (t *hijackTracker) add(c *HijackedConn) {
+	t.mu.Lock()
+	t.conns[c] = struct{}{}
+	t.mu.Unlock()
+}
+
+func (t *hijackTracker) remove(c *HijackedConn) {
+	t.mu.Lock()
+	delete(t.conns, c)
+	t.mu.Unlock()
+}
+
+func (t *hijackTracker) closeAll() {
+	t.mu.Lock()
+	conns := make([]*HijackedConn, 0, len(t.conns))
+	for c := range t.conns {
+		conns = append(conns, c)
+	}
+	t.mu.Unlock()
+
+	for _, c := range conns {
+		if c.opts.OnShutdown != nil {
+			c.opts.OnShutdown(c)
+		}
+
+		c.Close()
+	}
+}
+
+// hijackerOf returns the first http.Hijacker of the (wrapped) response writer "w".
+func hijackerOf(w ResponseWriter) http.Hijacker {
+	for w != nil {
+		switch v := w.(type) {
+		case *ResponseRecorder:
+			w = v.ResponseWriter
+		case *CompressResponseWriter:
+			if v.Hijacker != nil {
+				return v.Hijacker
+			}
+			w = v.ResponseWriter
+		case http.Hijacker:
+			return v
+		default:
+			return nil
+		}
+	}
+
+	return nil
+}
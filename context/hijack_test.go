@@ -0,0 +1,95 @@
+package context_test
+
+import (
+	"bufio"
+	stdContext "context"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12"
+)
+
+func TestHijack(t *testing.T) {
+	app := iris.New()
+	app.Get("/echo", func(ctx iris.Context) {
+		conn, err := ctx.Hijack(iris.HijackOptions{ReadTimeout: 5 * time.Second})
+		if err != nil {
+			ctx.StopWithError(iris.StatusInternalServerError, err)
+			return
+		}
+
+		if !ctx.IsHijacked() {
+			t.Error("expected hijacked context")
+		}
+
+		conn.Write([]byte("HTTP/1.1 101 Switching Protocols\r\nUpgrade: echo\r\nConnection: Upgrade\r\n\r\n"))
+		go func() {
+			defer conn.Close()
+			io.Copy(conn, conn)
+		}()
+	}, func(ctx iris.Context) {
+		t.Error("next handler should not be executed after hijack")
+	})
+
+	if err := app.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := &http.Server{Handler: app}
+	go srv.Serve(ln)
+
+	client, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	client.SetDeadline(time.Now().Add(5 * time.Second))
+
+	// "ping" is sent along with the request, so it's buffered by the server.
+	if _, err = client.Write([]byte("GET /echo HTTP/1.1\r\nHost: localhost\r\n\r\nping\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	r := bufio.NewReader(client)
+	status, err := r.ReadString('\n')
+	if err != nil || !strings.Contains(status, "101") {
+		t.Fatalf("unexpected status line: %q: %v", status, err)
+	}
+
+	for { // skip headers.
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		if line == "\r\n" {
+			break
+		}
+	}
+
+	if line, err := r.ReadString('\n'); err != nil || line != "ping\n" {
+		t.Fatalf("expected echo of ping but got: %q: %v", line, err)
+	}
+
+	client.Write([]byte("pong\n"))
+	if line, err := r.ReadString('\n'); err != nil || line != "pong\n" {
+		t.Fatalf("expected echo of pong but got: %q: %v", line, err)
+	}
+
+	// the hijacked connection is closed on shutdown.
+	if err = srv.Shutdown(stdContext.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = r.ReadString('\n'); err != io.EOF {
+		t.Fatalf("expected EOF after shutdown but got: %v", err)
+	}
+}
@@ -0,0 +1,187 @@
+package context
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// The kinds of a `Metric`.
+const (
+	// MetricCounter is a value which only increases, e.g. the number of created orders.
+	MetricCounter = "counter"
+	// MetricGauge is a value which can go up and down, e.g. the items of a queue.
+	MetricGauge = "gauge"
+)
+
+// Metric is the snapshot of a business metric of a route,
+// see `MetricsRegistry.Snapshot`.
+type Metric struct {
+	// Name is the metric's name, e.g. "orders_created".
+	Name string `json:"name"`
+	// Route is the registered path of the route which recorded the metric,
+	// e.g. "/orders/{id}", empty when it's recorded outside of a route.
+	Route string `json:"route"`
+	// Kind is the `MetricCounter` or the `MetricGauge`.
+	Kind string `json:"kind"`
+	// Value is the current value of the metric.
+	Value float64 `json:"value"`
+}
+
+// MetricsRegistry aggregates the business counters and gauges per route
+// in memory. It is safe for concurrent use, the updates of an existing
+// metric are lock-free.
+//
+// The accesslog middleware's Metrics sink exports the `DefaultMetricsRegistry`
+// in the Prometheus text format, see `Context.Metrics` to record them.
+type MetricsRegistry struct {
+	mu     sync.RWMutex
+	series map[metricKey]*metricValue
+}
+
+type metricKey struct {
+	name  string
+	route string
+}
+
+type metricValue struct {
+	kind string
+	bits uint64 // float64 bits.
+}
+
+func (v *metricValue) add(delta float64) {
+	for {
+		old := atomic.LoadUint64(&v.bits)
+		updated := math.Float64bits(math.Float64frombits(old) + delta)
+		if atomic.CompareAndSwapUint64(&v.bits, old, updated) {
+			return
+		}
+	}
+}
+
+func (v *metricValue) set(value float64) {
+	atomic.StoreUint64(&v.bits, math.Float64bits(value))
+}
+
+func (v *metricValue) value() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&v.bits))
+}
+
+// NewMetricsRegistry returns a new empty metrics registry.
+func NewMetricsRegistry() *MetricsRegistry {
+	return &MetricsRegistry{series: make(map[metricKey]*metricValue)}
+}
+
+// DefaultMetricsRegistry is the metrics registry which `Context.Metrics` records to.
+var DefaultMetricsRegistry = NewMetricsRegistry()
+
+// get returns the metric of the "name" and "route",
+// the kind of a metric is the one of its first record.
+func (r *MetricsRegistry) get(name, route, kind string) *metricValue {
+	key := metricKey{name: name, route: route}
+
+	r.mu.RLock()
+	v, ok := r.series[key]
+	r.mu.RUnlock()
+	if ok {
+		return v
+	}
+
+	r.mu.Lock()
+	if v, ok = r.series[key]; !ok {
+		v = &metricValue{kind: kind}
+		r.series[key] = v
+	}
+	r.mu.Unlock()
+
+	return v
+}
+
+// Route returns the metrics recorder of the given "route" path, see `Context.Metrics`.
+func (r *MetricsRegistry) Route(route string) *Metrics {
+	return &Metrics{registry: r, route: route}
+}
+
+// Snapshot returns the current metrics, sorted by name and route.
+func (r *MetricsRegistry) Snapshot() []Metric {
+	r.mu.RLock()
+	list := make([]Metric, 0, len(r.series))
+	for key, v := range r.series {
+		list = append(list, Metric{
+			Name:  key.name,
+			Route: key.route,
+			Kind:  v.kind,
+			Value: v.value(),
+		})
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Name == list[j].Name {
+			return list[i].Route < list[j].Route
+		}
+
+		return list[i].Name < list[j].Name
+	})
+
+	return list
+}
+
+// Reset removes all metrics.
+func (r *MetricsRegistry) Reset() {
+	r.mu.Lock()
+	r.series = make(map[metricKey]*metricValue)
+	r.mu.Unlock()
+}
+
+// Metrics records the business counters and gauges of a route,
+// see `Context.Metrics`.
+type Metrics struct {
+	registry *MetricsRegistry
+	route    string
+}
+
+// Inc increases the "name" counter by one.
+func (m *Metrics) Inc(name string) {
+	m.Add(name, 1)
+}
+
+// Add increases the "name" counter by "delta",
+// a negative delta is ignored as counters only increase.
+func (m *Metrics) Add(name string, delta float64) {
+	if delta < 0 {
+		return
+	}
+
+	m.registry.get(name, m.route, MetricCounter).add(delta)
+}
+
+// Set sets the "name" gauge to "value".
+func (m *Metrics) Set(name string, value float64) {
+	m.registry.get(name, m.route, MetricGauge).set(value)
+}
+
+// AddGauge adds the "delta", which can be negative, to the "name" gauge,
+// e.g. +1 when a job starts and -1 when it finishes.
+func (m *Metrics) AddGauge(name string, delta float64) {
+	m.registry.get(name, m.route, MetricGauge).add(delta)
+}
+
+// Metrics returns the business metrics recorder of the current route,
+// the metrics are aggregated per route path in the `DefaultMetricsRegistry`
+// and exported in the Prometheus text format by the accesslog middleware's Metrics sink,
+// so the handlers can record them without a metrics client dependency.
+//
+// Example Code:
+//  ctx.Metrics().Inc("orders_created")
+//  ctx.Metrics().Add("revenue", order.Total)
+//  ctx.Metrics().Set("cart_items", float64(len(cart.Items)))
+func (ctx *Context) Metrics() *Metrics {
+	route := ""
+	if r := ctx.GetCurrentRoute(); r != nil {
+		route = r.Path()
+	}
+
+	return DefaultMetricsRegistry.Route(route)
+}
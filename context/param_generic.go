@@ -0,0 +1,155 @@
+//go:build go1.18
+// +build go1.18
+
+package context
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/kataras/iris/v12/core/memstore"
+
+	"github.com/google/uuid"
+)
+
+// ParamValue is the type constraint of the `Param` and `ParamDefault` functions,
+// the supported path parameter value types.
+type ParamValue interface {
+	~string | ~bool |
+		~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 |
+		~float32 | ~float64 |
+		time.Time | uuid.UUID
+}
+
+// Param returns the value of the "name" path parameter as T.
+// When the route's parameter type (macro) already validated and converted the value,
+// e.g. "{id:int64}" and Param[int64], it's returned as it is, without parsing it again.
+// Otherwise the value is parsed, time.Time values are parsed
+// as RFC3339 or "2006-01-02" dates.
+//
+// It returns a `memstore.ErrEntryNotFound` error if the parameter does not exist
+// and a parse error if the value cannot be converted to T.
+//
+// Usage:
+//  app.Get("/users/{id:uint64}/posts/{date}", func(ctx iris.Context) {
+//      id, _ := context.Param[uint64](ctx, "id")
+//      date, err := context.Param[time.Time](ctx, "date")
+//      if err != nil {
+//          ctx.StopWithError(iris.StatusBadRequest, err)
+//          return
+//      }
+//  })
+//
+// Available when built with go1.18 or newer.
+func Param[T ParamValue](ctx *Context, name string) (T, error) {
+	var value T
+
+	entry, ok := ctx.params.Store.GetEntry(name)
+	if !ok || entry.ValueRaw == nil {
+		return value, &memstore.ErrEntryNotFound{Key: name, Kind: reflect.TypeOf(value).Kind()}
+	}
+
+	if v, ok := entry.ValueRaw.(T); ok { // validated and converted by the macro.
+		return v, nil
+	}
+
+	if err := convertParamValue(entry.ValueRaw, &value); err != nil {
+		return value, fmt.Errorf("param: %s: %w", name, err)
+	}
+
+	return value, nil
+}
+
+// ParamDefault same as `Param` but it returns the "def" value
+// if the parameter does not exist or its value cannot be converted to T.
+//
+// Available when built with go1.18 or newer.
+func ParamDefault[T ParamValue](ctx *Context, name string, def T) T {
+	v, err := Param[T](ctx, name)
+	if err != nil {
+		return def
+	}
+
+	return v
+}
+
+func convertParamValue(raw interface{}, dest interface{}) error {
+	s, isString := raw.(string)
+
+	switch d := dest.(type) {
+	case *time.Time:
+		if !isString {
+			return fmt.Errorf("cannot convert %T to time.Time", raw)
+		}
+
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			if t, err = time.Parse("2006-01-02", s); err != nil {
+				return err
+			}
+		}
+
+		*d = t
+		return nil
+	case *uuid.UUID:
+		if !isString {
+			return fmt.Errorf("cannot convert %T to uuid.UUID", raw)
+		}
+
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return err
+		}
+
+		*d = id
+		return nil
+	}
+
+	v := reflect.ValueOf(dest).Elem()
+	if !isString {
+		// A value of a custom type of the same kind, e.g. type UserID int64
+		// with a {id:int64} parameter, convert it without parsing.
+		if rv := reflect.ValueOf(raw); rv.Kind() == v.Kind() {
+			v.Set(rv.Convert(v.Type()))
+			return nil
+		}
+
+		s = fmt.Sprintf("%v", raw)
+	}
+
+	switch kind := v.Kind(); kind {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+	default:
+		return fmt.Errorf("unsupported type: %s", v.Type())
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+//go:build go1.18
+// +build go1.18
+
+package context_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/core/memstore"
+	"github.com/kataras/iris/v12/httptest"
+
+	"github.com/google/uuid"
+)
+
+type testUserID int64
+
+func TestParam(t *testing.T) {
+	app := iris.New()
+	app.Get("/users/{id:int64}/posts/{date}/{ref:uuid}/{page}", func(ctx iris.Context) {
+		id, err := context.Param[int64](ctx, "id")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		userID, err := context.Param[testUserID](ctx, "id")
+		if err != nil || int64(userID) != id {
+			t.Fatalf("expected user id: %d but got: %d (%v)", id, userID, err)
+		}
+
+		date, err := context.Param[time.Time](ctx, "date")
+		if err != nil {
+			ctx.StopWithError(iris.StatusBadRequest, err)
+			return
+		}
+
+		ref, err := context.Param[uuid.UUID](ctx, "ref")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var notFound *memstore.ErrEntryNotFound
+		if _, err = context.Param[int](ctx, "missing"); !errors.As(err, &notFound) || notFound.Key != "missing" {
+			t.Fatalf("expected a not found error but got: %v", err)
+		}
+
+		page := context.ParamDefault[uint8](ctx, "page", 1)
+		ctx.Writef("%d:%s:%s:%d", id, date.Format("Jan 2"), ref.String()[:8], page)
+	})
+
+	e := httptest.New(t, app)
+	e.GET("/users/42/posts/2021-07-15/6ba7b810-9dad-11d1-80b4-00c04fd430c8/3").Expect().
+		Status(httptest.StatusOK).Body().Equal("42:Jul 15:6ba7b810:3")
+	e.GET("/users/42/posts/2021-07-15/6ba7b810-9dad-11d1-80b4-00c04fd430c8/300").Expect().
+		Status(httptest.StatusOK).Body().Equal("42:Jul 15:6ba7b810:1")
+	e.GET("/users/42/posts/yesterday/6ba7b810-9dad-11d1-80b4-00c04fd430c8/3").Expect().
+		Status(httptest.StatusBadRequest)
+}
@@ -27,6 +27,12 @@ func (c *Pool) Acquire(w http.ResponseWriter, r *http.Request) *Context {
 // See Acquire.
 func (c *Pool) Release(ctx *Context) {
 	ctx.EndRequest()
+	if ctx.strict != nil {
+		// keep it out of the pool, so a late use of it is reported,
+		// see the Configuration.StrictMode field.
+		return
+	}
+
 	c.pool.Put(ctx)
 }
 
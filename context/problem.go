@@ -333,3 +333,82 @@ func (o *ProblemOptions) Apply(ctx *Context) {
 		ctx.Header("Retry-After", retryAfterHeaderValue)
 	}
 }
+
+// ProblemErrors holds the options to translate error responses
+// to RFC 7807 problem documents, see `Problem`:
+// the responses of the error code handlers which do not write a body,
+// the default error handler's ones and the recovered panics.
+// See the `APIBuilder.UseProblemErrors` method.
+type ProblemErrors struct {
+	// TypeBaseURI, if not empty, is the base URI of the problem's "type" member,
+	// the status code is appended to it, e.g. "https://example.com/problems/"
+	// produces "https://example.com/problems/404". Relative paths are resolved to absolute URIs.
+	// Defaults to "about:blank".
+	TypeBaseURI string
+	// Instance returns the problem's "instance" member, a URI reference
+	// that identifies the specific occurrence of the problem.
+	// Defaults to "urn:uuid:{id}" when the request has an ID, see `Context.SetID`
+	// and the requestid middleware.
+	Instance func(ctx *Context) string
+	// Extensions can add extension members to the problem, e.g. a trace id.
+	Extensions func(ctx *Context, p Problem)
+	// PanicDetails exposes the cause and the handler of a recovered panic
+	// through the "panic" extension member. Should be enabled only on development.
+	PanicDetails bool
+}
+
+// Problem returns the problem document of the current error response.
+// The "detail" member is the error stored on the Context, if it's a public one, see `Context.SetErr`.
+// If the error has an `ErrorCode` then the problem is the code's one, see `ErrorCode.Problem`,
+// and the "detail" member is the error's cause.
+func (e *ProblemErrors) Problem(ctx *Context) Problem {
+	statusCode := ctx.GetStatusCode()
+
+	typ := "about:blank"
+	if e.TypeBaseURI != "" {
+		typ = e.TypeBaseURI + strconv.Itoa(statusCode)
+	}
+
+	p := NewProblem().Type(typ).Status(statusCode)
+	if code, ok := AsErrorCode(ctx.GetErr()); ok {
+		// The status code of the response is kept,
+		// an error handler may have changed it.
+		p = code.Problem(ctx).Status(statusCode)
+	}
+
+	if e.Instance != nil {
+		if instance := e.Instance(ctx); instance != "" {
+			p.Instance(instance)
+		}
+	} else if id := ctx.GetID(); id != nil {
+		p.Instance(fmt.Sprintf("urn:uuid:%v", id))
+	}
+
+	if public, err := ctx.GetErrPublic(); public && err != nil {
+		if codeErr, ok := err.(*CodeError); ok {
+			err = codeErr.Err
+		}
+		switch err.(type) {
+		case ErrorCode, ErrPrivate:
+		default:
+			p.DetailErr(err)
+		}
+	} else if recovery, ok := IsErrPanicRecovery(err); ok && e.PanicDetails {
+		p.Key("panic", map[string]interface{}{
+			"cause":   fmt.Sprint(recovery.Cause),
+			"handler": recovery.CurrentHandler,
+		})
+	}
+
+	if e.Extensions != nil {
+		e.Extensions(ctx, p)
+	}
+
+	return p
+}
+
+// Handler writes the problem document of the current error response,
+// it can be registered as an error code handler too.
+func (e *ProblemErrors) Handler(ctx *Context) {
+	ctx.Problem(e.Problem(ctx)) // nolint:errcheck
+}
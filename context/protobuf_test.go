@@ -0,0 +1,55 @@
+package context_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/httptest"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestProtobuf(t *testing.T) {
+	app := iris.New()
+	app.Post("/", func(ctx iris.Context) {
+		var msg wrapperspb.StringValue
+		if err := ctx.ReadBody(&msg); err != nil {
+			ctx.StopWithReadError(err)
+			return
+		}
+
+		msg.Value += "!"
+		ctx.Negotiation().JSON().Protobuf()
+		ctx.Negotiate(&msg)
+	})
+
+	body, err := proto.Marshal(wrapperspb.String("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := httptest.New(t, app)
+	got := e.POST("/").WithBytes(body).
+		WithHeader("Content-Type", context.ContentProtobufHeaderValue).
+		WithHeader("Accept", context.ContentProtobufHeaderValue).Expect().
+		Status(httptest.StatusOK).ContentType(context.ContentProtobufHeaderValue).Body().Raw()
+
+	expected, _ := proto.Marshal(wrapperspb.String("hello!"))
+	if !bytes.Equal([]byte(got), expected) {
+		t.Fatalf("expected protobuf body: %x but got: %x", expected, got)
+	}
+
+	e.POST("/").WithBytes([]byte(`"hello"`)).
+		WithHeader("Content-Type", context.ContentJSONHeaderValue).
+		WithHeader("Accept", context.ContentJSONHeaderValue).Expect().
+		Status(httptest.StatusOK).Body().Equal(`"hello!"`)
+
+	defer func(max int64) { context.ProtobufMaxSize = max }(context.ProtobufMaxSize)
+	context.ProtobufMaxSize = 4
+	e.POST("/").WithBytes(body).
+		WithHeader("Content-Type", context.ContentProtobufHeaderValue).Expect().
+		Status(httptest.StatusRequestEntityTooLarge)
+}
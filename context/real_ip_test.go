@@ -0,0 +1,61 @@
+package context_test
+
+import (
+	"net/http"
+	stdhttptest "net/http/httptest"
+	"testing"
+
+	"github.com/kataras/iris/v12"
+)
+
+func TestContextRealIP(t *testing.T) {
+	app := iris.New()
+	app.Configure(iris.WithTrustedProxies("10.0.0.0/8", "192.168.1.10"))
+	app.Get("/", func(ctx iris.Context) {
+		ctx.WriteString(ctx.RemoteAddr())
+	})
+	if err := app.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		peer     string
+		headers  http.Header
+		expected string
+	}{
+		// untrusted peer, headers are ignored.
+		{"203.0.113.7:4000", http.Header{"X-Forwarded-For": {"1.2.3.4"}}, "203.0.113.7"},
+		// trusted peer, no headers.
+		{"10.1.2.3:4000", nil, "10.1.2.3"},
+		// trusted peers, the client tried to spoof its IP.
+		{"10.1.2.3:4000", http.Header{"X-Forwarded-For": {"1.2.3.4, 198.51.100.2, 192.168.1.10"}}, "198.51.100.2"},
+		// the proxy appended a new header line to the client's one.
+		{"10.1.2.3:4000", http.Header{"X-Forwarded-For": {"1.2.3.4", "198.51.100.2"}}, "198.51.100.2"},
+		{"10.1.2.3:4000", http.Header{"Forwarded": {"for=1.2.3.4", "for=198.51.100.2"}}, "198.51.100.2"},
+		// all trusted, the left-most one is the client.
+		{"192.168.1.10:4000", http.Header{"X-Forwarded-For": {"10.0.0.5, 10.0.0.6"}}, "10.0.0.5"},
+		// Forwarded takes precedence.
+		{"10.1.2.3:4000", http.Header{
+			"Forwarded":       {`for="[2001:db8:cafe::17]:4711";proto=https, for=10.0.0.9`},
+			"X-Forwarded-For": {"198.51.100.2"},
+		}, "2001:db8:cafe::17"},
+		{"10.1.2.3:4000", http.Header{"X-Real-Ip": {"198.51.100.3"}}, "198.51.100.3"},
+	}
+
+	for i, tt := range tests {
+		req := stdhttptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = tt.peer
+		for k, values := range tt.headers {
+			for _, v := range values {
+				req.Header.Add(k, v)
+			}
+		}
+
+		rec := stdhttptest.NewRecorder()
+		app.ServeHTTP(rec, req)
+
+		if got := rec.Body.String(); got != tt.expected {
+			t.Fatalf("[%d] expected: %s but got: %s", i, tt.expected, got)
+		}
+	}
+}
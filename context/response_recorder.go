@@ -45,6 +45,8 @@ type ResponseRecorder struct {
 	headers http.Header
 
 	result *http.Response
+	// the status code of the underline response writer on `BeginRecord`, see `Rollback`.
+	beginStatusCode int
 }
 
 var _ ResponseWriter = (*ResponseRecorder)(nil)
@@ -61,9 +63,27 @@ func (w *ResponseRecorder) BeginRecord(underline ResponseWriter) {
 	w.ResponseWriter = underline
 	w.headers = underline.Header().Clone()
 	w.result = nil
+	w.beginStatusCode = underline.StatusCode()
 	w.ResetBody()
 }
 
+// Rollback discards the status code, the headers and the body
+// recorded since the `BeginRecord` (see `Context.Record`),
+// so a different response can be written instead.
+// It returns `ErrResponseHeadersWritten` if the response
+// was already flushed to the client, e.g. by a `Flush` call.
+func (w *ResponseRecorder) Rollback() error {
+	if w.ResponseWriter.Written() != NoWritten {
+		return ErrResponseHeadersWritten
+	}
+
+	w.ResetHeaders()
+	w.ResponseWriter.WriteHeader(w.beginStatusCode)
+	w.ResetBody()
+	w.result = nil
+	return nil
+}
+
 // EndResponse is auto-called when the whole client's request is done,
 // releases the response recorder and its underline ResponseWriter.
 func (w *ResponseRecorder) EndResponse() {
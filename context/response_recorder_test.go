@@ -0,0 +1,54 @@
+package context_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/httptest"
+)
+
+func TestContextRollback(t *testing.T) {
+	app := iris.New()
+	app.Use(func(ctx iris.Context) {
+		if ctx.URLParamExists("norecord") {
+			if err := ctx.Rollback(); err != context.ErrNotRecording {
+				t.Fatalf("expected error: %v but got: %v", context.ErrNotRecording, err)
+			}
+			ctx.Next()
+			return
+		}
+
+		ctx.Header("X-Kept", "true")
+		ctx.Record()
+		ctx.Next()
+
+		if ctx.GetErr() != nil {
+			if err := ctx.Rollback(); err != nil {
+				t.Fatal(err)
+			}
+
+			ctx.StopWithJSON(iris.StatusServiceUnavailable, iris.Map{"error": "try again later"})
+		}
+	})
+	app.Get("/", func(ctx iris.Context) {
+		ctx.Header("X-Partial", "true")
+		ctx.StatusCode(iris.StatusCreated)
+		ctx.WriteString("partial")
+
+		if ctx.URLParamExists("fail") {
+			ctx.SetErr(errors.New("step two failed"))
+		}
+	})
+
+	e := httptest.New(t, app)
+	e.GET("/").Expect().Status(httptest.StatusCreated).Body().Equal("partial")
+
+	resp := e.GET("/").WithQuery("fail", true).Expect().Status(httptest.StatusServiceUnavailable)
+	resp.Header("X-Partial").Empty()
+	resp.Header("X-Kept").Equal("true")
+	resp.JSON().Object().Value("error").Equal("try again later")
+
+	e.GET("/").WithQuery("norecord", true).Expect().Status(httptest.StatusCreated)
+}
@@ -0,0 +1,24 @@
+package context
+
+import "testing"
+
+func TestSanitizeHTML(t *testing.T) {
+	input := `<h1>Title</h1><p>Hi <b>there</b> <a href="https://example.com">link</a><script>alert(1)</script></p>`
+
+	tests := []struct {
+		policy   string
+		expected string
+	}{
+		{SanitizeStrict, `TitleHi there link`},
+		{SanitizeComments, `Title<p>Hi <b>there</b> <a href="https://example.com" rel="nofollow noopener" target="_blank">link</a></p>`},
+		{SanitizeRichText, `<h1>Title</h1><p>Hi <b>there</b> <a href="https://example.com" rel="nofollow">link</a></p>`},
+		{"", `<h1>Title</h1><p>Hi <b>there</b> <a href="https://example.com" rel="nofollow">link</a></p>`},
+		{"unknown", `TitleHi there link`},
+	}
+
+	for i, tt := range tests {
+		if got := SanitizeHTML(input, tt.policy); got != tt.expected {
+			t.Fatalf("[%d:%s] expected: %q but got: %q", i, tt.policy, tt.expected, got)
+		}
+	}
+}
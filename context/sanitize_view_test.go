@@ -0,0 +1,27 @@
+package context_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/httptest"
+)
+
+func TestSanitizeViewFunc(t *testing.T) {
+	app := iris.New()
+	app.RegisterView(iris.HTML(fstest.MapFS{
+		"comment.html": {Data: []byte(`{{ sanitize .Body }}|{{ sanitize .Body .Policy }}|{{ .Body }}`)},
+	}, ".html"))
+	app.Get("/", func(ctx iris.Context) {
+		ctx.View("comment.html", iris.Map{
+			"Body":   `<b>hi</b><script>alert(1)</script>`,
+			"Policy": context.SanitizeStrict,
+		})
+	})
+
+	expected := `<b>hi</b>|hi|&lt;b&gt;hi&lt;/b&gt;&lt;script&gt;alert(1)&lt;/script&gt;`
+	e := httptest.New(t, app)
+	e.GET("/").Expect().Status(httptest.StatusOK).Body().Equal(expected)
+}
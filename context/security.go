@@ -0,0 +1,195 @@
+package context
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// The schemes of a `SecurityRequirement`.
+const (
+	// SecurityBearer is the OAuth 2.0 bearer token scheme (RFC 6750), e.g. a JWT.
+	SecurityBearer = "bearer"
+	// SecurityBasic is the HTTP basic authentication scheme (RFC 7617).
+	SecurityBasic = "basic"
+	// SecurityAPIKey is the API key scheme, the key is sent
+	// through a header, a URL query parameter or a cookie.
+	SecurityAPIKey = "apiKey"
+)
+
+// SecurityRequirement declares how the clients authenticate to a route,
+// so its 401 Unauthorized and 403 Forbidden responses carry the correct
+// WWW-Authenticate challenge and the OpenAPI document describes it,
+// see the `Route.Secure` and `Party.Secure` methods.
+type SecurityRequirement struct {
+	// Scheme is the `SecurityBearer`, `SecurityBasic` or `SecurityAPIKey`. Required.
+	Scheme string `json:"scheme"`
+	// Name is the name of the OpenAPI security scheme.
+	// Defaults to the scheme followed by "Auth", e.g. "bearerAuth".
+	Name string `json:"name,omitempty"`
+	// Realm is the protection space of the challenge, e.g. "api".
+	Realm string `json:"realm,omitempty"`
+	// Scopes are the scopes a bearer token requires,
+	// they are sent with the "insufficient_scope" error of a 403 Forbidden response.
+	Scopes []string `json:"scopes,omitempty"`
+	// In is the location of the API key: "header", "query" or "cookie".
+	// Defaults to "header".
+	In string `json:"in,omitempty"`
+	// KeyName is the name of the API key's header, query parameter or cookie.
+	// Defaults to "X-API-Key".
+	KeyName string `json:"keyName,omitempty"`
+}
+
+// SchemeName returns the name of the OpenAPI security scheme, see `Name`.
+func (r SecurityRequirement) SchemeName() string {
+	if r.Name != "" {
+		return r.Name
+	}
+
+	return r.Scheme + "Auth"
+}
+
+func (r SecurityRequirement) keyLocation() (string, string) {
+	in, name := r.In, r.KeyName
+	if in == "" {
+		in = "header"
+	}
+
+	if name == "" {
+		name = "X-API-Key"
+	}
+
+	return in, name
+}
+
+// Challenge returns the WWW-Authenticate header value of the requirement
+// for the response's "statusCode" or empty if the response does not challenge the client,
+// i.e. a 403 Forbidden challenges only the bearer tokens of insufficient scope.
+//
+// A bearer challenge carries the "invalid_token" error code when the request
+// sent credentials, so the clients can tell a missing token from a rejected one.
+func (r SecurityRequirement) Challenge(ctx *Context, statusCode int) string {
+	params := make([]string, 0, 4)
+	if r.Realm != "" {
+		params = append(params, "realm="+strconv.Quote(r.Realm))
+	}
+
+	var scheme string
+	switch r.Scheme {
+	case SecurityBearer:
+		scheme = "Bearer"
+		switch statusCode {
+		case http.StatusUnauthorized:
+			if ctx != nil && ctx.GetHeader("Authorization") != "" {
+				params = append(params, `error="invalid_token"`)
+			}
+		case http.StatusForbidden:
+			if len(r.Scopes) == 0 {
+				return ""
+			}
+
+			params = append(params, `error="insufficient_scope"`, "scope="+strconv.Quote(strings.Join(r.Scopes, " ")))
+		default:
+			return ""
+		}
+	case SecurityBasic:
+		if statusCode != http.StatusUnauthorized {
+			return ""
+		}
+
+		scheme = "Basic"
+		params = append(params, `charset="UTF-8"`)
+	case SecurityAPIKey:
+		if statusCode != http.StatusUnauthorized {
+			return ""
+		}
+
+		scheme = "APIKey"
+		in, name := r.keyLocation()
+		params = append(params, "in="+strconv.Quote(in), "name="+strconv.Quote(name))
+	default:
+		return ""
+	}
+
+	if len(params) == 0 {
+		return scheme
+	}
+
+	return scheme + " " + strings.Join(params, ", ")
+}
+
+// OpenAPIScheme returns the OpenAPI (v3) security scheme object of the requirement,
+// to be added to the "components.securitySchemes" of an OpenAPI document,
+// see `OpenAPISecuritySchemes`.
+func (r SecurityRequirement) OpenAPIScheme() map[string]interface{} {
+	switch r.Scheme {
+	case SecurityAPIKey:
+		in, name := r.keyLocation()
+		return map[string]interface{}{"type": "apiKey", "in": in, "name": name}
+	default:
+		return map[string]interface{}{"type": "http", "scheme": r.Scheme}
+	}
+}
+
+// OpenAPISecuritySchemes returns the OpenAPI (v3) "components.securitySchemes" object
+// of the given requirements, e.g. of all the application's routes.
+func OpenAPISecuritySchemes(requirements []SecurityRequirement) map[string]interface{} {
+	schemes := make(map[string]interface{}, len(requirements))
+	for _, r := range requirements {
+		schemes[r.SchemeName()] = r.OpenAPIScheme()
+	}
+
+	return schemes
+}
+
+// OpenAPISecurity returns the OpenAPI (v3) "security" list of an operation,
+// any of the given requirements authenticates the request.
+func OpenAPISecurity(requirements []SecurityRequirement) []map[string][]string {
+	security := make([]map[string][]string, 0, len(requirements))
+	for _, r := range requirements {
+		scopes := r.Scopes
+		if scopes == nil {
+			scopes = []string{}
+		}
+
+		security = append(security, map[string][]string{r.SchemeName(): scopes})
+	}
+
+	return security
+}
+
+const securityRequirementsContextKey = "iris.security.requirements"
+
+// SetSecurityRequirements sets the security requirements of the current request,
+// so a 401 Unauthorized or a 403 Forbidden status code, e.g. by an auth guard,
+// sends their WWW-Authenticate challenges, unless the header is already set.
+// It's called automatically on the routes of `Route.Secure`.
+func (ctx *Context) SetSecurityRequirements(requirements ...SecurityRequirement) {
+	ctx.values.Set(securityRequirementsContextKey, requirements)
+}
+
+// GetSecurityRequirements returns the security requirements of the current request,
+// see `SetSecurityRequirements`.
+func (ctx *Context) GetSecurityRequirements() []SecurityRequirement {
+	requirements, _ := ctx.values.Get(securityRequirementsContextKey).([]SecurityRequirement)
+	return requirements
+}
+
+// writeSecurityChallenges sets the WWW-Authenticate header of the security requirements.
+func (ctx *Context) writeSecurityChallenges(statusCode int) {
+	requirements := ctx.GetSecurityRequirements()
+	if len(requirements) == 0 {
+		return
+	}
+
+	header := ctx.writer.Header()
+	if header.Get(WWWAuthenticateHeaderKey) != "" { // set by the guard itself, e.g. basicauth.
+		return
+	}
+
+	for _, r := range requirements {
+		if challenge := r.Challenge(ctx, statusCode); challenge != "" {
+			header.Add(WWWAuthenticateHeaderKey, challenge)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package context_test
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/httptest"
+)
+
+func TestServeContentRange(t *testing.T) {
+	data := []byte("0123456789abcdefghijklmnopqrstuvwxyz")
+	modtime := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	app := iris.New()
+	app.Use(iris.Compression)
+	app.Get("/video.mp4", func(ctx iris.Context) {
+		ctx.ServeContent(bytes.NewReader(data), "video.mp4", modtime)
+	})
+
+	e := httptest.New(t, app)
+
+	r := e.GET("/video.mp4").WithHeader(context.RangeHeaderKey, "bytes=10-14").WithHeader("Accept-Encoding", "gzip").
+		Expect().Status(httptest.StatusPartialContent)
+	r.Header("Content-Range").Equal("bytes 10-14/36")
+	r.Header("Content-Type").Match("^video/mp4")
+	r.Headers().NotContainsKey("Content-Encoding")
+	r.Body().Equal("abcde")
+
+	r = e.GET("/video.mp4").WithHeader(context.RangeHeaderKey, "bytes=0-1,34-").Expect().
+		Status(httptest.StatusPartialContent)
+	r.Header("Content-Type").Match("^multipart/byteranges; boundary=")
+	body := r.Body().Raw()
+	if !strings.Contains(body, "Content-Range: bytes 0-1/36\r\n") || !strings.Contains(body, "\r\n\r\nyz\r\n") {
+		t.Fatalf("unexpected multipart body: %q", body)
+	}
+
+	// If-Range does not match, the whole (compressed) content is sent.
+	e.GET("/video.mp4").WithHeader(context.RangeHeaderKey, "bytes=0-1").
+		WithHeader("If-Range", "Thu, 31 Dec 2020 00:00:00 GMT").
+		Expect().Status(httptest.StatusOK).Body().Equal(string(data))
+	e.GET("/video.mp4").WithHeader(context.RangeHeaderKey, "bytes=0-1").
+		WithHeader("If-Range", modtime.Format(http.TimeFormat)).
+		Expect().Status(httptest.StatusPartialContent).Body().Equal("01")
+
+	e.GET("/video.mp4").WithHeader(context.RangeHeaderKey, "bytes=100-").Expect().
+		Status(httptest.StatusRequestedRangeNotSatisfiable)
+}
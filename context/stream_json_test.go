@@ -0,0 +1,61 @@
+package context_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/httptest"
+)
+
+func TestStreamJSON(t *testing.T) {
+	type item struct {
+		ID int `json:"id"`
+	}
+
+	app := iris.New()
+	app.Get("/chan", func(ctx iris.Context) {
+		ch := make(chan item)
+		go func() {
+			defer close(ch)
+			for i := 1; i <= 3; i++ {
+				ch <- item{ID: i}
+			}
+		}()
+
+		if err := ctx.StreamJSON(ch, iris.StreamJSONOptions{FlushCount: 2}); err != nil {
+			t.Error(err)
+		}
+	})
+	app.Get("/iter", func(ctx iris.Context) {
+		err := ctx.StreamJSON(func(yield func(v interface{}) bool) {
+			for i := 1; i <= 3; i++ {
+				if !yield(item{ID: i}) {
+					return
+				}
+			}
+
+			if yield(errors.New("stop")) {
+				t.Error("yield should stop after an error value")
+			}
+		})
+
+		if err == nil || err.Error() != "stop" {
+			t.Errorf("expected stop error but got: %v", err)
+		}
+	})
+	app.Get("/invalid", func(ctx iris.Context) {
+		if err := ctx.StreamJSON([]item{{ID: 1}}); err == nil {
+			t.Error("expected unsupported iterator error")
+		}
+	})
+
+	expected := "{\"id\":1}\n{\"id\":2}\n{\"id\":3}\n"
+	e := httptest.New(t, app)
+	e.GET("/chan").Expect().Status(httptest.StatusOK).
+		ContentType(context.ContentNDJSONHeaderValue).Body().Equal(expected)
+	e.GET("/iter").Expect().Status(httptest.StatusOK).
+		ContentType(context.ContentNDJSONHeaderValue).Body().Equal(expected)
+	e.GET("/invalid").Expect().Status(httptest.StatusOK)
+}
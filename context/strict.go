@@ -0,0 +1,234 @@
+package context
+
+import (
+	"bufio"
+	stdContext "context"
+	"errors"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrContextReleased is returned by the response writer on strict mode
+// when it's used after the handler returned and the Context was released,
+// see `Configuration.StrictMode`.
+var ErrContextReleased = errors.New("context: the response writer was used after the Context was released")
+
+// strictMode is the per-request state of the strict mode,
+// see the `Configuration.StrictMode` field.
+type strictMode struct {
+	ctx      *Context
+	w        http.ResponseWriter // the underline response writer.
+	released uint32
+
+	// the headers sent to the client, if any.
+	sentHeader http.Header
+}
+
+type strictReport struct {
+	app   Application
+	kind  string
+	route string
+}
+
+// the reported misuses, once per application, kind and route.
+var strictReported sync.Map
+
+func (s *strictMode) report(kind, format string, args ...interface{}) {
+	ctx := s.ctx
+
+	route := ""
+	if ctx.currentRoute != nil {
+		route = ctx.currentRoute.Name()
+	}
+
+	if _, loaded := strictReported.LoadOrStore(strictReport{app: ctx.app, kind: kind, route: route}, struct{}{}); loaded {
+		return
+	}
+
+	args = append([]interface{}{ctx.request.Method, ctx.request.URL.Path}, args...)
+	ctx.app.Logger().Warnf("strict mode: %s %s: "+format, args...)
+}
+
+func (s *strictMode) isReleased() bool {
+	return atomic.LoadUint32(&s.released) == 1
+}
+
+// check reports the use of the response writer after the Context was released
+// or after the request's deadline exceeded and reports whether the call can proceed.
+func (s *strictMode) check(action string) bool {
+	if s.isReleased() {
+		s.report("released", "%s after the handler returned and the Context was released, the call was ignored; "+
+			"wait for the goroutines which use the Context to finish before returning from the handler", action)
+		return false
+	}
+
+	if s.ctx.request.Context().Err() == stdContext.DeadlineExceeded {
+		s.report("timeout", "%s after the request's deadline exceeded, the client may have already received a timeout response; "+
+			"check the ctx.IsCanceled() or the ctx.Request().Context().Done() before writing", action)
+	}
+
+	return true
+}
+
+// checkNext reports a handler of the route which did not call the `Context.Next`
+// and so the route's done handlers were not executed.
+func (s *strictMode) checkNext() {
+	ctx := s.ctx
+	if ctx.currentRoute == nil || ctx.IsStopped() {
+		return
+	}
+
+	route, ok := ctx.currentRoute.(interface{ DoneHandlersLen() int })
+	if !ok {
+		return
+	}
+
+	doneIndex := len(ctx.handlers) - route.DoneHandlersLen()
+	if doneIndex >= len(ctx.handlers) || ctx.currentHandlerIndex >= doneIndex || ctx.currentHandlerIndex < 0 {
+		return
+	}
+
+	s.report("next", "the %s handler did not call ctx.Next(), so the route's done handlers (%s) were not executed; "+
+		"call ctx.Next() at the end of the handler, ctx.StopExecution() to skip them explicitly "+
+		"or set the ExecutionRules.Done.Force to execute them anyway",
+		HandlerName(ctx.handlers[ctx.currentHandlerIndex]), HandlersNames(ctx.handlers[doneIndex:]))
+}
+
+// headersSent keeps a copy of the headers sent to the client.
+func (s *strictMode) headersSent(header http.Header) {
+	if s.sentHeader == nil {
+		s.sentHeader = header.Clone()
+	}
+}
+
+// checkHeaders reports the response headers which were added or modified
+// after the headers were sent to the client, the trailers are excluded.
+func (s *strictMode) checkHeaders(header http.Header) {
+	if s.sentHeader == nil {
+		return
+	}
+
+	trailers := make(map[string]struct{})
+	for _, v := range s.sentHeader.Values("Trailer") {
+		for _, name := range strings.Split(v, ",") {
+			trailers[http.CanonicalHeaderKey(strings.TrimSpace(name))] = struct{}{}
+		}
+	}
+
+	var modified []string
+	for name, values := range header {
+		if strings.HasPrefix(name, http.TrailerPrefix) {
+			continue
+		}
+
+		if _, ok := trailers[name]; ok {
+			continue
+		}
+
+		if strings.Join(values, ",") != strings.Join(s.sentHeader[name], ",") {
+			modified = append(modified, name)
+		}
+	}
+
+	if len(modified) == 0 {
+		return
+	}
+
+	sort.Strings(modified)
+	s.report("header", "the %s response headers were modified after the headers were sent to the client and they were ignored; "+
+		"set the headers before the first write, e.g. before the ctx.Write, ctx.JSON and ctx.Flush calls", strings.Join(modified, ", "))
+}
+
+// end checks the sent headers and marks the Context as released.
+func (s *strictMode) end() {
+	s.checkHeaders(s.w.Header())
+	atomic.StoreUint32(&s.released, 1)
+}
+
+// strictResponseWriter wraps the underline http.ResponseWriter on strict mode.
+type strictResponseWriter struct {
+	http.ResponseWriter
+	strict *strictMode
+}
+
+var _ http.ResponseWriter = (*strictResponseWriter)(nil)
+
+// Unwrap returns the underline http.ResponseWriter.
+func (w *strictResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Header returns the response headers, a temporary map after the Context was released.
+func (w *strictResponseWriter) Header() http.Header {
+	if w.strict.isReleased() {
+		w.strict.check("the response headers were accessed")
+		return make(http.Header)
+	}
+
+	return w.ResponseWriter.Header()
+}
+
+// WriteHeader sends the status code and the headers.
+func (w *strictResponseWriter) WriteHeader(statusCode int) {
+	if !w.strict.check("the status code was written") {
+		return
+	}
+
+	w.ResponseWriter.WriteHeader(statusCode)
+	if statusCode >= http.StatusOK {
+		w.strict.headersSent(w.ResponseWriter.Header())
+	}
+}
+
+// Write writes the response body.
+func (w *strictResponseWriter) Write(b []byte) (int, error) {
+	if !w.strict.check("the response body was written") {
+		return 0, ErrContextReleased
+	}
+
+	n, err := w.ResponseWriter.Write(b)
+	w.strict.headersSent(w.ResponseWriter.Header())
+	return n, err
+}
+
+// Flush sends any buffered data to the client, if supported.
+func (w *strictResponseWriter) Flush() {
+	if !w.strict.check("the response was flushed") {
+		return
+	}
+
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+		w.strict.headersSent(w.ResponseWriter.Header())
+	}
+}
+
+// Hijack takes over the connection, if supported.
+func (w *strictResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if !w.strict.check("the connection was hijacked") {
+		return nil, nil, ErrContextReleased
+	}
+
+	if h, ok := w.ResponseWriter.(http.Hijacker); ok {
+		return h.Hijack()
+	}
+
+	return nil, nil, ErrHijackNotSupported
+}
+
+// Push initiates an HTTP/2 server push, if supported.
+func (w *strictResponseWriter) Push(target string, opts *http.PushOptions) error {
+	if !w.strict.check("a resource was pushed") {
+		return ErrContextReleased
+	}
+
+	if p, ok := w.ResponseWriter.(http.Pusher); ok {
+		return p.Push(target, opts)
+	}
+
+	return http.ErrNotSupported
+}
@@ -0,0 +1,78 @@
+package context_test
+
+import (
+	"bytes"
+	stdContext "context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/httptest"
+)
+
+func TestStrictMode(t *testing.T) {
+	var logs bytes.Buffer
+
+	app := iris.New().Configure(iris.WithStrictMode)
+	app.Logger().SetOutput(&logs)
+
+	app.Done(func(ctx iris.Context) {})
+	app.Get("/next", func(ctx iris.Context) { ctx.WriteString("next") })
+	app.Get("/stopped", func(ctx iris.Context) {
+		ctx.WriteString("stopped")
+		ctx.StopExecution()
+	})
+	app.Get("/header", func(ctx iris.Context) {
+		ctx.WriteString("header")
+		ctx.Header("X-Late", "true")
+		ctx.Next()
+	})
+	app.Get("/timeout", func(ctx iris.Context) {
+		timeoutCtx, cancel := stdContext.WithDeadline(ctx.Request().Context(), time.Now())
+		defer cancel()
+		ctx.ResetRequest(ctx.Request().WithContext(timeoutCtx))
+		ctx.WriteString("timeout")
+		ctx.Next()
+	})
+
+	start, done := make(chan struct{}), make(chan error)
+	app.Get("/released", func(ctx iris.Context) {
+		go func() {
+			<-start
+			_, err := ctx.WriteString("late")
+			done <- err
+		}()
+		ctx.Next()
+	})
+
+	e := httptest.New(t, app, httptest.LogLevel("warn"))
+	e.GET("/next").Expect().Status(httptest.StatusOK).Body().Equal("next")
+	e.GET("/next").Expect().Status(httptest.StatusOK)
+	e.GET("/stopped").Expect().Status(httptest.StatusOK)
+	e.GET("/header").Expect().Status(httptest.StatusOK).Header("X-Late").Empty()
+	e.GET("/timeout").Expect().Status(httptest.StatusOK)
+	e.GET("/released").Expect().Status(httptest.StatusOK).Body().Empty()
+
+	close(start)
+	if err := <-done; err != context.ErrContextReleased {
+		t.Fatalf("expected ErrContextReleased but got: %v", err)
+	}
+
+	got := logs.String()
+	for _, expected := range []string{
+		"strict mode: GET /next: the iris/context_test.TestStrictMode.func2 handler did not call ctx.Next()",
+		"strict mode: GET /header: the X-Late response headers were modified after the headers were sent to the client",
+		"strict mode: GET /timeout: the status code was written after the request's deadline exceeded",
+		"strict mode: GET /released: the response body was written after the handler returned and the Context was released",
+	} {
+		if !strings.Contains(got, expected) {
+			t.Fatalf("expected the logs to contain:\n%s\nbut got:\n%s", expected, got)
+		}
+	}
+
+	if n := strings.Count(got, "did not call ctx.Next()"); n != 1 {
+		t.Fatalf("expected the misuse to be reported once but it was reported %d times:\n%s", n, got)
+	}
+}
@@ -0,0 +1,726 @@
+package context
+
+import (
+	"encoding"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ErrStructuredField is returned (wrapped) when a header value
+// is not a valid RFC 8941 Structured Field or
+// when a Structured Field value can not be serialized.
+var ErrStructuredField = errors.New("invalid structured field")
+
+type (
+	// StructuredToken is the Token bare item type of a Structured Field,
+	// e.g. the "u" of the Priority header's "u=3".
+	StructuredToken string
+
+	// StructuredParam is a single parameter of a Structured Field item or inner list.
+	StructuredParam struct {
+		Key string
+		// Value is a bare item: int64, float64 (decimal), string,
+		// StructuredToken, []byte or bool.
+		Value interface{}
+	}
+
+	// StructuredParams is the ordered parameters of a Structured Field item or inner list.
+	StructuredParams []StructuredParam
+
+	// StructuredItem is an RFC 8941 Item, a bare item with its parameters, e.g. `"text";lang=en`.
+	StructuredItem struct {
+		// Value is the bare item: int64, float64 (decimal), string,
+		// StructuredToken, []byte or bool.
+		// The int type is accepted on serialization too.
+		Value  interface{}
+		Params StructuredParams
+	}
+
+	// StructuredInnerList is an RFC 8941 Inner List,
+	// a list of items with its parameters, e.g. `("a" "b");q=1`.
+	StructuredInnerList struct {
+		Items  []StructuredItem
+		Params StructuredParams
+	}
+
+	// StructuredMember is a member of a `StructuredList` or a `StructuredDictionary`,
+	// it is a `StructuredItem` or a `StructuredInnerList`.
+	StructuredMember interface {
+		structuredMember()
+	}
+
+	// StructuredList is an RFC 8941 List, e.g. the Accept-CH `Sec-CH-UA-Model, Sec-CH-UA-Platform`.
+	StructuredList []StructuredMember
+
+	// StructuredDictionaryMember is a single key-member entry of a `StructuredDictionary`.
+	StructuredDictionaryMember struct {
+		Key   string
+		Value StructuredMember
+	}
+
+	// StructuredDictionary is an RFC 8941 Dictionary, ordered, e.g. the Priority's `u=3, i`.
+	StructuredDictionary []StructuredDictionaryMember
+)
+
+var (
+	_ encoding.TextMarshaler = StructuredItem{}
+	_ encoding.TextMarshaler = StructuredList(nil)
+	_ encoding.TextMarshaler = StructuredDictionary(nil)
+)
+
+func (StructuredItem) structuredMember()      {}
+func (StructuredInnerList) structuredMember() {}
+
+// Get returns the value of the "key" parameter.
+func (p StructuredParams) Get(key string) (interface{}, bool) {
+	for _, param := range p {
+		if param.Key == key {
+			return param.Value, true
+		}
+	}
+
+	return nil, false
+}
+
+// Get returns the member of the "key".
+func (d StructuredDictionary) Get(key string) (StructuredMember, bool) {
+	for _, m := range d {
+		if m.Key == key {
+			return m.Value, true
+		}
+	}
+
+	return nil, false
+}
+
+// ParseStructuredItem parses an RFC 8941 Item header value, e.g. the Sec-CH-UA-Mobile's "?1".
+func ParseStructuredItem(header string) (StructuredItem, error) {
+	p := &sfParser{s: header}
+	p.discardSP()
+	item, err := p.parseItem()
+	if err != nil {
+		return StructuredItem{}, err
+	}
+
+	return item, p.end()
+}
+
+// ParseStructuredList parses an RFC 8941 List header value.
+// An empty value is an empty list.
+func ParseStructuredList(header string) (StructuredList, error) {
+	p := &sfParser{s: header}
+	p.discardSP()
+
+	var list StructuredList
+	for !p.empty() {
+		member, err := p.parseMember()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, member)
+
+		if ok, err := p.nextMember(); !ok || err != nil {
+			return list, err
+		}
+	}
+
+	return list, nil
+}
+
+// ParseStructuredDictionary parses an RFC 8941 Dictionary header value.
+// An empty value is an empty dictionary.
+// A duplicated key overrides the value of the previous one.
+func ParseStructuredDictionary(header string) (StructuredDictionary, error) {
+	p := &sfParser{s: header}
+	p.discardSP()
+
+	var dict StructuredDictionary
+	for !p.empty() {
+		key, err := p.parseKey()
+		if err != nil {
+			return nil, err
+		}
+
+		var member StructuredMember
+		if p.peek() == '=' {
+			p.pos++
+			if member, err = p.parseMember(); err != nil {
+				return nil, err
+			}
+		} else {
+			params, err := p.parseParams()
+			if err != nil {
+				return nil, err
+			}
+			member = StructuredItem{Value: true, Params: params}
+		}
+
+		dict = dict.set(key, member)
+
+		if ok, err := p.nextMember(); !ok || err != nil {
+			return dict, err
+		}
+	}
+
+	return dict, nil
+}
+
+func (d StructuredDictionary) set(key string, member StructuredMember) StructuredDictionary {
+	for i := range d {
+		if d[i].Key == key {
+			d[i].Value = member
+			return d
+		}
+	}
+
+	return append(d, StructuredDictionaryMember{Key: key, Value: member})
+}
+
+// MarshalText serializes the item as an RFC 8941 Item header value.
+func (i StructuredItem) MarshalText() ([]byte, error) {
+	var b strings.Builder
+	if err := writeStructuredItem(&b, i); err != nil {
+		return nil, err
+	}
+
+	return []byte(b.String()), nil
+}
+
+// MarshalText serializes the list as an RFC 8941 List header value.
+func (l StructuredList) MarshalText() ([]byte, error) {
+	var b strings.Builder
+	for i, member := range l {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+
+		if err := writeStructuredMember(&b, member); err != nil {
+			return nil, err
+		}
+	}
+
+	return []byte(b.String()), nil
+}
+
+// MarshalText serializes the dictionary as an RFC 8941 Dictionary header value.
+func (d StructuredDictionary) MarshalText() ([]byte, error) {
+	var b strings.Builder
+	for i, m := range d {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+
+		if err := writeStructuredKey(&b, m.Key); err != nil {
+			return nil, err
+		}
+
+		if item, ok := m.Value.(StructuredItem); ok && item.Value == true {
+			if err := writeStructuredParams(&b, item.Params); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		b.WriteByte('=')
+		if err := writeStructuredMember(&b, m.Value); err != nil {
+			return nil, err
+		}
+	}
+
+	return []byte(b.String()), nil
+}
+
+// GetStructuredHeaderItem parses the "name" request header as an RFC 8941 Item,
+// it returns `ErrNotFound` if the header is missing.
+//
+// Example Code:
+//  mobile, err := ctx.GetStructuredHeaderItem("Sec-CH-UA-Mobile")
+//  if err == nil && mobile.Value == true { [...] }
+func (ctx *Context) GetStructuredHeaderItem(name string) (StructuredItem, error) {
+	header, ok := ctx.structuredHeader(name)
+	if !ok {
+		return StructuredItem{}, ErrNotFound
+	}
+
+	return ParseStructuredItem(header)
+}
+
+// GetStructuredHeaderList parses the "name" request header as an RFC 8941 List,
+// multiple header lines are combined. A missing header is an empty list.
+func (ctx *Context) GetStructuredHeaderList(name string) (StructuredList, error) {
+	header, _ := ctx.structuredHeader(name)
+	return ParseStructuredList(header)
+}
+
+// GetStructuredHeaderDictionary parses the "name" request header as an RFC 8941 Dictionary,
+// multiple header lines are combined. A missing header is an empty dictionary.
+//
+// Example Code:
+//  priority, err := ctx.GetStructuredHeaderDictionary("Priority")
+//  [handle err...]
+//  urgency, ok := priority.Get("u")
+func (ctx *Context) GetStructuredHeaderDictionary(name string) (StructuredDictionary, error) {
+	header, _ := ctx.structuredHeader(name)
+	return ParseStructuredDictionary(header)
+}
+
+func (ctx *Context) structuredHeader(name string) (string, bool) {
+	values := ctx.request.Header.Values(name)
+	return strings.Join(values, ","), len(values) > 0
+}
+
+// SetStructuredHeader serializes and sets the "name" response header
+// to the "field" value, a `StructuredItem`, `StructuredList` or `StructuredDictionary`.
+// An empty list or dictionary removes the header.
+//
+// Example Code:
+//  ctx.SetStructuredHeader("Priority", context.StructuredDictionary{
+//      {Key: "u", Value: context.StructuredItem{Value: 1}},
+//  })
+func (ctx *Context) SetStructuredHeader(name string, field encoding.TextMarshaler) error {
+	value, err := field.MarshalText()
+	if err != nil {
+		return err
+	}
+
+	ctx.writer.Header().Del(name)
+	ctx.Header(name, string(value))
+	return nil
+}
+
+// sfParser implements the parsing algorithms of the RFC 8941, section 4.2.
+type sfParser struct {
+	s   string
+	pos int
+}
+
+func (p *sfParser) empty() bool {
+	return p.pos >= len(p.s)
+}
+
+func (p *sfParser) peek() byte {
+	if p.empty() {
+		return 0
+	}
+
+	return p.s[p.pos]
+}
+
+func (p *sfParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s at %d", ErrStructuredField, fmt.Sprintf(format, args...), p.pos)
+}
+
+func (p *sfParser) discardSP() {
+	for p.peek() == ' ' {
+		p.pos++
+	}
+}
+
+func (p *sfParser) discardOWS() {
+	for c := p.peek(); c == ' ' || c == '\t'; c = p.peek() {
+		p.pos++
+	}
+}
+
+func (p *sfParser) end() error {
+	p.discardSP()
+	if !p.empty() {
+		return p.errorf("unexpected %q", p.peek())
+	}
+
+	return nil
+}
+
+// nextMember consumes the comma between two list or dictionary members,
+// it reports false on the end of the input.
+func (p *sfParser) nextMember() (bool, error) {
+	p.discardOWS()
+	if p.empty() {
+		return false, nil
+	}
+
+	if p.peek() != ',' {
+		return false, p.errorf("expected a comma")
+	}
+	p.pos++
+	p.discardOWS()
+
+	if p.empty() {
+		return false, p.errorf("trailing comma")
+	}
+
+	return true, nil
+}
+
+func (p *sfParser) parseMember() (StructuredMember, error) {
+	if p.peek() == '(' {
+		return p.parseInnerList()
+	}
+
+	return p.parseItem()
+}
+
+func (p *sfParser) parseInnerList() (StructuredInnerList, error) {
+	var list StructuredInnerList
+	p.pos++ // (
+	for !p.empty() {
+		p.discardSP()
+		if p.peek() == ')' {
+			p.pos++
+			params, err := p.parseParams()
+			list.Params = params
+			return list, err
+		}
+
+		item, err := p.parseItem()
+		if err != nil {
+			return list, err
+		}
+		list.Items = append(list.Items, item)
+
+		if c := p.peek(); c != ' ' && c != ')' {
+			return list, p.errorf("expected a space or ')' in inner list")
+		}
+	}
+
+	return list, p.errorf("unterminated inner list")
+}
+
+func (p *sfParser) parseItem() (StructuredItem, error) {
+	value, err := p.parseBareItem()
+	if err != nil {
+		return StructuredItem{}, err
+	}
+
+	params, err := p.parseParams()
+	return StructuredItem{Value: value, Params: params}, err
+}
+
+func (p *sfParser) parseParams() (StructuredParams, error) {
+	var params StructuredParams
+	for p.peek() == ';' {
+		p.pos++
+		p.discardSP()
+		key, err := p.parseKey()
+		if err != nil {
+			return nil, err
+		}
+
+		var value interface{} = true
+		if p.peek() == '=' {
+			p.pos++
+			if value, err = p.parseBareItem(); err != nil {
+				return nil, err
+			}
+		}
+
+		params = params.set(key, value)
+	}
+
+	return params, nil
+}
+
+func (p StructuredParams) set(key string, value interface{}) StructuredParams {
+	for i := range p {
+		if p[i].Key == key {
+			p[i].Value = value
+			return p
+		}
+	}
+
+	return append(p, StructuredParam{Key: key, Value: value})
+}
+
+func (p *sfParser) parseKey() (string, error) {
+	if c := p.peek(); !isLCAlpha(c) && c != '*' {
+		return "", p.errorf("invalid key")
+	}
+
+	start := p.pos
+	for c := p.peek(); isKeyChar(c); c = p.peek() {
+		p.pos++
+	}
+
+	return p.s[start:p.pos], nil
+}
+
+func (p *sfParser) parseBareItem() (interface{}, error) {
+	switch c := p.peek(); {
+	case c == '-' || isDigit(c):
+		return p.parseNumber()
+	case c == '"':
+		return p.parseString()
+	case c == '*' || isAlpha(c):
+		return p.parseToken(), nil
+	case c == ':':
+		return p.parseByteSequence()
+	case c == '?':
+		return p.parseBoolean()
+	default:
+		return nil, p.errorf("unexpected %q", c)
+	}
+}
+
+func (p *sfParser) parseNumber() (interface{}, error) {
+	start := p.pos
+	if p.peek() == '-' {
+		p.pos++
+	}
+
+	if !isDigit(p.peek()) {
+		return nil, p.errorf("expected a digit")
+	}
+
+	digits, dot := p.pos, -1 // the sign is not counted.
+	for !p.empty() {
+		c := p.peek()
+		if c == '.' && dot == -1 {
+			if p.pos-digits > 12 {
+				return nil, p.errorf("decimal integer part too long")
+			}
+			dot = p.pos
+		} else if !isDigit(c) {
+			break
+		}
+		p.pos++
+
+		if n := p.pos - digits; (dot == -1 && n > 15) || n > 16 {
+			return nil, p.errorf("number too long")
+		}
+	}
+
+	num := p.s[start:p.pos]
+	if dot == -1 {
+		return strconv.ParseInt(num, 10, 64)
+	}
+
+	if fraction := p.pos - dot - 1; fraction == 0 || fraction > 3 {
+		return nil, p.errorf("invalid decimal fraction")
+	}
+
+	return strconv.ParseFloat(num, 64)
+}
+
+func (p *sfParser) parseString() (string, error) {
+	var b strings.Builder
+	p.pos++ // "
+	for !p.empty() {
+		c := p.s[p.pos]
+		p.pos++
+		switch {
+		case c == '\\':
+			if next := p.peek(); next != '"' && next != '\\' {
+				return "", p.errorf("invalid string escape")
+			}
+			b.WriteByte(p.s[p.pos])
+			p.pos++
+		case c == '"':
+			return b.String(), nil
+		case c < 0x20 || c > 0x7e:
+			return "", p.errorf("invalid string character")
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return "", p.errorf("unterminated string")
+}
+
+func (p *sfParser) parseToken() StructuredToken {
+	start := p.pos
+	p.pos++
+	for c := p.peek(); isTChar(c) || c == ':' || c == '/'; c = p.peek() {
+		p.pos++
+	}
+
+	return StructuredToken(p.s[start:p.pos])
+}
+
+func (p *sfParser) parseByteSequence() ([]byte, error) {
+	p.pos++ // :
+	end := strings.IndexByte(p.s[p.pos:], ':')
+	if end == -1 {
+		return nil, p.errorf("unterminated byte sequence")
+	}
+
+	encoded := p.s[p.pos : p.pos+end]
+	p.pos += end + 1
+	b, err := base64.RawStdEncoding.DecodeString(strings.TrimRight(encoded, "="))
+	if err != nil {
+		return nil, p.errorf("invalid byte sequence")
+	}
+
+	return b, nil
+}
+
+func (p *sfParser) parseBoolean() (bool, error) {
+	p.pos++ // ?
+	switch p.peek() {
+	case '1':
+		p.pos++
+		return true, nil
+	case '0':
+		p.pos++
+		return false, nil
+	default:
+		return false, p.errorf("invalid boolean")
+	}
+}
+
+// serialization, see the RFC 8941, section 4.1.
+
+func writeStructuredMember(b *strings.Builder, member StructuredMember) error {
+	switch m := member.(type) {
+	case StructuredItem:
+		return writeStructuredItem(b, m)
+	case StructuredInnerList:
+		b.WriteByte('(')
+		for i, item := range m.Items {
+			if i > 0 {
+				b.WriteByte(' ')
+			}
+
+			if err := writeStructuredItem(b, item); err != nil {
+				return err
+			}
+		}
+		b.WriteByte(')')
+		return writeStructuredParams(b, m.Params)
+	default:
+		return fmt.Errorf("%w: unexpected member type of %T", ErrStructuredField, member)
+	}
+}
+
+func writeStructuredItem(b *strings.Builder, item StructuredItem) error {
+	if err := writeStructuredBareItem(b, item.Value); err != nil {
+		return err
+	}
+
+	return writeStructuredParams(b, item.Params)
+}
+
+func writeStructuredParams(b *strings.Builder, params StructuredParams) error {
+	for _, param := range params {
+		b.WriteByte(';')
+		if err := writeStructuredKey(b, param.Key); err != nil {
+			return err
+		}
+
+		if param.Value == true {
+			continue
+		}
+
+		b.WriteByte('=')
+		if err := writeStructuredBareItem(b, param.Value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeStructuredKey(b *strings.Builder, key string) error {
+	if key == "" || (!isLCAlpha(key[0]) && key[0] != '*') {
+		return fmt.Errorf("%w: invalid key %q", ErrStructuredField, key)
+	}
+
+	for i := 1; i < len(key); i++ {
+		if !isKeyChar(key[i]) {
+			return fmt.Errorf("%w: invalid key %q", ErrStructuredField, key)
+		}
+	}
+
+	b.WriteString(key)
+	return nil
+}
+
+func writeStructuredBareItem(b *strings.Builder, value interface{}) error {
+	switch v := value.(type) {
+	case int:
+		return writeStructuredBareItem(b, int64(v))
+	case int64:
+		if v > 999999999999999 || v < -999999999999999 {
+			return fmt.Errorf("%w: integer %d out of range", ErrStructuredField, v)
+		}
+		b.WriteString(strconv.FormatInt(v, 10))
+	case float64:
+		v = math.RoundToEven(v*1000) / 1000
+		if math.IsNaN(v) || math.Abs(v) >= 1e12 {
+			return fmt.Errorf("%w: decimal %v out of range", ErrStructuredField, v)
+		}
+
+		s := strconv.FormatFloat(v, 'f', -1, 64)
+		if !strings.Contains(s, ".") {
+			s += ".0"
+		}
+		b.WriteString(s)
+	case string:
+		b.WriteByte('"')
+		for i := 0; i < len(v); i++ {
+			c := v[i]
+			if c < 0x20 || c > 0x7e {
+				return fmt.Errorf("%w: invalid string character %q", ErrStructuredField, c)
+			}
+
+			if c == '"' || c == '\\' {
+				b.WriteByte('\\')
+			}
+			b.WriteByte(c)
+		}
+		b.WriteByte('"')
+	case StructuredToken:
+		if v == "" || (!isAlpha(v[0]) && v[0] != '*') {
+			return fmt.Errorf("%w: invalid token %q", ErrStructuredField, v)
+		}
+
+		for i := 1; i < len(v); i++ {
+			if c := v[i]; !isTChar(c) && c != ':' && c != '/' {
+				return fmt.Errorf("%w: invalid token %q", ErrStructuredField, v)
+			}
+		}
+		b.WriteString(string(v))
+	case []byte:
+		b.WriteByte(':')
+		b.WriteString(base64.StdEncoding.EncodeToString(v))
+		b.WriteByte(':')
+	case bool:
+		if v {
+			b.WriteString("?1")
+		} else {
+			b.WriteString("?0")
+		}
+	default:
+		return fmt.Errorf("%w: unexpected bare item type of %T", ErrStructuredField, value)
+	}
+
+	return nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isLCAlpha(c byte) bool {
+	return c >= 'a' && c <= 'z'
+}
+
+func isAlpha(c byte) bool {
+	return isLCAlpha(c) || (c >= 'A' && c <= 'Z')
+}
+
+func isKeyChar(c byte) bool {
+	return isLCAlpha(c) || isDigit(c) || c == '_' || c == '-' || c == '.' || c == '*'
+}
+
+// isTChar reports whether "c" is a token character of the RFC 7230.
+func isTChar(c byte) bool {
+	if isAlpha(c) || isDigit(c) {
+		return true
+	}
+
+	return strings.IndexByte("!#$%&'*+-.^_`|~", c) != -1
+}
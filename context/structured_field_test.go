@@ -0,0 +1,140 @@
+package context_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/httptest"
+)
+
+func TestParseStructuredField(t *testing.T) {
+	item := func(value interface{}, params ...context.StructuredParam) context.StructuredItem {
+		return context.StructuredItem{Value: value, Params: params}
+	}
+
+	items := []struct {
+		header     string
+		expected   context.StructuredItem
+		serialized string
+	}{
+		{"42", item(int64(42)), "42"},
+		{"-1.50", item(-1.5), "-1.5"},
+		{` "say \"hi\"" `, item(`say "hi"`), `"say \"hi\""`},
+		{"*foo/bar:baz", item(context.StructuredToken("*foo/bar:baz")), "*foo/bar:baz"},
+		{":aGVsbG8=:", item([]byte("hello")), ":aGVsbG8=:"},
+		{"?1;a;b=?0;c=text", item(true,
+			context.StructuredParam{Key: "a", Value: true},
+			context.StructuredParam{Key: "b", Value: false},
+			context.StructuredParam{Key: "c", Value: context.StructuredToken("text")},
+		), "?1;a;b=?0;c=text"},
+	}
+
+	for i, tt := range items {
+		got, err := context.ParseStructuredItem(tt.header)
+		if err != nil {
+			t.Fatalf("[%d] %v", i, err)
+		}
+
+		if !reflect.DeepEqual(got, tt.expected) {
+			t.Fatalf("[%d] expected: %#v but got: %#v", i, tt.expected, got)
+		}
+
+		if b, err := got.MarshalText(); err != nil || string(b) != tt.serialized {
+			t.Fatalf("[%d] expected serialized: %q but got: %q (%v)", i, tt.serialized, string(b), err)
+		}
+	}
+
+	list, err := context.ParseStructuredList(`sugar, tea;q=0.5 ,	("a" b);lvl=5, ()`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedList := context.StructuredList{
+		item(context.StructuredToken("sugar")),
+		item(context.StructuredToken("tea"), context.StructuredParam{Key: "q", Value: 0.5}),
+		context.StructuredInnerList{
+			Items:  []context.StructuredItem{item("a"), item(context.StructuredToken("b"))},
+			Params: context.StructuredParams{{Key: "lvl", Value: int64(5)}},
+		},
+		context.StructuredInnerList{},
+	}
+	if !reflect.DeepEqual(list, expectedList) {
+		t.Fatalf("expected: %#v but got: %#v", expectedList, list)
+	}
+	if b, err := list.MarshalText(); err != nil || string(b) != `sugar, tea;q=0.5, ("a" b);lvl=5, ()` {
+		t.Fatalf("unexpected serialized list: %q (%v)", string(b), err)
+	}
+
+	dict, err := context.ParseStructuredDictionary(`u=3, i, u=1, x;p=2`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if urgency, ok := dict.Get("u"); !ok || !reflect.DeepEqual(urgency, item(int64(1))) {
+		t.Fatalf("expected the overridden urgency but got: %#v", urgency)
+	}
+	if b, err := dict.MarshalText(); err != nil || string(b) != `u=1, i, x;p=2` {
+		t.Fatalf("unexpected serialized dictionary: %q (%v)", string(b), err)
+	}
+
+	invalid := []string{
+		"",                 // empty item.
+		"1234567890123456", // integer too long.
+		"1.2345",           // decimal fraction too long.
+		"1.",               // decimal without fraction.
+		`"unterminated`,    // string.
+		`"\a"`,             // invalid escape.
+		"?2",               // boolean.
+		":aGVsbG8=",        // byte sequence.
+		"a, b",             // an item can not be a list.
+		"Key=1",            // uppercase parameter key.
+	}
+	for i, header := range invalid {
+		if _, err := context.ParseStructuredItem(header); !errors.Is(err, context.ErrStructuredField) {
+			t.Fatalf("[%d] expected an invalid structured field error for %q but got: %v", i, header, err)
+		}
+	}
+
+	if _, err := context.ParseStructuredList("a, b,"); !errors.Is(err, context.ErrStructuredField) {
+		t.Fatalf("expected a trailing comma error but got: %v", err)
+	}
+
+	if _, err := (context.StructuredItem{Value: "é"}).MarshalText(); !errors.Is(err, context.ErrStructuredField) {
+		t.Fatalf("expected a non-ASCII string error but got: %v", err)
+	}
+}
+
+func TestContextStructuredHeaders(t *testing.T) {
+	app := iris.New()
+	app.Get("/", func(ctx iris.Context) {
+		mobile, err := ctx.GetStructuredHeaderItem("Sec-CH-UA-Mobile")
+		if err != nil {
+			ctx.StopWithError(iris.StatusBadRequest, err)
+			return
+		}
+
+		priority, err := ctx.GetStructuredHeaderDictionary("Priority")
+		if err != nil {
+			ctx.StopWithError(iris.StatusBadRequest, err)
+			return
+		}
+
+		urgency, _ := priority.Get("u")
+		err = ctx.SetStructuredHeader("Priority", context.StructuredDictionary{
+			{Key: "u", Value: urgency},
+			{Key: "i", Value: context.StructuredItem{Value: mobile.Value}},
+		})
+		if err != nil {
+			ctx.StopWithError(iris.StatusInternalServerError, err)
+		}
+	})
+
+	e := httptest.New(t, app)
+	e.GET("/").WithHeader("Sec-CH-UA-Mobile", "?1").WithHeader("Priority", "u=2").Expect().
+		Status(httptest.StatusOK).Header("Priority").Equal("u=2, i")
+	e.GET("/").WithHeader("Sec-CH-UA-Mobile", "?0").WithHeader("Priority", "u=5").Expect().
+		Status(httptest.StatusOK).Header("Priority").Equal("u=5, i=?0")
+	e.GET("/").WithHeader("Sec-CH-UA-Mobile", "1.2345").Expect().Status(httptest.StatusBadRequest)
+	e.GET("/").Expect().Status(httptest.StatusBadRequest)
+}
@@ -0,0 +1,106 @@
+package context
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ValidationError describes a field which failed the validation,
+// see `ValidationErrors` and `Context.StopWithReadError`.
+type ValidationError struct {
+	// Field is the struct field's name.
+	Field string `json:"field" xml:"field" yaml:"Field"`
+	// Namespace is the full path of the field, e.g. "User.Address.Street".
+	Namespace string `json:"namespace,omitempty" xml:"namespace,omitempty" yaml:"Namespace,omitempty"`
+	// Tag is the failed validation rule, e.g. "required" or "email".
+	Tag string `json:"tag,omitempty" xml:"tag,omitempty" yaml:"Tag,omitempty"`
+	// Param is the rule's parameter, e.g. "8" on "min=8".
+	Param string `json:"param,omitempty" xml:"param,omitempty" yaml:"Param,omitempty"`
+	// Value is the invalid value.
+	// It's never sent to the client, as it may be sensitive (e.g. a password).
+	Value interface{} `json:"-" xml:"-" yaml:"-"`
+	// Message is the human-readable description of the failure.
+	Message string `json:"message" xml:"message" yaml:"Message"`
+}
+
+// ValidationErrors is a list of field validation errors.
+// A custom `Validator` may return it directly,
+// the github.com/go-playground/validator errors are converted
+// automatically, see `AsValidationErrors`.
+type ValidationErrors []ValidationError
+
+// Error completes the error interface.
+func (errs ValidationErrors) Error() string {
+	msgs := make([]string, 0, len(errs))
+	for _, err := range errs {
+		msgs = append(msgs, err.Message)
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// fieldError is the method set of the github.com/go-playground/validator#FieldError
+// (the most commonly used validator), so its errors can be converted without importing it.
+type fieldError interface {
+	error
+	Field() string
+	Namespace() string
+	Tag() string
+	Param() string
+	Value() interface{}
+}
+
+// AsValidationErrors reports whether the "err" is a validation failure
+// and returns its structured field errors.
+// It supports the `ValidationErrors` and
+// any slice of `FieldError`-like values (e.g. the validator.ValidationErrors).
+func AsValidationErrors(err error) (ValidationErrors, bool) {
+	if err == nil {
+		return nil, false
+	}
+
+	var errs ValidationErrors
+	if errors.As(err, &errs) {
+		return errs, true
+	}
+
+	for ; err != nil; err = errors.Unwrap(err) {
+		v := reflect.ValueOf(err)
+		if v.Kind() != reflect.Slice || v.Len() == 0 {
+			continue
+		}
+
+		errs = make(ValidationErrors, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			fe, ok := v.Index(i).Interface().(fieldError)
+			if !ok {
+				break
+			}
+
+			errs = append(errs, ValidationError{
+				Field:     fe.Field(),
+				Namespace: fe.Namespace(),
+				Tag:       fe.Tag(),
+				Param:     fe.Param(),
+				Value:     fe.Value(),
+				Message:   fieldErrorMessage(fe),
+			})
+		}
+
+		if len(errs) == v.Len() {
+			return errs, true
+		}
+	}
+
+	return nil, false
+}
+
+func fieldErrorMessage(fe fieldError) string {
+	if fe.Param() != "" {
+		return fmt.Sprintf("%s failed on the '%s=%s' rule", fe.Namespace(), fe.Tag(), fe.Param())
+	}
+
+	return fmt.Sprintf("%s failed on the '%s' rule", fe.Namespace(), fe.Tag())
+}
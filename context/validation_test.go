@@ -0,0 +1,137 @@
+package context_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/httptest"
+
+	"github.com/iris-contrib/httpexpect/v2"
+)
+
+// fieldError completes the method set of the go-playground/validator#FieldError.
+type fieldError struct {
+	field, tag, param string
+	value             interface{}
+}
+
+func (e fieldError) Error() string      { return e.field + ": " + e.tag }
+func (e fieldError) Field() string      { return e.field }
+func (e fieldError) Namespace() string  { return "user." + e.field }
+func (e fieldError) Tag() string        { return e.tag }
+func (e fieldError) Param() string      { return e.param }
+func (e fieldError) Value() interface{} { return e.value }
+
+type fieldErrors []fieldError
+
+func (errs fieldErrors) Error() string { return "validation failed" }
+
+type testValidator struct{}
+
+func (testValidator) Struct(v interface{}) error {
+	u := v.(*testUser)
+	var errs fieldErrors
+	if u.Username == "" {
+		errs = append(errs, fieldError{field: "username", tag: "required"})
+	}
+	if u.Age < 18 {
+		errs = append(errs, fieldError{field: "age", tag: "min", param: "18", value: u.Age})
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+type testUser struct {
+	Username string `json:"username" xml:"username" form:"username"`
+	Age      int    `json:"age" xml:"age" form:"age"`
+}
+
+func TestReadBodyValidation(t *testing.T) {
+	app := iris.New()
+	app.Validator = testValidator{}
+	app.Post("/", func(ctx iris.Context) {
+		var u testUser
+		if err := ctx.ReadBody(&u); err != nil {
+			ctx.StopWithReadError(err)
+			return
+		}
+
+		ctx.Writef("%s:%d", u.Username, u.Age)
+	})
+
+	e := httptest.New(t, app)
+	e.POST("/").WithJSON(testUser{Username: "makis", Age: 27}).Expect().
+		Status(httptest.StatusOK).Body().Equal("makis:27")
+	e.POST("/").WithForm(map[string]interface{}{"username": "kataras", "age": 28}).Expect().
+		Status(httptest.StatusOK).Body().Equal("kataras:28")
+	e.POST("/").WithHeader("Content-Type", "application/xml").
+		WithBytes([]byte("<testUser><username>iris</username><age>18</age></testUser>")).Expect().
+		Status(httptest.StatusOK).Body().Equal("iris:18")
+
+	// invalid body.
+	e.POST("/").WithHeader("Content-Type", "application/json").WithBytes([]byte("{")).Expect().
+		Status(httptest.StatusBadRequest)
+
+	// validation errors.
+	r := e.POST("/").WithJSON(testUser{Age: 16}).Expect().Status(httptest.StatusUnprocessableEntity)
+	r.ContentType("application/problem+json")
+	errs := r.JSON(httpexpect.ContentOpts{MediaType: "application/problem+json"}).Object().Value("errors").Array()
+	errs.Length().Equal(2)
+	errs.Element(0).Object().ValueEqual("field", "username").ValueEqual("tag", "required").
+		ValueEqual("message", "user.username failed on the 'required' rule")
+	errs.Element(1).Object().ValueEqual("field", "age").ValueEqual("param", "18").NotContainsKey("value")
+}
+
+func TestReadBodyDefaultValidator(t *testing.T) {
+	type user struct {
+		Username string `json:"username" validate:"required"`
+		Age      int    `json:"age" validate:"min=18"`
+	}
+
+	app := iris.New()
+	app.Post("/", func(ctx iris.Context) {
+		ctx.SetMaxRequestBodySize(64)
+
+		var u user
+		if err := ctx.ReadBody(&u); err != nil {
+			ctx.StopWithReadError(err)
+			return
+		}
+
+		ctx.Writef("%s:%d", u.Username, u.Age)
+	})
+	app.Post("/json", func(ctx iris.Context) {
+		var u user
+		if err := ctx.ReadJSON(&u); err != nil {
+			ctx.StopWithReadError(err)
+			return
+		}
+
+		ctx.Writef("%s:%d", u.Username, u.Age)
+	})
+
+	e := httptest.New(t, app)
+	e.POST("/").WithJSON(user{Username: "makis", Age: 27}).Expect().
+		Status(httptest.StatusOK).Body().Equal("makis:27")
+	// the rest of the Read methods are not validated without an Application's Validator.
+	e.POST("/json").WithJSON(user{Age: 16}).Expect().
+		Status(httptest.StatusOK).Body().Equal(":16")
+
+	errs := e.POST("/").WithJSON(user{Age: 16}).Expect().Status(httptest.StatusUnprocessableEntity).
+		JSON(httpexpect.ContentOpts{MediaType: "application/problem+json"}).Object().Value("errors").Array()
+	errs.Length().Equal(2)
+	errs.Element(0).Object().ValueEqual("field", "Username").ValueEqual("tag", "required")
+	errs.Element(1).Object().ValueEqual("field", "Age").ValueEqual("tag", "min").ValueEqual("param", "18")
+
+	// body too large.
+	e.POST("/").WithJSON(user{Username: strings.Repeat("a", 128), Age: 27}).Expect().
+		Status(httptest.StatusRequestEntityTooLarge)
+	// unsupported content type.
+	e.POST("/").WithHeader("Content-Type", "application/x-protobuf").WithBytes([]byte("body")).Expect().
+		Status(httptest.StatusUnsupportedMediaType)
+}
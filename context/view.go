@@ -44,3 +44,65 @@ type ViewEngineFuncer interface {
 	// AddFunc should adds a function to the template's function map.
 	AddFunc(funcName string, funcBody interface{})
 }
+
+// ViewStreamChunkSize is the number of bytes which the `Context.ViewStream`
+// writes to the response writer before it flushes them to the client.
+var ViewStreamChunkSize = 32 * 1024
+
+// ViewStreamWriter is the writer which the `Context.ViewStream` renders a template to.
+// It writes directly to the (compressed) response writer and it flushes
+// the written data to the client every `ViewStreamChunkSize` bytes,
+// so large pages are not kept in memory as a whole.
+// View engines which buffer the rendered output by default (e.g. the django one)
+// should write directly to it instead and flush it on the flush boundaries,
+// e.g. the html engine flushes the layout's head before it renders the page.
+type ViewStreamWriter struct {
+	ctx       *Context
+	chunkSize int
+	pending   int   // written bytes since the last flush.
+	written   int64 // total written bytes.
+}
+
+var _ io.Writer = (*ViewStreamWriter)(nil)
+
+// Write writes "p" to the response writer, it flushes the response
+// when the pending bytes exceed the chunk size.
+func (w *ViewStreamWriter) Write(p []byte) (int, error) {
+	n, err := w.ctx.Write(p)
+	w.pending += n
+	w.written += int64(n)
+	if err == nil && w.pending >= w.chunkSize {
+		w.Flush()
+	}
+
+	return n, err
+}
+
+// Flush sends the pending data to the client.
+func (w *ViewStreamWriter) Flush() {
+	w.pending = 0
+	w.ctx.writer.Flush()
+}
+
+// Written returns the total number of the written bytes.
+func (w *ViewStreamWriter) Written() int64 {
+	return w.written
+}
+
+// Context returns the request's context which the template is rendered for.
+func (w *ViewStreamWriter) Context() *Context {
+	return w.ctx
+}
+
+const viewStreamWriterContextKey = "iris.view.stream"
+
+// GetViewStreamWriter returns the writer of the current `ViewStream` render
+// or nil if the template is not streamed, e.g. to flush the rendered
+// sections of a page, see the "flush" template function.
+func (ctx *Context) GetViewStreamWriter() *ViewStreamWriter {
+	if w, ok := ctx.values.Get(viewStreamWriterContextKey).(*ViewStreamWriter); ok {
+		return w
+	}
+
+	return nil
+}
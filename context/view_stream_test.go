@@ -0,0 +1,118 @@
+package context_test
+
+import (
+	"net/http"
+	stdhttptest "net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/httptest"
+	"github.com/kataras/iris/v12/view"
+)
+
+func TestViewStream(t *testing.T) {
+	defer func(chunkSize int) { context.ViewStreamChunkSize = chunkSize }(context.ViewStreamChunkSize)
+	context.ViewStreamChunkSize = 64
+
+	engine := view.Django("./", ".django")
+	if err := engine.ParseTemplate("report.django", []byte(`{% for row in rows %}<tr><td>{{ row }}</td></tr>{% endfor %}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	app := iris.New()
+	app.RegisterView(engine)
+	app.Get("/report", func(ctx iris.Context) {
+		rows := make([]int, 100)
+		for i := range rows {
+			rows[i] = i
+		}
+
+		if err := ctx.ViewStream("report.django", iris.Map{"rows": rows}); err != nil {
+			t.Error(err)
+		}
+	})
+	app.Get("/missing", func(ctx iris.Context) {
+		if err := ctx.ViewStream("missing.django"); err == nil {
+			t.Error("expected a not exist error")
+		}
+	})
+
+	var expected strings.Builder
+	for i := 0; i < 100; i++ {
+		expected.WriteString("<tr><td>" + strconv.Itoa(i) + "</td></tr>")
+	}
+
+	e := httptest.New(t, app)
+	e.GET("/report").Expect().Status(httptest.StatusOK).
+		ContentType("text/html", "utf-8").Body().Equal(expected.String())
+	e.GET("/missing").Expect().Status(httptest.StatusInternalServerError)
+}
+
+type flushRecorder struct {
+	*stdhttptest.ResponseRecorder
+	flushed []string
+}
+
+func (r *flushRecorder) Flush() {
+	r.flushed = append(r.flushed, r.Body.String())
+	r.ResponseRecorder.Flush()
+}
+
+func TestViewStreamFlushBoundaries(t *testing.T) {
+	engine := view.HTML("./", ".html").Layout("layout.html")
+
+	app := iris.New()
+	app.RegisterView(engine)
+	app.Get("/stream", func(ctx iris.Context) {
+		if err := ctx.ViewStream("page.html", iris.Map{"Title": "Report"}); err != nil {
+			t.Error(err)
+		}
+	})
+	app.Get("/buffered", func(ctx iris.Context) {
+		if err := ctx.View("page.html", iris.Map{"Title": "Report"}); err != nil {
+			t.Error(err)
+		}
+	})
+	if err := app.Build(); err != nil {
+		t.Fatal(err)
+	}
+	// parse after Build, which registers the "flush" template function.
+	if err := engine.ParseTemplate("layout.html", []byte(`<head>{{ .Title }}</head><body>{{ yield }}</body>`), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.ParseTemplate("page.html", []byte(`<header>{{ .Title }}</header>{{ flush }}<main>report</main>`), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "<head>Report</head><body><header>Report</header><main>report</main></body>"
+	expectedFlushes := []string{
+		"<head>Report</head><body>",
+		"<head>Report</head><body><header>Report</header>",
+	}
+
+	w := &flushRecorder{ResponseRecorder: stdhttptest.NewRecorder()}
+	app.ServeHTTP(w, stdhttptest.NewRequest(http.MethodGet, "/stream", nil))
+	if got := w.Body.String(); got != expected {
+		t.Fatalf("expected body:\n%s\nbut got:\n%s", expected, got)
+	}
+	if len(w.flushed) < len(expectedFlushes) {
+		t.Fatalf("expected at least %d flushes but got %d: %v", len(expectedFlushes), len(w.flushed), w.flushed)
+	}
+	for i, expectedFlush := range expectedFlushes {
+		if got := w.flushed[i]; got != expectedFlush {
+			t.Fatalf("[%d] expected flushed output:\n%s\nbut got:\n%s", i, expectedFlush, got)
+		}
+	}
+
+	w = &flushRecorder{ResponseRecorder: stdhttptest.NewRecorder()}
+	app.ServeHTTP(w, stdhttptest.NewRequest(http.MethodGet, "/buffered", nil))
+	if got := w.Body.String(); got != expected {
+		t.Fatalf("expected body:\n%s\nbut got:\n%s", expected, got)
+	}
+	if len(w.flushed) != 0 {
+		t.Fatalf("expected no flushes for a buffered view but got %v", w.flushed)
+	}
+}
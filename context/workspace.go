@@ -0,0 +1,288 @@
+package context
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	// WorkspaceDir is the directory which the workspaces are created into,
+	// see `Context.Workspace` and `NewWorkspace`.
+	//
+	// Defaults to empty, the `os.TempDir()`.
+	WorkspaceDir = ""
+	// WorkspaceQuota is the default maximum number of bytes
+	// which can be written to a workspace, see `Workspace.SetQuota` too.
+	// Zero or negative means no limit.
+	//
+	// Defaults to 100MB.
+	WorkspaceQuota int64 = 100 << 20
+)
+
+// ErrWorkspaceQuota is returned when a write would exceed the quota of a `Workspace`.
+var ErrWorkspaceQuota = errors.New("workspace: quota exceeded")
+
+// ErrWorkspaceRemoved is returned by the methods of a removed `Workspace`.
+var ErrWorkspaceRemoved = errors.New("workspace: removed")
+
+// Workspace is a managed temporary (scratch) directory,
+// e.g. to process uploaded files or to generate reports.
+// The files written through its methods count against its quota.
+// Files written directly to its `Dir` do not.
+//
+// A request's workspace is created by the `Context.Workspace` method
+// and it's removed automatically after the response is sent.
+// The sessions package provides session-bound workspaces too.
+type Workspace struct {
+	dir   string
+	quota int64
+	used  int64 // atomic.
+
+	mu      sync.Mutex
+	removed bool
+	// written holds the bytes counted for each file path (atomic),
+	// they are released when the file is truncated by `Create`.
+	written map[string]*int64
+}
+
+// NewWorkspace creates a new `Workspace` directory inside the `WorkspaceDir`,
+// the caller is responsible to call its `Remove` method.
+func NewWorkspace() (*Workspace, error) {
+	dir, err := ioutil.TempDir(WorkspaceDir, "iris-workspace-")
+	if err != nil {
+		return nil, err
+	}
+
+	return &Workspace{dir: dir, quota: WorkspaceQuota, written: make(map[string]*int64)}, nil
+}
+
+// Dir returns the absolute path of the workspace directory.
+func (w *Workspace) Dir() string {
+	return w.dir
+}
+
+// Path returns the path of the "name" file inside the workspace.
+// The "name" can not escape the workspace directory.
+func (w *Workspace) Path(name string) string {
+	return filepath.Join(w.dir, filepath.FromSlash(filepath.Clean("/"+filepath.ToSlash(name))))
+}
+
+// SetQuota sets the maximum number of bytes which can be written to the workspace,
+// zero or negative means no limit.
+func (w *Workspace) SetQuota(quota int64) {
+	atomic.StoreInt64(&w.quota, quota)
+}
+
+// Quota returns the maximum number of bytes which can be written to the workspace.
+func (w *Workspace) Quota() int64 {
+	return atomic.LoadInt64(&w.quota)
+}
+
+// Used returns the number of bytes written to the workspace.
+func (w *Workspace) Used() int64 {
+	return atomic.LoadInt64(&w.used)
+}
+
+// reserve counts "n" bytes against the quota.
+func (w *Workspace) reserve(n int64) error {
+	for {
+		used := atomic.LoadInt64(&w.used)
+		if quota := w.Quota(); quota > 0 && used+n > quota {
+			return ErrWorkspaceQuota
+		}
+
+		if atomic.CompareAndSwapInt64(&w.used, used, used+n) {
+			return nil
+		}
+	}
+}
+
+// Create creates or truncates the "name" file inside the workspace,
+// its parent directories are created too.
+// The writes of the returned file count against the workspace's quota,
+// the bytes of a truncated file are released.
+func (w *Workspace) Create(name string) (*WorkspaceFile, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.removed {
+		return nil, ErrWorkspaceRemoved
+	}
+
+	path := w.Path(name)
+	if err := os.MkdirAll(filepath.Dir(path), os.FileMode(0700)); err != nil {
+		return nil, err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	written, ok := w.written[path]
+	if ok {
+		atomic.AddInt64(&w.used, -atomic.SwapInt64(written, 0))
+	} else {
+		written = new(int64)
+		w.written[path] = written
+	}
+
+	return &WorkspaceFile{file: f, workspace: w, written: written}, nil
+}
+
+// WriteFile writes the "data" to the "name" file inside the workspace.
+func (w *Workspace) WriteFile(name string, data []byte) error {
+	f, err := w.Create(name)
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+// Remove removes the workspace directory and all of its files.
+// Any next call of `Create` fails with `ErrWorkspaceRemoved`.
+func (w *Workspace) Remove() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.removed {
+		return nil
+	}
+
+	w.removed = true
+	return os.RemoveAll(w.dir)
+}
+
+// WorkspaceFile is a file of a `Workspace`,
+// its writes fail with `ErrWorkspaceQuota` when they would exceed the workspace's quota.
+// It does not expose the `os.File` methods which could bypass the quota, e.g. `WriteAt` and `Truncate`.
+type WorkspaceFile struct {
+	file      *os.File
+	workspace *Workspace
+	written   *int64 // atomic, shared by the files of the same path.
+}
+
+// Name returns the name of the file as presented to `Workspace.Create`.
+func (f *WorkspaceFile) Name() string {
+	return f.file.Name()
+}
+
+// Read reads up to len(p) bytes from the file.
+func (f *WorkspaceFile) Read(p []byte) (int, error) {
+	return f.file.Read(p)
+}
+
+// ReadAt reads len(p) bytes from the file starting at byte offset "off".
+func (f *WorkspaceFile) ReadAt(p []byte, off int64) (int, error) {
+	return f.file.ReadAt(p, off)
+}
+
+// Seek sets the offset for the next Read or Write on the file.
+func (f *WorkspaceFile) Seek(offset int64, whence int) (int64, error) {
+	return f.file.Seek(offset, whence)
+}
+
+// Stat returns the FileInfo structure describing the file.
+func (f *WorkspaceFile) Stat() (os.FileInfo, error) {
+	return f.file.Stat()
+}
+
+// Sync commits the current contents of the file to stable storage.
+func (f *WorkspaceFile) Sync() error {
+	return f.file.Sync()
+}
+
+// Close closes the file.
+func (f *WorkspaceFile) Close() error {
+	return f.file.Close()
+}
+
+// Write writes "p" to the file, if it fits in the workspace's quota.
+func (f *WorkspaceFile) Write(p []byte) (int, error) {
+	if err := f.workspace.reserve(int64(len(p))); err != nil {
+		return 0, err
+	}
+
+	n, err := f.file.Write(p)
+	if n < len(p) {
+		atomic.AddInt64(&f.workspace.used, int64(n-len(p)))
+	}
+	atomic.AddInt64(f.written, int64(n))
+
+	return n, err
+}
+
+// WriteString writes the "s" to the file, if it fits in the workspace's quota.
+func (f *WorkspaceFile) WriteString(s string) (int, error) {
+	return f.Write([]byte(s))
+}
+
+// ReadFrom copies the "r" contents to the file, e.g. an uploaded file,
+// it fails with `ErrWorkspaceQuota` when the workspace's quota is exceeded.
+func (f *WorkspaceFile) ReadFrom(r io.Reader) (n int64, err error) {
+	buf := make([]byte, 32*1024)
+	for {
+		nr, rErr := r.Read(buf)
+		if nr > 0 {
+			nw, wErr := f.Write(buf[:nr])
+			n += int64(nw)
+			if wErr != nil {
+				return n, wErr
+			}
+		}
+
+		if rErr == io.EOF {
+			return n, nil
+		}
+
+		if rErr != nil {
+			return n, rErr
+		}
+	}
+}
+
+const workspaceContextKey = "iris.workspace"
+
+// Workspace returns the request's `Workspace`, a temporary directory
+// which is created on the first call and it's removed,
+// with all of its files, after the response is sent (see `Defer`).
+// Its quota defaults to the `WorkspaceQuota`.
+//
+// Example Code:
+//  ws, err := ctx.Workspace()
+//  [handle err...]
+//  f, err := ws.Create("upload.csv")
+//  [handle err...]
+//  _, err = io.Copy(f, file) // fails with ErrWorkspaceQuota on large files.
+//  f.Close()
+//  [process ws.Path("upload.csv")...]
+func (ctx *Context) Workspace() (*Workspace, error) {
+	if v := ctx.values.Get(workspaceContextKey); v != nil {
+		if w, ok := v.(*Workspace); ok {
+			return w, nil
+		}
+	}
+
+	w, err := NewWorkspace()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx.values.Set(workspaceContextKey, w)
+	ctx.Defer(func() {
+		w.Remove()
+	})
+
+	return w, nil
+}
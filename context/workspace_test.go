@@ -0,0 +1,95 @@
+package context_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/httptest"
+)
+
+func TestWorkspace(t *testing.T) {
+	defer func(dir string) { context.WorkspaceDir = dir }(context.WorkspaceDir)
+	context.WorkspaceDir = t.TempDir()
+
+	app := iris.New()
+	app.Post("/upload", func(ctx iris.Context) {
+		ws, err := ctx.Workspace()
+		if err != nil {
+			ctx.StopWithError(iris.StatusInternalServerError, err)
+			return
+		}
+		ws.SetQuota(10)
+
+		if same, _ := ctx.Workspace(); same != ws {
+			t.Fatalf("expected the same workspace per request")
+		}
+
+		if ws.Path("../../etc/passwd") != ws.Path("etc/passwd") {
+			t.Fatalf("expected the path to not escape the workspace")
+		}
+
+		f, err := ws.Create("uploads/data.txt")
+		if err != nil {
+			ctx.StopWithError(iris.StatusInternalServerError, err)
+			return
+		}
+		defer f.Close()
+
+		if _, err = f.ReadFrom(ctx.Request().Body); err != nil {
+			ctx.StopWithError(iris.StatusRequestEntityTooLarge, err)
+			return
+		}
+
+		ctx.WriteString(ws.Dir())
+	})
+
+	e := httptest.New(t, app)
+	dir := e.POST("/upload").WithText("12345").Expect().Status(httptest.StatusOK).Body().Raw()
+	if !strings.HasPrefix(dir, context.WorkspaceDir) {
+		t.Fatalf("expected workspace inside: %s but got: %s", context.WorkspaceDir, dir)
+	}
+
+	// removed after the response.
+	for i := 0; ; i++ {
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			break
+		}
+		if i == 100 {
+			t.Fatalf("expected workspace: %s to be removed", dir)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	e.POST("/upload").WithText("12345678901").Expect().Status(httptest.StatusRequestEntityTooLarge).
+		Body().Equal(context.ErrWorkspaceQuota.Error())
+}
+
+func TestWorkspaceTruncate(t *testing.T) {
+	defer func(dir string) { context.WorkspaceDir = dir }(context.WorkspaceDir)
+	context.WorkspaceDir = t.TempDir()
+
+	ws, err := context.NewWorkspace()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Remove()
+	ws.SetQuota(10)
+
+	for i := 0; i < 3; i++ {
+		if err = ws.WriteFile("report.csv", []byte("12345678")); err != nil {
+			t.Fatalf("[%d] %v", i, err)
+		}
+	}
+
+	if expected, got := int64(8), ws.Used(); expected != got {
+		t.Fatalf("expected used bytes: %d but got: %d", expected, got)
+	}
+
+	if err = ws.WriteFile("other.csv", []byte("123")); err != context.ErrWorkspaceQuota {
+		t.Fatalf("expected quota error but got: %v", err)
+	}
+}
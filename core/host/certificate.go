@@ -0,0 +1,154 @@
+package host
+
+import (
+	"crypto/tls"
+	"errors"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrCertificatesNotReloadable is returned by the `Supervisor.ReloadCertificates`
+// when the host does not serve through the `ListenAndServeTLS` with certificate files.
+var ErrCertificatesNotReloadable = errors.New("host: certificates are not reloadable")
+
+// CertificateReloader holds a TLS certificate which can be replaced at serve-time,
+// without a restart. Its `GetCertificate` method
+// can be used as the `tls.Config.GetCertificate` field.
+//
+// Short-lived certificates (e.g. by Vault or cert-manager) rotate
+// with a call to its `Reload` method or automatically by `Watch`.
+type CertificateReloader struct {
+	certFileOrContents string
+	keyFileOrContents  string
+
+	cert atomic.Value // *tls.Certificate.
+
+	mu      sync.Mutex
+	modTime time.Time // the latest modification time of the files.
+}
+
+// NewCertificateReloader loads and returns a new `CertificateReloader`.
+// The "certFileOrContents" and "keyFileOrContents" can be filenames
+// or raw contents of the certificate and the private key,
+// only the filenames are watched for changes.
+func NewCertificateReloader(certFileOrContents, keyFileOrContents string) (*CertificateReloader, error) {
+	r := &CertificateReloader{
+		certFileOrContents: certFileOrContents,
+		keyFileOrContents:  keyFileOrContents,
+	}
+
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Reload loads the certificate and the private key again and,
+// on success, atomically swaps the served certificate.
+// The current certificate is kept on failure.
+// New TLS handshakes use the new certificate,
+// established connections are not affected.
+func (r *CertificateReloader) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	modTime := r.lastModTime()
+	cert, err := loadCertificate(r.certFileOrContents, r.keyFileOrContents)
+	if err != nil {
+		return err
+	}
+
+	r.cert.Store(cert)
+	r.modTime = modTime
+	return nil
+}
+
+// GetCertificate returns the current certificate.
+// It completes the `tls.Config.GetCertificate` field.
+func (r *CertificateReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return r.cert.Load().(*tls.Certificate), nil
+}
+
+// Watch checks the certificate and key files for modifications every "interval"
+// and reloads them on change. Reload failures are reported to the "onError",
+// if not nil, the current certificate is kept and the next change is tried again.
+// It returns a function which stops the watcher.
+//
+// Certificates given as raw contents are not watched.
+func (r *CertificateReloader) Watch(interval time.Duration, onError func(error)) (stop func()) {
+	done := make(chan struct{})
+	var once sync.Once
+	stop = func() { once.Do(func() { close(done) }) }
+
+	if interval <= 0 || !fileExists(r.certFileOrContents) || !fileExists(r.keyFileOrContents) {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				r.mu.Lock()
+				changed := r.lastModTime().After(r.modTime)
+				r.mu.Unlock()
+				if !changed {
+					continue
+				}
+
+				if err := r.Reload(); err != nil && onError != nil {
+					onError(err)
+				}
+			}
+		}
+	}()
+
+	return
+}
+
+// lastModTime returns the latest modification time of the certificate and key files.
+func (r *CertificateReloader) lastModTime() (t time.Time) {
+	for _, filename := range []string{r.certFileOrContents, r.keyFileOrContents} {
+		if info, err := os.Stat(filename); err == nil && info.ModTime().After(t) {
+			t = info.ModTime()
+		}
+	}
+
+	return
+}
+
+// WatchCertificates returns a host `Configurator` which checks
+// the certificate and key files of a `Supervisor.ListenAndServeTLS`
+// for modifications every "interval" and reloads them on change.
+// The watcher stops on shutdown.
+//
+// Usage:
+//  app.Run(iris.TLS(":443", "server.crt", "server.key", host.WatchCertificates(time.Minute)))
+func WatchCertificates(interval time.Duration) Configurator {
+	return func(su *Supervisor) {
+		su.certWatchInterval = interval
+	}
+}
+
+// ReloadCertificates loads the certificate and the private key
+// of a `ListenAndServeTLS` again and swaps the served ones, without a restart.
+// It returns `ErrCertificatesNotReloadable` if the host was not started
+// through `ListenAndServeTLS` with a certificate and a key.
+func (su *Supervisor) ReloadCertificates() error {
+	su.mu.Lock()
+	r := su.certReloader
+	su.mu.Unlock()
+
+	if r == nil {
+		return ErrCertificatesNotReloadable
+	}
+
+	return r.Reload()
+}
@@ -0,0 +1,112 @@
+package host
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeTestCertificate(t *testing.T, dir string, serial int64, modTime time.Time) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certFile, keyFile := filepath.Join(dir, "server.crt"), filepath.Join(dir, "server.key")
+	if err = ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	os.Chtimes(certFile, modTime, modTime)
+	os.Chtimes(keyFile, modTime, modTime)
+	return certFile, keyFile
+}
+
+func servedSerial(t *testing.T, r *CertificateReloader) int64 {
+	t.Helper()
+
+	cert, _ := r.GetCertificate(nil)
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return leaf.SerialNumber.Int64()
+}
+
+func TestCertificateReloader(t *testing.T) {
+	dir, err := ioutil.TempDir("", "iris-certs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	now := time.Now()
+	certFile, keyFile := writeTestCertificate(t, dir, 1, now.Add(-time.Minute))
+
+	r, err := NewCertificateReloader(certFile, keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := int64(1), servedSerial(t, r); expected != got {
+		t.Fatalf("expected serial: %d but got: %d", expected, got)
+	}
+
+	writeTestCertificate(t, dir, 2, now)
+	if err = r.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := int64(2), servedSerial(t, r); expected != got {
+		t.Fatalf("expected serial: %d but got: %d", expected, got)
+	}
+
+	// Invalid files keep the current certificate.
+	ioutil.WriteFile(keyFile, []byte("invalid"), 0600)
+	if err = r.Reload(); err == nil {
+		t.Fatalf("expected an error on invalid key")
+	}
+	if expected, got := int64(2), servedSerial(t, r); expected != got {
+		t.Fatalf("expected serial: %d but got: %d", expected, got)
+	}
+
+	stop := r.Watch(10*time.Millisecond, nil)
+	defer stop()
+
+	writeTestCertificate(t, dir, 3, now.Add(time.Minute))
+	deadline := time.Now().Add(2 * time.Second)
+	for servedSerial(t, r) != 3 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the watcher to reload the certificate")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,104 @@
+package host
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/kataras/iris/v12/core/netutil"
+)
+
+// ReadyInfo describes a host which is ready to accept connections.
+// See `TaskHost.Ready` method.
+type ReadyInfo struct {
+	// Network is the listener's network, e.g. "tcp".
+	Network string `json:"network"`
+	// Addr is the actual bound address, e.g. "127.0.0.1:53421"
+	// even if the server's address was "127.0.0.1:0".
+	Addr string `json:"addr"`
+	// URL is the listening full url (scheme+host).
+	URL string `json:"url"`
+	// TLS reports whether the host serves HTTPS.
+	TLS bool `json:"tls"`
+	// PID is the process id.
+	PID int `json:"pid"`
+}
+
+// Ready returns the bound address information of the host.
+// Should be called on Serve, see `Supervisor.RegisterOnServe`.
+func (h TaskHost) Ready() ReadyInfo {
+	su := h.Supervisor
+	isTLS := su.autoTLS || su.manuallyTLS || netutil.IsTLS(su.Server)
+
+	info := ReadyInfo{
+		Network: "tcp",
+		Addr:    su.Server.Addr,
+		TLS:     isTLS,
+		PID:     os.Getpid(),
+	}
+
+	if addr := su.Addr(); addr != nil {
+		info.Network = addr.Network()
+		info.Addr = addr.String()
+	}
+
+	info.URL = netutil.ResolveURL(netutil.ResolveScheme(isTLS), info.Addr)
+	return info
+}
+
+// WriteStartupJSONOnServe is a task which writes a machine-readable (JSON)
+// startup summary of the host, its `ReadyInfo` plus the current time,
+// to the "w" instead of the human-format `WriteStartupLogOnServe` one.
+// This function should be registered on Serve.
+func WriteStartupJSONOnServe(w io.Writer) func(TaskHost) {
+	return func(h TaskHost) {
+		summary := struct {
+			Time    string `json:"time"`
+			Message string `json:"message"`
+			ReadyInfo
+		}{
+			Time:      time.Now().Format(time.RFC3339),
+			Message:   "ready",
+			ReadyInfo: h.Ready(),
+		}
+
+		b, err := json.Marshal(summary)
+		if err != nil {
+			return
+		}
+
+		_, _ = w.Write(append(b, '\n'))
+	}
+}
+
+// ErrNoNotifySocket is returned by `NotifySystemd`
+// when the NOTIFY_SOCKET environment variable is missing,
+// i.e. the process is not managed by a systemd Type=notify service.
+var ErrNoNotifySocket = errors.New("host: NOTIFY_SOCKET is missing")
+
+// NotifySystemd sends the "state" (e.g. "READY=1" or "STOPPING=1")
+// to the service manager, like the sd_notify(3) does.
+// It returns ErrNoNotifySocket when the NOTIFY_SOCKET environment variable is missing.
+func NotifySystemd(state string) error {
+	socket := os.Getenv("NOTIFY_SOCKET")
+	if socket == "" {
+		return ErrNoNotifySocket
+	}
+
+	if strings.HasPrefix(socket, "@") { // abstract namespace socket.
+		socket = "\x00" + socket[1:]
+	}
+
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	_, err = conn.Write([]byte(state))
+	return err
+}
@@ -0,0 +1,141 @@
+package host
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestReadyOnRandomPort(t *testing.T) {
+	srv := &http.Server{
+		Addr: "127.0.0.1:0",
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("ready"))
+		}),
+	}
+
+	su := New(srv)
+	output := new(syncBuffer)
+	su.RegisterOnServe(WriteStartupJSONOnServe(output))
+
+	ready := make(chan ReadyInfo, 1)
+	su.RegisterOnServe(func(h TaskHost) {
+		ready <- h.Ready()
+	})
+
+	go su.ListenAndServe()
+	defer su.Shutdown(context.Background())
+
+	var info ReadyInfo
+	select {
+	case info = <-ready:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for the ready callback")
+	}
+
+	if info.Network != "tcp" || strings.HasSuffix(info.Addr, ":0") || info.URL != "http://"+info.Addr || info.PID != os.Getpid() {
+		t.Fatalf("unexpected ready info: %#v", info)
+	}
+
+	if got := su.Addr().String(); got != info.Addr {
+		t.Fatalf("expected bound address: %s but got: %s", info.Addr, got)
+	}
+
+	newTester(t, info.URL, nil).GET("/").Expect().Status(http.StatusOK).Body().Equal("ready")
+
+	var summary struct {
+		Message string `json:"message"`
+		ReadyInfo
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for output.String() == "" && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if err := json.Unmarshal([]byte(output.String()), &summary); err != nil {
+		t.Fatalf("%v: %s", err, output.String())
+	}
+
+	if summary.Message != "ready" || summary.ReadyInfo != info {
+		t.Fatalf("unexpected startup summary: %s", output.String())
+	}
+}
+
+func TestReadyTLSConfig(t *testing.T) {
+	srv := &http.Server{
+		Addr: "127.0.0.1:8443",
+		TLSConfig: &tls.Config{
+			GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) { return nil, nil },
+		},
+	}
+
+	info := TaskHost{Supervisor: New(srv)}.Ready()
+	if !info.TLS || info.URL != "https://127.0.0.1:8443" {
+		t.Fatalf("expected a TLS ready info of the server's TLSConfig but got: %#v", info)
+	}
+}
+
+func TestNotifySystemd(t *testing.T) {
+	os.Unsetenv("NOTIFY_SOCKET")
+	if err := NotifySystemd("READY=1"); err != ErrNoNotifySocket {
+		t.Fatalf("expected ErrNoNotifySocket but got: %v", err)
+	}
+
+	dir, err := os.MkdirTemp("", "notify")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	socket := filepath.Join(dir, "notify.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		t.Skipf("unixgram sockets are not supported: %v", err)
+	}
+	defer conn.Close()
+
+	os.Setenv("NOTIFY_SOCKET", socket)
+	defer os.Unsetenv("NOTIFY_SOCKET")
+
+	if err = NotifySystemd("READY=1"); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := make([]byte, 64)
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := string(buf[:n]); got != "READY=1" {
+		t.Fatalf("expected READY=1 but got: %s", got)
+	}
+}
@@ -43,6 +43,10 @@ type Supervisor struct {
 
 	mu sync.Mutex
 
+	certReloader      *CertificateReloader // see `ReloadCertificates`.
+	certWatchInterval time.Duration        // see `WatchCertificates`.
+
+	addr    net.Addr // the bound address of the listener, see `Addr` method.
 	onServe []func(TaskHost)
 	// IgnoreErrors should contains the errors that should be ignored
 	// on both serve functions return statements and error handlers.
@@ -169,6 +173,22 @@ func (su *Supervisor) newListener() (net.Listener, error) {
 	return l, nil
 }
 
+func (su *Supervisor) setAddr(addr net.Addr) {
+	su.mu.Lock()
+	su.addr = addr
+	su.mu.Unlock()
+}
+
+// Addr returns the actual address the server's listener is bound to,
+// e.g. "127.0.0.1:53421" when the Server.Addr is "127.0.0.1:0".
+// Returns nil if the server is not listening yet.
+func (su *Supervisor) Addr() net.Addr {
+	su.mu.Lock()
+	addr := su.addr
+	su.mu.Unlock()
+	return addr
+}
+
 // RegisterOnError registers a function to call when errors occurred by the underline http server.
 func (su *Supervisor) RegisterOnError(cb func(error)) {
 	su.mu.Lock()
@@ -259,6 +279,7 @@ func (su *Supervisor) supervise(blockFunc func() error) error {
 // Serve always returns a non-nil error. After Shutdown or Close, the
 // returned error is http.ErrServerClosed.
 func (su *Supervisor) Serve(l net.Listener) error {
+	su.setAddr(l.Addr())
 	return su.supervise(func() error { return su.Server.Serve(l) })
 }
 
@@ -300,6 +321,9 @@ func loadCertificate(c, k string) (*tls.Certificate, error) {
 // matching private key for the server must be provided. If the certificate
 // is signed by a certificate authority, the certFile should be the concatenation
 // of the server's certificate, any intermediates, and the CA's certificate.
+//
+// The certificate can be replaced without a restart,
+// see `ReloadCertificates` and `WatchCertificates`.
 func (su *Supervisor) ListenAndServeTLS(certFileOrContents string, keyFileOrContents string) error {
 	var getCertificate func(*tls.ClientHelloInfo) (*tls.Certificate, error)
 
@@ -311,13 +335,20 @@ func (su *Supervisor) ListenAndServeTLS(certFileOrContents string, keyFileOrCont
 			return errors.New("empty certFileOrContents or keyFileOrContents and Server.TLSConfig")
 		}
 
-		cert, err := loadCertificate(certFileOrContents, keyFileOrContents)
+		reloader, err := NewCertificateReloader(certFileOrContents, keyFileOrContents)
 		if err != nil {
 			return err
 		}
 
-		getCertificate = func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
-			return cert, nil
+		su.mu.Lock()
+		su.certReloader = reloader
+		su.mu.Unlock()
+
+		getCertificate = reloader.GetCertificate
+
+		if su.certWatchInterval > 0 {
+			stop := reloader.Watch(su.certWatchInterval, su.notifyErr)
+			su.RegisterOnShutdown(stop)
 		}
 	}
 
@@ -476,6 +507,7 @@ func (su *Supervisor) runTLS(getCertificate func(*tls.ClientHelloInfo) (*tls.Cer
 		return err
 	}
 
+	su.setAddr(ln.Addr())
 	return su.supervise(func() error { return su.Server.ServeTLS(ln, "", "") })
 }
 
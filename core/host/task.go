@@ -11,6 +11,7 @@ import (
 	"io"
 	"net/http"
 	"runtime"
+	"strings"
 	"time"
 
 	"github.com/kataras/iris/v12/core/netutil"
@@ -26,6 +27,9 @@ func WriteStartupLogOnServe(w io.Writer) func(TaskHost) {
 		addr := h.Supervisor.FriendlyAddr
 		if addr == "" {
 			addr = h.Supervisor.Server.Addr
+			if bound := h.Supervisor.Addr(); bound != nil && strings.HasSuffix(addr, ":0") {
+				addr = bound.String() // random port.
+			}
 		}
 		listeningURI := netutil.ResolveURL(guessScheme, addr)
 		interruptkey := "CTRL"
@@ -63,6 +67,7 @@ func (h TaskHost) Serve() error {
 	if err != nil {
 		return err
 	}
+	h.Supervisor.setAddr(l.Addr())
 
 	// if http.serverclosed ignore the error, it will have this error
 	// from the previous close
@@ -63,3 +63,92 @@ func GetIPAddress(ipAddresses []string, privateRanges []IPRange) (string, bool)
 
 	return "", false
 }
+
+// ParseIPNets parses a list of CIDRs (e.g. "10.0.0.0/8")
+// or single IP addresses (e.g. "192.168.1.10") to IP networks.
+// Invalid entries are skipped and returned as the second output value.
+func ParseIPNets(cidrs []string) (nets []*net.IPNet, invalid []string) {
+	nets = make([]*net.IPNet, 0, len(cidrs))
+	for _, s := range cidrs {
+		s = strings.TrimSpace(s)
+		if !strings.Contains(s, "/") {
+			ip := net.ParseIP(s)
+			if ip == nil {
+				invalid = append(invalid, s)
+				continue
+			}
+
+			bits := 8 * net.IPv6len
+			if ip4 := ip.To4(); ip4 != nil {
+				ip, bits = ip4, 8*net.IPv4len
+			}
+
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, ipNet, err := net.ParseCIDR(s)
+		if err != nil {
+			invalid = append(invalid, s)
+			continue
+		}
+
+		nets = append(nets, ipNet)
+	}
+
+	return
+}
+
+// IPInNets reports whether the "ip" is part of any of the "nets".
+func IPInNets(ip net.IP, nets []*net.IPNet) bool {
+	if ip == nil {
+		return false
+	}
+
+	for _, n := range nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ParseForwarded returns the "for" addresses
+// of a "Forwarded" header value (RFC 7239), in order.
+// Quotes, IPv6 brackets and ports are removed.
+//
+// Example: `for=192.0.2.60;proto=http, for="[2001:db8:cafe::17]:4711"`
+// returns []string{"192.0.2.60", "2001:db8:cafe::17"}.
+func ParseForwarded(v string) []string {
+	var addrs []string
+	for _, element := range strings.Split(v, ",") {
+		for _, pair := range strings.Split(element, ";") {
+			pair = strings.TrimSpace(pair)
+			if len(pair) < 4 || !strings.EqualFold(pair[:4], "for=") {
+				continue
+			}
+
+			addrs = append(addrs, trimForwardedNode(pair[4:]))
+		}
+	}
+
+	return addrs
+}
+
+func trimForwardedNode(node string) string {
+	node = strings.Trim(node, `"`)
+	if strings.HasPrefix(node, "[") { // IPv6.
+		if end := strings.IndexByte(node, ']'); end > 0 {
+			return node[1:end]
+		}
+
+		return node
+	}
+
+	if host, _, err := net.SplitHostPort(node); err == nil {
+		return host
+	}
+
+	return node
+}
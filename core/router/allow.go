@@ -0,0 +1,146 @@
+package router
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/kataras/iris/v12/context"
+)
+
+// PathMethods holds the allowed HTTP methods of each registered path.
+// The key is the route's subdomain followed by its registered path template,
+// e.g. "/users/{id:int}" or "admin./settings".
+// The methods are sorted based on the `AllMethods` order.
+//
+// It's the single introspection source for the 405 "Allow" response header,
+// the automatic OPTIONS responses and documentation tools,
+// e.g. the path item operations of an OpenAPI exporter,
+// so they never disagree.
+//
+// Usage:
+//  pathMethods := router.NewPathMethods(app.GetRoutes(), true)
+//  for path, methods := range pathMethods { ... }
+type PathMethods map[string][]string
+
+// NewPathMethods returns the allowed methods of the given "routes".
+// Error and offline routes are skipped.
+// If "autoOptions" is true then the OPTIONS method is added to all paths,
+// as the router does when the `Configuration.FireMethodNotAllowed` is enabled.
+func NewPathMethods(routes []*Route, autoOptions bool) PathMethods {
+	pathMethods := make(PathMethods)
+
+	for _, r := range routes {
+		if r.StatusCode > 0 || r.Method == MethodNone {
+			continue
+		}
+
+		key := pathMethodsKey(r.Subdomain, r.tmpl.Src)
+		pathMethods[key] = appendMethod(pathMethods[key], r.Method)
+		if autoOptions {
+			pathMethods[key] = appendMethod(pathMethods[key], http.MethodOptions)
+		}
+	}
+
+	for _, methods := range pathMethods {
+		sortMethods(methods)
+	}
+
+	return pathMethods
+}
+
+// Methods returns the allowed methods of the given route.
+func (p PathMethods) Methods(route context.RouteReadOnly) []string {
+	return p[pathMethodsKey(route.Subdomain(), route.Tmpl().Src)]
+}
+
+// Allow returns the value of the "Allow" header for the given routes,
+// all of them should match the same request path.
+func (p PathMethods) Allow(routes ...context.RouteReadOnly) string {
+	if len(routes) == 1 {
+		return strings.Join(p.Methods(routes[0]), ", ")
+	}
+
+	var methods []string
+	for _, route := range routes {
+		for _, method := range p.Methods(route) {
+			methods = appendMethod(methods, method)
+		}
+	}
+
+	sortMethods(methods)
+	return strings.Join(methods, ", ")
+}
+
+func pathMethodsKey(subdomain, path string) string {
+	return subdomain + path
+}
+
+func appendMethod(methods []string, method string) []string {
+	for _, m := range methods {
+		if m == method {
+			return methods
+		}
+	}
+
+	return append(methods, method)
+}
+
+func methodIndex(method string) int {
+	for i, m := range AllMethods {
+		if m == method {
+			return i
+		}
+	}
+
+	return len(AllMethods) // custom methods go last.
+}
+
+func sortMethods(methods []string) {
+	sort.SliceStable(methods, func(i, j int) bool {
+		return methodIndex(methods[i]) < methodIndex(methods[j])
+	})
+}
+
+// verifyPathMethods reports the static paths which the router's trees
+// resolve to a different set of methods than the "pathMethods" ones.
+func (h *routerHandler) verifyPathMethods(routes []*Route) (mismatches []string) {
+	checked := make(map[string]struct{})
+
+	for _, r := range routes {
+		if r.StatusCode > 0 || r.Method == MethodNone || !r.IsStatic() {
+			continue
+		}
+
+		key := pathMethodsKey(r.Subdomain, r.tmpl.Src)
+		if _, ok := checked[key]; ok {
+			continue
+		}
+		checked[key] = struct{}{}
+
+		var found []string
+		for _, t := range h.trees {
+			if t.subdomain != r.Subdomain {
+				continue
+			}
+
+			n := t.search(r.Path, new(context.RequestParams))
+			if n == nil || n.Route == nil || pathMethodsKey(n.Route.Subdomain(), n.Route.Tmpl().Src) != key {
+				continue
+			}
+
+			found = appendMethod(found, t.method)
+		}
+
+		if h.config != nil && h.config.GetFireMethodNotAllowed() {
+			found = appendMethod(found, http.MethodOptions)
+		}
+		sortMethods(found)
+
+		if expected := strings.Join(h.pathMethods[key], ", "); strings.Join(found, ", ") != expected {
+			mismatches = append(mismatches, key+": router ["+strings.Join(found, ", ")+"] vs allowed ["+expected+"]")
+		}
+	}
+
+	return
+}
@@ -0,0 +1,50 @@
+package router_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/core/router"
+	"github.com/kataras/iris/v12/httptest"
+)
+
+func TestAllowAndAutomaticOptions(t *testing.T) {
+	app := iris.New()
+	app.Configure(iris.WithFireMethodNotAllowed)
+
+	app.Get("/users", h)
+	app.Post("/users", h)
+	app.Delete("/users/{id:int}", h)
+	app.Put("/users/{id:int}", h)
+	app.Patch("/users/{name:string}", h)
+	app.Options("/custom", func(ctx iris.Context) {
+		ctx.Header("Allow", "OPTIONS")
+		ctx.StatusCode(iris.StatusOK)
+	})
+
+	e := httptest.New(t, app)
+
+	e.PUT("/users").Expect().Status(iris.StatusMethodNotAllowed).
+		Header("Allow").Equal("GET, POST, OPTIONS")
+	e.OPTIONS("/users").Expect().Status(iris.StatusNoContent).
+		Header("Allow").Equal("GET, POST, OPTIONS")
+	// Different path templates matching the same request path.
+	e.GET("/users/42").Expect().Status(iris.StatusMethodNotAllowed).
+		Header("Allow").Equal("PATCH, PUT, DELETE, OPTIONS")
+	// Registered OPTIONS routes are not overridden.
+	e.OPTIONS("/custom").Expect().Status(iris.StatusOK).
+		Header("Allow").Equal("OPTIONS")
+	e.OPTIONS("/notfound").Expect().Status(iris.StatusNotFound)
+
+	pathMethods := router.NewPathMethods(app.GetRoutes(), true)
+	expected := router.PathMethods{
+		"/users":               {"GET", "POST", "OPTIONS"},
+		"/users/{id:int}":      {"PUT", "DELETE", "OPTIONS"},
+		"/users/{name:string}": {"PATCH", "OPTIONS"},
+		"/custom":              {"OPTIONS"},
+	}
+	if !reflect.DeepEqual(pathMethods, expected) {
+		t.Fatalf("expected path methods:\n%v\nbut got:\n%v", expected, pathMethods)
+	}
+}
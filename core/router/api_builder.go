@@ -44,6 +44,8 @@ var AllMethods = []string{
 type repository struct {
 	routes []*Route
 	paths  map[string]*Route // only the fullname path part, required at CreateRoutes for registering index page.
+	// duplicates keeps the routes replaced by the default `RouteOverride` rule, see `conflicts`.
+	duplicates []RouteConflict
 }
 
 func (repo *repository) get(routeName string) *Route {
@@ -115,6 +117,7 @@ func (repo *repository) register(route *Route, rule RouteRegisterRule) (*Route,
 			} else {
 				// replace existing with the latest one, the default behavior.
 				repo.routes = append(repo.routes[:i], repo.routes[i+1:]...)
+				repo.duplicates = append(repo.duplicates, RouteConflict{Type: RouteDuplicate, Route: route, Shadowed: r})
 			}
 
 			continue
@@ -251,6 +254,13 @@ type APIBuilder struct {
 	handlerExecutionRules ExecutionRules
 	// the per-party (and its children) route registration rule, see `SetRegisterRule`.
 	routeRegisterRule RouteRegisterRule
+	// the per-party (and its children) feature flags, see `PartyIf`.
+	// If any of those reports false then the routes are not registered.
+	featureFlags []FeatureFlag
+	// the per-party (and its children) allowed request content types, see `ConsumesOnly`.
+	consumes []string
+	// the per-party (and its children) security requirements, see `Secure`.
+	security []context.SecurityRequirement
 
 	// routerFilterHandlers holds a reference
 	// of the handlers used by the current and its parent Party's registered
@@ -273,6 +283,16 @@ type APIBuilder struct {
 	// It defaults to the internal, simple, "defaultPartyMatcher".
 	// It applies when "routerFilters" are used.
 	partyMatcher PartyMatcherFunc
+	// problemErrors is set on the root APIBuilder through `UseProblemErrors`.
+	problemErrors *context.ProblemErrors
+	// namedMiddleware field is shared across Parties,
+	// see `RegisterMiddleware` and `UseNamed`.
+	namedMiddleware map[string]context.Handlers
+	// viewEngines field is shared across Parties,
+	// see `RegisterView` and `GetViewEngines`.
+	viewEngines *[]context.ViewEngine
+	// assets field is shared across Parties too, see `HandleAssets`.
+	assets *[]*Assets
 }
 
 var (
@@ -285,14 +305,17 @@ var (
 // which is responsible to build the API and the router handler.
 func NewAPIBuilder(logger *golog.Logger) *APIBuilder {
 	return &APIBuilder{
-		logger:        logger,
-		parent:        nil,
-		macros:        macro.Defaults,
-		relativePath:  "/",
-		routes:        new(repository),
-		apiBuilderDI:  &APIContainer{Container: hero.New().WithLogger(logger)},
-		routerFilters: make(map[Party]*Filter),
-		partyMatcher:  defaultPartyMatcher,
+		logger:          logger,
+		parent:          nil,
+		macros:          macro.Defaults,
+		relativePath:    "/",
+		routes:          new(repository),
+		apiBuilderDI:    &APIContainer{Container: hero.New().WithLogger(logger)},
+		routerFilters:   make(map[Party]*Filter),
+		partyMatcher:    defaultPartyMatcher,
+		namedMiddleware: make(map[string]context.Handlers),
+		viewEngines:     new([]context.ViewEngine),
+		assets:          new([]*Assets),
 	}
 }
 
@@ -339,6 +362,20 @@ func (api *APIBuilder) ConfigureContainer(builder ...func(*APIContainer)) *APICo
 	return api.apiBuilderDI
 }
 
+// Container returns the Dependency Injection container of this Party.
+// It is a child of the parent Party's container:
+// it inherits all the parent's dependencies, including the ones registered later on,
+// but any dependency registered here overrides the parent's one of the same type
+// for this Party and its children only.
+//
+// Usage:
+//  app.RegisterDependency(defaultPermissions)
+//  admin := app.Party("/admin")
+//  admin.Container().Register(elevatedPermissions)
+func (api *APIBuilder) Container() *hero.Container {
+	return api.ConfigureContainer().Container
+}
+
 // RegisterDependency calls the `ConfigureContainer.RegisterDependency` method
 // with the provided value(s). See `HandleFunc` and `PartyConfigure` methods too.
 func (api *APIBuilder) RegisterDependency(dependencies ...interface{}) {
@@ -512,6 +549,32 @@ func (api *APIBuilder) SetRegisterRule(rule RouteRegisterRule) Party {
 	return api
 }
 
+// ConsumesOnly sets the default allowed request body content types
+// for the routes registered after this call, on this Party and its children.
+// See `Route.ConsumesOnly` for more.
+//
+// Usage:
+//  api := app.Party("/api")
+//  api.ConsumesOnly("application/json", "+json")
+//  api.Post("/users", createUser) // 415 on any other content type.
+func (api *APIBuilder) ConsumesOnly(contentTypes ...string) Party {
+	api.consumes = contentTypes
+	return api
+}
+
+// Secure sets the default security requirements
+// for the routes registered after this call, on this Party and its children.
+// See `Route.Secure` for more.
+//
+// Usage:
+//  api := app.Party("/api", verifyJWT)
+//  api.Secure(iris.SecurityRequirement{Scheme: iris.SecurityBearer, Realm: "api"})
+//  api.Get("/orders", listOrders) // 401 responses send: WWW-Authenticate: Bearer realm="api".
+func (api *APIBuilder) Secure(requirements ...context.SecurityRequirement) Party {
+	api.security = requirements
+	return api
+}
+
 // Handle registers a route to this Party.
 // if empty method is passed then handler(s) are being registered to all methods, same as .Any.
 //
@@ -520,6 +583,49 @@ func (api *APIBuilder) Handle(method string, relativePath string, handlers ...co
 	return api.handle(0, method, relativePath, handlers...)
 }
 
+// HandleIf like `Handle` but it registers the route
+// only if the given "flag" reports true.
+// If the feature is disabled the route is created but it is not part of the route table,
+// so it is unreachable and hidden from any route introspection (e.g. `GetRoutes`).
+//
+// Usage:
+//  app.HandleIf(router.FeatureEnv("IRIS_EXPERIMENTAL"), "GET", "/beta", betaHandler)
+//
+// See `PartyIf` to enable or disable a whole group of routes.
+func (api *APIBuilder) HandleIf(flag FeatureFlag, method string, relativePath string, handlers ...context.Handler) *Route {
+	if flag != nil && !flag() {
+		return api.skipRoute(method, relativePath, handlers...)
+	}
+
+	return api.Handle(method, relativePath, handlers...)
+}
+
+// featureEnabled reports whether all the Party's feature flags report true.
+func (api *APIBuilder) featureEnabled() bool {
+	for _, flag := range api.featureFlags {
+		if !flag() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// skipRoute creates but does not register a route,
+// used when the route's feature is disabled.
+func (api *APIBuilder) skipRoute(method string, relativePath string, handlers ...context.Handler) *Route {
+	routes := api.createRoutes(0, []string{method}, relativePath, handlers...)
+
+	var route *Route // the last one is returned.
+	for _, route = range routes {
+		if route != nil {
+			api.logger.Debugf("API: %s excluded by feature flag", route.String())
+		}
+	}
+
+	return route
+}
+
 // handle registers a full route to this Party.
 // Use Handle or Get, Post, Put, Delete and et.c. instead.
 func (api *APIBuilder) handle(errorCode int, method string, relativePath string, handlers ...context.Handler) *Route {
@@ -532,6 +638,11 @@ func (api *APIBuilder) handle(errorCode int, method string, relativePath string,
 			continue
 		}
 
+		if !api.featureEnabled() {
+			api.logger.Debugf("API: %s excluded by feature flag", route.String())
+			continue
+		}
+
 		// global
 
 		route.topLink = api.routes.getRelative(route)
@@ -641,6 +752,10 @@ func (api *APIBuilder) HandleDir(requestPath string, fsOrDir interface{}, opts .
 
 	routes = append(routes, api.CreateRoutes([]string{http.MethodGet, http.MethodHead}, requestPath, h)...)
 
+	if !api.featureEnabled() {
+		return routes
+	}
+
 	for _, route := range routes {
 		if route.Method == http.MethodHead {
 		} else {
@@ -764,6 +879,7 @@ func (api *APIBuilder) createRoutes(errorCode int, methods []string, relativePat
 
 		route.MainHandlerName = mainHandlerName
 		route.MainHandlerIndex = mainHandlerIndex
+		route.doneHandlersLen = len(doneHandlers)
 
 		// The main handler source, could be the same as the register's if anonymous.
 		route.SourceFileName = mainHandlerFileName
@@ -773,6 +889,14 @@ func (api *APIBuilder) createRoutes(errorCode int, methods []string, relativePat
 		// route.Use(api.beginGlobalHandlers...)
 		// route.Done(api.doneGlobalHandlers...)
 
+		if len(api.consumes) > 0 && errorCode == 0 {
+			route.ConsumesOnly(api.consumes...)
+		}
+
+		if len(api.security) > 0 && errorCode == 0 {
+			route.Secure(api.security...)
+		}
+
 		route.NoLog = api.routesNoLog
 		routes[i] = route
 	}
@@ -866,20 +990,46 @@ func (api *APIBuilder) Party(relativePath string, handlers ...context.Handler) P
 		routerFilters:         api.routerFilters,
 		routerFilterHandlers:  api.routerFilterHandlers,
 		partyMatcher:          api.partyMatcher,
+		namedMiddleware:       api.namedMiddleware,
+		viewEngines:           api.viewEngines,
+		assets:                api.assets,
 		relativePath:          fullpath,
 		allowMethods:          allowMethods,
 		handlerExecutionRules: api.handlerExecutionRules,
 		routeRegisterRule:     api.routeRegisterRule,
+		featureFlags:          api.featureFlags[0:len(api.featureFlags):len(api.featureFlags)],
+		consumes:              api.consumes,
+		security:              api.security,
 		apiBuilderDI: &APIContainer{
-			// attach a new Container with correct dynamic path parameter start index for input arguments
+			// attach a new child Container with correct dynamic path parameter start index for input arguments
 			// based on the fullpath.
-			Container: api.apiBuilderDI.Container.Clone(),
+			Container: api.apiBuilderDI.Container.Child(),
 		},
 	}
 
 	return childAPI
 }
 
+// PartyIf like `Party` registers a new child Party
+// but its routes, and its children's ones, are registered
+// only if the given "flag" reports true.
+// That way enterprise-only or experimental endpoints are excluded
+// from the route table (and so from any route documentation tool)
+// instead of being just guarded by a middleware.
+//
+// Usage:
+//  beta := app.PartyIf(router.FeatureEnv("IRIS_BETA"), "/beta")
+//  beta.Get("/", betaIndex) // registered only when IRIS_BETA=true.
+func (api *APIBuilder) PartyIf(flag FeatureFlag, relativePath string, middleware ...context.Handler) Party {
+	p := api.Party(relativePath, middleware...)
+	if flag != nil {
+		child := p.(*APIBuilder)
+		child.featureFlags = append(child.featureFlags, flag)
+	}
+
+	return p
+}
+
 // PartyFunc same as `Party`, groups routes that share a base path or/and same handlers.
 // However this function accepts a function that receives this created Party instead.
 // Returns the Party in order the caller to be able to use this created Party to continue the
@@ -1226,6 +1376,38 @@ func (api *APIBuilder) GetDefaultErrorMiddleware() context.Handlers {
 	return api.middlewareErrorCode
 }
 
+// UseProblemErrors translates, application-wide, the error responses to RFC 7807 problem documents:
+// the responses of the error code handlers (see `OnErrorCode`) which do not write a body,
+// the default error handler's ones and the recovered panics (see the recover middleware).
+// The "opts" can customize the problem's type URI, instance and extension members.
+// Error handlers which write a body are sent as they are.
+//
+// Usage:
+//  app.UseProblemErrors(iris.ProblemErrors{TypeBaseURI: "/problems/"})
+func (api *APIBuilder) UseProblemErrors(opts ...context.ProblemErrors) {
+	options := new(context.ProblemErrors)
+	if len(opts) > 0 {
+		*options = opts[0]
+	}
+
+	root := api
+	for root.parent != nil {
+		root = root.parent
+	}
+
+	root.problemErrors = options
+}
+
+// GetProblemErrors returns the options registered through `UseProblemErrors` or nil.
+func (api *APIBuilder) GetProblemErrors() *context.ProblemErrors {
+	root := api
+	for root.parent != nil {
+		root = root.parent
+	}
+
+	return root.problemErrors
+}
+
 // UseError upserts one or more handlers that will be fired,
 // as middleware, before any error handler registered through `On(Any)ErrorCode`.
 // See `OnErrorCode` too.
@@ -1588,18 +1770,24 @@ func (api *APIBuilder) OnAnyErrorCode(handlers ...context.Handler) (routes []*Ro
 	return
 }
 
-// RegisterView registers and loads a view engine middleware for this group of routes.
-// It overrides any of the application's root registered view engines.
+// RegisterView registers a view engine middleware for this group of routes.
+// It overrides any of the application's root registered view engines
+// and the template layout set by a parent Party, use `Layout` after `RegisterView`
+// to set a layout of this engine for this group of routes, e.g.
+//  admin := app.Party("/admin")
+//  admin.RegisterView(iris.Django("./admin_views", ".html"))
+//  admin.Layout("layouts/admin.html")
+//
+// The engine is loaded on `Application.Build`, after the application's
+// shared template functions (see `Application.AddViewFunc`) are added to it.
 // To register a view engine per handler chain see the `Context.ViewEngine` instead.
 // Read `Configuration.ViewEngineContextKey` documentation for more.
 func (api *APIBuilder) RegisterView(viewEngine context.ViewEngine) {
-	if err := viewEngine.Load(); err != nil {
-		api.logger.Error(err)
-		return
-	}
+	*api.viewEngines = append(*api.viewEngines, viewEngine)
 
 	handler := func(ctx *context.Context) {
 		ctx.ViewEngine(viewEngine)
+		ctx.ViewLayout("") // the parent's layout belongs to another engine.
 		ctx.Next()
 	}
 	api.Use(handler)
@@ -1608,6 +1796,12 @@ func (api *APIBuilder) RegisterView(viewEngine context.ViewEngine) {
 	// to keep the iris.Application a compatible Party.
 }
 
+// GetViewEngines returns the view engines registered
+// through the `RegisterView` method of all Parties, in order.
+func (api *APIBuilder) GetViewEngines() []context.ViewEngine {
+	return *api.viewEngines
+}
+
 // FallbackView registers one or more fallback views for a template or a template layout.
 // Usage:
 //  FallbackView(iris.FallbackView("fallback.html"))
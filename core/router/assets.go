@@ -0,0 +1,281 @@
+package router
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kataras/iris/v12/context"
+)
+
+// AssetsOptions holds the optional settings of the `Party.HandleAssets` method.
+type AssetsOptions struct {
+	// Manifest is the name of a JSON manifest file, inside the file system,
+	// which maps the asset names to their fingerprinted file names,
+	// e.g. {"app.js": "app.3f9c1b.js"}, as generated by the frontend bundlers.
+	//
+	// Defaults to empty, the files are hashed on startup instead.
+	Manifest string
+	// HashLength is the length of the content hash
+	// appended to the file names when there is no manifest.
+	//
+	// Defaults to 8.
+	HashLength int
+	// MaxAge is the max-age of the Cache-Control header of the fingerprinted files,
+	// they never change so they can be cached forever.
+	//
+	// Defaults to 365 days.
+	MaxAge time.Duration
+}
+
+// Assets holds the fingerprinted file names of a static file system,
+// a new file name for each version of a file, so the files can be cached forever
+// by the clients and the proxies.
+// See the `Party.HandleAssets` method and the "asset" template function.
+type Assets struct {
+	prefix       string
+	fs           http.FileSystem
+	cacheControl string
+
+	paths map[string]string // asset name: fingerprinted file name.
+	files map[string]string // fingerprinted file name: file name.
+}
+
+// HandleAssets registers GET and HEAD routes which serve the files of the
+// given file system (physical or embedded) under fingerprinted file names,
+// e.g. "/assets/app.3f9c1b2a.js" for the "app.js" file,
+// with far-future cache headers.
+// The fingerprinted names are read from a manifest (see `AssetsOptions.Manifest`)
+// or computed by hashing the files on startup.
+//
+// The returned Assets resolve the request path of an asset name
+// and the builtin "asset" template function resolves it on all view engines:
+//  <script src="{{ asset "app.js" }}"></script>
+//
+// Usage:
+//  app.HandleAssets("/assets", "./public", iris.AssetsOptions{})
+func (api *APIBuilder) HandleAssets(requestPath string, fsOrDir interface{}, opts ...AssetsOptions) *Assets {
+	var options AssetsOptions
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+
+	var fs http.FileSystem
+	switch v := fsOrDir.(type) {
+	case string:
+		fs = http.Dir(v)
+	case http.FileSystem:
+		fs = v
+	default:
+		panic(fmt.Errorf(`unexpected "fsOrDir" argument type of %T (string or http.FileSystem)`, v))
+	}
+
+	_, fullpath := splitSubdomainAndPath(joinPath(api.relativePath, requestPath))
+	assets, err := NewAssets(fullpath, fs, options)
+	if err != nil {
+		// serve the files under their names, without fingerprints.
+		api.logger.Errorf("assets: %s: %v", fullpath, err)
+	}
+
+	requestPath = joinPath(requestPath, WildcardFileParam())
+	api.Handle(http.MethodGet, requestPath, assets.Handler)
+	api.Handle(http.MethodHead, requestPath, assets.Handler)
+
+	*api.assets = append(*api.assets, assets)
+	return assets
+}
+
+// GetAssets returns the Assets registered through `HandleAssets`
+// by this and the rest of the Parties.
+func (api *APIBuilder) GetAssets() []*Assets {
+	return *api.assets
+}
+
+// NewAssets returns the Assets of the "fs" file system, served under the "requestPath".
+// It returns a non-nil Assets, which resolve the files without fingerprints,
+// on a manifest or a file system error.
+// Use the `Party.HandleAssets` method to register their routes too.
+func NewAssets(requestPath string, fs http.FileSystem, opts AssetsOptions) (*Assets, error) {
+	if opts.HashLength <= 0 {
+		opts.HashLength = 8
+	}
+
+	if opts.MaxAge <= 0 {
+		opts.MaxAge = 365 * 24 * time.Hour
+	}
+
+	a := &Assets{
+		prefix:       strings.TrimSuffix(requestPath, "/"),
+		fs:           fs,
+		cacheControl: "public, max-age=" + strconv.FormatInt(int64(opts.MaxAge/time.Second), 10) + ", immutable",
+		paths:        make(map[string]string),
+		files:        make(map[string]string),
+	}
+
+	var err error
+	if opts.Manifest != "" {
+		err = a.readManifest(opts.Manifest)
+	} else {
+		err = a.hash("/", opts.HashLength)
+	}
+
+	return a, err
+}
+
+func (a *Assets) add(name, fingerprinted string) {
+	a.paths[name] = fingerprinted
+	a.files[fingerprinted] = name
+}
+
+func (a *Assets) readManifest(name string) error {
+	f, err := a.fs.Open(path.Join("/", name))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var manifest map[string]string
+	if err = json.NewDecoder(f).Decode(&manifest); err != nil {
+		return fmt.Errorf("manifest: %s: %w", name, err)
+	}
+
+	for name, fingerprinted := range manifest {
+		// the values may contain the request path, e.g. "/assets/app.3f9c1b.js".
+		fingerprinted = strings.TrimPrefix(strings.TrimPrefix(fingerprinted, a.prefix), "/")
+		// the fingerprinted file exists, the original may not.
+		a.paths[strings.TrimPrefix(name, "/")] = fingerprinted
+		a.files[fingerprinted] = fingerprinted
+	}
+
+	return nil
+}
+
+func (a *Assets) hash(dir string, hashLength int) error {
+	d, err := a.fs.Open(dir)
+	if err != nil {
+		return err
+	}
+
+	infos, err := d.Readdir(-1)
+	d.Close()
+	if err != nil {
+		return err
+	}
+
+	for _, info := range infos {
+		if strings.HasPrefix(info.Name(), ".") {
+			continue
+		}
+
+		name := path.Join(dir, info.Name())
+		if info.IsDir() {
+			if err = a.hash(name, hashLength); err != nil {
+				return err
+			}
+			continue
+		}
+
+		sum, err := a.sum(name)
+		if err != nil {
+			return err
+		}
+
+		if len(sum) > hashLength {
+			sum = sum[:hashLength]
+		}
+
+		name = strings.TrimPrefix(name, "/")
+		ext := path.Ext(name)
+		a.add(name, strings.TrimSuffix(name, ext)+"."+sum+ext)
+	}
+
+	return nil
+}
+
+func (a *Assets) sum(name string) (string, error) {
+	f, err := a.fs.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err = io.Copy(h, f); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Lookup returns the request path of the fingerprinted file of the asset "name",
+// e.g. "/assets/app.3f9c1b2a.js" for "app.js",
+// and reports whether the asset exists.
+func (a *Assets) Lookup(name string) (string, bool) {
+	name = strings.TrimPrefix(name, "/")
+	fingerprinted, ok := a.paths[name]
+	if !ok {
+		fingerprinted = name
+	}
+
+	return a.prefix + "/" + fingerprinted, ok
+}
+
+// Path returns the request path of the fingerprinted file of the asset "name",
+// or the request path of the "name" itself if it's not an asset,
+// see the "asset" template function.
+func (a *Assets) Path(name string) string {
+	p, _ := a.Lookup(name)
+	return p
+}
+
+// Manifest returns a copy of the asset names and their fingerprinted file names.
+func (a *Assets) Manifest() map[string]string {
+	manifest := make(map[string]string, len(a.paths))
+	for name, fingerprinted := range a.paths {
+		manifest[name] = fingerprinted
+	}
+
+	return manifest
+}
+
+// Handler serves the file of the "file" path parameter.
+// The fingerprinted files are sent with the far-future Cache-Control header,
+// see `AssetsOptions.MaxAge`, the rest are served as they are.
+func (a *Assets) Handler(ctx *context.Context) {
+	name := strings.TrimPrefix(ctx.Params().Get("file"), "/")
+	fileName, fingerprinted := a.files[name]
+	if !fingerprinted {
+		fileName = name
+	}
+
+	f, err := a.fs.Open(path.Join("/", fileName))
+	if err != nil {
+		ctx.NotFound()
+		return
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil || info.IsDir() {
+		ctx.NotFound()
+		return
+	}
+
+	if fingerprinted {
+		ctx.Header(context.CacheControlHeaderKey, a.cacheControl)
+	}
+
+	if _, err = detectOrWriteContentType(ctx, info.Name(), f); err != nil {
+		ctx.StopWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.ServeContent(f, path.Base(name), info.ModTime())
+}
@@ -0,0 +1,68 @@
+package router_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/httptest"
+)
+
+func TestHandleAssets(t *testing.T) {
+	sum := sha256.Sum256([]byte("console.log(1)"))
+	hash := hex.EncodeToString(sum[:])[:8]
+
+	app := iris.New()
+	assets := app.HandleAssets("/assets", http.FS(fstest.MapFS{
+		"js/app.js":   {Data: []byte("console.log(1)")},
+		"robots.txt":  {Data: []byte("User-agent: *")},
+		".gitignore":  {Data: []byte("*")},
+		"css/app.css": {Data: []byte("body{}")},
+	}))
+	static := app.Party("/static")
+	manifest := static.HandleAssets("/", http.FS(fstest.MapFS{
+		"manifest.json":    {Data: []byte(`{"main.js": "/static/main.3f9c1b.js"}`)},
+		"main.3f9c1b.js":   {Data: []byte("main")},
+		"vendor.0a1b2c.js": {Data: []byte("vendor")},
+	}), iris.AssetsOptions{Manifest: "manifest.json", MaxAge: 24 * time.Hour})
+
+	// The "asset" template function resolves the names of all the registered assets.
+	app.RegisterView(iris.HTML(fstest.MapFS{
+		"index.html": {Data: []byte(`{{ asset "js/app.js" }}|{{ asset "main.js" }}`)},
+	}, ".html"))
+	app.Get("/", func(ctx iris.Context) {
+		if err := ctx.View("index.html"); err != nil {
+			ctx.StopWithError(iris.StatusInternalServerError, err)
+		}
+	})
+
+	expectedPath := "/assets/js/app." + hash + ".js"
+	if got := assets.Path("js/app.js"); got != expectedPath {
+		t.Fatalf("expected path: %s but got: %s", expectedPath, got)
+	}
+	if _, ok := assets.Lookup(".gitignore"); ok {
+		t.Fatalf("expected hidden files to be skipped")
+	}
+	if got := manifest.Path("main.js"); got != "/static/main.3f9c1b.js" {
+		t.Fatalf("expected manifest path but got: %s", got)
+	}
+	if got := manifest.Path("missing.js"); got != "/static/missing.js" {
+		t.Fatalf("expected the path of a missing asset to be kept but got: %s", got)
+	}
+
+	e := httptest.New(t, app)
+	e.GET(expectedPath).Expect().Status(httptest.StatusOK).
+		Header("Cache-Control").Equal("public, max-age=31536000, immutable")
+	e.GET(expectedPath).Expect().Body().Equal("console.log(1)")
+	e.GET("/assets/robots.txt").Expect().Status(httptest.StatusOK).
+		Header("Cache-Control").Empty()
+	e.GET("/assets/js/app.deadbeef.js").Expect().Status(httptest.StatusNotFound)
+	e.GET("/static/main.3f9c1b.js").Expect().Status(httptest.StatusOK).
+		Header("Cache-Control").Equal("public, max-age=86400, immutable")
+
+	e.GET("/").Expect().Status(httptest.StatusOK).Body().Equal(expectedPath + "|/static/main.3f9c1b.js")
+}
@@ -0,0 +1,90 @@
+package router
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/kataras/iris/v12/context"
+)
+
+// matchContentType reports whether the "contentType" (without parameters)
+// matches the "pattern". The pattern can be a full media type, e.g. "application/json",
+// a wildcard one, e.g. "application/*" or "*/*"
+// or a structured syntax suffix, e.g. "+json" or "application/*+json".
+func matchContentType(pattern, contentType string) bool {
+	pattern = strings.ToLower(strings.TrimSpace(pattern))
+
+	if pattern == "*/*" || pattern == contentType {
+		return true
+	}
+
+	if idx := strings.IndexByte(pattern, '+'); idx != -1 {
+		suffix := pattern[idx:]
+		if !strings.HasSuffix(contentType, suffix) {
+			return false
+		}
+
+		prefix := pattern[:idx] // "" or "type/*".
+		if prefix == "" || prefix == "*/*" {
+			return true
+		}
+
+		pattern = prefix
+	}
+
+	if strings.HasSuffix(pattern, "/*") {
+		return strings.HasPrefix(contentType, pattern[:len(pattern)-1])
+	}
+
+	return false
+}
+
+// newConsumesHandler returns a handler which
+// fires the next handler only if the request's body
+// content type matches one of the "contentTypes".
+// Requests without a body are always accepted.
+func newConsumesHandler(contentTypes []string) context.Handler {
+	supported := strings.Join(contentTypes, ", ")
+
+	return func(ctx *context.Context) {
+		r := ctx.Request()
+		if r.ContentLength == 0 && len(r.TransferEncoding) == 0 {
+			ctx.Next()
+			return
+		}
+
+		contentType, _, _ := mime.ParseMediaType(r.Header.Get(context.ContentTypeHeaderKey))
+		for _, pattern := range contentTypes {
+			if matchContentType(pattern, contentType) {
+				ctx.Next()
+				return
+			}
+		}
+
+		// RFC 5789 and the W3C Linked Data Platform Accept-Post header.
+		switch r.Method {
+		case http.MethodPatch:
+			ctx.Header("Accept-Patch", supported)
+		case http.MethodPost:
+			ctx.Header("Accept-Post", supported)
+		}
+
+		detail := "the request's content type " + contentType + " is not supported, expected: " + supported
+		if contentType == "" {
+			detail = "the request's content type is missing, expected: " + supported
+		}
+
+		problem := context.NewProblem().
+			Title("Unsupported Media Type").
+			Detail(detail).
+			Status(http.StatusUnsupportedMediaType)
+
+		ctx.StopWithStatus(http.StatusUnsupportedMediaType)
+		ctx.Negotiation().
+			MIME(context.ContentJSONProblemHeaderValue, problem).
+			MIME(context.ContentXMLProblemHeaderValue, problem).
+			Text(detail)
+		ctx.Negotiate(nil)
+	}
+}
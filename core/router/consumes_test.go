@@ -0,0 +1,48 @@
+package router_test
+
+import (
+	"testing"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/httptest"
+)
+
+func TestConsumesOnly(t *testing.T) {
+	app := iris.New()
+	handler := func(ctx iris.Context) {
+		ctx.WriteString("OK")
+	}
+
+	app.Post("/any", handler)
+	app.Post("/json", handler).ConsumesOnly("application/json", "+json")
+
+	api := app.Party("/api")
+	api.ConsumesOnly("image/*")
+	api.Post("/image", handler)
+	api.Post("/text", handler).ConsumesOnly("text/plain")
+	api.Get("/", handler)
+
+	e := httptest.New(t, app)
+	e.POST("/any").WithHeader("Content-Type", "application/xml").WithBytes([]byte("<a/>")).
+		Expect().Status(httptest.StatusOK)
+	e.POST("/json").WithJSON(map[string]string{"a": "b"}).
+		Expect().Status(httptest.StatusOK).Body().Equal("OK")
+	e.POST("/json").WithHeader("Content-Type", "application/vnd.api+json; charset=utf-8").WithBytes([]byte("{}")).
+		Expect().Status(httptest.StatusOK)
+	e.POST("/json").WithHeader("Content-Type", "application/xml").WithBytes([]byte("<a/>")).
+		Expect().Status(httptest.StatusUnsupportedMediaType).
+		Header("Accept-Post").Equal("application/json, +json")
+	e.POST("/json").WithHeader("Content-Type", "application/xml").WithHeader("Accept", "application/problem+json").
+		WithBytes([]byte("<a/>")).Expect().Status(httptest.StatusUnsupportedMediaType).
+		ContentType("application/problem+json").Body().Contains(`"title": "Unsupported Media Type"`)
+
+	e.POST("/api/image").WithHeader("Content-Type", "image/png").WithBytes([]byte("png")).
+		Expect().Status(httptest.StatusOK)
+	e.POST("/api/image").WithHeader("Content-Type", "text/plain").WithBytes([]byte("png")).
+		Expect().Status(httptest.StatusUnsupportedMediaType)
+	e.POST("/api/text").WithHeader("Content-Type", "text/plain").WithBytes([]byte("text")).
+		Expect().Status(httptest.StatusOK)
+	e.POST("/api/text").WithHeader("Content-Type", "image/png").WithBytes([]byte("png")).
+		Expect().Status(httptest.StatusUnsupportedMediaType)
+	e.GET("/api").Expect().Status(httptest.StatusOK)
+}
@@ -0,0 +1,74 @@
+package router_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/httptest"
+
+	"github.com/iris-contrib/httpexpect/v2"
+)
+
+func TestErrorCodes(t *testing.T) {
+	catalog := context.NewErrorCatalog()
+	errUserNotFound := catalog.Register(iris.ErrorCode{
+		Code:     "USER_NOT_FOUND",
+		Status:   iris.StatusNotFound,
+		Message:  "User not found",
+		DocsURL:  "https://example.com/docs/errors#USER_NOT_FOUND",
+		ExitCode: 4,
+	})
+	errUserLocked := catalog.Register(iris.ErrorCode{Code: "USER_LOCKED", Status: iris.StatusLocked})
+
+	app := iris.New()
+	app.Get("/errors", catalog.Handler)
+	route := app.Get("/users/{id}", func(ctx iris.Context) {
+		if ctx.Params().Get("id") == "locked" {
+			ctx.StopWithErrorCode(errUserLocked)
+			return
+		}
+
+		ctx.StopWithErrorCode(errUserNotFound.Wrap(errors.New("no rows")))
+	}).Errors(errUserNotFound, errUserLocked)
+
+	problem := httpexpect.ContentOpts{MediaType: "application/problem+json"}
+	e := httptest.New(t, app)
+
+	e.GET("/users/42").Expect().Status(httptest.StatusNotFound).JSON(problem).Equal(iris.Map{
+		"type":   "https://example.com/docs/errors#USER_NOT_FOUND",
+		"title":  "User not found",
+		"status": iris.StatusNotFound,
+		"code":   "USER_NOT_FOUND",
+		"detail": "no rows",
+	})
+	e.GET("/users/locked").Expect().Status(httptest.StatusLocked).JSON(problem).Object().
+		ValueEqual("code", "USER_LOCKED").ValueEqual("title", "Locked").NotContainsKey("detail")
+	e.GET("/errors").Expect().Status(httptest.StatusOK).JSON().Path("$.codes[*].code").
+		Array().Equal([]string{"USER_NOT_FOUND", "USER_LOCKED"})
+
+	if expected, got := 4, catalog.ExitCode(errUserNotFound.Wrap(errors.New("no rows"))); expected != got {
+		t.Fatalf("expected exit code: %d but got: %d", expected, got)
+	}
+	if expected, got := 1, catalog.ExitCode(errUserLocked); expected != got {
+		t.Fatalf("expected exit code: %d but got: %d", expected, got)
+	}
+	if !errors.Is(errUserNotFound.Wrap(errors.New("no rows")), errUserNotFound) {
+		t.Fatalf("expected a wrapped error to match its code")
+	}
+
+	responses := route.OpenAPIResponses()
+	if len(responses) != 2 || responses["404"] == nil || responses["423"] == nil {
+		t.Fatalf("expected the 404 and 423 responses but got: %v", responses)
+	}
+
+	// The problem errors use the code of the stored error.
+	app = iris.New()
+	app.UseProblemErrors()
+	app.Get("/", func(ctx iris.Context) {
+		ctx.StopWithPlainError(iris.StatusNotFound, errUserNotFound)
+	})
+	httptest.New(t, app).GET("/").Expect().Status(httptest.StatusNotFound).JSON(problem).Object().
+		ValueEqual("code", "USER_NOT_FOUND").ValueEqual("title", "User not found").NotContainsKey("detail")
+}
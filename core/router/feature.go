@@ -0,0 +1,51 @@
+package router
+
+import (
+	"os"
+	"strconv"
+)
+
+// FeatureFlag reports whether a group of routes should be part of the route table.
+// It is evaluated once, at route registration time.
+//
+// See `Party.PartyIf` and `Party.HandleIf` methods.
+type FeatureFlag func() bool
+
+// Feature returns a FeatureFlag which reports the given "enabled" value.
+// Useful for flags resolved from build tags or application configuration, e.g.
+//  // +build enterprise
+//  const enterprise = true
+//  [...]
+//  app.PartyIf(router.Feature(enterprise), "/billing")
+func Feature(enabled bool) FeatureFlag {
+	return func() bool {
+		return enabled
+	}
+}
+
+// FeatureEnv returns a FeatureFlag which reports whether
+// the "key" environment variable holds a true value,
+// e.g. "1", "t", "true", "TRUE". Any other or missing value disables the feature.
+//
+// Usage:
+//  app.HandleIf(router.FeatureEnv("IRIS_EXPERIMENTAL"), "GET", "/beta", betaHandler)
+func FeatureEnv(key string) FeatureFlag {
+	return func() bool {
+		enabled, _ := strconv.ParseBool(os.Getenv(key))
+		return enabled
+	}
+}
+
+// FeatureAll returns a FeatureFlag which reports true
+// only when all of the given "flags" report true.
+func FeatureAll(flags ...FeatureFlag) FeatureFlag {
+	return func() bool {
+		for _, flag := range flags {
+			if flag != nil && !flag() {
+				return false
+			}
+		}
+
+		return true
+	}
+}
@@ -0,0 +1,43 @@
+package router_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/httptest"
+)
+
+func TestFeatureFlags(t *testing.T) {
+	const envKey = "IRIS_TEST_FEATURE_BETA"
+	os.Setenv(envKey, "true")
+	defer os.Unsetenv(envKey)
+
+	app := iris.New()
+	handler := func(ctx iris.Context) {
+		ctx.WriteString(ctx.Path())
+	}
+
+	app.HandleIf(iris.Feature(true), iris.MethodGet, "/on", handler)
+	if route := app.HandleIf(iris.Feature(false), iris.MethodGet, "/off", handler); route == nil {
+		t.Fatalf("expected a non-nil, unregistered, route")
+	}
+
+	beta := app.PartyIf(iris.FeatureEnv(envKey), "/beta")
+	beta.Get("/", handler)
+
+	enterprise := app.PartyIf(iris.Feature(false), "/enterprise")
+	enterprise.Get("/", handler)
+	enterprise.Party("/billing").Get("/", handler)
+
+	if n := len(app.GetRoutes()); n != 2 {
+		t.Fatalf("expected 2 registered routes but got %d", n)
+	}
+
+	e := httptest.New(t, app)
+	e.GET("/on").Expect().Status(httptest.StatusOK).Body().Equal("/on")
+	e.GET("/beta").Expect().Status(httptest.StatusOK).Body().Equal("/beta")
+	e.GET("/off").Expect().Status(httptest.StatusNotFound)
+	e.GET("/enterprise").Expect().Status(httptest.StatusNotFound)
+	e.GET("/enterprise/billing").Expect().Status(httptest.StatusNotFound)
+}
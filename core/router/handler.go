@@ -51,6 +51,9 @@ type routerHandler struct {
 	hosts                bool             // true if at least one route contains a Subdomain.
 	errorHosts           bool             // true if error handlers are registered to at least one Subdomain.
 	errorDefaultHandlers context.Handlers // the main handler(s) for default error code handlers, when not registered directly by the end-developer.
+	problemErrors        *context.ProblemErrors
+
+	pathMethods PathMethods // the allowed methods of each path, see `HandleRequest` and `Configuration.FireMethodNotAllowed`.
 }
 
 var _ RequestHandler = (*routerHandler)(nil)
@@ -145,7 +148,18 @@ func (h *routerHandler) Build(provider RoutesProvider) error {
 
 	// set the default error code handler, will be fired on error codes
 	// that are not handled by a specific handler (On(Any)ErrorCode).
-	h.errorDefaultHandlers = append(provider.GetDefaultErrorMiddleware(), defaultErrorHandler)
+	h.problemErrors = nil
+	if p, ok := provider.(interface {
+		GetProblemErrors() *context.ProblemErrors
+	}); ok {
+		h.problemErrors = p.GetProblemErrors()
+	}
+
+	if h.problemErrors != nil {
+		h.errorDefaultHandlers = append(provider.GetDefaultErrorMiddleware(), h.problemErrors.Handler)
+	} else {
+		h.errorDefaultHandlers = append(provider.GetDefaultErrorMiddleware(), defaultErrorHandler)
+	}
 
 	rp := errgroup.New("Routes Builder")
 	registeredRoutes := provider.GetRoutes()
@@ -229,6 +243,27 @@ func (h *routerHandler) Build(provider RoutesProvider) error {
 		}
 	}
 
+	fireMethodNotAllowed := h.config != nil && h.config.GetFireMethodNotAllowed()
+	h.pathMethods = NewPathMethods(registeredRoutes, fireMethodNotAllowed)
+	if fireMethodNotAllowed && h.logger != nil {
+		for _, mismatch := range h.verifyPathMethods(registeredRoutes) {
+			h.logger.Warnf("Routes Builder: allowed methods mismatch: %s", mismatch)
+		}
+	}
+
+	if p, ok := provider.(interface {
+		GetRouteConflicts() []RouteConflict
+	}); ok {
+		failOnConflicts := h.config != nil && h.config.GetFailOnRouteConflicts()
+		for _, conflict := range p.GetRouteConflicts() {
+			if failOnConflicts {
+				rp.Err(conflict)
+			} else if h.logger != nil {
+				h.logger.Warnf("Routes Builder: %s", conflict)
+			}
+		}
+	}
+
 	// TODO: move this and make it easier to read when all cases are, visually, tested.
 	if logger := h.logger; logger != nil && logger.Level == golog.DebugLevel && noLogCount < len(registeredRoutes) {
 		// group routes by method and print them without the [DBUG] and time info,
@@ -446,17 +481,28 @@ func (h *routerHandler) HandleRequest(ctx *context.Context) {
 	}
 
 	if config.GetFireMethodNotAllowed() {
+		// if `Configuration#FireMethodNotAllowed` is kept as defaulted(false) then this function will not
+		// run, therefore performance kept as before.
+		var matched []context.RouteReadOnly
 		for i := range h.trees {
 			t := h.trees[i]
-			// if `Configuration#FireMethodNotAllowed` is kept as defaulted(false) then this function will not
-			// run, therefore performance kept as before.
-			if h.subdomainAndPathAndMethodExists(ctx, t, "", path) {
-				// RCF rfc2616 https://www.w3.org/Protocols/rfc2616/rfc2616-sec10.html
-				// The response MUST include an Allow header containing a list of valid methods for the requested resource.
-				ctx.Header("Allow", t.method)
-				ctx.StatusCode(http.StatusMethodNotAllowed)
+			if n := h.subdomainAndPathAndMethodNode(ctx, t, "", path); n != nil {
+				matched = append(matched, n.Route)
+			}
+		}
+
+		if len(matched) > 0 {
+			// RCF rfc2616 https://www.w3.org/Protocols/rfc2616/rfc2616-sec10.html
+			// The response MUST include an Allow header containing a list of valid methods for the requested resource.
+			ctx.Header("Allow", h.pathMethods.Allow(matched...))
+			if method == http.MethodOptions {
+				// Automatic OPTIONS response, RFC 7231, 4.3.7.
+				ctx.StatusCode(http.StatusNoContent)
 				return
 			}
+
+			ctx.StatusCode(http.StatusMethodNotAllowed)
+			return
 		}
 	}
 
@@ -581,6 +627,11 @@ func (h *routerHandler) FireErrorCode(ctx *context.Context) {
 			//                EndRequest -> FireErrorCode (handlers' length is always 0)
 			ctx.HandlerIndex(0)
 			ctx.Do(n.Handlers)
+
+			if h.problemErrors != nil && !errorBodyWritten(ctx) {
+				// the error handler did not write a body, send a problem instead.
+				h.problemErrors.Handler(ctx)
+			}
 			return
 		}
 
@@ -593,9 +644,21 @@ func (h *routerHandler) FireErrorCode(ctx *context.Context) {
 	ctx.Do(h.errorDefaultHandlers)
 }
 
+func errorBodyWritten(ctx *context.Context) bool {
+	if w, ok := ctx.IsRecording(); ok {
+		return len(w.Body()) > 0
+	}
+
+	return ctx.ResponseWriter().Written() > 0
+}
+
 func (h *routerHandler) subdomainAndPathAndMethodExists(ctx *context.Context, t *trie, method, path string) bool {
+	return h.subdomainAndPathAndMethodNode(ctx, t, method, path) != nil
+}
+
+func (h *routerHandler) subdomainAndPathAndMethodNode(ctx *context.Context, t *trie, method, path string) *trieNode {
 	if method != "" && method != t.method {
-		return false
+		return nil
 	}
 
 	if h.hosts && t.subdomain != "" {
@@ -604,7 +667,7 @@ func (h *routerHandler) subdomainAndPathAndMethodExists(ctx *context.Context, t
 			// this fixes a bug when listening on
 			// 127.0.0.1:8080 for example
 			// and have a wildcard subdomain and a route registered to root domain.
-			return false // it's not a subdomain, it's something like 127.0.0.1 probably
+			return nil // it's not a subdomain, it's something like 127.0.0.1 probably
 		}
 		// it's a dynamic wildcard subdomain, we have just to check if ctx.subdomain is not empty
 		if t.subdomain == SubdomainWildcardIndicator {
@@ -614,7 +677,7 @@ func (h *routerHandler) subdomainAndPathAndMethodExists(ctx *context.Context, t
 			// sub.localhost -> valid
 			serverHost := ctx.Application().ConfigurationReadOnly().GetVHost()
 			if serverHost == requestHost {
-				return false // it's not a subdomain, it's a full domain (with .com...)
+				return nil // it's not a subdomain, it's a full domain (with .com...)
 			}
 
 			dotIdx := strings.IndexByte(requestHost, '.')
@@ -622,16 +685,15 @@ func (h *routerHandler) subdomainAndPathAndMethodExists(ctx *context.Context, t
 			if dotIdx > 0 && (slashIdx == -1 || slashIdx > dotIdx) {
 				// if "." was found anywhere but not at the first path segment (host).
 			} else {
-				return false
+				return nil
 			}
 			// continue to that, any subdomain is valid.
 		} else if !strings.HasPrefix(requestHost, t.subdomain) { // t.subdomain contains the dot.
-			return false
+			return nil
 		}
 	}
 
-	n := t.search(path, ctx.Params())
-	return n != nil
+	return t.search(path, ctx.Params())
 }
 
 // RouteExists reports whether a particular route exists
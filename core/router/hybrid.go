@@ -0,0 +1,218 @@
+package router
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kataras/iris/v12/context"
+)
+
+// HybridOptions holds the optional settings of the `Party.HandleHybrid` method.
+type HybridOptions struct {
+	// IndexName is the file name which is served
+	// when the request path is a directory.
+	//
+	// Defaults to "index.html".
+	IndexName string
+	// Extensions are appended to the request path
+	// when a file of that exact name does not exist,
+	// e.g. "/about" serves the "about.html" file.
+	//
+	// Defaults to ".html".
+	Extensions []string
+	// CacheTTL is the duration the existence check of a request path is cached.
+	// Set it to a negative value to disable the cache,
+	// e.g. when the pages are prerendered at runtime.
+	//
+	// Defaults to zero, cached forever.
+	CacheTTL time.Duration
+	// MaxCacheEntries is the maximum number of the cached request paths,
+	// the cache is reset when it's exceeded.
+	//
+	// Defaults to 10000.
+	MaxCacheEntries int
+	// ShowHidden serves the hidden files too, the ones with a path segment
+	// which starts with a dot, e.g. "/.env" or "/.git/config", like the `DirOptions.ShowHidden`.
+	// Otherwise the "handlers" are executed instead, set it to true
+	// to serve directories like the "/.well-known" one.
+	//
+	// Defaults to false.
+	ShowHidden bool
+}
+
+// HandleHybrid registers GET and HEAD routes which serve
+// the file of the request path from the given file system (physical or embedded), if it exists,
+// otherwise the "handlers" are executed, e.g. prerendered pages with a dynamic fallback.
+// The existence check is cached per request path, see `HybridOptions.CacheTTL`.
+//
+// The file system's root is mapped to the Party's path,
+// the "requestPath" can contain dynamic parameters.
+//
+// Usage:
+//  app.HandleHybrid("/", "./prerendered", router.HybridOptions{}, homeHandler)
+//  blog := app.Party("/blog")
+//  blog.HandleHybrid("/{slug}", "./prerendered/blog", router.HybridOptions{}, postHandler)
+func (api *APIBuilder) HandleHybrid(requestPath string, fsOrDir interface{}, opts HybridOptions, handlers ...context.Handler) []*Route {
+	var fs http.FileSystem
+	switch v := fsOrDir.(type) {
+	case string:
+		fs = http.Dir(v)
+	case http.FileSystem:
+		fs = v
+	default:
+		panic(fmt.Errorf(`unexpected "fsOrDir" argument type of %T (string or http.FileSystem)`, v))
+	}
+
+	_, prefix := splitSubdomainAndPath(api.relativePath)
+	if strings.Contains(prefix, "{") || prefix == "/" {
+		prefix = ""
+	}
+
+	h := newHybridHandler(fs, opts, prefix)
+	handlers = append(context.Handlers{h.serve}, handlers...)
+
+	return []*Route{
+		api.Handle(http.MethodGet, requestPath, handlers...),
+		api.Handle(http.MethodHead, requestPath, handlers...),
+	}
+}
+
+type hybridCacheEntry struct {
+	name    string // empty if not exists.
+	expires time.Time
+}
+
+type hybridHandler struct {
+	fs     http.FileSystem
+	opts   HybridOptions
+	prefix string
+
+	mu    sync.RWMutex
+	cache map[string]hybridCacheEntry
+}
+
+func newHybridHandler(fs http.FileSystem, opts HybridOptions, prefix string) *hybridHandler {
+	if opts.IndexName == "" {
+		opts.IndexName = "index.html"
+	}
+
+	if opts.Extensions == nil {
+		opts.Extensions = []string{".html"}
+	}
+
+	if opts.MaxCacheEntries <= 0 {
+		opts.MaxCacheEntries = 10000
+	}
+
+	return &hybridHandler{
+		fs:     fs,
+		opts:   opts,
+		prefix: prefix,
+		cache:  make(map[string]hybridCacheEntry),
+	}
+}
+
+// lookup returns the file name of the "requestPath"
+// or empty if it does not exist.
+func (h *hybridHandler) lookup(requestPath string) string {
+	if h.opts.CacheTTL < 0 {
+		return h.find(requestPath)
+	}
+
+	now := time.Now()
+
+	h.mu.RLock()
+	entry, ok := h.cache[requestPath]
+	h.mu.RUnlock()
+	if ok && (entry.expires.IsZero() || now.Before(entry.expires)) {
+		return entry.name
+	}
+
+	entry = hybridCacheEntry{name: h.find(requestPath)}
+	if h.opts.CacheTTL > 0 {
+		entry.expires = now.Add(h.opts.CacheTTL)
+	}
+
+	h.mu.Lock()
+	if len(h.cache) >= h.opts.MaxCacheEntries {
+		h.cache = make(map[string]hybridCacheEntry)
+	}
+	h.cache[requestPath] = entry
+	h.mu.Unlock()
+
+	return entry.name
+}
+
+func (h *hybridHandler) find(requestPath string) string {
+	name := path.Clean("/" + strings.TrimPrefix(requestPath, h.prefix))
+	if !h.opts.ShowHidden && hasHiddenSegment(name) {
+		return ""
+	}
+
+	candidates := make([]string, 0, len(h.opts.Extensions)+2)
+	if name != "/" {
+		candidates = append(candidates, name)
+		for _, ext := range h.opts.Extensions {
+			candidates = append(candidates, name+ext)
+		}
+	}
+	candidates = append(candidates, path.Join(name, h.opts.IndexName))
+
+	for _, candidate := range candidates {
+		f, err := h.fs.Open(candidate)
+		if err != nil {
+			continue
+		}
+
+		info, err := f.Stat()
+		f.Close()
+		if err == nil && !info.IsDir() {
+			return candidate
+		}
+	}
+
+	return ""
+}
+
+// hasHiddenSegment reports whether a segment of the "name" path starts with a dot.
+func hasHiddenSegment(name string) bool {
+	for _, segment := range strings.Split(name, "/") {
+		if strings.HasPrefix(segment, ".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (h *hybridHandler) serve(ctx *context.Context) {
+	name := h.lookup(ctx.Path())
+	if name == "" {
+		ctx.Next()
+		return
+	}
+
+	f, err := h.fs.Open(name)
+	if err != nil { // removed after the cached check.
+		ctx.Next()
+		return
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		ctx.Next()
+		return
+	}
+
+	if _, err = detectOrWriteContentType(ctx, info.Name(), f); err != nil {
+		ctx.StopWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.ServeContent(f, info.Name(), info.ModTime())
+}
@@ -0,0 +1,94 @@
+package router_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/httptest"
+)
+
+func TestHandleHybrid(t *testing.T) {
+	dir, err := os.MkdirTemp("", "hybrid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	writeFile := func(name, contents string) {
+		t.Helper()
+		name = filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(name), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(name, []byte(contents), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeFile("index.html", "static home")
+	writeFile("blog/hello.html", "static hello")
+
+	dynamic := func(ctx iris.Context) {
+		ctx.Writef("dynamic %s", ctx.Params().GetDefault("slug", "home"))
+	}
+
+	app := iris.New()
+	app.HandleHybrid("/", dir, iris.HybridOptions{}, dynamic)
+	blog := app.Party("/blog")
+	blog.HandleHybrid("/{slug}", filepath.Join(dir, "blog"), iris.HybridOptions{}, dynamic)
+	news := app.Party("/news")
+	news.HandleHybrid("/{slug}", filepath.Join(dir, "news"), iris.HybridOptions{CacheTTL: -1}, dynamic)
+
+	e := httptest.New(t, app)
+	e.GET("/").Expect().Status(httptest.StatusOK).
+		ContentType("text/html").Body().Equal("static home")
+	e.GET("/blog/hello").Expect().Status(httptest.StatusOK).Body().Equal("static hello")
+	e.GET("/blog/world").Expect().Status(httptest.StatusOK).Body().Equal("dynamic world")
+
+	// The existence check is cached.
+	writeFile("blog/world.html", "static world")
+	e.GET("/blog/world").Expect().Status(httptest.StatusOK).Body().Equal("dynamic world")
+
+	// Cache disabled.
+	e.GET("/news/today").Expect().Status(httptest.StatusOK).Body().Equal("dynamic today")
+	writeFile("news/today/index.html", "static today")
+	e.GET("/news/today").Expect().Status(httptest.StatusOK).Body().Equal("static today")
+}
+
+func TestHandleHybridHidden(t *testing.T) {
+	dir := t.TempDir()
+	for name, contents := range map[string]string{
+		".env":                      "SECRET=1",
+		".git/config":               "[core]",
+		".well-known/security.txt":  "Contact: security@example.com",
+		"public/.hidden/index.html": "hidden index",
+	} {
+		name = filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(name), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(name, []byte(contents), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dynamic := func(ctx iris.Context) {
+		ctx.Writef("dynamic %s", ctx.Path())
+	}
+
+	app := iris.New()
+	app.HandleHybrid("/{p:path}", dir, iris.HybridOptions{}, dynamic)
+	wellKnown := app.Party("/.well-known")
+	wellKnown.HandleHybrid("/{p:path}", filepath.Join(dir, ".well-known"), iris.HybridOptions{ShowHidden: true}, dynamic)
+
+	e := httptest.New(t, app)
+	// The hidden files are not served by default, the handlers are executed instead.
+	e.GET("/.env").Expect().Status(httptest.StatusOK).Body().Equal("dynamic /.env")
+	e.GET("/.git/config").Expect().Status(httptest.StatusOK).Body().Equal("dynamic /.git/config")
+	e.GET("/public/.hidden/").Expect().Status(httptest.StatusOK).Body().Equal("dynamic /public/.hidden")
+	// Served when ShowHidden is true.
+	e.GET("/.well-known/security.txt").Expect().Status(httptest.StatusOK).
+		Body().Equal("Contact: security@example.com")
+}
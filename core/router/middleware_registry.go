@@ -0,0 +1,60 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/kataras/iris/v12/context"
+)
+
+// RegisterMiddleware registers one or more handlers under the given "name",
+// so Parties and configuration files can reference them by name, see `UseNamed`.
+// The registry is shared across all Parties of the Application.
+//
+// A later registration of the same name overrides the previous one
+// for the next `UseNamed` calls, e.g. to replace a middleware per environment:
+//  app.RegisterMiddleware("auth", jwtAuth)
+//  if env == "development" {
+//      app.RegisterMiddleware("auth", devAuth)
+//  }
+//  [...]
+//  api := app.Party("/api")
+//  api.UseNamed(cfg.Middleware...) // e.g. "auth", "ratelimit".
+func (api *APIBuilder) RegisterMiddleware(name string, handlers ...context.Handler) {
+	if name == "" || len(handlers) == 0 {
+		return
+	}
+
+	api.namedMiddleware[name] = context.JoinHandlers(handlers, nil)
+}
+
+// GetMiddleware returns the handlers registered under the "name"
+// through `RegisterMiddleware` or nil.
+func (api *APIBuilder) GetMiddleware(name string) context.Handlers {
+	return api.namedMiddleware[name]
+}
+
+// UseNamed appends the handlers registered under the given "names",
+// in order, to the current Party's routes and child routes, like `Use` does.
+// The names are resolved once, at the time of the call.
+//
+// A name which is not registered is logged as an error and
+// its routes fail with 500 Internal Server Error,
+// so a missing middleware, e.g. an authentication one, is not silently skipped.
+func (api *APIBuilder) UseNamed(names ...string) {
+	for _, name := range names {
+		handlers, ok := api.namedMiddleware[name]
+		if !ok {
+			api.logger.Errorf("Party: %s: middleware %q is not registered", api.relativePath, name)
+			handlers = context.Handlers{missingMiddleware(name)}
+		}
+
+		api.Use(handlers...)
+	}
+}
+
+func missingMiddleware(name string) context.Handler {
+	return func(ctx *context.Context) {
+		ctx.Application().Logger().Errorf("middleware %q is not registered", name)
+		ctx.StopWithStatus(http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,51 @@
+package router_test
+
+import (
+	"testing"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/httptest"
+)
+
+func TestNamedMiddleware(t *testing.T) {
+	app := iris.New()
+	app.Logger().SetLevel("disable")
+
+	writeName := func(name string) iris.Handler {
+		return func(ctx iris.Context) {
+			ctx.WriteString(name + ",")
+			ctx.Next()
+		}
+	}
+	handler := func(ctx iris.Context) {
+		ctx.WriteString("main")
+	}
+
+	app.RegisterMiddleware("auth", writeName("auth"))
+	app.RegisterMiddleware("ratelimit", writeName("ratelimit1"), writeName("ratelimit2"))
+
+	api := app.Party("/api")
+	api.UseNamed("auth", "ratelimit")
+	api.Get("/", handler)
+
+	// override, e.g. per environment, for the next UseNamed calls,
+	// the registry is shared across Parties.
+	api.RegisterMiddleware("auth", writeName("devauth"))
+	admin := app.Party("/admin")
+	admin.UseNamed("auth")
+	admin.Get("/", handler)
+
+	if n := len(app.GetMiddleware("ratelimit")); n != 2 {
+		t.Fatalf("expected 2 handlers but got %d", n)
+	}
+
+	// a missing middleware fails closed.
+	missing := app.Party("/missing")
+	missing.UseNamed("notregistered")
+	missing.Get("/", handler)
+
+	e := httptest.New(t, app)
+	e.GET("/api").Expect().Status(httptest.StatusOK).Body().Equal("auth,ratelimit1,ratelimit2,main")
+	e.GET("/admin").Expect().Status(httptest.StatusOK).Body().Equal("devauth,main")
+	e.GET("/missing").Expect().Status(httptest.StatusInternalServerError)
+}
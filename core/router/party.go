@@ -2,6 +2,7 @@ package router
 
 import (
 	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/hero"
 	"github.com/kataras/iris/v12/macro"
 
 	"github.com/kataras/golog"
@@ -27,6 +28,17 @@ type Party interface {
 	//
 	// It returns the same `APIBuilder` featured with Dependency Injection.
 	ConfigureContainer(builder ...func(*APIContainer)) *APIContainer
+	// Container returns the Dependency Injection container of this Party.
+	// It is a child of the parent Party's container:
+	// it inherits all the parent's dependencies, including the ones registered later on,
+	// but any dependency registered here overrides the parent's one of the same type
+	// for this Party and its children only.
+	//
+	// Usage:
+	//  app.RegisterDependency(defaultPermissions)
+	//  admin := app.Party("/admin")
+	//  admin.Container().Register(elevatedPermissions)
+	Container() *hero.Container
 	// RegisterDependency calls the `ConfigureContainer.RegisterDependency` method
 	// with the provided value(s). See `HandleFunc` and `PartyConfigure` methods too.
 	RegisterDependency(dependencies ...interface{})
@@ -149,6 +161,17 @@ type Party interface {
 	// use the `Subdomain` or `WildcardSubdomain` methods
 	// or pass a "relativePath" as "admin." or "*." respectfully.
 	Party(relativePath string, middleware ...context.Handler) Party
+	// PartyIf like `Party` registers a new child Party
+	// but its routes, and its children's ones, are registered
+	// only if the given "flag" reports true.
+	// That way enterprise-only or experimental endpoints are excluded
+	// from the route table (and so from any route documentation tool)
+	// instead of being just guarded by a middleware.
+	//
+	// Usage:
+	//  beta := app.PartyIf(router.FeatureEnv("IRIS_BETA"), "/beta")
+	//  beta.Get("/", betaIndex) // registered only when IRIS_BETA=true.
+	PartyIf(flag FeatureFlag, relativePath string, middleware ...context.Handler) Party
 	// PartyFunc same as `Party`, groups routes that share a base path or/and same handlers.
 	// However this function accepts a function that receives this created Party instead.
 	// Returns the Party in order the caller to be able to use this created Party to continue the
@@ -222,6 +245,17 @@ type Party interface {
 	// If the current Party is the root, then it registers the middleware to all child Parties' routes too.
 	// To register a middleware for error handlers, look `UseError` method instead.
 	Use(middleware ...context.Handler)
+	// RegisterMiddleware registers one or more handlers under the given "name",
+	// so Parties and configuration files can reference them by name, see `UseNamed`.
+	// The registry is shared across all Parties.
+	// A later registration of the same name overrides the previous one for the next `UseNamed` calls.
+	RegisterMiddleware(name string, handlers ...context.Handler)
+	// GetMiddleware returns the handlers registered under the "name" through `RegisterMiddleware` or nil.
+	GetMiddleware(name string) context.Handlers
+	// UseNamed appends the handlers registered under the given "names",
+	// in order, to the current Party's routes and child routes, like `Use` does.
+	// A name which is not registered is logged and its routes fail with 500 Internal Server Error.
+	UseNamed(names ...string)
 	// UseOnce either inserts a middleware,
 	// or on the basis of the middleware already existing,
 	// replace that existing middleware instead.
@@ -290,12 +324,29 @@ type Party interface {
 	// * RouteError
 	// * RouteOverlap.
 	SetRegisterRule(rule RouteRegisterRule) Party
+	// ConsumesOnly sets the default allowed request body content types
+	// for the routes registered after this call, on this Party and its children.
+	// See `Route.ConsumesOnly` for more.
+	//
+	// Returns this Party.
+	ConsumesOnly(contentTypes ...string) Party
+	// Secure sets the default security requirements
+	// for the routes registered after this call, on this Party and its children.
+	// See `Route.Secure` for more.
+	Secure(requirements ...context.SecurityRequirement) Party
 
 	// Handle registers a route to the server's router.
 	// if empty method is passed then handler(s) are being registered to all methods, same as .Any.
 	//
 	// Returns the read-only route information.
 	Handle(method string, registeredPath string, handlers ...context.Handler) *Route
+	// HandleIf like `Handle` but it registers the route
+	// only if the given "flag" reports true.
+	// If the feature is disabled the route is created but it is not part of the route table,
+	// so it is unreachable and hidden from any route introspection (e.g. `GetRoutes`).
+	//
+	// See `PartyIf` to enable or disable a whole group of routes.
+	HandleIf(flag FeatureFlag, method string, registeredPath string, handlers ...context.Handler) *Route
 	// HandleMany works like `Handle` but can receive more than one
 	// paths separated by spaces and returns always a slice of *Route instead of a single instance of Route.
 	//
@@ -333,6 +384,33 @@ type Party interface {
 	// Examples:
 	// https://github.com/kataras/iris/tree/master/_examples/file-server
 	HandleDir(requestPath string, fileSystem interface{}, opts ...DirOptions) []*Route
+	// HandleHybrid registers GET and HEAD routes which serve
+	// the file of the request path from the given file system (physical or embedded), if it exists,
+	// otherwise the "handlers" are executed, e.g. prerendered pages with a dynamic fallback.
+	// The existence check is cached per request path, see `HybridOptions.CacheTTL`.
+	//
+	// Usage:
+	// HandleHybrid("/", "./prerendered", iris.HybridOptions{}, homeHandler)
+	HandleHybrid(requestPath string, fileSystem interface{}, opts HybridOptions, handlers ...context.Handler) []*Route
+	// HandleAssets registers GET and HEAD routes which serve the files of the
+	// given file system (physical or embedded) under fingerprinted file names,
+	// e.g. "/assets/app.3f9c1b2a.js" for the "app.js" file, with far-future cache headers.
+	// The returned Assets resolve the request path of an asset name,
+	// the "asset" template function uses them, e.g. {{ asset "app.js" }}.
+	//
+	// Usage:
+	// HandleAssets("/assets", "./public", iris.AssetsOptions{Manifest: "manifest.json"})
+	HandleAssets(requestPath string, fileSystem interface{}, opts ...AssetsOptions) *Assets
+	// Tus registers the routes of the tus resumable upload protocol
+	// (core, creation, expiration and termination extensions)
+	// under the "requestPath" and the "requestPath/{id}".
+	// The uploads are stored to the given "store".
+	//
+	// Usage:
+	// Tus("/files", iris.NewTusDirStore("./uploads"), iris.TusOptions{Expiration: 24 * time.Hour})
+	//
+	// See https://tus.io/protocols/resumable-upload.html for more.
+	Tus(requestPath string, store TusStore, opts ...TusOptions) []*Route
 
 	// None registers an "offline" route
 	// see context.ExecRoute(routeName) and
@@ -421,8 +499,10 @@ type Party interface {
 	// Returns the GET *Route.
 	Favicon(favPath string, requestPath ...string) *Route
 
-	// RegisterView registers and loads a view engine middleware for this group of routes.
-	// It overrides any of the application's root registered view engine.
+	// RegisterView registers a view engine middleware for this group of routes.
+	// It overrides any of the application's root registered view engine
+	// and the template layout set by a parent Party, use `Layout` after `RegisterView`.
+	// The engine is loaded on `Application.Build`, with the application's shared template functions.
 	// To register a view engine per handler chain see the `Context.ViewEngine` instead.
 	// Read `Configuration.ViewEngineContextKey` documentation for more.
 	RegisterView(viewEngine context.ViewEngine)
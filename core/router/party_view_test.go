@@ -0,0 +1,47 @@
+package router_test
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/httptest"
+)
+
+func TestPartyView(t *testing.T) {
+	app := iris.New()
+	app.RegisterView(iris.HTML(fstest.MapFS{
+		"index.html":          {Data: []byte(`public:{{ upper .Name }}`)},
+		"layouts/public.html": {Data: []byte(`<main>{{ yield }}</main>`)},
+	}, ".html"))
+	app.Layout("layouts/public.html")
+
+	admin := app.Party("/admin")
+	admin.RegisterView(iris.HTML(fstest.MapFS{
+		"index.html":         {Data: []byte(`admin:{{ upper .Name }}`)},
+		"layouts/admin.html": {Data: []byte(`<section>{{ yield }}</section>`)},
+	}, ".html"))
+	admin.Layout("layouts/admin.html")
+
+	// the parent's layout does not belong to the Party's engine.
+	reports := app.Party("/reports")
+	reports.RegisterView(iris.Django(fstest.MapFS{
+		"index.html": {Data: []byte(`reports:{{ Name|upper }}`)},
+	}, ".html"))
+
+	// the shared template functions are added to the Party engines too, on Build.
+	app.AddViewFunc("upper", strings.ToUpper)
+
+	handler := func(ctx iris.Context) {
+		ctx.View("index.html", iris.Map{"Name": "iris"})
+	}
+	app.Get("/", handler)
+	admin.Get("/", handler)
+	reports.Get("/", handler)
+
+	e := httptest.New(t, app)
+	e.GET("/").Expect().Status(httptest.StatusOK).Body().Equal("<main>public:IRIS</main>")
+	e.GET("/admin").Expect().Status(httptest.StatusOK).Body().Equal("<section>admin:IRIS</section>")
+	e.GET("/reports").Expect().Status(httptest.StatusOK).Body().Equal("reports:IRIS")
+}
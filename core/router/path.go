@@ -1,6 +1,7 @@
 package router
 
 import (
+	"fmt"
 	"net/http"
 	"path"
 	"strconv"
@@ -375,6 +376,8 @@ func toStringSlice(args []interface{}) (argsString []string) {
 				argsString[i] = arr[0]
 				argsString = append(argsString, arr[1:]...)
 			}
+		} else if v != nil {
+			argsString[i] = fmt.Sprint(v)
 		}
 	}
 	return
@@ -0,0 +1,229 @@
+package router
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/kataras/iris/v12/context"
+)
+
+// PolicyKinds are the builtin policies, in the order they are printed by the `PolicyReport`.
+// See `context.SetHandlerPolicy` to mark a custom middleware as a policy enforcer.
+var PolicyKinds = []string{
+	context.PolicyCompression,
+	context.PolicyCache,
+	context.PolicyAuth,
+	context.PolicyRateLimit,
+	context.PolicyBodyLimit,
+}
+
+// RoutePolicy holds the effective policies of a route.
+type RoutePolicy struct {
+	Method    string `json:"method"`
+	Subdomain string `json:"subdomain,omitempty"`
+	Path      string `json:"path"`
+	Name      string `json:"name"`
+	// Policies maps a policy (e.g. "auth") to the names of
+	// the route's handlers (including the router filters, see `UseRouter`) which enforce it.
+	Policies map[string][]string `json:"policies"`
+	// Conditional maps a policy to the names of the router filters which enforce it
+	// only when their Party's custom matcher (see `SetPartyMatcher`) matches the request.
+	Conditional map[string][]string `json:"conditional,omitempty"`
+}
+
+// Has reports whether the route enforces the given "policy".
+func (p RoutePolicy) Has(policy string) bool {
+	return len(p.Policies[policy]) > 0
+}
+
+// PolicyReport is a per-route effective policy matrix,
+// see `APIBuilder.PolicyReport` method.
+type PolicyReport []RoutePolicy
+
+// PolicyReport returns the effective policies (compression, cache, auth, rate limit, body limit and
+// any custom one) of each online route, so security reviews can audit what each endpoint actually enforces.
+// The policies are resolved through the route's handler names, see `context.SetHandlerPolicy`.
+//
+// It should be called after `Build`, the route's handlers are not final before that.
+// Set the IRIS_POLICY_REPORT environment variable to print it on `Application.Build`.
+//
+// Usage:
+//  app.Build()
+//  fmt.Println(app.PolicyReport())
+func (api *APIBuilder) PolicyReport() PolicyReport {
+	routes := api.GetRoutes()
+	report := make(PolicyReport, 0, len(routes))
+
+	for _, r := range routes {
+		if !r.IsOnline() {
+			continue
+		}
+
+		p := RoutePolicy{
+			Method:    r.Method,
+			Subdomain: r.Subdomain,
+			Path:      r.Path,
+			Name:      r.Name,
+			Policies:  make(map[string][]string),
+		}
+
+		for _, f := range api.routerFilters {
+			if !policyFilterMatch(f, r) {
+				continue
+			}
+
+			if policyFilterConditional(f) {
+				if p.Conditional == nil {
+					p.Conditional = make(map[string][]string)
+				}
+
+				addHandlerPolicies(p.Conditional, f.Handlers)
+				continue
+			}
+
+			addHandlerPolicies(p.Policies, f.Handlers)
+		}
+
+		addHandlerPolicies(p.Policies, r.Handlers)
+		report = append(report, p)
+	}
+
+	sort.SliceStable(report, func(i, j int) bool {
+		if report[i].Subdomain != report[j].Subdomain {
+			return report[i].Subdomain < report[j].Subdomain
+		}
+
+		if report[i].Path != report[j].Path {
+			return report[i].Path < report[j].Path
+		}
+
+		return report[i].Method < report[j].Method
+	})
+
+	return report
+}
+
+// PolicyReportHandler sends the `PolicyReport` as JSON
+// or as a text table when the "format" URL query parameter is "text".
+// Make sure it's protected, e.g. register it under an admin Party.
+//
+// Usage:
+//  admin.Get("/policies", app.PolicyReportHandler)
+func (api *APIBuilder) PolicyReportHandler(ctx *context.Context) {
+	report := api.PolicyReport()
+	if ctx.URLParam("format") == "text" {
+		ctx.WriteString(report.String()) // nolint:errcheck
+		return
+	}
+
+	ctx.JSON(report) // nolint:errcheck
+}
+
+func policyFilterMatch(f *Filter, r *Route) bool {
+	if f.Subdomain != "" && f.Subdomain != r.Subdomain &&
+		!(f.Subdomain == SubdomainWildcardIndicator && r.Subdomain != "") {
+		return false
+	}
+
+	prefix := strings.TrimSuffix(f.Path, "/")
+	return prefix == "" || r.Path == prefix || strings.HasPrefix(r.Path, prefix+"/")
+}
+
+// policyFilterConditional reports whether the filter's handlers
+// run only when a custom Party matcher matches the request.
+func policyFilterConditional(f *Filter) bool {
+	api, ok := f.Matcher.(*APIBuilder)
+	if !ok {
+		return f.Matcher != nil
+	}
+
+	return reflect.ValueOf(api.partyMatcher).Pointer() != reflect.ValueOf(defaultPartyMatcher).Pointer()
+}
+
+func addHandlerPolicies(policies map[string][]string, handlers context.Handlers) {
+	for _, h := range handlers {
+		policy, ok := context.HandlerPolicy(h)
+		if !ok {
+			continue
+		}
+
+		name := context.HandlerName(h)
+		exists := false
+		for _, n := range policies[policy] {
+			if n == name {
+				exists = true
+				break
+			}
+		}
+
+		if !exists {
+			policies[policy] = append(policies[policy], name)
+		}
+	}
+}
+
+// Kinds returns the builtin `PolicyKinds` followed by any custom policy of the report.
+func (r PolicyReport) Kinds() []string {
+	kinds := append([]string(nil), PolicyKinds...)
+	var custom []string
+	for _, p := range r {
+		for _, policies := range []map[string][]string{p.Policies, p.Conditional} {
+			for policy := range policies {
+				if !containsString(kinds, policy) && !containsString(custom, policy) {
+					custom = append(custom, policy)
+				}
+			}
+		}
+	}
+
+	sort.Strings(custom)
+	return append(kinds, custom...)
+}
+
+// String returns the report as a text table, e.g.
+//  METHOD  PATH          COMPRESSION  CACHE  AUTH  RATELIMIT  BODYLIMIT
+//  GET     /             -            -      -     -          -
+//  POST    /admin/users  yes          -      yes   yes        yes
+// A policy enforced only through a custom Party matcher is marked as "conditional".
+func (r PolicyReport) String() string {
+	kinds := r.Kinds()
+
+	buf := new(bytes.Buffer)
+	w := tabwriter.NewWriter(buf, 0, 0, 2, ' ', 0)
+	fmt.Fprint(w, "METHOD\tPATH")
+	for _, kind := range kinds {
+		fmt.Fprintf(w, "\t%s", strings.ToUpper(kind))
+	}
+	fmt.Fprintln(w)
+
+	for _, p := range r {
+		fmt.Fprintf(w, "%s\t%s", p.Method, p.Subdomain+p.Path)
+		for _, kind := range kinds {
+			mark := "-"
+			if p.Has(kind) {
+				mark = "yes"
+			} else if len(p.Conditional[kind]) > 0 {
+				mark = "conditional"
+			}
+			fmt.Fprintf(w, "\t%s", mark)
+		}
+		fmt.Fprintln(w)
+	}
+
+	w.Flush()
+	return buf.String()
+}
+
+func containsString(slice []string, s string) bool {
+	for _, v := range slice {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
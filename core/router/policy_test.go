@@ -0,0 +1,105 @@
+package router_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/cache"
+	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/httptest"
+	"github.com/kataras/iris/v12/middleware/basicauth"
+	"github.com/kataras/iris/v12/middleware/rate"
+)
+
+func customGuard(ctx iris.Context) {
+	ctx.Next()
+}
+
+func TestPolicyReport(t *testing.T) {
+	context.SetHandlerPolicy(context.HandlerName(customGuard), "guard")
+
+	app := iris.New()
+	handler := func(ctx iris.Context) {}
+	app.Get("/", handler)
+
+	api := app.Party("/api")
+	api.UseRouter(iris.Compression)
+	api.Get("/public", cache.Handler(time.Minute), handler)
+
+	admin := api.Party("/admin", basicauth.Default(map[string]string{"admin": "admin"}), rate.Limit(1, 5))
+	admin.Post("/users", iris.LimitRequestBodySize(1024), customGuard, handler)
+
+	app.Get("/policies", app.PolicyReportHandler)
+
+	if err := app.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	report := app.PolicyReport()
+	expected := `METHOD  PATH              COMPRESSION  CACHE  AUTH  RATELIMIT  BODYLIMIT  GUARD
+GET     /                 -            -      -     -          -          -
+POST    /api/admin/users  yes          -      yes   yes        yes        yes
+GET     /api/public       yes          yes    -     -          -          -
+GET     /policies         -            -      -     -          -          -
+`
+	if got := report.String(); got != expected {
+		t.Fatalf("expected:\n%s\nbut got:\n%s", expected, got)
+	}
+
+	if names := report[1].Policies[context.PolicyAuth]; len(names) != 1 || names[0] != "iris.basicauth" {
+		t.Fatalf("unexpected auth handlers: %v", names)
+	}
+
+	e := httptest.New(t, app)
+	e.GET("/policies").WithQuery("format", "text").Expect().Status(httptest.StatusOK).Body().Equal(expected)
+	e.GET("/policies").Expect().Status(httptest.StatusOK).JSON().Array().Element(1).Object().
+		ValueEqual("path", "/api/admin/users").Value("policies").Object().ContainsKey("ratelimit")
+}
+
+func TestPolicyReportFilterMatch(t *testing.T) {
+	app := iris.New()
+	handler := func(ctx iris.Context) {}
+
+	admin := app.Party("/admin")
+	admin.UseRouter(basicauth.Default(map[string]string{"admin": "admin"}))
+	admin.Get("/users", handler)
+	app.Get("/administrator", handler)
+
+	if err := app.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `METHOD  PATH            COMPRESSION  CACHE  AUTH  RATELIMIT  BODYLIMIT
+GET     /admin/users    -            -      yes   -          -
+GET     /administrator  -            -      -     -          -
+`
+	if got := app.PolicyReport().String(); got != expected {
+		t.Fatalf("expected:\n%s\nbut got:\n%s", expected, got)
+	}
+
+	app = iris.New()
+	app.SetPartyMatcher(func(ctx iris.Context, p iris.Party) bool {
+		return ctx.GetHeader("X-Beta") != ""
+	})
+
+	beta := app.Party("/beta")
+	beta.UseRouter(iris.Compression)
+	beta.Get("/", handler)
+
+	if err := app.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	report := app.PolicyReport()
+	expected = `METHOD  PATH   COMPRESSION  CACHE  AUTH  RATELIMIT  BODYLIMIT
+GET     /beta  conditional  -      -     -          -
+`
+	if got := report.String(); got != expected {
+		t.Fatalf("expected:\n%s\nbut got:\n%s", expected, got)
+	}
+
+	if report[0].Has(context.PolicyCompression) {
+		t.Fatalf("expected a conditional compression policy")
+	}
+}
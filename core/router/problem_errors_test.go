@@ -0,0 +1,66 @@
+package router_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/httptest"
+	"github.com/kataras/iris/v12/middleware/recover"
+
+	"github.com/iris-contrib/httpexpect/v2"
+)
+
+func TestProblemErrors(t *testing.T) {
+	app := iris.New()
+	app.UseRouter(recover.New())
+	app.UseRouter(func(ctx iris.Context) {
+		ctx.SetID("f81d4fae-7dec-11d0-a765-00a0c91e6bf6")
+		ctx.Next()
+	})
+	app.UseProblemErrors(iris.ProblemErrors{
+		TypeBaseURI: "https://example.com/problems/",
+		Extensions: func(ctx iris.Context, p iris.Problem) {
+			p.Key("path", ctx.Path())
+		},
+	})
+
+	app.OnErrorCode(iris.StatusForbidden, func(ctx iris.Context) {
+		// does not write a body.
+	})
+	app.OnErrorCode(iris.StatusConflict, func(ctx iris.Context) {
+		ctx.WriteString("custom conflict")
+	})
+
+	app.Get("/bad", func(ctx iris.Context) {
+		ctx.StopWithPlainError(iris.StatusBadRequest, errors.New("invalid id"))
+	})
+	app.Get("/forbidden", func(ctx iris.Context) {
+		ctx.StopWithStatus(iris.StatusForbidden)
+	})
+	app.Get("/conflict", func(ctx iris.Context) {
+		ctx.StopWithStatus(iris.StatusConflict)
+	})
+	app.Get("/panic", func(ctx iris.Context) {
+		panic("database is down")
+	})
+
+	problem := httpexpect.ContentOpts{MediaType: "application/problem+json"}
+	e := httptest.New(t, app)
+
+	e.GET("/bad").Expect().Status(httptest.StatusBadRequest).JSON(problem).Equal(iris.Map{
+		"type":     "https://example.com/problems/400",
+		"title":    "Bad Request",
+		"status":   iris.StatusBadRequest,
+		"detail":   "invalid id",
+		"instance": "urn:uuid:f81d4fae-7dec-11d0-a765-00a0c91e6bf6",
+		"path":     "/bad",
+	})
+	e.GET("/forbidden").Expect().Status(httptest.StatusForbidden).JSON(problem).Object().
+		ValueEqual("type", "https://example.com/problems/403").ValueEqual("title", "Forbidden")
+	e.GET("/conflict").Expect().Status(httptest.StatusConflict).Body().Equal("custom conflict")
+	e.GET("/missing").Expect().Status(httptest.StatusNotFound).JSON(problem).Object().
+		ValueEqual("status", iris.StatusNotFound).ValueEqual("path", "/missing")
+	e.GET("/panic").Expect().Status(httptest.StatusInternalServerError).JSON(problem).Object().
+		ValueEqual("title", "Internal Server Error").NotContainsKey("detail").NotContainsKey("panic")
+}
@@ -47,6 +47,9 @@ type Route struct {
 	// temp storage, they're appended to the Handlers on build.
 	// Execution happens after Begin and main Handler(s), can be empty.
 	doneHandlers context.Handlers
+	// the number of the done handlers at the end of the Handlers,
+	// see `FinalHandlers`.
+	doneHandlersLen int
 
 	Path string `json:"path"` // the underline router's representation, i.e "/api/user/:id"
 	// FormattedPath all dynamic named parameters (if any) replaced with %v,
@@ -69,6 +72,13 @@ type Route struct {
 	// It's used ONLY for logging.
 	overlappedLink *Route
 
+	// Consumes holds the allowed request body content types, see `ConsumesOnly`.
+	Consumes []string `json:"consumes,omitempty"`
+	// ErrorCodes holds the error codes this route may respond with, see `Errors`.
+	ErrorCodes []context.ErrorCode `json:"errorCodes,omitempty"`
+	// Security holds the security requirements of this route, see `Secure`.
+	Security []context.SecurityRequirement `json:"security,omitempty"`
+
 	// Sitemap properties: https://www.sitemaps.org/protocol.html
 	NoSitemap  bool      // when this route should be hidden from sitemap.
 	LastMod    time.Time `json:"lastMod,omitempty"`
@@ -86,11 +96,11 @@ type Route struct {
 // NewRoute returns a new route based on its method,
 // subdomain, the path (unparsed or original),
 // handlers and the macro container which all routes should share.
-// It parses the path based on the "macros",
+// It parses the path based on the "macros", through the `macro.DefaultTemplateCache`,
 // handlers are being changed to validate the macros at serve time, if needed.
 func NewRoute(p Party, statusErrorCode int, method, subdomain, unparsedPath string,
 	handlers context.Handlers, macros macro.Macros) (*Route, error) {
-	tmpl, err := macro.Parse(unparsedPath, macros)
+	tmpl, err := macro.DefaultTemplateCache.Parse(unparsedPath, macros)
 	if err != nil {
 		return nil, err
 	}
@@ -262,6 +272,7 @@ func (r *Route) BuildHandlers() {
 	r.Handlers = append(r.builtinBeginHandlers, append(r.beginHandlers, r.Handlers...)...)
 	// append done handlers.
 	r.Handlers = append(r.Handlers, r.doneHandlers...)
+	r.doneHandlersLen += len(r.doneHandlers)
 	// reset the temp storage, so a second call of
 	// BuildHandlers will not re-add them (i.e RefreshRouter).
 	r.builtinBeginHandlers = r.builtinBeginHandlers[0:0]
@@ -269,6 +280,18 @@ func (r *Route) BuildHandlers() {
 	r.doneHandlers = r.doneHandlers[0:0]
 }
 
+// FinalHandlers returns the last handler passed on the route's registration
+// followed by the route's done handlers, so the route can be executed without its middleware.
+// See `Application.ServeRoute` too.
+func (r *Route) FinalHandlers() context.Handlers {
+	idx := len(r.Handlers) - r.doneHandlersLen - 1
+	if idx < 0 || idx >= len(r.Handlers) {
+		return r.Handlers
+	}
+
+	return r.Handlers[idx:]
+}
+
 // String returns the form of METHOD, SUBDOMAIN, TMPL PATH.
 func (r *Route) String() string {
 	start := r.GetTitle()
@@ -304,6 +327,119 @@ func (r *Route) SetName(newRouteName string) *Route {
 	return r
 }
 
+// ConsumesOnly sets the allowed request body content types of this route.
+// Requests with a body of a different content type are stopped
+// with 415 Unsupported Media Type, before any route handler runs.
+// The error body is negotiated by the client's Accept header
+// (application/problem+json, application/problem+xml or text/plain).
+//
+// Content types can be full media types, e.g. "application/json",
+// wildcards, e.g. "image/*" or structured syntax suffixes, e.g. "+json"
+// which accepts "application/vnd.api+json" too.
+// Requests without a body are always accepted.
+//
+// See `Party.ConsumesOnly` to set a default for all routes of a Party.
+// Returns the `Route` itself.
+func (r *Route) ConsumesOnly(contentTypes ...string) *Route {
+	r.Consumes = contentTypes
+	if len(contentTypes) == 0 {
+		r.RemoveHandler(newConsumesHandler(nil))
+		return r
+	}
+
+	r.UseOnce(newConsumesHandler(contentTypes))
+	return r
+}
+
+// Errors declares the error codes this route may respond with,
+// see `context.ErrorCode` and `OpenAPIResponses`.
+// It is a documentation property, it does not change the route's handlers.
+// Returns the `Route` itself.
+func (r *Route) Errors(codes ...context.ErrorCode) *Route {
+	r.ErrorCodes = append(r.ErrorCodes, codes...)
+	return r
+}
+
+// OpenAPIResponses returns the OpenAPI "responses" object of the declared error codes,
+// to be merged with the route operation's responses of an OpenAPI document,
+// see `Errors` and `context.OpenAPIResponses`.
+// The 401 and 403 responses of the security requirements (see `Secure`)
+// are included, unless they are declared by an error code.
+func (r *Route) OpenAPIResponses() map[string]interface{} {
+	responses := context.OpenAPIResponses(r.ErrorCodes)
+	if len(r.Security) == 0 {
+		return responses
+	}
+
+	challenge := map[string]interface{}{
+		context.WWWAuthenticateHeaderKey: map[string]interface{}{
+			"description": "The authentication challenge of the security requirements.",
+			"schema":      map[string]interface{}{"type": "string"},
+		},
+	}
+
+	if _, ok := responses["401"]; !ok {
+		responses["401"] = map[string]interface{}{
+			"description": http.StatusText(http.StatusUnauthorized),
+			"headers":     challenge,
+		}
+	}
+
+	for _, requirement := range r.Security {
+		if requirement.Scheme == context.SecurityBearer && len(requirement.Scopes) > 0 {
+			if _, ok := responses["403"]; !ok {
+				responses["403"] = map[string]interface{}{
+					"description": http.StatusText(http.StatusForbidden),
+					"headers":     challenge,
+				}
+			}
+			break
+		}
+	}
+
+	return responses
+}
+
+// Secure declares the security requirements of this route, any of them authenticates a request.
+// The 401 Unauthorized and 403 Forbidden responses of the route, e.g. by an auth guard,
+// send the WWW-Authenticate challenges of the requirements
+// (see `context.SecurityRequirement.Challenge`)
+// and the `OpenAPISecurity` and `OpenAPIResponses` methods describe them.
+//
+// See `Party.Secure` to set a default for all routes of a Party.
+// Returns the `Route` itself.
+//
+// Usage:
+//  app.Get("/orders", verifyJWT, listOrders).Secure(iris.SecurityRequirement{
+//      Scheme: iris.SecurityBearer,
+//      Realm:  "api",
+//      Scopes: []string{"orders:read"},
+//  })
+func (r *Route) Secure(requirements ...context.SecurityRequirement) *Route {
+	r.Security = requirements
+	if len(requirements) == 0 {
+		r.RemoveHandler(newSecurityHandler(nil))
+		return r
+	}
+
+	r.UseOnce(newSecurityHandler(requirements))
+	return r
+}
+
+// newSecurityHandler returns a handler which sets the security requirements of the request.
+func newSecurityHandler(requirements []context.SecurityRequirement) context.Handler {
+	return func(ctx *context.Context) {
+		ctx.SetSecurityRequirements(requirements...)
+		ctx.Next()
+	}
+}
+
+// OpenAPISecurity returns the OpenAPI "security" list of the route operation,
+// see `Secure` and `context.OpenAPISecuritySchemes` for the document's components.
+func (r *Route) OpenAPISecurity() []map[string][]string {
+	return context.OpenAPISecurity(r.Security)
+}
+
 // ExcludeSitemap excludes this route page from sitemap generator.
 // It sets the NoSitemap field to true.
 //
@@ -634,6 +770,11 @@ func (rd routeReadOnlyWrapper) MainHandlerIndex() int {
 	return rd.Route.MainHandlerIndex
 }
 
+// DoneHandlersLen returns the number of the route's done handlers.
+func (rd routeReadOnlyWrapper) DoneHandlersLen() int {
+	return rd.Route.doneHandlersLen
+}
+
 func (rd routeReadOnlyWrapper) Property(key string) (interface{}, bool) {
 	properties := rd.Route.Party.Properties()
 	if properties != nil {
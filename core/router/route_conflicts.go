@@ -0,0 +1,147 @@
+package router
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kataras/iris/v12/macro"
+)
+
+// RouteConflictType is the type of a `RouteConflict`.
+type RouteConflictType uint8
+
+const (
+	// RouteDuplicate is the conflict of two registrations of the same method, host and path,
+	// e.g. "/users" and "/users/". The latest replaces the previous one,
+	// use the `SetRegisterRule` method to skip or overlap them on purpose instead.
+	RouteDuplicate RouteConflictType = iota + 1
+	// RouteAmbiguous is the conflict of two routes with the same path
+	// and different path parameters, where the latest accepts all the values the previous one does,
+	// e.g. "/users/{id:int}" and a later "/users/{name:string}",
+	// so the previous route is never reached.
+	RouteAmbiguous
+	// RouteShadowedWildcard is the conflict of two wildcard routes with the same path prefix,
+	// e.g. "/files/{p:path}" and a later "/files/{any:path}",
+	// so the previous route is never reached.
+	RouteShadowedWildcard
+)
+
+// String returns the text representation of the conflict type.
+func (t RouteConflictType) String() string {
+	switch t {
+	case RouteDuplicate:
+		return "duplicate"
+	case RouteAmbiguous:
+		return "ambiguous"
+	case RouteShadowedWildcard:
+		return "shadowed wildcard"
+	default:
+		return ""
+	}
+}
+
+// RouteConflict describes two conflicting routes,
+// see the `Configuration.FailOnRouteConflicts` and `APIBuilder.GetRouteConflicts`.
+type RouteConflict struct {
+	Type RouteConflictType
+	// Route is the route which serves the requests.
+	Route *Route
+	// Shadowed is the previously registered route which is replaced or never reached.
+	Shadowed *Route
+}
+
+// Error completes the error interface.
+// It reports the source file and line of both routes.
+func (c RouteConflict) Error() string {
+	return fmt.Sprintf("%s route: %s (%s) is unreachable, it conflicts with: %s (%s)",
+		c.Type, c.Shadowed.String(), routeSource(c.Shadowed), c.Route.String(), routeSource(c.Route))
+}
+
+func routeSource(r *Route) string {
+	return fmt.Sprintf("%s:%d", r.SourceFileName, r.SourceLineNumber)
+}
+
+// GetRouteConflicts returns the conflicts of the registered routes,
+// they are reported on `Build` as warnings or as errors when
+// the `Configuration.FailOnRouteConflicts` is true.
+func (api *APIBuilder) GetRouteConflicts() []RouteConflict {
+	return api.routes.conflicts()
+}
+
+// conflicts returns the replaced duplicates of the still registered routes
+// and the routes which are never reached because of a later registered one.
+func (repo *repository) conflicts() (conflicts []RouteConflict) {
+	registered := make(map[*Route]struct{}, len(repo.routes))
+	for _, r := range repo.routes {
+		registered[r] = struct{}{}
+	}
+
+	for _, c := range repo.duplicates {
+		if _, ok := registered[c.Route]; ok {
+			conflicts = append(conflicts, c)
+		}
+	}
+
+	// group the routes with path parameters by their formatted path,
+	// keep the registration order as the latest route is the one which is tried first.
+	var keys []string
+	groups := make(map[string][]*Route)
+	for _, r := range repo.routes {
+		if len(r.tmpl.Params) == 0 {
+			continue
+		}
+
+		key := fmt.Sprintf("%d %s %s%s", r.StatusCode, r.Method, r.Subdomain, r.FormattedPath)
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], r)
+	}
+
+	for _, key := range keys {
+		routes := groups[key]
+		for i, prev := range routes {
+			for _, next := range routes[i+1:] {
+				if prev.tmpl.IsTrailing() != next.tmpl.IsTrailing() || !shadowsParams(prev.tmpl.Params, next.tmpl.Params) {
+					continue
+				}
+
+				typ := RouteAmbiguous
+				if next.tmpl.IsTrailing() {
+					typ = RouteShadowedWildcard
+				}
+
+				conflicts = append(conflicts, RouteConflict{Type: typ, Route: next, Shadowed: prev})
+				break
+			}
+		}
+	}
+
+	return
+}
+
+// shadowsParams reports whether the "next" path parameters
+// accept all the values that the "prev" ones do.
+func shadowsParams(prev, next []macro.TemplateParam) bool {
+	if len(prev) != len(next) {
+		return false
+	}
+
+	for i := range next {
+		if !next[i].CanEval() {
+			continue // {name}, {name:string} and {name:path} accept any value.
+		}
+
+		if prev[i].Type != next[i].Type || paramSignature(prev[i]) != paramSignature(next[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// paramSignature returns the parameter's source without its name,
+// e.g. ":int min(1)}" for "{id:int min(1)}".
+func paramSignature(p macro.TemplateParam) string {
+	return strings.TrimPrefix(strings.TrimPrefix(p.Src, "{"), p.Name)
+}
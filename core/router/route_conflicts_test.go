@@ -0,0 +1,77 @@
+package router_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/core/errgroup"
+	"github.com/kataras/iris/v12/core/router"
+)
+
+func TestRouteConflicts(t *testing.T) {
+	handler := func(ctx iris.Context) {}
+
+	app := iris.New().Configure(iris.WithFailOnRouteConflicts)
+	app.Get("/users", handler)
+	app.Get("/users/", handler)                 // duplicate.
+	app.Get("/users/{id:int}", handler)         // shadowed by the next one.
+	app.Get("/users/{name:string}", handler)    // ambiguous.
+	app.Get("/posts/{id:int}", handler)         // shadowed by the next one.
+	app.Get("/posts/{postID:int}", handler)     // ambiguous.
+	app.Get("/files/{p:path}", handler)         // shadowed by the next one.
+	app.Get("/files/{any:path}", handler)       // shadowed wildcard.
+	app.Get("/items/{name:string}", handler)    // OK, fallback.
+	app.Get("/items/{id:int min(1)}", handler)  // OK.
+	app.Get("/orders/{id:int min(1)}", handler) // OK, different functions.
+	app.Get("/orders/{id:int max(10)}", handler)
+	app.Post("/users", handler) // OK, different method.
+
+	expected := []struct {
+		typ             router.RouteConflictType
+		route, shadowed string
+	}{
+		{router.RouteDuplicate, "/users", "/users"},
+		{router.RouteAmbiguous, "/users/{name:string}", "/users/{id:int}"},
+		{router.RouteAmbiguous, "/posts/{postID:int}", "/posts/{id:int}"},
+		{router.RouteShadowedWildcard, "/files/{any:path}", "/files/{p:path}"},
+	}
+
+	conflicts := app.GetRouteConflicts()
+	if len(conflicts) != len(expected) {
+		t.Fatalf("expected %d conflicts but got %d: %v", len(expected), len(conflicts), conflicts)
+	}
+
+	for i, c := range conflicts {
+		if c.Type != expected[i].typ || c.Route.Tmpl().Src != expected[i].route || c.Shadowed.Tmpl().Src != expected[i].shadowed {
+			t.Fatalf("[%d] expected %s conflict of %s and %s but got: %s", i, expected[i].typ, expected[i].route, expected[i].shadowed, c)
+		}
+
+		if strings.Count(c.Error(), "route_conflicts_test.go:") != 2 {
+			t.Fatalf("[%d] expected the source lines of both routes but got: %s", i, c)
+		}
+	}
+
+	err := app.Build()
+	if err == nil {
+		t.Fatal("expected a build error")
+	}
+
+	var buildConflicts []router.RouteConflict
+	errgroup.Walk(err, func(_ interface{}, err error) {
+		if c, ok := err.(router.RouteConflict); ok {
+			buildConflicts = append(buildConflicts, c)
+		}
+	})
+	if len(buildConflicts) != len(expected) {
+		t.Fatalf("expected %d conflict errors but got: %v", len(expected), err)
+	}
+
+	// Warnings only, by default.
+	app = iris.New()
+	app.Get("/users", handler)
+	app.Get("/users", handler)
+	if err = app.Build(); err != nil {
+		t.Fatal(err)
+	}
+}
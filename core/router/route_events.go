@@ -0,0 +1,90 @@
+package router
+
+import (
+	"github.com/kataras/iris/v12/context"
+)
+
+// RouteEventType is the type of a `RouteEvent`.
+type RouteEventType uint8
+
+const (
+	// RouteOnline is fired when a route starts serving requests,
+	// i.e. on the first build or after a `RestoreStatus` and `RefreshRouter`.
+	RouteOnline RouteEventType = iota + 1
+	// RouteOffline is fired when a route stops serving requests,
+	// i.e. after a `SetStatusOffline` and `RefreshRouter`.
+	RouteOffline
+)
+
+// String returns the text representation of the event type.
+func (t RouteEventType) String() string {
+	switch t {
+	case RouteOnline:
+		return "online"
+	case RouteOffline:
+		return "offline"
+	default:
+		return ""
+	}
+}
+
+// RouteEvent holds the information of a route change,
+// see `Router.OnRouteChange` method.
+type RouteEvent struct {
+	Type RouteEventType
+	// Method is the HTTP method the route started or stopped serving,
+	// the route's current method may be different (e.g. "NONE" on offline routes).
+	Method string
+	Route  context.RouteReadOnly
+}
+
+// OnRouteChange registers one or more listeners which are fired
+// when routes go online or offline, on `BuildRouter` and `RefreshRouter`.
+// Useful to keep external service discovery registrations in sync with
+// the actual serving surface of the application.
+//
+// Listeners are fired synchronously, after the router is built.
+func (router *Router) OnRouteChange(listeners ...func(RouteEvent)) {
+	router.mu.Lock()
+	router.routeListeners = append(router.routeListeners, listeners...)
+	router.mu.Unlock()
+}
+
+// routeChanges compares the online routes with the ones of the previous build.
+// Should be called under lock.
+func (router *Router) routeChanges(routes []*Route) (events []RouteEvent) {
+	online := make(map[*Route]string, len(routes))
+	for _, r := range routes {
+		if r.IsOnline() {
+			online[r] = r.Method
+		}
+	}
+
+	for r, method := range router.onlineRoutes {
+		if newMethod, ok := online[r]; !ok || newMethod != method {
+			events = append(events, RouteEvent{Type: RouteOffline, Method: method, Route: r.ReadOnly})
+		}
+	}
+
+	for _, r := range routes { // keep registration order.
+		method, ok := online[r]
+		if !ok {
+			continue
+		}
+
+		if oldMethod, ok := router.onlineRoutes[r]; !ok || oldMethod != method {
+			events = append(events, RouteEvent{Type: RouteOnline, Method: method, Route: r.ReadOnly})
+		}
+	}
+
+	router.onlineRoutes = online
+	return
+}
+
+func fireRouteEvents(listeners []func(RouteEvent), events []RouteEvent) {
+	for _, evt := range events {
+		for _, listener := range listeners {
+			listener(evt)
+		}
+	}
+}
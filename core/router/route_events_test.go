@@ -0,0 +1,62 @@
+package router_test
+
+import (
+	"testing"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/core/router"
+)
+
+func TestOnRouteChange(t *testing.T) {
+	app := iris.New()
+	handler := func(ctx iris.Context) {}
+	users := app.Get("/users", handler)
+	app.Post("/users", handler)
+	app.Get("/offline", handler).SetStatusOffline()
+
+	var events []string
+	app.OnRouteChange(func(evt router.RouteEvent) {
+		events = append(events, evt.Type.String()+" "+evt.Method+" "+evt.Route.Path())
+	})
+
+	if err := app.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"online GET /users", "online POST /users"}
+	assertEvents(t, expected, events)
+
+	events = events[0:0]
+	users.SetStatusOffline()
+	if err := app.RefreshRouter(); err != nil {
+		t.Fatal(err)
+	}
+	assertEvents(t, []string{"offline GET /users"}, events)
+
+	events = events[0:0]
+	users.RestoreStatus()
+	if err := app.RefreshRouter(); err != nil {
+		t.Fatal(err)
+	}
+	assertEvents(t, []string{"online GET /users"}, events)
+
+	events = events[0:0]
+	if err := app.RefreshRouter(); err != nil {
+		t.Fatal(err)
+	}
+	assertEvents(t, nil, events)
+}
+
+func assertEvents(t *testing.T, expected, got []string) {
+	t.Helper()
+
+	if len(expected) != len(got) {
+		t.Fatalf("expected events: %v but got: %v", expected, got)
+	}
+
+	for i := range expected {
+		if expected[i] != got[i] {
+			t.Fatalf("[%d] expected event: %s but got: %s", i, expected[i], got[i])
+		}
+	}
+}
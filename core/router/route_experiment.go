@@ -0,0 +1,456 @@
+package router
+
+import (
+	"bytes"
+	stdContext "context"
+	"io"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/kataras/iris/v12/context"
+)
+
+// ExperimentArm tells which handler of a route experiment serves a request,
+// see `ExperimentSplitter` and `Route.Experiment`.
+type ExperimentArm uint8
+
+const (
+	// ExperimentControl serves the request through the control handler.
+	ExperimentControl ExperimentArm = iota
+	// ExperimentCandidate serves the request through the candidate handler.
+	ExperimentCandidate
+	// ExperimentShadow serves the request through the control handler
+	// and runs the candidate one in the background, on a copy of the request,
+	// comparing their outputs. The candidate's response is discarded.
+	// Only GET and HEAD requests are shadowed, see `Experiment.ShadowUnsafeMethods`.
+	ExperimentShadow
+)
+
+// ExperimentSplitter decides which handler of a route experiment serves a request.
+// See `SplitPercent` and `ShadowPercent` too.
+type ExperimentSplitter func(ctx *context.Context) ExperimentArm
+
+// SplitPercent returns an `ExperimentSplitter` which serves
+// the given percentage (0-100) of the traffic through the candidate handler.
+func SplitPercent(percent float64) ExperimentSplitter {
+	return percentSplitter(percent, ExperimentCandidate)
+}
+
+// ShadowPercent returns an `ExperimentSplitter` which runs the candidate handler
+// in shadow mode (see `ExperimentShadow`) for the given percentage (0-100) of the traffic.
+func ShadowPercent(percent float64) ExperimentSplitter {
+	return percentSplitter(percent, ExperimentShadow)
+}
+
+func percentSplitter(percent float64, arm ExperimentArm) ExperimentSplitter {
+	return func(*context.Context) ExperimentArm {
+		if rand.Float64()*100 < percent {
+			return arm
+		}
+
+		return ExperimentControl
+	}
+}
+
+// ExperimentArmStats holds the statistics of a handler of a route experiment.
+type ExperimentArmStats struct {
+	Requests uint64 `json:"requests"`
+	// Errors is the number of responses with a status code of 500 or higher,
+	// panics included.
+	Errors       uint64         `json:"errors"`
+	TotalLatency time.Duration  `json:"totalLatency"`
+	MaxLatency   time.Duration  `json:"maxLatency"`
+	StatusCodes  map[int]uint64 `json:"statusCodes"`
+}
+
+// AvgLatency returns the average latency of the handler.
+func (s ExperimentArmStats) AvgLatency() time.Duration {
+	if s.Requests == 0 {
+		return 0
+	}
+
+	return s.TotalLatency / time.Duration(s.Requests)
+}
+
+func (s *ExperimentArmStats) record(statusCode int, latency time.Duration) {
+	s.Requests++
+	if statusCode >= 500 {
+		s.Errors++
+	}
+
+	s.TotalLatency += latency
+	if latency > s.MaxLatency {
+		s.MaxLatency = latency
+	}
+
+	if s.StatusCodes == nil {
+		s.StatusCodes = make(map[int]uint64)
+	}
+	s.StatusCodes[statusCode]++
+}
+
+func (s ExperimentArmStats) clone() ExperimentArmStats {
+	statusCodes := make(map[int]uint64, len(s.StatusCodes))
+	for code, n := range s.StatusCodes {
+		statusCodes[code] = n
+	}
+
+	s.StatusCodes = statusCodes
+	return s
+}
+
+// ExperimentStats holds the statistics of a route experiment.
+type ExperimentStats struct {
+	Control   ExperimentArmStats `json:"control"`
+	Candidate ExperimentArmStats `json:"candidate"`
+	// Shadowed is the number of requests served in shadow mode.
+	Shadowed uint64 `json:"shadowed"`
+	// StatusMismatches is the number of shadowed requests
+	// that the candidate responded with a different status code than the control.
+	StatusMismatches uint64 `json:"statusMismatches"`
+	// BodyMismatches is the number of shadowed requests
+	// that the candidate responded with a different body than the control.
+	BodyMismatches uint64 `json:"bodyMismatches"`
+	// ShadowSkipped is the number of requests which were not shadowed
+	// because of their method (see `Experiment.ShadowUnsafeMethods`),
+	// their body was larger than the `Experiment.MaxBodySize`,
+	// the control's response body was larger than the `Experiment.MaxResponseSize`
+	// or the control's response was flushed (streamed).
+	ShadowSkipped uint64 `json:"shadowSkipped"`
+}
+
+// ExperimentMismatch describes a shadowed request which
+// the candidate handler responded differently than the control one.
+type ExperimentMismatch struct {
+	Method          string
+	Path            string
+	ControlStatus   int
+	CandidateStatus int
+	ControlBody     []byte
+	CandidateBody   []byte
+}
+
+// DefaultExperimentMaxBodySize is the default `Experiment.MaxBodySize`, 1MB.
+const DefaultExperimentMaxBodySize int64 = 1 << 20
+
+// Experiment runs a candidate handler next to the control one of a route,
+// for a slice of the traffic, and collects latency and status statistics
+// of both, helping validate refactors of hot handlers safely.
+// See `Route.Experiment`.
+type Experiment struct {
+	Route     *Route
+	Control   context.Handler
+	Candidate context.Handler
+	Splitter  ExperimentSplitter
+	// OnMismatch, if not nil, is called, from a different goroutine,
+	// on each shadowed request which the candidate responded differently than the control.
+	OnMismatch func(ExperimentMismatch)
+	// MaxBodySize is the maximum size of a request body which is copied
+	// for the candidate handler in shadow mode, larger requests are served
+	// by the control handler only (see `ExperimentStats.ShadowSkipped`).
+	// Defaults to `DefaultExperimentMaxBodySize`.
+	MaxBodySize int64
+	// MaxResponseSize is the maximum size of the control's response body
+	// which is kept for the comparison in shadow mode, larger responses are not compared.
+	// Defaults to `DefaultExperimentMaxBodySize`.
+	MaxResponseSize int64
+	// ShadowUnsafeMethods shadows the requests of any method, not just GET and HEAD.
+	// WARNING: the candidate handler runs in addition to the control one,
+	// so the side effects of POST, PUT, PATCH and DELETE requests
+	// (e.g. database writes, emails and payments) happen twice.
+	// Enable it only when the candidate is free of side effects (e.g. it writes to a dry-run store).
+	// Defaults to false.
+	ShadowUnsafeMethods bool
+	// ShadowValues is the allow-list of the request values (see `Context.Values`)
+	// which are copied to the candidate's context in shadow mode, e.g. the authenticated user.
+	// The values are shared by reference with the control handler's request,
+	// so the candidate handler should not modify them. Defaults to none.
+	ShadowValues []string
+
+	mu    sync.Mutex
+	stats ExperimentStats
+}
+
+// Experiment replaces the route's main handler with an `Experiment`
+// which serves the requests through the "control" or the "candidate" handler,
+// as the "splitter" decides, and records their statistics.
+// If "control" is nil then the route's main handler is used instead.
+// It should be called before the application's build.
+//
+// Usage:
+//  exp := app.Get("/users/{id}", getUser).Experiment(nil, getUserV2, router.ShadowPercent(10))
+//  exp.ShadowValues = []string{"user"}
+//  app.Get("/debug/experiments/users", func(ctx iris.Context) {
+//      ctx.JSON(exp.Stats())
+//  })
+func (r *Route) Experiment(control, candidate context.Handler, splitter ExperimentSplitter) *Experiment {
+	if r.MainHandlerIndex < 0 || r.MainHandlerIndex >= len(r.Handlers) {
+		return nil
+	}
+
+	if control == nil {
+		control = r.Handlers[r.MainHandlerIndex]
+	}
+
+	if splitter == nil {
+		splitter = SplitPercent(0)
+	}
+
+	e := &Experiment{
+		Route:           r,
+		Control:         control,
+		Candidate:       candidate,
+		Splitter:        splitter,
+		MaxBodySize:     DefaultExperimentMaxBodySize,
+		MaxResponseSize: DefaultExperimentMaxBodySize,
+	}
+
+	r.Handlers[r.MainHandlerIndex] = e.Handler
+	return e
+}
+
+// Stats returns a copy of the experiment's statistics.
+func (e *Experiment) Stats() ExperimentStats {
+	e.mu.Lock()
+	stats := e.stats
+	e.mu.Unlock()
+
+	stats.Control = stats.Control.clone()
+	stats.Candidate = stats.Candidate.clone()
+	return stats
+}
+
+// Reset clears the experiment's statistics.
+func (e *Experiment) Reset() {
+	e.mu.Lock()
+	e.stats = ExperimentStats{}
+	e.mu.Unlock()
+}
+
+// Handler serves a request of the experiment's route.
+func (e *Experiment) Handler(ctx *context.Context) {
+	switch e.Splitter(ctx) {
+	case ExperimentCandidate:
+		e.serve(ctx, e.Candidate, &e.stats.Candidate)
+	case ExperimentShadow:
+		e.shadow(ctx)
+	default:
+		e.serve(ctx, e.Control, &e.stats.Control)
+	}
+}
+
+func (e *Experiment) serve(ctx *context.Context, h context.Handler, stats *ExperimentArmStats) {
+	start := time.Now()
+	defer func() {
+		latency := time.Since(start)
+		statusCode := ctx.GetStatusCode()
+		if rec := recover(); rec != nil {
+			statusCode = http.StatusInternalServerError
+			defer panic(rec)
+		}
+
+		e.mu.Lock()
+		stats.record(statusCode, latency)
+		e.mu.Unlock()
+	}()
+
+	h(ctx)
+}
+
+func (e *Experiment) shadow(ctx *context.Context) {
+	if method := ctx.Method(); !e.ShadowUnsafeMethods && method != http.MethodGet && method != http.MethodHead {
+		e.skipShadow(ctx)
+		return
+	}
+
+	var body []byte
+	if r := ctx.Request(); r.Body != nil && r.Body != http.NoBody {
+		var err error
+		if body, err = io.ReadAll(io.LimitReader(r.Body, e.MaxBodySize+1)); err != nil {
+			ctx.StopWithError(http.StatusBadRequest, err)
+			return
+		}
+
+		if int64(len(body)) > e.MaxBodySize {
+			// Too large to keep a copy, serve the rest of it through the control handler only.
+			r.Body = struct {
+				io.Reader
+				io.Closer
+			}{io.MultiReader(bytes.NewReader(body), r.Body), r.Body}
+
+			e.skipShadow(ctx)
+			return
+		}
+
+		r.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	shadowCtx, w := e.newShadowContext(ctx, body)
+
+	var controlBody []byte
+	if rec, ok := ctx.IsRecording(); ok {
+		offset := len(rec.Body())
+		e.serve(ctx, e.Control, &e.stats.Control)
+		if offset > len(rec.Body()) { // reset by the control handler.
+			offset = 0
+		}
+		controlBody = append([]byte(nil), rec.Body()[offset:]...)
+	} else {
+		// Do not buffer the response, keep a copy of it while it's written.
+		tee := &shadowTeeWriter{ResponseWriter: ctx.ResponseWriter(), maxSize: e.MaxResponseSize}
+		ctx.ResetResponseWriter(tee)
+		e.serve(ctx, e.Control, &e.stats.Control)
+		switch rec, ok := ctx.IsRecording(); {
+		case ok: // recorded by the control handler.
+			controlBody = append([]byte(nil), rec.Body()...)
+		case tee.streamed:
+			shadowCtx.ResponseWriter().EndResponse()
+			e.mu.Lock()
+			e.stats.ShadowSkipped++
+			e.mu.Unlock()
+			return
+		default:
+			controlBody = tee.body.Bytes()
+		}
+	}
+
+	controlStatus := ctx.GetStatusCode()
+
+	go func() {
+		start := time.Now()
+		candidateStatus := http.StatusInternalServerError
+		func() {
+			defer func() {
+				if rec := recover(); rec != nil {
+					shadowCtx.Application().Logger().Warnf("route experiment: %s: candidate panic: %v", e.Route.Name, rec)
+				}
+			}()
+
+			shadowCtx.Do(context.Handlers{e.Candidate})
+			candidateStatus = shadowCtx.GetStatusCode()
+			shadowCtx.ResponseWriter().FlushResponse()
+		}()
+		latency := time.Since(start)
+		shadowCtx.ResponseWriter().EndResponse()
+
+		statusMismatch := candidateStatus != controlStatus
+		bodyMismatch := !bytes.Equal(w.body.Bytes(), controlBody)
+
+		e.mu.Lock()
+		e.stats.Candidate.record(candidateStatus, latency)
+		e.stats.Shadowed++
+		if statusMismatch {
+			e.stats.StatusMismatches++
+		}
+		if bodyMismatch {
+			e.stats.BodyMismatches++
+		}
+		e.mu.Unlock()
+
+		if (statusMismatch || bodyMismatch) && e.OnMismatch != nil {
+			e.OnMismatch(ExperimentMismatch{
+				Method:          shadowCtx.Method(),
+				Path:            shadowCtx.Path(),
+				ControlStatus:   controlStatus,
+				CandidateStatus: candidateStatus,
+				ControlBody:     controlBody,
+				CandidateBody:   w.body.Bytes(),
+			})
+		}
+	}()
+}
+
+// skipShadow serves a request which is not shadowed through the control handler.
+func (e *Experiment) skipShadow(ctx *context.Context) {
+	e.mu.Lock()
+	e.stats.ShadowSkipped++
+	e.mu.Unlock()
+
+	e.serve(ctx, e.Control, &e.stats.Control)
+}
+
+// newShadowContext returns a new context, detached from the request's lifecycle,
+// which runs the candidate handler on a copy of the request
+// and writes its response to a `shadowWriter`.
+func (e *Experiment) newShadowContext(ctx *context.Context, body []byte) (*context.Context, *shadowWriter) {
+	r := ctx.Request().Clone(stdContext.Background())
+	if body != nil {
+		r.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	w := &shadowWriter{header: make(http.Header)}
+	shadowCtx := context.NewContext(ctx.Application())
+	shadowCtx.BeginRequest(w, r)
+	shadowCtx.SetCurrentRoute(ctx.GetCurrentRoute())
+	for _, entry := range ctx.Params().Store {
+		shadowCtx.Params().Store.Set(entry.Key, entry.ValueRaw)
+	}
+	for _, key := range e.ShadowValues {
+		if entry, ok := ctx.Values().GetEntry(key); ok {
+			shadowCtx.Values().Set(entry.Key, entry.ValueRaw)
+		}
+	}
+
+	return shadowCtx, w
+}
+
+// shadowWriter is the http.ResponseWriter of the candidate handler in shadow mode,
+// it keeps the response in memory.
+type shadowWriter struct {
+	header     http.Header
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (w *shadowWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *shadowWriter) WriteHeader(statusCode int) {
+	if w.statusCode == 0 {
+		w.statusCode = statusCode
+	}
+}
+
+func (w *shadowWriter) Write(b []byte) (int, error) {
+	return w.body.Write(b)
+}
+
+// shadowTeeWriter is the response writer of the control handler in shadow mode,
+// it keeps a copy of the response body, up to a max size, while it's written to the client.
+// A flushed response or a larger one is marked as streamed, it's not compared.
+type shadowTeeWriter struct {
+	context.ResponseWriter
+	maxSize  int64
+	body     bytes.Buffer
+	streamed bool
+}
+
+func (w *shadowTeeWriter) Write(b []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(b)
+	if !w.streamed {
+		if int64(w.body.Len()+n) > w.maxSize {
+			w.streamed = true
+			w.body.Reset()
+		} else {
+			w.body.Write(b[:n])
+		}
+	}
+
+	return n, err
+}
+
+func (w *shadowTeeWriter) Flusher() (http.Flusher, bool) {
+	if _, ok := w.ResponseWriter.Flusher(); !ok {
+		return nil, false
+	}
+
+	return w, true
+}
+
+func (w *shadowTeeWriter) Flush() {
+	w.streamed = true
+	w.ResponseWriter.Flush()
+}
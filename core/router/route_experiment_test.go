@@ -0,0 +1,165 @@
+package router_test
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/core/router"
+	"github.com/kataras/iris/v12/httptest"
+)
+
+func TestRouteExperiment(t *testing.T) {
+	app := iris.New()
+
+	control := func(ctx iris.Context) {
+		ctx.Writef("v1:%s", ctx.Params().Get("id"))
+	}
+	candidate := func(ctx iris.Context) {
+		body, _ := ctx.GetBody()
+		ctx.Writef("v2:%s:%s", ctx.Params().Get("id"), body)
+	}
+
+	split := app.Get("/split/{id}", control).Experiment(nil, candidate, router.SplitPercent(100))
+
+	mismatches := make(chan router.ExperimentMismatch, 1)
+	shadow := app.Post("/shadow/{id}", control).Experiment(nil, candidate, router.ShadowPercent(100))
+	shadow.ShadowUnsafeMethods = true
+	shadow.OnMismatch = func(m router.ExperimentMismatch) {
+		mismatches <- m
+	}
+
+	e := httptest.New(t, app)
+	e.GET("/split/42").Expect().Status(httptest.StatusOK).Body().Equal("v2:42:")
+
+	stats := split.Stats()
+	if stats.Candidate.Requests != 1 || stats.Control.Requests != 0 || stats.Candidate.StatusCodes[200] != 1 {
+		t.Fatalf("unexpected split stats: %#+v", stats)
+	}
+
+	// The control serves the request, the candidate runs in the background.
+	e.POST("/shadow/42").WithText("body").Expect().Status(httptest.StatusOK).Body().Equal("v1:42")
+
+	select {
+	case m := <-mismatches:
+		if expected, got := "v1:42", string(m.ControlBody); expected != got {
+			t.Fatalf("expected control body: %q but got: %q", expected, got)
+		}
+		if expected, got := "v2:42:body", string(m.CandidateBody); expected != got {
+			t.Fatalf("expected candidate body: %q but got: %q", expected, got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected a mismatch")
+	}
+
+	stats = shadow.Stats()
+	if stats.Shadowed != 1 || stats.BodyMismatches != 1 || stats.StatusMismatches != 0 ||
+		stats.Control.Requests != 1 || stats.Candidate.Requests != 1 {
+		t.Fatalf("unexpected shadow stats: %#+v", stats)
+	}
+
+	shadow.Reset()
+	if stats = shadow.Stats(); stats.Shadowed != 0 {
+		t.Fatalf("expected reset stats but got: %#+v", stats)
+	}
+}
+
+func TestRouteExperimentShadowLimits(t *testing.T) {
+	app := iris.New()
+	app.Use(func(ctx iris.Context) {
+		ctx.Values().Set("user", "kataras")
+		ctx.Values().Set("secret", "token")
+		ctx.Next()
+	})
+
+	control := func(ctx iris.Context) {
+		body, _ := ctx.GetBody()
+		ctx.Writef("%s:%s", ctx.Values().GetString("user"), body)
+	}
+	candidate := func(ctx iris.Context) {
+		body, _ := ctx.GetBody()
+		ctx.Writef("%s:%s:%s", ctx.Values().GetString("user"), ctx.Values().GetString("secret"), body)
+	}
+
+	mismatches := make(chan router.ExperimentMismatch, 1)
+	shadow := app.Post("/", control).Experiment(nil, candidate, router.ShadowPercent(100))
+	shadow.MaxBodySize = 8
+	shadow.ShadowUnsafeMethods = true
+	shadow.ShadowValues = []string{"user"}
+	shadow.OnMismatch = func(m router.ExperimentMismatch) {
+		mismatches <- m
+	}
+
+	e := httptest.New(t, app)
+	// Only the allowed values are copied to the candidate.
+	e.POST("/").WithText("body").Expect().Status(httptest.StatusOK).Body().Equal("kataras:body")
+	select {
+	case m := <-mismatches:
+		if expected, got := "kataras::body", string(m.CandidateBody); expected != got {
+			t.Fatalf("expected candidate body: %q but got: %q", expected, got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected a mismatch")
+	}
+
+	// A larger body is served by the control handler only, as it is.
+	e.POST("/").WithText("a larger body").Expect().Status(httptest.StatusOK).Body().Equal("kataras:a larger body")
+
+	stats := shadow.Stats()
+	if stats.Shadowed != 1 || stats.ShadowSkipped != 1 || stats.Control.Requests != 2 || stats.Candidate.Requests != 1 {
+		t.Fatalf("unexpected shadow stats: %#+v", stats)
+	}
+}
+
+func TestRouteExperimentShadowSafe(t *testing.T) {
+	app := iris.New()
+
+	var candidateCalls uint32
+	control := func(ctx iris.Context) {
+		ctx.WriteString("v1")
+		if ctx.URLParamExists("stream") {
+			ctx.ResponseWriter().Flush()
+			ctx.WriteString(":streamed")
+		}
+	}
+	candidate := func(ctx iris.Context) {
+		atomic.AddUint32(&candidateCalls, 1)
+		ctx.WriteString("v2")
+	}
+
+	mismatches := make(chan router.ExperimentMismatch, 1)
+	splitter := router.ShadowPercent(100)
+	shadowGet := app.Get("/", control).Experiment(nil, candidate, splitter)
+	shadowGet.OnMismatch = func(m router.ExperimentMismatch) {
+		mismatches <- m
+	}
+	shadowPost := app.Post("/", control).Experiment(nil, candidate, splitter)
+
+	e := httptest.New(t, app)
+	e.GET("/").Expect().Status(httptest.StatusOK).Body().Equal("v1")
+	select {
+	case m := <-mismatches:
+		if expected, got := "v1", string(m.ControlBody); expected != got {
+			t.Fatalf("expected control body: %q but got: %q", expected, got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected a mismatch")
+	}
+
+	// Unsafe methods are not shadowed by default.
+	e.POST("/").Expect().Status(httptest.StatusOK).Body().Equal("v1")
+	if stats := shadowPost.Stats(); stats.ShadowSkipped != 1 || stats.Shadowed != 0 || stats.Control.Requests != 1 {
+		t.Fatalf("unexpected shadow stats: %#+v", stats)
+	}
+
+	// Streamed responses are not compared.
+	e.GET("/").WithQuery("stream", true).Expect().Status(httptest.StatusOK).Body().Equal("v1:streamed")
+	if stats := shadowGet.Stats(); stats.ShadowSkipped != 1 || stats.Shadowed != 1 || stats.Control.Requests != 2 {
+		t.Fatalf("unexpected shadow stats: %#+v", stats)
+	}
+
+	if expected, got := uint32(1), atomic.LoadUint32(&candidateCalls); expected != got {
+		t.Fatalf("expected %d candidate calls but got: %d", expected, got)
+	}
+}
@@ -39,6 +39,11 @@ type Router struct {
 	// key = subdomain
 	// value = closest of static routes, filled on `BuildRouter/RefreshRouter`.
 	closestPaths map[string]*closestmatch.ClosestMatch
+
+	// see `OnRouteChange`.
+	routeListeners []func(RouteEvent)
+	// the online routes and their methods of the last build.
+	onlineRoutes map[*Route]string
 }
 
 // NewRouter returns a new empty Router.
@@ -188,6 +193,14 @@ func (router *Router) BuildRouter(cPool *context.Pool, requestHandler RequestHan
 		return err
 	}
 
+	var (
+		listeners []func(RouteEvent)
+		events    []RouteEvent
+	)
+	// fire the route events after unlock,
+	// so listeners can access the router.
+	defer func() { fireRouteEvents(listeners, events) }()
+
 	router.mu.Lock()
 	defer router.mu.Unlock()
 
@@ -248,6 +261,11 @@ func (router *Router) BuildRouter(cPool *context.Pool, requestHandler RequestHan
 		router.closestPaths[subdomain] = closestmatch.New(paths, []int{3, 4, 6})
 	}
 
+	if len(router.routeListeners) > 0 {
+		listeners = router.routeListeners
+		events = router.routeChanges(router.routesProvider.GetRoutes())
+	}
+
 	return nil
 }
 
@@ -0,0 +1,89 @@
+package router_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/httptest"
+	"github.com/kataras/iris/v12/middleware/basicauth"
+)
+
+func TestSecure(t *testing.T) {
+	bearer := iris.SecurityRequirement{Scheme: iris.SecurityBearer, Realm: "api", Scopes: []string{"orders:read", "orders:write"}}
+	apiKey := iris.SecurityRequirement{Scheme: iris.SecurityAPIKey, Name: "key"}
+
+	guard := func(ctx iris.Context) {
+		switch ctx.GetHeader("Authorization") {
+		case "":
+			ctx.StopWithStatus(iris.StatusUnauthorized)
+		case "Bearer reader":
+			ctx.StopWithError(iris.StatusForbidden, errors.New("missing scope"))
+		case "Bearer ok":
+			ctx.Next()
+		default:
+			ctx.StopWithError(iris.StatusUnauthorized, context.PrivateError(errors.New("invalid token")))
+		}
+	}
+	ok := func(ctx iris.Context) { ctx.WriteString("ok") }
+
+	app := iris.New()
+	api := app.Party("/api", guard)
+	api.Secure(bearer, apiKey)
+	route := api.Get("/orders", ok)
+	api.Get("/public", ok).Secure()
+
+	admin := app.Party("/admin", basicauth.Default(map[string]string{"admin": "admin"}))
+	admin.Secure(iris.SecurityRequirement{Scheme: iris.SecurityBasic, Realm: "ignored"})
+	admin.Get("/", ok)
+
+	plain := app.Party("/plain", guard)
+	plain.Get("/", ok).Secure(iris.SecurityRequirement{Scheme: iris.SecurityBasic, Realm: "plain"})
+
+	e := httptest.New(t, app)
+	e.GET("/api/orders").Expect().Status(httptest.StatusUnauthorized).
+		Header("WWW-Authenticate").Equal(`Bearer realm="api"`)
+	e.GET("/api/orders").Expect().Headers().Value("Www-Authenticate").Array().Equal([]string{
+		`Bearer realm="api"`,
+		`APIKey in="header", name="X-API-Key"`,
+	})
+	e.GET("/api/orders").WithHeader("Authorization", "Bearer expired").Expect().Status(httptest.StatusUnauthorized).
+		Header("WWW-Authenticate").Equal(`Bearer realm="api", error="invalid_token"`)
+	e.GET("/api/orders").WithHeader("Authorization", "Bearer reader").Expect().Status(httptest.StatusForbidden).
+		Header("WWW-Authenticate").Equal(`Bearer realm="api", error="insufficient_scope", scope="orders:read orders:write"`)
+	e.GET("/api/orders").WithHeader("Authorization", "Bearer ok").Expect().Status(httptest.StatusOK).
+		Header("WWW-Authenticate").Empty()
+	e.GET("/api/public").Expect().Status(httptest.StatusUnauthorized).
+		Header("WWW-Authenticate").Empty()
+	// The guard's own challenge is kept.
+	e.GET("/admin").Expect().Status(httptest.StatusUnauthorized).
+		Header("WWW-Authenticate").NotEqual(`Basic realm="ignored", charset="UTF-8"`)
+	e.GET("/plain").Expect().Status(httptest.StatusUnauthorized).
+		Header("WWW-Authenticate").Equal(`Basic realm="plain", charset="UTF-8"`)
+
+	expectedSecurity := []map[string][]string{
+		{"bearerAuth": {"orders:read", "orders:write"}},
+		{"key": {}},
+	}
+	if got := route.OpenAPISecurity(); !reflect.DeepEqual(got, expectedSecurity) {
+		t.Fatalf("expected security: %v but got: %v", expectedSecurity, got)
+	}
+
+	responses := route.OpenAPIResponses()
+	if _, ok := responses["401"]; !ok {
+		t.Fatalf("expected a 401 response")
+	}
+	if _, ok := responses["403"]; !ok {
+		t.Fatalf("expected a 403 response of the bearer scopes")
+	}
+
+	expectedSchemes := map[string]interface{}{
+		"bearerAuth": map[string]interface{}{"type": "http", "scheme": "bearer"},
+		"key":        map[string]interface{}{"type": "apiKey", "in": "header", "name": "X-API-Key"},
+	}
+	if got := context.OpenAPISecuritySchemes(route.Security); !reflect.DeepEqual(got, expectedSchemes) {
+		t.Fatalf("expected schemes: %v but got: %v", expectedSchemes, got)
+	}
+}
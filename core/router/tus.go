@@ -0,0 +1,378 @@
+package router
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kataras/iris/v12/context"
+)
+
+// TusVersion is the supported version of the tus resumable upload protocol.
+// See https://tus.io/protocols/resumable-upload.html.
+const TusVersion = "1.0.0"
+
+const (
+	tusResumableHeaderKey = "Tus-Resumable"
+	tusOffsetContentType  = "application/offset+octet-stream"
+)
+
+// ErrTusUploadNotFound should be returned by a `TusStore` when an upload does not exist.
+var ErrTusUploadNotFound = errors.New("tus: upload not found")
+
+type (
+	// TusUpload holds the information of a resumable upload.
+	TusUpload struct {
+		ID string `json:"id"`
+		// Size is the total size of the upload in bytes, the Upload-Length header.
+		Size int64 `json:"size"`
+		// Offset is the number of bytes received so far.
+		Offset int64 `json:"offset"`
+		// Metadata is the decoded Upload-Metadata header.
+		Metadata map[string]string `json:"metadata,omitempty"`
+		// ExpiresAt is the time the upload expires, zero for no expiration.
+		ExpiresAt time.Time `json:"expiresAt,omitempty"`
+	}
+
+	// TusStore is the storage of the resumable uploads.
+	// See the middleware/tus package for a file system implementation.
+	TusStore interface {
+		// Create should create a new, empty, upload.
+		Create(upload TusUpload) error
+		// Get should return the upload of the given "id"
+		// or `ErrTusUploadNotFound`.
+		Get(id string) (TusUpload, error)
+		// WriteChunk should append the "r" contents to the upload of the given "id",
+		// starting from the given "offset". It returns the number of bytes written,
+		// the upload's offset should be increased by that number even on errors.
+		WriteChunk(id string, offset int64, r io.Reader) (int64, error)
+		// Delete should remove the upload of the given "id".
+		Delete(id string) error
+	}
+
+	// TusOptions holds the optional settings of the `Party.Tus` method.
+	TusOptions struct {
+		// MaxSize is the maximum size of an upload in bytes, the Tus-Max-Size header.
+		// Defaults to zero, no limit.
+		MaxSize int64
+		// Expiration is the duration an incomplete upload is kept.
+		// Defaults to zero, uploads never expire.
+		Expiration time.Duration
+		// OnComplete, if not nil, it is fired on the request which completes an upload.
+		OnComplete func(ctx *context.Context, upload TusUpload)
+	}
+)
+
+// Tus registers the routes of the tus resumable upload protocol
+// (core, creation, expiration and termination extensions)
+// under the "requestPath" and the "requestPath/{id}".
+// The uploads are stored to the given "store".
+//
+// Usage:
+//  app.Tus("/files", tus.NewDirStore("./uploads"), router.TusOptions{
+//   Expiration: 24 * time.Hour,
+//   OnComplete: func(ctx iris.Context, upload router.TusUpload) {...},
+//  })
+func (api *APIBuilder) Tus(requestPath string, store TusStore, opts ...TusOptions) []*Route {
+	h := &tusHandler{store: store, locks: make(map[string]*tusLock)}
+	if len(opts) > 0 {
+		h.opts = opts[0]
+	}
+
+	idPath := joinPath(requestPath, "/{id:string}")
+	return []*Route{
+		api.Handle(http.MethodOptions, requestPath, h.options),
+		api.Handle(http.MethodPost, requestPath, h.create),
+		api.Handle(http.MethodHead, idPath, h.head),
+		api.Handle(http.MethodPatch, idPath, h.patch),
+		api.Handle(http.MethodDelete, idPath, h.terminate),
+	}
+}
+
+type tusHandler struct {
+	store TusStore
+	opts  TusOptions
+
+	mu    sync.Mutex
+	locks map[string]*tusLock // the locks of the uploads which are written.
+}
+
+type tusLock struct {
+	sync.Mutex
+	refs int
+}
+
+// lock locks the upload of the given "id", so its concurrent PATCH requests
+// are served one by one, and returns the function which unlocks it.
+func (h *tusHandler) lock(id string) func() {
+	h.mu.Lock()
+	l, ok := h.locks[id]
+	if !ok {
+		l = new(tusLock)
+		h.locks[id] = l
+	}
+	l.refs++
+	h.mu.Unlock()
+
+	l.Lock()
+	return func() {
+		l.Unlock()
+
+		h.mu.Lock()
+		if l.refs--; l.refs == 0 {
+			delete(h.locks, id)
+		}
+		h.mu.Unlock()
+	}
+}
+
+func (h *tusHandler) extensions() string {
+	if h.opts.Expiration > 0 {
+		return "creation,expiration,termination"
+	}
+
+	return "creation,termination"
+}
+
+func (h *tusHandler) options(ctx *context.Context) {
+	ctx.Header(tusResumableHeaderKey, TusVersion)
+	ctx.Header("Tus-Version", TusVersion)
+	ctx.Header("Tus-Extension", h.extensions())
+	if h.opts.MaxSize > 0 {
+		ctx.Header("Tus-Max-Size", strconv.FormatInt(h.opts.MaxSize, 10))
+	}
+
+	ctx.StatusCode(http.StatusNoContent)
+}
+
+// begin validates the client's protocol version.
+func (h *tusHandler) begin(ctx *context.Context) bool {
+	ctx.Header(tusResumableHeaderKey, TusVersion)
+	ctx.Header("Cache-Control", "no-store")
+
+	if ctx.GetHeader(tusResumableHeaderKey) != TusVersion {
+		ctx.Header("Tus-Version", TusVersion)
+		ctx.StopWithStatus(http.StatusPreconditionFailed)
+		return false
+	}
+
+	return true
+}
+
+func (h *tusHandler) create(ctx *context.Context) {
+	if !h.begin(ctx) {
+		return
+	}
+
+	size, err := strconv.ParseInt(ctx.GetHeader("Upload-Length"), 10, 64)
+	if err != nil || size < 0 {
+		ctx.StopWithText(http.StatusBadRequest, "invalid Upload-Length header")
+		return
+	}
+
+	if h.opts.MaxSize > 0 && size > h.opts.MaxSize {
+		ctx.StopWithStatus(http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	metadata, err := parseTusMetadata(ctx.GetHeader("Upload-Metadata"))
+	if err != nil {
+		ctx.StopWithText(http.StatusBadRequest, "invalid Upload-Metadata header")
+		return
+	}
+
+	id := make([]byte, 16)
+	if _, err = rand.Read(id); err != nil {
+		ctx.StopWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	upload := TusUpload{
+		ID:       hex.EncodeToString(id),
+		Size:     size,
+		Metadata: metadata,
+	}
+	if h.opts.Expiration > 0 {
+		upload.ExpiresAt = time.Now().Add(h.opts.Expiration)
+	}
+
+	if err = h.store.Create(upload); err != nil {
+		ctx.StopWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	h.writeExpires(ctx, upload)
+	ctx.Header("Location", strings.TrimSuffix(ctx.Path(), "/")+"/"+upload.ID)
+	ctx.StatusCode(http.StatusCreated)
+
+	if size == 0 && h.opts.OnComplete != nil {
+		h.opts.OnComplete(ctx, upload)
+	}
+}
+
+func (h *tusHandler) writeExpires(ctx *context.Context, upload TusUpload) {
+	if !upload.ExpiresAt.IsZero() {
+		ctx.Header("Upload-Expires", upload.ExpiresAt.UTC().Format(http.TimeFormat))
+	}
+}
+
+// get returns the upload of the current request, expired uploads are removed.
+func (h *tusHandler) get(ctx *context.Context) (TusUpload, bool) {
+	upload, err := h.store.Get(ctx.Params().Get("id"))
+	if err != nil {
+		if err == ErrTusUploadNotFound {
+			ctx.StopWithStatus(http.StatusNotFound)
+		} else {
+			ctx.StopWithError(http.StatusInternalServerError, err)
+		}
+
+		return upload, false
+	}
+
+	if !upload.ExpiresAt.IsZero() && upload.Offset < upload.Size && time.Now().After(upload.ExpiresAt) {
+		h.store.Delete(upload.ID)
+		ctx.StopWithStatus(http.StatusGone)
+		return upload, false
+	}
+
+	return upload, true
+}
+
+func (h *tusHandler) head(ctx *context.Context) {
+	if !h.begin(ctx) {
+		return
+	}
+
+	upload, ok := h.get(ctx)
+	if !ok {
+		return
+	}
+
+	ctx.Header("Upload-Offset", strconv.FormatInt(upload.Offset, 10))
+	ctx.Header("Upload-Length", strconv.FormatInt(upload.Size, 10))
+	if len(upload.Metadata) > 0 {
+		ctx.Header("Upload-Metadata", formatTusMetadata(upload.Metadata))
+	}
+	h.writeExpires(ctx, upload)
+	ctx.StatusCode(http.StatusOK)
+}
+
+func (h *tusHandler) patch(ctx *context.Context) {
+	if !h.begin(ctx) {
+		return
+	}
+
+	if ctx.GetContentTypeRequested() != tusOffsetContentType {
+		ctx.StopWithStatus(http.StatusUnsupportedMediaType)
+		return
+	}
+
+	offset, err := strconv.ParseInt(ctx.GetHeader("Upload-Offset"), 10, 64)
+	if err != nil || offset < 0 {
+		ctx.StopWithText(http.StatusBadRequest, "invalid Upload-Offset header")
+		return
+	}
+
+	// The offset is checked and updated under the upload's lock,
+	// otherwise concurrent requests of the same offset would interleave their writes.
+	unlock := h.lock(ctx.Params().Get("id"))
+	defer unlock()
+
+	upload, ok := h.get(ctx)
+	if !ok {
+		return
+	}
+
+	if offset != upload.Offset {
+		ctx.StopWithStatus(http.StatusConflict)
+		return
+	}
+
+	if length := ctx.GetContentLength(); length > 0 && offset+length > upload.Size {
+		ctx.StopWithStatus(http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	// Note: on errors (e.g. client disconnected) the received bytes are kept,
+	// the client should ask for the current offset through a HEAD request and resume.
+	n, err := h.store.WriteChunk(upload.ID, offset, io.LimitReader(ctx.Request().Body, upload.Size-offset))
+	upload.Offset += n
+	if err != nil {
+		ctx.StopWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.Header("Upload-Offset", strconv.FormatInt(upload.Offset, 10))
+	h.writeExpires(ctx, upload)
+	ctx.StatusCode(http.StatusNoContent)
+
+	if upload.Offset == upload.Size && h.opts.OnComplete != nil {
+		h.opts.OnComplete(ctx, upload)
+	}
+}
+
+func (h *tusHandler) terminate(ctx *context.Context) {
+	if !h.begin(ctx) {
+		return
+	}
+
+	upload, ok := h.get(ctx)
+	if !ok {
+		return
+	}
+
+	if err := h.store.Delete(upload.ID); err != nil {
+		ctx.StopWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.StatusCode(http.StatusNoContent)
+}
+
+func parseTusMetadata(header string) (map[string]string, error) {
+	if header == "" {
+		return nil, nil
+	}
+
+	metadata := make(map[string]string)
+	for _, pair := range strings.Split(header, ",") {
+		parts := strings.Fields(pair)
+		switch len(parts) {
+		case 1:
+			metadata[parts[0]] = ""
+		case 2:
+			value, err := base64.StdEncoding.DecodeString(parts[1])
+			if err != nil {
+				return nil, err
+			}
+			metadata[parts[0]] = string(value)
+		default:
+			return nil, errors.New("tus: invalid metadata")
+		}
+	}
+
+	return metadata, nil
+}
+
+func formatTusMetadata(metadata map[string]string) string {
+	pairs := make([]string, 0, len(metadata))
+	for key, value := range metadata {
+		if value == "" {
+			pairs = append(pairs, key)
+			continue
+		}
+
+		pairs = append(pairs, key+" "+base64.StdEncoding.EncodeToString([]byte(value)))
+	}
+	sort.Strings(pairs)
+
+	return strings.Join(pairs, ",")
+}
@@ -0,0 +1,125 @@
+package router_test
+
+import (
+	"io"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/httptest"
+)
+
+func TestTus(t *testing.T) {
+	dir, err := os.MkdirTemp("", "tus")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var completed iris.TusUpload
+
+	app := iris.New()
+	app.Tus("/files", iris.NewTusDirStore(dir), iris.TusOptions{
+		MaxSize: 10,
+		OnComplete: func(ctx iris.Context, upload iris.TusUpload) {
+			completed = upload
+		},
+	})
+
+	e := httptest.New(t, app)
+	e.OPTIONS("/files").Expect().Status(httptest.StatusNoContent).
+		Header("Tus-Extension").Equal("creation,termination")
+
+	e.POST("/files").WithHeader("Upload-Length", "5").Expect().Status(httptest.StatusPreconditionFailed)
+	e.POST("/files").WithHeader("Tus-Resumable", "1.0.0").WithHeader("Upload-Length", "11").
+		Expect().Status(httptest.StatusRequestEntityTooLarge)
+
+	location := e.POST("/files").WithHeader("Tus-Resumable", "1.0.0").
+		WithHeader("Upload-Length", "6").WithHeader("Upload-Metadata", "filename aGVsbG8udHh0").
+		Expect().Status(httptest.StatusCreated).Header("Location").Raw()
+
+	patch := func(offset, body string) *httptest.Request {
+		return e.PATCH(location).WithHeader("Tus-Resumable", "1.0.0").
+			WithHeader("Content-Type", "application/offset+octet-stream").
+			WithHeader("Upload-Offset", offset).WithBytes([]byte(body))
+	}
+
+	patch("0", "abc").Expect().Status(httptest.StatusNoContent).Header("Upload-Offset").Equal("3")
+	patch("0", "abc").Expect().Status(httptest.StatusConflict)
+
+	head := e.HEAD(location).WithHeader("Tus-Resumable", "1.0.0").Expect().Status(httptest.StatusOK)
+	head.Header("Upload-Offset").Equal("3")
+	head.Header("Upload-Length").Equal("6")
+	head.Header("Upload-Metadata").Equal("filename aGVsbG8udHh0")
+
+	patch("3", "def").Expect().Status(httptest.StatusNoContent).Header("Upload-Offset").Equal("6")
+	if completed.Offset != 6 || completed.Metadata["filename"] != "hello.txt" {
+		t.Fatalf("unexpected completed upload: %#+v", completed)
+	}
+
+	b, err := os.ReadFile(dir + "/" + completed.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := "abcdef", string(b); expected != got {
+		t.Fatalf("expected file contents: %q but got: %q", expected, got)
+	}
+
+	e.DELETE(location).WithHeader("Tus-Resumable", "1.0.0").Expect().Status(httptest.StatusNoContent)
+	e.HEAD(location).WithHeader("Tus-Resumable", "1.0.0").Expect().Status(httptest.StatusNotFound)
+}
+
+// slowTusStore delays the writes, so concurrent requests overlap.
+type slowTusStore struct {
+	iris.TusStore
+}
+
+func (s slowTusStore) WriteChunk(id string, offset int64, r io.Reader) (int64, error) {
+	time.Sleep(50 * time.Millisecond)
+	return s.TusStore.WriteChunk(id, offset, r)
+}
+
+func TestTusConcurrentPatch(t *testing.T) {
+	dir, err := os.MkdirTemp("", "tus")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	app := iris.New()
+	app.Tus("/files", slowTusStore{iris.NewTusDirStore(dir)})
+
+	e := httptest.New(t, app)
+	location := e.POST("/files").WithHeader("Tus-Resumable", "1.0.0").WithHeader("Upload-Length", "6").
+		Expect().Status(httptest.StatusCreated).Header("Location").Raw()
+
+	// Only one of the requests of the same offset is written, the rest conflict.
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		statuses = make(map[int]int)
+	)
+	for _, body := range []string{"abc", "xyz", "123"} {
+		wg.Add(1)
+		go func(body string) {
+			defer wg.Done()
+			status := e.PATCH(location).WithHeader("Tus-Resumable", "1.0.0").
+				WithHeader("Content-Type", "application/offset+octet-stream").
+				WithHeader("Upload-Offset", "0").WithBytes([]byte(body)).Expect().Raw().StatusCode
+
+			mu.Lock()
+			statuses[status]++
+			mu.Unlock()
+		}(body)
+	}
+	wg.Wait()
+
+	if statuses[httptest.StatusNoContent] != 1 || statuses[httptest.StatusConflict] != 2 {
+		t.Fatalf("expected one written and two conflicted requests but got: %v", statuses)
+	}
+
+	e.HEAD(location).WithHeader("Tus-Resumable", "1.0.0").Expect().Status(httptest.StatusOK).
+		Header("Upload-Offset").Equal("3")
+}
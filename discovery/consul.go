@@ -0,0 +1,131 @@
+package discovery
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Consul is a Registry which registers services to a Consul agent
+// through its HTTP API.
+// Each online route is registered as a "METHOD /path" service tag.
+type Consul struct {
+	// Address is the agent's url.
+	// Defaults to "http://127.0.0.1:8500".
+	Address string
+	// Token is the optional ACL token.
+	Token string
+	// HealthPath, if not empty, registers an HTTP health check
+	// against the service's URL plus this path, e.g. "/health".
+	HealthPath string
+	// Client is the HTTP Client which sends the requests to the agent.
+	// Defaults to the http.DefaultClient.
+	Client *http.Client
+}
+
+var _ Registry = (*Consul)(nil)
+
+type consulService struct {
+	ID      string            `json:"ID"`
+	Name    string            `json:"Name"`
+	Address string            `json:"Address"`
+	Port    int               `json:"Port"`
+	Tags    []string          `json:"Tags,omitempty"`
+	Meta    map[string]string `json:"Meta,omitempty"`
+	Check   *consulCheck      `json:"Check,omitempty"`
+}
+
+type consulCheck struct {
+	HTTP                           string `json:"HTTP"`
+	Interval                       string `json:"Interval"`
+	DeregisterCriticalServiceAfter string `json:"DeregisterCriticalServiceAfter"`
+}
+
+// Register completes the Registry interface.
+// It registers or updates the "svc" to the Consul agent.
+func (c *Consul) Register(ctx context.Context, svc Service) error {
+	tags := make([]string, 0, len(svc.Tags)+len(svc.Endpoints))
+	tags = append(tags, svc.Tags...)
+	for _, e := range svc.Endpoints {
+		tags = append(tags, e.Method+" "+e.Subdomain+e.Path)
+	}
+
+	scheme := "http"
+	if svc.TLS {
+		scheme = "https"
+	}
+
+	s := consulService{
+		ID:      svc.ID,
+		Name:    svc.Name,
+		Address: svc.Host,
+		Port:    svc.Port,
+		Tags:    tags,
+		Meta:    map[string]string{"scheme": scheme},
+	}
+
+	if c.HealthPath != "" {
+		s.Check = &consulCheck{
+			HTTP:                           strings.TrimSuffix(svc.URL, "/") + c.HealthPath,
+			Interval:                       "10s",
+			DeregisterCriticalServiceAfter: "1m",
+		}
+	}
+
+	body, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	return c.do(ctx, "/v1/agent/service/register", bytes.NewReader(body))
+}
+
+// Deregister completes the Registry interface.
+// It removes the "svc" from the Consul agent.
+func (c *Consul) Deregister(ctx context.Context, svc Service) error {
+	return c.do(ctx, "/v1/agent/service/deregister/"+url.PathEscape(svc.ID), nil)
+}
+
+func (c *Consul) do(ctx context.Context, path string, body io.Reader) error {
+	addr := c.Address
+	if addr == "" {
+		addr = "http://127.0.0.1:8500"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, strings.TrimSuffix(addr, "/")+path, body)
+	if err != nil {
+		return err
+	}
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	if c.Token != "" {
+		req.Header.Set("X-Consul-Token", c.Token)
+	}
+
+	client := c.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		b, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("consul: %s: %s", resp.Status, bytes.TrimSpace(b))
+	}
+
+	return nil
+}
@@ -0,0 +1,224 @@
+// Package discovery keeps external service discovery registries
+// (e.g. Consul, etcd or Kubernetes EndpointSlices) in sync with
+// the hosts and the online routes of an Iris Application.
+// This package directly imports the iris root package and cannot be used
+// inside Iris' codebase itself. Only external packages/programs can make use of it.
+package discovery
+
+import (
+	"context"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/core/host"
+	"github.com/kataras/iris/v12/core/router"
+)
+
+type (
+	// Endpoint describes an online route of a Service.
+	Endpoint struct {
+		Method    string `json:"method"`
+		Subdomain string `json:"subdomain,omitempty"`
+		Path      string `json:"path"`
+		Name      string `json:"name,omitempty"`
+	}
+
+	// Service describes a running host of an Iris Application,
+	// it's sent to the Registry when the host is ready to accept connections
+	// and every time its routes go online or offline.
+	Service struct {
+		ID   string   `json:"id"`
+		Name string   `json:"name"`
+		Host string   `json:"host"`
+		Port int      `json:"port"`
+		URL  string   `json:"url"`
+		TLS  bool     `json:"tls"`
+		Tags []string `json:"tags,omitempty"`
+		// Endpoints is the list of the online routes.
+		Endpoints []Endpoint `json:"endpoints"`
+	}
+
+	// Registry is the interface which should be completed
+	// by a service discovery backend, see `Consul` and `NewKV`.
+	// Kubernetes EndpointSlices or any other backend can be supported
+	// by a custom implementation.
+	Registry interface {
+		// Register should register or update (upsert) the "svc".
+		Register(ctx context.Context, svc Service) error
+		// Deregister should remove the "svc".
+		Deregister(ctx context.Context, svc Service) error
+	}
+)
+
+// Options holds the configuration for the `Register` function.
+type Options struct {
+	// Name is the service name.
+	// Defaults to the Application's name or "iris".
+	Name string
+	// ID returns the unique service id of a host.
+	// Defaults to Name-Hostname-Port.
+	ID func(name string, info iris.ReadyInfo) string
+	// Address overrides the advertised host,
+	// e.g. the pod IP when listening on "0.0.0.0".
+	Address string
+	// Tags are passed to the Service as they are.
+	Tags []string
+	// Timeout is the maximum duration of a Registry call.
+	// Defaults to 5 seconds.
+	Timeout time.Duration
+}
+
+// Register keeps the "registry" in sync with the "app":
+// a service is registered when a host is ready to accept connections,
+// it's updated when routes go online or offline (see `Router.OnRouteChange`)
+// and it's deregistered on host's shutdown.
+// Errors are logged through the Application's logger.
+//
+// Usage:
+//  discovery.Register(app, &discovery.Consul{Address: "http://127.0.0.1:8500"}, discovery.Options{
+//   Name: "users",
+//  })
+//  app.Listen(":8080")
+//
+// Should be called before `app.Listen/Run` methods.
+func Register(app *iris.Application, registry Registry, opts Options) {
+	if opts.Name == "" {
+		opts.Name = app.String()
+		if opts.Name == "" {
+			opts.Name = "iris"
+		}
+	}
+
+	if opts.ID == nil {
+		opts.ID = defaultID
+	}
+
+	if opts.Timeout <= 0 {
+		opts.Timeout = 5 * time.Second
+	}
+
+	r := &registrar{
+		app:      app,
+		registry: registry,
+		opts:     opts,
+		services: make(map[*host.Supervisor]Service),
+	}
+
+	app.ConfigureHost(func(su *host.Supervisor) {
+		su.RegisterOnServe(func(h host.TaskHost) {
+			r.register(su, r.newService(h.Ready()))
+		})
+		su.RegisterOnShutdown(func() {
+			r.deregister(su)
+		})
+	})
+
+	app.OnRouteChange(func(router.RouteEvent) {
+		r.refresh()
+	})
+}
+
+func defaultID(name string, info iris.ReadyInfo) string {
+	hostname, _ := os.Hostname()
+	_, port, _ := net.SplitHostPort(info.Addr)
+	return name + "-" + hostname + "-" + port
+}
+
+type registrar struct {
+	app      *iris.Application
+	registry Registry
+	opts     Options
+
+	mu       sync.Mutex
+	services map[*host.Supervisor]Service
+}
+
+func (r *registrar) newService(info iris.ReadyInfo) Service {
+	hostname, port, _ := net.SplitHostPort(info.Addr)
+	if r.opts.Address != "" {
+		hostname = r.opts.Address
+	}
+
+	portNum, _ := strconv.Atoi(port)
+
+	return Service{
+		ID:   r.opts.ID(r.opts.Name, info),
+		Name: r.opts.Name,
+		Host: hostname,
+		Port: portNum,
+		URL:  info.URL,
+		TLS:  info.TLS,
+		Tags: r.opts.Tags,
+	}
+}
+
+func (r *registrar) endpoints() []Endpoint {
+	routes := r.app.GetRoutes()
+	endpoints := make([]Endpoint, 0, len(routes))
+	for _, route := range routes {
+		if !route.IsOnline() {
+			continue
+		}
+
+		endpoints = append(endpoints, Endpoint{
+			Method:    route.Method,
+			Subdomain: route.Subdomain,
+			Path:      route.Path,
+			Name:      route.Name,
+		})
+	}
+
+	return endpoints
+}
+
+func (r *registrar) register(su *host.Supervisor, svc Service) {
+	svc.Endpoints = r.endpoints()
+
+	r.mu.Lock()
+	r.services[su] = svc
+	r.mu.Unlock()
+
+	r.call(r.registry.Register, svc)
+}
+
+// refresh updates the endpoints of the registered services.
+func (r *registrar) refresh() {
+	endpoints := r.endpoints()
+
+	r.mu.Lock()
+	services := make([]Service, 0, len(r.services))
+	for su, svc := range r.services {
+		svc.Endpoints = endpoints
+		r.services[su] = svc
+		services = append(services, svc)
+	}
+	r.mu.Unlock()
+
+	for _, svc := range services {
+		r.call(r.registry.Register, svc)
+	}
+}
+
+func (r *registrar) deregister(su *host.Supervisor) {
+	r.mu.Lock()
+	svc, ok := r.services[su]
+	delete(r.services, su)
+	r.mu.Unlock()
+
+	if ok {
+		r.call(r.registry.Deregister, svc)
+	}
+}
+
+func (r *registrar) call(fn func(context.Context, Service) error, svc Service) {
+	ctx, cancel := context.WithTimeout(context.Background(), r.opts.Timeout)
+	defer cancel()
+
+	if err := fn(ctx, svc); err != nil {
+		r.app.Logger().Errorf("discovery: %s: %v", svc.ID, err)
+	}
+}
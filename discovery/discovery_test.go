@@ -0,0 +1,141 @@
+package discovery_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/discovery"
+)
+
+type memoryRegistry struct {
+	mu     sync.Mutex
+	events chan string
+	last   discovery.Service
+}
+
+func (r *memoryRegistry) Register(_ context.Context, svc discovery.Service) error {
+	r.mu.Lock()
+	r.last = svc
+	r.mu.Unlock()
+	r.events <- "register"
+	return nil
+}
+
+func (r *memoryRegistry) Deregister(_ context.Context, svc discovery.Service) error {
+	r.events <- "deregister " + svc.ID
+	return nil
+}
+
+func (r *memoryRegistry) wait(t *testing.T, expected string) discovery.Service {
+	t.Helper()
+
+	select {
+	case got := <-r.events:
+		if got != expected {
+			t.Fatalf("expected event: %s but got: %s", expected, got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timeout waiting for: %s", expected)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.last
+}
+
+func TestRegister(t *testing.T) {
+	app := iris.New()
+	app.Get("/users", func(iris.Context) {})
+	offline := app.Get("/users/{id}", func(iris.Context) {})
+
+	registry := &memoryRegistry{events: make(chan string, 10)}
+	discovery.Register(app, registry, discovery.Options{
+		Name:    "users",
+		Address: "10.0.0.1",
+		ID: func(name string, info iris.ReadyInfo) string {
+			return name + "-1"
+		},
+	})
+
+	go app.Listen("127.0.0.1:0", iris.WithoutStartupLog, iris.WithoutInterruptHandler)
+
+	svc := registry.wait(t, "register")
+	if svc.ID != "users-1" || svc.Name != "users" || svc.Host != "10.0.0.1" || svc.Port == 0 || len(svc.Endpoints) != 2 {
+		t.Fatalf("unexpected service: %#v", svc)
+	}
+
+	offline.SetStatusOffline()
+	if err := app.RefreshRouter(); err != nil {
+		t.Fatal(err)
+	}
+
+	svc = registry.wait(t, "register")
+	if expected := (discovery.Endpoint{Method: "GET", Path: "/users", Name: "GET/users"}); len(svc.Endpoints) != 1 || svc.Endpoints[0] != expected {
+		t.Fatalf("expected endpoints: %#v but got: %#v", expected, svc.Endpoints)
+	}
+
+	if err := app.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	registry.wait(t, "deregister users-1")
+}
+
+func TestConsul(t *testing.T) {
+	type request struct {
+		method, path, token string
+		body                map[string]interface{}
+	}
+
+	requests := make(chan request, 2)
+	agent := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := request{method: r.Method, path: r.URL.Path, token: r.Header.Get("X-Consul-Token")}
+		if b, _ := ioutil.ReadAll(r.Body); len(b) > 0 {
+			json.Unmarshal(b, &req.body)
+		}
+		requests <- req
+	}))
+	defer agent.Close()
+
+	consul := &discovery.Consul{Address: agent.URL, Token: "secret", HealthPath: "/health"}
+	svc := discovery.Service{
+		ID:        "users-1",
+		Name:      "users",
+		Host:      "10.0.0.1",
+		Port:      8080,
+		URL:       "http://10.0.0.1:8080",
+		Endpoints: []discovery.Endpoint{{Method: "GET", Path: "/users"}},
+	}
+
+	if err := consul.Register(context.Background(), svc); err != nil {
+		t.Fatal(err)
+	}
+
+	req := <-requests
+	if req.method != http.MethodPut || req.path != "/v1/agent/service/register" || req.token != "secret" {
+		t.Fatalf("unexpected register request: %#v", req)
+	}
+
+	if tags := req.body["Tags"].([]interface{}); len(tags) != 1 || tags[0] != "GET /users" {
+		t.Fatalf("unexpected tags: %v", tags)
+	}
+
+	if check := req.body["Check"].(map[string]interface{}); check["HTTP"] != "http://10.0.0.1:8080/health" {
+		t.Fatalf("unexpected check: %v", check)
+	}
+
+	if err := consul.Deregister(context.Background(), svc); err != nil {
+		t.Fatal(err)
+	}
+
+	if req = <-requests; req.method != http.MethodPut || req.path != "/v1/agent/service/deregister/users-1" {
+		t.Fatalf("unexpected deregister request: %#v", req)
+	}
+}
@@ -0,0 +1,69 @@
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+)
+
+// KVStore is the interface which a key-value store client,
+// e.g. the etcd one, should complete in order to be used as a Registry,
+// see `NewKV` function.
+//
+// An etcd (go.etcd.io/etcd/client/v3) adapter looks like that:
+//  type etcdStore struct{ c *clientv3.Client }
+//
+//  func (s etcdStore) Put(ctx context.Context, key, value string) error {
+//   _, err := s.c.Put(ctx, key, value)
+//   return err
+//  }
+//
+//  func (s etcdStore) Delete(ctx context.Context, key string) error {
+//   _, err := s.c.Delete(ctx, key)
+//   return err
+//  }
+type KVStore interface {
+	Put(ctx context.Context, key, value string) error
+	Delete(ctx context.Context, key string) error
+}
+
+// KV is a Registry which stores the services, encoded as JSON,
+// to a key-value store under the "Prefix/Name/ID" keys.
+type KV struct {
+	Store  KVStore
+	Prefix string
+}
+
+var _ Registry = (*KV)(nil)
+
+// NewKV returns a new KV Registry.
+// The "prefix" defaults to "/services".
+func NewKV(store KVStore, prefix string) *KV {
+	if prefix == "" {
+		prefix = "/services"
+	}
+
+	return &KV{Store: store, Prefix: strings.TrimSuffix(prefix, "/")}
+}
+
+// Key returns the key of the "svc".
+func (kv *KV) Key(svc Service) string {
+	return kv.Prefix + "/" + svc.Name + "/" + svc.ID
+}
+
+// Register completes the Registry interface.
+// It stores the "svc" as JSON.
+func (kv *KV) Register(ctx context.Context, svc Service) error {
+	b, err := json.Marshal(svc)
+	if err != nil {
+		return err
+	}
+
+	return kv.Store.Put(ctx, kv.Key(svc), string(b))
+}
+
+// Deregister completes the Registry interface.
+// It deletes the "svc".
+func (kv *KV) Deregister(ctx context.Context, svc Service) error {
+	return kv.Store.Delete(ctx, kv.Key(svc))
+}
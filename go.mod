@@ -5,7 +5,9 @@ go 1.16
 require (
 	github.com/BurntSushi/toml v0.3.1
 	github.com/CloudyKit/jet/v6 v6.1.0
+	github.com/DATA-DOG/go-sqlmock v1.5.0
 	github.com/Shopify/goreferrer v0.0.0-20210630161223-536fa16abd6f
+	github.com/alicebob/miniredis/v2 v2.30.0
 	github.com/andybalholm/brotli v1.0.3
 	github.com/aymerick/raymond v2.0.3-0.20180322193309-b565731e1464+incompatible
 	github.com/blang/semver/v4 v4.0.0
@@ -13,9 +15,12 @@ require (
 	github.com/eknkc/amber v0.0.0-20171010120322-cdade1c07385
 	github.com/fatih/structs v1.1.0
 	github.com/flosch/pongo2/v4 v4.0.2
+	github.com/fxamacker/cbor/v2 v2.2.0
+	github.com/go-playground/validator/v10 v10.6.1
 	github.com/go-redis/redis/v8 v8.11.0
 	github.com/golang/snappy v0.0.4
 	github.com/google/uuid v1.3.0
+	github.com/gorilla/websocket v1.4.2
 	github.com/iris-contrib/httpexpect/v2 v2.0.5
 	github.com/iris-contrib/jade v1.1.4
 	github.com/iris-contrib/schema v0.0.6
@@ -6,6 +6,7 @@ import (
 	"sort"
 
 	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/macro"
 )
 
 // binding contains the Dependency and the Input, it's the result of a function or struct + dependencies.
@@ -133,7 +134,7 @@ func getBindingsFor(inputs []reflect.Type, deps []*Dependency, disablePayloadAut
 	totalParamsExpected := 0
 	if paramsCount != -1 {
 		for i, in := range inputs {
-			if _, canBePathParameter := context.ParamResolvers[in]; !canBePathParameter {
+			if _, canBePathParameter := context.ParamResolvers[in]; !canBePathParameter && !macro.IsValueType(in) {
 				continue
 			}
 			shouldBindParams[i] = struct{}{}
@@ -0,0 +1,150 @@
+package hero
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/kataras/iris/v12/context"
+)
+
+// cachedSweepThreshold is the number of cached entries
+// which triggers a removal of the expired ones.
+const cachedSweepThreshold = 1024
+
+// CachedDependency wraps a dependency so its value is computed
+// once per request, no matter how many handlers (middlewares and the controller)
+// accept it, and optionally cached across requests by a key.
+// See `Cached` function.
+type CachedDependency struct {
+	// Dependency is the wrapped dependency, e.g. a func(iris.Context) (*Profile, error).
+	Dependency interface{}
+	// TTL is the duration the value is cached across requests.
+	// Zero or negative means that it's computed on each request.
+	TTL time.Duration
+	// Key returns the cache key of a request, e.g. the user id.
+	// It's required when TTL is positive, so a value is never shared
+	// across requests that should not share it, e.g. a user's profile.
+	// Return a constant key for an application-wide value.
+	Key func(ctx *context.Context) string
+
+	ctxKey  string
+	mu      sync.RWMutex
+	entries map[string]cachedEntry
+}
+
+type cachedEntry struct {
+	value   reflect.Value
+	expires time.Time
+}
+
+// Cached returns a dependency which computes the "dependency"'s value
+// once per request (stored in the request's Values) and, if "ttl" is positive,
+// it caches it across requests by key, see `By` method.
+// A positive "ttl" without a key function panics on registration.
+// Errors are never cached.
+// The expiration uses the request's clock, see `Context.Now`.
+//
+// Usage:
+//  hero.Register(hero.Cached(func(ctx iris.Context) (*Profile, error) {
+//   return db.GetProfile(ctx.Params().Get("user"))
+//  }, time.Minute).By(func(ctx iris.Context) string {
+//   return ctx.Params().Get("user")
+//  }))
+func Cached(dependency interface{}, ttl time.Duration) *CachedDependency {
+	return &CachedDependency{
+		Dependency: dependency,
+		TTL:        ttl,
+	}
+}
+
+// By sets the function which returns the cache key of a request.
+// Returns itself.
+func (d *CachedDependency) By(key func(ctx *context.Context) string) *CachedDependency {
+	d.Key = key
+	return d
+}
+
+// Invalidate removes the cached value of the given "key",
+// e.g. when the user profile is updated.
+func (d *CachedDependency) Invalidate(key string) {
+	d.mu.Lock()
+	delete(d.entries, key)
+	d.mu.Unlock()
+}
+
+func (d *CachedDependency) wrap(handler DependencyHandler) DependencyHandler {
+	return func(ctx *context.Context, input *Input) (reflect.Value, error) {
+		if v, ok := ctx.Values().Get(d.ctxKey).(reflect.Value); ok {
+			return v, nil
+		}
+
+		var (
+			key string
+			now time.Time
+		)
+
+		if d.TTL > 0 {
+			key = d.Key(ctx)
+			now = ctx.Now()
+			d.mu.RLock()
+			entry, ok := d.entries[key]
+			d.mu.RUnlock()
+			if ok && now.Before(entry.expires) {
+				ctx.Values().Set(d.ctxKey, entry.value)
+				return entry.value, nil
+			}
+		}
+
+		v, err := handler(ctx, input)
+		if err != nil {
+			return v, err
+		}
+
+		ctx.Values().Set(d.ctxKey, v)
+
+		if d.TTL > 0 {
+			d.mu.Lock()
+			if len(d.entries) >= cachedSweepThreshold {
+				for k, entry := range d.entries {
+					if !now.Before(entry.expires) {
+						delete(d.entries, k)
+					}
+				}
+			}
+			d.entries[key] = cachedEntry{value: v, expires: now.Add(d.TTL)}
+			d.mu.Unlock()
+		}
+
+		return v, nil
+	}
+}
+
+func fromCached(disablePayloadAutoBinding bool, dest *Dependency, funcDependencies []*Dependency) bool {
+	cached, ok := dest.OriginalValue.(*CachedDependency)
+	if !ok {
+		return false
+	}
+
+	if cached.TTL > 0 && cached.Key == nil {
+		panic(fmt.Sprintf("bad value: cached dependency with a TTL of %s but without a key function, see the By method: %T", cached.TTL, cached.Dependency))
+	}
+
+	if cached.entries == nil { // first registration.
+		cached.entries = make(map[string]cachedEntry)
+		cached.ctxKey = fmt.Sprintf("iris.hero.cached.%p", cached)
+	}
+
+	d := newDependency(cached.Dependency, disablePayloadAutoBinding, funcDependencies...)
+	if d.Static { // static values are already computed once.
+		*dest = *d
+		return true
+	}
+
+	dest.Source = d.Source
+	dest.DestType = d.DestType
+	dest.Explicit = d.Explicit
+	dest.Handle = cached.wrap(d.Handle)
+	return true
+}
@@ -0,0 +1,83 @@
+package hero_test
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12"
+	. "github.com/kataras/iris/v12/hero"
+	"github.com/kataras/iris/v12/httptest"
+)
+
+type profile struct {
+	Name  string
+	Calls int
+}
+
+func TestCached(t *testing.T) {
+	clock := iris.NewMockClock(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	calls := 0
+	c := New()
+	c.Register(Cached(func(ctx iris.Context) (*profile, error) {
+		name := ctx.Params().Get("name")
+		if name == "invalid" {
+			return nil, errors.New("invalid user")
+		}
+
+		calls++
+		return &profile{Name: name, Calls: calls}, nil
+	}, time.Minute).By(func(ctx iris.Context) string {
+		return ctx.Params().Get("name")
+	}))
+
+	app := iris.New().SetClock(clock)
+	app.Get("/{name}", c.Handler(func(ctx iris.Context, p *profile) { ctx.Next() }), c.Handler(func(p *profile) string {
+		return p.Name + ":" + strconv.Itoa(p.Calls)
+	}))
+
+	e := httptest.New(t, app)
+	// computed once per request, even if two handlers accept it.
+	e.GET("/kataras").Expect().Status(httptest.StatusOK).Body().Equal("kataras:1")
+	// cached across requests by key.
+	e.GET("/kataras").Expect().Status(httptest.StatusOK).Body().Equal("kataras:1")
+	e.GET("/makis").Expect().Status(httptest.StatusOK).Body().Equal("makis:2")
+	// errors are not cached.
+	e.GET("/invalid").Expect().Status(httptest.StatusBadRequest)
+
+	clock.Advance(time.Minute)
+	e.GET("/kataras").Expect().Status(httptest.StatusOK).Body().Equal("kataras:3")
+	e.GET("/kataras").Expect().Status(httptest.StatusOK).Body().Equal("kataras:3")
+}
+
+func TestCachedPerRequest(t *testing.T) {
+	calls := 0
+	c := New()
+	c.Register(Cached(func(ctx iris.Context) *profile {
+		calls++
+		return &profile{Calls: calls}
+	}, 0))
+
+	app := iris.New()
+	app.Get("/", c.Handler(func(ctx iris.Context, p *profile) { ctx.Next() }), c.Handler(func(p *profile) string {
+		return strconv.Itoa(p.Calls)
+	}))
+
+	e := httptest.New(t, app)
+	e.GET("/").Expect().Status(httptest.StatusOK).Body().Equal("1")
+	e.GET("/").Expect().Status(httptest.StatusOK).Body().Equal("2")
+}
+
+func TestCachedWithoutKey(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("expected a panic on a cached dependency with a TTL but without a key")
+		}
+	}()
+
+	New().Register(Cached(func(ctx iris.Context) *profile {
+		return &profile{Name: ctx.Params().Get("name")}
+	}, time.Minute))
+}
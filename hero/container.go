@@ -62,6 +62,17 @@ type Container struct {
 	// resultHandlers is a list of functions that serve the return struct value of a function handler.
 	// Defaults to "defaultResultHandler" but it can be overridden.
 	resultHandlers []func(next ResultHandler) ResultHandler
+
+	// parent is the container which created this one through `Child`, if any.
+	parent *Container
+	// children holds the containers created through `Child`,
+	// used to deliver the next registrations of this Container to them.
+	children []*Container
+	// inherited is the number of the Dependencies
+	// that were inherited by the parent container.
+	// Local registrations are always placed after them,
+	// so they have priority over the parent's ones.
+	inherited int
 }
 
 // A Report holds meta information about dependency sources and target values per package,
@@ -250,6 +261,32 @@ func (c *Container) Clone() *Container {
 	return cloned
 }
 
+// Child returns a new child container.
+// The child inherits the ErrorHandler, Dependencies and all Options from "c" receiver,
+// just like `Clone` does, however, any future registrations
+// of the parent are delivered to the child too.
+// The dependencies registered directly to the child
+// override the parent's ones of the same type, for the child and its own children only.
+//
+// Example Code:
+//  c := hero.New()
+//  c.Register(&defaultPermissions{})
+//  admin := c.Child()
+//  admin.Register(&elevatedPermissions{})
+//  c.Register(&db{}) // available to the "admin" container as well.
+func (c *Container) Child() *Container {
+	child := c.Clone()
+	child.parent = c
+	child.inherited = len(child.Dependencies)
+	c.children = append(c.children, child)
+	return child
+}
+
+// Parent returns the container which created this one through `Child`, if any.
+func (c *Container) Parent() *Container {
+	return c.parent
+}
+
 // Register adds a dependency.
 // The value can be a single struct value-instance or a function
 // which has one input and one output, that output type
@@ -282,15 +319,35 @@ func Register(dependency interface{}) *Dependency {
 // - Register(func(User) OtherResponse {...})
 func (c *Container) Register(dependency interface{}) *Dependency {
 	d := newDependency(dependency, c.DisablePayloadAutoBinding, c.Dependencies...)
+	c.register(d, false)
+	return d
+}
+
+func (c *Container) register(d *Dependency, inherited bool) {
 	if d.DestType == nil {
 		// prepend the dynamic dependency so it will be tried at the end
 		// (we don't care about performance here, design-time)
 		c.Dependencies = append([]*Dependency{d}, c.Dependencies...)
+		// keep the boundary of the inherited ones.
+		c.inherited++
+	} else if inherited {
+		// place it before the local ones, so they can still override it.
+		if c.inherited > len(c.Dependencies) {
+			c.inherited = len(c.Dependencies)
+		}
+
+		deps := make([]*Dependency, 0, len(c.Dependencies)+1)
+		deps = append(deps, c.Dependencies[:c.inherited]...)
+		deps = append(deps, d)
+		c.Dependencies = append(deps, c.Dependencies[c.inherited:]...)
+		c.inherited++
 	} else {
 		c.Dependencies = append(c.Dependencies, d)
 	}
 
-	return d
+	for _, child := range c.children {
+		child.register(d, true)
+	}
 }
 
 // UseResultHandler adds a result handler to the Container.
@@ -129,3 +129,31 @@ func TestContainerUseResultHandler(t *testing.T) {
 	e := httptest.New(t, app)
 	e.GET("/42").Expect().Status(httptest.StatusOK).JSON().Equal(expectedResponse)
 }
+
+func TestContainerChild(t *testing.T) {
+	parent := New()
+	parent.Register(testInput{Name: "parent"})
+
+	child := parent.Child()
+	if child.Parent() != parent {
+		t.Fatalf("expected child's parent to be the parent container")
+	}
+
+	child.Register(testInput{Name: "child"})
+	// registered after the child's creation.
+	parent.Register(&testServiceImpl{prefix: "parent:"})
+	// registered again after the child's creation, the child's one should still have priority.
+	parent.Register(testInput{Name: "parent2"})
+
+	handler := func(in testInput, service testService) string {
+		return service.Say(in.Name)
+	}
+
+	app := iris.New()
+	app.Get("/parent", parent.Handler(handler))
+	app.Get("/child", child.Handler(handler))
+
+	e := httptest.New(t, app)
+	e.GET("/parent").Expect().Status(httptest.StatusOK).Body().Equal("parent: parent2")
+	e.GET("/child").Expect().Status(httptest.StatusOK).Body().Equal("parent: child")
+}
@@ -85,7 +85,8 @@ func newDependency(dependency interface{}, disablePayloadAutoBinding bool, funcD
 // Resolver     DependencyResolver
 
 func resolveDependency(v reflect.Value, disablePayloadAutoBinding bool, dest *Dependency, funcDependencies ...*Dependency) bool {
-	return fromDependencyHandler(v, dest) ||
+	return fromCached(disablePayloadAutoBinding, dest, funcDependencies) ||
+		fromDependencyHandler(v, dest) ||
 		fromStructValue(v, dest) ||
 		fromFunc(v, dest) ||
 		len(funcDependencies) > 0 && fromDependentFunc(v, disablePayloadAutoBinding, dest, funcDependencies)
@@ -43,6 +43,9 @@ func defaultResultHandler(ctx *context.Context, v interface{}) error {
 	case context.ContentMsgPackHeaderValue, context.ContentMsgPack2HeaderValue:
 		_, err := ctx.MsgPack(v)
 		return err
+	case context.ContentCBORHeaderValue:
+		_, err := ctx.CBOR(v)
+		return err
 	default:
 		// otherwise default to JSON.
 		_, err := ctx.JSON(v)
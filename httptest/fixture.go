@@ -0,0 +1,109 @@
+package httptest
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/sessions"
+
+	"github.com/iris-contrib/httpexpect/v2"
+)
+
+// Fixture holds the state which is pre-seeded to a test request,
+// so handlers which require an authenticated user, a session
+// or a feature flag can be tested without going through the login flows.
+// See `WithFixture` package-level function.
+type Fixture struct {
+	// User, if not nil, is set as the request's user, see `Context.SetUser`.
+	User interface{}
+	// Sessions is the sessions manager of the application.
+	// Required to seed the Session values.
+	Sessions *sessions.Sessions
+	// Session holds the values of the request's session.
+	Session map[string]interface{}
+	// Features holds the feature flag states of the request,
+	// see `Context.SetFeature`.
+	Features map[string]bool
+	// Values holds any other request values, see `Context.Values`.
+	Values map[string]interface{}
+}
+
+func (f Fixture) apply(ctx *context.Context) {
+	if f.User != nil {
+		if err := ctx.SetUser(f.User); err != nil {
+			ctx.Application().Logger().Errorf("httptest: fixture: %v", err)
+		}
+	}
+
+	if f.Sessions != nil {
+		// Add the session cookie to the request too,
+		// so the sessions middleware starts the same session.
+		sess := f.Sessions.Start(ctx, context.CookieAllowReclaim())
+		for key, value := range f.Session {
+			sess.Set(key, value)
+		}
+	}
+
+	for name, enabled := range f.Features {
+		ctx.SetFeature(name, enabled)
+	}
+
+	for key, value := range f.Values {
+		ctx.Values().Set(key, value)
+	}
+}
+
+const fixtureHeaderKey = "X-Iris-Httptest-Fixture"
+
+var (
+	fixtures  sync.Map // string:Fixture.
+	fixtureID uint64
+)
+
+// WithFixture pre-seeds the "fixture" state to the "req" request
+// and returns the request itself. Note that the application's
+// router wrappers and router filters (`UseRouter`) are skipped for that request.
+//
+// Usage:
+//  e := httptest.New(t, app)
+//  admin := httptest.Fixture{
+//      User:     &iris.SimpleUser{Username: "kataras", Roles: []string{"admin"}},
+//      Sessions: sess,
+//      Session:  map[string]interface{}{"tenant": "acme"},
+//      Features: map[string]bool{"billing": true},
+//  }
+//  httptest.WithFixture(e.GET("/admin"), admin).Expect().Status(httptest.StatusOK)
+func WithFixture(req *httpexpect.Request, fixture Fixture) *httpexpect.Request {
+	id := strconv.FormatUint(atomic.AddUint64(&fixtureID, 1), 10)
+	fixtures.Store(id, fixture)
+	return req.WithHeader(fixtureHeaderKey, id)
+}
+
+// fixtureHandler serves the requests of the `New` test framework,
+// it applies the fixture of the `WithFixture` requests.
+func fixtureHandler(app *iris.Application) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(fixtureHeaderKey)
+		if id == "" {
+			app.ServeHTTP(w, r)
+			return
+		}
+
+		r.Header.Del(fixtureHeaderKey)
+		v, ok := fixtures.Load(id)
+		if !ok {
+			app.ServeHTTP(w, r)
+			return
+		}
+		fixtures.Delete(id)
+
+		ctx := app.ContextPool.Acquire(w, r)
+		v.(Fixture).apply(ctx)
+		app.ServeHTTPC(ctx)
+		app.ContextPool.Release(ctx)
+	})
+}
@@ -0,0 +1,40 @@
+package httptest_test
+
+import (
+	"testing"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/httptest"
+	"github.com/kataras/iris/v12/sessions"
+)
+
+func TestFixture(t *testing.T) {
+	sess := sessions.New(sessions.Config{Cookie: "session_id"})
+
+	app := iris.New()
+	app.Use(sess.Handler())
+	app.Get("/admin", func(ctx iris.Context) {
+		u := ctx.User()
+		if u == nil {
+			ctx.StopWithStatus(iris.StatusUnauthorized)
+			return
+		}
+
+		username, _ := u.GetUsername()
+		ctx.Writef("%s:%s:%v", username, sessions.Get(ctx).GetString("tenant"), ctx.FeatureEnabled("billing"))
+	})
+
+	e := httptest.New(t, app)
+	e.GET("/admin").Expect().Status(httptest.StatusUnauthorized)
+
+	admin := httptest.Fixture{
+		User:     &iris.SimpleUser{Username: "kataras"},
+		Sessions: sess,
+		Session:  map[string]interface{}{"tenant": "acme"},
+		Features: map[string]bool{"billing": true},
+	}
+	httptest.WithFixture(e.GET("/admin"), admin).Expect().Status(httptest.StatusOK).Body().Equal("kataras:acme:true")
+
+	// Fixtures are applied once.
+	e.GET("/admin").Expect().Status(httptest.StatusUnauthorized)
+}
@@ -130,7 +130,7 @@ func New(t *testing.T, app *iris.Application, setters ...OptionSetter) *httpexpe
 	testConfiguration := httpexpect.Config{
 		BaseURL: conf.URL,
 		Client: &http.Client{
-			Transport: httpexpect.NewBinder(app),
+			Transport: httpexpect.NewBinder(fixtureHandler(app)),
 			Jar:       httpexpect.NewJar(),
 		},
 		Reporter: reporter,
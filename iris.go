@@ -1,13 +1,16 @@
 package iris
 
 import (
+	"bytes"
 	stdContext "context"
+	stdJSON "encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"regexp"
 	"strings"
@@ -22,8 +25,10 @@ import (
 	"github.com/kataras/iris/v12/middleware/accesslog"
 	"github.com/kataras/iris/v12/middleware/recover"
 	"github.com/kataras/iris/v12/middleware/requestid"
+	"github.com/kataras/iris/v12/middleware/signedurl"
 	"github.com/kataras/iris/v12/view"
 
+	"github.com/go-playground/validator/v10"
 	"github.com/kataras/golog"
 	"github.com/kataras/tunnel"
 
@@ -73,9 +78,21 @@ type Application struct {
 	I18n *i18n.I18n
 
 	// Validator is the request body validator, defaults to nil.
+	// Note that the `Context.ReadBody` validates through
+	// the github.com/go-playground/validator when it's nil, see `ValidateBody`.
 	Validator context.Validator
+	// URLSigner signs and verifies session-independent URLs,
+	// e.g. password reset, download and unsubscribe links.
+	// Use the `SignURL` method to sign a route's URL
+	// and the `URLSigner.Handler` to verify it.
+	//
+	// Defaults to nil, set it through `signedurl.New(secret)`.
+	URLSigner *signedurl.Signer
 	// Minifier to minify responses.
 	minifier *minify.M
+	// describables are the registered values which describe their options
+	// on `ExportConfig`, see `AddDescribable`.
+	describables []describable
 
 	// view engine
 	view view.View
@@ -106,6 +123,12 @@ type Application struct {
 	// Hosts field is available after `Run` or `NewHost`.
 	Hosts             []*host.Supervisor
 	hostConfigurators []host.Configurator
+	// notifies the systemd service manager once, see `NewHost`.
+	systemdNotifyOnce sync.Once
+	// the application's clock, see `SetClock` and `Clock` methods.
+	clock context.Clock
+	// the application's cookie codec, see `SetCookieCodec` and `CookieCodec` methods.
+	cookieCodec context.SecureCookie
 }
 
 // New creates and returns a fresh empty iris *Application instance.
@@ -313,6 +336,42 @@ func (app *Application) IsDebug() bool {
 	return app.logger.Level >= golog.DebugLevel
 }
 
+// SetClock sets the Clock which is used by the `Context.Now` method
+// and the framework's time-dependent features, e.g. to freeze and advance the time on tests.
+// See `NewMockClock` too.
+// Should be called before `Build` or `Listen` methods.
+func (app *Application) SetClock(clock context.Clock) *Application {
+	app.clock = clock
+	return app
+}
+
+// Clock returns the application's Clock,
+// defaults to the `context.SystemClock`.
+func (app *Application) Clock() context.Clock {
+	if app.clock == nil {
+		return context.SystemClock
+	}
+
+	return app.clock
+}
+
+// SetCookieCodec sets the codec which signs and encrypts the values
+// of the `Context.SetSecureCookie` and `Context.GetSecureCookie` methods.
+// See `NewCookieCodec` too.
+//
+// Example Code:
+//  codec, err := iris.NewCookieCodec(iris.CookieKey{Hash: hashKey, Block: blockKey})
+//  app.SetCookieCodec(codec)
+func (app *Application) SetCookieCodec(codec context.SecureCookie) *Application {
+	app.cookieCodec = codec
+	return app
+}
+
+// CookieCodec returns the application's cookie codec, defaults to nil.
+func (app *Application) CookieCodec() context.SecureCookie {
+	return app.cookieCodec
+}
+
 // I18nReadOnly returns the i18n's read-only features.
 // See `I18n` method for more.
 func (app *Application) I18nReadOnly() context.I18nReadOnly {
@@ -336,7 +395,33 @@ func (app *Application) Validate(v interface{}) error {
 	// }
 
 	// no need to check the kind, underline lib does it but in the future this may change (look above).
-	err := app.Validator.Struct(v)
+	return validateStruct(app.Validator, v)
+}
+
+var (
+	defaultBodyValidator     context.Validator
+	defaultBodyValidatorOnce sync.Once
+)
+
+// ValidateBody validates a value read by the `Context.ReadBody` method.
+// It does nothing when the Validator is set, as the Read methods
+// already validated the value through `Validate`, otherwise
+// it validates it through a default github.com/go-playground/validator,
+// so the rest of the Read methods are not affected.
+func (app *Application) ValidateBody(v interface{}) error {
+	if app.Validator != nil {
+		return nil
+	}
+
+	defaultBodyValidatorOnce.Do(func() {
+		defaultBodyValidator = validator.New()
+	})
+
+	return validateStruct(defaultBodyValidator, v)
+}
+
+func validateStruct(validator context.Validator, v interface{}) error {
+	err := validator.Struct(v)
 	if err != nil {
 		if !strings.HasPrefix(err.Error(), "validator: ") {
 			return err
@@ -376,6 +461,202 @@ func Minify(ctx Context) {
 	ctx.Next()
 }
 
+// ErrURLSignerMissing is returned by the `Application.SignURL` method
+// when the Application's URLSigner field is nil.
+var ErrURLSignerMissing = errors.New("iris: URLSigner is missing")
+
+// SignURL returns the path of the route registered with the "routeName"
+// and its dynamic path parameters' values, signed with the given "claims"
+// and valid for the given "expiry" duration.
+// The returned URL is verified by the `URLSigner.Handler` middleware.
+//
+// Example Code:
+//  app.URLSigner = signedurl.New([]byte("secret"))
+//  app.Get("/unsubscribe/{id}", app.URLSigner.Handler, unsubscribe).Name = "unsubscribe"
+//  [...]
+//  link, err := app.SignURL("unsubscribe", []interface{}{42}, 24*time.Hour, signedurl.Claims{"email": email})
+func (app *Application) SignURL(routeName string, params []interface{}, expiry time.Duration, claims signedurl.Claims) (string, error) {
+	if app.URLSigner == nil {
+		return "", ErrURLSignerMissing
+	}
+
+	if app.GetRoute(routeName) == nil {
+		return "", fmt.Errorf("iris: route %q not found", routeName)
+	}
+
+	path := router.NewRoutePathReverser(app).Path(routeName, params...)
+	if path == "" {
+		return "", fmt.Errorf("iris: route %q: unable to resolve path", routeName)
+	}
+
+	return app.URLSigner.Sign(path, expiry, claims)
+}
+
+// ServeRouteOptions holds the optional settings of the `Application.ServeRoute` method.
+type ServeRouteOptions struct {
+	// SkipMiddleware, if true, executes only the route's last handler and its done handlers,
+	// the router wrappers, the router filters and the route's middleware are skipped.
+	// See `Route.FinalHandlers` too.
+	SkipMiddleware bool
+	// Header holds the request headers, e.g. the parent request's ones.
+	Header http.Header
+	// Query holds the request URL query.
+	Query url.Values
+	// Context is the request's context, e.g. the parent request's one, defaults to a background context.
+	Context stdContext.Context
+}
+
+// RouteResponse holds the captured response of an `Application.ServeRoute` call.
+type RouteResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// routeResponseWriter captures a `RouteResponse`.
+type routeResponseWriter struct {
+	*RouteResponse
+}
+
+func (w routeResponseWriter) Header() http.Header {
+	return w.RouteResponse.Header
+}
+
+func (w routeResponseWriter) Write(b []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	w.Body = append(w.Body, b...)
+	return len(b), nil
+}
+
+func (w routeResponseWriter) WriteHeader(statusCode int) {
+	if w.StatusCode == 0 {
+		w.StatusCode = statusCode
+	}
+}
+
+func (w routeResponseWriter) Flush() {}
+
+// ServeRoute executes the route registered with the "routeName" in-process,
+// without a network round trip, and returns its captured response.
+// The "params" are the route's dynamic path parameters' values, in order.
+// The "body" can be nil, a byte slice, a string, an io.Reader
+// or any value to be sent as JSON.
+// Offline routes, see `None`, can be executed as well.
+//
+// By default the request passes through the whole Application,
+// including its router wrappers and the route's middleware,
+// set the `ServeRouteOptions.SkipMiddleware` to execute only the route's last handler.
+//
+// Example Code:
+//  app.Get("/users/{id:int64}", getUser).Name = "user"
+//  app.Get("/users/{id:int64}/orders", getOrders).Name = "orders"
+//  app.Get("/dashboard/{id:int64}", func(ctx iris.Context) {
+//      id := ctx.Params().GetInt64Default("id", 0)
+//      opts := iris.ServeRouteOptions{Header: ctx.Request().Header, Context: ctx.Request().Context()}
+//      user, _ := app.ServeRoute("user", []interface{}{id}, nil, opts)
+//      orders, _ := app.ServeRoute("orders", []interface{}{id}, nil, opts)
+//      [...]
+//  })
+func (app *Application) ServeRoute(routeName string, params []interface{}, body interface{}, options ...ServeRouteOptions) (*RouteResponse, error) {
+	route := app.GetRoute(routeName)
+	if route == nil {
+		return nil, fmt.Errorf("iris: route %q not found", routeName)
+	}
+
+	var opts ServeRouteOptions
+	if len(options) > 0 {
+		opts = options[0]
+	}
+
+	if err := app.Build(); err != nil {
+		return nil, err
+	}
+
+	path := router.NewRoutePathReverser(app).Path(routeName, params...)
+	if path == "" {
+		return nil, fmt.Errorf("iris: route %q: unable to resolve path", routeName)
+	}
+
+	var (
+		r           io.Reader
+		contentType string
+	)
+	switch v := body.(type) {
+	case nil:
+	case []byte:
+		r = bytes.NewReader(v)
+	case string:
+		r = strings.NewReader(v)
+	case io.Reader:
+		r = v
+	default:
+		b, err := stdJSON.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		r = bytes.NewReader(b)
+		contentType = context.ContentJSONHeaderValue
+	}
+
+	reqCtx := opts.Context
+	if reqCtx == nil {
+		reqCtx = stdContext.Background()
+	}
+
+	req, err := http.NewRequestWithContext(reqCtx, route.Method, path, r)
+	if err != nil {
+		return nil, err
+	}
+
+	req.RequestURI = path
+	req.URL.RawQuery = opts.Query.Encode()
+	if len(req.URL.RawQuery) > 0 {
+		req.RequestURI += "?" + req.URL.RawQuery
+	}
+
+	req.Host = app.config.GetVHost()
+	if req.Host == "" {
+		req.Host = "localhost"
+	}
+	if subdomain := route.Subdomain; subdomain != "" && subdomain != router.SubdomainWildcardIndicator {
+		req.Host = subdomain + req.Host
+	}
+	req.RemoteAddr = "127.0.0.1:0"
+
+	for key, values := range opts.Header {
+		if key == "Content-Length" || key == "Accept-Encoding" { // captured bodies are not compressed.
+			continue
+		}
+		req.Header[key] = append([]string(nil), values...)
+	}
+	if contentType != "" {
+		req.Header.Set(context.ContentTypeHeaderKey, contentType)
+	}
+
+	resp := &RouteResponse{Header: make(http.Header)}
+	w := routeResponseWriter{resp}
+	if !opts.SkipMiddleware {
+		app.ServeHTTP(w, req)
+	} else {
+		ctx := app.ContextPool.Acquire(w, req)
+		for i, p := range route.Tmpl().Params {
+			if i < len(params) {
+				ctx.Params().Set(p.Name, fmt.Sprint(params[i]))
+			}
+		}
+
+		ctx.SetCurrentRoute(route.ReadOnly)
+		ctx.Do(route.FinalHandlers())
+		app.ContextPool.Release(ctx)
+	}
+
+	if resp.StatusCode == 0 {
+		resp.StatusCode = http.StatusOK
+	}
+
+	return resp, nil
+}
+
 // Minifier returns the minifier instance.
 // By default it can minifies:
 // - text/html
@@ -394,6 +675,102 @@ func (app *Application) RegisterView(viewEngine view.Engine) {
 	app.view.Register(viewEngine)
 }
 
+// AddViewFunc adds a template function to the registry shared by all view engines,
+// e.g. URL, i18n, asset or CSRF helpers.
+// The function is adapted to the registered engine (and any later registered one),
+// e.g. to the html/template FuncMap and to the pongo2 globals and filters,
+// so switching engines does not require to register the helpers again.
+//
+// Example Code:
+//  app.AddViewFunc("upper", strings.ToUpper)
+//  // HTML: {{ upper .Name }}
+//  // Django: {{ upper(name) }} or {{ name|upper }}
+//
+// A function which accepts an `iris.Context` as its first input argument
+// receives the context of the request which the template is rendered for
+// (the HTML, Django and Jet engines support it, the rest receive a nil context), e.g.
+//  app.AddViewFunc("username", func(ctx iris.Context) string {
+//      return ctx.User().GetUsername()
+//  })
+//  // HTML: {{ username }}
+//
+// The builtin helpers, added on `Build`, are:
+// - urlFor(routeName, args...), the path of a named route,
+// the missing leading parameters are filled from the current request's ones
+// - tr(key, args...), the translation of a key in the request's language, see `I18n`
+// - csrfField(), the hidden form field of the request's CSRF token, see `view.CSRFField`
+// - sanitize(html, policy), the html sanitized by a policy of the `context.HTMLSanitizers`
+// - flush(), sends the output rendered so far to the client, see `Context.ViewStream`
+// - asset(name), the fingerprinted path of a static file, see `Party.HandleAssets`.
+func (app *Application) AddViewFunc(funcName string, funcBody interface{}) {
+	app.view.AddFunc(funcName, funcBody)
+}
+
+// trViewFunc is the request-aware "tr" template function,
+// it translates the "key" to the request's language: {{ tr "key" args }}
+// or to the given language: {{ tr "lang" "key" args }}.
+func (app *Application) trViewFunc(ctx *context.Context, keyOrLang string, args ...interface{}) string {
+	if len(args) > 0 {
+		if key, ok := args[0].(string); ok {
+			if _, _, isLang := app.I18n.TryMatchString(keyOrLang); isLang {
+				return app.I18n.Tr(keyOrLang, key, args[1:]...)
+			}
+		}
+	}
+
+	if ctx == nil { // outside of a request, e.g. `ViewToString`.
+		return app.I18n.Tr("", keyOrLang, args...)
+	}
+
+	return ctx.Tr(keyOrLang, args...)
+}
+
+// urlForViewFunc returns the request-aware "urlFor" template function,
+// it returns the path of a route by its name and its parameters' values:
+// {{ urlFor "user.post" 42 7 }}.
+// The missing leading parameters are filled from the current request's ones,
+// e.g. {{ urlFor "user.post" 7 }} renders /users/42/posts/7 on a /users/42 page.
+func (app *Application) urlForViewFunc(rv *router.RoutePathReverser) func(*context.Context, string, ...interface{}) string {
+	return func(ctx *context.Context, routeName string, args ...interface{}) string {
+		if ctx != nil {
+			if r := app.GetRoute(routeName); r != nil {
+				params := r.Tmpl().Params
+				if missing := len(params) - len(args); missing > 0 {
+					values := make([]interface{}, 0, len(params))
+					for _, p := range params[:missing] {
+						value := ctx.Params().Get(p.Name)
+						if value == "" {
+							break
+						}
+						values = append(values, value)
+					}
+
+					if len(values) == missing {
+						args = append(values, args...)
+					}
+				}
+			}
+		}
+
+		return rv.Path(routeName, args...)
+	}
+}
+
+// assetViewFunc returns the "asset" template function,
+// it returns the fingerprinted path of a static file of the registered assets:
+// {{ asset "app.js" }} renders /assets/app.3f9c1b2a.js.
+func assetViewFunc(assets []*router.Assets) func(string) string {
+	return func(name string) string {
+		for _, a := range assets {
+			if p, ok := a.Lookup(name); ok {
+				return p
+			}
+		}
+
+		return assets[0].Path(name)
+	}
+}
+
 // View executes and writes the result of a template file to the writer.
 //
 // First parameter is the writer to write the parsed template.
@@ -413,6 +790,39 @@ func (app *Application) View(writer io.Writer, filename string, layout string, b
 	return app.view.ExecuteWriter(writer, filename, layout, bindingData)
 }
 
+// ViewToWriter renders a template to the writer outside of the request cycle,
+// e.g. e-mails and background jobs, with the same templates, template functions
+// and layout as the HTTP responses. The engine's layout is used,
+// unless a "layout" (or `NoLayout`) is given.
+//
+// Note that the view engine is loaded and the framework's template functions
+// (e.g. "urlpath" and "tr") are added on `Build`, which is called by `Listen` and `Run`.
+//
+// Example Code:
+//  app.ViewToWriter(emailBody, "emails/welcome.html", iris.Map{"Name": user.Name})
+func (app *Application) ViewToWriter(writer io.Writer, filename string, bindingData interface{}, layout ...string) error {
+	if !app.view.Registered() {
+		err := errors.New("view engine is missing, use `RegisterView`")
+		app.logger.Error(err)
+		return err
+	}
+
+	return app.view.ToWriter(writer, filename, bindingData, layout...)
+}
+
+// ViewToString works like `ViewToWriter` but it returns the rendered template as string.
+//
+// Example Code:
+//  body, err := app.ViewToString("emails/welcome.html", iris.Map{"Name": user.Name}, iris.NoLayout)
+func (app *Application) ViewToString(filename string, bindingData interface{}, layout ...string) (string, error) {
+	var b strings.Builder
+	if err := app.ViewToWriter(&b, filename, bindingData, layout...); err != nil {
+		return "", err
+	}
+
+	return b.String(), nil
+}
+
 // ConfigureHost accepts one or more `host#Configuration`, these configurators functions
 // can access the host created by `app.Run` or `app.Listen`,
 // they're being executed when application is ready to being served to the public.
@@ -435,6 +845,25 @@ func (app *Application) ConfigureHost(configurators ...host.Configurator) *Appli
 	return app
 }
 
+// OnReady registers a callback which is fired when a host
+// is ready to accept connections. The callback receives the actual bound address,
+// useful when listening on a random port (":0"), e.g. on tests.
+//
+// Usage:
+//  app.OnReady(func(info iris.ReadyInfo) {
+//   fmt.Println(info.URL)
+//  })
+//  app.Listen("127.0.0.1:0")
+//
+// Should be registered before the `app.Run` or `app.Listen` methods.
+func (app *Application) OnReady(cb func(ReadyInfo)) *Application {
+	return app.ConfigureHost(func(su *host.Supervisor) {
+		su.RegisterOnServe(func(h host.TaskHost) {
+			cb(h.Ready())
+		})
+	})
+}
+
 // NewHost accepts a standard *http.Server object,
 // completes the necessary missing parts of that "srv"
 // and returns a new, ready-to-use, host (supervisor).
@@ -484,11 +913,28 @@ func (app *Application) NewHost(srv *http.Server) *host.Supervisor {
 	// the below schedules some tasks that will run among the server
 
 	if !app.config.DisableStartupLog {
-		// show the available info to exit from app.
-		su.RegisterOnServe(host.WriteStartupLogOnServe(app.logger.Printer.Output)) // app.logger.Writer -> Info
+		if app.config.StartupLogJSON {
+			// machine-readable summary for orchestration tools.
+			su.RegisterOnServe(host.WriteStartupJSONOnServe(app.logger.Printer.Output))
+		} else {
+			// show the available info to exit from app.
+			su.RegisterOnServe(host.WriteStartupLogOnServe(app.logger.Printer.Output)) // app.logger.Writer -> Info
+		}
 		// app.logger.Debugf("Host: register startup notifier")
 	}
 
+	if os.Getenv("NOTIFY_SOCKET") != "" {
+		// running as a systemd Type=notify service,
+		// report readiness on the first host.
+		su.RegisterOnServe(func(host.TaskHost) {
+			app.systemdNotifyOnce.Do(func() {
+				if err := host.NotifySystemd("READY=1"); err != nil {
+					app.logger.Debugf("Host: systemd notify: %v", err)
+				}
+			})
+		})
+	}
+
 	if !app.config.DisableInterruptHandler {
 		// when CTRL/CMD+C pressed.
 		shutdownTimeout := 10 * time.Second
@@ -535,6 +981,32 @@ func (app *Application) Shutdown(ctx stdContext.Context) error {
 	return nil
 }
 
+// ReloadCertificates loads the TLS certificates of all the application's hosts
+// which serve through the `TLS` runner again and swaps them, without a restart.
+// Hosts that do not serve TLS certificate files are skipped.
+// Returns an error on the first failure, otherwise nil.
+//
+// See `host.WatchCertificates` to reload them automatically on file changes.
+func (app *Application) ReloadCertificates() error {
+	app.mu.Lock()
+	defer app.mu.Unlock()
+
+	for i, su := range app.Hosts {
+		if err := su.ReloadCertificates(); err != nil {
+			if err == host.ErrCertificatesNotReloadable {
+				continue
+			}
+
+			app.logger.Debugf("Host[%d]: Error while trying to reload certificates", i)
+			return err
+		}
+
+		app.logger.Debugf("Host[%d]: Certificates reloaded", i)
+	}
+
+	return nil
+}
+
 // Build sets up, once, the framework.
 // It builds the default router with its default macros
 // and the template functions that are very-closed to iris.
@@ -567,6 +1039,12 @@ func (app *Application) Build() error {
 		app.logger.SetLevel(app.config.LogLevel)
 	}
 
+	var invalidProxies []string
+	app.config.trustedProxyNets, invalidProxies = netutil.ParseIPNets(app.config.TrustedProxies)
+	for _, proxy := range invalidProxies {
+		app.logger.Warnf("Application: trusted proxies: %q is not a valid CIDR or IP address, it's ignored", proxy)
+	}
+
 	if app.defaultMode { // the app.I18n and app.View will be not available until Build.
 		if !app.I18n.Loaded() {
 			for _, s := range []string{"./locales/*/*", "./locales/*", "./translations"} {
@@ -596,25 +1074,61 @@ func (app *Application) Build() error {
 	}
 
 	if app.I18n.Loaded() {
-		// {{ tr "lang" "key" arg1 arg2 }}
-		app.view.AddFunc("tr", app.I18n.Tr)
+		// {{ tr "key" arg1 arg2 }} or {{ tr "lang" "key" arg1 arg2 }}
+		app.view.AddFunc("tr", app.trViewFunc)
 		app.Router.PrependRouterWrapper(app.I18n.Wrapper())
 	}
 
+	// here is where we declare the closed-relative framework functions,
+	// shared by the application's and the Party view engines.
+	// Each engine has their defaults, i.e yield,render,render_r,partial, params...
+	rv := router.NewRoutePathReverser(app.APIBuilder)
+	app.view.AddFunc("urlpath", rv.Path)
+	// app.view.AddFunc("url", rv.URL)
+	funcs := app.view.Funcs()
+	for funcName, funcBody := range map[string]interface{}{
+		"urlFor":    app.urlForViewFunc(rv),
+		"csrfField": view.CSRFField,
+		"sanitize":  view.SanitizeHTML,
+		"flush":     view.Flush,
+	} {
+		if _, exists := funcs[funcName]; !exists { // do not override the developer's ones.
+			app.view.AddFunc(funcName, funcBody)
+		}
+	}
+
+	if assets := app.APIBuilder.GetAssets(); len(assets) > 0 {
+		if _, exists := funcs["asset"]; !exists {
+			app.view.AddFunc("asset", assetViewFunc(assets))
+		}
+	}
+
 	if app.view.Registered() {
 		app.logger.Debugf("Application: view engine %q is registered", app.view.Name())
 		// view engine
-		// here is where we declare the closed-relative framework functions.
-		// Each engine has their defaults, i.e yield,render,render_r,partial, params...
-		rv := router.NewRoutePathReverser(app.APIBuilder)
-		app.view.AddFunc("urlpath", rv.Path)
-		// app.view.AddFunc("url", rv.URL)
 		if err := app.view.Load(); err != nil {
 			app.logger.Errorf("View Builder: %v", err)
 			return err
 		}
 	}
 
+	if engines := app.APIBuilder.GetViewEngines(); len(engines) > 0 {
+		// the Party view engines share the same template functions as the application's one.
+		funcs := app.view.Funcs()
+		for _, engine := range engines {
+			if funcer, ok := engine.(view.EngineFuncer); ok {
+				for funcName, funcBody := range funcs {
+					funcer.AddFunc(funcName, funcBody)
+				}
+			}
+
+			if err := engine.Load(); err != nil {
+				app.logger.Errorf("View Builder: %s: %v", engine.Name(), err)
+				return err
+			}
+		}
+	}
+
 	if !app.Router.Downgraded() {
 		// router
 		if _, err := injectLiveReload(app); err != nil {
@@ -642,6 +1156,12 @@ func (app *Application) Build() error {
 		app.HTTPErrorHandler = routerHandler
 		// re-build of the router from outside can be done with
 		// app.RefreshRouter()
+
+		if os.Getenv("IRIS_POLICY_REPORT") != "" {
+			// e.g. $ IRIS_POLICY_REPORT=1 go run main.go
+			// prints the effective policies of each route for security reviews.
+			app.logger.Printer.Write([]byte(app.PolicyReport().String())) // nolint:errcheck
+		}
 	}
 
 	// if end := time.Since(start); end.Seconds() > 5 {
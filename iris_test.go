@@ -0,0 +1,161 @@
+package iris
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/kataras/iris/v12/view"
+)
+
+func TestServeRoute(t *testing.T) {
+	app := New()
+	app.UseRouter(func(ctx Context) {
+		ctx.Header("X-Router", "true")
+		ctx.Next()
+	})
+
+	auth := func(ctx Context) {
+		if ctx.GetHeader("Authorization") == "" {
+			ctx.StopWithStatus(StatusUnauthorized)
+			return
+		}
+
+		ctx.Next()
+	}
+
+	app.Get("/users/{id:int64}", auth, func(ctx Context) {
+		ctx.Writef("%d:%s", ctx.Params().GetInt64Default("id", 0), ctx.URLParam("lang"))
+	}).Name = "user"
+	app.None("/echo", func(ctx Context) {
+		var v Map
+		if err := ctx.ReadJSON(&v); err != nil {
+			ctx.StopWithError(StatusBadRequest, err)
+			return
+		}
+
+		ctx.StatusCode(StatusCreated)
+		ctx.WriteString(v["message"].(string))
+	}).Name = "echo"
+	app.Get("/dashboard/{id:int64}", func(ctx Context) {
+		resp, err := app.ServeRoute("user", []interface{}{ctx.Params().GetInt64Default("id", 0)}, nil, ServeRouteOptions{
+			Header:  ctx.Request().Header,
+			Context: ctx.Request().Context(),
+		})
+		if err != nil {
+			ctx.StopWithError(StatusInternalServerError, err)
+			return
+		}
+
+		ctx.StatusCode(resp.StatusCode)
+		ctx.Write(resp.Body)
+	}).Name = "dashboard"
+
+	tests := []struct {
+		routeName      string
+		params         []interface{}
+		body           interface{}
+		options        ServeRouteOptions
+		expectedStatus int
+		expectedBody   string
+	}{
+		{"user", []interface{}{42}, nil, ServeRouteOptions{}, StatusUnauthorized, ""},
+		{"user", []interface{}{42}, nil, ServeRouteOptions{Header: http.Header{"Authorization": {"Bearer x"}}, Query: map[string][]string{"lang": {"en"}}},
+			StatusOK, "42:en"},
+		{"user", []interface{}{42}, nil, ServeRouteOptions{SkipMiddleware: true}, StatusOK, "42:"},
+		{"echo", nil, Map{"message": "hello"}, ServeRouteOptions{}, StatusCreated, "hello"},
+	}
+
+	for i, tt := range tests {
+		resp, err := app.ServeRoute(tt.routeName, tt.params, tt.body, tt.options)
+		if err != nil {
+			t.Fatalf("[%d] %v", i, err)
+		}
+
+		if resp.StatusCode != tt.expectedStatus || (tt.expectedBody != "" && string(resp.Body) != tt.expectedBody) {
+			t.Fatalf("[%d] expected: %d %s but got: %d %s", i, tt.expectedStatus, tt.expectedBody, resp.StatusCode, resp.Body)
+		}
+
+		if expected := !tt.options.SkipMiddleware; (resp.Header.Get("X-Router") == "true") != expected {
+			t.Fatalf("[%d] expected router wrapper execution: %v", i, expected)
+		}
+	}
+
+	if _, err := app.ServeRoute("missing", nil, nil); err == nil {
+		t.Fatalf("expected a route not found error")
+	}
+
+	// Internal composition.
+	resp, err := app.ServeRoute("dashboard", []interface{}{7}, nil, ServeRouteOptions{Header: http.Header{"Authorization": {"Bearer x"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "7:"; resp.StatusCode != StatusOK || string(resp.Body) != expected {
+		t.Fatalf("expected: %s but got: %d %s", expected, resp.StatusCode, resp.Body)
+	}
+}
+
+func TestViewToString(t *testing.T) {
+	app := New()
+	app.RegisterView(HTML(fstest.MapFS{
+		"emails/welcome.html": {Data: []byte(`Welcome {{ upper .Name }}, see {{ urlpath "profile" .Name }}`)},
+		"layouts/email.html":  {Data: []byte(`<body>{{ yield }}</body>`)},
+	}, ".html").Layout("layouts/email.html"))
+	app.AddViewFunc("upper", strings.ToUpper)
+	app.Get("/profile/{name}", func(ctx Context) {}).Name = "profile"
+
+	if err := app.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	body, err := app.ViewToString("emails/welcome", Map{"Name": "iris"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "<body>Welcome IRIS, see /profile/iris</body>"; body != expected {
+		t.Fatalf("expected: %q but got: %q", expected, body)
+	}
+
+	var b strings.Builder
+	if err = app.ViewToWriter(&b, "emails/welcome.html", Map{"Name": "iris"}, NoLayout); err != nil {
+		t.Fatal(err)
+	}
+	if expected := "Welcome IRIS, see /profile/iris"; b.String() != expected {
+		t.Fatalf("expected: %q but got: %q", expected, b.String())
+	}
+
+	if _, err = New().ViewToString("index.html", nil); err == nil {
+		t.Fatalf("expected an error without a registered view engine")
+	}
+}
+
+func TestViewContextFuncs(t *testing.T) {
+	app := New()
+	app.RegisterView(HTML(fstest.MapFS{
+		"user.html": {Data: []byte(`{{ urlFor "post" 7 }}|{{ csrfField }}|{{ who "!" }}`)},
+	}, ".html"))
+	app.AddViewFunc("who", func(ctx Context, suffix string) string {
+		return ctx.Path() + suffix
+	})
+	app.Use(func(ctx Context) {
+		ctx.Values().Set(view.CSRFTokenContextKey, `a"b`)
+		ctx.Next()
+	})
+	app.Get("/users/{id}", func(ctx Context) {
+		if err := ctx.View("user.html"); err != nil {
+			ctx.StopWithError(StatusInternalServerError, err)
+		}
+	}).Name = "user"
+	app.Get("/users/{id}/posts/{postID}", func(ctx Context) {}).Name = "post"
+
+	resp, err := app.ServeRoute("user", []interface{}{42}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `/users/42/posts/7|<input type="hidden" name="csrf.token" value="a&#34;b">|/users/42!`
+	if resp.StatusCode != StatusOK || string(resp.Body) != expected {
+		t.Fatalf("expected: %s but got: %d %s", expected, resp.StatusCode, resp.Body)
+	}
+}
@@ -0,0 +1,152 @@
+// Package badger implements the kv.Store on top of a Badger database,
+// the expiration of the keys is handled by Badger itself.
+package badger
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/kv"
+
+	"github.com/dgraph-io/badger/v2"
+)
+
+// DefaultFileMode used as the default "fileMode" for creating the database's directory.
+var DefaultFileMode os.FileMode = 0755
+
+// Store is the Badger (key-value file-based) store.
+type Store struct {
+	// Service is the underline badger database connection,
+	// it's initialized at `New` or `NewFromDB`.
+	Service *badger.DB
+}
+
+var _ kv.Store = (*Store)(nil)
+
+// New opens, or creates, the Badger database of the "directoryPath", e.g. "./data/kv",
+// and returns a new store.
+func New(directoryPath string) (*Store, error) {
+	if directoryPath == "" {
+		return nil, errors.New("kv/badger: directoryPath is required")
+	}
+
+	if err := os.MkdirAll(directoryPath, DefaultFileMode); err != nil {
+		return nil, err
+	}
+
+	opts := badger.DefaultOptions(directoryPath)
+	opts.Logger = context.DefaultLogger("kv.badger").DisableNewLine()
+
+	service, err := badger.Open(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewFromDB(service), nil
+}
+
+// NewFromDB same as `New` but accepts an already-created badger connection instead.
+func NewFromDB(service *badger.DB) *Store {
+	return &Store{Service: service}
+}
+
+// Get returns the value of the "key" or `kv.ErrNotFound`.
+func (s *Store) Get(key string) (value []byte, err error) {
+	err = s.Service.View(func(txn *badger.Txn) error {
+		item, err := txn.Get([]byte(key))
+		if err != nil {
+			return err
+		}
+
+		value, err = item.ValueCopy(nil)
+		return err
+	})
+
+	return value, notFound(err)
+}
+
+func notFound(err error) error {
+	if err == badger.ErrKeyNotFound {
+		return kv.ErrNotFound
+	}
+
+	return err
+}
+
+// Set stores the "value" of the "key", it expires after "ttl".
+func (s *Store) Set(key string, value []byte, ttl time.Duration) error {
+	return s.Service.Update(func(txn *badger.Txn) error {
+		e := badger.NewEntry([]byte(key), value)
+		if ttl > 0 {
+			e = e.WithTTL(ttl)
+		}
+
+		return txn.SetEntry(e)
+	})
+}
+
+// Delete removes the "key".
+func (s *Store) Delete(key string) error {
+	return s.Service.Update(func(txn *badger.Txn) error {
+		return txn.Delete([]byte(key))
+	})
+}
+
+// TTL returns the remaining lifetime of the "key".
+func (s *Store) TTL(key string) (ttl time.Duration, err error) {
+	err = s.Service.View(func(txn *badger.Txn) error {
+		item, err := txn.Get([]byte(key))
+		if err != nil {
+			return err
+		}
+
+		if expiresAt := item.ExpiresAt(); expiresAt > 0 {
+			ttl = time.Until(time.Unix(int64(expiresAt), 0))
+		} else {
+			ttl = kv.NoExpiration
+		}
+
+		return nil
+	})
+
+	return ttl, notFound(err)
+}
+
+// Iterate calls the "fn" for the keys starting with the "prefix", in lexical order.
+func (s *Store) Iterate(prefix string, fn func(key string, value []byte) bool) error {
+	p := []byte(prefix)
+
+	return s.Service.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.Prefix = p
+
+		iter := txn.NewIterator(opts)
+		defer iter.Close()
+
+		for iter.Seek(p); iter.ValidForPrefix(p); iter.Next() {
+			item := iter.Item()
+
+			stop := false
+			err := item.Value(func(value []byte) error {
+				stop = !fn(string(item.Key()), value)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+
+			if stop {
+				break
+			}
+		}
+
+		return nil
+	})
+}
+
+// Close closes the Badger database.
+func (s *Store) Close() error {
+	return s.Service.Close()
+}
@@ -0,0 +1,208 @@
+// Package boltdb implements the kv.Store on top of a BoltDB (bbolt) file.
+package boltdb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/kataras/iris/v12/kv"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// DefaultFileMode used as the default "fileMode" for creating
+// the database's directory and file.
+var DefaultFileMode os.FileMode = 0755
+
+// DefaultBucket is the bucket which `New` stores the keys to.
+var DefaultBucket = "kv"
+
+// Store is the BoltDB (file-based) key-value store.
+// Each value is prefixed by its expiration (unix nanoseconds, zero if it never expires),
+// the expired keys are removed on `New` and skipped on reads.
+type Store struct {
+	bucket []byte
+	// Service is the underline BoltDB database connection,
+	// it's initialized at `New` or `NewFromDB`.
+	Service *bolt.DB
+}
+
+var _ kv.Store = (*Store)(nil)
+
+// New opens, or creates, the BoltDB file of the "path", e.g. "./data/app.db",
+// and returns a new store of the `DefaultBucket`.
+func New(path string) (*Store, error) {
+	if path == "" {
+		return nil, errors.New("kv/boltdb: path is required")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), DefaultFileMode); err != nil {
+		return nil, err
+	}
+
+	service, err := bolt.Open(path, DefaultFileMode, &bolt.Options{Timeout: 20 * time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	return NewFromDB(service, DefaultBucket)
+}
+
+// NewFromDB same as `New` but accepts an already-created boltdb connection instead,
+// so many stores, of different buckets, can share the same file.
+func NewFromDB(service *bolt.DB, bucketName string) (*Store, error) {
+	s := &Store{bucket: []byte(bucketName), Service: service}
+
+	err := service.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists(s.bucket)
+		if err != nil {
+			return err
+		}
+
+		return s.cleanup(b)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// cleanup removes the expired keys.
+func (s *Store) cleanup(b *bolt.Bucket) error {
+	var expired [][]byte
+
+	now := time.Now()
+	c := b.Cursor()
+	for k, v := c.First(); k != nil; k, v = c.Next() {
+		if _, ok := decode(v, now); !ok {
+			expired = append(expired, append([]byte(nil), k...))
+		}
+	}
+
+	for _, k := range expired {
+		if err := b.Delete(k); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+const headerLen = 8
+
+func encode(value []byte, ttl time.Duration) []byte {
+	b := make([]byte, headerLen+len(value))
+	if ttl > 0 {
+		binary.BigEndian.PutUint64(b, uint64(time.Now().Add(ttl).UnixNano()))
+	}
+	copy(b[headerLen:], value)
+	return b
+}
+
+// expiration returns the expiration time of an encoded value, zero if it never expires.
+func expiration(b []byte) time.Time {
+	if len(b) < headerLen {
+		return time.Time{}
+	}
+
+	if n := binary.BigEndian.Uint64(b); n > 0 {
+		return time.Unix(0, int64(n))
+	}
+
+	return time.Time{}
+}
+
+// decode returns the value of an encoded one and reports whether it's valid.
+func decode(b []byte, now time.Time) ([]byte, bool) {
+	if len(b) < headerLen {
+		return nil, false
+	}
+
+	if expires := expiration(b); !expires.IsZero() && !now.Before(expires) {
+		return nil, false
+	}
+
+	return b[headerLen:], true
+}
+
+// Get returns the value of the "key" or `kv.ErrNotFound`.
+func (s *Store) Get(key string) (value []byte, err error) {
+	err = s.Service.View(func(tx *bolt.Tx) error {
+		v, ok := decode(tx.Bucket(s.bucket).Get([]byte(key)), time.Now())
+		if !ok {
+			return kv.ErrNotFound
+		}
+
+		value = append([]byte(nil), v...) // valid only inside the transaction.
+		return nil
+	})
+
+	return
+}
+
+// Set stores the "value" of the "key", it expires after "ttl".
+func (s *Store) Set(key string, value []byte, ttl time.Duration) error {
+	return s.Service.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(s.bucket).Put([]byte(key), encode(value, ttl))
+	})
+}
+
+// Delete removes the "key".
+func (s *Store) Delete(key string) error {
+	return s.Service.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(s.bucket).Delete([]byte(key))
+	})
+}
+
+// TTL returns the remaining lifetime of the "key".
+func (s *Store) TTL(key string) (ttl time.Duration, err error) {
+	err = s.Service.View(func(tx *bolt.Tx) error {
+		now := time.Now()
+		b := tx.Bucket(s.bucket).Get([]byte(key))
+		if _, ok := decode(b, now); !ok {
+			return kv.ErrNotFound
+		}
+
+		if expires := expiration(b); !expires.IsZero() {
+			ttl = expires.Sub(now)
+		} else {
+			ttl = kv.NoExpiration
+		}
+
+		return nil
+	})
+
+	return
+}
+
+// Iterate calls the "fn" for the keys starting with the "prefix", in lexical order.
+func (s *Store) Iterate(prefix string, fn func(key string, value []byte) bool) error {
+	p := []byte(prefix)
+
+	return s.Service.View(func(tx *bolt.Tx) error {
+		now := time.Now()
+		c := tx.Bucket(s.bucket).Cursor()
+		for k, v := c.Seek(p); k != nil && bytes.HasPrefix(k, p); k, v = c.Next() {
+			value, ok := decode(v, now)
+			if !ok {
+				continue
+			}
+
+			if !fn(string(k), value) {
+				break
+			}
+		}
+
+		return nil
+	})
+}
+
+// Close closes the BoltDB database.
+func (s *Store) Close() error {
+	return s.Service.Close()
+}
@@ -0,0 +1,178 @@
+// Package kv provides a uniform key-value store interface for the small
+// persistent needs of an application, e.g. sessions (see the sessiondb/kvdb package),
+// the response cache (see `cache/client.Handler.Store`)
+// and the rate limits (see `middleware/rate.Store`),
+// so a single-binary deployment does not require a Redis server.
+//
+// The in-memory store lives in this package,
+// the file-based ones live in the boltdb and badger subpackages.
+package kv
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNotFound is returned by the `Store.Get` and `Store.TTL` methods
+// when a key does not exist or it has expired.
+var ErrNotFound = errors.New("kv: key not found")
+
+// NoExpiration is the duration returned by the `Store.TTL` method
+// for the keys which never expire.
+const NoExpiration time.Duration = -1
+
+// Store is the interface which the key-value backends implement.
+// The implementations should be safe for concurrent use.
+type Store interface {
+	// Get returns the value of the "key" or `ErrNotFound`.
+	Get(key string) ([]byte, error)
+	// Set stores the "value" of the "key",
+	// it expires after "ttl", a zero or negative ttl never expires.
+	Set(key string, value []byte, ttl time.Duration) error
+	// Delete removes the "key", a missing key is not an error.
+	Delete(key string) error
+	// TTL returns the remaining lifetime of the "key",
+	// `NoExpiration` if it never expires, or `ErrNotFound`.
+	TTL(key string) (time.Duration, error)
+	// Iterate calls the "fn" for the keys starting with the "prefix"
+	// (all keys if it's empty), in lexical order, until it returns false.
+	// The "value" is valid only during the call.
+	Iterate(prefix string, fn func(key string, value []byte) bool) error
+	// Close releases the resources of the store.
+	Close() error
+}
+
+type memoryEntry struct {
+	value   []byte
+	expires time.Time // zero if it never expires.
+}
+
+func (e memoryEntry) expired(now time.Time) bool {
+	return !e.expires.IsZero() && !now.Before(e.expires)
+}
+
+// Memory is the in-memory `Store`, its values do not survive a restart.
+// The expired keys are removed periodically, every 1024 new keys.
+type Memory struct {
+	mu      sync.RWMutex
+	entries map[string]memoryEntry
+	sets    int
+}
+
+var _ Store = (*Memory)(nil)
+
+// NewMemory returns a new in-memory store.
+func NewMemory() *Memory {
+	return &Memory{entries: make(map[string]memoryEntry)}
+}
+
+// purgeEvery is the number of the new keys after which the expired keys are removed.
+const purgeEvery = 1024
+
+// Get returns the value of the "key" or `ErrNotFound`.
+func (m *Memory) Get(key string) ([]byte, error) {
+	m.mu.RLock()
+	e, ok := m.entries[key]
+	m.mu.RUnlock()
+
+	if !ok || e.expired(time.Now()) {
+		return nil, ErrNotFound
+	}
+
+	return e.value, nil
+}
+
+// Set stores a copy of the "value" of the "key".
+func (m *Memory) Set(key string, value []byte, ttl time.Duration) error {
+	e := memoryEntry{value: append([]byte(nil), value...)}
+	if ttl > 0 {
+		e.expires = time.Now().Add(ttl)
+	}
+
+	m.mu.Lock()
+	if _, exists := m.entries[key]; !exists {
+		m.sets++
+		if m.sets >= purgeEvery {
+			m.sets = 0
+			m.purge(time.Now())
+		}
+	}
+	m.entries[key] = e
+	m.mu.Unlock()
+
+	return nil
+}
+
+func (m *Memory) purge(now time.Time) {
+	for key, e := range m.entries {
+		if e.expired(now) {
+			delete(m.entries, key)
+		}
+	}
+}
+
+// Delete removes the "key".
+func (m *Memory) Delete(key string) error {
+	m.mu.Lock()
+	delete(m.entries, key)
+	m.mu.Unlock()
+	return nil
+}
+
+// TTL returns the remaining lifetime of the "key".
+func (m *Memory) TTL(key string) (time.Duration, error) {
+	m.mu.RLock()
+	e, ok := m.entries[key]
+	m.mu.RUnlock()
+
+	now := time.Now()
+	if !ok || e.expired(now) {
+		return 0, ErrNotFound
+	}
+
+	if e.expires.IsZero() {
+		return NoExpiration, nil
+	}
+
+	return e.expires.Sub(now), nil
+}
+
+// Iterate calls the "fn" for the keys starting with the "prefix", in lexical order.
+func (m *Memory) Iterate(prefix string, fn func(key string, value []byte) bool) error {
+	now := time.Now()
+
+	m.mu.RLock()
+	keys := make([]string, 0, len(m.entries))
+	for key, e := range m.entries {
+		if strings.HasPrefix(key, prefix) && !e.expired(now) {
+			keys = append(keys, key)
+		}
+	}
+	m.mu.RUnlock()
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value, err := m.Get(key)
+		if err != nil { // removed or expired in the meantime.
+			continue
+		}
+
+		if !fn(key, value) {
+			break
+		}
+	}
+
+	return nil
+}
+
+// Close removes all keys.
+func (m *Memory) Close() error {
+	m.mu.Lock()
+	m.entries = make(map[string]memoryEntry)
+	m.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,138 @@
+package kv_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12/kv"
+	"github.com/kataras/iris/v12/kv/badger"
+	"github.com/kataras/iris/v12/kv/boltdb"
+)
+
+// newStores returns a new store of each backend.
+func newStores(t *testing.T) map[string]kv.Store {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	boltStore, err := boltdb.New(filepath.Join(dir, "bolt", "kv.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	badgerStore, err := badger.New(filepath.Join(dir, "badger"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return map[string]kv.Store{
+		"memory": kv.NewMemory(),
+		"boltdb": boltStore,
+		"badger": badgerStore,
+	}
+}
+
+func TestStores(t *testing.T) {
+	for name, store := range newStores(t) {
+		store := store
+		t.Run(name, func(t *testing.T) {
+			defer store.Close()
+			testStore(t, store)
+		})
+	}
+}
+
+func testStore(t *testing.T, store kv.Store) {
+	if _, err := store.Get("missing"); err != kv.ErrNotFound {
+		t.Fatalf("expected ErrNotFound but got: %v", err)
+	}
+	if _, err := store.TTL("missing"); err != kv.ErrNotFound {
+		t.Fatalf("expected ErrNotFound but got: %v", err)
+	}
+
+	for key, value := range map[string]string{"user:2": "b", "user:1": "a", "order:1": "o"} {
+		if err := store.Set(key, []byte(value), 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.Set("temp", []byte("t"), time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	if value, err := store.Get("user:1"); err != nil || string(value) != "a" {
+		t.Fatalf("expected value: a but got: %q: %v", value, err)
+	}
+
+	if ttl, err := store.TTL("user:1"); err != nil || ttl != kv.NoExpiration {
+		t.Fatalf("expected no expiration but got: %s: %v", ttl, err)
+	}
+	if ttl, err := store.TTL("temp"); err != nil || ttl <= 59*time.Minute || ttl > time.Hour {
+		t.Fatalf("expected a ttl of about an hour but got: %s: %v", ttl, err)
+	}
+
+	var keys []string
+	err := store.Iterate("user:", func(key string, value []byte) bool {
+		keys = append(keys, key+"="+string(value))
+		return true
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 2 || keys[0] != "user:1=a" || keys[1] != "user:2=b" {
+		t.Fatalf("expected the user keys in order but got: %v", keys)
+	}
+
+	n := 0
+	if err = store.Iterate("", func(string, []byte) bool { n++; return false }); err != nil || n != 1 {
+		t.Fatalf("expected the iteration to stop after the first key but got: %d: %v", n, err)
+	}
+
+	if err = store.Delete("user:1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = store.Get("user:1"); err != kv.ErrNotFound {
+		t.Fatalf("expected ErrNotFound after delete but got: %v", err)
+	}
+	if err = store.Delete("user:1"); err != nil {
+		t.Fatalf("expected no error on a missing key but got: %v", err)
+	}
+}
+
+func TestStoresExpiration(t *testing.T) {
+	for name, store := range newStores(t) {
+		store := store
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			defer store.Close()
+			testStoreExpiration(t, store)
+		})
+	}
+}
+
+// testStoreExpiration waits for more than a second,
+// the Badger expiration is stored in seconds.
+func testStoreExpiration(t *testing.T, store kv.Store) {
+	if err := store.Set("key", []byte("value"), time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := store.Get("key"); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(1100 * time.Millisecond)
+
+	if _, err := store.Get("key"); err != kv.ErrNotFound {
+		t.Fatalf("expected ErrNotFound after the expiration but got: %v", err)
+	}
+
+	if _, err := store.TTL("key"); err != kv.ErrNotFound {
+		t.Fatalf("expected ErrNotFound after the expiration but got: %v", err)
+	}
+
+	store.Iterate("", func(key string, _ []byte) bool {
+		t.Fatalf("expected the expired key to be skipped but got: %s", key)
+		return false
+	})
+}
@@ -20,6 +20,8 @@ import (
 // and send a custom error response you have to register it:
 //  app.Macros().Get("uuid").HandleError(func(ctx iris.Context, paramIndex int, err error)).
 // You can also set custom macros by `app.Macros().Register`.
+// The failing parameter function of a valid value, e.g. the max(120) of the {age:int min(18) max(120)},
+// is reported as a `*macro.ParamConstraintError`.
 //
 // See macro.HandleError to set it.
 type ParamErrorHandler = func(*context.Context, int, error) // alias.
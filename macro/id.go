@@ -0,0 +1,160 @@
+package macro
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// IDFormat describes an application-specific resource ID format,
+// e.g. the "usr_" prefixed user IDs ("usr_4f9a2c01d3b7e865").
+// See `Macros.RegisterID` to register it as a parameter type.
+type IDFormat struct {
+	// Prefix is the required prefix of the IDs, e.g. "usr_".
+	Prefix string
+	// Generate returns the random part of a new ID.
+	// Defaults to 16 lowercase hex characters.
+	Generate func() string
+	// Validate reports whether the random part of an ID is well-formed.
+	// Defaults to a non-empty alphanumeric check.
+	Validate func(s string) bool
+}
+
+// New returns a new ID of this format.
+func (f IDFormat) New() string {
+	if f.Generate != nil {
+		return f.Prefix + f.Generate()
+	}
+
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("id: %v", err))
+	}
+
+	return f.Prefix + hex.EncodeToString(b)
+}
+
+// Valid reports whether the "id" matches this format.
+func (f IDFormat) Valid(id string) bool {
+	if !strings.HasPrefix(id, f.Prefix) {
+		return false
+	}
+
+	s := id[len(f.Prefix):]
+	if f.Validate != nil {
+		return f.Validate(s)
+	}
+
+	if s == "" {
+		return false
+	}
+
+	for _, r := range s {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return false
+		}
+	}
+
+	return true
+}
+
+var (
+	errorType  = reflect.TypeOf((*error)(nil)).Elem()
+	valueTypes sync.Map // reflect.Type:struct{}.
+)
+
+// IsValueType reports whether the "typ" is the value type
+// of a parameter type registered through `Macros.RegisterID`.
+// The hero and mvc packages use it to bind path parameters to input arguments of that type.
+func IsValueType(typ reflect.Type) bool {
+	_, ok := valueTypes.Load(typ)
+	return ok
+}
+
+// RegisterID registers a new parameter type, named after the "indent",
+// for the IDs of the given "format".
+// Routes reject malformed IDs with the parameter's error code (defaults to 404, see the `else` literal)
+// before their handlers run.
+//
+// The optional "convert" function converts a valid ID to a typed value,
+// it MUST be a type of func(id string) (T, error), otherwise it panics.
+// The T type is registered as a path parameter type (see `IsValueType`)
+// so hero handlers and controllers can accept it as input argument.
+// Without a "convert" function the parameter value is the ID string itself.
+//
+// Usage:
+//  type UserID string
+//
+//  userID := macro.IDFormat{Prefix: "usr_"}
+//  app.Macros().RegisterID("user_id", userID, func(id string) (UserID, error) {
+//      return UserID(id), nil
+//  })
+//
+//  app.ConfigureContainer().Get("/users/{id:user_id else 400}", func(id UserID) string {...})
+func (ms *Macros) RegisterID(indent string, format IDFormat, convert interface{}) *Macro {
+	convertFn, valueType := makeIDConverter(convert)
+
+	evaluator := func(paramValue string) (interface{}, bool) {
+		if !format.Valid(paramValue) {
+			return fmt.Errorf("invalid %s: %s", indent, paramValue), false
+		}
+
+		if convertFn == nil {
+			return paramValue, true
+		}
+
+		v, err := convertFn(paramValue)
+		if err != nil {
+			return err, false
+		}
+
+		return v, true
+	}
+
+	m := ms.Register(indent, "", false, false, evaluator)
+	if m != nil {
+		m.valueType = valueType
+	}
+
+	return m
+}
+
+// GetByValueType returns the macro which its parameter type
+// produces values of "typ" type (see `RegisterID`), it can return nil.
+func (ms *Macros) GetByValueType(typ reflect.Type) *Macro {
+	for _, m := range *ms {
+		if m.valueType != nil && m.valueType == typ {
+			return m
+		}
+	}
+
+	return nil
+}
+
+func makeIDConverter(convert interface{}) (func(string) (interface{}, error), reflect.Type) {
+	if convert == nil {
+		return nil, nil
+	}
+
+	fn := reflect.ValueOf(convert)
+	typ := fn.Type()
+	if typ.Kind() != reflect.Func || typ.NumIn() != 1 || typ.In(0).Kind() != reflect.String ||
+		typ.NumOut() != 2 || typ.Out(1) != errorType {
+		panic(fmt.Sprintf("id: convert must be a type of func(string) (T, error) but got: %T", convert))
+	}
+
+	valueType := typ.Out(0)
+	valueTypes.Store(valueType, struct{}{})
+
+	return func(id string) (interface{}, error) {
+		out := fn.Call([]reflect.Value{reflect.ValueOf(id)})
+		if err, _ := out[1].Interface().(error); err != nil {
+			return nil, err
+		}
+
+		return out[0].Interface(), nil
+	}, valueType
+}
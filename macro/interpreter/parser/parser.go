@@ -105,6 +105,12 @@ func (p *ParamParser) Parse(paramTypes []ast.ParamType) (*ast.ParamStatement, er
 
 	for {
 		t := l.NextToken()
+		if lastParamFunc.Name != "" && (t.Type == token.IDENT || t.Type == token.ELSE || t.Type == token.RBRACE || t.Type == token.EOF) {
+			// param function without parentheses, e.g. {slug:string alphanum}.
+			stmt.Funcs = append(stmt.Funcs, lastParamFunc)
+			lastParamFunc = ast.ParamFunc{} // reset
+		}
+
 		if t.Type == token.EOF {
 			if stmt.Name == "" {
 				p.appendErr("[1:] parameter name is missing")
@@ -239,7 +239,42 @@ func TestParseParam(t *testing.T) {
 				ErrorCode: 404,
 			},
 		}, // 12
-
+		{
+			true,
+			ast.ParamStatement{
+				Src:  "{slug:string maxlen(64) alphanum}", // test param funcs without parentheses.
+				Name: "slug",
+				Type: mustLookupParamType("string"),
+				Funcs: []ast.ParamFunc{
+					{
+						Name: "maxlen",
+						Args: []string{"64"},
+					},
+					{
+						Name: "alphanum",
+					},
+				},
+				ErrorCode: 404,
+			},
+		}, // 13
+		{
+			true,
+			ast.ParamStatement{
+				Src:  "{id:int even min(1) else 400}",
+				Name: "id",
+				Type: mustLookupParamType("number"),
+				Funcs: []ast.ParamFunc{
+					{
+						Name: "even",
+					},
+					{
+						Name: "min",
+						Args: []string{"1"},
+					},
+				},
+				ErrorCode: 400,
+			},
+		}, // 14
 	}
 
 	p := new(ParamParser)
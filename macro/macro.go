@@ -257,6 +257,7 @@ type (
 		Evaluator   ParamEvaluator
 		handleError interface{}
 		funcs       []ParamFunc
+		valueType   reflect.Type // the evaluator's value type, if known, see `Macros.RegisterID`.
 	}
 
 	// ParamFuncBuilder is a func
@@ -314,13 +315,16 @@ func (m *Macro) Trailing() bool {
 }
 
 // HandleError registers a handler which will be executed
-// when a parameter evaluator returns false and a non nil value which is a type of `error`.
+// when a parameter evaluator returns false and a non nil value which is a type of `error`
+// or when a parameter function fails, i.e the "min(18)" of the "{age:int min(18)}",
+// the error is a type of `*ParamConstraintError` then.
 // The "fnHandler" value MUST BE a type of `func(iris.Context, paramIndex int, err error)`,
 // otherwise the program will receive a panic before server startup.
 // The status code of the ErrCode (`else` literal) is set
 // before the error handler but it can be modified inside the handler itself.
 func (m *Macro) HandleError(fnHandler interface{}) *Macro { // See handler.MakeFilter.
 	m.handleError = fnHandler
+	macrosChanged()
 	return m
 }
 
@@ -351,9 +355,11 @@ func (m *Macro) registerFunc(funcName string, fullFn ParamFuncBuilder) {
 		return
 	}
 
-	for _, fn := range m.funcs {
+	defer macrosChanged()
+
+	for i, fn := range m.funcs {
 		if fn.Name == funcName {
-			fn.Func = fullFn
+			m.funcs[i].Func = fullFn
 			return
 		}
 	}
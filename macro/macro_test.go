@@ -498,3 +498,104 @@ func TestConvertBuilderFunc(t *testing.T) {
 	// should panic.
 	evalFunc([]string{"1"}).Call([]reflect.Value{reflect.ValueOf("kataras")})
 }
+
+func TestParamConstraints(t *testing.T) {
+	tests := []struct {
+		src   string
+		value string
+		pass  bool
+		fn    string
+		args  []string
+	}{
+		{"{age:int min(18) max(120)}", "18", true, "", nil},                           // 0
+		{"{age:int min(18) max(120)}", "17", false, "min", []string{"18"}},            // 1
+		{"{age:int min(18) max(120)}", "121", false, "max", []string{"120"}},          // 2
+		{"{slug:string maxlen(4) alphanum}", "ab12", true, "", nil},                   // 3
+		{"{slug:string maxlen(4) alphanum}", "abc12", false, "maxlen", []string{"4"}}, // 4
+		{"{slug:string maxlen(4) alphanum}", "ab-1", false, "alphanum", nil},          // 5
+		{"{name:string minlen(2)}", "αβ", true, "", nil},                              // 6
+		{"{name:string minlen(2)}", "α", false, "minlen", []string{"2"}},              // 7
+	}
+
+	for i, tt := range tests {
+		tmpl, err := Parse(tt.src, *Defaults)
+		if err != nil {
+			t.Fatalf("tests[%d] - %v", i, err)
+		}
+
+		p := tmpl.Params[0]
+		if value, passed := p.Eval(tt.value); passed != tt.pass {
+			t.Fatalf("tests[%d] - expected pass: %v but got: %v", i, tt.pass, passed)
+		} else if !passed && value != nil {
+			t.Fatalf("tests[%d] - expected a nil value without a HandleError but got: %v", i, value)
+		}
+
+		p.HandleError = func() {} // any non-nil value, see handler.MakeFilter.
+		value, passed := p.Eval(tt.value)
+		if passed {
+			continue
+		}
+
+		cErr, ok := value.(*ParamConstraintError)
+		if !ok {
+			t.Fatalf("tests[%d] - expected a *ParamConstraintError but got: %T", i, value)
+		}
+
+		if cErr.Param != p.Name || cErr.Func != tt.fn || !reflect.DeepEqual(cErr.Args, tt.args) || cErr.Value != tt.value {
+			t.Fatalf("tests[%d] - unexpected constraint error: %#v", i, cErr)
+		}
+	}
+}
+
+func TestTemplateCache(t *testing.T) {
+	code := NewMacro("code", "", true, false, nil)
+	macros := Macros{code}
+	cache := NewTemplateCache()
+	cache.MaxEntries = 2
+
+	src := "/tenants/{tenant}/{id:code prefix(t)}"
+	tmpl, err := cache.Parse(src, macros)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tmpl.Params[1].Funcs) != 0 {
+		t.Fatalf("expected no functions before the prefix registration")
+	}
+
+	if _, err = cache.Parse(src, macros); err != nil {
+		t.Fatal(err)
+	}
+	if stats := cache.Stats(); stats.Hits != 1 || stats.Misses != 1 || stats.Entries != 1 {
+		t.Fatalf("unexpected stats: %#v", stats)
+	}
+
+	// a macro change invalidates the cached templates.
+	code.RegisterFunc("prefix", func(prefix string) func(string) bool {
+		return func(paramValue string) bool { return len(paramValue) > 0 && paramValue[:1] == prefix }
+	})
+	if tmpl, err = cache.Parse(src, macros); err != nil {
+		t.Fatal(err)
+	}
+	if _, passed := tmpl.Params[1].Eval("x1"); passed || len(tmpl.Params[1].Funcs) != 1 {
+		t.Fatalf("expected the template to be parsed again after the prefix registration")
+	}
+
+	for _, src := range []string{"/a/{id:code}", "/b/{id:code}"} {
+		if _, err = cache.Parse(src, macros); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err = cache.Parse("/{id:unknown}", macros); err == nil {
+		t.Fatalf("expected a parse error")
+	}
+
+	if stats := cache.Stats(); stats.Entries != 2 || stats.Evictions != 1 || stats.Misses != 5 {
+		t.Fatalf("unexpected stats: %#v", stats)
+	}
+
+	cache.Reset()
+	if stats := cache.Stats(); stats != (TemplateCacheStats{}) {
+		t.Fatalf("expected empty stats after reset but got: %#v", stats)
+	}
+}
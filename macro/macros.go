@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/kataras/iris/v12/macro/interpreter/ast"
 
@@ -12,6 +13,8 @@ import (
 )
 
 var (
+	alphanumEval = MustRegexp("^[a-zA-Z0-9]+$")
+
 	// String type
 	// Allows anything (single path segment, as everything except the `Path`).
 	// Its functions can be used by the rest of the macros and param types whenever not available function by name is used.
@@ -47,7 +50,21 @@ var (
 			return func(paramValue string) bool {
 				return max >= len(paramValue)
 			}
-		})
+		}).
+		// checks if param value's number of characters (runes) is at least 'min'
+		RegisterFunc("minlen", func(min int) func(string) bool {
+			return func(paramValue string) bool {
+				return utf8.RuneCountInString(paramValue) >= min
+			}
+		}).
+		// checks if param value's number of characters (runes) is not bigger than 'max'
+		RegisterFunc("maxlen", func(max int) func(string) bool {
+			return func(paramValue string) bool {
+				return max >= utf8.RuneCountInString(paramValue)
+			}
+		}).
+		// checks if param value contains only letters (upper or lowercase) and numbers
+		RegisterFunc("alphanum", alphanumEval)
 
 	// Int or number type
 	// both positive and negative numbers, actual value can be min-max int64 or min-max int32 depends on the arch.
@@ -464,6 +481,7 @@ func (ms *Macros) register(macro *Macro) bool {
 	cp = append(cp, macro)
 
 	*ms = cp
+	macrosChanged()
 	return true
 }
 
@@ -478,6 +496,7 @@ func (ms *Macros) Unregister(indent string) bool {
 			cp = cp[:len(cp)-1]
 
 			*ms = cp
+			macrosChanged()
 			return true
 		}
 	}
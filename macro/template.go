@@ -1,7 +1,9 @@
 package macro
 
 import (
+	"fmt"
 	"reflect"
+	"strings"
 
 	"github.com/kataras/iris/v12/macro/interpreter/ast"
 	"github.com/kataras/iris/v12/macro/interpreter/parser"
@@ -40,14 +42,17 @@ type TemplateParam struct {
 	TypeEvaluator ParamEvaluator  `json:"-"`
 	Funcs         []reflect.Value `json:"-"`
 
-	stringInFuncs []func(string) bool
-	canEval       bool
+	funcSpecs         []ast.ParamFunc // the parsed name and arguments of each one of the Funcs.
+	stringInFuncs     []func(string) bool
+	stringInFuncSpecs []ast.ParamFunc
+	canEval           bool
 }
 
 func (p TemplateParam) preComputed() TemplateParam {
-	for _, pfn := range p.Funcs {
+	for i, pfn := range p.Funcs {
 		if fn, ok := pfn.Interface().(func(string) bool); ok {
 			p.stringInFuncs = append(p.stringInFuncs, fn)
+			p.stringInFuncSpecs = append(p.stringInFuncSpecs, p.funcSpec(i))
 		}
 	}
 
@@ -67,22 +72,68 @@ func (p *TemplateParam) CanEval() bool {
 	return p.canEval
 }
 
+// funcSpec returns the parsed name and arguments of the "i" function.
+func (p TemplateParam) funcSpec(i int) ast.ParamFunc {
+	if i < len(p.funcSpecs) {
+		return p.funcSpecs[i]
+	}
+
+	return ast.ParamFunc{}
+}
+
 type errorInterface interface {
 	Error() string
 }
 
+// ParamConstraintError is the error which is passed to the `Macro.HandleError`
+// when a parameter value is a valid value of its type
+// but it does not pass one of the parameter's functions (constraints),
+// e.g. the "min(18)" of the "{age:int min(18) max(120)}" on a "/16" request path.
+type ParamConstraintError struct {
+	// Param is the name of the parameter, e.g. "age".
+	Param string
+	// Func is the name of the failing function, e.g. "min".
+	Func string
+	// Args are the route's arguments of the failing function, e.g. ["18"].
+	Args []string
+	// Value is the request's parameter value, e.g. "16".
+	Value string
+}
+
+// Error completes the error interface.
+func (e *ParamConstraintError) Error() string {
+	return fmt.Sprintf("parameter %q: value %q does not pass %s(%s)", e.Param, e.Value, e.Func, strings.Join(e.Args, ","))
+}
+
+// constraintFailed returns the `ParamConstraintError` of the "spec" function,
+// only when a HandleError was registered, like the type evaluator's errors.
+func (p *TemplateParam) constraintFailed(spec ast.ParamFunc, paramValue string) (interface{}, bool) {
+	if p.HandleError == nil {
+		return nil, false
+	}
+
+	return &ParamConstraintError{
+		Param: p.Name,
+		Func:  spec.Name,
+		Args:  spec.Args,
+		Value: paramValue,
+	}, false
+}
+
 // Eval is the most critical part of the TemplateParam.
 // It is responsible to return the type-based value if passed otherwise nil.
 // If the "paramValue" is the correct type of the registered parameter type
 // and all functions, if any, are passed.
+// If a function does not pass and a HandleError was registered,
+// the value is a `*ParamConstraintError` of the failing function.
 //
 // It is called from the converted macro handler (middleware)
 // from the higher-level component of "kataras/iris/macro/handler#MakeHandler".
 func (p *TemplateParam) Eval(paramValue string) (interface{}, bool) {
 	if p.TypeEvaluator == nil {
-		for _, fn := range p.stringInFuncs {
+		for i, fn := range p.stringInFuncs {
 			if !fn(paramValue) {
-				return nil, false
+				return p.constraintFailed(p.stringInFuncSpecs[i], paramValue)
 			}
 		}
 		return paramValue, true
@@ -103,11 +154,11 @@ func (p *TemplateParam) Eval(paramValue string) (interface{}, bool) {
 
 	if len(p.Funcs) > 0 {
 		paramIn := []reflect.Value{reflect.ValueOf(newValue)}
-		for _, evalFunc := range p.Funcs {
+		for i, evalFunc := range p.Funcs {
 			// or make it as func(interface{}) bool and pass directly the "newValue"
 			// but that would not be as easy for end-developer, so keep that "slower":
 			if !evalFunc.Call(paramIn)[0].Interface().(bool) { // i.e func(paramValue int) bool
-				return nil, false
+				return p.constraintFailed(p.funcSpec(i), paramValue)
 			}
 		}
 	}
@@ -164,6 +215,7 @@ func Parse(src string, macros Macros) (Template, error) {
 				continue
 			}
 			tmplParam.Funcs = append(tmplParam.Funcs, evalFn)
+			tmplParam.funcSpecs = append(tmplParam.funcSpecs, paramfn)
 		}
 
 		tmpl.Params = append(tmpl.Params, tmplParam.preComputed())
@@ -0,0 +1,160 @@
+package macro
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// macrosVersion is increased on every change of the registered macros
+// and their functions, so the templates cached before that change
+// are parsed again, see `TemplateCache`.
+var macrosVersion uint64
+
+func macrosChanged() {
+	atomic.AddUint64(&macrosVersion, 1)
+}
+
+// DefaultTemplateCacheMaxEntries is the default `TemplateCache.MaxEntries`.
+const DefaultTemplateCacheMaxEntries = 4096
+
+// DefaultTemplateCache is the template cache used by the router
+// to parse the route paths, see `TemplateCache`.
+var DefaultTemplateCache = NewTemplateCache()
+
+type (
+	// TemplateCache keeps the parsed templates by their path template (source) and macros,
+	// so applications which register and remove similar routes at runtime,
+	// e.g. per tenant, do not parse the same path template again.
+	// The cached templates are parsed again after a change of the macros,
+	// i.e. a `Macros.Register`, `Macros.Unregister`, `Macro.RegisterFunc` or `Macro.HandleError` call.
+	// Note that the parsed templates are shared, they should not be modified.
+	//
+	// It is safe for concurrent use.
+	TemplateCache struct {
+		// MaxEntries is the maximum number of the cached templates,
+		// when it's reached an entry is evicted to make room for the new one.
+		// Defaults to `DefaultTemplateCacheMaxEntries`.
+		MaxEntries int
+
+		mu      sync.RWMutex
+		entries map[string]templateCacheEntry
+		stats   TemplateCacheStats
+	}
+
+	templateCacheEntry struct {
+		tmpl    Template
+		version uint64
+	}
+
+	// TemplateCacheStats holds the statistics of a `TemplateCache`.
+	TemplateCacheStats struct {
+		// Entries is the number of the cached templates.
+		Entries int `json:"entries"`
+		// Hits is the number of the templates served from the cache.
+		Hits uint64 `json:"hits"`
+		// Misses is the number of the templates which had to be parsed.
+		Misses uint64 `json:"misses"`
+		// Evictions is the number of the templates removed to make room for new ones.
+		Evictions uint64 `json:"evictions"`
+		// ParseDuration is the total time spent on parsing the templates.
+		ParseDuration time.Duration `json:"parseDuration"`
+		// MaxParseDuration is the longest time spent on parsing a single template.
+		MaxParseDuration time.Duration `json:"maxParseDuration"`
+	}
+)
+
+// NewTemplateCache returns a new empty template cache.
+func NewTemplateCache() *TemplateCache {
+	return &TemplateCache{
+		MaxEntries: DefaultTemplateCacheMaxEntries,
+		entries:    make(map[string]templateCacheEntry),
+	}
+}
+
+// Parse returns the cached template of the "src" and "macros" or parses it, see `Parse`.
+// Templates which fail to parse are not cached.
+func (c *TemplateCache) Parse(src string, macros Macros) (Template, error) {
+	key := templateCacheKey(src, macros)
+	version := atomic.LoadUint64(&macrosVersion)
+
+	c.mu.RLock()
+	entry, ok := c.entries[key]
+	c.mu.RUnlock()
+
+	if ok && entry.version == version {
+		c.mu.Lock()
+		c.stats.Hits++
+		c.mu.Unlock()
+		return entry.tmpl, nil
+	}
+
+	start := time.Now()
+	tmpl, err := Parse(src, macros)
+	took := time.Since(start)
+
+	c.mu.Lock()
+	c.stats.Misses++
+	c.stats.ParseDuration += took
+	if took > c.stats.MaxParseDuration {
+		c.stats.MaxParseDuration = took
+	}
+
+	if err == nil {
+		if _, exists := c.entries[key]; !exists {
+			c.evict()
+		}
+		c.entries[key] = templateCacheEntry{tmpl: tmpl, version: version}
+	}
+	c.mu.Unlock()
+
+	return tmpl, err
+}
+
+func (c *TemplateCache) evict() { // protected by the caller.
+	maxEntries := c.MaxEntries
+	if maxEntries <= 0 {
+		maxEntries = DefaultTemplateCacheMaxEntries
+	}
+
+	for key := range c.entries {
+		if len(c.entries) < maxEntries {
+			return
+		}
+
+		delete(c.entries, key)
+		c.stats.Evictions++
+	}
+}
+
+// Stats returns a copy of the cache's statistics.
+func (c *TemplateCache) Stats() TemplateCacheStats {
+	c.mu.RLock()
+	stats := c.stats
+	stats.Entries = len(c.entries)
+	c.mu.RUnlock()
+
+	return stats
+}
+
+// Reset removes the cached templates and clears the statistics.
+func (c *TemplateCache) Reset() {
+	c.mu.Lock()
+	c.entries = make(map[string]templateCacheEntry)
+	c.stats = TemplateCacheStats{}
+	c.mu.Unlock()
+}
+
+// templateCacheKey returns the key of a template, the same source
+// can be parsed to different templates by different macros.
+func templateCacheKey(src string, macros Macros) string {
+	var b strings.Builder
+	b.WriteString(src)
+	for _, m := range macros {
+		fmt.Fprintf(&b, "\x00%p", m)
+	}
+
+	return b.String()
+}
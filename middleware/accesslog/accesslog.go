@@ -46,6 +46,27 @@ func GetFields(ctx *context.Context) (fields *Fields) {
 	return
 }
 
+// Set sets a custom field of this request's log entry,
+// e.g. a user ID, an order ID or whether the response was served from a cache.
+// Handlers and middlewares can call it at any point of the handlers chain,
+// the field is written by all the formatters, see the `CSV.Fields` and `CLF.Fields`
+// to write it as a separate column.
+//
+// Usage:
+//  accesslog.Set(ctx, "user_id", user.ID)
+func Set(ctx *context.Context, key string, value interface{}) {
+	GetFields(ctx).Set(key, value)
+}
+
+// SetFields same as `Set` but it sets one or more fields at once.
+// Note that the fields are written in random order, use `Set` to keep their order.
+func SetFields(ctx *context.Context, fields map[string]interface{}) {
+	store := GetFields(ctx)
+	for key, value := range fields {
+		store.Set(key, value)
+	}
+}
+
 // Skip called when a specific route should be skipped from the logging process.
 // It's an easy to use alternative for iris.NewConditionalHandler.
 func Skip(ctx *context.Context) {
@@ -77,6 +98,9 @@ type (
 	// See `AccessLog.Clock` field.
 	Clock     interface{ Now() time.Time }
 	clockFunc func() time.Time
+	// contextClock is the default Clock,
+	// it uses the request's `Context.Now` when available.
+	contextClock struct{}
 )
 
 // Now completes the `Clock` interface.
@@ -84,6 +108,11 @@ func (c clockFunc) Now() time.Time {
 	return c()
 }
 
+// Now completes the `Clock` interface.
+func (contextClock) Now() time.Time {
+	return time.Now()
+}
+
 var (
 	// UTC returns time with UTC based location.
 	UTC = clockFunc(func() time.Time { return time.Now().UTC() })
@@ -127,7 +156,8 @@ type AccessLog struct {
 	// to change the time location (e.g. `UTC`).
 	//
 	// This field is used to set the time the log fired.
-	// By default the middleware is using the local time, however
+	// By default the middleware is using the request's `Context.Now` (local time),
+	// so it respects the application's clock (see `Application.SetClock`), however
 	// can be changed to `UTC` too.
 	//
 	// Do NOT touch this field if you don't know what you're doing.
@@ -136,6 +166,7 @@ type AccessLog struct {
 	// If true then the middleware will fire the logs in a separate
 	// go routine, making the request to finish first.
 	// The log will be printed based on a copy of the Request's Context instead.
+	// See `NewAsyncWriter` to write through a bounded queue instead.
 	//
 	// Defaults to false.
 	Async bool
@@ -224,6 +255,10 @@ type AccessLog struct {
 	// take the field key from the extractor itself.
 	formatter Formatter
 	broker    *Broker
+	filter    Filter
+	redactor  Redactor
+	sinks     []Sink
+	ring      *ringBuffer // see `SetRingBuffer`.
 
 	// the log instance for custom formatters.
 	logsPool *sync.Pool
@@ -233,6 +268,8 @@ type AccessLog struct {
 	remaining uint32
 	// reports whether the logger is already closed, see `Close` & `CloseContext` methods.
 	isClosed uint32
+	// reports whether the logger is paused, see `Pause` & `Resume` methods.
+	paused uint32
 }
 
 // PanicLog holds the type for the available panic log levels.
@@ -265,7 +302,7 @@ const (
 // https://github.com/kataras/iris/tree/master/_examples/logging/request-logger/accesslog-broker
 func New(w io.Writer) *AccessLog {
 	ac := &AccessLog{
-		Clock:              clockFunc(time.Now),
+		Clock:              contextClock{},
 		Delim:              defaultDelim,
 		TimeFormat:         defaultTimeFormat,
 		Blank:              nil,
@@ -322,7 +359,21 @@ func New(w io.Writer) *AccessLog {
 // file is required on program termination.
 //
 // It panics on error.
-func File(path string) *AccessLog {
+//
+// The optional "rotation" settings enable the file rotation,
+// e.g. File("access.log", Rotation{MaxSize: 100, MaxBackups: 7, Compress: true}).
+// In that case the destination is a `RotatingFile`,
+// which is buffered too and it's reopened on SIGHUP signals.
+func File(path string, rotation ...Rotation) *AccessLog {
+	if len(rotation) > 0 {
+		f, err := NewRotatingFile(path, rotation[0])
+		if err != nil {
+			panic(err)
+		}
+
+		return New(f)
+	}
+
 	f := mustOpenFile(path)
 	return New(bufio.NewReadWriter(bufio.NewReader(f), bufio.NewWriter(f)))
 }
@@ -349,15 +400,16 @@ func mustOpenFile(path string) *os.File {
 // Use its `NewListener` and `CloseListener`
 // to listen and unlisten for incoming logs.
 //
-// Should be called before serve-time.
+// It can be called at serve-time too, see `TailHandler`.
 func (ac *AccessLog) Broker() *Broker {
 	ac.mu.Lock()
 	if ac.broker == nil {
 		ac.broker = newBroker()
 	}
+	b := ac.broker
 	ac.mu.Unlock()
 
-	return ac.broker
+	return b
 }
 
 // SetOutput sets the log's output destination. Accepts one or more io.Writer values.
@@ -524,8 +576,11 @@ func (ac *AccessLog) CloseContext(ctx stdContext.Context) (err error) {
 		return
 	}
 
-	if ac.broker != nil {
-		ac.broker.close <- struct{}{}
+	ac.mu.RLock()
+	broker := ac.broker
+	ac.mu.RUnlock()
+	if broker != nil {
+		broker.close <- struct{}{}
 	}
 
 	if ac.Async {
@@ -590,6 +645,10 @@ func (ac *AccessLog) Write(p []byte) (n int, err error) {
 		}
 	}
 
+	if ac.IsPaused() {
+		return len(p), nil
+	}
+
 	ac.mu.Lock()
 	n, err = ac.Writer.Write(p)
 	ac.mu.Unlock()
@@ -685,6 +744,39 @@ func (ac *AccessLog) SetFormatter(f Formatter) *AccessLog {
 	return ac
 }
 
+// SetFilter sets a filter which reports whether a log should be printed,
+// e.g. to skip health checks and static assets
+// while errors are always captured.
+// See `FilterStatus`, `FilterSkipPaths`, `FilterSample`,
+// `FilterAll` and `FilterAny` package-level functions.
+// Returns this AccessLog instance.
+//
+// Usage:
+//  ac.SetFilter(accesslog.FilterAll(
+//   accesslog.FilterSkipPaths("/health", "/static/*"),
+//   accesslog.FilterAny(accesslog.FilterStatus(400), accesslog.FilterSample(100)),
+//  ))
+func (ac *AccessLog) SetFilter(filter Filter) *AccessLog {
+	ac.filter = filter
+	return ac
+}
+
+// SetRedactor sets a Redactor which masks sensitive
+// request headers, query parameters and body fields
+// before the log is formatted, e.g. the Authorization header and password fields.
+// See `DefaultRedaction` package-level function.
+// Returns this AccessLog instance.
+//
+// Usage:
+//  ac.SetRedactor(&accesslog.Redaction{
+//   Headers: []string{"Authorization"},
+//   Body:    []string{"password"},
+//  })
+func (ac *AccessLog) SetRedactor(redactor Redactor) *AccessLog {
+	ac.redactor = redactor
+	return ac
+}
+
 // AddFields maps one or more log entries with values extracted by the Request Context.
 // You can also add fields per request handler, look the `GetFields` package-level function.
 // Note that this method can override a key stored by a handler's fields.
@@ -693,6 +785,40 @@ func (ac *AccessLog) AddFields(setters ...FieldSetter) *AccessLog {
 	return ac
 }
 
+// Describe returns the effective options of the AccessLog,
+// it completes the iris.Describable interface so they can be
+// included on the Application's ExportConfig.
+func (ac *AccessLog) Describe() interface{} {
+	sinks := make([]string, 0, len(ac.sinks))
+	for _, s := range ac.sinks {
+		sinks = append(sinks, fmt.Sprintf("%T", s))
+	}
+
+	return map[string]interface{}{
+		"Writer":             fmt.Sprintf("%T", ac.Writer),
+		"Formatter":          fmt.Sprintf("%T", ac.formatter),
+		"Sinks":              sinks,
+		"Async":              ac.Async,
+		"Delim":              string(ac.Delim),
+		"TimeFormat":         ac.TimeFormat,
+		"LatencyRound":       ac.LatencyRound.String(),
+		"IP":                 ac.IP,
+		"BytesReceivedBody":  ac.BytesReceivedBody,
+		"BytesSentBody":      ac.BytesSentBody,
+		"BytesReceived":      ac.BytesReceived,
+		"BytesSent":          ac.BytesSent,
+		"RequestBody":        ac.RequestBody,
+		"ResponseBody":       ac.ResponseBody,
+		"BodyMinify":         ac.BodyMinify,
+		"KeepMultiLineError": ac.KeepMultiLineError,
+		"PanicLog":           ac.PanicLog,
+		"Filter":             ac.filter != nil,
+		"Redactor":           ac.redactor != nil,
+		"RingBuffer":         ac.ring != nil,
+		"Paused":             ac.IsPaused(),
+	}
+}
+
 func (ac *AccessLog) shouldReadRequestBody() bool {
 	return ac.RequestBody || ac.BytesReceived || ac.BytesReceivedBody
 
@@ -785,9 +911,12 @@ func (ac *AccessLog) after(ctx *context.Context, lat time.Duration, method, path
 		responseBody  string
 		bytesReceived int // total or body, depends on the configuration.
 		bytesSent     int
+		// the request and response bodies of a hijacked connection
+		// are not available, see `Context.Hijack`.
+		hijacked = ctx.IsHijacked()
 	)
 
-	if ac.shouldReadRequestBody() {
+	if ac.shouldReadRequestBody() && !hijacked {
 		//	any error handler stored ( ctx.SetErr or StopWith(Plain)Error )
 		if ctxErr := ctx.GetErr(); ctxErr != nil {
 			// If there is an error here
@@ -831,7 +960,7 @@ func (ac *AccessLog) after(ctx *context.Context, lat time.Duration, method, path
 		}
 	}
 
-	if ac.shouldReadResponseBody() {
+	if ac.shouldReadResponseBody() && !hijacked {
 		responseData := ctx.Recorder().Body()
 		responseBodyLength := len(responseData)
 		if ac.BytesSentBody {
@@ -858,7 +987,7 @@ func (ac *AccessLog) after(ctx *context.Context, lat time.Duration, method, path
 			}
 			bytesSent = len(b) + responseBodyLength + dateLengthProx
 		}
-	} else if ac.BytesSentBody {
+	} else if ac.BytesSentBody && !hijacked {
 		bytesSent = ctx.ResponseWriter().Written()
 	}
 
@@ -922,9 +1051,30 @@ func (ac *AccessLog) Print(ctx *context.Context,
 		defer atomic.AddUint32(&ac.remaining, ^uint32(0))
 	}
 
-	now := ac.Clock.Now()
+	var now time.Time
+	if _, ok := ac.Clock.(contextClock); ok && ctx != nil {
+		now = ctx.Now()
+	} else {
+		now = ac.Clock.Now()
+	}
+
+	if r := ac.redactor; r != nil {
+		var reqContentType, respContentType string
+		if ctx != nil {
+			reqContentType = ctx.GetContentTypeRequested()
+			respContentType = ctx.GetContentType()
+		}
+
+		query = redactQuery(r, query)
+		reqBody = redactBody(r, reqContentType, reqBody)
+		respBody = redactBody(r, respContentType, respBody)
+	}
+
+	ac.mu.RLock() // the broker can be created at serve-time, see `TailHandler`.
+	broker, ring := ac.broker, ac.ring
+	ac.mu.RUnlock()
 
-	if hasFormatter, hasBroker := ac.formatter != nil, ac.broker != nil; hasFormatter || hasBroker {
+	if hasFormatter, hasBroker, hasFilter, hasSinks, hasRing := ac.formatter != nil, broker != nil, ac.filter != nil, len(ac.sinks) > 0, ring != nil; hasFormatter || hasBroker || hasFilter || hasSinks || hasRing {
 		log := ac.logsPool.Get().(*Log)
 		log.Logger = ac
 		log.Now = now
@@ -944,6 +1094,11 @@ func (ac *AccessLog) Print(ctx *context.Context,
 		log.BytesSent = bytesSent
 		log.Ctx = ctx
 
+		if hasFilter && !ac.filter(log) {
+			ac.logsPool.Put(log)
+			return
+		}
+
 		var handled bool
 		if hasFormatter {
 			handled, err = ac.formatter.Format(log) // formatter can alter this, we wait until it's finished.
@@ -954,7 +1109,19 @@ func (ac *AccessLog) Print(ctx *context.Context,
 		}
 
 		if hasBroker { // after Format, it may want to customize the log's fields.
-			ac.broker.notify(log.Clone()) // a listener cannot edit the log as we use object pooling.
+			broker.notify(log.Clone()) // a listener cannot edit the log as we use object pooling.
+		}
+
+		if hasRing {
+			ring.add(log)
+		}
+
+		if !ac.IsPaused() {
+			for _, sink := range ac.sinks {
+				if sErr := sink.Send(log); sErr != nil && err == nil {
+					err = sErr
+				}
+			}
 		}
 
 		ac.logsPool.Put(log) // we don't need it anymore.
@@ -1016,7 +1183,9 @@ func (ac *AccessLog) Print(ctx *context.Context,
 
 	builder.WriteByte(newLine)
 
-	_, err = ac.Write(builder.Bytes())
+	if _, wErr := ac.Write(builder.Bytes()); wErr != nil {
+		err = wErr
+	}
 	builder.Reset()
 	ac.bufPool.Put(builder)
 
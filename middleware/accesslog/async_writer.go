@@ -0,0 +1,229 @@
+package accesslog
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+// QueuePolicy describes what an `AsyncWriter` does when its queue is full.
+type QueuePolicy uint8
+
+const (
+	// QueueDrop drops the new log lines when the queue is full,
+	// request handling never waits for the destination writer.
+	// See `AsyncWriter.Dropped` method.
+	QueueDrop QueuePolicy = iota
+	// QueueBlock waits until there is room in the queue.
+	QueueBlock
+)
+
+type asyncEntry struct {
+	key, value []byte
+	flush      chan error // if not nil then it's a flush request.
+}
+
+// AsyncSendFunc sends an entry of an `AsyncWriter`'s queue,
+// e.g. a message and its partition key to a broker.
+// See `NewAsyncSender`.
+type AsyncSendFunc func(key, value []byte) error
+
+// AsyncWriter is an io.Writer which pushes the log lines to a bounded queue,
+// they are written to the underlying writer by a background goroutine,
+// so a slow destination (e.g. disk or network) can't add latency to request handling.
+//
+// Usage:
+//  w := accesslog.NewAsyncWriter(file, 4096, accesslog.QueueDrop)
+//  ac := accesslog.New(w)
+//
+// The AccessLog's Close method drains the queue
+// and closes the underlying writer, if it's an io.Closer.
+type AsyncWriter struct {
+	dest   io.Writer // nil on `NewAsyncSender`.
+	send   AsyncSendFunc
+	policy QueuePolicy
+	queue  chan asyncEntry
+
+	dropped uint64
+	written uint64
+
+	mu     sync.RWMutex // protects the queue against writes after close.
+	closed bool
+	done   chan struct{}
+	err    error // the last write error, reported and reset by Flush.
+}
+
+var (
+	_ io.WriteCloser = (*AsyncWriter)(nil)
+	_ Flusher        = (*AsyncWriter)(nil)
+)
+
+// NewAsyncWriter returns a new AsyncWriter which writes to the "dest"
+// through a queue of "size" log lines, with the given "policy" for a full queue.
+func NewAsyncWriter(dest io.Writer, size int, policy QueuePolicy) *AsyncWriter {
+	send := func(_, value []byte) error {
+		_, err := dest.Write(value)
+		return err
+	}
+
+	return newAsyncWriter(dest, send, size, policy)
+}
+
+// NewAsyncSender returns a new AsyncWriter which sends its queued entries,
+// pushed by the `WriteEntry` (or `Write`, without a key) method, through the "send" function.
+// Useful for destinations which accept more than a byte slice, e.g. a keyed message of a broker.
+//
+// Usage:
+//  w := accesslog.NewAsyncSender(func(key, value []byte) error {
+//      return producer.Produce("access-logs", key, value)
+//  }, 4096, accesslog.QueueDrop)
+func NewAsyncSender(send AsyncSendFunc, size int, policy QueuePolicy) *AsyncWriter {
+	return newAsyncWriter(nil, send, size, policy)
+}
+
+func newAsyncWriter(dest io.Writer, send AsyncSendFunc, size int, policy QueuePolicy) *AsyncWriter {
+	if size <= 0 {
+		size = 1024
+	}
+
+	w := &AsyncWriter{
+		dest:   dest,
+		send:   send,
+		policy: policy,
+		queue:  make(chan asyncEntry, size),
+		done:   make(chan struct{}),
+	}
+
+	go w.run()
+	return w
+}
+
+func (w *AsyncWriter) run() {
+	defer close(w.done)
+
+	for entry := range w.queue {
+		if entry.flush != nil {
+			err := w.err
+			w.err = nil
+			if flusher, ok := w.dest.(Flusher); ok {
+				if fErr := flusher.Flush(); err == nil {
+					err = fErr
+				}
+			}
+
+			entry.flush <- err
+			continue
+		}
+
+		if err := w.send(entry.key, entry.value); err != nil {
+			w.err = err
+		} else {
+			atomic.AddUint64(&w.written, 1)
+		}
+	}
+}
+
+// Write pushes a copy of "p" to the queue.
+// If the queue is full then, depending on the policy,
+// it's dropped or it waits for room.
+// It returns io.ErrClosedPipe after Close.
+func (w *AsyncWriter) Write(p []byte) (int, error) {
+	if err := w.push(asyncEntry{value: append([]byte(nil), p...)}); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// WriteEntry pushes a copy of the "key" and "value" to the queue,
+// they are passed as they are to the send function of a `NewAsyncSender`,
+// the key is ignored by an AsyncWriter of an io.Writer.
+// If the queue is full then, depending on the policy,
+// it's dropped or it waits for room.
+// It returns io.ErrClosedPipe after Close.
+func (w *AsyncWriter) WriteEntry(key, value []byte) error {
+	return w.push(asyncEntry{
+		key:   append([]byte(nil), key...),
+		value: append([]byte(nil), value...),
+	})
+}
+
+func (w *AsyncWriter) push(entry asyncEntry) error {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.closed {
+		return io.ErrClosedPipe
+	}
+
+	if w.policy == QueueBlock {
+		w.queue <- entry
+		return nil
+	}
+
+	select {
+	case w.queue <- entry:
+	default:
+		atomic.AddUint64(&w.dropped, 1)
+	}
+
+	return nil
+}
+
+// Dropped returns the total number of the dropped log lines
+// because the queue was full.
+func (w *AsyncWriter) Dropped() uint64 {
+	return atomic.LoadUint64(&w.dropped)
+}
+
+// Written returns the total number of the log lines
+// written to the underlying writer.
+func (w *AsyncWriter) Written() uint64 {
+	return atomic.LoadUint64(&w.written)
+}
+
+// Len returns the number of the log lines waiting in the queue.
+func (w *AsyncWriter) Len() int {
+	return len(w.queue)
+}
+
+// Flush waits for the queued log lines to be written,
+// flushes the underlying writer (if it's a Flusher)
+// and returns the last write error, if any.
+func (w *AsyncWriter) Flush() error {
+	w.mu.RLock()
+	if w.closed {
+		w.mu.RUnlock()
+		return nil
+	}
+
+	ch := make(chan error, 1)
+	w.queue <- asyncEntry{flush: ch}
+	w.mu.RUnlock()
+
+	return <-ch
+}
+
+// Close drains the queue, flushes and closes the underlying writer.
+func (w *AsyncWriter) Close() error {
+	err := w.Flush()
+
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	close(w.queue)
+	w.mu.Unlock()
+
+	<-w.done
+
+	if closer, ok := w.dest.(io.Closer); ok {
+		if cErr := closer.Close(); err == nil {
+			err = cErr
+		}
+	}
+
+	return err
+}
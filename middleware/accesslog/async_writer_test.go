@@ -0,0 +1,86 @@
+package accesslog
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type blockingWriter struct {
+	mu      sync.Mutex
+	buf     bytes.Buffer
+	unblock chan struct{}
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	<-w.unblock
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
+
+func TestAsyncWriter(t *testing.T) {
+	dest := &blockingWriter{unblock: make(chan struct{})}
+	w := NewAsyncWriter(dest, 2, QueueDrop)
+
+	// The first line is taken by the background goroutine, which blocks,
+	// the next two fill the queue and the rest are dropped.
+	w.Write([]byte("1\n"))
+	for w.Len() > 0 {
+		time.Sleep(time.Millisecond)
+	}
+	for i := 0; i < 5; i++ {
+		w.Write([]byte("n\n"))
+	}
+
+	if expected, got := uint64(3), w.Dropped(); expected != got {
+		t.Fatalf("expected dropped: %d but got: %d", expected, got)
+	}
+
+	close(dest.unblock)
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := "1\nn\nn\n", dest.buf.String(); expected != got {
+		t.Fatalf("expected output: %q but got: %q", expected, got)
+	}
+
+	if expected, got := uint64(3), w.Written(); expected != got {
+		t.Fatalf("expected written: %d but got: %d", expected, got)
+	}
+
+	if _, err := w.Write([]byte("closed\n")); err != io.ErrClosedPipe {
+		t.Fatalf("expected error: %v but got: %v", io.ErrClosedPipe, err)
+	}
+}
+
+func TestAsyncSender(t *testing.T) {
+	var entries []string
+	w := NewAsyncSender(func(key, value []byte) error {
+		entries = append(entries, string(key)+"="+string(value))
+		return nil
+	}, 4, QueueBlock)
+
+	key, value := []byte("host"), []byte("1")
+	if err := w.WriteEntry(key, value); err != nil {
+		t.Fatal(err)
+	}
+	value[0] = '2' // the entry is a copy.
+	w.Write([]byte("3"))
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := "host=1,=3", strings.Join(entries, ","); expected != got {
+		t.Fatalf("expected entries: %q but got: %q", expected, got)
+	}
+
+	if err := w.WriteEntry(key, value); err != io.ErrClosedPipe {
+		t.Fatalf("expected error: %v but got: %v", io.ErrClosedPipe, err)
+	}
+}
@@ -92,6 +92,22 @@ func (b *Broker) CloseListener(ln LogChan) {
 	b.closingListeners <- ln
 }
 
+// closeListener removes the "ln" listener
+// while it keeps receiving (and discarding) its pending logs,
+// so the broker is never blocked by a listener which stopped reading.
+func (b *Broker) closeListener(ln LogChan) {
+	for {
+		select {
+		case b.closingListeners <- ln:
+			return
+		case _, ok := <-ln:
+			if !ok {
+				return
+			}
+		}
+	}
+}
+
 // As we cant export a read-only and pass it as closing client
 // we will return a read-write channel on NewListener and add a note that the user
 // should NOT send data back to the channel, its use is read-only.
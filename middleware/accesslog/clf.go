@@ -0,0 +1,171 @@
+package accesslog
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// CLFTimeFormat is the time format of the Apache Common Log Format.
+const CLFTimeFormat = "02/Jan/2006:15:04:05 -0700"
+
+// CLF is a Formatter type for the Apache Common Log Format
+// and, if Combined is true, for the Combined Log Format.
+// The output can be parsed by log analyzers like GoAccess and AWStats.
+//
+// Common Log Format:
+//  127.0.0.1 - frank [10/Oct/2000:13:55:36 -0700] "GET /apache_pb.gif HTTP/1.0" 200 2326
+// Combined Log Format:
+//  127.0.0.1 - frank [10/Oct/2000:13:55:36 -0700] "GET /apache_pb.gif HTTP/1.0" 200 2326 "http://www.example.com/start.html" "Mozilla/4.08"
+//
+// Usage:
+//  ac.SetFormatter(&accesslog.CLF{Combined: true})
+type CLF struct {
+	// Combined appends the Referer and User-Agent request headers.
+	Combined bool
+	// Fields is a list of custom field keys (see `Set` and `AccessLog.AddFields`)
+	// whose values are appended, quoted, to each line, in the given order.
+	// A missing field is written as "-".
+	Fields []string
+
+	bufPool *sync.Pool
+	ac      *AccessLog
+}
+
+// SetOutput is called automatically by the middleware when this Formatter is used.
+func (f *CLF) SetOutput(dest io.Writer) {
+	f.ac, _ = dest.(*AccessLog)
+	f.bufPool = &sync.Pool{
+		New: func() interface{} {
+			return new(bytes.Buffer)
+		},
+	}
+}
+
+// requestURI returns the path and the, redacted, query of the "u" request URL.
+func (f *CLF) requestURI(u *url.URL) string {
+	requestURI := u.EscapedPath()
+	if u.RawQuery == "" {
+		return requestURI
+	}
+
+	query, err := url.ParseQuery(u.RawQuery)
+	if err != nil {
+		return requestURI
+	}
+
+	if f.ac != nil && f.ac.redactor != nil {
+		for key, values := range query {
+			for i, value := range values {
+				if redacted, ok := f.ac.redactor.Redact(RedactQuery, key, value); ok {
+					values[i] = redacted
+				}
+			}
+		}
+	}
+
+	return requestURI + "?" + query.Encode()
+}
+
+var clfQuoteReplacer = strings.NewReplacer(`"`, `\"`, "\n", `\n`, "\r", `\r`)
+
+// Format writes a log line in the Common or Combined Log Format.
+func (f *CLF) Format(log *Log) (bool, error) {
+	var (
+		user       string
+		requestURI = log.Path
+		proto      = "HTTP/1.1"
+		referer    string
+		userAgent  string
+	)
+
+	if ctx := log.Ctx; ctx != nil {
+		r := ctx.Request()
+		// The request line is rebuilt from the path and the query,
+		// so the query values are redacted, see `AccessLog.SetRedactor`.
+		requestURI = f.requestURI(r.URL)
+		proto = r.Proto
+		referer = log.Header("Referer")
+		userAgent = log.Header("User-Agent")
+
+		if u := ctx.User(); u != nil {
+			user, _ = u.GetUsername()
+		} else {
+			user, _, _ = r.BasicAuth()
+		}
+	} else if len(log.Query) > 0 {
+		query := make(url.Values, len(log.Query))
+		for _, q := range log.Query {
+			query.Add(q.Key, q.Value)
+		}
+		requestURI += "?" + query.Encode()
+	}
+
+	buf := f.bufPool.Get().(*bytes.Buffer)
+
+	writeCLFValue(buf, log.IP)
+	buf.WriteString(" - ") // RFC 1413 identity, not available.
+	writeCLFValue(buf, user)
+	buf.WriteString(" [")
+	buf.WriteString(log.Now.Format(CLFTimeFormat))
+	buf.WriteString(`] "`)
+	buf.WriteString(log.Method)
+	buf.WriteByte(' ')
+	clfQuoteReplacer.WriteString(buf, requestURI)
+	buf.WriteByte(' ')
+	buf.WriteString(proto)
+	buf.WriteString(`" `)
+	buf.WriteString(strconv.Itoa(log.Code))
+	buf.WriteByte(' ')
+	if log.BytesSent > 0 {
+		buf.WriteString(strconv.Itoa(log.BytesSent))
+	} else {
+		buf.WriteByte('-')
+	}
+
+	if f.Combined {
+		if referer == "" {
+			referer = "-"
+		}
+		if userAgent == "" {
+			userAgent = "-"
+		}
+
+		buf.WriteString(` "`)
+		clfQuoteReplacer.WriteString(buf, referer)
+		buf.WriteString(`" "`)
+		clfQuoteReplacer.WriteString(buf, userAgent)
+		buf.WriteByte('"')
+	}
+
+	for _, key := range f.Fields {
+		value := "-"
+		if v := log.Fields.Get(key); v != nil {
+			value = fmt.Sprintf("%v", v)
+		}
+
+		buf.WriteString(` "`)
+		clfQuoteReplacer.WriteString(buf, value)
+		buf.WriteByte('"')
+	}
+
+	buf.WriteByte(newLine)
+
+	_, err := f.ac.Write(buf.Bytes())
+	buf.Reset()
+	f.bufPool.Put(buf)
+	return true, err
+}
+
+func writeCLFValue(buf *bytes.Buffer, s string) {
+	if s == "" {
+		buf.WriteByte('-')
+		return
+	}
+
+	buf.WriteString(strings.ReplaceAll(s, " ", "_"))
+}
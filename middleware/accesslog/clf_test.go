@@ -0,0 +1,60 @@
+package accesslog
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12/core/memstore"
+)
+
+func TestCLF(t *testing.T) {
+	staticNow, _ := time.Parse(defaultTimeFormat, "1993-01-01 05:00:00")
+	lat, _ := time.ParseDuration("1s")
+
+	tests := []struct {
+		formatter *CLF
+		expected  string
+	}{
+		{
+			formatter: &CLF{},
+			expected:  "::1 - - [01/Jan/1993:05:00:00 +0000] \"GET /?sleep=1s HTTP/1.1\" 200 81\n",
+		},
+		{
+			formatter: &CLF{Combined: true},
+			expected:  "::1 - - [01/Jan/1993:05:00:00 +0000] \"GET /?sleep=1s HTTP/1.1\" 200 81 \"-\" \"-\"\n",
+		},
+		{
+			formatter: &CLF{Fields: []string{"user_id", "cache"}},
+			expected:  "::1 - - [01/Jan/1993:05:00:00 +0000] \"GET /?sleep=1s HTTP/1.1\" 200 81 \"42\" \"-\"\n",
+		},
+	}
+
+	for i, tt := range tests {
+		buf := new(bytes.Buffer)
+		ac := New(buf)
+		ac.Clock = TClock(staticNow)
+		ac.SetFormatter(tt.formatter)
+
+		ac.Print(
+			nil,
+			lat,
+			"",
+			200,
+			"GET",
+			"/",
+			"::1",
+			"",
+			"Index",
+			573,
+			81,
+			nil,
+			[]memstore.StringEntry{{Key: "sleep", Value: "1s"}},
+			memstore.Store{{Key: "user_id", ValueRaw: 42}})
+
+		ac.Close()
+		if got := buf.String(); tt.expected != got {
+			t.Fatalf("[%d] expected:\n%s\n\nbut got:\n%s", i, tt.expected, got)
+		}
+	}
+}
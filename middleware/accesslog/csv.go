@@ -2,9 +2,12 @@ package accesslog
 
 import (
 	"encoding/csv"
+	"fmt"
 	"io"
 	"strconv"
 	"sync"
+
+	"github.com/kataras/iris/v12/core/memstore"
 )
 
 // CSV is a Formatter type for csv encoded logs.
@@ -23,8 +26,11 @@ type CSV struct {
 	// 	return new Date(epoch_in_millis);
 	// }
 	DateScript string
-
-	// TODO: Fields []string // field name, position?
+	// Fields is a list of custom field keys (see `Set` and `AccessLog.AddFields`)
+	// to be written as separate columns, after the rest ones,
+	// instead of the "Req Values" column. The header names are the keys themselves.
+	// Missing fields are written as empty values.
+	Fields []string
 }
 
 // SetOutput initializes the csv writer.
@@ -87,6 +93,8 @@ func (f *CSV) SetOutput(dest io.Writer) {
 		keys = append(keys, "Response")
 	}
 
+	keys = append(keys, f.Fields...)
+
 	w.Write(keys)
 	w.Flush()
 }
@@ -114,7 +122,7 @@ func (f *CSV) Format(log *Log) (bool, error) {
 		values = append(values, log.IP)
 	}
 
-	if s := log.RequestValuesLine(); s != "" || f.Header {
+	if s := f.requestValuesLine(log); s != "" || f.Header {
 		// even if it's empty, if Header was set, then add it.
 		values = append(values, s)
 	}
@@ -135,9 +143,45 @@ func (f *CSV) Format(log *Log) (bool, error) {
 		values = append(values, log.Response)
 	}
 
+	for _, key := range f.Fields {
+		var value string
+		if v := log.Fields.Get(key); v != nil {
+			value = fmt.Sprintf("%v", v)
+		}
+
+		values = append(values, value)
+	}
+
 	w := f.writerPool.Get().(*csv.Writer)
 	err := w.Write(values)
 	w.Flush() // it works as "reset" too.
 	f.writerPool.Put(w)
 	return true, err
 }
+
+// requestValuesLine returns the log's request values line
+// without the fields which are written as separate columns.
+func (f *CSV) requestValuesLine(log *Log) string {
+	if len(f.Fields) == 0 || len(log.Fields) == 0 {
+		return log.RequestValuesLine()
+	}
+
+	fields := make(memstore.Store, 0, len(log.Fields))
+	for _, entry := range log.Fields {
+		if !f.isColumn(entry.Key) {
+			fields = append(fields, entry)
+		}
+	}
+
+	return requestValuesLine(log.Code, log.PathParams, log.Query, fields)
+}
+
+func (f *CSV) isColumn(key string) bool {
+	for _, k := range f.Fields {
+		if k == key {
+			return true
+		}
+	}
+
+	return false
+}
@@ -52,3 +52,33 @@ func TestCSV(t *testing.T) {
 		t.Fatalf("expected:\n%s\n\nbut got:\n%s", expected, got)
 	}
 }
+
+func TestCSVFields(t *testing.T) {
+	buf := new(bytes.Buffer)
+	ac := New(buf)
+	ac.RequestBody = false
+	staticNow, _ := time.Parse(defaultTimeFormat, "1993-01-01 05:00:00")
+	ac.Clock = TClock(staticNow)
+	ac.SetFormatter(&CSV{
+		Header: true,
+		Fields: []string{"tenant", "user_id"},
+	})
+
+	fields := memstore.Store{}
+	fields.Set("tenant", "acme")
+	fields.Set("trace_id", "abc")
+	fields.Set("user_id", 42)
+
+	ac.Print(nil, time.Second, "", 200, "GET", "/", "::1", "", "", 0, 0, nil, nil, fields)
+	ac.Print(nil, time.Second, "", 200, "GET", "/", "::1", "", "", 0, 0, nil, nil, nil)
+
+	expected := `Timestamp,Latency,Code,Method,Path,IP,Req Values,In,Out,tenant,user_id
+725864400000,1s,200,GET,/,::1,trace_id=abc,0,0,acme,42
+725864400000,1s,200,GET,/,::1,,0,0,,
+`
+
+	ac.Close()
+	if got := buf.String(); expected != got {
+		t.Fatalf("expected:\n%s\n\nbut got:\n%s", expected, got)
+	}
+}
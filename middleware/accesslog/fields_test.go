@@ -0,0 +1,37 @@
+package accesslog_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/httptest"
+	"github.com/kataras/iris/v12/middleware/accesslog"
+)
+
+func TestSetFields(t *testing.T) {
+	w := new(bytes.Buffer)
+	ac := accesslog.New(w)
+	ac.IP = false
+	ac.BytesReceivedBody = false
+	ac.BytesSentBody = false
+	ac.SetFormatter(&accesslog.CSV{Fields: []string{"user_id", "cache"}})
+
+	app := iris.New()
+	app.UseRouter(ac.Handler)
+	app.Get("/orders/{id}", func(ctx iris.Context) {
+		accesslog.Set(ctx, "user_id", 42)
+		accesslog.SetFields(ctx, map[string]interface{}{"cache": "hit"})
+		accesslog.Set(ctx, "order_id", ctx.Params().Get("id"))
+	})
+
+	e := httptest.New(t, app)
+	e.GET("/orders/7").Expect().Status(httptest.StatusOK)
+	ac.Close()
+
+	line := strings.TrimSpace(w.String())
+	if expected := ",200,GET,/orders/7,id=7 order_id=7,42,hit"; !strings.HasSuffix(line, expected) {
+		t.Fatalf("expected log line to end with: %q but got: %q", expected, line)
+	}
+}
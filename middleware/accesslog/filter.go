@@ -0,0 +1,94 @@
+package accesslog
+
+import (
+	"strings"
+	"sync/atomic"
+)
+
+// Filter reports whether a log should be printed.
+// See `AccessLog.SetFilter` method.
+type Filter func(log *Log) bool
+
+// FilterStatus returns a Filter which accepts
+// only logs with a status code equal or greater than "min",
+// e.g. FilterStatus(400) to skip successful requests.
+func FilterStatus(min int) Filter {
+	return func(log *Log) bool {
+		return log.Code >= min
+	}
+}
+
+// FilterSkipPaths returns a Filter which skips
+// the logs of the given request paths.
+// A path ending with "/*" skips all paths starting with it,
+// e.g. FilterSkipPaths("/health", "/static/*").
+func FilterSkipPaths(paths ...string) Filter {
+	var (
+		exact    = make(map[string]struct{})
+		prefixes []string
+	)
+
+	for _, path := range paths {
+		if strings.HasSuffix(path, "/*") {
+			prefixes = append(prefixes, strings.TrimSuffix(path, "*"))
+			continue
+		}
+
+		exact[path] = struct{}{}
+	}
+
+	return func(log *Log) bool {
+		if _, ok := exact[log.Path]; ok {
+			return false
+		}
+
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(log.Path, prefix) {
+				return false
+			}
+		}
+
+		return true
+	}
+}
+
+// FilterSample returns a Filter which accepts one in "n" logs.
+func FilterSample(n int) Filter {
+	if n <= 1 {
+		return func(*Log) bool { return true }
+	}
+
+	var counter uint64
+	return func(*Log) bool {
+		return (atomic.AddUint64(&counter, 1)-1)%uint64(n) == 0
+	}
+}
+
+// FilterAll returns a Filter which accepts a log
+// only when all of the given "filters" accept it.
+func FilterAll(filters ...Filter) Filter {
+	return func(log *Log) bool {
+		for _, filter := range filters {
+			if !filter(log) {
+				return false
+			}
+		}
+
+		return true
+	}
+}
+
+// FilterAny returns a Filter which accepts a log
+// when any of the given "filters" accepts it.
+// The rest of the filters are not executed.
+func FilterAny(filters ...Filter) Filter {
+	return func(log *Log) bool {
+		for _, filter := range filters {
+			if filter(log) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
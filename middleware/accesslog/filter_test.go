@@ -0,0 +1,44 @@
+package accesslog
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestFilter(t *testing.T) {
+	buf := new(bytes.Buffer)
+	ac := New(buf)
+	ac.Clock = TClock(time.Time{})
+	ac.IP = false
+	ac.RequestBody = false
+	ac.BytesReceivedBody = false
+	ac.BytesSentBody = false
+	ac.SetFilter(FilterAll(
+		FilterSkipPaths("/health", "/static/*"),
+		FilterAny(FilterStatus(400), FilterSample(2)),
+	))
+
+	printLog := func(code int, path string) {
+		ac.Print(nil, 0, ac.TimeFormat, code, "GET", path, "", "", "", 0, 0, nil, nil, nil)
+	}
+
+	printLog(200, "/health")
+	printLog(500, "/static/css/main.css")
+	printLog(200, "/1") // sampled.
+	printLog(200, "/2")
+	printLog(200, "/3") // sampled.
+	printLog(404, "/4")
+	printLog(500, "/5")
+
+	expected := `0001-01-01 00:00:00|0s|200|GET|/1|
+0001-01-01 00:00:00|0s|200|GET|/3|
+0001-01-01 00:00:00|0s|404|GET|/4|
+0001-01-01 00:00:00|0s|500|GET|/5|
+`
+
+	ac.Close()
+	if got := buf.String(); expected != got {
+		t.Fatalf("expected:\n%s\n\nbut got:\n%s", expected, got)
+	}
+}
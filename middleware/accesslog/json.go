@@ -10,12 +10,24 @@ import (
 )
 
 // JSON is a Formatter type for JSON logs.
+// It prints one JSON object per request (JSON Lines),
+// unless Indent is set (pretty mode, useful for development).
 type JSON struct {
 	// Indent in spaces.
 	// Note that, if set to > 0 then jsoniter is used instead of easyjson.
 	Indent     string
 	EscapeHTML bool
 	HumanTime  bool
+	// TimeFormat, if not empty, prints the timestamp field as text
+	// with the given layout, e.g. time.RFC3339,
+	// instead of the unix timestamp in milliseconds.
+	// It overrides the HumanTime field.
+	TimeFormat string
+	// FieldNames can optionally rename the default JSON keys, e.g.
+	//  map[string]string{"timestamp": "@timestamp", "code": "status"}
+	// Available keys: timestamp, latency, code, method, path, ip, query,
+	// params, fields, request, response, bytes_received and bytes_sent.
+	FieldNames map[string]string
 
 	jsoniter jsoniter.API
 	ac       *AccessLog
@@ -48,7 +60,7 @@ var (
 // Writes to the destination directly,
 // locks on each Format call.
 func (f *JSON) Format(log *Log) (bool, error) {
-	if f.jsoniter != nil {
+	if f.jsoniter != nil && f.TimeFormat == "" && len(f.FieldNames) == 0 {
 		if f.HumanTime {
 			// 1. Don't write the unix timestamp,
 			// key will be visible though as we don't omit the field.
@@ -5,6 +5,7 @@
 package accesslog
 
 import (
+	"bytes"
 	"encoding/json"
 
 	"github.com/kataras/iris/v12/core/memstore"
@@ -12,21 +13,26 @@ import (
 )
 
 func (f *JSON) writeEasyJSON(in *Log) error {
+	b, err := f.marshalEasyJSON(in)
+	if err != nil {
+		return err
+	}
+
+	f.ac.Write(b)
+	return nil
+}
+
+func (f *JSON) marshalEasyJSON(in *Log) ([]byte, error) {
 	out := &jwriter.Writer{NoEscapeHTML: !f.EscapeHTML}
 
 	out.RawByte('{')
-	first := true
-	_ = first
 	{
-		const prefix string = ",\"timestamp\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
+		f.writeKey(out, "timestamp", true)
 
-		if f.HumanTime {
+		if f.TimeFormat != "" {
+			t := in.Now.Format(f.TimeFormat)
+			out.String(t)
+		} else if f.HumanTime {
 			t := in.Now.Format(in.TimeFormat)
 			out.String(t)
 		} else {
@@ -34,33 +40,27 @@ func (f *JSON) writeEasyJSON(in *Log) error {
 		}
 	}
 	{
-		const prefix string = ",\"latency\":"
-		out.RawString(prefix)
+		f.writeKey(out, "latency", false)
 		out.Int64(int64(in.Latency))
 	}
 	{
-		const prefix string = ",\"code\":"
-		out.RawString(prefix)
+		f.writeKey(out, "code", false)
 		out.Int(int(in.Code))
 	}
 	{
-		const prefix string = ",\"method\":"
-		out.RawString(prefix)
+		f.writeKey(out, "method", false)
 		out.String(in.Method)
 	}
 	{
-		const prefix string = ",\"path\":"
-		out.RawString(prefix)
+		f.writeKey(out, "path", false)
 		out.String(in.Path)
 	}
 	if in.IP != "" {
-		const prefix string = ",\"ip\":"
-		out.RawString(prefix)
+		f.writeKey(out, "ip", false)
 		out.String(in.IP)
 	}
 	if len(in.Query) != 0 {
-		const prefix string = ",\"query\":"
-		out.RawString(prefix)
+		f.writeKey(out, "query", false)
 		{
 			out.RawByte('[')
 			for v4, v5 := range in.Query {
@@ -73,8 +73,7 @@ func (f *JSON) writeEasyJSON(in *Log) error {
 		}
 	}
 	if len(in.PathParams) != 0 {
-		const prefix string = ",\"params\":"
-		out.RawString(prefix)
+		f.writeKey(out, "params", false)
 		{
 			out.RawByte('[')
 			for v6, v7 := range in.PathParams {
@@ -87,8 +86,7 @@ func (f *JSON) writeEasyJSON(in *Log) error {
 		}
 	}
 	if len(in.Fields) != 0 {
-		const prefix string = ",\"fields\":"
-		out.RawString(prefix)
+		f.writeKey(out, "fields", false)
 		{
 			out.RawByte('[')
 			for v8, v9 := range in.Fields {
@@ -101,35 +99,53 @@ func (f *JSON) writeEasyJSON(in *Log) error {
 		}
 	}
 	if in.Logger.RequestBody {
-		const prefix string = ",\"request\":"
-		out.RawString(prefix)
+		f.writeKey(out, "request", false)
 		out.String(string(in.Request))
 	}
 	if in.Logger.ResponseBody {
 
-		const prefix string = ",\"response\":"
-		out.RawString(prefix)
+		f.writeKey(out, "response", false)
 		out.String(string(in.Response))
 
 	}
 	if in.BytesReceived != 0 {
-		const prefix string = ",\"bytes_received\":"
-		out.RawString(prefix)
+		f.writeKey(out, "bytes_received", false)
 		out.Int(int(in.BytesReceived))
 	}
 	if in.BytesSent != 0 {
-		const prefix string = ",\"bytes_sent\":"
-		out.RawString(prefix)
+		f.writeKey(out, "bytes_sent", false)
 		out.Int(int(in.BytesSent))
 	}
 	out.RawByte('}')
 	out.RawByte(newLine)
 
 	if out.Error != nil {
-		return out.Error
+		return nil, out.Error
 	}
-	f.ac.Write(out.Buffer.BuildBytes())
-	return nil
+	b := out.Buffer.BuildBytes()
+	if f.Indent != "" {
+		buf := new(bytes.Buffer)
+		if err := json.Indent(buf, b, "", f.Indent); err != nil {
+			return nil, err
+		}
+		b = buf.Bytes()
+	}
+
+	return b, nil
+}
+
+// writeKey writes the "key" or its `JSON.FieldNames` replacement.
+func (f *JSON) writeKey(out *jwriter.Writer, key string, first bool) {
+	if !first {
+		out.RawByte(',')
+	}
+
+	if name, ok := f.FieldNames[key]; ok && name != "" {
+		key = name
+	}
+
+	out.String(key)
+	out.RawByte(':')
 }
 
 func easyJSONEntry(out *jwriter.Writer, in memstore.Entry) {
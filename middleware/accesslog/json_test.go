@@ -0,0 +1,105 @@
+package accesslog
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12/core/memstore"
+)
+
+func TestJSON(t *testing.T) {
+	staticNow, _ := time.Parse(defaultTimeFormat, "1993-01-01 05:00:00")
+	lat, _ := time.ParseDuration("1s")
+
+	tests := []struct {
+		formatter *JSON
+		expected  string
+	}{
+		{
+			formatter: &JSON{},
+			expected: `{"timestamp":725864400000,"latency":1000000000,"code":200,"method":"GET","path":"/","ip":"::1","query":[{"key":"sleep","value":"1s"}],"bytes_received":573,"bytes_sent":81}
+{"timestamp":725864400000,"latency":1000000000,"code":200,"method":"GET","path":"/","ip":"::1","query":[{"key":"sleep","value":"1s"}],"bytes_received":573,"bytes_sent":81}
+`,
+		},
+		{
+			formatter: &JSON{
+				TimeFormat: time.RFC3339,
+				FieldNames: map[string]string{"timestamp": "@timestamp", "code": "status"},
+			},
+			expected: `{"@timestamp":"1993-01-01T05:00:00Z","latency":1000000000,"status":200,"method":"GET","path":"/","ip":"::1","query":[{"key":"sleep","value":"1s"}],"bytes_received":573,"bytes_sent":81}
+{"@timestamp":"1993-01-01T05:00:00Z","latency":1000000000,"status":200,"method":"GET","path":"/","ip":"::1","query":[{"key":"sleep","value":"1s"}],"bytes_received":573,"bytes_sent":81}
+`,
+		},
+		{
+			formatter: &JSON{
+				Indent:     "  ",
+				FieldNames: map[string]string{"path": "url"},
+			},
+			expected: `{
+  "timestamp": 725864400000,
+  "latency": 1000000000,
+  "code": 200,
+  "method": "GET",
+  "url": "/",
+  "ip": "::1",
+  "query": [
+    {
+      "key": "sleep",
+      "value": "1s"
+    }
+  ],
+  "bytes_received": 573,
+  "bytes_sent": 81
+}
+{
+  "timestamp": 725864400000,
+  "latency": 1000000000,
+  "code": 200,
+  "method": "GET",
+  "url": "/",
+  "ip": "::1",
+  "query": [
+    {
+      "key": "sleep",
+      "value": "1s"
+    }
+  ],
+  "bytes_received": 573,
+  "bytes_sent": 81
+}
+`,
+		},
+	}
+
+	for i, tt := range tests {
+		buf := new(bytes.Buffer)
+		ac := New(buf)
+		ac.RequestBody = false
+		ac.Clock = TClock(staticNow)
+		ac.SetFormatter(tt.formatter)
+
+		for j := 0; j < 2; j++ {
+			ac.Print(
+				nil,
+				lat,
+				"",
+				200,
+				"GET",
+				"/",
+				"::1",
+				"",
+				"Index",
+				573,
+				81,
+				nil,
+				[]memstore.StringEntry{{Key: "sleep", Value: "1s"}},
+				nil)
+		}
+
+		ac.Close()
+		if got := buf.String(); tt.expected != got {
+			t.Fatalf("[%d] expected:\n%s\n\nbut got:\n%s", i, tt.expected, got)
+		}
+	}
+}
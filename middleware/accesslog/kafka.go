@@ -0,0 +1,129 @@
+package accesslog
+
+import (
+	"io"
+	"os"
+	"sync"
+)
+
+// KafkaProducer is the interface which a Kafka client should implement
+// to be used by a `KafkaSink`. It's a small adapter over
+// the producers of the Kafka client packages,
+// e.g. the segmentio/kafka-go's Writer.WriteMessages or the Shopify/sarama's SyncProducer.SendMessage.
+// Messages of the same "key" should be sent to the same partition.
+type KafkaProducer interface {
+	Produce(topic string, key, value []byte) error
+}
+
+// KafkaSink is a Sink which produces the logs to a Kafka topic.
+// The messages are produced by a background goroutine, through an `AsyncWriter`,
+// so a slow or unreachable broker can't add latency to request handling.
+type KafkaSink struct {
+	// Producer sends the messages, required.
+	Producer KafkaProducer
+	// Topic is the Kafka topic, required.
+	Topic string
+	// PartitionByHost, if true, then the messages are keyed by the server's hostname,
+	// so the logs of an instance are kept in order on the same partition.
+	// Otherwise the messages are keyed by the request's host.
+	//
+	// Defaults to false.
+	PartitionByHost bool
+	// Format encodes the log to the message value.
+	//
+	// Defaults to DefaultSinkFormat (JSON).
+	Format SinkFormat
+	// QueueSize is the size of the queue of the messages.
+	//
+	// Defaults to 1024.
+	QueueSize int
+	// QueuePolicy is the policy of a full queue.
+	//
+	// Defaults to QueueDrop.
+	QueuePolicy QueuePolicy
+
+	hostnameOnce sync.Once
+	hostname     []byte
+
+	queueOnce sync.Once
+	w         *AsyncWriter
+}
+
+var (
+	_ Sink      = (*KafkaSink)(nil)
+	_ Flusher   = (*KafkaSink)(nil)
+	_ io.Closer = (*KafkaSink)(nil)
+)
+
+// NewKafkaSink returns a new KafkaSink which sends the logs
+// through the "producer" to the "topic", partitioned by the server's hostname.
+//
+// Usage:
+//  ac.AddSink(accesslog.NewKafkaSink(producer, "access-logs"))
+func NewKafkaSink(producer KafkaProducer, topic string) *KafkaSink {
+	return &KafkaSink{
+		Producer:        producer,
+		Topic:           topic,
+		PartitionByHost: true,
+	}
+}
+
+// Send completes the Sink interface.
+func (s *KafkaSink) Send(log *Log) error {
+	format := s.Format
+	if format == nil {
+		format = DefaultSinkFormat
+	}
+
+	value, err := format(log)
+	if err != nil {
+		return err
+	}
+
+	var key []byte
+	if s.PartitionByHost {
+		s.hostnameOnce.Do(func() {
+			hostname, _ := os.Hostname()
+			s.hostname = []byte(hostname)
+		})
+		key = s.hostname
+	} else if log.Ctx != nil {
+		key = []byte(log.Ctx.Host())
+	}
+
+	return s.queue().WriteEntry(key, value)
+}
+
+func (s *KafkaSink) queue() *AsyncWriter {
+	s.queueOnce.Do(func() {
+		s.w = NewAsyncSender(s.produce, s.QueueSize, s.QueuePolicy)
+	})
+
+	return s.w
+}
+
+func (s *KafkaSink) produce(key, value []byte) error {
+	if len(key) == 0 {
+		key = nil
+	}
+
+	return s.Producer.Produce(s.Topic, key, value)
+}
+
+// Dropped returns the total number of the dropped messages
+// because the queue was full, see `KafkaSink.QueuePolicy`.
+func (s *KafkaSink) Dropped() uint64 {
+	return s.queue().Dropped()
+}
+
+// Flush waits for the queued messages to be produced
+// and returns the last error, if any.
+func (s *KafkaSink) Flush() error {
+	return s.queue().Flush()
+}
+
+// Close produces the queued messages.
+// The Producer is not closed.
+func (s *KafkaSink) Close() error {
+	return s.queue().Close()
+}
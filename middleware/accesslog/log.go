@@ -60,11 +60,35 @@ func (l *Log) Clone() Log {
 	return *l
 }
 
+// Header returns the value of the request header "key".
+// The value is masked if the AccessLog's Redactor says so,
+// formatters should use that method instead of reading the Ctx's headers.
+func (l *Log) Header(key string) string {
+	if l.Ctx == nil {
+		return ""
+	}
+
+	value := l.Ctx.GetHeader(key)
+	if value == "" || l.Logger == nil || l.Logger.redactor == nil {
+		return value
+	}
+
+	if redacted, ok := l.Logger.redactor.Redact(RedactHeader, key, value); ok {
+		return redacted
+	}
+
+	return value
+}
+
 // RequestValuesLine returns a string line which
 // combines the path parameters, query and custom fields.
 func (l *Log) RequestValuesLine() string {
+	return requestValuesLine(l.Code, l.PathParams, l.Query, l.Fields)
+}
+
+func requestValuesLine(code int, pathParams memstore.Store, query []memstore.StringEntry, fields memstore.Store) string {
 	buf := new(strings.Builder)
-	_, n := parseRequestValues(buf, l.Code, l.PathParams, l.Query, l.Fields)
+	_, n := parseRequestValues(buf, code, pathParams, query, fields)
 	if n == 0 {
 		return ""
 	}
@@ -0,0 +1,293 @@
+package accesslog
+
+import (
+	"bytes"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kataras/iris/v12/context"
+)
+
+// DefaultMetricsBuckets are the default latency histogram buckets, in seconds.
+var DefaultMetricsBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Metrics is a Sink which aggregates per-route latency histograms,
+// status codes and bytes counters in memory,
+// turning the access logger into a lightweight metrics source.
+// The metrics can be exposed through its `Handler` (Prometheus text format)
+// or read by the `Snapshot` method, e.g. to feed a Prometheus registry's collector.
+// The business counters and gauges recorded through the `Context.Metrics` are exported too.
+//
+// Usage:
+//  metrics := accesslog.NewMetrics()
+//  ac.AddSink(metrics)
+//  app.Get("/metrics", metrics.Handler)
+type Metrics struct {
+	// Namespace is the prefix of the exported metric names.
+	// Defaults to "iris".
+	Namespace string
+	// Buckets are the latency histogram buckets, in seconds, sorted in increasing order.
+	Buckets []float64
+	// Registry holds the business counters and gauges exported alongside the HTTP metrics,
+	// see `Context.Metrics`. Defaults to the `context.DefaultMetricsRegistry`.
+	// Set it to nil to not export them.
+	Registry *context.MetricsRegistry
+
+	mu     sync.Mutex
+	since  time.Time
+	routes map[metricsKey]*RouteMetrics
+}
+
+var _ Sink = (*Metrics)(nil)
+
+type metricsKey struct {
+	method string
+	route  string
+}
+
+// RouteMetrics holds the metrics of a route,
+// see `Metrics.Snapshot` method.
+type RouteMetrics struct {
+	// Method is the request method. Non-standard methods of requests
+	// that didn't match a route are collapsed to "OTHER",
+	// so clients cannot create unlimited metrics.
+	Method string `json:"method"`
+	// Route is the registered route path (e.g. "/users/{id}")
+	// or empty for requests that didn't match a route.
+	Route string `json:"route"`
+	// Count is the total number of the requests.
+	Count uint64 `json:"count"`
+	// Sum is the total latency.
+	Sum time.Duration `json:"sum"`
+	// Buckets are the cumulative counters of each histogram bucket
+	// (requests with latency less than or equal to the `Metrics.Buckets`).
+	Buckets []uint64 `json:"buckets"`
+	// Codes are the number of requests per status code.
+	Codes         map[int]uint64 `json:"codes"`
+	BytesReceived uint64         `json:"bytes_received"`
+	BytesSent     uint64         `json:"bytes_sent"`
+}
+
+// NewMetrics returns a new Metrics sink.
+// If "buckets" are missing then the `DefaultMetricsBuckets` are used instead.
+func NewMetrics(buckets ...float64) *Metrics {
+	if len(buckets) == 0 {
+		buckets = DefaultMetricsBuckets
+	}
+
+	return &Metrics{
+		Namespace: "iris",
+		Buckets:   buckets,
+		Registry:  context.DefaultMetricsRegistry,
+		since:     time.Now(),
+		routes:    make(map[metricsKey]*RouteMetrics),
+	}
+}
+
+// Send completes the Sink interface.
+// It records the log's latency, status code and bytes.
+func (m *Metrics) Send(log *Log) error {
+	key := metricsKey{method: log.Method}
+	if log.Ctx != nil {
+		if route := log.Ctx.GetCurrentRoute(); route != nil {
+			key.route = route.Path()
+		}
+	}
+
+	if key.route == "" && !isStandardMethod(key.method) {
+		key.method = "OTHER"
+	}
+
+	seconds := log.Latency.Seconds()
+
+	m.mu.Lock()
+	rm, ok := m.routes[key]
+	if !ok {
+		rm = &RouteMetrics{
+			Method:  key.method,
+			Route:   key.route,
+			Buckets: make([]uint64, len(m.Buckets)),
+			Codes:   make(map[int]uint64),
+		}
+		m.routes[key] = rm
+	}
+
+	rm.Count++
+	rm.Sum += log.Latency
+	for i, le := range m.Buckets {
+		if seconds <= le {
+			rm.Buckets[i]++
+		}
+	}
+	rm.Codes[log.Code]++
+	rm.BytesReceived += uint64(log.BytesReceived)
+	rm.BytesSent += uint64(log.BytesSent)
+	m.mu.Unlock()
+
+	return nil
+}
+
+// isStandardMethod reports whether the "method" is one of the net/http's method constants.
+func isStandardMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodConnect, http.MethodOptions, http.MethodTrace:
+		return true
+	default:
+		return false
+	}
+}
+
+// Snapshot returns a copy of the current metrics, sorted by route and method,
+// and the time the metrics started being collected,
+// useful to calculate the throughput (e.g. Count / time.Since(since).Seconds()).
+func (m *Metrics) Snapshot() ([]RouteMetrics, time.Time) {
+	m.mu.Lock()
+	list := make([]RouteMetrics, 0, len(m.routes))
+	for _, rm := range m.routes {
+		cp := *rm
+		cp.Buckets = append([]uint64(nil), rm.Buckets...)
+		cp.Codes = make(map[int]uint64, len(rm.Codes))
+		for code, n := range rm.Codes {
+			cp.Codes[code] = n
+		}
+
+		list = append(list, cp)
+	}
+	since := m.since
+	m.mu.Unlock()
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Route == list[j].Route {
+			return list[i].Method < list[j].Method
+		}
+
+		return list[i].Route < list[j].Route
+	})
+
+	return list, since
+}
+
+// Reset clears the collected metrics.
+func (m *Metrics) Reset() {
+	m.mu.Lock()
+	m.routes = make(map[metricsKey]*RouteMetrics)
+	m.since = time.Now()
+	m.mu.Unlock()
+}
+
+// Handler writes the metrics in the Prometheus text exposition format.
+// Make sure it's protected if the routes should not be public.
+func (m *Metrics) Handler(ctx *context.Context) {
+	Skip(ctx)
+
+	list, _ := m.Snapshot()
+	ns := m.Namespace
+	if ns == "" {
+		ns = "iris"
+	}
+
+	var (
+		buf      = new(bytes.Buffer)
+		duration = ns + "_http_request_duration_seconds"
+		requests = ns + "_http_requests_total"
+		received = ns + "_http_request_size_bytes_total"
+		sent     = ns + "_http_response_size_bytes_total"
+	)
+
+	writeMetricHeader(buf, duration, "histogram", "The HTTP request latencies in seconds.")
+	for _, rm := range list {
+		labels := metricLabels("method", rm.Method, "route", rm.Route)
+		for i, le := range m.Buckets {
+			writeMetric(buf, duration+"_bucket", labels+`,le="`+formatFloat(le)+`"`, strconv.FormatUint(rm.Buckets[i], 10))
+		}
+		writeMetric(buf, duration+"_bucket", labels+`,le="+Inf"`, strconv.FormatUint(rm.Count, 10))
+		writeMetric(buf, duration+"_sum", labels, formatFloat(rm.Sum.Seconds()))
+		writeMetric(buf, duration+"_count", labels, strconv.FormatUint(rm.Count, 10))
+	}
+
+	writeMetricHeader(buf, requests, "counter", "The total number of HTTP requests by status code.")
+	for _, rm := range list {
+		codes := make([]int, 0, len(rm.Codes))
+		for code := range rm.Codes {
+			codes = append(codes, code)
+		}
+		sort.Ints(codes)
+
+		for _, code := range codes {
+			labels := metricLabels("method", rm.Method, "route", rm.Route, "code", strconv.Itoa(code))
+			writeMetric(buf, requests, labels, strconv.FormatUint(rm.Codes[code], 10))
+		}
+	}
+
+	writeMetricHeader(buf, received, "counter", "The total number of bytes received.")
+	for _, rm := range list {
+		writeMetric(buf, received, metricLabels("method", rm.Method, "route", rm.Route), strconv.FormatUint(rm.BytesReceived, 10))
+	}
+
+	writeMetricHeader(buf, sent, "counter", "The total number of bytes sent.")
+	for _, rm := range list {
+		writeMetric(buf, sent, metricLabels("method", rm.Method, "route", rm.Route), strconv.FormatUint(rm.BytesSent, 10))
+	}
+
+	if m.Registry != nil {
+		writeBusinessMetrics(buf, ns, m.Registry.Snapshot())
+	}
+
+	ctx.ContentType("text/plain; version=0.0.4")
+	ctx.Write(buf.Bytes()) // nolint:errcheck
+}
+
+// writeBusinessMetrics writes the metrics of the `Context.Metrics`,
+// the "list" is sorted by name.
+func writeBusinessMetrics(buf *bytes.Buffer, ns string, list []context.Metric) {
+	prev := ""
+	for _, metric := range list {
+		name := ns + "_" + metricNameReplacer.Replace(metric.Name)
+		if metric.Kind == context.MetricCounter && !strings.HasSuffix(name, "_total") {
+			name += "_total"
+		}
+
+		if name != prev {
+			writeMetricHeader(buf, name, metric.Kind, "The "+metric.Name+" "+metric.Kind+".")
+			prev = name
+		}
+
+		writeMetric(buf, name, metricLabels("route", metric.Route), formatFloat(metric.Value))
+	}
+}
+
+// metricNameReplacer replaces the characters which are not valid in a Prometheus metric name.
+var metricNameReplacer = strings.NewReplacer(".", "_", "-", "_", " ", "_", "/", "_", ":", "_")
+
+func writeMetricHeader(buf *bytes.Buffer, name, typ, help string) {
+	buf.WriteString("# HELP " + name + " " + help + "\n")
+	buf.WriteString("# TYPE " + name + " " + typ + "\n")
+}
+
+func writeMetric(buf *bytes.Buffer, name, labels, value string) {
+	buf.WriteString(name + "{" + labels + "} " + value + "\n")
+}
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func metricLabels(pairs ...string) string {
+	var b strings.Builder
+	for i := 0; i < len(pairs)-1; i += 2 {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+
+		b.WriteString(pairs[i] + `="` + labelValueReplacer.Replace(pairs[i+1]) + `"`)
+	}
+
+	return b.String()
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
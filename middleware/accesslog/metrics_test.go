@@ -0,0 +1,115 @@
+package accesslog_test
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/httptest"
+	"github.com/kataras/iris/v12/middleware/accesslog"
+)
+
+func TestMetrics(t *testing.T) {
+	metrics := accesslog.NewMetrics(0.5, 1)
+
+	ac := accesslog.New(ioutil.Discard)
+	ac.AddSink(metrics)
+	defer ac.Close()
+
+	app := iris.New()
+	app.UseRouter(ac.Handler)
+	app.Get("/metrics", metrics.Handler)
+	app.Get("/users/{id}", func(ctx iris.Context) {
+		ctx.WriteString("user")
+	})
+
+	e := httptest.New(t, app)
+	e.GET("/users/1").Expect().Status(httptest.StatusOK)
+	e.GET("/users/2").Expect().Status(httptest.StatusOK)
+	e.GET("/notfound").Expect().Status(httptest.StatusNotFound)
+
+	list, _ := metrics.Snapshot()
+	if len(list) != 2 {
+		t.Fatalf("expected 2 routes but got: %#v", list)
+	}
+
+	if rm := list[1]; rm.Route != "/users/{id}" || rm.Count != 2 || rm.Codes[200] != 2 || rm.BytesSent == 0 {
+		t.Fatalf("unexpected route metrics: %#v", rm)
+	}
+
+	body := e.GET("/metrics").Expect().Status(httptest.StatusOK).
+		ContentType("text/plain").Body().Raw()
+
+	for _, expected := range []string{
+		`# TYPE iris_http_request_duration_seconds histogram`,
+		`iris_http_request_duration_seconds_bucket{method="GET",route="/users/{id}",le="0.5"} 2`,
+		`iris_http_request_duration_seconds_bucket{method="GET",route="/users/{id}",le="+Inf"} 2`,
+		`iris_http_request_duration_seconds_count{method="GET",route="/users/{id}"} 2`,
+		`iris_http_requests_total{method="GET",route="/users/{id}",code="200"} 2`,
+		`iris_http_requests_total{method="GET",route="",code="404"} 1`,
+	} {
+		if !strings.Contains(body, expected+"\n") {
+			t.Fatalf("expected metrics to contain:\n%s\nbut got:\n%s", expected, body)
+		}
+	}
+
+	// the metrics handler itself is not recorded.
+	if list, _ = metrics.Snapshot(); len(list) != 2 {
+		t.Fatalf("expected 2 routes but got: %d", len(list))
+	}
+
+	// non-standard methods of unmatched requests share a single entry.
+	e.Request("FOO1", "/notfound").Expect().Status(httptest.StatusNotFound)
+	e.Request("FOO2", "/users/1").Expect().Status(httptest.StatusNotFound)
+	if list, _ = metrics.Snapshot(); len(list) != 3 {
+		t.Fatalf("expected 3 routes but got: %#v", list)
+	}
+
+	if rm := list[1]; rm.Method != "OTHER" || rm.Route != "" || rm.Count != 2 {
+		t.Fatalf("unexpected route metrics: %#v", rm)
+	}
+}
+
+func TestBusinessMetrics(t *testing.T) {
+	context.DefaultMetricsRegistry.Reset()
+	metrics := accesslog.NewMetrics()
+
+	app := iris.New()
+	app.Get("/metrics", metrics.Handler)
+	app.Post("/orders", func(ctx iris.Context) {
+		ctx.Metrics().Inc("orders_created")
+		ctx.Metrics().Add("revenue", 9.5)
+		ctx.Metrics().Set("cart.items", 3)
+	})
+	app.Delete("/orders/{id}", func(ctx iris.Context) {
+		ctx.Metrics().Inc("orders_created_total")
+		ctx.Metrics().AddGauge("cart.items", -1)
+	})
+
+	e := httptest.New(t, app)
+	e.POST("/orders").Expect().Status(httptest.StatusOK)
+	e.POST("/orders").Expect().Status(httptest.StatusOK)
+	e.DELETE("/orders/1").Expect().Status(httptest.StatusOK)
+
+	body := e.GET("/metrics").Expect().Status(httptest.StatusOK).Body().Raw()
+	for _, expected := range []string{
+		`# TYPE iris_orders_created_total counter`,
+		`iris_orders_created_total{route="/orders"} 2`,
+		`iris_orders_created_total{route="/orders/{id}"} 1`,
+		`iris_revenue_total{route="/orders"} 19`,
+		`# TYPE iris_cart_items gauge`,
+		`iris_cart_items{route="/orders"} 3`,
+		`iris_cart_items{route="/orders/{id}"} -1`,
+	} {
+		if !strings.Contains(body, expected+"\n") {
+			t.Fatalf("expected metrics to contain:\n%s\nbut got:\n%s", expected, body)
+		}
+	}
+
+	// "orders_created" and "orders_created_total" are the same exported metric.
+	if n := strings.Count(body, "# TYPE iris_orders_created_total"); n != 1 {
+		t.Fatalf("expected a single metric header but got: %d", n)
+	}
+}
@@ -0,0 +1,213 @@
+package accesslog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/kataras/iris/v12/core/memstore"
+)
+
+// RedactSource describes where a value to be redacted comes from.
+type RedactSource uint8
+
+const (
+	// RedactHeader is the source of the request headers,
+	// see `Log.Header` method.
+	RedactHeader RedactSource = iota
+	// RedactQuery is the source of the URL query parameters.
+	RedactQuery
+	// RedactBody is the source of the request and response body fields,
+	// JSON (at any depth) and form-urlencoded bodies are supported.
+	RedactBody
+)
+
+// Redactor masks sensitive values before a log is formatted.
+// See `AccessLog.SetRedactor` method.
+type Redactor interface {
+	// Redact should return the value to be logged instead of the "value" of the "key"
+	// and report whether it should be replaced.
+	Redact(source RedactSource, key, value string) (string, bool)
+}
+
+// RedactorFunc is a function shortcut for a Redactor.
+type RedactorFunc func(source RedactSource, key, value string) (string, bool)
+
+// Redact completes the Redactor interface.
+func (fn RedactorFunc) Redact(source RedactSource, key, value string) (string, bool) {
+	return fn(source, key, value)
+}
+
+// DefaultRedactMask is the default `Redaction.Mask`.
+const DefaultRedactMask = "[REDACTED]"
+
+// Redaction is the builtin Redactor.
+// It replaces the values of the given (case-insensitive) keys with the Mask.
+type Redaction struct {
+	// Headers the request header keys to be redacted,
+	// e.g. "Authorization".
+	Headers []string
+	// Query the URL query parameter keys to be redacted.
+	Query []string
+	// Body the request and response body field keys to be redacted,
+	// e.g. "password".
+	Body []string
+	// Mask replaces the redacted values.
+	//
+	// Defaults to "[REDACTED]".
+	Mask string
+}
+
+var _ Redactor = (*Redaction)(nil)
+
+// DefaultRedaction returns a new Redaction which masks the common credentials,
+// e.g. the Authorization and Cookie headers
+// and the password, token and secret fields.
+//
+// Usage:
+//  r := accesslog.DefaultRedaction()
+//  r.Body = append(r.Body, "credit_card")
+//  ac.SetRedactor(r)
+func DefaultRedaction() *Redaction {
+	keys := []string{"password", "token", "access_token", "refresh_token", "secret", "api_key"}
+
+	return &Redaction{
+		Headers: []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Api-Key"},
+		Query:   keys,
+		Body:    keys,
+		Mask:    DefaultRedactMask,
+	}
+}
+
+// Redact completes the Redactor interface.
+func (r *Redaction) Redact(source RedactSource, key, _ string) (string, bool) {
+	var keys []string
+	switch source {
+	case RedactHeader:
+		keys = r.Headers
+	case RedactQuery:
+		keys = r.Query
+	case RedactBody:
+		keys = r.Body
+	}
+
+	for _, k := range keys {
+		if strings.EqualFold(k, key) {
+			if r.Mask == "" {
+				return DefaultRedactMask, true
+			}
+
+			return r.Mask, true
+		}
+	}
+
+	return "", false
+}
+
+// redactQuery returns a copy of the "query" with its values redacted,
+// or the "query" itself if nothing was redacted.
+func redactQuery(r Redactor, query []memstore.StringEntry) []memstore.StringEntry {
+	var redacted []memstore.StringEntry
+
+	for i, entry := range query {
+		value, ok := r.Redact(RedactQuery, entry.Key, entry.Value)
+		if !ok {
+			continue
+		}
+
+		if redacted == nil {
+			redacted = make([]memstore.StringEntry, len(query))
+			copy(redacted, query)
+		}
+
+		redacted[i].Value = value
+	}
+
+	if redacted == nil {
+		return query
+	}
+
+	return redacted
+}
+
+// redactBody returns the "body" with its fields redacted, based on the "contentType".
+// Bodies that cannot be parsed are returned as they are.
+func redactBody(r Redactor, contentType, body string) string {
+	if body == "" {
+		return body
+	}
+
+	if strings.Contains(contentType, "json") || (contentType == "" && (body[0] == '{' || body[0] == '[')) {
+		dec := json.NewDecoder(strings.NewReader(body))
+		dec.UseNumber()
+
+		var v interface{}
+		if err := dec.Decode(&v); err != nil || !redactJSON(r, v) {
+			return body
+		}
+
+		buf := new(bytes.Buffer)
+		enc := json.NewEncoder(buf)
+		enc.SetEscapeHTML(false)
+		if err := enc.Encode(v); err != nil {
+			return body
+		}
+
+		return strings.TrimSuffix(buf.String(), "\n")
+	}
+
+	if strings.HasPrefix(contentType, "application/x-www-form-urlencoded") {
+		values, err := url.ParseQuery(body)
+		if err != nil {
+			return body
+		}
+
+		modified := false
+		for key, vals := range values {
+			for i, val := range vals {
+				if redacted, ok := r.Redact(RedactBody, key, val); ok {
+					vals[i] = redacted
+					modified = true
+				}
+			}
+		}
+
+		if modified {
+			return values.Encode()
+		}
+	}
+
+	return body
+}
+
+func redactJSON(r Redactor, v interface{}) (modified bool) {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for key, field := range value {
+			s, isString := field.(string)
+			if !isString {
+				s = fmt.Sprint(field)
+			}
+
+			if redacted, ok := r.Redact(RedactBody, key, s); ok {
+				value[key] = redacted
+				modified = true
+				continue
+			}
+
+			if redactJSON(r, field) {
+				modified = true
+			}
+		}
+	case []interface{}:
+		for _, elem := range value {
+			if redactJSON(r, elem) {
+				modified = true
+			}
+		}
+	}
+
+	return
+}
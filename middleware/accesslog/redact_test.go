@@ -0,0 +1,119 @@
+package accesslog
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/core/memstore"
+)
+
+func TestRedaction(t *testing.T) {
+	buf := new(bytes.Buffer)
+	ac := New(buf)
+	ac.Clock = TClock(time.Time{})
+	ac.ResponseBody = true
+	ac.SetRedactor(DefaultRedaction())
+
+	ctx := new(context.Context)
+	req := httptest.NewRequest(http.MethodPost, "/login", nil)
+	req.Header.Set("Authorization", "Bearer secret-token")
+	req.Header.Set("User-Agent", "test")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	ctx.ResetRequest(req)
+	w := context.AcquireResponseWriter()
+	w.BeginResponse(httptest.NewRecorder())
+	w.Header().Set("Content-Type", "application/json")
+	ctx.ResetResponseWriter(w)
+
+	err := ac.Print(ctx,
+		time.Second,
+		defaultTimeFormat,
+		200,
+		"POST",
+		"/login",
+		"::1",
+		"username=kataras&password=123",
+		`{"user":{"name":"kataras","Password":"123","tokens":[{"token":"abc"}]},"age":27}`,
+		0,
+		0,
+		nil,
+		[]memstore.StringEntry{{Key: "lang", Value: "en"}, {Key: "api_key", Value: "xyz"}},
+		nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ac.Close()
+
+	got := buf.String()
+	for _, secret := range []string{"123", "abc", "xyz"} {
+		if strings.Contains(got, secret) {
+			t.Fatalf("expected %q to be redacted but got:\n%s", secret, got)
+		}
+	}
+
+	expected := []string{
+		"lang=en api_key=[REDACTED]",
+		"password=%5BREDACTED%5D&username=kataras",
+		`{"age":27,"user":{"Password":"[REDACTED]","name":"kataras","tokens":[{"token":"[REDACTED]"}]}}`,
+	}
+	for _, s := range expected {
+		if !strings.Contains(got, s) {
+			t.Fatalf("expected log line to contain %q but got:\n%s", s, got)
+		}
+	}
+
+	log := &Log{Logger: ac, Ctx: ctx}
+	if expected, got := DefaultRedactMask, log.Header("Authorization"); expected != got {
+		t.Fatalf("expected Authorization header: %q but got: %q", expected, got)
+	}
+	if expected, got := "test", log.Header("User-Agent"); expected != got {
+		t.Fatalf("expected User-Agent header: %q but got: %q", expected, got)
+	}
+}
+
+func TestRedactionCLF(t *testing.T) {
+	buf := new(bytes.Buffer)
+	ac := New(buf)
+	ac.Clock = TClock(time.Time{})
+	ac.SetFormatter(&CLF{})
+	ac.SetRedactor(DefaultRedaction())
+
+	ctx := new(context.Context)
+	ctx.ResetRequest(httptest.NewRequest(http.MethodGet, "/login?token=secret&lang=en", nil))
+	w := context.AcquireResponseWriter()
+	w.BeginResponse(httptest.NewRecorder())
+	ctx.ResetResponseWriter(w)
+
+	err := ac.Print(ctx,
+		time.Second,
+		"",
+		200,
+		"GET",
+		"/login",
+		"::1",
+		"",
+		"",
+		0,
+		0,
+		nil,
+		[]memstore.StringEntry{{Key: "token", Value: "secret"}, {Key: "lang", Value: "en"}},
+		nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ac.Close()
+
+	got := buf.String()
+	if strings.Contains(got, "secret") {
+		t.Fatalf("expected the query token to be redacted but got:\n%s", got)
+	}
+
+	if expected := `"GET /login?lang=en&token=%5BREDACTED%5D HTTP/1.1"`; !strings.Contains(got, expected) {
+		t.Fatalf("expected log line to contain %q but got:\n%s", expected, got)
+	}
+}
@@ -0,0 +1,178 @@
+package accesslog
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kataras/iris/v12/context"
+)
+
+// ringBuffer holds the last N logs in memory.
+type ringBuffer struct {
+	mu   sync.RWMutex
+	logs []Log
+	next int
+	full bool
+}
+
+func newRingBuffer(size int) *ringBuffer {
+	return &ringBuffer{logs: make([]Log, size)}
+}
+
+func (r *ringBuffer) add(log *Log) {
+	entry := log.Clone()
+	// The log is stored after the request, so
+	// detach it from the request's (pooled) context and values.
+	entry.Ctx = nil
+	entry.Query = append(entry.Query[:0:0], entry.Query...)
+	entry.PathParams = append(entry.PathParams[:0:0], entry.PathParams...)
+	entry.Fields = append(entry.Fields[:0:0], entry.Fields...)
+
+	r.mu.Lock()
+	r.logs[r.next] = entry
+	r.next = (r.next + 1) % len(r.logs)
+	if r.next == 0 {
+		r.full = true
+	}
+	r.mu.Unlock()
+}
+
+// list returns the logs accepted by the "filter", the oldest first.
+func (r *ringBuffer) list(filter Filter) []Log {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	start, n := 0, r.next
+	if r.full {
+		start, n = r.next, len(r.logs)
+	}
+
+	logs := make([]Log, 0, n)
+	for i := 0; i < n; i++ {
+		log := r.logs[(start+i)%len(r.logs)]
+		if filter != nil && !filter(&log) {
+			continue
+		}
+
+		logs = append(logs, log)
+	}
+
+	return logs
+}
+
+// SetRingBuffer keeps the last "size" logs in memory,
+// they can be inspected through the `Recent` method and the `RecentHandler`,
+// even while the logger is paused (see `Pause`).
+// Zero or negative "size" disables the ring buffer.
+//
+// Should be called before serve-time.
+func (ac *AccessLog) SetRingBuffer(size int) *AccessLog {
+	ac.mu.Lock()
+	if size > 0 {
+		ac.ring = newRingBuffer(size)
+	} else {
+		ac.ring = nil
+	}
+	ac.mu.Unlock()
+
+	return ac
+}
+
+// Recent returns the logs of the ring buffer accepted by the "filter" (can be nil),
+// the oldest first. The logs are not attached to a request, their Ctx field is nil.
+// See `SetRingBuffer`.
+func (ac *AccessLog) Recent(filter Filter) []Log {
+	ac.mu.RLock()
+	ring := ac.ring
+	ac.mu.RUnlock()
+
+	if ring == nil {
+		return nil
+	}
+
+	return ring.list(filter)
+}
+
+// Pause stops writing logs to the output destinations and the sinks,
+// e.g. to pause disk logging during a traffic spike,
+// the ring buffer (see `SetRingBuffer`) and the tail clients
+// (see `TailHandler`) keep receiving them. See `Resume` too.
+func (ac *AccessLog) Pause() {
+	atomic.StoreUint32(&ac.paused, 1)
+}
+
+// Resume continues writing logs to the output destinations and the sinks
+// after a `Pause` call.
+func (ac *AccessLog) Resume() {
+	atomic.StoreUint32(&ac.paused, 0)
+}
+
+// IsPaused reports whether the logger is paused, see `Pause`.
+func (ac *AccessLog) IsPaused() bool {
+	return atomic.LoadUint32(&ac.paused) > 0
+}
+
+// RecentHandler writes the logs of the ring buffer (see `SetRingBuffer`)
+// as a JSON array, the newest first, for quick production triage.
+// Requests of the handler itself are not logged.
+//
+// The logs can be filtered through the URL query parameters:
+//  code:    the minimum status code, e.g. ?code=500
+//  method:  the HTTP method, e.g. ?method=POST
+//  path:    the request path prefix, e.g. ?path=/api
+//  ip:      the remote address, e.g. ?ip=::1
+//  latency: the minimum latency, e.g. ?latency=500ms
+//  limit:   the maximum number of logs, e.g. ?limit=20
+//
+// Usage:
+//  ac.SetRingBuffer(1000)
+//  admin := app.Party("/admin", basicAuth)
+//  admin.Get("/accesslog", ac.RecentHandler)
+//  admin.Post("/accesslog/pause", ac.PauseHandler)
+//  admin.Post("/accesslog/resume", ac.ResumeHandler)
+//
+// Make sure it's protected, logs may contain sensitive data.
+func (ac *AccessLog) RecentHandler(ctx *context.Context) {
+	Skip(ctx)
+
+	filter := tailFilter(ctx)
+	if latency, err := time.ParseDuration(ctx.URLParam("latency")); err == nil {
+		minLatency := func(log *Log) bool {
+			return log.Latency >= latency
+		}
+
+		if filter != nil {
+			filter = FilterAll(filter, minLatency)
+		} else {
+			filter = minLatency
+		}
+	}
+
+	logs := ac.Recent(filter)
+	for i, j := 0, len(logs)-1; i < j; i, j = i+1, j-1 {
+		logs[i], logs[j] = logs[j], logs[i]
+	}
+
+	if limit := ctx.URLParamIntDefault("limit", 0); limit > 0 && limit < len(logs) {
+		logs = logs[:limit]
+	}
+
+	ctx.JSON(logs)
+}
+
+// PauseHandler pauses the logger, see `Pause`.
+// It responds with 204 No Content.
+func (ac *AccessLog) PauseHandler(ctx *context.Context) {
+	Skip(ctx)
+	ac.Pause()
+	ctx.StatusCode(204)
+}
+
+// ResumeHandler resumes the logger, see `Resume`.
+// It responds with 204 No Content.
+func (ac *AccessLog) ResumeHandler(ctx *context.Context) {
+	Skip(ctx)
+	ac.Resume()
+	ctx.StatusCode(204)
+}
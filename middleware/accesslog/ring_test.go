@@ -0,0 +1,64 @@
+package accesslog_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/httptest"
+	"github.com/kataras/iris/v12/middleware/accesslog"
+)
+
+func TestRingBuffer(t *testing.T) {
+	w := new(bytes.Buffer)
+	ac := accesslog.New(w).SetRingBuffer(3)
+	defer ac.Close()
+
+	app := iris.New()
+	app.UseRouter(ac.Handler)
+	app.Get("/admin/accesslog", ac.RecentHandler)
+	app.Post("/admin/accesslog/pause", ac.PauseHandler)
+	app.Post("/admin/accesslog/resume", ac.ResumeHandler)
+	app.Get("/{id:int}", func(ctx iris.Context) {
+		ctx.Writef("%d", ctx.Params().GetIntDefault("id", 0))
+	})
+
+	e := httptest.New(t, app)
+	e.GET("/1").Expect().Status(httptest.StatusOK)
+	e.POST("/admin/accesslog/pause").Expect().Status(httptest.StatusNoContent)
+	if !ac.IsPaused() {
+		t.Fatalf("expected to be paused")
+	}
+
+	written := w.Len()
+	for i := 2; i <= 4; i++ {
+		e.GET(fmt.Sprintf("/%d", i)).Expect().Status(httptest.StatusOK)
+	}
+	e.GET("/notfound").Expect().Status(httptest.StatusNotFound)
+
+	if w.Len() != written {
+		t.Fatalf("expected no logs to be written while paused but got: %q", w.String()[written:])
+	}
+
+	// Only the last 3 are kept.
+	logs := ac.Recent(nil)
+	if expected, got := 3, len(logs); expected != got {
+		t.Fatalf("expected %d logs but got %d", expected, got)
+	}
+	if expected, got := "/3", logs[0].Path; expected != got {
+		t.Fatalf("expected oldest log path: %s but got: %s", expected, got)
+	}
+
+	e.GET("/admin/accesslog").WithQuery("code", 404).Expect().Status(httptest.StatusOK).
+		JSON().Array().Length().Equal(1)
+	recent := e.GET("/admin/accesslog").WithQuery("limit", 2).Expect().Status(httptest.StatusOK).JSON().Array()
+	recent.Length().Equal(2)
+	recent.Element(0).Object().Value("path").Equal("/notfound")
+
+	e.POST("/admin/accesslog/resume").Expect().Status(httptest.StatusNoContent)
+	e.GET("/5").Expect().Status(httptest.StatusOK)
+	if w.Len() == written {
+		t.Fatalf("expected logs to be written after resume")
+	}
+}
@@ -0,0 +1,389 @@
+package accesslog
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Rotation holds the rotation settings of a `File` access log.
+type Rotation struct {
+	// MaxSize is the maximum size of the log file in megabytes
+	// before it gets rotated.
+	//
+	// Defaults to zero, no size limit.
+	MaxSize int
+	// Daily rotates the log file on the first write of a new day.
+	//
+	// Defaults to false.
+	Daily bool
+	// MaxBackups is the maximum number of the old log files to keep.
+	//
+	// Defaults to zero, all old log files are kept.
+	MaxBackups int
+	// Compress the rotated log files with gzip.
+	//
+	// Defaults to false.
+	Compress bool
+}
+
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// RotatingFile is a buffered file writer which rotates its file
+// based on the `Rotation` settings. The old files are renamed
+// to "name-$timestamp.ext" (and compressed to "name-$timestamp.ext.gz"),
+// a "-$n" suffix is added to the timestamp of backups rotated at the same millisecond.
+//
+// The file is reopened on SIGHUP signals,
+// so it can be used with external tools like logrotate too.
+//
+// Look the `File` package-level function.
+type RotatingFile struct {
+	path string
+	opts Rotation
+
+	mu   sync.Mutex
+	file *os.File
+	w    *bufio.Writer
+	size int64
+	day  string
+
+	sighup     chan os.Signal
+	sighupOnce sync.Once      // stops the SIGHUP listener once.
+	wg         sync.WaitGroup // waits for the compression goroutines.
+	bgMu       sync.Mutex     // runs one compression and clean up at a time.
+}
+
+var (
+	_ io.ReadWriteCloser = (*RotatingFile)(nil)
+	_ Flusher            = (*RotatingFile)(nil)
+	_ FileTruncater      = (*RotatingFile)(nil)
+)
+
+// NewRotatingFile opens or creates the "path" file
+// and returns a new RotatingFile writer.
+func NewRotatingFile(path string, opts Rotation) (*RotatingFile, error) {
+	f := &RotatingFile{
+		path:   path,
+		opts:   opts,
+		sighup: make(chan os.Signal, 1),
+	}
+
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+
+	signal.Notify(f.sighup, syscall.SIGHUP)
+	go func(sighup <-chan os.Signal) {
+		for range sighup { // ends on Close.
+			f.Reopen()
+		}
+	}(f.sighup)
+
+	return f, nil
+}
+
+func (f *RotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	f.file = file
+	f.size = info.Size()
+	f.day = info.ModTime().Format("2006-01-02")
+	if f.w == nil {
+		f.w = bufio.NewWriter(file)
+	} else {
+		f.w.Reset(file)
+	}
+
+	return nil
+}
+
+func (f *RotatingFile) close() error {
+	if f.file == nil {
+		return nil
+	}
+
+	err := f.w.Flush()
+	if cErr := f.file.Close(); err == nil {
+		err = cErr
+	}
+	f.file = nil
+
+	return err
+}
+
+// Write writes "p" to the log file.
+// The file is rotated before the write if it's required.
+// Note that "p" is never splitted between two files.
+// If the rotation fails then "p" is written to the current file
+// and the rotation error is returned.
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var rotateErr error
+	if f.shouldRotate(len(p)) {
+		rotateErr = f.rotate()
+	}
+
+	if f.file == nil {
+		if rotateErr != nil {
+			return 0, rotateErr
+		}
+
+		return 0, os.ErrClosed
+	}
+
+	n, err := f.w.Write(p)
+	f.size += int64(n)
+	if err == nil {
+		err = rotateErr
+	}
+
+	return n, err
+}
+
+func (f *RotatingFile) shouldRotate(n int) bool {
+	if f.size == 0 {
+		return false
+	}
+
+	if f.opts.MaxSize > 0 && f.size+int64(n) > int64(f.opts.MaxSize)<<20 {
+		return true
+	}
+
+	return f.opts.Daily && time.Now().Format("2006-01-02") != f.day
+}
+
+// Rotate rotates the log file manually.
+func (f *RotatingFile) Rotate() error {
+	f.mu.Lock()
+	err := f.rotate()
+	f.mu.Unlock()
+	return err
+}
+
+func (f *RotatingFile) rotate() error {
+	if err := f.close(); err != nil {
+		// Keep writing to the current file.
+		if oErr := f.open(); oErr != nil {
+			return fmt.Errorf("%v, %v", err, oErr)
+		}
+
+		return err
+	}
+
+	backup := f.backupName()
+	if err := os.Rename(f.path, backup); err != nil {
+		// Keep writing to the current file.
+		if oErr := f.open(); oErr != nil {
+			return fmt.Errorf("%v, %v", err, oErr)
+		}
+
+		return err
+	}
+
+	if err := f.open(); err != nil {
+		return err
+	}
+
+	f.wg.Add(1)
+	go func() {
+		defer f.wg.Done()
+		f.bgMu.Lock()
+		defer f.bgMu.Unlock()
+
+		if f.opts.Compress {
+			if err := compressFile(backup); err != nil {
+				return
+			}
+		}
+
+		f.removeOldBackups()
+	}()
+
+	return nil
+}
+
+// backupName returns the name of a new backup of the log file,
+// with a "-$n" suffix if a backup of the same timestamp already exists,
+// so a backup is never replaced.
+func (f *RotatingFile) backupName() string {
+	ext := filepath.Ext(f.path)
+	name := strings.TrimSuffix(f.path, ext) + "-" + time.Now().Format(backupTimeFormat)
+
+	backup := name + ext
+	for n := 1; fileExists(backup) || fileExists(backup+".gz"); n++ {
+		backup = name + "-" + strconv.Itoa(n) + ext
+	}
+
+	return backup
+}
+
+func fileExists(path string) bool {
+	_, err := os.Lstat(path)
+	return !os.IsNotExist(err)
+}
+
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+".gz", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+
+	gw := gzip.NewWriter(dst)
+	if _, err = io.Copy(gw, src); err == nil {
+		err = gw.Close()
+	}
+
+	if cErr := dst.Close(); err == nil {
+		err = cErr
+	}
+
+	if err != nil {
+		os.Remove(path + ".gz")
+		return err
+	}
+
+	src.Close()
+	return os.Remove(path)
+}
+
+func (f *RotatingFile) removeOldBackups() {
+	if f.opts.MaxBackups <= 0 {
+		return
+	}
+
+	ext := filepath.Ext(f.path)
+	prefix := strings.TrimSuffix(f.path, ext) + "-"
+	backups, err := filepath.Glob(prefix + "*" + ext + "*")
+	if err != nil || len(backups) <= f.opts.MaxBackups {
+		return
+	}
+
+	// The timestamp format and the "-$n" suffix keep them in chronological order.
+	sort.Slice(backups, func(i, j int) bool {
+		tsI, nI := backupOrder(backups[i], prefix, ext)
+		tsJ, nJ := backupOrder(backups[j], prefix, ext)
+		if tsI == tsJ {
+			return nI < nJ
+		}
+
+		return tsI < tsJ
+	})
+
+	for _, backup := range backups[:len(backups)-f.opts.MaxBackups] {
+		os.Remove(backup)
+	}
+}
+
+// backupOrder returns the timestamp and the "-$n" suffix of a backup name.
+func backupOrder(backup, prefix, ext string) (string, int) {
+	ts := strings.TrimSuffix(strings.TrimSuffix(strings.TrimPrefix(backup, prefix), ".gz"), ext)
+	if len(ts) <= len(backupTimeFormat) {
+		return ts, 0
+	}
+
+	n, _ := strconv.Atoi(strings.TrimPrefix(ts[len(backupTimeFormat):], "-"))
+	return ts[:len(backupTimeFormat)], n
+}
+
+// Reopen flushes and closes the current log file
+// and opens the file of the same path.
+// It's called automatically on SIGHUP signals.
+func (f *RotatingFile) Reopen() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.close(); err != nil {
+		return err
+	}
+
+	return f.open()
+}
+
+// Read reads from the current log file.
+func (f *RotatingFile) Read(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	return f.file.Read(p)
+}
+
+// Flush writes any buffered data to the log file.
+func (f *RotatingFile) Flush() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return nil
+	}
+
+	return f.w.Flush()
+}
+
+// Truncate flushes any buffered data and changes the size of the log file.
+func (f *RotatingFile) Truncate(size int64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return os.ErrClosed
+	}
+
+	if err := f.w.Flush(); err != nil {
+		return err
+	}
+
+	if err := f.file.Truncate(size); err != nil {
+		return err
+	}
+
+	f.size = size
+	return nil
+}
+
+// Close stops listening for SIGHUP signals,
+// waits for any in-progress compression and closes the log file.
+func (f *RotatingFile) Close() error {
+	f.sighupOnce.Do(func() {
+		signal.Stop(f.sighup)
+		close(f.sighup)
+	})
+
+	f.mu.Lock()
+	err := f.close()
+	f.mu.Unlock()
+
+	f.wg.Wait()
+	return err
+}
@@ -0,0 +1,101 @@
+package accesslog
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRotatingFile(t *testing.T) {
+	dir, err := os.MkdirTemp("", "accesslog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "access.log")
+	f, err := NewRotatingFile(path, Rotation{MaxSize: 1, MaxBackups: 2, Compress: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	line := []byte(strings.Repeat("a", 1<<19-1) + "\n") // 512 KB.
+	for i := 0; i < 8; i++ {
+		if _, err = f.Write(line); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(2 * time.Millisecond) // unique backup names.
+	}
+
+	if err = f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := int64(2*len(line)), info.Size(); expected != got {
+		t.Fatalf("expected current file size: %d but got: %d", expected, got)
+	}
+
+	backups, _ := filepath.Glob(filepath.Join(dir, "access-*.log.gz"))
+	if expected, got := 2, len(backups); expected != got {
+		t.Fatalf("expected %d compressed backups but got: %d", expected, got)
+	}
+}
+
+func TestRotatingFileRotateFailure(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "access.log")
+	f, err := NewRotatingFile(path, Rotation{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	// Rotations of the same millisecond do not replace each other.
+	for i := 0; i < 5; i++ {
+		if _, err = f.Write([]byte("line\n")); err != nil {
+			t.Fatal(err)
+		}
+
+		if err = f.Rotate(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	backups, _ := filepath.Glob(filepath.Join(dir, "access-*.log"))
+	if expected, got := 5, len(backups); expected != got {
+		t.Fatalf("expected %d backups but got: %d", expected, got)
+	}
+
+	// The rename fails, the file is reopened.
+	if err = os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = f.Rotate(); err == nil {
+		t.Fatal("expected a rotation error")
+	}
+
+	if _, err = f.Write([]byte("after\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = f.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := "after\n", string(b); expected != got {
+		t.Fatalf("expected file contents: %q but got: %q", expected, got)
+	}
+}
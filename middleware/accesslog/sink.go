@@ -0,0 +1,51 @@
+package accesslog
+
+import (
+	"bytes"
+	"io"
+)
+
+// Sink receives the access logs, e.g. to send them
+// to a central infrastructure without an intermediate file tailer.
+// See `AccessLog.AddSink` method and
+// the builtin `SyslogSink`, `KafkaSink` and `Metrics` implementations.
+type Sink interface {
+	// Send should deliver the "log".
+	// The log is reused after the call, it should not be retained.
+	Send(log *Log) error
+}
+
+// SinkFormat encodes a log for a Sink.
+type SinkFormat func(log *Log) ([]byte, error)
+
+// DefaultSinkFormat encodes the log as a JSON object.
+func DefaultSinkFormat(log *Log) ([]byte, error) {
+	b, err := new(JSON).marshalEasyJSON(log)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSuffix(b, []byte{newLine}), nil
+}
+
+// AddSink registers one or more sinks which receive the logs
+// after the Formatter and Broker ones.
+// If a sink is an io.Closer then it's closed on `Close`.
+// Call it before `Handler` method.
+// Returns this AccessLog instance.
+//
+// Usage:
+//  sink, err := accesslog.NewSyslogSink("udp", "localhost:514", accesslog.SyslogOptions{})
+//  ac.AddSink(sink)
+func (ac *AccessLog) AddSink(sinks ...Sink) *AccessLog {
+	ac.mu.Lock()
+	for _, sink := range sinks {
+		ac.sinks = append(ac.sinks, sink)
+		if closer, ok := sink.(io.Closer); ok {
+			ac.Closers = append(ac.Closers, closer)
+		}
+	}
+	ac.mu.Unlock()
+
+	return ac
+}
@@ -0,0 +1,218 @@
+package accesslog
+
+import (
+	"bufio"
+	"io/ioutil"
+	"net"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testKafkaProducer struct {
+	topic      string
+	key, value []byte
+}
+
+func (p *testKafkaProducer) Produce(topic string, key, value []byte) error {
+	p.topic = topic
+	p.key = key
+	p.value = value
+	return nil
+}
+
+func printSinkLog(ac *AccessLog, code int) error {
+	return ac.Print(nil, time.Second, "", code, "GET", "/admin", "::1", "", "", 0, 0, nil, nil, nil)
+}
+
+func TestKafkaSink(t *testing.T) {
+	staticNow, _ := time.Parse(defaultTimeFormat, "1993-01-01 05:00:00")
+	producer := new(testKafkaProducer)
+
+	ac := New(ioutil.Discard)
+	ac.Clock = TClock(staticNow)
+	ac.AddSink(NewKafkaSink(producer, "access"))
+
+	if err := printSinkLog(ac, 200); err != nil {
+		t.Fatal(err)
+	}
+	ac.Close()
+
+	hostname, _ := os.Hostname()
+	if producer.topic != "access" || string(producer.key) != hostname {
+		t.Fatalf("expected topic: access and key: %s but got: %s and %s", hostname, producer.topic, producer.key)
+	}
+
+	expected := `{"timestamp":725864400000,"latency":1000000000,"code":200,"method":"GET","path":"/admin","ip":"::1","request":""}`
+	if got := string(producer.value); expected != got {
+		t.Fatalf("expected value:\n%s\nbut got:\n%s", expected, got)
+	}
+}
+
+type blockingKafkaProducer struct {
+	release  chan struct{}
+	produced chan []byte
+}
+
+func (p *blockingKafkaProducer) Produce(topic string, key, value []byte) error {
+	<-p.release
+	p.produced <- value
+	return nil
+}
+
+func TestKafkaSinkQueue(t *testing.T) {
+	producer := &blockingKafkaProducer{release: make(chan struct{}), produced: make(chan []byte, 3)}
+	sink := NewKafkaSink(producer, "access")
+	sink.QueueSize = 1
+
+	ac := New(ioutil.Discard)
+	ac.AddSink(sink)
+
+	// The requests do not wait for the producer.
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 3; i++ {
+			printSinkLog(ac, 200)
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the sink not to block on a slow producer")
+	}
+
+	close(producer.release)
+	ac.Close()
+
+	// One in progress, one queued and one dropped.
+	if n := len(producer.produced); n+int(sink.Dropped()) != 3 || n == 0 {
+		t.Fatalf("expected the messages of the full queue to be dropped but got: %d produced and %d dropped", n, sink.Dropped())
+	}
+}
+
+var syslogRegexp = regexp.MustCompile(`^<(\d+)>1 1993-01-01T05:00:00Z host app \d+ accesslog - \{.*"code":(\d+).*\}$`)
+
+func TestSyslogSink(t *testing.T) {
+	staticNow, _ := time.Parse(defaultTimeFormat, "1993-01-01 05:00:00")
+	opts := SyslogOptions{Hostname: "host", AppName: "app"}
+
+	// UDP, one message per datagram.
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pc.Close()
+
+	sink, err := NewSyslogSink("udp", pc.LocalAddr().String(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ac := New(ioutil.Discard)
+	ac.Clock = TClock(staticNow)
+	ac.AddSink(sink)
+
+	tests := []struct {
+		code int
+		pri  string
+	}{
+		{200, "134"}, // local0.info
+		{404, "132"}, // local0.warning
+		{500, "131"}, // local0.err
+	}
+
+	buf := make([]byte, 1024)
+	for _, tt := range tests {
+		if err = printSinkLog(ac, tt.code); err != nil {
+			t.Fatal(err)
+		}
+
+		pc.SetReadDeadline(time.Now().Add(5 * time.Second))
+		n, _, err := pc.ReadFrom(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got := string(buf[:n])
+		m := syslogRegexp.FindStringSubmatch(got)
+		if m == nil || m[1] != tt.pri || m[2] != strconv.Itoa(tt.code) {
+			t.Fatalf("[%d] unexpected syslog message: %s", tt.code, got)
+		}
+	}
+	ac.Close()
+
+	// TCP, octet-counting framing.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			received <- err.Error()
+			return
+		}
+		defer conn.Close()
+
+		line, _ := bufio.NewReader(conn).ReadString('}')
+		received <- line
+	}()
+
+	sink, err = NewSyslogSink("tcp", ln.Addr().String(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ac = New(ioutil.Discard)
+	ac.Clock = TClock(staticNow)
+	ac.AddSink(sink)
+	if err = printSinkLog(ac, 200); err != nil {
+		t.Fatal(err)
+	}
+	ac.Close()
+
+	got := <-received
+	idx := strings.IndexByte(got, ' ')
+	if idx == -1 || got[:idx] != strconv.Itoa(len(got)-idx-1) || !syslogRegexp.MatchString(got[idx+1:]) {
+		t.Fatalf("unexpected framed syslog message: %s", got)
+	}
+}
+
+func TestSyslogSinkKernFacility(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pc.Close()
+
+	sink, err := NewSyslogSink("udp", pc.LocalAddr().String(), SyslogOptions{Facility: 0, FacilitySet: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ac := New(ioutil.Discard)
+	ac.AddSink(sink)
+	if err = printSinkLog(ac, 200); err != nil {
+		t.Fatal(err)
+	}
+	ac.Close()
+
+	buf := make([]byte, 1024)
+	pc.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := pc.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := string(buf[:n]); !strings.HasPrefix(got, "<6>1 ") { // kern.info
+		t.Fatalf("unexpected syslog message: %s", got)
+	}
+}
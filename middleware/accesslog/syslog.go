@@ -0,0 +1,256 @@
+package accesslog
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// SyslogOptions holds the optional settings of a `SyslogSink`.
+type SyslogOptions struct {
+	// Facility is the syslog facility code.
+	// It's used only when the FacilitySet field is true.
+	//
+	// Defaults to 16 (local0).
+	Facility int
+	// FacilitySet reports whether the Facility field is set,
+	// so the kern (0) facility can be selected.
+	//
+	// Defaults to false.
+	FacilitySet bool
+	// Hostname is the HOSTNAME field.
+	//
+	// Defaults to os.Hostname.
+	Hostname string
+	// AppName is the APP-NAME field.
+	//
+	// Defaults to the executable's name.
+	AppName string
+	// MsgID is the MSGID field.
+	//
+	// Defaults to "accesslog".
+	MsgID string
+	// Format encodes the log to the MSG part.
+	//
+	// Defaults to DefaultSinkFormat (JSON).
+	Format SinkFormat
+	// Timeout is the connection and write timeout.
+	//
+	// Defaults to 5 seconds.
+	Timeout time.Duration
+	// QueueSize is the size of the queue of the messages
+	// which are written to the connection by a background goroutine,
+	// so a slow or unreachable server can't add latency to request handling.
+	// See `AsyncWriter`.
+	//
+	// Defaults to 1024.
+	QueueSize int
+	// QueuePolicy is the policy of a full queue.
+	//
+	// Defaults to QueueDrop.
+	QueuePolicy QueuePolicy
+}
+
+// The syslog severities of the access logs, based on the response status code.
+const (
+	syslogSeverityError   = 3 // 5xx.
+	syslogSeverityWarning = 4 // 4xx.
+	syslogSeverityInfo    = 6
+)
+
+// SyslogSink is a Sink which sends the logs to a syslog server
+// in the RFC 5424 format, over UDP, TCP (octet-counting framing, RFC 6587)
+// or a unix socket. The messages are written through an `AsyncWriter`
+// and the connection is re-established on write failures.
+type SyslogSink struct {
+	opts     SyslogOptions
+	facility int
+	procID   string
+	stream   bool
+
+	mu  sync.Mutex // protects the buf.
+	buf bytes.Buffer
+
+	w *AsyncWriter
+}
+
+var (
+	_ Sink      = (*SyslogSink)(nil)
+	_ Flusher   = (*SyslogSink)(nil)
+	_ io.Closer = (*SyslogSink)(nil)
+)
+
+// NewSyslogSink returns a new SyslogSink which dials the syslog server
+// of the given "network" ("udp", "tcp", "unix" or "unixgram") and "addr".
+func NewSyslogSink(network, addr string, opts SyslogOptions) (*SyslogSink, error) {
+	facility := 16
+	if opts.FacilitySet {
+		facility = opts.Facility
+	}
+
+	if opts.Hostname == "" {
+		opts.Hostname, _ = os.Hostname()
+	}
+
+	if opts.AppName == "" {
+		opts.AppName = filepath.Base(os.Args[0])
+	}
+
+	if opts.MsgID == "" {
+		opts.MsgID = "accesslog"
+	}
+
+	if opts.Format == nil {
+		opts.Format = DefaultSinkFormat
+	}
+
+	if opts.Timeout <= 0 {
+		opts.Timeout = 5 * time.Second
+	}
+
+	conn := &syslogConn{network: network, addr: addr, timeout: opts.Timeout}
+	if err := conn.dial(); err != nil {
+		return nil, err
+	}
+
+	s := &SyslogSink{
+		opts:     opts,
+		facility: facility,
+		procID:   strconv.Itoa(os.Getpid()),
+		stream:   network == "tcp" || network == "tcp4" || network == "tcp6" || network == "unix",
+		w:        NewAsyncWriter(conn, opts.QueueSize, opts.QueuePolicy),
+	}
+
+	return s, nil
+}
+
+// Send completes the Sink interface.
+func (s *SyslogSink) Send(log *Log) error {
+	msg, err := s.opts.Format(log)
+	if err != nil {
+		return err
+	}
+
+	severity := syslogSeverityInfo
+	if log.Code >= 500 {
+		severity = syslogSeverityError
+	} else if log.Code >= 400 {
+		severity = syslogSeverityWarning
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// <PRI>VERSION TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA MSG
+	var header bytes.Buffer
+	header.WriteByte('<')
+	header.WriteString(strconv.Itoa(s.facility*8 + severity))
+	header.WriteString(">1 ")
+	header.WriteString(log.Now.Format(time.RFC3339Nano))
+	header.WriteByte(' ')
+	writeSyslogField(&header, s.opts.Hostname)
+	writeSyslogField(&header, s.opts.AppName)
+	writeSyslogField(&header, s.procID)
+	writeSyslogField(&header, s.opts.MsgID)
+	header.WriteString("- ")
+
+	s.buf.Reset()
+	if s.stream {
+		s.buf.WriteString(strconv.Itoa(header.Len() + len(msg)))
+		s.buf.WriteByte(' ')
+	}
+	s.buf.Write(header.Bytes())
+	s.buf.Write(msg)
+
+	_, err = s.w.Write(s.buf.Bytes()) // the AsyncWriter keeps a copy.
+	return err
+}
+
+// Dropped returns the total number of the dropped messages
+// because the queue was full, see `SyslogOptions.QueuePolicy`.
+func (s *SyslogSink) Dropped() uint64 {
+	return s.w.Dropped()
+}
+
+// Flush waits for the queued messages to be sent
+// and returns the last write error, if any.
+func (s *SyslogSink) Flush() error {
+	return s.w.Flush()
+}
+
+// syslogConn is the destination writer of a SyslogSink's queue,
+// each write is a single message.
+type syslogConn struct {
+	network, addr string
+	timeout       time.Duration
+
+	conn net.Conn
+}
+
+func (c *syslogConn) dial() error {
+	conn, err := net.DialTimeout(c.network, c.addr, c.timeout)
+	if err != nil {
+		return err
+	}
+
+	c.conn = conn
+	return nil
+}
+
+func (c *syslogConn) Write(p []byte) (int, error) {
+	n, err := c.write(p)
+	if err != nil {
+		// try once more with a new connection.
+		if c.conn != nil {
+			c.conn.Close()
+			c.conn = nil
+		}
+
+		if err = c.dial(); err != nil {
+			return 0, err
+		}
+
+		n, err = c.write(p)
+	}
+
+	return n, err
+}
+
+func (c *syslogConn) write(p []byte) (int, error) {
+	if c.conn == nil {
+		return 0, net.ErrClosed
+	}
+
+	c.conn.SetWriteDeadline(time.Now().Add(c.timeout))
+	return c.conn.Write(p)
+}
+
+func (c *syslogConn) Close() error {
+	if c.conn == nil {
+		return nil
+	}
+
+	err := c.conn.Close()
+	c.conn = nil
+	return err
+}
+
+// writeSyslogField writes a header field, "-" if empty, followed by a space.
+func writeSyslogField(buf *bytes.Buffer, value string) {
+	if value == "" {
+		value = "-"
+	}
+
+	buf.WriteString(value)
+	buf.WriteByte(' ')
+}
+
+// Close sends the queued messages and closes the connection.
+func (s *SyslogSink) Close() error {
+	return s.w.Close()
+}
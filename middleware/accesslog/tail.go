@@ -0,0 +1,126 @@
+package accesslog
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/kataras/iris/v12/context"
+)
+
+// tailQueueSize is the number of the pending logs per tail client,
+// logs are dropped for slow clients.
+const tailQueueSize = 128
+
+// TailHandler streams the incoming logs to the client as Server-Sent Events,
+// each event's data is a log encoded as JSON (see `DefaultSinkFormat`),
+// e.g. to debug production traffic without accessing the server's log files.
+// Requests of the tail handler itself are not logged.
+//
+// Each client can filter the logs through the URL query parameters:
+//  code:   the minimum status code, e.g. ?code=500
+//  method: the HTTP method, e.g. ?method=POST
+//  path:   the request path prefix, e.g. ?path=/api
+//  ip:     the remote address, e.g. ?ip=::1
+//
+// Usage:
+//  app.Get("/debug/accesslog", basicAuth, ac.TailHandler)
+//
+// Make sure it's protected, logs may contain sensitive data,
+// see `SetRedactor` method too.
+func (ac *AccessLog) TailHandler(ctx *context.Context) {
+	Skip(ctx)
+
+	filter := tailFilter(ctx)
+	broker := ac.Broker()
+
+	ctx.ContentType("text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("X-Accel-Buffering", "no") // disable nginx buffering.
+	ctx.StatusCode(200)
+	ctx.ResponseWriter().Flush()
+
+	var (
+		logs  = broker.NewListener()
+		queue = make(chan []byte, tailQueueSize)
+		done  = ctx.Request().Context().Done()
+	)
+
+	// The broker waits for each listener to receive a log,
+	// so they are read and queued as fast as possible.
+	go func() {
+		defer close(queue)
+
+		for {
+			select {
+			case log, ok := <-logs:
+				if !ok { // access log closed.
+					return
+				}
+
+				if filter != nil && !filter(&log) {
+					continue
+				}
+
+				b, err := DefaultSinkFormat(&log)
+				if err != nil {
+					continue
+				}
+
+				select {
+				case queue <- b:
+				default: // slow client, drop it.
+				}
+			case <-done:
+				broker.closeListener(logs)
+				return
+			}
+		}
+	}()
+
+	for b := range queue {
+		if _, err := ctx.Write(tailEvent(b)); err != nil {
+			return
+		}
+
+		ctx.ResponseWriter().Flush()
+	}
+}
+
+func tailEvent(data []byte) []byte {
+	event := make([]byte, 0, len(data)+8)
+	event = append(event, "data: "...)
+	event = append(event, data...)
+	return append(event, '\n', '\n')
+}
+
+func tailFilter(ctx *context.Context) Filter {
+	var filters []Filter
+
+	if code, err := strconv.Atoi(ctx.URLParam("code")); err == nil {
+		filters = append(filters, FilterStatus(code))
+	}
+
+	if method := ctx.URLParam("method"); method != "" {
+		filters = append(filters, func(log *Log) bool {
+			return strings.EqualFold(log.Method, method)
+		})
+	}
+
+	if path := ctx.URLParam("path"); path != "" {
+		filters = append(filters, func(log *Log) bool {
+			return strings.HasPrefix(log.Path, path)
+		})
+	}
+
+	if ip := ctx.URLParam("ip"); ip != "" {
+		filters = append(filters, func(log *Log) bool {
+			return log.IP == ip
+		})
+	}
+
+	if len(filters) == 0 {
+		return nil
+	}
+
+	return FilterAll(filters...)
+}
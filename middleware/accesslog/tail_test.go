@@ -0,0 +1,68 @@
+package accesslog_test
+
+import (
+	"bufio"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/middleware/accesslog"
+)
+
+func TestTailHandler(t *testing.T) {
+	ac := accesslog.New(ioutil.Discard)
+	defer ac.Close()
+
+	app := iris.New()
+	app.UseRouter(ac.Handler)
+	app.Get("/debug/accesslog", ac.TailHandler)
+	app.Get("/", func(ctx iris.Context) {
+		ctx.WriteString("index")
+	})
+	if err := app.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewServer(app)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/debug/accesslog?code=400&method=get")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if expected, got := "text/event-stream", resp.Header.Get("Content-Type"); !strings.HasPrefix(got, expected) {
+		t.Fatalf("expected content type: %s but got: %s", expected, got)
+	}
+
+	events := make(chan string, 1)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			if line := scanner.Text(); strings.HasPrefix(line, "data: ") {
+				events <- line
+				return
+			}
+		}
+	}()
+
+	// Filtered out by the client's filter.
+	http.Get(srv.URL + "/")
+	http.Post(srv.URL+"/notfound", "text/plain", nil)
+	// Passes the filter.
+	http.Get(srv.URL + "/notfound")
+
+	select {
+	case event := <-events:
+		if !strings.Contains(event, `"code":404,"method":"GET","path":"/notfound"`) {
+			t.Fatalf("unexpected event: %s", event)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for the log event")
+	}
+}
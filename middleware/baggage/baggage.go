@@ -0,0 +1,134 @@
+// Package baggage implements the W3C Baggage propagation for Iris,
+// see https://www.w3.org/TR/baggage.
+package baggage
+
+import (
+	"net/http"
+
+	"github.com/kataras/iris/v12/context"
+)
+
+func init() {
+	context.SetHandlerName("iris/middleware/baggage.*", "iris.baggage")
+}
+
+// Policy holds the rules which are enforced on the incoming baggage.
+type Policy struct {
+	// AllowedKeys, if not empty, is the list of the accepted member keys,
+	// the rest members are dropped.
+	AllowedKeys []string
+	// MaxMembers is the maximum number of the accepted members,
+	// the overflowing ones are dropped.
+	// Defaults to `context.BaggageMaxMembers`.
+	MaxMembers int
+	// MaxBytes is the maximum size of the accepted members (encoded),
+	// the overflowing ones are dropped.
+	// Defaults to `context.BaggageMaxBytes`.
+	MaxBytes int
+}
+
+// Enforce applies the policy to the "b" baggage.
+func (p Policy) Enforce(b *context.Baggage) {
+	maxMembers := p.MaxMembers
+	if maxMembers <= 0 {
+		maxMembers = context.BaggageMaxMembers
+	}
+
+	maxBytes := p.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = context.BaggageMaxBytes
+	}
+
+	var allowed map[string]struct{}
+	if len(p.AllowedKeys) > 0 {
+		allowed = make(map[string]struct{}, len(p.AllowedKeys))
+		for _, key := range p.AllowedKeys {
+			allowed[key] = struct{}{}
+		}
+	}
+
+	n, size := 0, 0
+	b.Filter(func(m context.BaggageMember) bool {
+		if allowed != nil {
+			if _, ok := allowed[m.Key]; !ok {
+				return false
+			}
+		}
+
+		memberSize := len(m.String())
+		if n > 0 {
+			memberSize++ // comma.
+		}
+
+		if n >= maxMembers || size+memberSize > maxBytes {
+			return false
+		}
+
+		n++
+		size += memberSize
+		return true
+	})
+}
+
+// New returns a new baggage middleware which parses the incoming "baggage" header,
+// enforces the "policy" and stores the result to the request,
+// see `Context.Baggage` to retrieve it.
+//
+// Usage:
+//  app.Use(baggage.New(baggage.Policy{AllowedKeys: []string{"tenant", "experiment"}}))
+//
+// The baggage is propagated to outgoing requests made through the `Transport`.
+func New(policy Policy) context.Handler {
+	return func(ctx *context.Context) {
+		b, _ := context.ParseBaggage(ctx.GetHeader(context.BaggageHeaderKey))
+		policy.Enforce(b)
+		ctx.SetBaggage(b)
+
+		ctx.Next()
+	}
+}
+
+// Transport is a `net/http#RoundTripper` which sets the "baggage" header
+// of outgoing requests to the baggage of their context, if the header is missing.
+//
+// Usage:
+//  client := &http.Client{Transport: baggage.NewTransport(nil)}
+//  req, _ := http.NewRequestWithContext(ctx.Request().Context(), http.MethodGet, url, nil)
+//  client.Do(req)
+type Transport struct {
+	// Base is the underline RoundTripper.
+	// Defaults to `http.DefaultTransport`.
+	Base http.RoundTripper
+}
+
+// NewTransport returns a new baggage `Transport` which wraps the "base" RoundTripper.
+func NewTransport(base http.RoundTripper) *Transport {
+	return &Transport{Base: base}
+}
+
+// RoundTrip implements the `net/http#RoundTripper` interface.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	if b := context.BaggageFromContext(req.Context()); b.Len() > 0 && req.Header.Get(context.BaggageHeaderKey) == "" {
+		req = req.Clone(req.Context())
+		req.Header.Set(context.BaggageHeaderKey, b.String())
+	}
+
+	return base.RoundTrip(req)
+}
+
+// Client returns a shallow copy of the "client" (or the `http.DefaultClient` if nil)
+// which propagates the baggage of its requests' context, see `Transport`.
+func Client(client *http.Client) *http.Client {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	c := *client
+	c.Transport = NewTransport(client.Transport)
+	return &c
+}
@@ -0,0 +1,73 @@
+package baggage_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	stdhttptest "net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/httptest"
+	"github.com/kataras/iris/v12/middleware/baggage"
+)
+
+func TestBaggage(t *testing.T) {
+	downstream := stdhttptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Header.Get(context.BaggageHeaderKey)))
+	}))
+	defer downstream.Close()
+
+	client := baggage.Client(nil)
+
+	app := iris.New()
+	app.Use(baggage.New(baggage.Policy{AllowedKeys: []string{"tenant", "variant", "user"}}))
+	app.Get("/", func(ctx iris.Context) {
+		b := ctx.Baggage()
+		ctx.Writef("%s:%d:%d", b.Get("tenant"), b.GetIntDefault("variant", -1), b.Len())
+	})
+	app.Get("/outbound", func(ctx iris.Context) {
+		req, err := http.NewRequestWithContext(ctx.Request().Context(), http.MethodGet, downstream.URL, nil)
+		if err != nil {
+			ctx.StopWithError(iris.StatusInternalServerError, err)
+			return
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			ctx.StopWithError(iris.StatusBadGateway, err)
+			return
+		}
+		defer resp.Body.Close()
+
+		body, _ := ioutil.ReadAll(resp.Body)
+		ctx.Write(body)
+	})
+
+	e := httptest.New(t, app)
+	e.GET("/").WithHeader("baggage", "tenant=acme, variant=2;ttl=60, secret=x, malformed").Expect().
+		Status(httptest.StatusOK).Body().Equal("acme:2:2")
+	e.GET("/").Expect().Status(httptest.StatusOK).Body().Equal(":-1:0")
+
+	e.GET("/outbound").WithHeader("baggage", "user=J%C3%B6rg%20K,tenant=acme,secret=x").Expect().
+		Status(httptest.StatusOK).Body().Equal("user=J%C3%B6rg%20K,tenant=acme")
+}
+
+func TestBaggagePolicy(t *testing.T) {
+	b, err := context.ParseBaggage("a=1,b=2,c=3")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	baggage.Policy{MaxMembers: 2}.Enforce(b)
+	if expected, got := "a=1,b=2", b.String(); expected != got {
+		t.Fatalf("expected: %s but got: %s", expected, got)
+	}
+
+	b, _ = context.ParseBaggage("a=1,b=" + strings.Repeat("x", 10))
+	baggage.Policy{MaxBytes: 10}.Enforce(b)
+	if expected, got := "a=1", b.String(); expected != got {
+		t.Fatalf("expected: %s but got: %s", expected, got)
+	}
+}
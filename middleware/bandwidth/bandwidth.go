@@ -0,0 +1,268 @@
+// Package bandwidth implements token-bucket bandwidth limits for Iris responses,
+// including file serving and streaming writes.
+package bandwidth
+
+import (
+	"bufio"
+	stdContext "context"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kataras/iris/v12/context"
+
+	"golang.org/x/time/rate"
+)
+
+func init() {
+	context.SetHandlerName("iris/middleware/bandwidth.(*Bandwidth).Handler-fm", "iris.bandwidth")
+}
+
+type (
+	// Limit holds the token-bucket settings of a bandwidth limit.
+	Limit struct {
+		// BytesPerSecond is the sustained rate of the response bytes.
+		// Zero means no limit.
+		BytesPerSecond int
+		// Burst is the maximum number of bytes
+		// that can be written at once.
+		// Defaults to BytesPerSecond (one second of data).
+		Burst int
+	}
+
+	// Options holds the bandwidth limits of a `Bandwidth` handler.
+	// Zero limits are ignored.
+	Options struct {
+		// Global limits the total bandwidth of all responses
+		// served by the handler.
+		Global Limit
+		// Connection limits the bandwidth of each client connection,
+		// shared by its concurrent requests, e.g. HTTP/2 streams.
+		Connection Limit
+		// Request limits the bandwidth of each response.
+		Request Limit
+		// Tier, if not nil, reports the tier of the request's client,
+		// e.g. based on its API key. The limit of that tier
+		// from the Tiers field overrides the Request limit.
+		Tier func(ctx *context.Context) string
+		// Tiers holds the per-request limits of each tier.
+		Tiers map[string]Limit
+	}
+
+	// Metrics holds the statistics of a `Bandwidth` handler.
+	Metrics struct {
+		// Requests is the number of throttled requests so far.
+		Requests uint64
+		// Active is the number of the in-flight throttled requests.
+		Active int64
+		// BytesWritten is the number of response bytes written so far.
+		BytesWritten uint64
+		// Waited is the total time the responses waited for tokens.
+		Waited time.Duration
+	}
+
+	// Bandwidth is the bandwidth limiter.
+	// Use its `Handler` method to register it as a middleware,
+	// globally, per Party or per route.
+	Bandwidth struct {
+		opts   Options
+		global *rate.Limiter
+
+		mu    sync.Mutex
+		conns map[string]*connLimiter
+
+		requests     uint64
+		active       int64
+		bytesWritten uint64
+		waited       int64
+	}
+
+	connLimiter struct {
+		limiter *rate.Limiter
+		refs    int
+	}
+)
+
+func (l Limit) limiter() *rate.Limiter {
+	if l.BytesPerSecond <= 0 {
+		return nil
+	}
+
+	burst := l.Burst
+	if burst <= 0 {
+		burst = l.BytesPerSecond
+	}
+
+	return rate.NewLimiter(rate.Limit(l.BytesPerSecond), burst)
+}
+
+// New returns a new bandwidth limiter based on the given "opts".
+//
+// Usage:
+//  b := bandwidth.New(bandwidth.Options{
+//      Global:  bandwidth.Limit{BytesPerSecond: 10 << 20},
+//      Request: bandwidth.Limit{BytesPerSecond: 512 << 10, Burst: 64 << 10},
+//      Tier: func(ctx iris.Context) string {
+//          return ctx.GetHeader("X-API-Tier")
+//      },
+//      Tiers: map[string]bandwidth.Limit{"pro": {BytesPerSecond: 2 << 20}},
+//  })
+//  downloads := app.Party("/downloads", b.Handler)
+func New(opts Options) *Bandwidth {
+	return &Bandwidth{
+		opts:   opts,
+		global: opts.Global.limiter(),
+		conns:  make(map[string]*connLimiter),
+	}
+}
+
+// Handler throttles the response writes of the next handlers.
+func (b *Bandwidth) Handler(ctx *context.Context) {
+	limiters := make([]*rate.Limiter, 0, 3)
+	if b.global != nil {
+		limiters = append(limiters, b.global)
+	}
+
+	connKey := ctx.Request().RemoteAddr
+	if l := b.acquireConn(connKey); l != nil {
+		limiters = append(limiters, l)
+		defer b.releaseConn(connKey)
+	}
+
+	reqLimit := b.opts.Request
+	if b.opts.Tier != nil {
+		if l, ok := b.opts.Tiers[b.opts.Tier(ctx)]; ok {
+			reqLimit = l
+		}
+	}
+	if l := reqLimit.limiter(); l != nil {
+		limiters = append(limiters, l)
+	}
+
+	if len(limiters) == 0 {
+		ctx.Next()
+		return
+	}
+
+	atomic.AddUint64(&b.requests, 1)
+	atomic.AddInt64(&b.active, 1)
+	defer atomic.AddInt64(&b.active, -1)
+
+	w := ctx.ResponseWriter()
+	w.SetWriter(&throttledWriter{
+		ResponseWriter: w.Naive(),
+		ctx:            ctx.Request().Context(),
+		limiters:       limiters,
+		b:              b,
+	})
+
+	ctx.Next()
+}
+
+func (b *Bandwidth) acquireConn(key string) *rate.Limiter {
+	if b.opts.Connection.BytesPerSecond <= 0 {
+		return nil
+	}
+
+	b.mu.Lock()
+	c, ok := b.conns[key]
+	if !ok {
+		c = &connLimiter{limiter: b.opts.Connection.limiter()}
+		b.conns[key] = c
+	}
+	c.refs++
+	b.mu.Unlock()
+
+	return c.limiter
+}
+
+func (b *Bandwidth) releaseConn(key string) {
+	b.mu.Lock()
+	if c, ok := b.conns[key]; ok {
+		c.refs--
+		if c.refs <= 0 {
+			delete(b.conns, key)
+		}
+	}
+	b.mu.Unlock()
+}
+
+// Metrics returns a snapshot of the bandwidth limiter's statistics.
+func (b *Bandwidth) Metrics() Metrics {
+	return Metrics{
+		Requests:     atomic.LoadUint64(&b.requests),
+		Active:       atomic.LoadInt64(&b.active),
+		BytesWritten: atomic.LoadUint64(&b.bytesWritten),
+		Waited:       time.Duration(atomic.LoadInt64(&b.waited)),
+	}
+}
+
+// throttledWriter wraps the underline net/http response writer,
+// all writes of the Iris response writers (e.g. compress, recorder)
+// end up here.
+type throttledWriter struct {
+	http.ResponseWriter
+
+	ctx      stdContext.Context
+	limiters []*rate.Limiter
+	b        *Bandwidth
+}
+
+func (w *throttledWriter) Write(p []byte) (int, error) {
+	if len(p) == 0 {
+		return w.ResponseWriter.Write(p)
+	}
+
+	written := 0
+	for len(p) > 0 {
+		chunk := len(p)
+		for _, l := range w.limiters {
+			if burst := l.Burst(); burst > 0 && chunk > burst {
+				chunk = burst
+			}
+		}
+
+		start := time.Now()
+		for _, l := range w.limiters {
+			if err := l.WaitN(w.ctx, chunk); err != nil {
+				return written, err
+			}
+		}
+		atomic.AddInt64(&w.b.waited, int64(time.Since(start)))
+
+		n, err := w.ResponseWriter.Write(p[:chunk])
+		written += n
+		atomic.AddUint64(&w.b.bytesWritten, uint64(n))
+		if err != nil {
+			return written, err
+		}
+
+		p = p[chunk:]
+	}
+
+	return written, nil
+}
+
+func (w *throttledWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (w *throttledWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if h, ok := w.ResponseWriter.(http.Hijacker); ok {
+		return h.Hijack()
+	}
+
+	return nil, nil, context.ErrHijackNotSupported
+}
+
+func (w *throttledWriter) Push(target string, opts *http.PushOptions) error {
+	if p, ok := w.ResponseWriter.(http.Pusher); ok {
+		return p.Push(target, opts)
+	}
+
+	return http.ErrNotSupported
+}
@@ -0,0 +1,68 @@
+package bandwidth_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/httptest"
+	"github.com/kataras/iris/v12/middleware/bandwidth"
+)
+
+func TestBandwidth(t *testing.T) {
+	const size = 48 << 10
+	data := strings.Repeat("a", size)
+
+	b := bandwidth.New(bandwidth.Options{
+		Request: bandwidth.Limit{BytesPerSecond: 64 << 10, Burst: 16 << 10},
+		Tier: func(ctx iris.Context) string {
+			return ctx.GetHeader("X-API-Tier")
+		},
+		Tiers: map[string]bandwidth.Limit{"pro": {BytesPerSecond: 64 << 20}},
+	})
+
+	app := iris.New()
+	app.Get("/stream", b.Handler, func(ctx iris.Context) {
+		ctx.WriteString(data)
+	})
+	app.Get("/file", b.Handler, func(ctx iris.Context) {
+		ctx.ServeContent(bytes.NewReader([]byte(data)), "data.txt", time.Time{})
+	})
+	app.Get("/free", func(ctx iris.Context) {
+		ctx.WriteString(data)
+	})
+
+	e := httptest.New(t, app)
+
+	tests := []struct {
+		path      string
+		tier      string
+		throttled bool
+	}{
+		{"/stream", "", true},
+		{"/file", "", true},
+		{"/stream", "pro", false},
+		{"/free", "", false},
+	}
+
+	for i, tt := range tests {
+		start := time.Now()
+		req := e.GET(tt.path)
+		if tt.tier != "" {
+			req.WithHeader("X-API-Tier", tt.tier)
+		}
+		req.Expect().Status(httptest.StatusOK).Body().Length().Equal(size)
+
+		// 16KB burst, the rest 32KB at 64KB/s.
+		if elapsed := time.Since(start); (elapsed >= 400*time.Millisecond) != tt.throttled {
+			t.Fatalf("[%d] %s: expected throttled: %v but took: %s", i, tt.path, tt.throttled, elapsed)
+		}
+	}
+
+	metrics := b.Metrics()
+	if metrics.Requests != 3 || metrics.Active != 0 || metrics.BytesWritten != 3*size || metrics.Waited < 800*time.Millisecond {
+		t.Fatalf("unexpected metrics: %#+v", metrics)
+	}
+}
@@ -15,6 +15,7 @@ import (
 
 func init() {
 	context.SetHandlerName("iris/middleware/basicauth.*", "iris.basicauth")
+	context.SetHandlerPolicy("iris.basicauth", context.PolicyAuth)
 }
 
 const (
@@ -0,0 +1,332 @@
+// Package botdetect implements request fingerprinting and bot detection for Iris.
+package botdetect
+
+import (
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/middleware/rate"
+)
+
+func init() {
+	context.SetHandlerName("iris/middleware/botdetect.*", "iris.botdetect")
+}
+
+// BotScore is the minimum score that a non-classified client
+// should reach in order to be classified as a bot by the `New` middleware.
+var BotScore = 50
+
+// GoodBots is a list of lowercase User-Agent substrings
+// of the well-known crawlers, they get a "claims-good-bot" signal.
+// Note that the User-Agent can be spoofed, so they are
+// classified as `context.BotGood` only through `VerifyGoodBots`.
+var GoodBots = []string{
+	"googlebot", "bingbot", "duckduckbot", "yandexbot", "baiduspider",
+	"applebot", "slurp", "facebookexternalhit", "twitterbot", "linkedinbot",
+}
+
+// GoodBotHosts is a list of the reverse DNS host suffixes
+// of the well-known crawlers, see `ReverseDNS`.
+var GoodBotHosts = []string{
+	".googlebot.com", ".google.com", ".search.msn.com", ".duckduckgo.com",
+	".yandex.ru", ".yandex.net", ".yandex.com", ".baidu.com", ".baidu.jp",
+	".applebot.apple.com", ".crawl.yahoo.net",
+}
+
+// Headless is a list of lowercase User-Agent substrings of headless browsers
+// and browser automation tools.
+var Headless = []string{
+	"headlesschrome", "phantomjs", "puppeteer", "playwright", "selenium", "webdriver", "electron",
+}
+
+// Headers is a `context.Fingerprinter` which scores the client
+// based on its request headers. It's the default fingerprinter of the `New` middleware.
+//
+// Signals:
+//  "missing-user-agent", "script", "headless", "claims-good-bot",
+//  "missing-accept", "missing-accept-language" and "missing-accept-encoding".
+var Headers context.FingerprinterFunc = func(ctx *context.Context, fp *context.Fingerprint) {
+	ua := strings.ToLower(ctx.GetHeader("User-Agent"))
+	if ua == "" {
+		fp.AddScore(60, "missing-user-agent")
+	} else {
+		if contains(ua, GoodBots) {
+			fp.Signals = append(fp.Signals, "claims-good-bot")
+		}
+
+		if ctx.IsScript() {
+			fp.AddScore(60, "script")
+		}
+
+		if contains(ua, Headless) {
+			fp.AddScore(50, "headless")
+		}
+	}
+
+	if ctx.GetHeader("Accept") == "" {
+		fp.AddScore(15, "missing-accept")
+	}
+
+	if ctx.GetHeader("Accept-Language") == "" {
+		fp.AddScore(20, "missing-accept-language")
+	}
+
+	if ctx.GetHeader("Accept-Encoding") == "" {
+		fp.AddScore(10, "missing-accept-encoding")
+	}
+}
+
+func contains(s string, substrings []string) bool {
+	for _, sub := range substrings {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IPReputation is the interface which IP reputation services
+// (e.g. block lists, abuse databases, reverse DNS verification) should implement.
+// See the `Reputation` package-level function.
+type IPReputation interface {
+	// Reputation should return a score from 0 (trusted) to 100 (malicious) for the given IP.
+	// A negative score lowers the client's total score.
+	Reputation(ip string) (int, error)
+}
+
+// IPReputationFunc is the function type of an `IPReputation`.
+type IPReputationFunc func(ip string) (int, error)
+
+// Reputation completes the `IPReputation` interface.
+func (fn IPReputationFunc) Reputation(ip string) (int, error) {
+	return fn(ip)
+}
+
+// Reputation returns a `context.Fingerprinter` which adds the score of the client's IP
+// to the fingerprint's score. Errors are recorded as an "ip-reputation-error" signal,
+// the request is not stopped.
+func Reputation(r IPReputation) context.Fingerprinter {
+	return context.FingerprinterFunc(func(ctx *context.Context, fp *context.Fingerprint) {
+		score, err := r.Reputation(fp.IP)
+		if err != nil {
+			fp.Signals = append(fp.Signals, "ip-reputation-error")
+			return
+		}
+
+		if score != 0 {
+			fp.AddScore(score, "ip-reputation")
+		}
+	})
+}
+
+// VerifyGoodBots returns a `context.Fingerprinter` which verifies the clients
+// that claim to be a well-known crawler through their User-Agent,
+// the ones with the "claims-good-bot" signal of `Headers`, so it should be registered after that.
+// The "verify" function reports whether the client's IP belongs to a well-known crawler, e.g. `ReverseDNS`.
+// Verified clients are classified as `context.BotGood`,
+// the rest get a score of 100 and a "spoofed-good-bot" signal.
+// Errors are recorded as a "good-bot-verification-error" signal, the client is not classified.
+//
+// Usage:
+//  app.UseRouter(botdetect.New(botdetect.Headers, botdetect.VerifyGoodBots(botdetect.ReverseDNS)))
+func VerifyGoodBots(verify func(ip string) (bool, error)) context.Fingerprinter {
+	return context.FingerprinterFunc(func(ctx *context.Context, fp *context.Fingerprint) {
+		if !fp.HasSignal("claims-good-bot") {
+			return
+		}
+
+		ok, err := verify(fp.IP)
+		if err != nil {
+			fp.Signals = append(fp.Signals, "good-bot-verification-error")
+			return
+		}
+
+		if !ok {
+			fp.AddScore(100, "spoofed-good-bot")
+			return
+		}
+
+		fp.Class = context.BotGood
+		fp.Signals = append(fp.Signals, "good-bot")
+	})
+}
+
+// ReverseDNS reports whether the "ip" belongs to a well-known crawler:
+// one of its reverse DNS hosts should end with one of the `GoodBotHosts`
+// and that host should resolve back to the same IP.
+// It can be passed to the `VerifyGoodBots` function.
+// Note that it performs two DNS lookups per call, wrap it with a cache on high traffic.
+func ReverseDNS(ip string) (bool, error) {
+	hosts, err := net.LookupAddr(ip)
+	if err != nil {
+		if dnsErr, ok := err.(*net.DNSError); ok && dnsErr.IsNotFound {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	for _, host := range hosts {
+		host = strings.TrimSuffix(strings.ToLower(host), ".")
+		if !hasSuffix(host, GoodBotHosts) {
+			continue
+		}
+
+		addrs, err := net.LookupHost(host)
+		if err != nil {
+			return false, err
+		}
+
+		for _, addr := range addrs {
+			if addr == ip {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+func hasSuffix(s string, suffixes []string) bool {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(s, suffix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// New returns a new bot detection middleware which fills the request's `context.Fingerprint`
+// using the given fingerprinters, in order. If no fingerprinters are given then `Headers` is used instead.
+// After all fingerprinters are executed, a non-classified client is classified as `context.BotBad`
+// when its score is at least `BotScore`, otherwise as `context.BotHuman`.
+//
+// The middleware never stops the request,
+// use the `Policy` middleware to challenge, throttle or block bots per route.
+//
+// Usage:
+//  tlsFingerprint := botdetect.NewTLS()
+//  app.UseRouter(botdetect.New(botdetect.Headers, tlsFingerprint))
+//  app.Get("/", func(ctx iris.Context) {
+//      fp := ctx.Fingerprint()
+//  })
+func New(fingerprinters ...context.Fingerprinter) context.Handler {
+	if len(fingerprinters) == 0 {
+		fingerprinters = []context.Fingerprinter{Headers}
+	}
+
+	return func(ctx *context.Context) {
+		fp := ctx.SetFingerprint(fingerprinters...)
+		if fp.Class == context.BotUnknown {
+			if fp.Score >= BotScore {
+				fp.Class = context.BotBad
+			} else {
+				fp.Class = context.BotHuman
+			}
+		}
+
+		ctx.Next()
+	}
+}
+
+// Action describes what a `Policy` should do with a classified client.
+type Action uint8
+
+const (
+	// Default lets the `Policy` to decide the action,
+	// it's `Block` for bots and `Allow` for good bots.
+	Default Action = iota
+	// Allow continues to the next handler.
+	Allow
+	// Challenge executes the `Policy.Challenge` handler, e.g. a captcha middleware.
+	Challenge
+	// Throttle executes the `Policy.Throttle` handler, e.g. a rate limiter.
+	Throttle
+	// Block stops the request with 403 Forbidden.
+	Block
+)
+
+// Policy holds the actions per client class
+// of a bot detection policy, per route or Party.
+// See its `Handler` method.
+type Policy struct {
+	// Bot is the action for clients classified as `context.BotBad`.
+	// Defaults to Block.
+	Bot Action
+	// Good is the action for clients classified as `context.BotGood`.
+	// Defaults to Allow.
+	Good Action
+	// Score, if greater than zero, makes clients with a fingerprint score
+	// of at least this value to be treated as bots, even if they are classified as humans.
+	// Useful for stricter routes, e.g. login and checkout.
+	Score int
+	// Challenge is the handler which is executed on a Challenge action.
+	// It should call ctx.Next() when the client passes the challenge,
+	// e.g. the hcaptcha.New and recaptcha.New middlewares.
+	// Defaults to 403 Forbidden.
+	Challenge context.Handler
+	// Throttle is the handler which is executed on a Throttle action.
+	// It should call ctx.Next() when the client is allowed to continue.
+	// The rate limiter identifier is set to the client's IP,
+	// not the fingerprint ID, which a client can change through its request headers.
+	// Defaults to a rate limiter of 1 request per second with a burst of 5.
+	Throttle context.Handler
+}
+
+// Handler returns the policy middleware. It should be registered
+// after the `New` middleware, otherwise clients are not classified
+// and only the Score field of the policy is taken into account.
+//
+// Usage:
+//  app.Post("/login", botdetect.Policy{Bot: botdetect.Challenge, Challenge: hcaptcha.New(secret)}.Handler(), loginHandler)
+//  app.Get("/search", botdetect.Policy{Bot: botdetect.Throttle, Good: botdetect.Throttle}.Handler(), searchHandler)
+func (p Policy) Handler() context.Handler {
+	if p.Bot == Default {
+		p.Bot = Block
+	}
+
+	if p.Good == Default {
+		p.Good = Allow
+	}
+
+	if p.Challenge == nil {
+		p.Challenge = block
+	}
+
+	if p.Throttle == nil {
+		p.Throttle = rate.Limit(1, 5, rate.PurgeEvery(time.Minute, 5*time.Minute))
+	}
+
+	return func(ctx *context.Context) {
+		fp := ctx.Fingerprint()
+
+		action := Allow
+		switch {
+		case fp.Class == context.BotBad, p.Score > 0 && fp.Score >= p.Score:
+			action = p.Bot
+		case fp.Class == context.BotGood:
+			action = p.Good
+		}
+
+		switch action {
+		case Challenge:
+			p.Challenge(ctx)
+		case Throttle:
+			rate.SetIdentifier(ctx, fp.IP)
+			p.Throttle(ctx)
+		case Block:
+			block(ctx)
+		default:
+			ctx.Next()
+		}
+	}
+}
+
+func block(ctx *context.Context) {
+	ctx.StopWithStatus(http.StatusForbidden)
+}
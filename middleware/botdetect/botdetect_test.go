@@ -0,0 +1,141 @@
+package botdetect_test
+
+import (
+	"io/ioutil"
+	"net"
+	"net/http"
+	stdhttptest "net/http/httptest"
+	"testing"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/httptest"
+	"github.com/kataras/iris/v12/middleware/botdetect"
+)
+
+const browserUA = "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/90.0.4430.93 Safari/537.36"
+
+func TestBotDetect(t *testing.T) {
+	blocklist := botdetect.IPReputationFunc(func(ip string) (int, error) {
+		if ip == "1.2.3.4" {
+			return 100, nil
+		}
+
+		return 0, nil
+	})
+	verifyGoodBots := botdetect.VerifyGoodBots(func(ip string) (bool, error) {
+		return ip == "66.249.66.1", nil
+	})
+
+	app := iris.New().Configure(iris.WithRemoteAddrHeader("X-Real-Ip"))
+	app.UseRouter(botdetect.New(botdetect.Headers, botdetect.Reputation(blocklist), verifyGoodBots))
+
+	fingerprint := func(ctx iris.Context) {
+		ctx.JSON(ctx.Fingerprint())
+	}
+	app.Get("/", fingerprint)
+	app.Get("/protected", botdetect.Policy{}.Handler(), fingerprint)
+	app.Get("/strict", botdetect.Policy{Score: 10}.Handler(), fingerprint)
+	app.Get("/search", botdetect.Policy{Bot: botdetect.Throttle, Good: botdetect.Block}.Handler(), fingerprint)
+
+	e := httptest.New(t, app)
+	browser := func(path string) *httptest.Request {
+		return e.GET(path).WithHeader("User-Agent", browserUA).
+			WithHeader("Accept", "text/html").
+			WithHeader("Accept-Language", "en-US").
+			WithHeader("Accept-Encoding", "gzip")
+	}
+
+	fp := browser("/protected").Expect().Status(httptest.StatusOK).JSON().Object()
+	fp.Value("class").Equal(iris.BotHuman)
+	fp.Value("score").Equal(0)
+	fp.NotContainsKey("signals")
+	id := fp.Value("id").String().NotEmpty().Raw()
+	browser("/").WithHeader("Cookie", "session=1").Expect().Status(httptest.StatusOK).
+		JSON().Object().Value("id").Equal(id)
+
+	fp = e.GET("/").WithHeader("User-Agent", "curl/7.64.1").Expect().Status(httptest.StatusOK).JSON().Object()
+	fp.Value("class").Equal(iris.BotBad)
+	fp.Value("score").Equal(100)
+	fp.Value("signals").Array().Contains("script", "missing-accept-language")
+	e.GET("/protected").WithHeader("User-Agent", "curl/7.64.1").Expect().Status(httptest.StatusForbidden)
+	e.GET("/protected").Expect().Status(httptest.StatusForbidden)
+
+	browser("/protected").WithHeader("X-Real-Ip", "1.2.3.4").Expect().Status(httptest.StatusForbidden)
+	browser("/strict").Expect().Status(httptest.StatusOK)
+	e.GET("/strict").WithHeader("User-Agent", browserUA).WithHeader("Accept", "text/html").
+		WithHeader("Accept-Encoding", "gzip").Expect().Status(httptest.StatusForbidden)
+
+	googlebot := "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)"
+	fp = e.GET("/protected").WithHeader("User-Agent", googlebot).WithHeader("X-Real-Ip", "66.249.66.1").
+		Expect().Status(httptest.StatusOK).JSON().Object()
+	fp.Value("class").Equal(iris.BotGood)
+	fp.Value("signals").Array().Contains("claims-good-bot", "good-bot")
+	e.GET("/search").WithHeader("User-Agent", googlebot).WithHeader("X-Real-Ip", "66.249.66.1").
+		Expect().Status(httptest.StatusForbidden)
+	// A spoofed User-Agent is not enough.
+	fp = e.GET("/").WithHeader("User-Agent", googlebot).WithHeader("X-Real-Ip", "5.6.7.8").
+		Expect().Status(httptest.StatusOK).JSON().Object()
+	fp.Value("class").Equal(iris.BotBad)
+	fp.Value("signals").Array().Contains("claims-good-bot", "spoofed-good-bot").NotContains("good-bot")
+	e.GET("/protected").WithHeader("User-Agent", googlebot).WithHeader("X-Real-Ip", "5.6.7.8").
+		Expect().Status(httptest.StatusForbidden)
+
+	for i := 0; i < 5; i++ {
+		e.GET("/search").WithHeader("User-Agent", "python-requests/2.25").Expect().Status(httptest.StatusOK)
+	}
+	e.GET("/search").WithHeader("User-Agent", "python-requests/2.25").Expect().Status(httptest.StatusTooManyRequests)
+	// Rotating a header does not reset the limit.
+	e.GET("/search").WithHeader("User-Agent", "python-requests/2.26").Expect().Status(httptest.StatusTooManyRequests)
+	e.GET("/search").WithHeader("User-Agent", "python-requests/2.26").WithHeader("X-Real-Ip", "5.6.7.9").
+		Expect().Status(httptest.StatusOK)
+	browser("/search").Expect().Status(httptest.StatusOK)
+}
+
+func TestTLSFingerprint(t *testing.T) {
+	tlsFingerprint := botdetect.NewTLS()
+
+	app := iris.New()
+	app.UseRouter(botdetect.New(botdetect.Headers, tlsFingerprint))
+	app.Get("/", func(ctx iris.Context) {
+		ctx.WriteString(ctx.Fingerprint().TLS)
+	})
+	if err := app.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := stdhttptest.NewUnstartedServer(app)
+	srv.Listener = tlsFingerprint.Listener(ln)
+	srv.StartTLS()
+	defer srv.Close()
+
+	client := srv.Client()
+	var hashes []string
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(srv.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if resp.StatusCode != http.StatusOK || len(body) != 32 {
+			t.Fatalf("expected a JA3 hash but got: %d %q", resp.StatusCode, body)
+		}
+
+		hashes = append(hashes, string(body))
+		client.CloseIdleConnections()
+	}
+
+	if hashes[0] != hashes[1] {
+		t.Fatalf("expected the same JA3 hash for the same client but got: %v", hashes)
+	}
+}
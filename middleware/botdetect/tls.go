@@ -0,0 +1,296 @@
+package botdetect
+
+import (
+	"crypto/md5" // #nosec G501 JA3 is defined as an MD5 hash.
+	"encoding/binary"
+	"encoding/hex"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/kataras/iris/v12/context"
+)
+
+// TLS is a `context.Fingerprinter` which captures the TLS ClientHello
+// of the incoming connections and sets the JA3 fingerprint
+// (an MD5 hash of the TLS version, cipher suites, extensions, curves and point formats)
+// of the client to the request's `context.Fingerprint.TLS` field.
+//
+// The crypto/tls package does not expose the ClientHello extensions,
+// so the TLS fingerprinter wraps the raw TCP listener,
+// before the TLS one, see its `Listener` method.
+type TLS struct {
+	hashes sync.Map // remote address: JA3 hash.
+
+	// Known is an optional map of JA3 hashes to a bot class,
+	// e.g. the hashes of known scrapers or your own mobile application.
+	// It should be set before serving.
+	Known map[string]context.BotClass
+}
+
+// NewTLS returns a new TLS fingerprinter.
+//
+// Usage:
+//  tlsFingerprint := botdetect.NewTLS()
+//  ln, err := net.Listen("tcp", ":443")
+//  ln = tls.NewListener(tlsFingerprint.Listener(ln), tlsConfig)
+//  app.UseRouter(botdetect.New(botdetect.Headers, tlsFingerprint))
+//  app.Run(iris.Listener(ln))
+func NewTLS() *TLS {
+	return new(TLS)
+}
+
+// Listener wraps a raw (non-TLS) listener in order
+// to capture the TLS ClientHello of its connections.
+func (t *TLS) Listener(ln net.Listener) net.Listener {
+	return &tlsListener{Listener: ln, t: t}
+}
+
+// Get returns the JA3 hash of the connection of the given remote address,
+// e.g. ctx.Request().RemoteAddr. It returns an empty string
+// if the connection is closed or it's not a TLS one.
+func (t *TLS) Get(remoteAddr string) string {
+	if v, ok := t.hashes.Load(remoteAddr); ok {
+		return v.(string)
+	}
+
+	return ""
+}
+
+// Fingerprint completes the `context.Fingerprinter` interface.
+func (t *TLS) Fingerprint(ctx *context.Context, fp *context.Fingerprint) {
+	hash := t.Get(ctx.Request().RemoteAddr)
+	if hash == "" {
+		return
+	}
+
+	fp.SetTLS(hash)
+	if class, ok := t.Known[hash]; ok && class != context.BotUnknown {
+		fp.Class = class
+		fp.Signals = append(fp.Signals, "known-tls")
+	}
+}
+
+type tlsListener struct {
+	net.Listener
+	t *TLS
+}
+
+func (l *tlsListener) Accept() (net.Conn, error) {
+	c, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	return &tlsConn{Conn: c, t: l.t, addr: c.RemoteAddr().String()}, nil
+}
+
+// maxClientHelloSize limits the bytes of a connection
+// which are buffered until the ClientHello is parsed.
+const maxClientHelloSize = 16*1024 + 5
+
+type tlsConn struct {
+	net.Conn
+	t    *TLS
+	addr string
+
+	buf  []byte
+	done bool
+}
+
+func (c *tlsConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	if !c.done && n > 0 {
+		c.buf = append(c.buf, b[:n]...)
+		if hello, complete := readClientHello(c.buf); complete || len(c.buf) >= maxClientHelloSize {
+			c.done = true
+			c.buf = nil
+			if hello != "" {
+				c.t.hashes.Store(c.addr, hello)
+			}
+		}
+	}
+
+	return n, err
+}
+
+func (c *tlsConn) Close() error {
+	c.t.hashes.Delete(c.addr)
+	return c.Conn.Close()
+}
+
+// readClientHello parses the first TLS record and returns the JA3 hash of its ClientHello.
+// The "complete" output reports whether the parsing is finished, successfully or not.
+func readClientHello(b []byte) (string, bool) {
+	const (
+		recordHeaderLen     = 5
+		recordTypeHandshake = 0x16
+		typeClientHello     = 0x01
+	)
+
+	if len(b) < recordHeaderLen {
+		return "", false
+	}
+
+	if b[0] != recordTypeHandshake {
+		return "", true
+	}
+
+	n := int(binary.BigEndian.Uint16(b[3:5]))
+	if len(b) < recordHeaderLen+n {
+		return "", false
+	}
+
+	s := helloReader(b[recordHeaderLen : recordHeaderLen+n])
+	// handshake type (1), length (3), client version (2), random (32).
+	msgType, _ := s.uint8()
+	if msgType != typeClientHello || !s.skip(3) {
+		return "", true
+	}
+
+	version, ok := s.uint16()
+	if !ok || !s.skip(32) {
+		return "", true
+	}
+
+	sessionID, ok := s.vector8()
+	if !ok || len(sessionID) > 32 {
+		return "", true
+	}
+
+	cipherSuites, ok := s.vector16()
+	if !ok {
+		return "", true
+	}
+
+	if _, ok = s.vector8(); !ok { // compression methods.
+		return "", true
+	}
+
+	var extensions, curves, points []uint16
+	if exts, ok := s.vector16(); ok {
+		for len(exts) > 0 {
+			typ, ok := exts.uint16()
+			if !ok {
+				return "", true
+			}
+
+			data, ok := exts.vector16()
+			if !ok {
+				return "", true
+			}
+
+			if isGREASE(typ) {
+				continue
+			}
+
+			extensions = append(extensions, typ)
+			switch typ {
+			case 10: // supported_groups.
+				list, _ := data.vector16()
+				curves = list.uint16s()
+			case 11: // ec_point_formats.
+				list, _ := data.vector8()
+				for _, p := range list {
+					points = append(points, uint16(p))
+				}
+			}
+		}
+	}
+
+	ja3 := strings.Join([]string{
+		strconv.Itoa(int(version)),
+		joinUint16(cipherSuites.uint16s()),
+		joinUint16(extensions),
+		joinUint16(curves),
+		joinUint16(points),
+	}, ",")
+
+	sum := md5.Sum([]byte(ja3)) // #nosec G401
+	return hex.EncodeToString(sum[:]), true
+}
+
+// isGREASE reports whether "v" is a GREASE value (RFC 8701), ignored by JA3.
+func isGREASE(v uint16) bool {
+	return v&0x0f0f == 0x0a0a && v>>8 == v&0xff
+}
+
+func joinUint16(values []uint16) string {
+	var b strings.Builder
+	for _, v := range values {
+		if isGREASE(v) {
+			continue
+		}
+
+		if b.Len() > 0 {
+			b.WriteByte('-')
+		}
+		b.WriteString(strconv.Itoa(int(v)))
+	}
+
+	return b.String()
+}
+
+type helloReader []byte
+
+func (s *helloReader) skip(n int) bool {
+	if len(*s) < n {
+		return false
+	}
+
+	*s = (*s)[n:]
+	return true
+}
+
+func (s *helloReader) uint8() (uint8, bool) {
+	if len(*s) < 1 {
+		return 0, false
+	}
+
+	v := (*s)[0]
+	*s = (*s)[1:]
+	return v, true
+}
+
+func (s *helloReader) uint16() (uint16, bool) {
+	if len(*s) < 2 {
+		return 0, false
+	}
+
+	v := binary.BigEndian.Uint16(*s)
+	*s = (*s)[2:]
+	return v, true
+}
+
+func (s *helloReader) vector8() (helloReader, bool) {
+	n, ok := s.uint8()
+	if !ok || len(*s) < int(n) {
+		return nil, false
+	}
+
+	v := (*s)[:n]
+	*s = (*s)[n:]
+	return v, true
+}
+
+func (s *helloReader) vector16() (helloReader, bool) {
+	n, ok := s.uint16()
+	if !ok || len(*s) < int(n) {
+		return nil, false
+	}
+
+	v := (*s)[:n]
+	*s = (*s)[n:]
+	return v, true
+}
+
+func (s helloReader) uint16s() []uint16 {
+	values := make([]uint16, 0, len(s)/2)
+	for len(s) >= 2 {
+		values = append(values, binary.BigEndian.Uint16(s))
+		s = s[2:]
+	}
+
+	return values
+}
@@ -0,0 +1,118 @@
+// Package clienthints advertises the Client Hints the server is interested in
+// and exposes the received ones to the handlers and the templates,
+// for responsive content decisions on the server side,
+// see https://developer.mozilla.org/en-US/docs/Web/HTTP/Client_hints.
+package clienthints
+
+import (
+	"strings"
+
+	"github.com/kataras/iris/v12/context"
+)
+
+func init() {
+	context.SetHandlerName("iris/middleware/clienthints.*", "iris.clienthints")
+}
+
+// DefaultViewDataKey is the default `Options.ViewDataKey`.
+const DefaultViewDataKey = "ClientHints"
+
+var (
+	// Responsive is the preset of the hints to select the images and the layout
+	// which fit the client's screen.
+	Responsive = []string{
+		context.ClientHintUAMobile,
+		context.ClientHintViewportWidth,
+		context.ClientHintWidth,
+		context.ClientHintDPR,
+	}
+	// UserAgent is the preset of the high entropy user agent hints,
+	// in addition to the ones sent by default (Sec-CH-UA, Sec-CH-UA-Mobile and Sec-CH-UA-Platform).
+	UserAgent = []string{
+		context.ClientHintUAPlatformVersion,
+		context.ClientHintUAModel,
+		context.ClientHintUAArch,
+		context.ClientHintUAFullVersionList,
+	}
+	// Network is the preset of the hints which describe the client's connection.
+	Network = []string{
+		context.ClientHintSaveData,
+		context.ClientHintECT,
+		context.ClientHintRTT,
+		context.ClientHintDownlink,
+	}
+)
+
+// Options holds the configuration of a `New` handler.
+type Options struct {
+	// Hints is the list of the Client Hints header keys to ask the client for,
+	// sent through the "Accept-CH" response header, see the `Responsive`,
+	// `UserAgent` and `Network` presets.
+	Hints []string `json:"hints" yaml:"Hints" toml:"Hints"`
+	// Critical is the list of the hints which affect the response
+	// so much that the client should retry the request with them,
+	// if it did not send them already, through the "Critical-CH" response header.
+	// The critical hints are asked too, even if they are missing from the Hints field.
+	Critical []string `json:"critical,omitempty" yaml:"Critical" toml:"Critical"`
+	// ViewDataKey is the view data key of the request's `context.ClientHints`,
+	// to make responsive decisions inside the templates, e.g.
+	//  {{ if .ClientHints.Mobile }} [...] {{ end }}
+	//  <img src="/hero-{{ .ClientHints.ImageWidth 320 640 1280 }}.webp">
+	// Defaults to "ClientHints", set it to "-" to not set the view data.
+	ViewDataKey string `json:"viewDataKey,omitempty" yaml:"ViewDataKey" toml:"ViewDataKey"`
+}
+
+// New returns a new handler which advertises the Client Hints of the "opts"
+// and sets the received ones to the view data.
+// The responses vary by the advertised hints, so caches store a response per hints' values.
+// The handlers can read the hints through the `Context.ClientHints` method.
+//
+// Usage:
+//  app.Use(clienthints.New(clienthints.Options{
+//      Hints:    append(clienthints.Responsive, clienthints.Network...),
+//      Critical: []string{context.ClientHintDPR},
+//  }))
+//  app.Get("/", func(ctx iris.Context) {
+//      width := ctx.ClientHints().ImageWidth(320, 640, 1280)
+//      [...]
+//  })
+func New(opts Options) context.Handler {
+	hints := make([]string, 0, len(opts.Hints)+len(opts.Critical))
+	seen := make(map[string]struct{})
+	for _, list := range [][]string{opts.Hints, opts.Critical} {
+		for _, hint := range list {
+			key := strings.ToLower(hint)
+			if _, ok := seen[key]; ok || hint == "" {
+				continue
+			}
+
+			seen[key] = struct{}{}
+			hints = append(hints, hint)
+		}
+	}
+
+	acceptCH := strings.Join(hints, ", ")
+	criticalCH := strings.Join(opts.Critical, ", ")
+
+	viewDataKey := opts.ViewDataKey
+	if viewDataKey == "" {
+		viewDataKey = DefaultViewDataKey
+	}
+
+	return func(ctx *context.Context) {
+		if acceptCH != "" {
+			h := ctx.ResponseWriter().Header()
+			h.Set(context.AcceptCHHeaderKey, acceptCH)
+			h.Add(context.VaryHeaderKey, acceptCH)
+			if criticalCH != "" {
+				h.Set(context.CriticalCHHeaderKey, criticalCH)
+			}
+		}
+
+		if viewDataKey != "-" {
+			ctx.ViewData(viewDataKey, ctx.ClientHints())
+		}
+
+		ctx.Next()
+	}
+}
@@ -0,0 +1,36 @@
+package clienthints_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/httptest"
+	"github.com/kataras/iris/v12/middleware/clienthints"
+)
+
+func TestClientHints(t *testing.T) {
+	app := iris.New()
+	app.RegisterView(iris.HTML(fstest.MapFS{
+		"index.html": {Data: []byte(`{{ if .ClientHints.Mobile }}mobile{{ else }}desktop{{ end }}:{{ .ClientHints.ImageWidth 320 640 1280 }}`)},
+	}, ".html"))
+	app.Use(clienthints.New(clienthints.Options{
+		Hints:    clienthints.Responsive,
+		Critical: []string{context.ClientHintDPR, context.ClientHintSaveData},
+	}))
+	app.Get("/", func(ctx iris.Context) {
+		ctx.View("index.html")
+	})
+
+	e := httptest.New(t, app)
+	resp := e.GET("/").Expect().Status(httptest.StatusOK)
+	resp.Header(context.AcceptCHHeaderKey).Equal("Sec-CH-UA-Mobile, Sec-CH-Viewport-Width, Sec-CH-Width, Sec-CH-DPR, Save-Data")
+	resp.Header(context.CriticalCHHeaderKey).Equal("Sec-CH-DPR, Save-Data")
+	resp.Header(context.VaryHeaderKey).Equal("Sec-CH-UA-Mobile, Sec-CH-Viewport-Width, Sec-CH-Width, Sec-CH-DPR, Save-Data")
+	resp.Body().Equal("desktop:1280")
+
+	e.GET("/").WithHeader(context.ClientHintUAMobile, "?1").
+		WithHeader(context.ClientHintViewportWidth, "400").WithHeader(context.ClientHintDPR, "1.5").
+		Expect().Status(httptest.StatusOK).Body().Equal("mobile:640")
+}
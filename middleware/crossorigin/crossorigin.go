@@ -0,0 +1,142 @@
+// Package crossorigin sets the Cross-Origin-Resource-Policy (CORP),
+// Cross-Origin-Embedder-Policy (COEP) and Cross-Origin-Opener-Policy (COOP) response headers.
+// Pages which use SharedArrayBuffer or high resolution timers
+// should be cross-origin isolated, that requires a coordinated set of these headers,
+// see the `Isolated` and `EmbeddableAssets` presets.
+package crossorigin
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kataras/iris/v12/context"
+)
+
+func init() {
+	context.SetHandlerName("iris/middleware/crossorigin.*", "iris.crossorigin")
+}
+
+// The header keys.
+const (
+	ResourcePolicyHeaderKey           = "Cross-Origin-Resource-Policy"
+	EmbedderPolicyHeaderKey           = "Cross-Origin-Embedder-Policy"
+	EmbedderPolicyReportOnlyHeaderKey = "Cross-Origin-Embedder-Policy-Report-Only"
+	OpenerPolicyHeaderKey             = "Cross-Origin-Opener-Policy"
+	OpenerPolicyReportOnlyHeaderKey   = "Cross-Origin-Opener-Policy-Report-Only"
+)
+
+// Policy holds the cross-origin policies of a `New` handler.
+// Empty fields are not sent.
+type Policy struct {
+	// ResourcePolicy is the Cross-Origin-Resource-Policy header value,
+	// which origins can load the resources:
+	// "same-origin", "same-site" or "cross-origin".
+	ResourcePolicy string `json:"resourcePolicy,omitempty" yaml:"ResourcePolicy" toml:"ResourcePolicy"`
+	// EmbedderPolicy is the Cross-Origin-Embedder-Policy header value,
+	// which cross-origin resources the document can load:
+	// "require-corp", "credentialless" or "unsafe-none".
+	EmbedderPolicy string `json:"embedderPolicy,omitempty" yaml:"EmbedderPolicy" toml:"EmbedderPolicy"`
+	// OpenerPolicy is the Cross-Origin-Opener-Policy header value,
+	// whether the document shares its browsing context group with cross-origin documents:
+	// "same-origin", "same-origin-allow-popups" or "unsafe-none".
+	OpenerPolicy string `json:"openerPolicy,omitempty" yaml:"OpenerPolicy" toml:"OpenerPolicy"`
+	// ReportTo is the optional reporting endpoint name (see the Reporting-Endpoints header)
+	// of the embedder and opener policies violations.
+	ReportTo string `json:"reportTo,omitempty" yaml:"ReportTo" toml:"ReportTo"`
+	// ReportOnly sends the embedder and opener policies as "Report-Only" headers,
+	// the violations are reported but not enforced.
+	// Useful to roll out the policies safely.
+	ReportOnly bool `json:"reportOnly,omitempty" yaml:"ReportOnly" toml:"ReportOnly"`
+}
+
+var (
+	// Isolated is the preset of a cross-origin isolated document,
+	// required to use SharedArrayBuffer and high resolution timers.
+	// Its cross-origin resources should be served with the `EmbeddableAssets` preset (or through CORS).
+	Isolated = Policy{
+		ResourcePolicy: "same-origin",
+		EmbedderPolicy: "require-corp",
+		OpenerPolicy:   "same-origin",
+	}
+	// EmbeddableAssets is the preset of resources (scripts, images, fonts and e.t.c.)
+	// which are embedded by cross-origin isolated documents of other origins.
+	EmbeddableAssets = Policy{
+		ResourcePolicy: "cross-origin",
+	}
+	// SameSite is the preset of resources which are loaded only by the same site.
+	SameSite = Policy{
+		ResourcePolicy: "same-site",
+	}
+
+	// Presets holds the available presets by name,
+	// e.g. to select a preset through a configuration file,
+	// see `NewPreset`.
+	Presets = map[string]Policy{
+		"isolated":          Isolated,
+		"embeddable-assets": EmbeddableAssets,
+		"same-site":         SameSite,
+	}
+)
+
+// New returns a new handler which sets the "policy" headers to the responses.
+// Register it on a Party to apply the policy to all of its routes,
+// a child Party's policy overrides its parent's one.
+//
+// Usage:
+//  app.Use(crossorigin.New(crossorigin.Isolated))
+//  assets := app.Party("/assets")
+//  assets.Use(crossorigin.New(crossorigin.EmbeddableAssets))
+func New(policy Policy) context.Handler {
+	embedderKey, openerKey := EmbedderPolicyHeaderKey, OpenerPolicyHeaderKey
+	if policy.ReportOnly {
+		embedderKey, openerKey = EmbedderPolicyReportOnlyHeaderKey, OpenerPolicyReportOnlyHeaderKey
+	}
+
+	headers := make(map[string]string)
+	if v := policy.ResourcePolicy; v != "" {
+		headers[ResourcePolicyHeaderKey] = v
+	}
+
+	if v := policy.EmbedderPolicy; v != "" {
+		headers[embedderKey] = withReportTo(v, policy.ReportTo)
+	}
+
+	if v := policy.OpenerPolicy; v != "" {
+		headers[openerKey] = withReportTo(v, policy.ReportTo)
+	}
+
+	return func(ctx *context.Context) {
+		h := ctx.ResponseWriter().Header()
+		for key, value := range headers {
+			h.Set(key, value)
+		}
+
+		ctx.Next()
+	}
+}
+
+// NewPreset returns a new handler of the preset registered with the given "name",
+// see `Presets`. It panics if the preset does not exist.
+func NewPreset(name string) context.Handler {
+	policy, ok := Presets[strings.ToLower(name)]
+	if !ok {
+		names := make([]string, 0, len(Presets))
+		for name := range Presets {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		panic(fmt.Sprintf("crossorigin: unknown preset: %q, available presets: %s", name, strings.Join(names, ", ")))
+	}
+
+	return New(policy)
+}
+
+func withReportTo(value, reportTo string) string {
+	if reportTo == "" {
+		return value
+	}
+
+	return value + `; report-to="` + reportTo + `"`
+}
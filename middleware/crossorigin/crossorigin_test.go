@@ -0,0 +1,52 @@
+package crossorigin_test
+
+import (
+	"testing"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/httptest"
+	"github.com/kataras/iris/v12/middleware/crossorigin"
+)
+
+func TestCrossOrigin(t *testing.T) {
+	app := iris.New()
+	app.Use(crossorigin.NewPreset("isolated"))
+	app.Get("/", func(ctx iris.Context) {
+		ctx.WriteString("index")
+	})
+
+	assets := app.Party("/assets")
+	assets.Use(crossorigin.New(crossorigin.EmbeddableAssets))
+	assets.Get("/app.js", func(ctx iris.Context) {
+		ctx.WriteString("app")
+	})
+
+	reports := app.Party("/staging")
+	reports.Use(crossorigin.New(crossorigin.Policy{
+		EmbedderPolicy: "require-corp",
+		OpenerPolicy:   "same-origin",
+		ReportTo:       "coep",
+		ReportOnly:     true,
+	}))
+	reports.Get("/", func(ctx iris.Context) {})
+
+	e := httptest.New(t, app)
+	resp := e.GET("/").Expect().Status(httptest.StatusOK)
+	resp.Header(crossorigin.ResourcePolicyHeaderKey).Equal("same-origin")
+	resp.Header(crossorigin.EmbedderPolicyHeaderKey).Equal("require-corp")
+	resp.Header(crossorigin.OpenerPolicyHeaderKey).Equal("same-origin")
+
+	resp = e.GET("/assets/app.js").Expect().Status(httptest.StatusOK)
+	resp.Header(crossorigin.ResourcePolicyHeaderKey).Equal("cross-origin")
+
+	resp = e.GET("/staging").Expect().Status(httptest.StatusOK)
+	resp.Header(crossorigin.EmbedderPolicyReportOnlyHeaderKey).Equal(`require-corp; report-to="coep"`)
+	resp.Header(crossorigin.OpenerPolicyReportOnlyHeaderKey).Equal(`same-origin; report-to="coep"`)
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected a panic on unknown preset")
+		}
+	}()
+	crossorigin.NewPreset("unknown")
+}
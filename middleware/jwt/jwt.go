@@ -4,4 +4,5 @@ import "github.com/kataras/iris/v12/context"
 
 func init() {
 	context.SetHandlerName("iris/middleware/jwt.*", "iris.jwt")
+	context.SetHandlerPolicy("iris.jwt", context.PolicyAuth)
 }
@@ -0,0 +1,372 @@
+// Package objectstore implements a streaming proxy for large file uploads.
+// The multipart file parts of the incoming request are directly streamed,
+// chunk by chunk, to an S3-compatible object storage through its multipart upload API,
+// without buffering the whole file in memory or writing temporary files on disk.
+package objectstore
+
+import (
+	"bytes"
+	stdContext "context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"mime/multipart"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/kataras/iris/v12/context"
+
+	"github.com/google/uuid"
+)
+
+func init() {
+	context.SetHandlerName("iris/middleware/objectstore.*", "iris.objectstore")
+}
+
+// MinPartSize is the minimum size of a part (except the last one)
+// that S3-compatible storages accept, 5 MiB.
+const MinPartSize = 5 << 20
+
+type (
+	// Storage describes the multipart upload API of an S3-compatible object storage.
+	// Implementations are usually thin wrappers of the official SDKs' clients.
+	Storage interface {
+		// CreateMultipartUpload initiates a multipart upload and returns its upload ID.
+		CreateMultipartUpload(ctx stdContext.Context, key, contentType string) (uploadID string, err error)
+		// UploadPart uploads a single part of the object. The "partNumber" starts from 1.
+		// Returns the ETag of the uploaded part.
+		UploadPart(ctx stdContext.Context, key, uploadID string, partNumber int, body io.ReadSeeker, size int64) (etag string, err error)
+		// CompleteMultipartUpload assembles the previously uploaded parts.
+		CompleteMultipartUpload(ctx stdContext.Context, key, uploadID string, parts []Part) error
+		// AbortMultipartUpload aborts the multipart upload
+		// and frees the storage consumed by any previously uploaded parts.
+		AbortMultipartUpload(ctx stdContext.Context, key, uploadID string) error
+	}
+
+	// Part holds the information of an uploaded part.
+	Part struct {
+		Number int    `json:"number"`
+		ETag   string `json:"etag"`
+		Size   int64  `json:"size"`
+	}
+
+	// Progress holds the progress information of an upload,
+	// see `Options.OnProgress`.
+	Progress struct {
+		FormName string
+		FileName string
+		Key      string
+		// Bytes is the total number of bytes uploaded so far.
+		Bytes int64
+		// Parts is the number of parts uploaded so far.
+		Parts int
+	}
+
+	// Result holds the information of a completed upload.
+	// See `Get` package-level function.
+	Result struct {
+		FormName    string `json:"formName"`
+		FileName    string `json:"fileName"`
+		Key         string `json:"key"`
+		ContentType string `json:"contentType"`
+		UploadID    string `json:"uploadID"`
+		Size        int64  `json:"size"`
+		// Checksum is the hex-encoded checksum of the whole file,
+		// computed through the `Options.Hash`.
+		Checksum string `json:"checksum"`
+		Parts    []Part `json:"parts"`
+	}
+
+	// Options holds the optional settings for the `New` and `Upload` package-level functions.
+	Options struct {
+		// PartSize is the size of each part, except the last one.
+		// Defaults to and cannot be less than `MinPartSize`.
+		PartSize int64
+		// MaxFiles sets a limit of the files of a single request.
+		// Defaults to zero, no limit.
+		MaxFiles int
+		// Key should return the object's key of a multipart file part.
+		// Defaults to the `RandomKey`, the client cannot choose the key.
+		// Use the `FileNameKey` to keep the client's file name instead.
+		Key func(ctx *context.Context, part *multipart.Part) string
+		// Hash returns a new hash used to compute the checksum of the whole file.
+		// Defaults to sha256.New.
+		Hash func() hash.Hash
+		// ExpectedChecksum can optionally return the expected hex-encoded checksum of a file part,
+		// e.g. from a request header or a previous form field.
+		// If it is not empty and it does not match the computed one
+		// then the upload is aborted and the `ErrChecksumMismatch` is returned.
+		ExpectedChecksum func(ctx *context.Context, part *multipart.Part) string
+		// OnProgress, if not nil, it is fired after each uploaded part.
+		OnProgress func(ctx *context.Context, p Progress)
+		// OnError, if not nil, it is fired on upload failures, instead of
+		// the default behavior of stopping the handlers chain with 400 or 500 status codes.
+		OnError func(ctx *context.Context, err error)
+	}
+)
+
+var (
+	// ErrChecksumMismatch is returned when the computed checksum of a file
+	// does not match the `Options.ExpectedChecksum`.
+	ErrChecksumMismatch = errors.New("objectstore: checksum mismatch")
+	// ErrTooManyFiles is returned when the request contains more than `Options.MaxFiles` files.
+	ErrTooManyFiles = errors.New("objectstore: too many files")
+	// ErrNotMultipart is returned when the request is not a multipart one.
+	ErrNotMultipart = errors.New("objectstore: request is not multipart/form-data")
+)
+
+const resultsContextKey = "iris.objectstore.results"
+
+// New returns a new handler which streams the request's file parts
+// to the "storage" and fires the next handler on success.
+// The next handlers can read the uploaded files' information
+// through the `Get` package-level function.
+//
+// Note that the request body is consumed, so any non-file form fields
+// are not available to the next handlers.
+//
+// Usage:
+//  app.Post("/upload", objectstore.New(myS3Storage, objectstore.Options{
+//   OnProgress: func(ctx iris.Context, p objectstore.Progress) {...},
+//  }), func(ctx iris.Context) {
+//   ctx.JSON(objectstore.Get(ctx))
+//  })
+func New(storage Storage, opts Options) context.Handler {
+	return func(ctx *context.Context) {
+		results, err := Upload(ctx, storage, opts)
+		if err != nil {
+			if opts.OnError != nil {
+				opts.OnError(ctx, err)
+				return
+			}
+
+			statusCode := 500
+			switch err {
+			case ErrChecksumMismatch, ErrTooManyFiles, ErrNotMultipart:
+				statusCode = 400
+			}
+
+			ctx.StopWithError(statusCode, err)
+			return
+		}
+
+		ctx.Values().Set(resultsContextKey, results)
+		ctx.Next()
+	}
+}
+
+// Get returns the uploaded files' information
+// of the `New` handler. Returns nil if no files were uploaded.
+func Get(ctx *context.Context) []Result {
+	if v := ctx.Values().Get(resultsContextKey); v != nil {
+		if results, ok := v.([]Result); ok {
+			return results
+		}
+	}
+
+	return nil
+}
+
+// RandomKey is the default `Options.Key`.
+// It returns a random, server-generated, object key
+// with the lowercase extension of the part's file name, e.g. "$uuid.png".
+func RandomKey(_ *context.Context, part *multipart.Part) string {
+	return uuid.New().String() + fileExt(part.FileName())
+}
+
+// FileNameKey is an `Options.Key` which returns the base name of the part's file name,
+// without any directories and leading dots, e.g. "../../avatar.png" results to "avatar.png".
+// It falls back to the `RandomKey` if the file name is empty after the sanitization.
+// Note that a client can overwrite any object of the same name,
+// so it should be used on trusted clients or under a per-client prefix.
+//
+// Usage:
+//  Key: func(ctx iris.Context, part *multipart.Part) string {
+//      return userID(ctx) + "/" + objectstore.FileNameKey(ctx, part)
+//  }
+func FileNameKey(ctx *context.Context, part *multipart.Part) string {
+	name := path.Base(strings.ReplaceAll(part.FileName(), "\\", "/"))
+	name = strings.TrimLeft(name, ".")
+	if name == "" || name == "/" {
+		return RandomKey(ctx, part)
+	}
+
+	return name
+}
+
+// fileExt returns the lowercase extension of the "filename"
+// if it contains only letters and digits, otherwise empty.
+func fileExt(filename string) string {
+	ext := strings.ToLower(path.Ext(strings.ReplaceAll(filename, "\\", "/")))
+	if len(ext) < 2 || len(ext) > 16 {
+		return ""
+	}
+
+	for _, r := range ext[1:] {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') {
+			return ""
+		}
+	}
+
+	return ext
+}
+
+var bufPool = sync.Pool{}
+
+func acquireBuffer(size int64) *[]byte {
+	if v := bufPool.Get(); v != nil {
+		if b := v.(*[]byte); int64(cap(*b)) >= size {
+			*b = (*b)[:size]
+			return b
+		}
+	}
+
+	b := make([]byte, size)
+	return &b
+}
+
+// Upload streams all file parts of the request to the "storage".
+// If the client disconnects or any error occurs
+// the in-progress multipart upload is aborted.
+func Upload(ctx *context.Context, storage Storage, opts Options) ([]Result, error) {
+	if opts.PartSize < MinPartSize {
+		opts.PartSize = MinPartSize
+	}
+
+	if opts.Hash == nil {
+		opts.Hash = sha256.New
+	}
+
+	reader, err := ctx.Request().MultipartReader()
+	if err != nil {
+		return nil, ErrNotMultipart
+	}
+
+	if opts.Key == nil {
+		opts.Key = RandomKey
+	}
+
+	bufPtr := acquireBuffer(opts.PartSize)
+	defer bufPool.Put(bufPtr)
+	buf := *bufPtr
+
+	var results []Result
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+
+			return results, err
+		}
+
+		if part.FileName() == "" { // skip non-file fields.
+			part.Close()
+			continue
+		}
+
+		if opts.MaxFiles > 0 && len(results) >= opts.MaxFiles {
+			part.Close()
+			return results, ErrTooManyFiles
+		}
+
+		result, err := uploadPart(ctx, storage, opts, part, buf)
+		part.Close()
+		if err != nil {
+			return results, err
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+func uploadPart(ctx *context.Context, storage Storage, opts Options, part *multipart.Part, buf []byte) (Result, error) {
+	reqCtx := ctx.Request().Context()
+
+	key := opts.Key(ctx, part)
+
+	contentType := part.Header.Get(context.ContentTypeHeaderKey)
+	if contentType == "" {
+		contentType = context.ContentBinaryHeaderValue
+	}
+
+	result := Result{
+		FormName:    part.FormName(),
+		FileName:    part.FileName(),
+		Key:         key,
+		ContentType: contentType,
+	}
+
+	uploadID, err := storage.CreateMultipartUpload(reqCtx, key, contentType)
+	if err != nil {
+		return result, err
+	}
+	result.UploadID = uploadID
+
+	abort := func(err error) (Result, error) {
+		// The request's context may be canceled already (client disconnected),
+		// so use a fresh one to clean up the uploaded parts.
+		if abortErr := storage.AbortMultipartUpload(stdContext.Background(), key, uploadID); abortErr != nil {
+			err = fmt.Errorf("%w: abort: %v", err, abortErr)
+		}
+
+		return result, err
+	}
+
+	h := opts.Hash()
+	for partNumber := 1; ; partNumber++ {
+		n, readErr := io.ReadFull(part, buf)
+		if n > 0 || partNumber == 1 { // an empty file still needs a single part.
+			if err = reqCtx.Err(); err != nil { // client disconnected.
+				return abort(err)
+			}
+
+			h.Write(buf[:n])
+			etag, err := storage.UploadPart(reqCtx, key, uploadID, partNumber, bytes.NewReader(buf[:n]), int64(n))
+			if err != nil {
+				return abort(err)
+			}
+
+			result.Size += int64(n)
+			result.Parts = append(result.Parts, Part{Number: partNumber, ETag: etag, Size: int64(n)})
+
+			if opts.OnProgress != nil {
+				opts.OnProgress(ctx, Progress{
+					FormName: result.FormName,
+					FileName: result.FileName,
+					Key:      key,
+					Bytes:    result.Size,
+					Parts:    len(result.Parts),
+				})
+			}
+		}
+
+		if readErr != nil {
+			if readErr == io.EOF || readErr == io.ErrUnexpectedEOF {
+				break
+			}
+
+			return abort(readErr)
+		}
+	}
+
+	result.Checksum = hex.EncodeToString(h.Sum(nil))
+	if opts.ExpectedChecksum != nil {
+		if expected := opts.ExpectedChecksum(ctx, part); expected != "" && !strings.EqualFold(expected, result.Checksum) {
+			return abort(ErrChecksumMismatch)
+		}
+	}
+
+	if err = storage.CompleteMultipartUpload(reqCtx, key, uploadID, result.Parts); err != nil {
+		return abort(err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,147 @@
+package objectstore_test
+
+import (
+	"bytes"
+	stdContext "context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/httptest"
+	"github.com/kataras/iris/v12/middleware/objectstore"
+)
+
+type memStorage struct {
+	mu      sync.Mutex
+	parts   map[string][][]byte
+	objects map[string][]byte
+	aborted []string
+}
+
+func newMemStorage() *memStorage {
+	return &memStorage{
+		parts:   make(map[string][][]byte),
+		objects: make(map[string][]byte),
+	}
+}
+
+func (s *memStorage) CreateMultipartUpload(ctx stdContext.Context, key, contentType string) (string, error) {
+	return "upload-" + key, nil
+}
+
+func (s *memStorage) UploadPart(ctx stdContext.Context, key, uploadID string, partNumber int, body io.ReadSeeker, size int64) (string, error) {
+	b, err := ioutil.ReadAll(body)
+	if err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	s.parts[uploadID] = append(s.parts[uploadID], b)
+	s.mu.Unlock()
+	return fmt.Sprintf("etag-%d", partNumber), nil
+}
+
+func (s *memStorage) CompleteMultipartUpload(ctx stdContext.Context, key, uploadID string, parts []objectstore.Part) error {
+	s.mu.Lock()
+	s.objects[key] = bytes.Join(s.parts[uploadID], nil)
+	delete(s.parts, uploadID)
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *memStorage) AbortMultipartUpload(ctx stdContext.Context, key, uploadID string) error {
+	s.mu.Lock()
+	delete(s.parts, uploadID)
+	s.aborted = append(s.aborted, key)
+	s.mu.Unlock()
+	return nil
+}
+
+func TestUpload(t *testing.T) {
+	storage := newMemStorage()
+	content := bytes.Repeat([]byte("a"), objectstore.MinPartSize+10)
+	sum := sha256.Sum256(content)
+	checksum := hex.EncodeToString(sum[:])
+
+	var progress []int64
+	app := iris.New()
+	app.Post("/upload", objectstore.New(storage, objectstore.Options{
+		ExpectedChecksum: func(ctx iris.Context, part *multipart.Part) string {
+			return ctx.GetHeader("X-Checksum")
+		},
+		OnProgress: func(ctx iris.Context, p objectstore.Progress) {
+			progress = append(progress, p.Bytes)
+		},
+	}), func(ctx iris.Context) {
+		results := objectstore.Get(ctx)
+		ctx.Writef("%s:%d:%d:%s", results[0].Key, results[0].Size, len(results[0].Parts), results[0].Checksum)
+	})
+
+	body := new(bytes.Buffer)
+	w := multipart.NewWriter(body)
+	fw, _ := w.CreateFormFile("file", "big.txt")
+	fw.Write(content)
+	w.Close()
+
+	e := httptest.New(t, app)
+	got := e.POST("/upload").WithHeader("X-Checksum", checksum).
+		WithBytes(body.Bytes()).WithHeader("Content-Type", w.FormDataContentType()).
+		Expect().Status(httptest.StatusOK).Body().Raw()
+
+	// The key is generated by the server, the client's file name is not used.
+	key := strings.SplitN(got, ":", 2)[0]
+	if key == "big.txt" || !strings.HasSuffix(key, ".txt") {
+		t.Fatalf("expected a random key with the .txt extension but got: %s", key)
+	}
+	if expected := fmt.Sprintf("%s:%d:2:%s", key, len(content), checksum); got != expected {
+		t.Fatalf("expected: %s but got: %s", expected, got)
+	}
+
+	if !bytes.Equal(storage.objects[key], content) {
+		t.Fatalf("stored object does not match the uploaded content")
+	}
+
+	if expected := []int64{objectstore.MinPartSize, int64(len(content))}; fmt.Sprint(progress) != fmt.Sprint(expected) {
+		t.Fatalf("expected progress: %v but got: %v", expected, progress)
+	}
+
+	e.POST("/upload").WithHeader("X-Checksum", "invalid").
+		WithBytes(body.Bytes()).WithHeader("Content-Type", w.FormDataContentType()).
+		Expect().Status(httptest.StatusBadRequest)
+
+	if len(storage.aborted) != 1 {
+		t.Fatalf("expected the upload to be aborted on checksum mismatch")
+	}
+}
+
+func TestUploadFileNameKey(t *testing.T) {
+	storage := newMemStorage()
+
+	app := iris.New()
+	app.Post("/upload", objectstore.New(storage, objectstore.Options{Key: objectstore.FileNameKey}), func(ctx iris.Context) {
+		ctx.WriteString(objectstore.Get(ctx)[0].Key)
+	})
+
+	e := httptest.New(t, app)
+	for fileName, expected := range map[string]string{
+		"avatar.png":       "avatar.png",
+		"../../etc/passwd": "passwd",
+		`..\secrets\.env`:  "env",
+	} {
+		body := new(bytes.Buffer)
+		w := multipart.NewWriter(body)
+		fw, _ := w.CreateFormFile("file", fileName)
+		fw.Write([]byte("data"))
+		w.Close()
+
+		e.POST("/upload").WithBytes(body.Bytes()).WithHeader("Content-Type", w.FormDataContentType()).
+			Expect().Status(httptest.StatusOK).Body().Equal(expected)
+	}
+}
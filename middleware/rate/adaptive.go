@@ -0,0 +1,265 @@
+package rate
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/kataras/iris/v12/context"
+)
+
+// Algorithm is the type of the available adaptive concurrency limit algorithms.
+// See `AdaptiveOptions.Algorithm` field.
+type Algorithm uint8
+
+const (
+	// AIMD is the additive-increase/multiplicative-decrease algorithm.
+	// The limit is increased by one on each successful request
+	// and it is multiplied by the `AdaptiveOptions.Backoff` ratio
+	// when a request fails (5xx status code) or
+	// its latency exceeds the `AdaptiveOptions.LatencyThreshold`.
+	AIMD Algorithm = iota
+	// Gradient adjusts the limit based on the gradient between the long-term (no load)
+	// and the current latency. When the latency starts growing the limit is decreased,
+	// before the downstream dependencies are saturated.
+	Gradient
+)
+
+// AdaptiveOptions holds the settings for the `Adaptive` and `NewAdaptive` package-level functions.
+type AdaptiveOptions struct {
+	// Algorithm to adjust the limit, defaults to AIMD.
+	Algorithm Algorithm
+	// InitialLimit is the number of the allowed concurrent requests on start.
+	// Defaults to 20.
+	InitialLimit int
+	// MinLimit is the minimum number of the allowed concurrent requests.
+	// Defaults to 1.
+	MinLimit int
+	// MaxLimit is the maximum number of the allowed concurrent requests.
+	// Defaults to 1000.
+	MaxLimit int
+
+	// LatencyThreshold is used by the AIMD algorithm,
+	// a request which takes longer is treated as a failure.
+	// Defaults to 1 second.
+	LatencyThreshold time.Duration
+	// Backoff is used by the AIMD algorithm,
+	// the ratio the limit is multiplied by on failures.
+	// Defaults to 0.9.
+	Backoff float64
+
+	// Tolerance is used by the Gradient algorithm,
+	// the ratio of the latency growth that is tolerated before decreasing the limit.
+	// Defaults to 1.5.
+	Tolerance float64
+	// Smoothing is used by the Gradient algorithm,
+	// the weight of a new limit against the current one, from 0 to 1.
+	// Defaults to 0.2.
+	Smoothing float64
+	// LongWindow is used by the Gradient algorithm,
+	// the number of samples of the long-term latency average.
+	// Defaults to 600.
+	LongWindow int
+
+	// ExceedHandler is fired when the limit is reached.
+	// Defaults to a 429 Too Many Requests status code.
+	ExceedHandler context.Handler
+	// OnLimitChange, if not nil, it is fired each time the limit is changed.
+	OnLimitChange func(previous, limit int)
+}
+
+// AdaptiveStats holds the metrics of an AdaptiveLimiter.
+// See `AdaptiveLimiter.Stats` method.
+type AdaptiveStats struct {
+	Limit    int `json:"limit"`
+	InFlight int `json:"inFlight"`
+	// Accepted and Rejected are the total number of requests served and refused.
+	Accepted uint64 `json:"accepted"`
+	Rejected uint64 `json:"rejected"`
+	// Increases and Decreases are the total number of limit changes.
+	Increases uint64 `json:"increases"`
+	Decreases uint64 `json:"decreases"`
+}
+
+// AdaptiveLimiter limits the number of concurrent requests
+// and adjusts that limit automatically based on the observed latencies,
+// protecting the downstream dependencies during degradation.
+//
+// Create a new AdaptiveLimiter per route (or group of routes)
+// through the `NewAdaptive` package-level function and register its `Handler`.
+type AdaptiveLimiter struct {
+	opts AdaptiveOptions
+
+	mu       sync.Mutex
+	limit    float64
+	inFlight int
+	longRTT  float64
+	stats    AdaptiveStats
+}
+
+// Adaptive returns a new adaptive concurrency limiter handler.
+// It's a shortcut of NewAdaptive(opts).Handler.
+//
+// Usage:
+//  app.Get("/search", rate.Adaptive(rate.AdaptiveOptions{Algorithm: rate.Gradient}), searchHandler)
+func Adaptive(opts AdaptiveOptions) context.Handler {
+	return NewAdaptive(opts).Handler
+}
+
+// NewAdaptive returns a new AdaptiveLimiter.
+// Register its `Handler` method as a route middleware
+// and use its `Stats` method to collect its metrics.
+func NewAdaptive(opts AdaptiveOptions) *AdaptiveLimiter {
+	if opts.MinLimit <= 0 {
+		opts.MinLimit = 1
+	}
+
+	if opts.MaxLimit <= 0 {
+		opts.MaxLimit = 1000
+	}
+
+	if opts.MaxLimit < opts.MinLimit {
+		opts.MaxLimit = opts.MinLimit
+	}
+
+	if opts.InitialLimit <= 0 {
+		opts.InitialLimit = 20
+	}
+
+	if opts.LatencyThreshold <= 0 {
+		opts.LatencyThreshold = time.Second
+	}
+
+	if opts.Backoff <= 0 || opts.Backoff >= 1 {
+		opts.Backoff = 0.9
+	}
+
+	if opts.Tolerance < 1 {
+		opts.Tolerance = 1.5
+	}
+
+	if opts.Smoothing <= 0 || opts.Smoothing > 1 {
+		opts.Smoothing = 0.2
+	}
+
+	if opts.LongWindow <= 0 {
+		opts.LongWindow = 600
+	}
+
+	if opts.ExceedHandler == nil {
+		opts.ExceedHandler = func(ctx *context.Context) {
+			ctx.StopWithStatus(429) // Too Many Requests.
+		}
+	}
+
+	l := &AdaptiveLimiter{opts: opts}
+	l.limit = l.clamp(float64(opts.InitialLimit))
+	return l
+}
+
+// Handler is the middleware which limits the concurrent requests.
+func (l *AdaptiveLimiter) Handler(ctx *context.Context) {
+	if !l.acquire() {
+		l.opts.ExceedHandler(ctx)
+		return
+	}
+
+	start := time.Now()
+	defer func() {
+		l.release(time.Since(start), ctx.GetStatusCode() >= 500)
+	}()
+
+	ctx.Next()
+}
+
+// Stats returns the current metrics of the limiter.
+func (l *AdaptiveLimiter) Stats() AdaptiveStats {
+	l.mu.Lock()
+	stats := l.stats
+	stats.Limit = int(l.limit)
+	stats.InFlight = l.inFlight
+	l.mu.Unlock()
+
+	return stats
+}
+
+func (l *AdaptiveLimiter) acquire() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.inFlight >= int(l.limit) {
+		l.stats.Rejected++
+		return false
+	}
+
+	l.inFlight++
+	l.stats.Accepted++
+	return true
+}
+
+func (l *AdaptiveLimiter) release(rtt time.Duration, failed bool) {
+	l.mu.Lock()
+	inFlight := l.inFlight
+	l.inFlight--
+
+	previous := int(l.limit)
+	switch l.opts.Algorithm {
+	case Gradient:
+		l.limit = l.gradient(rtt, inFlight)
+	default:
+		l.limit = l.aimd(rtt, failed, inFlight)
+	}
+	limit := int(l.limit)
+
+	if limit > previous {
+		l.stats.Increases++
+	} else if limit < previous {
+		l.stats.Decreases++
+	}
+	l.mu.Unlock()
+
+	if limit != previous && l.opts.OnLimitChange != nil {
+		l.opts.OnLimitChange(previous, limit)
+	}
+}
+
+func (l *AdaptiveLimiter) aimd(rtt time.Duration, failed bool, inFlight int) float64 {
+	if failed || rtt > l.opts.LatencyThreshold {
+		return l.clamp(math.Floor(l.limit * l.opts.Backoff))
+	}
+
+	// Don't grow the limit when the application does not use it.
+	if float64(inFlight)*2 >= l.limit {
+		return l.clamp(l.limit + 1)
+	}
+
+	return l.limit
+}
+
+func (l *AdaptiveLimiter) gradient(rtt time.Duration, inFlight int) float64 {
+	shortRTT := float64(rtt)
+	if shortRTT <= 0 {
+		return l.limit
+	}
+
+	if l.longRTT == 0 {
+		l.longRTT = shortRTT
+	} else {
+		window := float64(l.opts.LongWindow)
+		l.longRTT = l.longRTT*(window-1)/window + shortRTT/window
+	}
+
+	gradient := math.Max(0.5, math.Min(1, l.opts.Tolerance*l.longRTT/shortRTT))
+	queueSize := math.Sqrt(l.limit)
+	newLimit := l.limit*gradient + queueSize
+
+	if newLimit > l.limit && float64(inFlight)*2 < l.limit {
+		return l.limit
+	}
+
+	return l.clamp(l.limit*(1-l.opts.Smoothing) + newLimit*l.opts.Smoothing)
+}
+
+func (l *AdaptiveLimiter) clamp(limit float64) float64 {
+	return math.Max(float64(l.opts.MinLimit), math.Min(float64(l.opts.MaxLimit), limit))
+}
@@ -0,0 +1,63 @@
+package rate
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAdaptiveAIMD(t *testing.T) {
+	var changes int
+	l := NewAdaptive(AdaptiveOptions{
+		InitialLimit:     10,
+		MaxLimit:         11,
+		LatencyThreshold: 100 * time.Millisecond,
+		OnLimitChange:    func(previous, limit int) { changes++ },
+	})
+
+	for i := 0; i < 10; i++ {
+		if !l.acquire() {
+			t.Fatalf("[%d] expected to acquire", i)
+		}
+	}
+
+	if l.acquire() {
+		t.Fatalf("expected limit to be reached")
+	}
+
+	l.release(time.Millisecond, false) // 10 in flight, increase.
+	l.release(time.Millisecond, false) // 9 in flight, max limit reached.
+	if got := l.Stats().Limit; got != 11 {
+		t.Fatalf("expected limit: %d but got: %d", 11, got)
+	}
+
+	l.release(time.Second, false)     // slow, decrease.
+	l.release(time.Millisecond, true) // failure, decrease.
+
+	stats := l.Stats()
+	expected := AdaptiveStats{Limit: 8, InFlight: 6, Accepted: 10, Rejected: 1, Increases: 1, Decreases: 2}
+	if stats != expected {
+		t.Fatalf("expected stats: %#+v but got: %#+v", expected, stats)
+	}
+
+	if changes != 3 {
+		t.Fatalf("expected %d limit changes but got: %d", 3, changes)
+	}
+}
+
+func TestAdaptiveGradient(t *testing.T) {
+	l := NewAdaptive(AdaptiveOptions{Algorithm: Gradient, InitialLimit: 100})
+
+	for i := 0; i < 100; i++ {
+		l.acquire()
+		l.release(10*time.Millisecond, false)
+	}
+
+	for i := 0; i < 50; i++ {
+		l.acquire()
+		l.release(100*time.Millisecond, false) // latency grows.
+	}
+
+	if got := l.Stats().Limit; got >= 100 {
+		t.Fatalf("expected limit to be decreased but got: %d", got)
+	}
+}
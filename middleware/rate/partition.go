@@ -0,0 +1,118 @@
+package rate
+
+import (
+	"strings"
+
+	"github.com/kataras/iris/v12/context"
+)
+
+// KeyTemplate is an `Option` that can be passed at the `Limit` package-level function.
+// It partitions the limiter by the given key template, instead of the client's remote address,
+// so e.g. the requests of each tenant are throttled individually rather than the whole route.
+// The template's "{name}" placeholders are replaced by the values
+// of the route's path parameters, the "{ip}" one is replaced by the client's remote address.
+// An explicit `SetIdentifier` call overrides it.
+//
+// Usage:
+//  limit := rate.Limit(10, 20, rate.KeyTemplate("{org}"), rate.MaxKeys(10000))
+//  app.Get("/orgs/{org}/reports", limit, handler)
+//
+// Example templates: "{org}", "{org}:{ip}", "repo:{owner}/{repo}".
+func KeyTemplate(template string) Option {
+	keyFunc := parseKeyTemplate(template)
+	return func(l *Limiter) {
+		l.keyTemplate = keyFunc
+	}
+}
+
+// MaxKeys is an `Option` that can be passed at the `Limit` package-level function.
+// It sets the maximum number of the tracked clients (keys).
+// When the limit is reached the least recently used client is evicted,
+// so the memory is bounded even when the keys are controlled by the clients (see `KeyTemplate`).
+// Zero or negative value means no limit, see `PurgeEvery` too.
+func MaxKeys(n int) Option {
+	return func(l *Limiter) {
+		l.maxKeys = n
+	}
+}
+
+// touch returns the client of the "id" and marks it as the most recently used one.
+func (l *Limiter) touch(id string) (*Client, bool) {
+	l.mu.Lock()
+	client, ok := l.clients[id]
+	if ok {
+		l.lru.MoveToFront(client.elem)
+	}
+	l.mu.Unlock()
+
+	return client, ok
+}
+
+// store adds the client, evicting the least recently used one if necessary.
+// Caller should lock.
+func (l *Limiter) store(id string, client *Client) {
+	if existing, ok := l.clients[id]; ok {
+		// Stored by a concurrent request, replace it.
+		l.remove(id, existing)
+	}
+
+	l.clients[id] = client
+	if l.lru == nil {
+		return
+	}
+
+	client.elem = l.lru.PushFront(id)
+	for l.lru.Len() > l.maxKeys {
+		oldest := l.lru.Back()
+		l.remove(oldest.Value.(string), l.clients[oldest.Value.(string)])
+	}
+}
+
+// remove deletes the client. Caller should lock.
+func (l *Limiter) remove(id string, client *Client) {
+	delete(l.clients, id)
+	if l.lru != nil && client != nil && client.elem != nil {
+		l.lru.Remove(client.elem)
+		client.elem = nil
+	}
+}
+
+func parseKeyTemplate(template string) func(ctx *context.Context) string {
+	type part struct {
+		text    string
+		isParam bool
+	}
+
+	var parts []part
+	for template != "" {
+		start := strings.IndexByte(template, '{')
+		end := strings.IndexByte(template, '}')
+		if start == -1 || end < start {
+			parts = append(parts, part{text: template})
+			break
+		}
+
+		if start > 0 {
+			parts = append(parts, part{text: template[:start]})
+		}
+
+		parts = append(parts, part{text: template[start+1 : end], isParam: true})
+		template = template[end+1:]
+	}
+
+	return func(ctx *context.Context) string {
+		var b strings.Builder
+		for _, p := range parts {
+			switch {
+			case !p.isParam:
+				b.WriteString(p.text)
+			case p.text == "ip":
+				b.WriteString(ctx.RemoteAddr())
+			default:
+				b.WriteString(ctx.Params().Get(p.text))
+			}
+		}
+
+		return b.String()
+	}
+}
@@ -0,0 +1,31 @@
+package rate_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/httptest"
+	"github.com/kataras/iris/v12/middleware/rate"
+)
+
+func TestKeyTemplate(t *testing.T) {
+	app := iris.New()
+	limit := rate.Limit(rate.Every(time.Hour), 1, rate.KeyTemplate("org:{org}"), rate.MaxKeys(2))
+	app.Get("/orgs/{org}/reports", limit, func(ctx iris.Context) {
+		ctx.WriteString(rate.Get(ctx).ID)
+	})
+
+	e := httptest.New(t, app)
+	e.GET("/orgs/acme/reports").Expect().Status(httptest.StatusOK).Body().Equal("org:acme")
+	// The noisy tenant is throttled individually.
+	e.GET("/orgs/acme/reports").Expect().Status(httptest.StatusTooManyRequests)
+	e.GET("/orgs/globex/reports").Expect().Status(httptest.StatusOK)
+	e.GET("/orgs/acme/reports").Expect().Status(httptest.StatusTooManyRequests)
+	// Evicts the least recently used one (globex).
+	e.GET("/orgs/initech/reports").Expect().Status(httptest.StatusOK)
+	// Tracked again, evicts acme.
+	e.GET("/orgs/globex/reports").Expect().Status(httptest.StatusOK)
+	e.GET("/orgs/initech/reports").Expect().Status(httptest.StatusTooManyRequests)
+	e.GET("/orgs/acme/reports").Expect().Status(httptest.StatusOK)
+}
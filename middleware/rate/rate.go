@@ -3,17 +3,22 @@
 package rate
 
 import (
+	"container/list"
 	"math"
 	"sync"
 	"time"
 
 	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/kv"
 
 	"golang.org/x/time/rate"
 )
 
 func init() {
 	context.SetHandlerName("iris/middleware/rate.(*Limiter).serveHTTP-fm", "iris.ratelimit")
+	context.SetHandlerName("iris/middleware/rate.(*AdaptiveLimiter).Handler-fm", "iris.ratelimit.adaptive")
+	context.SetHandlerPolicy("iris.ratelimit", context.PolicyRateLimit)
+	context.SetHandlerPolicy("iris.ratelimit.adaptive", context.PolicyRateLimit)
 }
 
 // Option declares a function which can be passed on `Limit` package-level
@@ -21,6 +26,9 @@ func init() {
 // * ExceedHandler
 // * ClientData
 // * PurgeEvery
+// * KeyTemplate
+// * MaxKeys
+// * Store
 type Option func(*Limiter)
 
 // ExceedHandler is an `Option` that can be passed at the `Limit` package-level function.
@@ -87,6 +95,14 @@ type (
 
 		clients map[string]*Client
 		mu      sync.RWMutex // mutex for clients.
+
+		keyTemplate func(ctx *context.Context) string // see `KeyTemplate`.
+		maxKeys     int                               // see `MaxKeys`.
+		lru         *list.List                        // the recently used clients first, when maxKeys > 0.
+
+		kvStore  kv.Store // see `Store`.
+		kvPrefix string
+		kvLocks  []sync.Mutex // the locks of the stored buckets, by key.
 	}
 
 	// Client holds some request information and the rate limiter itself.
@@ -99,6 +115,8 @@ type (
 
 		lastSeen time.Time
 		mu       sync.RWMutex // mutex for lastSeen.
+
+		elem *list.Element // the element of the limiter's LRU list, see `MaxKeys`.
 	}
 )
 
@@ -110,7 +128,7 @@ const Inf = math.MaxFloat64
 //
 // E.g. Limit(1, 5) to allow 1 request per second, with a maximum burst size of 5.
 //
-// See `ExceedHandler`, `ClientData` and `PurgeEvery` for the available "options".
+// See `ExceedHandler`, `ClientData`, `PurgeEvery` and `Store` for the available "options".
 func Limit(limit float64, burst int, options ...Option) context.Handler {
 	l := &Limiter{
 		clients:   make(map[string]*Client),
@@ -125,6 +143,14 @@ func Limit(limit float64, burst int, options ...Option) context.Handler {
 		opt(l)
 	}
 
+	if l.limit == rate.Inf || l.limit <= 0 {
+		l.kvStore = nil
+	}
+
+	if l.maxKeys > 0 && l.kvStore == nil {
+		l.lru = list.New()
+	}
+
 	return l.serveHTTP
 }
 
@@ -133,17 +159,31 @@ func (l *Limiter) Purge(condition func(*Client) bool) {
 	l.mu.Lock()
 	for id, client := range l.clients {
 		if condition(client) {
-			delete(l.clients, id)
+			l.remove(id, client)
 		}
 	}
 	l.mu.Unlock()
 }
 
 func (l *Limiter) serveHTTP(ctx *context.Context) {
-	id := getIdentifier(ctx)
-	l.mu.RLock()
-	client, ok := l.clients[id]
-	l.mu.RUnlock()
+	id := l.getIdentifier(ctx)
+	if l.kvStore != nil {
+		l.serveStored(ctx, id)
+		return
+	}
+
+	var (
+		client *Client
+		ok     bool
+	)
+
+	if l.lru != nil {
+		client, ok = l.touch(id)
+	} else {
+		l.mu.RLock()
+		client, ok = l.clients[id]
+		l.mu.RUnlock()
+	}
 
 	if !ok {
 		client = &Client{
@@ -158,7 +198,7 @@ func (l *Limiter) serveHTTP(ctx *context.Context) {
 		//  if l.store(ctx, client) {
 		// ^ no, let's keep it simple.
 		l.mu.Lock()
-		l.clients[id] = client
+		l.store(id, client)
 		l.mu.Unlock()
 	}
 
@@ -168,7 +208,7 @@ func (l *Limiter) serveHTTP(ctx *context.Context) {
 
 	ctx.Values().Set(clientContextKey, client)
 
-	if client.Limiter.Allow() {
+	if client.Limiter.AllowN(ctx.Now(), 1) {
 		ctx.Next()
 		return
 	}
@@ -186,11 +226,15 @@ func SetIdentifier(ctx *context.Context, key string) {
 	ctx.Values().Set(identifierContextKey, key)
 }
 
-func getIdentifier(ctx *context.Context) string {
+func (l *Limiter) getIdentifier(ctx *context.Context) string {
 	if entry, ok := ctx.Values().GetEntry(identifierContextKey); ok {
 		return entry.ValueRaw.(string)
 	}
 
+	if l.keyTemplate != nil {
+		return l.keyTemplate(ctx)
+	}
+
 	return ctx.RemoteAddr()
 }
 
@@ -0,0 +1,102 @@
+package rate
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/kv"
+
+	"golang.org/x/time/rate"
+)
+
+// kvLockStripes is the number of the locks of a limiter with a `Store`,
+// the requests of different clients are served in parallel.
+const kvLockStripes = 256
+
+// Store is an `Option` that can be passed at the `Limit` package-level function.
+// It keeps the clients' token buckets to the given key-value "store", under the "prefix" keys,
+// instead of the memory, so the limits survive a restart of a single-binary deployment
+// (e.g. with a kv/boltdb store). A bucket is read and written on each allowed request,
+// without a store transaction, so a "prefix" should be used by a single limiter of a single process,
+// other limiters can use the same store under different prefixes.
+// A bucket is removed by the store once it is full again,
+// so the `PurgeEvery` and `MaxKeys` options are not required.
+// It's ignored on an infinite or a zero limit.
+//
+// Usage:
+//  store, err := boltdb.New("./data/app.db")
+//  limit := rate.Limit(1, 5, rate.Store(store, "ratelimit:api:"))
+func Store(store kv.Store, prefix string) Option {
+	return func(l *Limiter) {
+		l.kvStore = store
+		l.kvPrefix = prefix
+		l.kvLocks = make([]sync.Mutex, kvLockStripes)
+	}
+}
+
+// kvLock returns the lock of a stored bucket's key.
+func (l *Limiter) kvLock(key string) *sync.Mutex {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return &l.kvLocks[h.Sum32()%kvLockStripes]
+}
+
+// serveStored is the `serveHTTP` of a limiter which keeps its buckets to a kv store.
+// A bucket is stored as its theoretical arrival time (unix nanoseconds),
+// the time that the bucket is full again.
+func (l *Limiter) serveStored(ctx *context.Context, id string) {
+	client := &Client{ID: id, lastSeen: time.Now()}
+	if l.clientDataFunc != nil {
+		client.Data = l.clientDataFunc(ctx)
+	}
+
+	var (
+		now      = ctx.Now()
+		key      = l.kvPrefix + id
+		interval = time.Duration(float64(time.Second) / float64(l.limit))
+		tat      time.Time
+	)
+
+	mu := l.kvLock(key)
+	mu.Lock()
+	if b, err := l.kvStore.Get(key); err == nil && len(b) == 8 {
+		tat = time.Unix(0, int64(binary.BigEndian.Uint64(b)))
+	} else if err != nil && err != kv.ErrNotFound {
+		ctx.Application().Logger().Debugf("rate: get: %s: %v", key, err)
+	}
+
+	client.Limiter = rate.NewLimiter(l.limit, l.burstSize)
+	if tat.After(now) {
+		// Restore the bucket: empty at the time which results to a full one at "tat".
+		client.Limiter.AllowN(tat.Add(-time.Duration(l.burstSize)*interval), l.burstSize)
+	}
+
+	allowed := client.Limiter.AllowN(now, 1)
+	if allowed {
+		if tat.Before(now) {
+			tat = now
+		}
+		tat = tat.Add(interval)
+
+		b := make([]byte, 8)
+		binary.BigEndian.PutUint64(b, uint64(tat.UnixNano()))
+		if err := l.kvStore.Set(key, b, tat.Sub(now)); err != nil {
+			ctx.Application().Logger().Errorf("rate: set: %s: %v", key, err)
+		}
+	}
+	mu.Unlock()
+
+	ctx.Values().Set(clientContextKey, client)
+
+	if allowed {
+		ctx.Next()
+		return
+	}
+
+	if l.exceedHandler != nil {
+		l.exceedHandler(ctx)
+	}
+}
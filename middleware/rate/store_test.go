@@ -0,0 +1,107 @@
+package rate_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12"
+	irishttptest "github.com/kataras/iris/v12/httptest"
+	"github.com/kataras/iris/v12/kv"
+	"github.com/kataras/iris/v12/middleware/rate"
+)
+
+func TestStore(t *testing.T) {
+	store := kv.NewMemory()
+	clock := iris.NewMockClock(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	newApp := func() *iris.Application {
+		app := iris.New()
+		app.SetClock(clock)
+		app.Get("/", rate.Limit(1, 2, rate.Store(store, "test:")), func(ctx iris.Context) {
+			ctx.WriteString(rate.Get(ctx).ID)
+		})
+		return app
+	}
+
+	e := irishttptest.New(t, newApp())
+	e.GET("/").Expect().Status(irishttptest.StatusOK)
+	e.GET("/").Expect().Status(irishttptest.StatusOK)
+	e.GET("/").Expect().Status(irishttptest.StatusTooManyRequests)
+
+	// The bucket is kept to the store, a restarted application continues from it.
+	e = irishttptest.New(t, newApp())
+	e.GET("/").Expect().Status(irishttptest.StatusTooManyRequests)
+
+	clock.Advance(time.Second)
+	e.GET("/").Expect().Status(irishttptest.StatusOK)
+	e.GET("/").Expect().Status(irishttptest.StatusTooManyRequests)
+
+	clock.Advance(2 * time.Second)
+	e.GET("/").Expect().Status(irishttptest.StatusOK)
+	e.GET("/").Expect().Status(irishttptest.StatusOK)
+	e.GET("/").Expect().Status(irishttptest.StatusTooManyRequests)
+
+	if n := countKeys(t, store, "test:"); n != 1 {
+		t.Fatalf("expected a single stored bucket but got: %d", n)
+	}
+}
+
+func TestStoreConcurrent(t *testing.T) {
+	store := kv.NewMemory()
+	clock := iris.NewMockClock(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	app := iris.New().Configure(iris.WithRemoteAddrHeader("X-Real-Ip"))
+	app.SetClock(clock)
+	app.Get("/", rate.Limit(1, 5, rate.Store(store, "test:")))
+	if err := app.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		wg      sync.WaitGroup
+		allowed = make(map[string]*uint32)
+	)
+	for _, ip := range []string{"1.1.1.1", "2.2.2.2", "3.3.3.3"} {
+		n := new(uint32)
+		allowed[ip] = n
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func(ip string) {
+				defer wg.Done()
+
+				req := httptest.NewRequest(http.MethodGet, "/", nil)
+				req.Header.Set("X-Real-Ip", ip)
+				rec := httptest.NewRecorder()
+				app.ServeHTTP(rec, req)
+				if rec.Code == http.StatusOK {
+					atomic.AddUint32(n, 1)
+				}
+			}(ip)
+		}
+	}
+	wg.Wait()
+
+	for ip, n := range allowed {
+		if expected, got := uint32(5), atomic.LoadUint32(n); expected != got {
+			t.Fatalf("%s: expected %d allowed requests but got: %d", ip, expected, got)
+		}
+	}
+}
+
+func countKeys(t *testing.T, store kv.Store, prefix string) (n int) {
+	t.Helper()
+
+	err := store.Iterate(prefix, func(string, []byte) bool {
+		n++
+		return true
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return
+}
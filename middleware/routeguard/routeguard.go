@@ -0,0 +1,293 @@
+// Package routeguard takes a route offline when its panic or error rate
+// crosses a threshold, to limit the blast radius of a bad deploy to that route.
+// The disabled routes respond with 503 Service Unavailable and the incident's ID,
+// until they are enabled again through the admin handlers.
+package routeguard
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kataras/iris/v12/context"
+
+	"github.com/google/uuid"
+)
+
+func init() {
+	context.SetHandlerName("iris/middleware/routeguard.*", "iris.routeguard")
+}
+
+// IncidentHeaderKey is the response header of the disabled routes
+// which holds the incident's ID.
+const IncidentHeaderKey = "X-Incident-ID"
+
+// Options holds the configuration of a `Guard`.
+type Options struct {
+	// Threshold is the failure rate, between 0 and 1,
+	// which takes a route offline. Defaults to 0.5.
+	Threshold float64
+	// MinRequests is the minimum number of requests of a route, in a window,
+	// before its failure rate is evaluated. Defaults to 20.
+	MinRequests uint64
+	// Window is the duration of the time window the requests are counted in.
+	// Defaults to 1 minute.
+	Window time.Duration
+	// IsFailure reports whether a served request failed.
+	// Defaults to the requests which panicked or responded with a 5xx status code.
+	IsFailure func(ctx *context.Context, panicked bool) bool
+	// OnTrip is fired when a route is taken offline, e.g. to page the on-call team.
+	// It should not block.
+	OnTrip func(incident Incident)
+	// Clock is used to count the windows and timestamp the incidents.
+	//
+	// Defaults to the `context.SystemClock`.
+	Clock context.Clock
+}
+
+// Incident describes a route which was taken offline.
+type Incident struct {
+	ID string `json:"id"`
+	// Route is the route's name, e.g. "GET/users/{id}".
+	Route    string    `json:"route"`
+	Requests uint64    `json:"requests"`
+	Failures uint64    `json:"failures"`
+	Rate     float64   `json:"rate"`
+	Time     time.Time `json:"time"`
+	// LastError is the error of the last failed request, if any.
+	LastError string `json:"lastError,omitempty"`
+}
+
+// Error completes the error interface, the disabled routes
+// stop the request with the incident as the error, see `Context.GetErr`.
+func (i Incident) Error() string {
+	return fmt.Sprintf("route %s is temporarily disabled, incident: %s", i.Route, i.ID)
+}
+
+type routeState struct {
+	windowStart time.Time
+	requests    uint64
+	failures    uint64
+	lastError   string
+	incident    *Incident // not nil when the route is disabled.
+}
+
+// Guard counts the panics and the errors of each route and takes a route
+// offline when its failure rate crosses the `Options.Threshold` in a window.
+// Register its `Handler` through `Application.UseGlobal`.
+//
+// Usage:
+//  guard := routeguard.New(routeguard.Options{
+//      OnTrip: func(incident routeguard.Incident) { alert(incident) },
+//  })
+//  app.UseRouter(recover.New())
+//  app.UseGlobal(guard.Handler)
+//
+//  admin := app.Party("/admin", basicAuth)
+//  admin.Get("/incidents", guard.IncidentsHandler)
+//  admin.Post("/incidents/enable", guard.EnableHandler)
+//
+// Make sure the admin handlers are protected.
+type Guard struct {
+	opts Options
+
+	mu     sync.Mutex
+	routes map[string]*routeState
+}
+
+// New returns a new route Guard.
+func New(opts Options) *Guard {
+	if opts.Threshold <= 0 {
+		opts.Threshold = 0.5
+	}
+
+	if opts.MinRequests == 0 {
+		opts.MinRequests = 20
+	}
+
+	if opts.Window <= 0 {
+		opts.Window = time.Minute
+	}
+
+	if opts.IsFailure == nil {
+		opts.IsFailure = isFailure
+	}
+
+	if opts.Clock == nil {
+		opts.Clock = context.SystemClock
+	}
+
+	return &Guard{
+		opts:   opts,
+		routes: make(map[string]*routeState),
+	}
+}
+
+func isFailure(ctx *context.Context, panicked bool) bool {
+	if panicked {
+		return true
+	}
+
+	if _, ok := context.IsErrPanicRecovery(ctx.GetErr()); ok {
+		return true
+	}
+
+	return ctx.GetStatusCode() >= 500
+}
+
+// Handler serves the 503 Service Unavailable error of the disabled routes
+// and counts the failures of the rest.
+// It should be registered through the `Application.UseGlobal` method.
+func (g *Guard) Handler(ctx *context.Context) {
+	route := ctx.GetCurrentRoute()
+	if route == nil || route.StatusErrorCode() > 0 {
+		ctx.Next()
+		return
+	}
+
+	routeName := route.Name()
+	if incident, disabled := g.Incident(routeName); disabled {
+		ctx.Header(IncidentHeaderKey, incident.ID)
+		ctx.StopWithError(http.StatusServiceUnavailable, incident)
+		return
+	}
+
+	panicked := true
+	defer func() {
+		if !panicked {
+			g.record(ctx, routeName, false, nil)
+			return
+		}
+
+		r := recover()
+		g.record(ctx, routeName, true, r)
+		if r != nil {
+			panic(r) // let the recover middleware handle it.
+		}
+	}()
+
+	ctx.Next()
+	panicked = false
+}
+
+func (g *Guard) record(ctx *context.Context, routeName string, panicked bool, cause interface{}) {
+	failed := g.opts.IsFailure(ctx, panicked)
+	now := g.opts.Clock.Now()
+
+	g.mu.Lock()
+	state, ok := g.routes[routeName]
+	if !ok {
+		state = &routeState{windowStart: now}
+		g.routes[routeName] = state
+	}
+
+	if state.incident != nil { // disabled by a concurrent request.
+		g.mu.Unlock()
+		return
+	}
+
+	if now.Sub(state.windowStart) >= g.opts.Window {
+		state.windowStart = now
+		state.requests, state.failures = 0, 0
+	}
+
+	state.requests++
+	if failed {
+		state.failures++
+		if panicked {
+			state.lastError = fmt.Sprint(cause)
+		} else if err := ctx.GetErr(); err != nil {
+			state.lastError = err.Error()
+		}
+	}
+
+	rate := float64(state.failures) / float64(state.requests)
+	if !failed || state.requests < g.opts.MinRequests || rate < g.opts.Threshold {
+		g.mu.Unlock()
+		return
+	}
+
+	incident := Incident{
+		ID:        uuid.New().String(),
+		Route:     routeName,
+		Requests:  state.requests,
+		Failures:  state.failures,
+		Rate:      rate,
+		Time:      now,
+		LastError: state.lastError,
+	}
+	state.incident = &incident
+	g.mu.Unlock()
+
+	ctx.Application().Logger().Errorf("routeguard: %s (%d/%d failed requests): %s", incident.Error(),
+		incident.Failures, incident.Requests, incident.LastError)
+
+	if g.opts.OnTrip != nil {
+		g.opts.OnTrip(incident)
+	}
+}
+
+// Incident returns the incident of the "routeName" route
+// and reports whether the route is disabled.
+func (g *Guard) Incident(routeName string) (Incident, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if state, ok := g.routes[routeName]; ok && state.incident != nil {
+		return *state.incident, true
+	}
+
+	return Incident{}, false
+}
+
+// Incidents returns the incidents of the disabled routes, the oldest first.
+func (g *Guard) Incidents() []Incident {
+	g.mu.Lock()
+	incidents := make([]Incident, 0)
+	for _, state := range g.routes {
+		if state.incident != nil {
+			incidents = append(incidents, *state.incident)
+		}
+	}
+	g.mu.Unlock()
+
+	sort.Slice(incidents, func(i, j int) bool {
+		return incidents[i].Time.Before(incidents[j].Time)
+	})
+
+	return incidents
+}
+
+// Enable takes the "routeName" route back online with a fresh window
+// and reports whether it was disabled.
+func (g *Guard) Enable(routeName string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	state, ok := g.routes[routeName]
+	if !ok || state.incident == nil {
+		return false
+	}
+
+	delete(g.routes, routeName)
+	return true
+}
+
+// IncidentsHandler writes the incidents of the disabled routes as a JSON array.
+func (g *Guard) IncidentsHandler(ctx *context.Context) {
+	ctx.JSON(g.Incidents())
+}
+
+// EnableHandler takes the route of the "route" form value
+// (a URL query or a body field), e.g. "GET/users/{id}", back online.
+// It responds with 204 No Content or 404 Not Found if the route was not disabled.
+func (g *Guard) EnableHandler(ctx *context.Context) {
+	if !g.Enable(ctx.FormValue("route")) {
+		ctx.StopWithStatus(http.StatusNotFound)
+		return
+	}
+
+	ctx.StatusCode(http.StatusNoContent)
+}
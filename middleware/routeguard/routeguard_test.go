@@ -0,0 +1,73 @@
+package routeguard_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/httptest"
+	"github.com/kataras/iris/v12/middleware/recover"
+	"github.com/kataras/iris/v12/middleware/routeguard"
+)
+
+func TestRouteGuard(t *testing.T) {
+	clock := context.NewMockClock(time.Now())
+
+	var tripped []routeguard.Incident
+	guard := routeguard.New(routeguard.Options{
+		Threshold:   0.5,
+		MinRequests: 4,
+		Window:      time.Minute,
+		OnTrip: func(incident routeguard.Incident) {
+			tripped = append(tripped, incident)
+		},
+		Clock: clock,
+	})
+
+	app := iris.New()
+	app.Logger().SetLevel("disable")
+	app.UseRouter(recover.New())
+	app.UseGlobal(guard.Handler)
+	app.Get("/orders/{fail:boolean}", func(ctx iris.Context) {
+		if ctx.Params().GetBoolDefault("fail", false) {
+			panic("bad deploy")
+		}
+	})
+	app.Get("/users", func(ctx iris.Context) {})
+	app.Get("/incidents", guard.IncidentsHandler)
+	app.Post("/incidents/enable", guard.EnableHandler)
+
+	e := httptest.New(t, app)
+	e.GET("/orders/false").Expect().Status(httptest.StatusOK)
+	e.GET("/orders/true").Expect().Status(httptest.StatusInternalServerError)
+	// A new window.
+	clock.Advance(time.Minute)
+	e.GET("/orders/false").Expect().Status(httptest.StatusOK)
+	e.GET("/orders/true").Expect().Status(httptest.StatusInternalServerError)
+	e.GET("/orders/false").Expect().Status(httptest.StatusOK)
+	if len(tripped) > 0 {
+		t.Fatalf("expected no incidents before the minimum requests but got: %v", tripped)
+	}
+	e.GET("/orders/true").Expect().Status(httptest.StatusInternalServerError)
+
+	if len(tripped) != 1 {
+		t.Fatalf("expected a single incident but got: %v", tripped)
+	}
+	incident := tripped[0]
+	if incident.Route != "GET/orders/{fail:boolean}" || incident.Requests != 4 || incident.Failures != 2 || incident.LastError != "bad deploy" {
+		t.Fatalf("unexpected incident: %#v", incident)
+	}
+
+	e.GET("/orders/false").Expect().Status(httptest.StatusServiceUnavailable).
+		Header(routeguard.IncidentHeaderKey).Equal(incident.ID)
+	e.GET("/users").Expect().Status(httptest.StatusOK)
+
+	e.GET("/incidents").Expect().Status(httptest.StatusOK).JSON().Array().
+		Element(0).Object().ValueEqual("id", incident.ID).ValueEqual("route", incident.Route)
+
+	e.POST("/incidents/enable").WithFormField("route", "GET/users").Expect().Status(httptest.StatusNotFound)
+	e.POST("/incidents/enable").WithFormField("route", incident.Route).Expect().Status(httptest.StatusNoContent)
+	e.GET("/orders/false").Expect().Status(httptest.StatusOK)
+	e.GET("/incidents").Expect().Status(httptest.StatusOK).JSON().Array().Empty()
+}
@@ -0,0 +1,290 @@
+// Package routeusage counts the hits and tracks the last access time of each route,
+// so routes which are not used for a long period, candidates for removal, can be reported.
+package routeusage
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"text/tabwriter"
+	"time"
+
+	"github.com/kataras/iris/v12/context"
+)
+
+func init() {
+	context.SetHandlerName("iris/middleware/routeusage.*", "iris.routeusage")
+}
+
+// Usage holds the usage statistics of a route.
+type Usage struct {
+	// Route is the route's name, e.g. "GET/users/{id}".
+	Route   string    `json:"route"`
+	Method  string    `json:"method,omitempty"`
+	Path    string    `json:"path,omitempty"`
+	Hits    uint64    `json:"hits"`
+	LastHit time.Time `json:"lastHit,omitempty"`
+}
+
+type counter struct {
+	hits    uint64
+	lastHit int64 // unix nano.
+}
+
+// Tracker counts the hits and keeps the last access time of each route,
+// with atomic operations, no locks on the request path.
+// Register its `Handler` through `Application.UseRouter`.
+//
+// Usage:
+//  usage := routeusage.New()
+//  stop, err := usage.Persist("./route-usage.json", time.Minute)
+//  [handle err...]
+//  iris.RegisterOnInterrupt(func() { stop() })
+//  app.UseRouter(usage.Handler)
+//
+//  admin := app.Party("/admin", basicAuth)
+//  admin.Get("/routes/unused", usage.ReportHandler)
+//
+// Make sure the report handler is protected.
+type Tracker struct {
+	// Clock is used to timestamp the hits and the reports.
+	//
+	// Defaults to the `context.SystemClock`.
+	Clock context.Clock
+
+	counters sync.Map // route name:*counter.
+	since    int64    // unix nano, the start of the tracking.
+}
+
+// New returns a new route usage Tracker.
+func New() *Tracker {
+	return &Tracker{
+		Clock: context.SystemClock,
+		since: time.Now().UnixNano(),
+	}
+}
+
+// Since returns the start time of the tracking,
+// routes which were never hit are considered unused since then.
+func (t *Tracker) Since() time.Time {
+	return time.Unix(0, atomic.LoadInt64(&t.since))
+}
+
+// Handler records the route which served the request.
+// It should be registered through the `Application.UseRouter` method.
+func (t *Tracker) Handler(ctx *context.Context) {
+	ctx.Next()
+
+	route := ctx.GetCurrentRoute()
+	if route == nil || route.StatusErrorCode() > 0 {
+		return
+	}
+
+	t.Hit(route.Name())
+}
+
+// Hit records a hit of the "routeName" route.
+func (t *Tracker) Hit(routeName string) {
+	v, ok := t.counters.Load(routeName)
+	if !ok {
+		v, _ = t.counters.LoadOrStore(routeName, new(counter))
+	}
+
+	c := v.(*counter)
+	atomic.AddUint64(&c.hits, 1)
+	atomic.StoreInt64(&c.lastHit, t.Clock.Now().UnixNano())
+}
+
+// Usage returns the usage statistics of the "routeName" route.
+func (t *Tracker) Usage(routeName string) Usage {
+	u := Usage{Route: routeName}
+	if v, ok := t.counters.Load(routeName); ok {
+		c := v.(*counter)
+		u.Hits = atomic.LoadUint64(&c.hits)
+		u.LastHit = time.Unix(0, atomic.LoadInt64(&c.lastHit))
+	}
+
+	return u
+}
+
+// Unused returns the usage statistics of the "routes" which were not hit
+// for at least the "unusedFor" duration, the least recently used first.
+// Routes which were never hit are reported only if the tracking started
+// before that duration, see `Since`.
+func (t *Tracker) Unused(routes []context.RouteReadOnly, unusedFor time.Duration) []Usage {
+	deadline := t.Clock.Now().Add(-unusedFor)
+
+	var unused []Usage
+	for _, r := range routes {
+		if r.StatusErrorCode() > 0 {
+			continue
+		}
+
+		u := t.Usage(r.Name())
+		u.Method, u.Path = r.Method(), r.Path()
+
+		lastSeen := u.LastHit
+		if u.Hits == 0 {
+			lastSeen = t.Since()
+		}
+
+		if lastSeen.After(deadline) {
+			continue
+		}
+
+		unused = append(unused, u)
+	}
+
+	sort.SliceStable(unused, func(i, j int) bool {
+		return unused[i].LastHit.Before(unused[j].LastHit)
+	})
+
+	return unused
+}
+
+// WriteReport writes the `Unused` routes as a text table to "w",
+// e.g. to the standard output of a command.
+func (t *Tracker) WriteReport(w io.Writer, routes []context.RouteReadOnly, unusedFor time.Duration) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "METHOD\tPATH\tHITS\tLAST HIT\n")
+	for _, u := range t.Unused(routes, unusedFor) {
+		lastHit := "never"
+		if u.Hits > 0 {
+			lastHit = u.LastHit.Format(time.RFC3339)
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\n", u.Method, u.Path, u.Hits, lastHit)
+	}
+
+	return tw.Flush()
+}
+
+// ReportHandler writes the routes of the application which were not hit
+// for the duration of the "days" URL query parameter (defaults to 30)
+// as a JSON array. The "format=text" URL query parameter writes a text table instead.
+func (t *Tracker) ReportHandler(ctx *context.Context) {
+	unusedFor := time.Duration(ctx.URLParamIntDefault("days", 30)) * 24 * time.Hour
+	routes := ctx.Application().GetRoutesReadOnly()
+
+	if ctx.URLParam("format") == "text" {
+		ctx.ContentType(context.ContentTextHeaderValue)
+		t.WriteReport(ctx, routes, unusedFor)
+		return
+	}
+
+	unused := t.Unused(routes, unusedFor)
+	if unused == nil {
+		unused = []Usage{}
+	}
+
+	ctx.JSON(unused)
+}
+
+type snapshot struct {
+	Since  time.Time `json:"since"`
+	Routes []Usage   `json:"routes"`
+}
+
+// Save writes the usage statistics of all routes, encoded as JSON, to "w".
+func (t *Tracker) Save(w io.Writer) error {
+	s := snapshot{Since: t.Since()}
+	t.counters.Range(func(key, _ interface{}) bool {
+		s.Routes = append(s.Routes, t.Usage(key.(string)))
+		return true
+	})
+
+	return json.NewEncoder(w).Encode(s)
+}
+
+// Load reads the usage statistics written by `Save` from "r"
+// and merges them with the current ones,
+// so the tracking continues across application restarts.
+func (t *Tracker) Load(r io.Reader) error {
+	var s snapshot
+	if err := json.NewDecoder(r).Decode(&s); err != nil {
+		return err
+	}
+
+	if !s.Since.IsZero() && s.Since.Before(t.Since()) {
+		atomic.StoreInt64(&t.since, s.Since.UnixNano())
+	}
+
+	for _, u := range s.Routes {
+		v, _ := t.counters.LoadOrStore(u.Route, new(counter))
+		c := v.(*counter)
+		atomic.AddUint64(&c.hits, u.Hits)
+		if lastHit := u.LastHit.UnixNano(); lastHit > atomic.LoadInt64(&c.lastHit) {
+			atomic.StoreInt64(&c.lastHit, lastHit)
+		}
+	}
+
+	return nil
+}
+
+// Persist loads the usage statistics from the "filename", if it exists,
+// and saves them to that file every "interval".
+// It returns a function which stops the saving, after a final save.
+func (t *Tracker) Persist(filename string, interval time.Duration) (stop func() error, err error) {
+	if f, err := os.Open(filename); err == nil {
+		err = t.Load(f)
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	save := func() error {
+		// write to a temporary file first, so a crash
+		// in the middle of the write does not corrupt the previous one.
+		tmp := filename + ".tmp"
+		f, err := os.Create(tmp)
+		if err != nil {
+			return err
+		}
+
+		if err = t.Save(f); err != nil {
+			f.Close()
+			return err
+		}
+
+		if err = f.Close(); err != nil {
+			return err
+		}
+
+		return os.Rename(tmp, filename)
+	}
+
+	done := make(chan struct{})
+	var once sync.Once
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				save()
+			}
+		}
+	}()
+
+	stop = func() (err error) {
+		once.Do(func() {
+			close(done)
+			err = save()
+		})
+
+		return
+	}
+
+	return stop, nil
+}
@@ -0,0 +1,100 @@
+package routeusage_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/httptest"
+	"github.com/kataras/iris/v12/middleware/routeusage"
+)
+
+func TestRouteUsage(t *testing.T) {
+	clock := context.NewMockClock(time.Now())
+
+	usage := routeusage.New()
+	usage.Clock = clock
+
+	app := iris.New()
+	app.UseRouter(usage.Handler)
+	app.Get("/users", func(ctx iris.Context) {})
+	app.Get("/users/{id}", func(ctx iris.Context) {})
+	app.Get("/legacy", func(ctx iris.Context) {})
+	app.Get("/report", usage.ReportHandler)
+
+	e := httptest.New(t, app)
+	e.GET("/users").Expect().Status(httptest.StatusOK)
+	e.GET("/users/42").Expect().Status(httptest.StatusOK)
+	e.GET("/users/43").Expect().Status(httptest.StatusOK)
+	e.GET("/notfound").Expect().Status(httptest.StatusNotFound)
+
+	if expected, got := uint64(2), usage.Usage("GET/users/{id}").Hits; expected != got {
+		t.Fatalf("expected %d hits but got: %d", expected, got)
+	}
+
+	// Not enough data yet.
+	if unused := usage.Unused(app.GetRoutesReadOnly(), 30*24*time.Hour); len(unused) > 0 {
+		t.Fatalf("expected no unused routes but got: %#+v", unused)
+	}
+
+	clock.Advance(31 * 24 * time.Hour)
+	e.GET("/users").Expect().Status(httptest.StatusOK)
+
+	unused := e.GET("/report").WithQuery("days", 30).Expect().Status(httptest.StatusOK).JSON().Array()
+	// The never hit routes are reported too, the tracking started before 30 days.
+	unused.Length().Equal(3)
+	unused.Element(0).Object().ValueEqual("path", "/legacy").ValueEqual("hits", 0)
+	unused.Element(1).Object().ValueEqual("path", "/report").ValueEqual("hits", 0)
+	unused.Element(2).Object().ValueEqual("path", "/users/{id}").ValueEqual("hits", 2)
+
+	e.GET("/report").WithQuery("days", 30).WithQuery("format", "text").Expect().
+		Status(httptest.StatusOK).Body().Contains("/legacy").Contains("never").NotContains("/users\t")
+
+	// Persistence.
+	var buf bytes.Buffer
+	if err := usage.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	restored := routeusage.New()
+	if err := restored.Load(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := usage.Usage("GET/users/{id}"), restored.Usage("GET/users/{id}"); expected.Hits != got.Hits || !expected.LastHit.Equal(got.LastHit) {
+		t.Fatalf("expected: %#+v but got: %#+v", expected, got)
+	}
+
+	if !restored.Since().Equal(usage.Since()) {
+		t.Fatalf("expected since: %s but got: %s", usage.Since(), restored.Since())
+	}
+
+	filename := filepath.Join(t.TempDir(), "usage.json")
+	stop, err := usage.Persist(filename, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	restored = routeusage.New()
+	if stop, err = restored.Persist(filename, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	defer stop()
+
+	if expected, got := uint64(2), restored.Usage("GET/users/{id}").Hits; expected != got {
+		t.Fatalf("expected %d hits but got: %d", expected, got)
+	}
+
+	var report strings.Builder
+	restored.WriteReport(&report, app.GetRoutesReadOnly(), 0)
+	if !strings.Contains(report.String(), "/users/{id}") {
+		t.Fatalf("unexpected report:\n%s", report.String())
+	}
+}
@@ -0,0 +1,274 @@
+// Package signedurl implements session-independent, HMAC-signed URLs
+// with an expiration time and optional one-time usage,
+// e.g. for password reset, file download and unsubscribe links.
+package signedurl
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/kataras/iris/v12/context"
+)
+
+func init() {
+	context.SetHandlerName("iris/middleware/signedurl.*", "iris.signedurl")
+	context.SetHandlerPolicy("iris.signedurl", context.PolicyAuth)
+}
+
+// The reserved URL query parameters of a signed URL.
+const (
+	ExpiresParam   = "expires"
+	NonceParam     = "nonce"
+	SignatureParam = "signature"
+)
+
+var (
+	// ErrInvalidSignature is returned when the URL's signature is missing
+	// or it does not match the URL.
+	ErrInvalidSignature = errors.New("signedurl: invalid signature")
+	// ErrExpired is returned when the URL's expiration time has passed.
+	ErrExpired = errors.New("signedurl: expired")
+	// ErrUsed is returned when a one-time URL was already used.
+	ErrUsed = errors.New("signedurl: already used")
+	// ErrReservedClaim is returned by `Sign` when a claim key is one
+	// of the reserved URL query parameters.
+	ErrReservedClaim = errors.New("signedurl: reserved claim key")
+)
+
+// Claims holds the custom key-value data of a signed URL,
+// they are stored as URL query parameters.
+// See `Get` package-level function.
+type Claims map[string]string
+
+// Store keeps the used nonces of the one-time URLs.
+// See `Signer.Store` field.
+type Store interface {
+	// Consume should mark the "nonce" as used and report whether
+	// it was not used before. The "expiresAt" is the time
+	// the nonce can be safely removed from the store.
+	Consume(nonce string, expiresAt time.Time) bool
+}
+
+// Signer signs and verifies URLs.
+// Create a new Signer through the `New` package-level function.
+type Signer struct {
+	// OneTime, if true, then signed URLs are
+	// accepted only once, see `Store` field too.
+	//
+	// Defaults to false.
+	OneTime bool
+	// Store keeps the used nonces of the one-time URLs.
+	// Set it to a shared store when running on multiple instances.
+	//
+	// Defaults to an in-memory store.
+	Store Store
+	// Clock returns the current time.
+	//
+	// Defaults to the `context.SystemClock`.
+	Clock func() time.Time
+	// ErrorHandler is fired by the `Handler` method on verification failures.
+	//
+	// Defaults to 403 Forbidden status code on invalid signatures
+	// and to 410 Gone on expired or used URLs.
+	ErrorHandler func(ctx *context.Context, err error)
+
+	secret []byte
+}
+
+// New returns a new URL Signer based on the given "secret" key.
+//
+// Usage:
+//  signer := signedurl.New([]byte("secret"))
+//  signer.OneTime = true
+//  link, _ := signer.Sign("/reset-password", time.Hour, signedurl.Claims{"user": "kataras"})
+//  app.Get("/reset-password", signer.Handler, func(ctx iris.Context) {
+//   claims := signedurl.Get(ctx)
+//  })
+func New(secret []byte) *Signer {
+	s := &Signer{
+		secret: secret,
+		ErrorHandler: func(ctx *context.Context, err error) {
+			code := 403
+			if err == ErrExpired || err == ErrUsed {
+				code = 410
+			}
+
+			ctx.StopWithError(code, err)
+		},
+	}
+
+	store := NewMemoryStore()
+	store.Clock = s.now
+	s.Store = store
+
+	return s
+}
+
+func (s *Signer) now() time.Time {
+	if s.Clock == nil {
+		return context.SystemClock.Now()
+	}
+
+	return s.Clock()
+}
+
+// Sign returns the "path" signed with the given "claims",
+// valid for the given "expiry" duration.
+// The "path" may contain URL query parameters, they are signed too.
+func (s *Signer) Sign(path string, expiry time.Duration, claims Claims) (string, error) {
+	u, err := url.Parse(path)
+	if err != nil {
+		return "", err
+	}
+
+	query := u.Query()
+	for k, v := range claims {
+		if k == ExpiresParam || k == NonceParam || k == SignatureParam {
+			return "", fmt.Errorf("%w: %s", ErrReservedClaim, k)
+		}
+
+		query.Set(k, v)
+	}
+
+	nonce := make([]byte, 16)
+	if _, err = rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	query.Set(ExpiresParam, strconv.FormatInt(s.now().Add(expiry).Unix(), 10))
+	query.Set(NonceParam, base64.RawURLEncoding.EncodeToString(nonce))
+
+	payload := u.EscapedPath() + "?" + query.Encode()
+	return payload + "&" + SignatureParam + "=" + s.signature(payload), nil
+}
+
+func (s *Signer) signature(payload string) string {
+	h := hmac.New(sha256.New, s.secret)
+	h.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+}
+
+// Verify reports whether the "u" URL is a valid signed one
+// and returns its claims.
+func (s *Signer) Verify(u *url.URL) (Claims, error) {
+	query := u.Query()
+	signature := query.Get(SignatureParam)
+	if signature == "" {
+		return nil, ErrInvalidSignature
+	}
+	query.Del(SignatureParam)
+
+	payload := u.EscapedPath() + "?" + query.Encode()
+	if !hmac.Equal([]byte(signature), []byte(s.signature(payload))) {
+		return nil, ErrInvalidSignature
+	}
+
+	expires, err := strconv.ParseInt(query.Get(ExpiresParam), 10, 64)
+	if err != nil {
+		return nil, ErrInvalidSignature
+	}
+
+	expiresAt := time.Unix(expires, 0)
+	if s.now().After(expiresAt) {
+		return nil, ErrExpired
+	}
+
+	if s.OneTime && !s.Store.Consume(query.Get(NonceParam), expiresAt) {
+		return nil, ErrUsed
+	}
+
+	claims := make(Claims, len(query))
+	for k := range query {
+		if k == ExpiresParam || k == NonceParam {
+			continue
+		}
+
+		claims[k] = query.Get(k)
+	}
+
+	return claims, nil
+}
+
+const claimsContextKey = "iris.signedurl.claims"
+
+// Handler is the verification middleware.
+// It fires the next handler only when the request URL is a valid signed one.
+// The next handlers can retrieve the URL's claims through the `Get` package-level function.
+func (s *Signer) Handler(ctx *context.Context) {
+	claims, err := s.Verify(ctx.Request().URL)
+	if err != nil {
+		s.ErrorHandler(ctx, err)
+		return
+	}
+
+	ctx.Values().Set(claimsContextKey, claims)
+	ctx.Next()
+}
+
+// Get returns the verified claims of the signed URL.
+// Returns nil if the `Signer.Handler` was not registered.
+func Get(ctx *context.Context) Claims {
+	if v := ctx.Values().Get(claimsContextKey); v != nil {
+		if claims, ok := v.(Claims); ok {
+			return claims
+		}
+	}
+
+	return nil
+}
+
+// MemoryStore is the default in-memory `Store`.
+type MemoryStore struct {
+	// Clock returns the current time, the `New` function
+	// sets it to the Signer's clock.
+	//
+	// Defaults to the `context.SystemClock`.
+	Clock func() time.Time
+
+	mu        sync.Mutex
+	nonces    map[string]time.Time
+	lastPurge time.Time
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+// NewMemoryStore returns a new in-memory Store.
+// Expired nonces are removed on `Consume` calls, at most once per minute.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{nonces: make(map[string]time.Time)}
+}
+
+// Consume completes the `Store` interface.
+func (s *MemoryStore) Consume(nonce string, expiresAt time.Time) bool {
+	now := context.SystemClock.Now()
+	if s.Clock != nil {
+		now = s.Clock()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.lastPurge) > time.Minute {
+		for n, exp := range s.nonces {
+			if now.After(exp) {
+				delete(s.nonces, n)
+			}
+		}
+		s.lastPurge = now
+	}
+
+	if _, used := s.nonces[nonce]; used {
+		return false
+	}
+
+	s.nonces[nonce] = expiresAt
+	return true
+}
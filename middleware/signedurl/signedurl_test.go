@@ -0,0 +1,99 @@
+package signedurl_test
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/httptest"
+	"github.com/kataras/iris/v12/middleware/signedurl"
+
+	"github.com/iris-contrib/httpexpect/v2"
+)
+
+func TestSignedURL(t *testing.T) {
+	now := time.Now()
+
+	app := iris.New()
+	app.URLSigner = signedurl.New([]byte("secret"))
+	app.URLSigner.OneTime = true
+	app.URLSigner.Clock = func() time.Time { return now }
+
+	app.Get("/unsubscribe/{id:int}", app.URLSigner.Handler, func(ctx iris.Context) {
+		claims := signedurl.Get(ctx)
+		ctx.Writef("%d:%s", ctx.Params().GetIntDefault("id", 0), claims["email"])
+	}).Name = "unsubscribe"
+
+	link, err := app.SignURL("unsubscribe", []interface{}{42}, time.Hour, signedurl.Claims{"email": "kataras2006@hotmail.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expired, err := app.SignURL("unsubscribe", []interface{}{42}, -time.Second, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = app.SignURL("unsubscribe", []interface{}{42}, time.Hour, signedurl.Claims{signedurl.NonceParam: "x"}); err == nil {
+		t.Fatalf("expected reserved claim error")
+	}
+
+	e := httptest.New(t, app)
+	get := func(link string) *httpexpect.Response {
+		parts := strings.SplitN(link, "?", 2)
+		return e.GET(parts[0]).WithQueryString(parts[1]).Expect()
+	}
+
+	e.GET("/unsubscribe/42").Expect().Status(httptest.StatusForbidden)
+	get(strings.Replace(link, "/42", "/43", 1)).Status(httptest.StatusForbidden)
+	get(link + "&email=other").Status(httptest.StatusForbidden)
+	get(expired).Status(httptest.StatusGone)
+	get(link).Status(httptest.StatusOK).Body().Equal("42:kataras2006@hotmail.com")
+	get(link).Status(httptest.StatusGone) // one-time.
+}
+
+func TestSignedURLZeroClock(t *testing.T) {
+	var signer signedurl.Signer // no Clock.
+
+	link, err := signer.Sign("/download?file=report.pdf", time.Minute, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	u, err := url.Parse(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, err := signer.Verify(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := "report.pdf", claims["file"]; expected != got {
+		t.Fatalf("expected claim: %s but got: %s", expected, got)
+	}
+}
+
+func TestMemoryStoreClock(t *testing.T) {
+	now := time.Now().Add(-24 * time.Hour)
+
+	store := signedurl.NewMemoryStore()
+	store.Clock = func() time.Time { return now }
+
+	if !store.Consume("a", now.Add(time.Minute)) {
+		t.Fatalf("expected the first consume to succeed")
+	}
+
+	if store.Consume("a", now.Add(time.Minute)) {
+		t.Fatalf("expected the nonce to be used")
+	}
+
+	// purged through the store's clock.
+	now = now.Add(2 * time.Minute)
+	if !store.Consume("b", now.Add(time.Minute)) || !store.Consume("a", now.Add(time.Minute)) {
+		t.Fatalf("expected the expired nonce to be removed")
+	}
+}
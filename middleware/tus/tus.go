@@ -0,0 +1,135 @@
+// Package tus implements a file system storage for the uploads of the
+// tus resumable upload protocol handler, see the `Party.Tus` method.
+package tus
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/kataras/iris/v12/core/router"
+)
+
+// DirStore is a `router.TusStore` which keeps the uploads in a directory.
+// Each upload is stored as a "$id" file and its information as a "$id.info" JSON file.
+type DirStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+var _ router.TusStore = (*DirStore)(nil)
+
+// NewDirStore returns a new `DirStore` which stores the uploads to the "dir" directory.
+// The directory is created if it does not exist.
+func NewDirStore(dir string) *DirStore {
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		panic(err)
+	}
+
+	return &DirStore{dir: dir}
+}
+
+// Path returns the file path of an upload.
+func (s *DirStore) Path(id string) string {
+	return filepath.Join(s.dir, filepath.Base(id))
+}
+
+func (s *DirStore) saveInfo(upload router.TusUpload) error {
+	b, err := json.Marshal(upload)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(s.Path(upload.ID)+".info", b, 0600)
+}
+
+// Create completes the `router.TusStore` interface.
+func (s *DirStore) Create(upload router.TusUpload) error {
+	f, err := os.OpenFile(s.Path(upload.ID), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	f.Close()
+
+	s.mu.Lock()
+	err = s.saveInfo(upload)
+	s.mu.Unlock()
+	return err
+}
+
+// Get completes the `router.TusStore` interface.
+func (s *DirStore) Get(id string) (router.TusUpload, error) {
+	s.mu.Lock()
+	upload, err := s.getInfo(id)
+	s.mu.Unlock()
+	if os.IsNotExist(err) {
+		err = router.ErrTusUploadNotFound
+	}
+
+	return upload, err
+}
+
+// WriteChunk completes the `router.TusStore` interface.
+func (s *DirStore) WriteChunk(id string, offset int64, r io.Reader) (int64, error) {
+	f, err := os.OpenFile(s.Path(id), os.O_WRONLY, 0600)
+	if err != nil {
+		if os.IsNotExist(err) {
+			err = router.ErrTusUploadNotFound
+		}
+
+		return 0, err
+	}
+
+	var n int64
+	if _, err = f.Seek(offset, io.SeekStart); err == nil {
+		n, err = io.Copy(f, r)
+	}
+
+	if cErr := f.Close(); err == nil {
+		err = cErr
+	}
+
+	if n > 0 {
+		s.mu.Lock()
+		upload, gErr := s.getInfo(id)
+		if gErr == nil {
+			upload.Offset = offset + n
+			gErr = s.saveInfo(upload)
+		}
+		s.mu.Unlock()
+
+		if err == nil {
+			err = gErr
+		}
+	}
+
+	return n, err
+}
+
+func (s *DirStore) getInfo(id string) (upload router.TusUpload, err error) {
+	b, err := os.ReadFile(s.Path(id) + ".info")
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(b, &upload)
+	return
+}
+
+// Delete completes the `router.TusStore` interface.
+func (s *DirStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.Remove(s.Path(id) + ".info"); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	if err := os.Remove(s.Path(id)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,157 @@
+package mvc
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kataras/iris/v12/cache/client"
+	"github.com/kataras/iris/v12/context"
+)
+
+// ActionCache holds the cached responses of a controller's methods,
+// see `ControllerActivator.CacheFor` and `CacheFor` option.
+//
+// Mutating methods of the same controller can invalidate
+// the cached responses through the `GetActionCache` package-level function.
+type ActionCache struct {
+	prefix string
+
+	mu       sync.RWMutex
+	handlers map[string]*client.Handler // key = method name.
+}
+
+func newActionCache(controllerName string) *ActionCache {
+	return &ActionCache{
+		prefix:   controllerName + ".",
+		handlers: make(map[string]*client.Handler),
+	}
+}
+
+func (a *ActionCache) add(funcName string, expiration time.Duration) *client.Handler {
+	h := client.NewHandler(expiration)
+
+	a.mu.Lock()
+	a.handlers[funcName] = h
+	a.mu.Unlock()
+
+	return h
+}
+
+func (a *ActionCache) get(funcName string) *client.Handler {
+	a.mu.RLock()
+	h := a.handlers[funcName]
+	a.mu.RUnlock()
+	return h
+}
+
+// Key returns the cache key of the "funcName" method's response
+// for the given arguments, the path parameter values of the route in order,
+// including the ones of the parent Party.
+func (a *ActionCache) Key(funcName string, args ...interface{}) string {
+	var b strings.Builder
+	b.WriteString(a.prefix)
+	b.WriteString(funcName)
+	for _, arg := range args {
+		b.WriteByte('/')
+		b.WriteString(fmt.Sprint(arg))
+	}
+
+	return b.String()
+}
+
+func (a *ActionCache) requestKey(ctx *context.Context, funcName string) string {
+	params := ctx.Params().Store
+	args := make([]interface{}, 0, len(params))
+	for _, p := range params {
+		args = append(args, p.String())
+	}
+
+	key := a.Key(funcName, args...)
+	if query := ctx.Request().URL.Query(); len(query) > 0 {
+		key += "?" + query.Encode() // sorted by key.
+	}
+
+	return key
+}
+
+// Invalidate removes the cached responses of the "funcName" method
+// for the given arguments (path parameter values), with any URL query.
+// If no arguments are given then all cached responses of that method are removed.
+//
+// Example Code:
+//  func (c *UserController) PutBy(id int64, u User) error {
+//      // [update the user...]
+//      mvc.GetActionCache(c.Ctx).Invalidate("GetBy", id)
+//  }
+func (a *ActionCache) Invalidate(funcName string, args ...interface{}) {
+	h := a.get(funcName)
+	if h == nil {
+		return
+	}
+
+	if len(args) == 0 {
+		h.InvalidateFunc(nil)
+		return
+	}
+
+	key := a.Key(funcName, args...)
+	h.InvalidateFunc(func(k string) bool {
+		return k == key || strings.HasPrefix(k, key+"?")
+	})
+}
+
+// InvalidateAll removes all cached responses of the controller.
+func (a *ActionCache) InvalidateAll() {
+	a.mu.RLock()
+	for _, h := range a.handlers {
+		h.InvalidateFunc(nil)
+	}
+	a.mu.RUnlock()
+}
+
+func (a *ActionCache) wrap(funcName string, handler context.Handler) context.Handler {
+	h := a.get(funcName)
+	cached := handler
+	if h != nil {
+		cached = h.Wrap(handler)
+	}
+
+	return func(ctx *context.Context) {
+		ctx.Values().Set(actionCacheContextKey, a)
+		if h != nil {
+			client.SetKey(ctx, a.requestKey(ctx, funcName))
+		}
+
+		cached(ctx)
+	}
+}
+
+const actionCacheContextKey = "iris.mvc.cache"
+
+// GetActionCache returns the `ActionCache` of the current request's controller.
+// It returns nil if the controller has no cached methods.
+func GetActionCache(ctx *context.Context) *ActionCache {
+	if v := ctx.Values().Get(actionCacheContextKey); v != nil {
+		if a, ok := v.(*ActionCache); ok {
+			return a
+		}
+	}
+
+	return nil
+}
+
+// CacheFor returns an `Option` which caches the responses of the given
+// controller's methods for "expiration" duration,
+// exactly like the `ControllerActivator.CacheFor` method does.
+//
+// Usage:
+//  m.Handle(new(UserController), mvc.CacheFor(time.Minute, "Get", "GetBy"))
+func CacheFor(expiration time.Duration, funcNames ...string) OptionFunc {
+	return func(c *ControllerActivator) {
+		for _, funcName := range funcNames {
+			c.CacheFor(funcName, expiration)
+		}
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 
+	"github.com/kataras/iris/v12/cache/client"
 	"github.com/kataras/iris/v12/context"
 	"github.com/kataras/iris/v12/core/router"
 	"github.com/kataras/iris/v12/hero"
@@ -39,6 +41,8 @@ type shared interface {
 type BeforeActivation interface {
 	shared
 	Dependencies() *hero.Container
+	CacheFor(funcName string, expiration time.Duration) *client.Handler
+	Timeout(funcName string, timeout time.Duration)
 }
 
 // AfterActivation is being used as the only one input argument of a
@@ -85,6 +89,11 @@ type ControllerActivator struct {
 	// Look the `Use` method too.
 	BeginHandlers context.Handlers
 
+	// the cached responses of the controller's methods, see `CacheFor`.
+	actionCache *ActionCache
+	// the timeouts of the controller's methods, see `Timeout`.
+	timeouts map[string]*actionTimeout
+
 	// true if this controller listens and serves to websocket events.
 	servesWebsocket bool
 
@@ -252,6 +261,27 @@ func (c *ControllerActivator) Dependencies() *hero.Container {
 }
 
 // checks if a method is already registered.
+// CacheFor caches the responses of the "funcName" controller's method
+// for "expiration" duration. The cache entries are keyed by the method's
+// path parameter values (its input arguments) and the URL query.
+// Use the `GetActionCache` package-level function to invalidate them
+// from the mutating methods of the same controller.
+//
+// It should be called before the `Handle` of the same method, e.g. inside the `BeforeActivation`.
+// It returns the cache handler, its rules can be modified through its `AddRule` method.
+//
+// Usage:
+//  func (c *UserController) BeforeActivation(b mvc.BeforeActivation) {
+//      b.CacheFor("GetBy", 5*time.Minute)
+//  }
+func (c *ControllerActivator) CacheFor(funcName string, expiration time.Duration) *client.Handler {
+	if c.actionCache == nil {
+		c.actionCache = newActionCache(c.fullName)
+	}
+
+	return c.actionCache.add(funcName, expiration)
+}
+
 func (c *ControllerActivator) isReservedMethod(name string) bool {
 	for methodName := range c.routes {
 		if methodName == name {
@@ -442,6 +472,15 @@ func (c *ControllerActivator) saveRoutes(funcName string, routes []*router.Route
 }
 
 func (c *ControllerActivator) handlerOf(relPath, methodName string) context.Handler {
+	handler := c.actionHandler(methodName, c.methodHandlerOf(relPath, methodName))
+	if c.actionCache != nil {
+		return c.actionCache.wrap(methodName, handler)
+	}
+
+	return handler
+}
+
+func (c *ControllerActivator) methodHandlerOf(relPath, methodName string) context.Handler {
 	c.attachInjector()
 
 	fullpath := c.app.Router.GetRelPath() + relPath
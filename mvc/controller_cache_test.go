@@ -0,0 +1,66 @@
+// black-box testing
+package mvc_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/httptest"
+	"github.com/kataras/iris/v12/mvc"
+)
+
+type testControllerCache struct {
+	Ctx   iris.Context
+	Calls map[string]int
+}
+
+func (c *testControllerCache) BeforeActivation(b mvc.BeforeActivation) {
+	b.CacheFor("GetBy", time.Minute)
+}
+
+func (c *testControllerCache) GetBy(id int64) string {
+	c.Calls[c.Ctx.Path()]++
+	return fmt.Sprintf("%d:%d:%s", id, c.Calls[c.Ctx.Path()], c.Ctx.URLParam("lang"))
+}
+
+func (c *testControllerCache) Get() string {
+	c.Calls["list"]++
+	return fmt.Sprint(c.Calls["list"])
+}
+
+func (c *testControllerCache) PutBy(id int64) {
+	mvc.GetActionCache(c.Ctx).Invalidate("GetBy", id)
+}
+
+func (c *testControllerCache) Delete() {
+	mvc.GetActionCache(c.Ctx).InvalidateAll()
+}
+
+func TestControllerCache(t *testing.T) {
+	calls := make(map[string]int)
+
+	app := iris.New()
+	m := mvc.New(app.Party("/users"))
+	m.Register(calls)
+	m.Handle(new(testControllerCache), mvc.CacheFor(time.Minute, "Get"))
+
+	e := httptest.New(t, app)
+	e.GET("/users/1").Expect().Status(httptest.StatusOK).Body().Equal("1:1:")
+	e.GET("/users/1").Expect().Status(httptest.StatusOK).Body().Equal("1:1:")
+	e.GET("/users/1").WithQuery("lang", "en").Expect().Status(httptest.StatusOK).Body().Equal("1:2:en")
+	e.GET("/users/2").Expect().Status(httptest.StatusOK).Body().Equal("2:1:")
+	e.GET("/users").Expect().Status(httptest.StatusOK).Body().Equal("1")
+	e.GET("/users").Expect().Status(httptest.StatusOK).Body().Equal("1")
+
+	// Invalidates /users/1 with any query.
+	e.PUT("/users/1").Expect().Status(httptest.StatusOK)
+	e.GET("/users/1").Expect().Status(httptest.StatusOK).Body().Equal("1:3:")
+	e.GET("/users/1").WithQuery("lang", "en").Expect().Status(httptest.StatusOK).Body().Equal("1:4:en")
+	e.GET("/users/2").Expect().Status(httptest.StatusOK).Body().Equal("2:1:")
+
+	e.DELETE("/users").Expect().Status(httptest.StatusOK)
+	e.GET("/users/2").Expect().Status(httptest.StatusOK).Body().Equal("2:2:")
+	e.GET("/users").Expect().Status(httptest.StatusOK).Body().Equal("2")
+}
@@ -218,6 +218,9 @@ func (p *methodParser) parsePathParam(path string, w string, funcArgPos int) (st
 			return "", 0, errors.New("no trailing path parameter found")
 		}
 		m = trailings[0]
+	} else if vm := p.macros.GetByValueType(typ.In(funcArgPos)); vm != nil {
+		// a typed ID parameter, e.g. GetBy(id UserID).
+		m = vm
 	} else {
 		// validMacros := p.macros.LookupForGoType(goType)
 
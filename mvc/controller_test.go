@@ -10,6 +10,7 @@ import (
 	"github.com/kataras/iris/v12/core/router"
 	"github.com/kataras/iris/v12/hero"
 	"github.com/kataras/iris/v12/httptest"
+	"github.com/kataras/iris/v12/macro"
 
 	. "github.com/kataras/iris/v12/mvc"
 )
@@ -759,3 +760,29 @@ func (c *testControllerFieldErrorHandlerContinue) HandleError(ctx iris.Context,
 func (c *testControllerFieldErrorHandlerContinue) PostTestField() string {
 	return fmt.Sprintf("%s is %d years old\n", c.Form.Username, c.Form.Age)
 }
+
+type testUserID string
+
+type testControllerTypedID struct{}
+
+func (c *testControllerTypedID) GetBy(id testUserID) string {
+	return "user:" + string(id)
+}
+
+func TestControllerTypedID(t *testing.T) {
+	app := iris.New()
+	app.Macros().RegisterID("user_id", macro.IDFormat{Prefix: "usr_"}, func(id string) (testUserID, error) {
+		return testUserID(id[len("usr_"):]), nil
+	})
+
+	New(app.Party("/users")).Handle(new(testControllerTypedID))
+	app.ConfigureContainer().Get("/accounts/{id:user_id else 400}", func(id testUserID) string {
+		return "account:" + string(id)
+	})
+
+	e := httptest.New(t, app)
+	e.GET("/users/usr_4f9a2c01").Expect().Status(httptest.StatusOK).Body().Equal("user:4f9a2c01")
+	e.GET("/users/4f9a2c01").Expect().Status(httptest.StatusNotFound)
+	e.GET("/accounts/usr_4f9a2c01").Expect().Status(httptest.StatusOK).Body().Equal("account:4f9a2c01")
+	e.GET("/accounts/usr_").Expect().Status(httptest.StatusBadRequest)
+}
@@ -0,0 +1,82 @@
+// black-box testing
+package mvc_test
+
+import (
+	stdContext "context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/httptest"
+	"github.com/kataras/iris/v12/middleware/recover"
+	"github.com/kataras/iris/v12/mvc"
+)
+
+type testControllerTimeout struct {
+	Ctx iris.Context
+}
+
+func (c *testControllerTimeout) BeforeActivation(b mvc.BeforeActivation) {
+	b.Timeout("GetSlow", 20*time.Millisecond)
+}
+
+func (c *testControllerTimeout) GetSlow() string {
+	<-c.Ctx.Request().Context().Done()
+	return "late"
+}
+
+func (c *testControllerTimeout) GetFast() string {
+	return "fast"
+}
+
+func (c *testControllerTimeout) GetPanic() {
+	panic("boom")
+}
+
+func TestControllerTimeout(t *testing.T) {
+	app := iris.New()
+
+	var recovered *mvc.ActionPanicError
+	app.Use(func(ctx iris.Context) {
+		recover.New()(ctx)
+		if err, ok := ctx.IsRecovered(); ok {
+			recovered, _ = err.Cause.(*mvc.ActionPanicError)
+		}
+	})
+
+	var (
+		c          *mvc.ControllerActivator
+		handledErr error
+	)
+	m := mvc.New(app)
+	m.HandleError(func(ctx *context.Context, err error) {
+		handledErr = err
+		ctx.WriteString("timeout")
+	})
+	m.Handle(new(testControllerTimeout), mvc.Timeout(time.Minute, "GetFast"), mvc.OptionFunc(func(a *mvc.ControllerActivator) {
+		c = a
+	}))
+
+	e := httptest.New(t, app)
+	e.GET("/slow").Expect().Status(httptest.StatusServiceUnavailable).Body().Equal("timeout")
+	e.GET("/fast").Expect().Status(httptest.StatusOK).Body().Equal("fast")
+
+	var timeoutErr *mvc.ActionTimeoutError
+	if !errors.As(handledErr, &timeoutErr) || timeoutErr.Action != "GetSlow" || !errors.Is(handledErr, stdContext.DeadlineExceeded) {
+		t.Fatalf("expected an action timeout error but got: %v", handledErr)
+	}
+
+	if expected, got := uint64(1), c.TimeoutOverruns("GetSlow"); expected != got {
+		t.Fatalf("expected %d overruns but got: %d", expected, got)
+	}
+	if expected, got := uint64(0), c.TimeoutOverruns("GetFast"); expected != got {
+		t.Fatalf("expected %d overruns but got: %d", expected, got)
+	}
+
+	e.GET("/panic").Expect().Status(httptest.StatusInternalServerError)
+	if recovered == nil || recovered.Action != "GetPanic" || recovered.Value != "boom" {
+		t.Fatalf("expected an action panic error but got: %#+v", recovered)
+	}
+}
@@ -0,0 +1,159 @@
+package mvc
+
+import (
+	stdContext "context"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"sync/atomic"
+	"time"
+
+	"github.com/kataras/iris/v12/context"
+)
+
+// ActionTimeoutError is the error which is rendered through the controller's error handler
+// (see `Application.HandleError`) when a controller's method exceeds its timeout,
+// see `ControllerActivator.Timeout` and `Timeout` option.
+// The response status code is set to 503 Service Unavailable before the error handler runs.
+type ActionTimeoutError struct {
+	// Controller is the full name of the controller, e.g. "user.Controller".
+	Controller string
+	// Action is the method name, e.g. "GetBy".
+	Action  string
+	Timeout time.Duration
+}
+
+// Error completes the error interface.
+func (e *ActionTimeoutError) Error() string {
+	return fmt.Sprintf("mvc: %s.%s: exceeded the %s timeout", e.Controller, e.Action, e.Timeout)
+}
+
+// Is reports whether the "err" is a `context.DeadlineExceeded`.
+func (e *ActionTimeoutError) Is(err error) bool {
+	return err == stdContext.DeadlineExceeded
+}
+
+// ActionPanicError is the value which a controller's method panic is re-thrown with,
+// so the central recovery (e.g. the recover middleware and its `context.ErrPanicRecovery.Cause`)
+// can report the controller and the method which panicked.
+type ActionPanicError struct {
+	// Controller is the full name of the controller, e.g. "user.Controller".
+	Controller string
+	// Action is the method name, e.g. "GetBy".
+	Action string
+	// Value is the original panic value.
+	Value interface{}
+	// Stack is the stack trace of the original panic.
+	Stack []byte
+}
+
+// Error completes the error interface.
+func (e *ActionPanicError) Error() string {
+	return fmt.Sprintf("mvc: %s.%s: panic: %v", e.Controller, e.Action, e.Value)
+}
+
+// Unwrap returns the original panic value if it's an error.
+func (e *ActionPanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+type actionTimeout struct {
+	timeout  time.Duration
+	overruns uint64
+}
+
+// Timeout sets a timeout to the "funcName" controller's method.
+// The request's context (`ctx.Request().Context()`) is canceled
+// after the "timeout" and, if the method did not respond by then,
+// whatever it writes is discarded, the overrun is recorded (see `TimeoutOverruns`)
+// and an `ActionTimeoutError` is rendered through the controller's error handler instead.
+//
+// Should be called before serve-time, e.g. on `BeforeActivation`.
+func (c *ControllerActivator) Timeout(funcName string, timeout time.Duration) {
+	if c.timeouts == nil {
+		c.timeouts = make(map[string]*actionTimeout)
+	}
+
+	c.timeouts[funcName] = &actionTimeout{timeout: timeout}
+}
+
+// TimeoutOverruns returns the number of the requests
+// which the "funcName" controller's method exceeded its timeout.
+func (c *ControllerActivator) TimeoutOverruns(funcName string) uint64 {
+	if t, ok := c.timeouts[funcName]; ok {
+		return atomic.LoadUint64(&t.overruns)
+	}
+
+	return 0
+}
+
+// actionHandler wraps the "funcName" controller's method handler,
+// it applies the method's timeout and re-throws its panics as `ActionPanicError`.
+func (c *ControllerActivator) actionHandler(funcName string, handler context.Handler) context.Handler {
+	return func(ctx *context.Context) {
+		defer func() {
+			if v := recover(); v != nil {
+				if v == http.ErrAbortHandler {
+					panic(v)
+				}
+
+				if _, ok := v.(*ActionPanicError); ok {
+					panic(v)
+				}
+
+				panic(&ActionPanicError{
+					Controller: c.fullName,
+					Action:     funcName,
+					Value:      v,
+					Stack:      debug.Stack(),
+				})
+			}
+		}()
+
+		t, ok := c.timeouts[funcName]
+		if !ok || t.timeout <= 0 {
+			handler(ctx)
+			return
+		}
+
+		c.serveTimeout(ctx, funcName, t, handler)
+	}
+}
+
+func (c *ControllerActivator) serveTimeout(ctx *context.Context, funcName string, t *actionTimeout, handler context.Handler) {
+	r := ctx.Request()
+	timeoutCtx, cancel := stdContext.WithTimeout(r.Context(), t.timeout)
+	defer cancel()
+
+	ctx.ResetRequest(r.WithContext(timeoutCtx))
+	defer ctx.ResetRequest(r)
+
+	ctx.Record()
+	handler(ctx)
+
+	if timeoutCtx.Err() != stdContext.DeadlineExceeded {
+		return
+	}
+
+	atomic.AddUint64(&t.overruns, 1)
+	err := &ActionTimeoutError{Controller: c.fullName, Action: funcName, Timeout: t.timeout}
+	c.Router().Logger().Warn(err)
+
+	ctx.Rollback()
+	ctx.StatusCode(http.StatusServiceUnavailable)
+	c.injector.Container.GetErrorHandler(ctx).HandleError(ctx, err)
+}
+
+// Timeout returns an `Option` which sets a timeout to the given
+// controller's methods, exactly like the `ControllerActivator.Timeout` method does.
+//
+// Usage:
+//  m.Handle(new(ReportController), mvc.Timeout(2*time.Second, "Get", "GetBy"))
+func Timeout(timeout time.Duration, funcNames ...string) OptionFunc {
+	return func(c *ControllerActivator) {
+		for _, funcName := range funcNames {
+			c.Timeout(funcName, timeout)
+		}
+	}
+}
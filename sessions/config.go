@@ -12,6 +12,10 @@ import (
 const (
 	// DefaultCookieName the secret cookie's name for sessions
 	DefaultCookieName = "irissessionid"
+	// DefaultFlashViewDataKey the default view data key of the flash messages.
+	DefaultFlashViewDataKey = "flash"
+	// DefaultLockTimeout the default maximum duration a request waits for its session's lock.
+	DefaultLockTimeout = 10 * time.Second
 )
 
 type (
@@ -54,16 +58,63 @@ type (
 		// Defaults to infinitive/unlimited life duration(0).
 		Expires time.Duration
 
+		// Sliding, if true, renews the session's expiration to the "Expires" duration
+		// on each request of the session (sliding window),
+		// so only idle sessions expire.
+		//
+		// Defaults to false.
+		Sliding bool
+
+		// MaxLifetime is the absolute maximum lifetime of a session since its creation,
+		// the session expires after that, even if it is active, e.g. with "Sliding" renewals.
+		// Note that the creation time of a session restored from a Database
+		// is the time it was loaded by the current application's process.
+		//
+		// Defaults to zero, no maximum lifetime.
+		MaxLifetime time.Duration
+
 		// SessionIDGenerator can be set to a function which
 		// return a unique session id.
 		// By default we will use a uuid impl package to generate
 		// that, but developers can change that with simple assignment.
 		SessionIDGenerator func(ctx *context.Context) string
 
+		// Lock, if true, locks the session for the whole request's lifecycle,
+		// so concurrent requests of the same session (e.g. AJAX calls) are serialized
+		// and their writes can not overwrite each other.
+		// The sessions are locked in-memory, a `Database` which implements
+		// the `Locker` interface locks them across processes, see `UseLocker` too.
+		// A request which waits more than the "LockTimeout" for the lock
+		// is stopped with 503 Service Unavailable.
+		//
+		// Defaults to false.
+		Lock bool
+
+		// LockTimeout is the maximum duration a request waits for its session's lock,
+		// see "Lock".
+		//
+		// Defaults to 10 seconds.
+		LockTimeout time.Duration
+
 		// DisableSubdomainPersistence set it to true in order dissallow your subdomains to have access to the session cookie
 		//
 		// Defaults to false.
 		DisableSubdomainPersistence bool
+
+		// DiscardUnreadFlashes, if true, removes the flash messages
+		// after exactly one subsequent request, even if they were not read,
+		// e.g. a message set before a redirect is only shown on the redirected page.
+		// By default a flash message is kept until it's read.
+		//
+		// Defaults to false.
+		DiscardUnreadFlashes bool
+
+		// FlashViewDataKey is the view data key (see `Context.ViewData`)
+		// which the flash messages of the request's session are exposed to the templates,
+		// as a `Flashes` value, by the `Sessions.Handler` middleware.
+		//
+		// Defaults to "flash".
+		FlashViewDataKey string
 	}
 )
 
@@ -77,6 +128,14 @@ func (c Config) Validate() Config {
 		c.Cookie = DefaultCookieName
 	}
 
+	if c.FlashViewDataKey == "" {
+		c.FlashViewDataKey = DefaultFlashViewDataKey
+	}
+
+	if c.LockTimeout <= 0 {
+		c.LockTimeout = DefaultLockTimeout
+	}
+
 	if c.SessionIDGenerator == nil {
 		c.SessionIDGenerator = func(*context.Context) string {
 			id, _ := uuid.NewRandom()
@@ -0,0 +1,596 @@
+package sessions
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/gob"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kataras/iris/v12/context"
+
+	"github.com/kataras/golog"
+)
+
+const (
+	// DefaultCookieChunkSize is the default `CookieStore.ChunkSize`.
+	DefaultCookieChunkSize = 2048
+	// DefaultCookieMaxChunks is the default `CookieStore.MaxChunks`.
+	DefaultCookieMaxChunks = 8
+)
+
+// ErrCookieStoreTooLarge is returned by the `CookieStore` when the session data
+// do not fit in its `MaxChunks` cookies.
+var ErrCookieStoreTooLarge = errors.New("sessions: cookie store: session data are too large")
+
+// CookieStore is a stateless session `Database`, the session values are kept
+// on the client, serialized into signed and optionally encrypted cookies,
+// so no server-side storage is required and any instance of the application
+// can serve any request, e.g. on fully stateless deployments.
+//
+// The session data larger than the "ChunkSize" are split into more cookies,
+// named after the session's cookie: "<Cookie>_data", "<Cookie>_data_1" and so on,
+// which are reassembled on read. Every change of the session values
+// updates the response cookies, so the values should be modified before the response is written.
+//
+// The server keeps the session in memory for the duration of its request(s) only,
+// the `Sessions.Handler` middleware is required.
+// Flash messages are not stored on the cookies.
+//
+// Create a new CookieStore through the `NewCookieStore` package-level function.
+type CookieStore struct {
+	// Transcoder serializes the session values.
+	//
+	// Defaults to the `DefaultTranscoder`.
+	Transcoder Transcoder
+	// ChunkSize is the maximum length of the serialized session data
+	// stored per cookie, before their encoding.
+	// Note that the encoded cookie value is larger, up to about twice its size,
+	// and browsers limit a cookie to 4KB.
+	//
+	// Defaults to 2048.
+	ChunkSize int
+	// MaxChunks is the maximum number of cookies of a session,
+	// a change which exceeds it fails with `ErrCookieStoreTooLarge`.
+	//
+	// Defaults to 8.
+	MaxChunks int
+
+	encoding context.SecureCookie
+	logger   *golog.Logger
+
+	mu      sync.Mutex
+	entries map[string]*cookieEntry // the sessions of the in-flight requests.
+}
+
+var _ Database = (*CookieStore)(nil)
+
+// cookieEntry holds a session, and its requests, while it's served.
+type cookieEntry struct {
+	// the request which the changes are written to,
+	// the last bound one which is still served.
+	ctx     *context.Context
+	man     *Sessions
+	options []context.CookieOption
+	bound   []cookieBinding // the requests which serve the session.
+
+	values  map[string][]byte
+	expires time.Time
+	chunks  int  // the number of data cookies the client holds.
+	loaded  bool // restored from the request cookies.
+	dirty   bool // changed before a request was bound.
+	refs    int  // the number of the requests which serve the session.
+}
+
+// cookieBinding is a request bound to a session of the `CookieStore`.
+type cookieBinding struct {
+	ctx     *context.Context
+	man     *Sessions
+	options []context.CookieOption
+}
+
+// cookiePayload is the serialized form of the session data.
+type cookiePayload struct {
+	SID     string
+	Expires time.Time
+	Values  map[string][]byte
+}
+
+// NewCookieStore returns a new `CookieStore` which signs,
+// and optionally encrypts, the session cookies through the "encoding",
+// e.g. a `context.CookieCodec`: its block key encrypts the data too.
+//
+// Usage:
+//  codec, err := iris.NewCookieCodec(iris.CookieKey{Hash: hashKey, Block: blockKey})
+//  [handle err...]
+//  sess := sessions.New(sessions.Config{Cookie: "session"})
+//  sess.UseDatabase(sessions.NewCookieStore(codec))
+//  app.Use(sess.Handler())
+func NewCookieStore(encoding context.SecureCookie) *CookieStore {
+	if encoding == nil {
+		panic("sessions: cookie store: nil encoding")
+	}
+
+	return &CookieStore{
+		Transcoder: DefaultTranscoder,
+		ChunkSize:  DefaultCookieChunkSize,
+		MaxChunks:  DefaultCookieMaxChunks,
+		encoding:   encoding,
+		logger:     golog.Default,
+		entries:    make(map[string]*cookieEntry),
+	}
+}
+
+// SetLogger sets the logger once before server ran.
+func (st *CookieStore) SetLogger(logger *golog.Logger) {
+	st.logger = logger
+}
+
+// Acquire returns the lifetime of a session restored from the request cookies,
+// otherwise it begins a new empty one.
+func (st *CookieStore) Acquire(sid string, expires time.Duration) LifeTime {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	e, ok := st.entries[sid]
+	if ok && e.loaded {
+		return LifeTime{Time: e.expires}
+	}
+
+	if !ok {
+		e = new(cookieEntry)
+		st.entries[sid] = e
+	}
+
+	e.values = make(map[string][]byte)
+	e.expires = time.Time{}
+	if expires > 0 {
+		e.expires = time.Now().Add(expires)
+	}
+
+	return LifeTime{}
+}
+
+// OnUpdateExpiration updates the expiration of the session cookies.
+func (st *CookieStore) OnUpdateExpiration(sid string, newExpires time.Duration) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	e, ok := st.entries[sid]
+	if !ok {
+		return ErrNotFound
+	}
+
+	e.expires = time.Now().Add(newExpires)
+	return st.write(sid, e)
+}
+
+// Set sets a session value and updates the session cookies.
+// The "immutable" input argument is not supported.
+func (st *CookieStore) Set(sid string, key string, value interface{}, _ time.Duration, _ bool) error {
+	b, err := st.Transcoder.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	e, ok := st.entries[sid]
+	if !ok {
+		return ErrNotFound
+	}
+
+	prev, existed := e.values[key]
+	e.values[key] = b
+	if err = st.write(sid, e); err != nil {
+		// keep the values in sync with the client's cookies.
+		if existed {
+			e.values[key] = prev
+		} else {
+			delete(e.values, key)
+		}
+	}
+
+	return err
+}
+
+func (st *CookieStore) get(sid, key string) ([]byte, bool) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	e, ok := st.entries[sid]
+	if !ok {
+		return nil, false
+	}
+
+	b, ok := e.values[key]
+	return b, ok
+}
+
+// Get retrieves a session value based on the key.
+func (st *CookieStore) Get(sid string, key string) (value interface{}) {
+	if err := st.Decode(sid, key, &value); err != nil {
+		return nil
+	}
+
+	return
+}
+
+// Decode binds the "outPtr" to the value associated to the provided "key".
+func (st *CookieStore) Decode(sid, key string, outPtr interface{}) error {
+	b, ok := st.get(sid, key)
+	if !ok {
+		return ErrNotFound
+	}
+
+	return st.Transcoder.Unmarshal(b, outPtr)
+}
+
+// Visit loops through all session keys and values.
+func (st *CookieStore) Visit(sid string, cb func(key string, value interface{})) error {
+	st.mu.Lock()
+	e, ok := st.entries[sid]
+	if !ok {
+		st.mu.Unlock()
+		return ErrNotFound
+	}
+
+	values := make(map[string][]byte, len(e.values))
+	for key, b := range e.values {
+		values[key] = b
+	}
+	st.mu.Unlock()
+
+	for key, b := range values {
+		var value interface{}
+		if err := st.Transcoder.Unmarshal(b, &value); err != nil {
+			return err
+		}
+
+		cb(key, value)
+	}
+
+	return nil
+}
+
+// Len returns the length of the session's entries (keys).
+func (st *CookieStore) Len(sid string) int {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if e, ok := st.entries[sid]; ok {
+		return len(e.values)
+	}
+
+	return 0
+}
+
+// Delete removes a session value and updates the session cookies.
+func (st *CookieStore) Delete(sid string, key string) (deleted bool) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	e, ok := st.entries[sid]
+	if !ok {
+		return false
+	}
+
+	if _, deleted = e.values[key]; deleted {
+		delete(e.values, key)
+		if err := st.write(sid, e); err != nil {
+			st.logger.Debugf("sessions: cookie store: delete: %s: %v", sid, err)
+		}
+	}
+
+	return
+}
+
+// Clear removes all session values and the data cookies, it keeps the session.
+func (st *CookieStore) Clear(sid string) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	e, ok := st.entries[sid]
+	if !ok {
+		return ErrNotFound
+	}
+
+	e.values = make(map[string][]byte)
+	return st.write(sid, e)
+}
+
+// Release removes the session and its data cookies.
+func (st *CookieStore) Release(sid string) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	e, ok := st.entries[sid]
+	if !ok {
+		return nil
+	}
+
+	delete(st.entries, sid)
+	e.values = nil
+	return st.write(sid, e)
+}
+
+// Close does nothing, the sessions live on the client.
+func (st *CookieStore) Close() error {
+	return nil
+}
+
+// load restores the "sid" session from the request cookies,
+// it's called before the session manager starts the session.
+func (st *CookieStore) load(ctx *context.Context, man *Sessions, sid string, options []context.CookieOption) {
+	if sid == "" {
+		return
+	}
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if e, ok := st.entries[sid]; ok && e.refs > 0 {
+		return // served by another request, its values are the latest ones.
+	}
+
+	e := &cookieEntry{
+		ctx:     ctx,
+		man:     man,
+		options: options,
+		values:  make(map[string][]byte),
+	}
+
+	payload, chunks, err := st.decode(ctx, man)
+	e.chunks = chunks
+	if err != nil {
+		if chunks > 0 {
+			st.logger.Debugf("sessions: cookie store: %s: %v", sid, err)
+		}
+	} else if payload.SID == sid {
+		e.values = payload.Values
+		e.expires = payload.Expires
+		e.loaded = true
+	}
+
+	st.entries[sid] = e
+}
+
+// attach binds the request to the started "sess" session, so any change of it
+// updates the response cookies, see `bind`. The "requestSid" is the session ID
+// of the request's cookie, its data cookies are removed if a new session was started.
+// It returns the function which releases the session from the memory at the end of the request.
+func (st *CookieStore) attach(ctx *context.Context, requestSid string, sess *Session, options []context.CookieOption) func() {
+	sid := sess.ID()
+
+	st.mu.Lock()
+	if requestSid != "" && requestSid != sid {
+		if e, ok := st.entries[requestSid]; ok && e.refs == 0 {
+			delete(st.entries, requestSid)
+			e.values = nil
+			// the entry may be loaded by another request, write to this one.
+			e.ctx, e.man, e.options = ctx, sess.Man, options
+			st.write(requestSid, e)
+		}
+	}
+
+	if e, ok := st.entries[sid]; ok {
+		e.refs++
+		if e.loaded {
+			sess.mu.Lock()
+			sess.isNew = false
+			sess.mu.Unlock()
+		}
+	}
+	st.mu.Unlock()
+
+	st.bind(ctx, sess, options)
+	return func() {
+		st.release(ctx, sess)
+	}
+}
+
+// bind sets the request which the changes of the "sess" session are written to
+// and writes the changes made before, e.g. on `Session.Regenerate`.
+func (st *CookieStore) bind(ctx *context.Context, sess *Session, options []context.CookieOption) {
+	sid := sess.ID()
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	e, ok := st.entries[sid]
+	if !ok {
+		return
+	}
+
+	e.bound = append(e.bound, cookieBinding{ctx: ctx, man: sess.Man, options: options})
+	e.ctx, e.man, e.options = ctx, sess.Man, options
+	if e.dirty {
+		if err := st.write(sid, e); err != nil {
+			st.logger.Debugf("sessions: cookie store: %s: %v", sid, err)
+		}
+	}
+}
+
+// release unbinds the "ctx" request from the "sess" session,
+// so its changes are no longer written to that request, which is about to be released,
+// and removes the session from the memory when its last request is served.
+func (st *CookieStore) release(ctx *context.Context, sess *Session) {
+	sid := sess.ID()
+
+	st.mu.Lock()
+	e, ok := st.entries[sid]
+	if !ok {
+		st.mu.Unlock()
+		return
+	}
+
+	for i, b := range e.bound {
+		if b.ctx == ctx {
+			e.bound = append(e.bound[:i], e.bound[i+1:]...)
+			break
+		}
+	}
+
+	if n := len(e.bound); n > 0 {
+		last := e.bound[n-1]
+		e.ctx, e.man, e.options = last.ctx, last.man, last.options
+	} else {
+		e.ctx, e.man, e.options = nil, nil, nil
+	}
+
+	if e.refs--; e.refs > 0 {
+		st.mu.Unlock()
+		return
+	}
+
+	delete(st.entries, sid)
+	st.mu.Unlock()
+
+	sess.provider.forget(sess)
+}
+
+// write updates the response cookies of the "e" session,
+// the caller should hold the lock.
+func (st *CookieStore) write(sid string, e *cookieEntry) error {
+	if e.ctx == nil || e.man == nil {
+		e.dirty = true
+		return nil
+	}
+	e.dirty = false
+
+	var chunks []string
+	if len(e.values) > 0 {
+		var err error
+		if chunks, err = st.encode(e.man, cookiePayload{SID: sid, Expires: e.expires, Values: e.values}); err != nil {
+			return err
+		}
+	}
+
+	var expires time.Duration
+	if !e.expires.IsZero() {
+		if expires = time.Until(e.expires); expires <= 0 {
+			chunks = nil
+		}
+	}
+	expires = e.man.cookieLifetime(expires)
+
+	options := e.man.extraCookieOptions(e.options)
+	for i, value := range chunks {
+		e.ctx.UpsertCookie(e.man.newCookie(e.ctx, e.man.dataCookieName(i), value, expires), options...)
+	}
+
+	for i := len(chunks); i < e.chunks; i++ {
+		e.ctx.RemoveCookie(e.man.dataCookieName(i), options...)
+	}
+
+	e.chunks = len(chunks)
+	return nil
+}
+
+// encode serializes the "payload" and splits it into signed cookie values.
+// Each value is prefixed by a random identifier of this write
+// and the number of the values, so values of different writes are never mixed.
+func (st *CookieStore) encode(man *Sessions, payload cookiePayload) ([]string, error) {
+	data := new(bytes.Buffer)
+	if err := gob.NewEncoder(data).Encode(payload); err != nil {
+		return nil, err
+	}
+
+	n := (data.Len() + st.ChunkSize - 1) / st.ChunkSize
+	if n > st.MaxChunks {
+		return nil, ErrCookieStoreTooLarge
+	}
+
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	prefix := hex.EncodeToString(id) + "|" + strconv.Itoa(n) + "|"
+
+	b := data.Bytes()
+	values := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		end := (i + 1) * st.ChunkSize
+		if end > len(b) {
+			end = len(b)
+		}
+
+		value, err := st.encoding.Encode(man.dataCookieName(i), prefix+string(b[i*st.ChunkSize:end]))
+		if err != nil {
+			return nil, err
+		}
+
+		values = append(values, value)
+	}
+
+	return values, nil
+}
+
+// decode reassembles and verifies the session data of the request cookies.
+// It returns the number of the data cookies the request holds too.
+func (st *CookieStore) decode(ctx *context.Context, man *Sessions) (payload cookiePayload, chunks int, err error) {
+	var (
+		prefix string
+		n      int
+		data   strings.Builder
+	)
+
+	for ; ; chunks++ {
+		c, cErr := ctx.Request().Cookie(man.dataCookieName(chunks))
+		if cErr != nil {
+			break
+		}
+
+		if err != nil {
+			continue // just count the rest.
+		}
+
+		var value string
+		if err = st.encoding.Decode(c.Name, c.Value, &value); err != nil {
+			continue
+		}
+
+		// id|n|data.
+		parts := strings.SplitN(value, "|", 3)
+		if len(parts) != 3 || (prefix != "" && prefix != parts[0]+"|"+parts[1]) {
+			err = ErrDecryption
+			continue
+		}
+
+		if prefix == "" {
+			prefix = parts[0] + "|" + parts[1]
+			if n, err = strconv.Atoi(parts[1]); err != nil {
+				continue
+			}
+		}
+
+		data.WriteString(parts[2])
+	}
+
+	if err != nil {
+		return
+	}
+
+	if chunks == 0 || chunks < n {
+		err = ErrNotFound
+		return
+	}
+
+	err = gob.NewDecoder(strings.NewReader(data.String())).Decode(&payload)
+	return
+}
+
+// dataCookieName returns the name of the "i"th data cookie of a `CookieStore`.
+func (s *Sessions) dataCookieName(i int) string {
+	if i == 0 {
+		return s.config.Cookie + "_data"
+	}
+
+	return fmt.Sprintf("%s_data_%d", s.config.Cookie, i)
+}
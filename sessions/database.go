@@ -20,6 +20,7 @@ var ErrNotImplemented = errors.New("not implemented yet")
 // I want to protect you, believe me.
 // The scope of the database is to store somewhere the sessions in order to
 // keep them after restarting the server, nothing more.
+// The only exception is the `CookieStore`, for stateless deployments.
 //
 // Synchronization are made automatically, you can register one using `UseDatabase`.
 //
@@ -0,0 +1,149 @@
+package sessions
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrDecryption is returned by the `EncryptedTranscoder.Unmarshal`
+// when a value can not be decrypted by any of its keys.
+var ErrDecryption = errors.New("sessions: value can not be decrypted")
+
+// encryptedValuePrefix marks the values encrypted by an `EncryptedTranscoder`.
+var encryptedValuePrefix = []byte("\x00enc1")
+
+const encryptionKeyIDLen = 4
+
+type encryptionKey struct {
+	id   []byte
+	aead cipher.AEAD
+}
+
+// EncryptedTranscoder is a `Transcoder` which encrypts the serialized session values
+// with AES-GCM before they are stored to the database, regardless of the backend,
+// so personal data kept in the sessions are protected at rest.
+//
+// The first key encrypts new values and all of them are accepted to decrypt,
+// so keys can be rotated by prepending a new one and removing the oldest
+// after the sessions' lifetime. Each value holds a short identifier of its key.
+//
+// Create a new EncryptedTranscoder through the `NewEncryptedTranscoder` package-level function.
+type EncryptedTranscoder struct {
+	// Transcoder serializes the values before encryption.
+	Transcoder Transcoder
+	// AllowPlaintext, if true, decodes values which are not encrypted
+	// through the underline Transcoder, e.g. values stored
+	// before the encryption was enabled. Defaults to false.
+	AllowPlaintext bool
+
+	keys []encryptionKey
+}
+
+var _ Transcoder = (*EncryptedTranscoder)(nil)
+
+// NewEncryptedTranscoder returns a new `EncryptedTranscoder` which serializes the values
+// through the "transcoder" (if nil then the current `DefaultTranscoder` is used)
+// and encrypts them with the first of the "keys".
+// Each key should be 16, 24 or 32 bytes long to select AES-128, AES-192 or AES-256.
+//
+// Example Code:
+//  transcoder, err := sessions.NewEncryptedTranscoder(nil, newKey, oldKey)
+//  if err != nil { panic(err) }
+//  sessions.DefaultTranscoder = transcoder
+//
+//  sess.UseDatabase(redis.New(redis.DefaultConfig()))
+func NewEncryptedTranscoder(transcoder Transcoder, keys ...[]byte) (*EncryptedTranscoder, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("sessions: at least one encryption key is required")
+	}
+
+	if transcoder == nil {
+		transcoder = DefaultTranscoder
+	}
+
+	t := &EncryptedTranscoder{Transcoder: transcoder}
+	for i, k := range keys {
+		block, err := aes.NewCipher(k)
+		if err != nil {
+			return nil, fmt.Errorf("sessions: key[%d]: %w", i, err)
+		}
+
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("sessions: key[%d]: %w", i, err)
+		}
+
+		sum := sha256.Sum256(k)
+		t.keys = append(t.keys, encryptionKey{id: sum[:encryptionKeyIDLen], aead: aead})
+	}
+
+	return t, nil
+}
+
+// Marshal serializes the "value" through the underline Transcoder
+// and encrypts the result with the current (first) key.
+func (t *EncryptedTranscoder) Marshal(value interface{}) ([]byte, error) {
+	plaintext, err := t.Transcoder.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	key := t.keys[0]
+	nonceSize := key.aead.NonceSize()
+
+	b := make([]byte, 0, len(encryptedValuePrefix)+encryptionKeyIDLen+nonceSize+len(plaintext)+key.aead.Overhead())
+	b = append(b, encryptedValuePrefix...)
+	b = append(b, key.id...)
+
+	nonce := b[len(b) : len(b)+nonceSize]
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	b = b[:len(b)+nonceSize]
+
+	return key.aead.Seal(b, nonce, plaintext, encryptedValuePrefix), nil
+}
+
+// Unmarshal decrypts the "b" with the key which encrypted it
+// and deserializes the result to the "outPtr" through the underline Transcoder.
+func (t *EncryptedTranscoder) Unmarshal(b []byte, outPtr interface{}) error {
+	if !bytes.HasPrefix(b, encryptedValuePrefix) {
+		if t.AllowPlaintext {
+			return t.Transcoder.Unmarshal(b, outPtr)
+		}
+
+		return ErrDecryption
+	}
+
+	b = b[len(encryptedValuePrefix):]
+	if len(b) < encryptionKeyIDLen {
+		return ErrDecryption
+	}
+
+	id, b := b[:encryptionKeyIDLen], b[encryptionKeyIDLen:]
+	for _, key := range t.keys {
+		if !bytes.Equal(key.id, id) {
+			continue
+		}
+
+		nonceSize := key.aead.NonceSize()
+		if len(b) < nonceSize {
+			return ErrDecryption
+		}
+
+		plaintext, err := key.aead.Open(nil, b[:nonceSize], b[nonceSize:], encryptedValuePrefix)
+		if err != nil {
+			return ErrDecryption
+		}
+
+		return t.Transcoder.Unmarshal(plaintext, outPtr)
+	}
+
+	return ErrDecryption
+}
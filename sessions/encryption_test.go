@@ -0,0 +1,75 @@
+package sessions_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/kataras/iris/v12/sessions"
+)
+
+func TestEncryptedTranscoder(t *testing.T) {
+	oldKey := bytes.Repeat([]byte("o"), 32)
+	newKey := bytes.Repeat([]byte("n"), 16)
+
+	oldTranscoder, err := sessions.NewEncryptedTranscoder(sessions.GobTranscoder{}, oldKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rotated, err := sessions.NewEncryptedTranscoder(sessions.GobTranscoder{}, newKey, oldKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	value := "alice@example.com"
+	oldData, err := oldTranscoder.Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if bytes.Contains(oldData, []byte(value)) {
+		t.Fatalf("expected an encrypted value but got: %q", oldData)
+	}
+
+	// Values encrypted by an old key are still readable after a rotation.
+	var got interface{}
+	if err = rotated.Unmarshal(oldData, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got != value {
+		t.Fatalf("expected: %q but got: %v", value, got)
+	}
+
+	// New values are encrypted by the new key only.
+	newData, err := rotated.Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = oldTranscoder.Unmarshal(newData, &got); !errors.Is(err, sessions.ErrDecryption) {
+		t.Fatalf("expected ErrDecryption but got: %v", err)
+	}
+
+	// Tampered values are rejected.
+	newData[len(newData)-1] ^= 1
+	if err = rotated.Unmarshal(newData, &got); !errors.Is(err, sessions.ErrDecryption) {
+		t.Fatalf("expected ErrDecryption but got: %v", err)
+	}
+
+	// Plaintext values are rejected unless allowed.
+	plainData, _ := sessions.GobTranscoder{}.Marshal(value)
+	if err = rotated.Unmarshal(plainData, &got); !errors.Is(err, sessions.ErrDecryption) {
+		t.Fatalf("expected ErrDecryption but got: %v", err)
+	}
+
+	rotated.AllowPlaintext = true
+	got = nil
+	if err = rotated.Unmarshal(plainData, &got); err != nil || got != value {
+		t.Fatalf("expected: %q but got: %v (%v)", value, got, err)
+	}
+
+	if _, err = sessions.NewEncryptedTranscoder(nil, []byte("short")); err == nil {
+		t.Fatal("expected an invalid key size error")
+	}
+}
@@ -0,0 +1,101 @@
+package sessions
+
+import (
+	"time"
+
+	"github.com/kataras/iris/v12/context"
+)
+
+// ExpireReason describes why a session expired, see `OnExpire`.
+type ExpireReason uint8
+
+const (
+	// ExpireIdle is the reason of a session which was not used for its "Expires" duration.
+	ExpireIdle ExpireReason = iota + 1
+	// ExpireAbsolute is the reason of a session which reached its "MaxLifetime".
+	ExpireAbsolute
+)
+
+// String returns the text representation of the reason, "idle" or "absolute".
+func (r ExpireReason) String() string {
+	switch r {
+	case ExpireIdle:
+		return "idle"
+	case ExpireAbsolute:
+		return "absolute"
+	default:
+		return ""
+	}
+}
+
+// ExpireListener is the form of an expire listener.
+// Look `OnExpire` for more.
+type ExpireListener func(sid string, reason ExpireReason)
+
+// OnExpire registers one or more expire listeners.
+// An expire listener is fired when a session expired, either because it was idle
+// for its "Expires" duration or because it reached its "MaxLifetime",
+// right before it is destroyed (see `OnDestroy`).
+// Note that if an expire listener is blocking, then the session manager will delay respectfully,
+// use a goroutine inside the listener to avoid that behavior.
+func (s *Sessions) OnExpire(listeners ...ExpireListener) {
+	for _, ln := range listeners {
+		s.provider.registerExpireListener(ln)
+	}
+}
+
+// lifetimeOf returns the expiration duration of a session created at "created",
+// it's the "Expires" duration limited by the remaining "MaxLifetime" at "now".
+func (s *Sessions) lifetimeOf(created, now time.Time) time.Duration {
+	expires := s.config.Expires
+	if max := s.config.MaxLifetime; max > 0 {
+		remaining := created.Add(max).Sub(now)
+		if remaining <= 0 {
+			remaining = time.Nanosecond
+		}
+
+		if expires <= 0 || remaining < expires {
+			expires = remaining
+		}
+	}
+
+	return expires
+}
+
+// cookieLifetime returns the cookie expiration of a session which expires after "expires",
+// it keeps the browser-session cookies of a -1 "Expires".
+func (s *Sessions) cookieLifetime(expires time.Duration) time.Duration {
+	if s.config.Expires == -1 {
+		return -1
+	}
+
+	return expires
+}
+
+func (s *Sessions) hasReachedMaxLifetime(sess *Session, now time.Time) bool {
+	return s.config.MaxLifetime > 0 && !now.Before(sess.created.Add(s.config.MaxLifetime))
+}
+
+func (s *Sessions) expireReason(sess *Session, now time.Time) ExpireReason {
+	if s != nil && s.hasReachedMaxLifetime(sess, now) {
+		return ExpireAbsolute
+	}
+
+	return ExpireIdle
+}
+
+// slide renews the expiration of the "sess" session, see `Config.Sliding`.
+func (s *Sessions) slide(ctx *context.Context, sess *Session, cookieOptions []context.CookieOption) {
+	if !s.config.Sliding || s.config.Expires <= 0 || sess.IsNew() {
+		return
+	}
+
+	now := ctx.Now()
+	expires := s.lifetimeOf(sess.created, now)
+	if err := s.provider.UpdateExpiration(sess.sid, expires, now); err != nil && err != ErrNotImplemented {
+		s.config.Logger.Debugf("sessions: sliding expiration: %s: %v", sess.sid, err)
+		return
+	}
+
+	s.updateCookie(ctx, sess.sid, s.cookieLifetime(expires), cookieOptions...)
+}
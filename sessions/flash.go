@@ -0,0 +1,33 @@
+package sessions
+
+// Flashes exposes the flash messages of a session to the templates,
+// the `Sessions.Handler` middleware registers it as view data
+// (see `Config.FlashViewDataKey`) when the session has flash messages.
+// A flash message is marked as read only when the template accesses it.
+//
+// Usage in html/template:
+//  {{ with .flash }}{{ .GetString "success" }}{{ end }}
+//  {{ range $key, $value := .flash.All }}<p class="{{ $key }}">{{ $value }}</p>{{ end }}
+type Flashes struct {
+	session *Session
+}
+
+// Has reports whether there are flash messages.
+func (f Flashes) Has() bool {
+	return f.session.HasFlash()
+}
+
+// Get returns the flash message of the "key", see `Session.GetFlash`.
+func (f Flashes) Get(key string) interface{} {
+	return f.session.GetFlash(key)
+}
+
+// GetString returns the flash message of the "key" as string, see `Session.GetFlashString`.
+func (f Flashes) GetString(key string) string {
+	return f.session.GetFlashString(key)
+}
+
+// All returns all the flash messages, see `Session.GetFlashes`.
+func (f Flashes) All() map[string]interface{} {
+	return f.session.GetFlashes()
+}
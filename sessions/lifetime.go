@@ -23,12 +23,18 @@ type LifeTime struct {
 // Begin will begin the life based on the time.Now().Add(d).
 // Use `Continue` to continue from a stored time(database-based session does that).
 func (lt *LifeTime) Begin(d time.Duration, onExpire func()) {
+	lt.BeginAt(time.Now(), d, onExpire)
+}
+
+// BeginAt is like `Begin` but the life begins at the given "now" time,
+// e.g. the `Context.Now()` one.
+func (lt *LifeTime) BeginAt(now time.Time, d time.Duration, onExpire func()) {
 	if d <= 0 {
 		return
 	}
 
 	lt.mu.Lock()
-	lt.Time = time.Now().Add(d)
+	lt.Time = now.Add(d)
 	lt.timer = time.AfterFunc(d, onExpire)
 	lt.mu.Unlock()
 }
@@ -51,9 +57,15 @@ func (lt *LifeTime) Revive(onExpire func()) {
 
 // Shift resets the lifetime based on "d".
 func (lt *LifeTime) Shift(d time.Duration) {
+	lt.ShiftAt(time.Now(), d)
+}
+
+// ShiftAt is like `Shift` but the lifetime is reset at the given "now" time,
+// e.g. the `Context.Now()` one.
+func (lt *LifeTime) ShiftAt(now time.Time, d time.Duration) {
 	lt.mu.Lock()
 	if d > 0 && lt.timer != nil {
-		lt.Time = time.Now().Add(d)
+		lt.Time = now.Add(d)
 		lt.timer.Reset(d)
 	}
 	lt.mu.Unlock()
@@ -71,11 +83,20 @@ func (lt *LifeTime) ExpireNow() {
 
 // HasExpired reports whether "lt" represents is expired.
 func (lt *LifeTime) HasExpired() bool {
+	return lt.HasExpiredAt(time.Now())
+}
+
+// HasExpiredAt reports whether "lt" is expired at the given "now" time,
+// e.g. the `Context.Now()` one.
+func (lt *LifeTime) HasExpiredAt(now time.Time) bool {
+	lt.mu.RLock()
+	defer lt.mu.RUnlock()
+
 	if lt.IsZero() {
 		return false
 	}
 
-	return lt.Time.Before(time.Now())
+	return lt.Time.Before(now)
 }
 
 // DurationUntilExpiration returns the duration until expires, it can return negative number if expired,
@@ -0,0 +1,97 @@
+package sessions
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrLockTimeout is returned by a `Locker` when a session
+// could not be locked in the given timeout.
+var ErrLockTimeout = errors.New("sessions: lock timeout")
+
+// Locker is the interface which locks a session,
+// so concurrent requests of the same session are serialized, see `Config.Lock`.
+// The default Locker locks the sessions of the current process only,
+// a `Database` which implements the Locker interface (e.g. the redis one)
+// locks the sessions across all the processes which share that database.
+type Locker interface {
+	// Lock blocks until the "sid" session is locked and returns the function which unlocks it.
+	// It returns `ErrLockTimeout` if the session was not locked after the "timeout".
+	Lock(sid string, timeout time.Duration) (unlock func(), err error)
+}
+
+type sessionLock struct {
+	ch   chan struct{}
+	refs int // the number of the holder and the waiters.
+}
+
+// memLocker is the default, in-memory, Locker.
+type memLocker struct {
+	mu    sync.Mutex
+	locks map[string]*sessionLock
+}
+
+var _ Locker = (*memLocker)(nil)
+
+func newMemLocker() *memLocker {
+	return &memLocker{locks: make(map[string]*sessionLock)}
+}
+
+func (l *memLocker) Lock(sid string, timeout time.Duration) (func(), error) {
+	l.mu.Lock()
+	lock, ok := l.locks[sid]
+	if !ok {
+		lock = &sessionLock{ch: make(chan struct{}, 1)}
+		l.locks[sid] = lock
+	}
+	lock.refs++
+	l.mu.Unlock()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case lock.ch <- struct{}{}:
+		var once sync.Once
+		return func() {
+			once.Do(func() {
+				<-lock.ch
+				l.release(sid, lock)
+			})
+		}, nil
+	case <-timer.C:
+		l.release(sid, lock)
+		return nil, ErrLockTimeout
+	}
+}
+
+func (l *memLocker) release(sid string, lock *sessionLock) {
+	l.mu.Lock()
+	if lock.refs--; lock.refs == 0 {
+		delete(l.locks, sid)
+	}
+	l.mu.Unlock()
+}
+
+// UseLocker sets the Locker of the sessions, see `Config.Lock`.
+// Note that `UseDatabase` sets the database as the Locker,
+// if it implements the `Locker` interface.
+func (s *Sessions) UseLocker(locker Locker) {
+	if locker == nil {
+		return
+	}
+
+	s.locker = locker
+}
+
+// lock locks the "sid" session through the registered Locker,
+// it falls back to the in-memory one if the Locker is a database which does not support locking.
+func (s *Sessions) lock(sid string) (func(), error) {
+	unlock, err := s.locker.Lock(sid, s.config.LockTimeout)
+	if err == ErrNotImplemented {
+		return s.memLocker.Lock(sid, s.config.LockTimeout)
+	}
+
+	return unlock, err
+}
@@ -14,6 +14,9 @@ type (
 		sessions         map[string]*Session
 		db               Database
 		destroyListeners []DestroyListener
+		expireListeners  []ExpireListener
+		createListeners  []CreateListener
+		updateListeners  []UpdateListener
 	}
 )
 
@@ -38,21 +41,18 @@ func (p *provider) RegisterDatabase(db Database) {
 	p.mu.Unlock()
 }
 
-// newSession returns a new session from sessionid
-func (p *provider) newSession(man *Sessions, sid string, expires time.Duration) *Session {
+// newSession returns a new session from sessionid,
+// created at the "now" time of the request (see `Context.Now`).
+func (p *provider) newSession(man *Sessions, sid string, expires time.Duration, now time.Time) *Session {
 	sess := &Session{
 		sid:      sid,
+		created:  now,
 		Man:      man,
 		provider: p,
 		flashes:  make(map[string]*flashMessage),
 	}
 
-	onExpire := func() {
-		p.mu.Lock()
-		p.deleteSession(sess)
-		p.mu.Unlock()
-	}
-
+	onExpire := p.expireFunc(sess)
 	lifetime := p.db.Acquire(sid, expires)
 
 	// simple and straight:
@@ -67,16 +67,27 @@ func (p *provider) newSession(man *Sessions, sid string, expires time.Duration)
 		// Even if the database has an unlimited session (possible by a previous app run)
 		// priority to the "expires" is given,
 		// again if <=0 then it does nothing.
-		lifetime.Begin(expires, onExpire)
+		lifetime.BeginAt(now, expires, onExpire)
 	}
 
 	sess.Lifetime = &lifetime
 	return sess
 }
 
+// expireFunc returns the function which is called when the "sess" session expires.
+func (p *provider) expireFunc(sess *Session) func() {
+	return func() {
+		p.fireExpire(sess.ID(), sess.Man.expireReason(sess, time.Now()))
+
+		p.mu.Lock()
+		p.deleteSession(sess)
+		p.mu.Unlock()
+	}
+}
+
 // Init creates the session  and returns it
-func (p *provider) Init(man *Sessions, sid string, expires time.Duration) *Session {
-	newSession := p.newSession(man, sid, expires)
+func (p *provider) Init(man *Sessions, sid string, expires time.Duration, now time.Time) *Session {
+	newSession := p.newSession(man, sid, expires, now)
 	newSession.isNew = true
 	p.mu.Lock()
 	p.sessions[sid] = newSession
@@ -100,7 +111,7 @@ var ErrNotFound = errors.New("session not found")
 // because the call of the provider's `UpdateExpiration` is always called when the client has a valid session cookie.
 //
 // If a backend database is used then it may return an `ErrNotImplemented` error if the underline database does not support this operation.
-func (p *provider) UpdateExpiration(sid string, expires time.Duration) error {
+func (p *provider) UpdateExpiration(sid string, expires time.Duration, now time.Time) error {
 	if expires <= 0 {
 		return nil
 	}
@@ -112,12 +123,12 @@ func (p *provider) UpdateExpiration(sid string, expires time.Duration) error {
 		return ErrNotFound
 	}
 
-	sess.Lifetime.Shift(expires)
+	sess.Lifetime.ShiftAt(now, expires)
 	return p.db.OnUpdateExpiration(sid, expires)
 }
 
 // Read returns the store which sid parameter belongs
-func (p *provider) Read(man *Sessions, sid string, expires time.Duration) *Session {
+func (p *provider) Read(man *Sessions, sid string, expires time.Duration, now time.Time) *Session {
 	p.mu.RLock()
 	sess, found := p.sessions[sid]
 	p.mu.RUnlock()
@@ -130,7 +141,7 @@ func (p *provider) Read(man *Sessions, sid string, expires time.Duration) *Sessi
 		return sess
 	}
 
-	return p.Init(man, sid, expires) // if not found create new
+	return p.Init(man, sid, expires, now) // if not found create new
 }
 
 func (p *provider) registerDestroyListener(ln DestroyListener) {
@@ -140,6 +151,45 @@ func (p *provider) registerDestroyListener(ln DestroyListener) {
 	p.destroyListeners = append(p.destroyListeners, ln)
 }
 
+func (p *provider) registerExpireListener(ln ExpireListener) {
+	if ln == nil {
+		return
+	}
+	p.expireListeners = append(p.expireListeners, ln)
+}
+
+func (p *provider) fireExpire(sid string, reason ExpireReason) {
+	for _, ln := range p.expireListeners {
+		ln(sid, reason)
+	}
+}
+
+func (p *provider) registerCreateListener(ln CreateListener) {
+	if ln == nil {
+		return
+	}
+	p.createListeners = append(p.createListeners, ln)
+}
+
+func (p *provider) fireCreate(sid string) {
+	for _, ln := range p.createListeners {
+		ln(sid)
+	}
+}
+
+func (p *provider) registerUpdateListener(ln UpdateListener) {
+	if ln == nil {
+		return
+	}
+	p.updateListeners = append(p.updateListeners, ln)
+}
+
+func (p *provider) fireUpdate(sid, key string, value interface{}) {
+	for _, ln := range p.updateListeners {
+		ln(sid, key, value)
+	}
+}
+
 func (p *provider) fireDestroy(sid string) {
 	for _, ln := range p.destroyListeners {
 		ln(sid)
@@ -168,10 +218,25 @@ func (p *provider) DestroyAll() {
 	p.mu.Unlock()
 }
 
+// forget removes the "sess" session from the memory, without releasing
+// its database entry or firing the destroy listeners,
+// e.g. a session of a `CookieStore` at the end of its request.
+func (p *provider) forget(sess *Session) {
+	p.mu.Lock()
+	if p.sessions[sess.sid] == sess {
+		delete(p.sessions, sess.sid)
+	}
+	p.mu.Unlock()
+
+	sess.Lifetime.ExpireNow() // stop the expiration timer.
+	sess.removeWorkspace()
+}
+
 func (p *provider) deleteSession(sess *Session) {
 	sid := sess.sid
 
 	delete(p.sessions, sid)
 	p.db.Release(sid)
+	sess.removeWorkspace()
 	p.fireDestroy(sid)
 }
@@ -0,0 +1,130 @@
+package sessions
+
+import (
+	"time"
+
+	"github.com/kataras/iris/v12/context"
+)
+
+// Regenerate issues a new session ID for this session, it keeps its values,
+// flash messages and creation time, and it invalidates the old ID
+// on the server and on the registered session database.
+// The client's session cookie is updated to the new ID.
+// The same *Session value keeps to be valid, its `ID` method returns the new one.
+//
+// Call it on privilege changes, e.g. right after a successful login,
+// in order to protect against session fixation attacks.
+// See the `Sessions.RegenerateOnAuth` middleware too.
+//
+// Note that values stored through `SetImmutable` are restored as mutable ones.
+//
+// It returns `ErrNotFound` if the session is already destroyed.
+func (s *Session) Regenerate(ctx *context.Context, cookieOptions ...context.CookieOption) error {
+	man := s.Man
+	newSid := man.config.SessionIDGenerator(ctx)
+
+	expires, err := s.provider.regenerate(s, newSid, ctx.Now())
+	if err != nil {
+		return err
+	}
+
+	man.updateCookie(ctx, newSid, man.cookieLifetime(expires), cookieOptions...)
+	if man.cookieStore != nil {
+		man.cookieStore.bind(ctx, s, cookieOptions)
+	}
+
+	return nil
+}
+
+// regenerate moves the "sess" session and its values to the "newSid" entry
+// and releases the old one. It returns the remaining lifetime of the session at the "now" time.
+func (p *provider) regenerate(sess *Session, newSid string, now time.Time) (time.Duration, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	oldSid := sess.ID()
+	if _, found := p.sessions[oldSid]; !found {
+		return 0, ErrNotFound
+	}
+
+	var expires time.Duration
+	if !sess.Lifetime.IsZero() {
+		if expires = sess.Lifetime.Time.Sub(now); expires <= 0 {
+			return 0, ErrNotFound
+		}
+	}
+
+	values := make(map[string]interface{}, p.db.Len(oldSid))
+	if err := p.db.Visit(oldSid, func(key string, value interface{}) {
+		values[key] = value
+	}); err != nil {
+		return 0, err
+	}
+
+	lifetime := p.db.Acquire(newSid, expires)
+	for key, value := range values {
+		if err := p.db.Set(newSid, key, value, expires, false); err != nil {
+			p.db.Release(newSid)
+			return 0, err
+		}
+	}
+
+	// Stop the old expiration timer, the new lifetime keeps the remaining duration.
+	sess.Lifetime.ExpireNow()
+	lifetime.Time = time.Time{}
+	lifetime.BeginAt(now, expires, p.expireFunc(sess))
+
+	sess.mu.Lock()
+	sess.sid = newSid
+	sess.Lifetime = &lifetime
+	sess.mu.Unlock()
+
+	delete(p.sessions, oldSid)
+	p.sessions[newSid] = sess
+	p.db.Release(oldSid)
+
+	return expires, nil
+}
+
+const sessionAuthKey = "iris.session.auth"
+
+// RegenerateOnAuth returns a middleware which regenerates the session ID
+// (see `Session.Regenerate`) on authentication events,
+// i.e. when the request's authenticated user (see `Context.User`)
+// differs from the one of the previous request of the same session,
+// e.g. after a login, a logout or a switch of the user.
+// Register it after the `Handler` and the authentication middleware(s).
+//
+// Usage:
+//  app.Use(sess.Handler())
+//  app.Use(basicauth.Default(users))
+//  app.Use(sess.RegenerateOnAuth())
+func (s *Sessions) RegenerateOnAuth(cookieOptions ...context.CookieOption) context.Handler {
+	return func(ctx *context.Context) {
+		sess := Get(ctx)
+		if sess == nil {
+			ctx.Next()
+			return
+		}
+
+		var userID string
+		if u := ctx.User(); u != nil {
+			userID, _ = u.GetID()
+			if userID == "" {
+				userID, _ = u.GetUsername()
+			}
+		}
+
+		if sess.GetString(sessionAuthKey) != userID {
+			if err := sess.Regenerate(ctx, cookieOptions...); err != nil {
+				s.config.Logger.Debugf("sessions: regenerate: %s: %v", sess.ID(), err)
+			} else if userID == "" {
+				sess.Delete(sessionAuthKey)
+			} else {
+				sess.Set(sessionAuthKey, userID)
+			}
+		}
+
+		ctx.Next()
+	}
+}
@@ -0,0 +1,407 @@
+package sessions
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kataras/iris/v12/context"
+)
+
+const (
+	// DefaultRememberCookie is the default `RememberConfig.Cookie`.
+	DefaultRememberCookie = "irisremember"
+	// DefaultRememberExpires is the default `RememberConfig.Expires`.
+	DefaultRememberExpires = 30 * 24 * time.Hour
+	// DefaultRememberRotationGrace is the default `RememberConfig.RotationGrace`.
+	DefaultRememberRotationGrace = 5 * time.Second
+	// DefaultRememberSessionKey is the default `RememberConfig.SessionKey`.
+	DefaultRememberSessionKey = "iris.session.user"
+)
+
+// ErrRememberTheft is reported to the `RememberConfig.OnTheft` when
+// an already used token of a remember-me series is presented,
+// i.e. the cookie was stolen and used by either the attacker or the user.
+var ErrRememberTheft = errors.New("sessions: remember-me token reuse")
+
+// ErrRememberRotated should be returned by the `RememberStore.Rotate`
+// when the token of the series was already rotated by another request.
+var ErrRememberRotated = errors.New("sessions: remember-me token already rotated")
+
+// RememberToken is a remember-me credential as it's kept by a `RememberStore`.
+// The client holds the series and the plain token,
+// the store keeps only the hash of the token.
+type RememberToken struct {
+	// Series identifies the login, it stays the same across the token rotations.
+	Series string `json:"series"`
+	// TokenHash is the SHA-256 hash of the current token.
+	TokenHash string `json:"tokenHash"`
+	// PreviousTokenHash is the hash of the token before the latest rotation,
+	// it's accepted for the `RememberConfig.RotationGrace` after the rotation.
+	PreviousTokenHash string `json:"previousTokenHash,omitempty"`
+	// UserID is the ID of the remembered user.
+	UserID string `json:"userID"`
+	// Rotated is the time of the latest rotation.
+	Rotated time.Time `json:"rotated"`
+	// Expires is the expiration time of the series.
+	Expires time.Time `json:"expires"`
+}
+
+// RememberStore is the interface which stores the remember-me tokens, see `RememberMe`.
+// The default one keeps them in memory, a persistent one should be used
+// so the users are remembered across application restarts and instances.
+type RememberStore interface {
+	// Get returns the token of the "series" or `ErrNotFound`.
+	Get(series string) (RememberToken, error)
+	// Set inserts or replaces the token of its series.
+	Set(token RememberToken) error
+	// Rotate replaces the token of its series only if the stored one's TokenHash
+	// is the "oldTokenHash", atomically, otherwise it returns `ErrRememberRotated`,
+	// so concurrent requests of the same token do not rotate it twice.
+	Rotate(token RememberToken, oldTokenHash string) error
+	// Delete removes the token of the "series".
+	Delete(series string) error
+	// DeleteUser removes the tokens of all the series of the "userID" user.
+	DeleteUser(userID string) error
+}
+
+type memRememberStore struct {
+	mu     sync.RWMutex
+	tokens map[string]RememberToken
+}
+
+var _ RememberStore = (*memRememberStore)(nil)
+
+func (m *memRememberStore) Get(series string) (RememberToken, error) {
+	m.mu.RLock()
+	token, ok := m.tokens[series]
+	m.mu.RUnlock()
+	if !ok {
+		return RememberToken{}, ErrNotFound
+	}
+
+	return token, nil
+}
+
+func (m *memRememberStore) Set(token RememberToken) error {
+	m.mu.Lock()
+	m.tokens[token.Series] = token
+	m.mu.Unlock()
+	return nil
+}
+
+func (m *memRememberStore) Rotate(token RememberToken, oldTokenHash string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored, ok := m.tokens[token.Series]
+	if !ok {
+		return ErrNotFound
+	}
+
+	if stored.TokenHash != oldTokenHash {
+		return ErrRememberRotated
+	}
+
+	m.tokens[token.Series] = token
+	return nil
+}
+
+func (m *memRememberStore) Delete(series string) error {
+	m.mu.Lock()
+	delete(m.tokens, series)
+	m.mu.Unlock()
+	return nil
+}
+
+func (m *memRememberStore) DeleteUser(userID string) error {
+	m.mu.Lock()
+	for series, token := range m.tokens {
+		if token.UserID == userID {
+			delete(m.tokens, series)
+		}
+	}
+	m.mu.Unlock()
+	return nil
+}
+
+// RememberConfig is the configuration of a `RememberMe`.
+type RememberConfig struct {
+	// Cookie is the name of the remember-me cookie.
+	//
+	// Defaults to "irisremember".
+	Cookie string
+	// Expires is the lifetime of a remember-me series, since the login.
+	//
+	// Defaults to 30 days.
+	Expires time.Duration
+	// RotationGrace is the duration which the previous token of a series
+	// is still accepted after a rotation, without a new rotation,
+	// so concurrent requests of the same client are not reported as a theft.
+	//
+	// Defaults to 5 seconds.
+	RotationGrace time.Duration
+	// SessionKey is the session key which holds the ID of the authenticated user.
+	//
+	// Defaults to "iris.session.user".
+	SessionKey string
+	// Store keeps the remember-me tokens.
+	//
+	// Defaults to an in-memory store.
+	Store RememberStore
+	// OnTheft, if not nil, is called when a stolen token is detected,
+	// right after all the series of the user are invalidated,
+	// e.g. to warn the user.
+	OnTheft func(ctx *context.Context, userID string)
+}
+
+// RememberMe keeps the users logged in across sessions
+// through long-lived remember-me cookies, which hold a series and a token pair.
+// The token is rotated on each use and the store keeps only its hash.
+// The reuse of an old token of a series means that the cookie was stolen,
+// all the series of that user are invalidated then.
+//
+// Create a new RememberMe through the `Sessions.RememberMe` method.
+type RememberMe struct {
+	config RememberConfig
+	man    *Sessions
+}
+
+// RememberMe returns a new remember-me manager of this sessions manager.
+//
+// Usage:
+//  remember := sess.RememberMe(sessions.RememberConfig{Store: myStore})
+//  app.Use(sess.Handler(), remember.Handler)
+//
+//  app.Post("/login", func(ctx iris.Context) {
+//      [authenticate the user...]
+//      if ctx.FormValue("remember") == "on" {
+//          remember.Remember(ctx, userID)
+//      }
+//  })
+//  app.Get("/logout", func(ctx iris.Context) {
+//      remember.Forget(ctx)
+//  })
+//  app.Get("/profile", func(ctx iris.Context) {
+//      userID := remember.UserID(ctx)
+//  })
+func (s *Sessions) RememberMe(cfg RememberConfig) *RememberMe {
+	if cfg.Cookie == "" {
+		cfg.Cookie = DefaultRememberCookie
+	}
+	if cfg.Expires <= 0 {
+		cfg.Expires = DefaultRememberExpires
+	}
+	if cfg.RotationGrace <= 0 {
+		cfg.RotationGrace = DefaultRememberRotationGrace
+	}
+	if cfg.SessionKey == "" {
+		cfg.SessionKey = DefaultRememberSessionKey
+	}
+	if cfg.Store == nil {
+		cfg.Store = &memRememberStore{tokens: make(map[string]RememberToken)}
+	}
+
+	return &RememberMe{config: cfg, man: s}
+}
+
+// Remember logs in the "userID" user on the request's session
+// and starts a new remember-me series for it, call it after a successful login.
+// The session ID is regenerated too, see `Session.Regenerate`.
+func (r *RememberMe) Remember(ctx *context.Context, userID string) error {
+	sess := Get(ctx)
+	if sess == nil {
+		return ErrNotFound
+	}
+
+	if err := sess.Regenerate(ctx); err != nil {
+		return err
+	}
+	sess.Set(r.config.SessionKey, userID)
+
+	if series, _ := r.readCookie(ctx); series != "" {
+		r.config.Store.Delete(series)
+	}
+
+	series, err := randomToken(16)
+	if err != nil {
+		return err
+	}
+
+	now := ctx.Now()
+	return r.issue(ctx, RememberToken{
+		Series:  series,
+		UserID:  userID,
+		Expires: now.Add(r.config.Expires),
+	}, false)
+}
+
+// Forget logs out the user of the request's session,
+// it removes the remember-me series of the request's cookie and the cookie itself.
+func (r *RememberMe) Forget(ctx *context.Context) error {
+	if sess := Get(ctx); sess != nil {
+		sess.Delete(r.config.SessionKey)
+	}
+
+	series, _ := r.readCookie(ctx)
+	if series == "" {
+		return nil
+	}
+
+	r.removeCookie(ctx)
+	return r.config.Store.Delete(series)
+}
+
+// ForgetUser removes all the remember-me series of the "userID" user,
+// e.g. on a password change or a "log out everywhere" action.
+func (r *RememberMe) ForgetUser(userID string) error {
+	return r.config.Store.DeleteUser(userID)
+}
+
+// UserID returns the ID of the logged in user of the request's session,
+// either through `Remember` or restored by the `Handler` from a remember-me cookie.
+func (r *RememberMe) UserID(ctx *context.Context) string {
+	if sess := Get(ctx); sess != nil {
+		return sess.GetString(r.config.SessionKey)
+	}
+
+	return ""
+}
+
+// Handler logs in the user of a valid remember-me cookie when the request's session
+// is not logged in, e.g. after the session expired, and rotates the cookie's token.
+// The session ID is regenerated on login, see `Session.Regenerate`.
+// It should be registered after the `Sessions.Handler`.
+func (r *RememberMe) Handler(ctx *context.Context) {
+	if sess := Get(ctx); sess != nil && sess.GetString(r.config.SessionKey) == "" {
+		if err := r.restore(ctx, sess); err != nil && err != ErrNotFound {
+			r.man.config.Logger.Debugf("sessions: remember-me: %v", err)
+		}
+	}
+
+	ctx.Next()
+}
+
+func (r *RememberMe) restore(ctx *context.Context, sess *Session) error {
+	series, token := r.readCookie(ctx)
+	if series == "" {
+		return ErrNotFound
+	}
+
+	stored, err := r.config.Store.Get(series)
+	if err != nil {
+		r.removeCookie(ctx)
+		return err
+	}
+
+	now := ctx.Now()
+	if !now.Before(stored.Expires) {
+		r.removeCookie(ctx)
+		return r.config.Store.Delete(series)
+	}
+
+	switch tokenHash := hashToken(token); {
+	case equalHash(tokenHash, stored.TokenHash):
+		// rotate the token below.
+	case equalHash(tokenHash, stored.PreviousTokenHash) && now.Sub(stored.Rotated) < r.config.RotationGrace:
+		// a concurrent request of a just rotated token,
+		// log in without a new rotation, the client receives the current token by the other response.
+		if err = sess.Regenerate(ctx); err != nil {
+			return err
+		}
+		sess.Set(r.config.SessionKey, stored.UserID)
+		return nil
+	default:
+		r.removeCookie(ctx)
+		if err = r.config.Store.DeleteUser(stored.UserID); err != nil {
+			return err
+		}
+		if r.config.OnTheft != nil {
+			r.config.OnTheft(ctx, stored.UserID)
+		}
+		return ErrRememberTheft
+	}
+
+	if err = sess.Regenerate(ctx); err != nil {
+		return err
+	}
+	sess.Set(r.config.SessionKey, stored.UserID)
+
+	if err = r.issue(ctx, stored, true); err == ErrRememberRotated {
+		// a concurrent request of the same token rotated it first,
+		// the client receives the current token by the other response.
+		return nil
+	}
+
+	return err
+}
+
+// issue rotates the token of the "stored" series
+// and writes the new series and token pair to the client's cookie.
+// If "rotate" is true then the token is replaced only if it's not rotated
+// by another request in the meantime, see `RememberStore.Rotate`.
+func (r *RememberMe) issue(ctx *context.Context, stored RememberToken, rotate bool) error {
+	token, err := randomToken(32)
+	if err != nil {
+		return err
+	}
+
+	oldTokenHash := stored.TokenHash
+	stored.PreviousTokenHash = oldTokenHash
+	stored.TokenHash = hashToken(token)
+	stored.Rotated = ctx.Now()
+	if rotate {
+		err = r.config.Store.Rotate(stored, oldTokenHash)
+	} else {
+		err = r.config.Store.Set(stored)
+	}
+	if err != nil {
+		return err
+	}
+
+	cookie := r.man.newCookie(ctx, r.config.Cookie, stored.Series+":"+token, stored.Expires.Sub(ctx.Now()))
+	ctx.UpsertCookie(cookie, r.man.extraCookieOptions(nil)...)
+	return nil
+}
+
+// readCookie returns the series and the token of the request's remember-me cookie.
+func (r *RememberMe) readCookie(ctx *context.Context) (series, token string) {
+	c, err := ctx.Request().Cookie(r.config.Cookie)
+	if err != nil {
+		return
+	}
+
+	if i := strings.IndexByte(c.Value, ':'); i > 0 {
+		series, token = c.Value[:i], c.Value[i+1:]
+	}
+
+	return
+}
+
+func (r *RememberMe) removeCookie(ctx *context.Context) {
+	ctx.RemoveCookie(r.config.Cookie, r.man.extraCookieOptions(nil)...)
+}
+
+func randomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashToken(token string) string {
+	h := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(h[:])
+}
+
+func equalHash(a, b string) bool {
+	return b != "" && subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
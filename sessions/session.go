@@ -4,7 +4,9 @@ import (
 	"reflect"
 	"strconv"
 	"sync"
+	"time"
 
+	"github.com/kataras/iris/v12/context"
 	"github.com/kataras/iris/v12/core/memstore"
 )
 
@@ -17,21 +19,26 @@ type (
 	Session struct {
 		sid     string
 		isNew   bool
+		created time.Time
 		flashes map[string]*flashMessage
-		mu      sync.RWMutex // for flashes.
+		mu      sync.RWMutex // for flashes and workspace.
 		// Lifetime it contains the expiration data, use it for read-only information.
 		// See `Sessions.UpdateExpiration` too.
 		Lifetime *LifeTime
 		// Man is the sessions manager that this session created of.
 		Man *Sessions
 
-		provider *provider
+		provider  *provider
+		workspace *context.Workspace
 	}
 
 	flashMessage struct {
 		// if true then this flash message is removed on the flash gc
 		shouldRemove bool
-		value        interface{}
+		// if true then this flash message survived a request,
+		// see `Config.DiscardUnreadFlashes`.
+		survived bool
+		value    interface{}
 	}
 )
 
@@ -52,6 +59,12 @@ func (s *Session) ID() string {
 	return s.sid
 }
 
+// CreatedAt returns the time this session was created,
+// see `Config.MaxLifetime` too.
+func (s *Session) CreatedAt() time.Time {
+	return s.created
+}
+
 // IsNew returns true if this session is just
 // created by the current application's process.
 func (s *Session) IsNew() bool {
@@ -70,11 +83,16 @@ func (s *Session) Decode(key string, outPtr interface{}) error {
 
 // when running on the session manager removes any 'old' flash messages.
 func (s *Session) runFlashGC() {
+	discardUnread := s.Man != nil && s.Man.config.DiscardUnreadFlashes
+
 	s.mu.Lock()
 	for key, v := range s.flashes {
-		if v.shouldRemove {
+		if v.shouldRemove || (discardUnread && v.survived) {
 			delete(s.flashes, key)
+			continue
 		}
+
+		v.survived = true
 	}
 	s.mu.Unlock()
 }
@@ -518,7 +536,9 @@ func (s *Session) Len() int {
 }
 
 func (s *Session) set(key string, value interface{}, immutable bool) {
-	s.provider.db.Set(s.sid, key, value, s.Lifetime.DurationUntilExpiration(), immutable)
+	if err := s.provider.db.Set(s.sid, key, value, s.Lifetime.DurationUntilExpiration(), immutable); err == nil {
+		s.provider.fireUpdate(s.sid, key, value)
+	}
 }
 
 // Set fills the session with an entry "value", based on its "key".
@@ -566,6 +586,9 @@ func (s *Session) SetFlash(key string, value interface{}) {
 // returns true if actually something was removed.
 func (s *Session) Delete(key string) bool {
 	removed := s.provider.db.Delete(s.sid, key)
+	if removed {
+		s.provider.fireUpdate(s.sid, key, nil)
+	}
 	return removed
 }
 
@@ -578,7 +601,9 @@ func (s *Session) DeleteFlash(key string) {
 
 // Clear removes all entries.
 func (s *Session) Clear() {
-	s.provider.db.Clear(s.sid)
+	if err := s.provider.db.Clear(s.sid); err == nil {
+		s.provider.fireUpdate(s.sid, "", nil)
+	}
 }
 
 // ClearFlashes removes all flash messages.
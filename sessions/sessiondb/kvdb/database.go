@@ -0,0 +1,226 @@
+// Package kvdb implements the sessions.Database on top of a kv.Store,
+// e.g. the in-memory, the BoltDB or the Badger one of the kv package.
+package kvdb
+
+import (
+	"time"
+
+	"github.com/kataras/iris/v12/kv"
+	"github.com/kataras/iris/v12/sessions"
+
+	"github.com/kataras/golog"
+)
+
+// DefaultPrefix is the prefix of the session keys, see `Database.Prefix`.
+var DefaultPrefix = "sessions:"
+
+// Database the key-value session storage.
+//
+// Usage:
+//  store, err := boltdb.New("./data/app.db")
+//  [...]
+//  sess.UseDatabase(kvdb.New(store))
+type Database struct {
+	// Store is the underline key-value store.
+	Store kv.Store
+	// Prefix is prepended to the session keys,
+	// so the store can be shared with other packages,
+	// e.g. the response cache. Defaults to the `DefaultPrefix`.
+	Prefix string
+
+	logger *golog.Logger
+}
+
+var _ sessions.Database = (*Database)(nil)
+
+// New returns a new session database of the given key-value "store".
+func New(store kv.Store) *Database {
+	return &Database{Store: store, Prefix: DefaultPrefix}
+}
+
+// SetLogger sets the logger once before server ran.
+// By default the Iris one is injected.
+func (db *Database) SetLogger(logger *golog.Logger) {
+	db.logger = logger
+}
+
+// the session entry holds its expiration, its values live under the $prefix$sid/ keys.
+func (db *Database) makeSessionKey(sid string) string {
+	return db.Prefix + sid
+}
+
+func (db *Database) makePrefix(sid string) string {
+	return db.Prefix + sid + "/"
+}
+
+func (db *Database) makeKey(sid, key string) string {
+	return db.makePrefix(sid) + key
+}
+
+// Acquire receives a session's lifetime from the database,
+// if the return value is LifeTime{} then the session manager sets the life time based on the expiration duration lives in configuration.
+func (db *Database) Acquire(sid string, expires time.Duration) sessions.LifeTime {
+	ttl, err := db.Store.TTL(db.makeSessionKey(sid))
+	if err == nil {
+		if ttl == kv.NoExpiration {
+			return sessions.LifeTime{}
+		}
+
+		// found, return the expiration.
+		return sessions.LifeTime{Time: time.Now().Add(ttl)}
+	}
+
+	if err != kv.ErrNotFound {
+		db.logger.Error(err)
+	}
+
+	// not found, create an entry with ttl and return an empty lifetime, session manager will do its job.
+	if err = db.Store.Set(db.makeSessionKey(sid), nil, expires); err != nil {
+		db.logger.Error(err)
+	}
+
+	return sessions.LifeTime{}
+}
+
+// OnUpdateExpiration will re-set the database's session's entry ttl.
+func (db *Database) OnUpdateExpiration(sid string, newExpires time.Duration) error {
+	if err := db.Store.Set(db.makeSessionKey(sid), nil, newExpires); err != nil {
+		return err
+	}
+
+	values := make(map[string][]byte)
+	err := db.Store.Iterate(db.makePrefix(sid), func(key string, value []byte) bool {
+		values[key] = append([]byte(nil), value...)
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	for key, value := range values {
+		if err = db.Store.Set(key, value, newExpires); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Set sets a key value of a specific session.
+// Ignore the "immutable".
+func (db *Database) Set(sid string, key string, value interface{}, ttl time.Duration, immutable bool) error {
+	valueBytes, err := sessions.DefaultTranscoder.Marshal(value)
+	if err != nil {
+		db.logger.Error(err)
+		return err
+	}
+
+	if err = db.Store.Set(db.makeKey(sid, key), valueBytes, ttl); err != nil {
+		db.logger.Error(err)
+	}
+
+	return err
+}
+
+// Get retrieves a session value based on the key.
+func (db *Database) Get(sid string, key string) (value interface{}) {
+	if err := db.Decode(sid, key, &value); err == nil {
+		return value
+	}
+
+	return nil
+}
+
+// Decode binds the "outPtr" to the value associated to the provided "key".
+func (db *Database) Decode(sid, key string, outPtr interface{}) error {
+	valueBytes, err := db.Store.Get(db.makeKey(sid, key))
+	if err != nil {
+		if err != kv.ErrNotFound {
+			db.logger.Error(err)
+		}
+		return err
+	}
+
+	return sessions.DefaultTranscoder.Unmarshal(valueBytes, outPtr)
+}
+
+// Visit loops through all session keys and values.
+func (db *Database) Visit(sid string, cb func(key string, value interface{})) error {
+	prefix := db.makePrefix(sid)
+
+	var err error
+	iterErr := db.Store.Iterate(prefix, func(key string, valueBytes []byte) bool {
+		var value interface{}
+		if err = sessions.DefaultTranscoder.Unmarshal(valueBytes, &value); err != nil {
+			db.logger.Errorf("[sessionsdb.kvdb.Visit] %v", err)
+			return false
+		}
+
+		cb(key[len(prefix):], value)
+		return true
+	})
+	if iterErr != nil {
+		return iterErr
+	}
+
+	return err
+}
+
+// Len returns the length of the session's entries (keys).
+func (db *Database) Len(sid string) (n int) {
+	err := db.Store.Iterate(db.makePrefix(sid), func(string, []byte) bool {
+		n++
+		return true
+	})
+	if err != nil {
+		db.logger.Error(err)
+	}
+
+	return
+}
+
+// Delete removes a session key value based on its key.
+func (db *Database) Delete(sid string, key string) (deleted bool) {
+	if err := db.Store.Delete(db.makeKey(sid, key)); err != nil {
+		db.logger.Error(err)
+		return false
+	}
+
+	return true
+}
+
+// Clear removes all session key values but it keeps the session entry.
+func (db *Database) Clear(sid string) error {
+	var keys []string
+	err := db.Store.Iterate(db.makePrefix(sid), func(key string, _ []byte) bool {
+		keys = append(keys, key)
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		if err = db.Store.Delete(key); err != nil {
+			db.logger.Warnf("Database.Clear: %s: %v", key, err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Release destroys the session, it clears and removes the session entry,
+// session manager will create a new session ID on the next request after this call.
+func (db *Database) Release(sid string) error {
+	if err := db.Clear(sid); err != nil {
+		return err
+	}
+
+	return db.Store.Delete(db.makeSessionKey(sid))
+}
+
+// Close closes the underline key-value store.
+func (db *Database) Close() error {
+	return db.Store.Close()
+}
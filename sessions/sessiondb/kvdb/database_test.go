@@ -0,0 +1,92 @@
+package kvdb_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/httptest"
+	"github.com/kataras/iris/v12/kv"
+	"github.com/kataras/iris/v12/kv/badger"
+	"github.com/kataras/iris/v12/kv/boltdb"
+	"github.com/kataras/iris/v12/sessions"
+	"github.com/kataras/iris/v12/sessions/sessiondb/kvdb"
+)
+
+func TestDatabase(t *testing.T) {
+	dir := t.TempDir()
+
+	boltStore, err := boltdb.New(filepath.Join(dir, "bolt", "kv.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	badgerStore, err := badger.New(filepath.Join(dir, "badger"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, store := range map[string]kv.Store{
+		"memory": kv.NewMemory(),
+		"boltdb": boltStore,
+		"badger": badgerStore,
+	} {
+		store := store
+		t.Run(name, func(t *testing.T) {
+			db := kvdb.New(store)
+			defer db.Close()
+			testDatabase(t, store, db)
+		})
+	}
+}
+
+func testDatabase(t *testing.T, store kv.Store, db *kvdb.Database) {
+	sess := sessions.New(sessions.Config{Cookie: "sessionid", Expires: time.Hour})
+	sess.UseDatabase(db)
+
+	app := iris.New()
+	app.Use(sess.Handler())
+	app.Get("/set", func(ctx iris.Context) {
+		s := sessions.Get(ctx)
+		s.Set("name", ctx.URLParam("name"))
+		ctx.WriteString(s.ID())
+	})
+	app.Get("/get", func(ctx iris.Context) {
+		ctx.Writef("%s:%d", sessions.Get(ctx).GetString("name"), sessions.Get(ctx).Len())
+	})
+	app.Get("/clear", func(ctx iris.Context) {
+		sessions.Get(ctx).Clear()
+	})
+	app.Get("/destroy", func(ctx iris.Context) {
+		sess.Destroy(ctx)
+	})
+
+	e := httptest.New(t, app, httptest.URL("http://example.com"))
+	sid := e.GET("/set").WithQuery("name", "iris").Expect().Status(httptest.StatusOK).Body().Raw()
+	e.GET("/get").Expect().Status(httptest.StatusOK).Body().Equal("iris:1")
+
+	// The session entry and its values expire with the session.
+	for _, key := range []string{kvdb.DefaultPrefix + sid, kvdb.DefaultPrefix + sid + "/name"} {
+		ttl, err := store.TTL(key)
+		if err != nil {
+			t.Fatalf("%s: %v", key, err)
+		}
+
+		if ttl <= 0 || ttl > time.Hour {
+			t.Fatalf("%s: expected a ttl of about an hour but got: %s", key, ttl)
+		}
+	}
+
+	e.GET("/clear").Expect().Status(httptest.StatusOK)
+	e.GET("/get").Expect().Status(httptest.StatusOK).Body().Equal(":0")
+
+	e.GET("/set").WithQuery("name", "iris").Expect().Status(httptest.StatusOK)
+	e.GET("/destroy").Expect().Status(httptest.StatusOK)
+	if _, err := store.Get(kvdb.DefaultPrefix + sid + "/name"); err != kv.ErrNotFound {
+		t.Fatalf("expected the values of the destroyed session to be removed but got: %v", err)
+	}
+	if _, err := store.TTL(kvdb.DefaultPrefix + sid); err != kv.ErrNotFound {
+		t.Fatalf("expected the destroyed session entry to be removed but got: %v", err)
+	}
+}
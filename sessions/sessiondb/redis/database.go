@@ -1,7 +1,9 @@
 package redis
 
 import (
+	"crypto/rand"
 	"crypto/tls"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"time"
@@ -18,6 +20,8 @@ const (
 	DefaultRedisAddr = "127.0.0.1:6379"
 	// DefaultRedisTimeout the redis idle timeout option, time.Duration(30) * time.Second.
 	DefaultRedisTimeout = time.Duration(30) * time.Second
+	// DefaultLockExpiration the expiration of a session's lock, 30 * time.Second.
+	DefaultLockExpiration = 30 * time.Second
 )
 
 // Config the redis configuration used inside sessions
@@ -31,6 +35,16 @@ type Config struct {
 	// Clusters a list of network addresses for clusters.
 	// If not empty "Addr" is ignored and Redis clusters feature is used instead.
 	Clusters []string
+	// MasterName is the name of the master of a Redis Sentinel setup.
+	// If not empty "Addr" and "Clusters" are ignored and the client
+	// connects to the current master through the "Sentinels" nodes,
+	// following the failovers automatically.
+	MasterName string
+	// Sentinels a list of network addresses of the Sentinel nodes.
+	// See "MasterName".
+	Sentinels []string
+	// SentinelPassword is the optional password of the Sentinel nodes.
+	SentinelPassword string
 	// Use the specified Username to authenticate the current connection
 	// with one of the connections defined in the ACL list when connecting
 	// to a Redis 6.0 instance, or greater, that is using the Redis ACL system.
@@ -48,6 +62,8 @@ type Config struct {
 	// Timeout for connect, write and read, defaults to 30 seconds, 0 means no timeout.
 	Timeout time.Duration
 	// Prefix "myprefix-for-this-website". Defaults to "".
+	// The keys of the sessions are prefixed by it,
+	// so more than one application can share the same Redis database.
 	Prefix string
 
 	// TLSConfig will cause Dial to perform a TLS handshake using the provided
@@ -55,6 +71,12 @@ type Config struct {
 	// See https://golang.org/pkg/crypto/tls/#Config
 	TLSConfig *tls.Config
 
+	// LockExpiration is the expiration of a session's lock (see `Database.Lock`),
+	// so a lock of a crashed process does not block the session forever.
+	// It should be longer than the requests' duration.
+	// Defaults to 30 seconds.
+	LockExpiration time.Duration
+
 	// A Driver should support be a go client for redis communication.
 	// It can be set to a custom one or a mock one (for testing).
 	//
@@ -65,16 +87,17 @@ type Config struct {
 // DefaultConfig returns the default configuration for Redis service.
 func DefaultConfig() Config {
 	return Config{
-		Network:   DefaultRedisNetwork,
-		Addr:      DefaultRedisAddr,
-		Username:  "",
-		Password:  "",
-		Database:  "",
-		MaxActive: 10,
-		Timeout:   DefaultRedisTimeout,
-		Prefix:    "",
-		TLSConfig: nil,
-		Driver:    GoRedis(),
+		Network:        DefaultRedisNetwork,
+		Addr:           DefaultRedisAddr,
+		Username:       "",
+		Password:       "",
+		Database:       "",
+		MaxActive:      10,
+		Timeout:        DefaultRedisTimeout,
+		Prefix:         "",
+		TLSConfig:      nil,
+		LockExpiration: DefaultLockExpiration,
+		Driver:         GoRedis(),
 	}
 }
 
@@ -84,7 +107,10 @@ type Database struct {
 	logger *golog.Logger
 }
 
-var _ sessions.Database = (*Database)(nil)
+var (
+	_ sessions.Database = (*Database)(nil)
+	_ sessions.Locker   = (*Database)(nil)
+)
 
 // New returns a new redis sessions database.
 func New(cfg ...Config) *Database {
@@ -104,6 +130,10 @@ func New(cfg ...Config) *Database {
 			c.Addr = DefaultRedisAddr
 		}
 
+		if c.LockExpiration <= 0 {
+			c.LockExpiration = DefaultLockExpiration
+		}
+
 		if c.Driver == nil {
 			c.Driver = GoRedis()
 		}
@@ -137,8 +167,39 @@ const SessionIDKey = "session_id"
 
 // Acquire receives a session's lifetime from the database,
 // if the return value is LifeTime{} then the session manager sets the life time based on the expiration duration lives in configuration.
+//
+// The session's TTL is kept in sync with the session's lifetime,
+// a session entry without a TTL (e.g. created by an older version) is set to expire in "expires".
+// Drivers which implement the `AcquireDriver` interface acquire the session in a single round-trip.
 func (db *Database) Acquire(sid string, expires time.Duration) sessions.LifeTime {
 	sidKey := db.makeSID(sid)
+
+	if d, ok := db.c.Driver.(AcquireDriver); ok {
+		valueBytes, err := sessions.DefaultTranscoder.Marshal(sid)
+		if err != nil {
+			db.logger.Error(err)
+			return sessions.LifeTime{}
+		}
+
+		untilExpire, existed, err := d.Acquire(sidKey, SessionIDKey, valueBytes)
+		if err != nil {
+			db.logger.Debug(err)
+			return sessions.LifeTime{}
+		}
+
+		if existed && untilExpire > 0 {
+			return sessions.LifeTime{Time: time.Now().Add(untilExpire)}
+		}
+
+		if expires > 0 {
+			if err = db.c.Driver.UpdateTTL(sidKey, expires); err != nil {
+				db.logger.Debug(err)
+			}
+		}
+
+		return sessions.LifeTime{} // session manager will handle the rest.
+	}
+
 	if !db.c.Driver.Exists(sidKey) {
 		if err := db.Set(sid, SessionIDKey, sid, 0, false); err != nil {
 			db.logger.Debug(err)
@@ -152,6 +213,16 @@ func (db *Database) Acquire(sid string, expires time.Duration) sessions.LifeTime
 	}
 
 	untilExpire := db.c.Driver.TTL(sidKey)
+	if untilExpire <= 0 { // no TTL, sync it with the session's lifetime.
+		if expires > 0 {
+			if err := db.c.Driver.UpdateTTL(sidKey, expires); err != nil {
+				db.logger.Debug(err)
+			}
+		}
+
+		return sessions.LifeTime{}
+	}
+
 	return sessions.LifeTime{Time: time.Now().Add(untilExpire)}
 }
 
@@ -253,7 +324,7 @@ func (db *Database) Visit(sid string, cb func(key string, value interface{})) er
 
 // Len returns the length of the session's entries (keys).
 func (db *Database) Len(sid string) int {
-	return db.c.Driver.Len(sid)
+	return db.c.Driver.Len(db.makeSID(sid))
 }
 
 // Delete removes a session key value based on its key.
@@ -272,7 +343,7 @@ func (db *Database) Clear(sid string) error {
 		if key == SessionIDKey {
 			continue
 		}
-		if err := db.c.Driver.Delete(sid, key); err != nil {
+		if err := db.c.Driver.Delete(db.makeSID(sid), key); err != nil {
 			db.logger.Debugf("unable to delete session '%s' value of key: '%s': %v", sid, key, err)
 			return err
 		}
@@ -292,6 +363,59 @@ func (db *Database) Release(sid string) error {
 	return err
 }
 
+// lockRetryInterval is the interval between the lock attempts of a locked session.
+const lockRetryInterval = 25 * time.Millisecond
+
+// Lock locks the "sid" session across all the processes which share the redis database,
+// it completes the `sessions.Locker` interface, see `sessions.Config.Lock`.
+// The lock expires after the `Config.LockExpiration`.
+// It returns `sessions.ErrNotImplemented` if the Driver does not implement the `LockDriver` interface.
+func (db *Database) Lock(sid string, timeout time.Duration) (func(), error) {
+	d, ok := db.c.Driver.(LockDriver)
+	if !ok {
+		return nil, sessions.ErrNotImplemented
+	}
+
+	token, err := newLockToken()
+	if err != nil {
+		return nil, err
+	}
+
+	key := db.makeSID(sid) + ":lock"
+	deadline := time.Now().Add(timeout)
+	for {
+		locked, err := d.TryLock(key, token, db.c.LockExpiration)
+		if err != nil {
+			return nil, err
+		}
+
+		if locked {
+			break
+		}
+
+		if time.Now().Add(lockRetryInterval).After(deadline) {
+			return nil, sessions.ErrLockTimeout
+		}
+
+		time.Sleep(lockRetryInterval)
+	}
+
+	return func() {
+		if err := d.Unlock(key, token); err != nil {
+			db.logger.Debugf("unable to unlock session '%s': %v", sid, err)
+		}
+	}, nil
+}
+
+func newLockToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
 // Close terminates the redis connection.
 func (db *Database) Close() error {
 	return closeDB(db)
@@ -0,0 +1,166 @@
+package redis_test
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12/sessions"
+	"github.com/kataras/iris/v12/sessions/sessiondb/redis"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/alicebob/miniredis/v2/server"
+)
+
+func newTestDatabase(t *testing.T, cfg redis.Config) *redis.Database {
+	t.Helper()
+
+	cfg.Timeout = time.Second
+	cfg.Prefix = "test-"
+	db := redis.New(cfg)
+	t.Cleanup(func() { db.Close() })
+
+	return db
+}
+
+func TestDatabaseAcquire(t *testing.T) {
+	m := miniredis.RunT(t)
+	db := newTestDatabase(t, redis.Config{Addr: m.Addr()})
+
+	// A new session entry expires with the session.
+	if lt := db.Acquire("new", time.Hour); !lt.Time.IsZero() {
+		t.Fatalf("expected a new session's lifetime but got: %v", lt.Time)
+	}
+
+	if got := m.TTL("test-new"); got != time.Hour {
+		t.Fatalf("expected TTL: %s but got: %s", time.Hour, got)
+	}
+
+	// An existing session entry returns its lifetime.
+	if lt := db.Acquire("new", time.Hour); lt.Time.IsZero() || time.Until(lt.Time) > time.Hour {
+		t.Fatalf("expected the stored lifetime but got: %v", lt.Time)
+	}
+
+	// An existing session entry without a TTL is synced with the session's lifetime.
+	m.HSet("test-old", redis.SessionIDKey, "old")
+	if lt := db.Acquire("old", time.Hour); !lt.Time.IsZero() {
+		t.Fatalf("expected a new session's lifetime but got: %v", lt.Time)
+	}
+
+	if got := m.TTL("test-old"); got != time.Hour {
+		t.Fatalf("expected TTL: %s but got: %s", time.Hour, got)
+	}
+
+	if err := db.OnUpdateExpiration("old", 2*time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := m.TTL("test-old"); got != 2*time.Hour {
+		t.Fatalf("expected TTL: %s but got: %s", 2*time.Hour, got)
+	}
+
+	// The entry is removed once its TTL is passed.
+	m.FastForward(2 * time.Hour)
+	if m.Exists("test-old") {
+		t.Fatal("expected the expired session entry to be removed")
+	}
+}
+
+func TestDatabaseValues(t *testing.T) {
+	m := miniredis.RunT(t)
+	db := newTestDatabase(t, redis.Config{Addr: m.Addr()})
+
+	db.Acquire("sid", time.Hour)
+	if err := db.Set("sid", "name", "iris", 0, false); err != nil {
+		t.Fatal(err)
+	}
+
+	var name string
+	if err := db.Decode("sid", "name", &name); err != nil || name != "iris" {
+		t.Fatalf("expected value: iris but got: %s (%v)", name, err)
+	}
+
+	if n := db.Len("sid"); n != 2 { // including the session ID entry.
+		t.Fatalf("expected 2 entries but got: %d", n)
+	}
+
+	if err := db.Clear("sid"); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := db.Len("sid"); n != 1 {
+		t.Fatalf("expected the session ID entry only but got: %d entries", n)
+	}
+
+	if err := db.Release("sid"); err != nil {
+		t.Fatal(err)
+	}
+
+	if m.Exists("test-sid") {
+		t.Fatal("expected the released session entry to be removed")
+	}
+}
+
+func TestDatabaseLock(t *testing.T) {
+	m := miniredis.RunT(t)
+	db := newTestDatabase(t, redis.Config{Addr: m.Addr()})
+
+	unlock, err := db.Lock("sid", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = db.Lock("sid", 50*time.Millisecond); err != sessions.ErrLockTimeout {
+		t.Fatalf("expected ErrLockTimeout but got: %v", err)
+	}
+
+	unlock()
+	unlock, err = db.Lock("sid", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unlock()
+}
+
+func TestDatabaseSentinel(t *testing.T) {
+	master := miniredis.RunT(t)
+	host, port, err := net.SplitHostPort(master.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sentinel := miniredis.RunT(t)
+	err = sentinel.Server().Register("SENTINEL", func(c *server.Peer, cmd string, args []string) {
+		if len(args) != 2 || args[1] != "mymaster" {
+			c.WriteError("ERR unknown master")
+			return
+		}
+
+		switch strings.ToLower(args[0]) {
+		case "get-master-addr-by-name":
+			c.WriteStrings([]string{host, port})
+		case "sentinels":
+			c.WriteLen(0)
+		default:
+			c.WriteError("ERR unknown subcommand")
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db := newTestDatabase(t, redis.Config{MasterName: "mymaster", Sentinels: []string{sentinel.Addr()}})
+	db.Acquire("sid", time.Hour)
+	if err = db.Set("sid", "name", "iris", 0, false); err != nil {
+		t.Fatal(err)
+	}
+
+	if !master.Exists("test-sid") {
+		t.Fatal("expected the session entry to be stored to the master")
+	}
+
+	if sentinel.Exists("test-sid") {
+		t.Fatal("expected the session entry not to be stored to the sentinel")
+	}
+}
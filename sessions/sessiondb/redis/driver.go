@@ -19,8 +19,29 @@ type Driver interface {
 	Delete(sid, key string) error
 }
 
+// AcquireDriver is an optional interface which a Driver can implement
+// to acquire a session in a single round-trip, e.g. through pipelining.
+// Acquire should create the session entry, by setting the "key" to "value",
+// if it does not exist and return the session's TTL (zero or negative if the entry has no TTL)
+// and whether the entry already existed.
+type AcquireDriver interface {
+	Acquire(sid, key string, value interface{}) (ttl time.Duration, existed bool, err error)
+}
+
+// LockDriver is an optional interface which a Driver can implement
+// to lock the sessions across processes, see `Database.Lock`.
+type LockDriver interface {
+	// TryLock should set the "key" to the "token", with the "ttl" expiration,
+	// only if it does not exist and report whether it was set.
+	TryLock(key, token string, ttl time.Duration) (bool, error)
+	// Unlock should remove the "key" only if its value is the "token".
+	Unlock(key, token string) error
+}
+
 var (
-	_ Driver = (*GoRedisDriver)(nil)
+	_ Driver        = (*GoRedisDriver)(nil)
+	_ AcquireDriver = (*GoRedisDriver)(nil)
+	_ LockDriver    = (*GoRedisDriver)(nil)
 )
 
 // GoRedis returns the default Driver for the redis sessions database
@@ -14,6 +14,8 @@ type (
 	Options = redis.Options
 	// ClusterOptions is just a type alias for the go-redis Cluster Client Options.
 	ClusterOptions = redis.ClusterOptions
+	// FailoverOptions is just a type alias for the go-redis Sentinel (Failover) Client Options.
+	FailoverOptions = redis.FailoverOptions
 )
 
 // GoRedisClient is the interface which both
@@ -30,8 +32,9 @@ type GoRedisDriver struct {
 	client GoRedisClient
 	// Customize any go-redis fields manually
 	// before Connect.
-	ClientOptions  Options
-	ClusterOptions ClusterOptions
+	ClientOptions   Options
+	ClusterOptions  ClusterOptions
+	FailoverOptions FailoverOptions
 }
 
 var defaultContext = stdContext.Background()
@@ -85,7 +88,7 @@ func (r *GoRedisDriver) mergeClusterOptions(c Config) *ClusterOptions {
 	}
 
 	if opts.Password == "" {
-		opts.Username = c.Password
+		opts.Password = c.Password
 	}
 
 	if opts.ReadTimeout == 0 {
@@ -111,9 +114,57 @@ func (r *GoRedisDriver) mergeClusterOptions(c Config) *ClusterOptions {
 	return &opts
 }
 
+func (r *GoRedisDriver) mergeFailoverOptions(c Config) *FailoverOptions {
+	opts := r.FailoverOptions
+
+	if opts.MasterName == "" {
+		opts.MasterName = c.MasterName
+	}
+
+	if len(opts.SentinelAddrs) == 0 {
+		opts.SentinelAddrs = c.Sentinels
+	}
+
+	if opts.SentinelPassword == "" {
+		opts.SentinelPassword = c.SentinelPassword
+	}
+
+	if opts.Username == "" {
+		opts.Username = c.Username
+	}
+
+	if opts.Password == "" {
+		opts.Password = c.Password
+	}
+
+	if opts.DB == 0 {
+		opts.DB, _ = strconv.Atoi(c.Database)
+	}
+
+	if opts.ReadTimeout == 0 {
+		opts.ReadTimeout = c.Timeout
+	}
+
+	if opts.WriteTimeout == 0 {
+		opts.WriteTimeout = c.Timeout
+	}
+
+	if opts.TLSConfig == nil {
+		opts.TLSConfig = c.TLSConfig
+	}
+
+	if opts.PoolSize == 0 {
+		opts.PoolSize = c.MaxActive
+	}
+
+	return &opts
+}
+
 // Connect initializes the redis client.
 func (r *GoRedisDriver) Connect(c Config) error {
-	if len(c.Clusters) > 0 {
+	if c.MasterName != "" {
+		r.client = redis.NewFailoverClient(r.mergeFailoverOptions(c))
+	} else if len(c.Clusters) > 0 {
 		r.client = redis.NewClusterClient(r.mergeClusterOptions(c))
 	} else {
 		r.client = redis.NewClient(r.mergeClientOptions(c))
@@ -140,6 +191,46 @@ func (r *GoRedisDriver) Set(sid, key string, value interface{}) error {
 	return r.client.HSet(defaultContext, sid, key, value).Err()
 }
 
+// Acquire creates the session entry, if it does not exist, and returns its TTL
+// in a single round-trip (pipeline).
+func (r *GoRedisDriver) Acquire(sid, key string, value interface{}) (time.Duration, bool, error) {
+	var (
+		created *redis.BoolCmd
+		ttl     *redis.DurationCmd
+	)
+
+	_, err := r.client.Pipelined(defaultContext, func(p redis.Pipeliner) error {
+		created = p.HSetNX(defaultContext, sid, key, value)
+		ttl = p.PTTL(defaultContext, sid)
+		return nil
+	})
+	if err != nil {
+		return 0, false, err
+	}
+
+	return ttl.Val(), !created.Val(), nil
+}
+
+// TryLock sets the "key" to the "token" with the "ttl" expiration,
+// only if the key does not exist (SET NX PX).
+func (r *GoRedisDriver) TryLock(key, token string, ttl time.Duration) (bool, error) {
+	return r.client.SetNX(defaultContext, key, token, ttl).Result()
+}
+
+// unlockScript removes the lock only if it is still held by the same token,
+// an expired lock may be acquired by another process in the meantime.
+var unlockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// Unlock removes the "key" only if its value is the "token".
+func (r *GoRedisDriver) Unlock(key, token string) error {
+	return unlockScript.Run(defaultContext, r.client, []string{key}, token).Err()
+}
+
 // Get returns the associated value of the session's given "key".
 func (r *GoRedisDriver) Get(sid, key string) (interface{}, error) {
 	return r.client.HGet(defaultContext, sid, key).Bytes()
@@ -0,0 +1,418 @@
+package sql
+
+import (
+	stdsql "database/sql"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/kataras/iris/v12/sessions"
+
+	"github.com/kataras/golog"
+)
+
+const (
+	// DefaultTable is the default name of the sessions table, "iris_sessions".
+	// The values are stored in the "iris_sessions_values" one.
+	DefaultTable = "iris_sessions"
+	// DefaultGCInterval is the default interval of the expired sessions cleanup, 10 minutes.
+	DefaultGCInterval = 10 * time.Minute
+)
+
+// ErrConflict is returned by `Database.Set` when the session value
+// was modified by another application instance since it was last read by this one.
+// The write is dropped, read the value again to retry.
+var ErrConflict = errors.New("sessiondb/sql: value was modified concurrently")
+
+// Config the SQL database configuration used inside sessions.
+type Config struct {
+	// Dialect is the SQL flavor of the database, `Postgres` or `MySQL`.
+	// Defaults to `Postgres`.
+	Dialect Dialect
+	// Table is the name of the sessions table.
+	// Defaults to `DefaultTable`.
+	Table string
+	// GCInterval is the interval of the background cleanup of the expired sessions.
+	// Defaults to `DefaultGCInterval`, a negative value disables the cleanup.
+	GCInterval time.Duration
+	// Migrate, if true, creates the tables on `New`, see `Database.Migrate`.
+	Migrate bool
+}
+
+// Database the SQL (Postgres, MySQL) session storage
+// for deployments that cannot run a key-value store.
+//
+// The session entries are stored in the "Table" table
+// and their values, encoded by the `sessions.DefaultTranscoder`, in the "Table_values" one.
+// Writes are optimistic: each value holds a version and a `Set` of a value
+// which was read before fails with `ErrConflict`
+// if another application instance modified it in the meantime.
+type Database struct {
+	// DB is the underline database connection.
+	DB *stdsql.DB
+
+	config  Config
+	queries queries
+	logger  *golog.Logger
+
+	// versions holds the versions of the values read by this instance,
+	// cleared on each cleanup.
+	versions sync.Map // sid+key:int64.
+
+	closeOnce sync.Once
+	gcStop    chan struct{}
+}
+
+var _ sessions.Database = (*Database)(nil)
+
+// New returns a new SQL database session storage
+// based on an open "db" connection and the given configuration.
+// The driver of the "db" is imported by the caller, i.e:
+//  import _ "github.com/jackc/pgx/v4/stdlib"
+//
+//  conn, err := sql.Open("pgx", "postgres://localhost/app")
+//  db, err := sessionsql.New(conn, sessionsql.Config{Dialect: sessionsql.Postgres, Migrate: true})
+//  sess.UseDatabase(db)
+func New(db *stdsql.DB, cfg Config) (*Database, error) {
+	if db == nil {
+		return nil, errors.New("sessiondb/sql: db is nil")
+	}
+
+	if cfg.Dialect.Name == "" {
+		cfg.Dialect = Postgres
+	}
+
+	if cfg.Table == "" {
+		cfg.Table = DefaultTable
+	}
+
+	if cfg.GCInterval == 0 {
+		cfg.GCInterval = DefaultGCInterval
+	}
+
+	d := &Database{
+		DB:      db,
+		config:  cfg,
+		queries: newQueries(cfg.Dialect, cfg.Table),
+		logger:  golog.Default,
+		gcStop:  make(chan struct{}),
+	}
+
+	if cfg.Migrate {
+		if err := d.Migrate(); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.GCInterval > 0 {
+		go d.gcLoop(cfg.GCInterval)
+	}
+
+	return d, nil
+}
+
+// Migrate creates the sessions and values tables, and the expiration index,
+// if they do not exist yet.
+func (db *Database) Migrate() error {
+	for _, query := range []string{db.queries.createSessions, db.queries.createValues, db.queries.createIndex} {
+		if query == "" {
+			continue
+		}
+
+		if _, err := db.DB.Exec(query); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GC removes the expired sessions and their values.
+// It's called every "GCInterval" automatically.
+func (db *Database) GC() error {
+	now := time.Now().UTC()
+
+	tx, err := db.DB.Begin()
+	if err != nil {
+		return err
+	}
+
+	if _, err = tx.Exec(db.queries.gcValues, now); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if _, err = tx.Exec(db.queries.gcSessions, now); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (db *Database) gcLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-db.gcStop:
+			return
+		case <-ticker.C:
+			if err := db.GC(); err != nil {
+				db.logger.Errorf("[sessionsdb.sql.GC] %v", err)
+			}
+
+			db.versions.Range(func(key, _ interface{}) bool {
+				db.versions.Delete(key)
+				return true
+			})
+		}
+	}
+}
+
+// SetLogger sets the logger once before server ran.
+// By default the Iris one is injected.
+func (db *Database) SetLogger(logger *golog.Logger) {
+	db.logger = logger
+}
+
+func expiresAt(expires time.Duration) interface{} {
+	if expires <= 0 {
+		return nil
+	}
+
+	return time.Now().Add(expires).UTC()
+}
+
+// Acquire receives a session's lifetime from the database,
+// if the return value is LifeTime{} then the session manager sets the life time based on the expiration duration lives in configuration.
+func (db *Database) Acquire(sid string, expires time.Duration) sessions.LifeTime {
+	var t stdsql.NullTime
+	err := db.DB.QueryRow(db.queries.selectSession, sid).Scan(&t)
+	if err == nil {
+		if !t.Valid {
+			return sessions.LifeTime{}
+		}
+
+		if t.Time.After(time.Now()) {
+			// found, return the expiration.
+			return sessions.LifeTime{Time: t.Time}
+		}
+
+		// expired but not collected yet, start over.
+		if err = db.Release(sid); err != nil {
+			return sessions.LifeTime{}
+		}
+	} else if err != stdsql.ErrNoRows {
+		db.logger.Error(err)
+		return sessions.LifeTime{}
+	}
+
+	// not found, create an entry and return an empty lifetime, session manager will do its job.
+	if _, err = db.DB.Exec(db.queries.insertSession, sid, expiresAt(expires)); err != nil {
+		db.logger.Error(err)
+	}
+
+	return sessions.LifeTime{}
+}
+
+// OnUpdateExpiration re-sets the expiration of the session entry.
+func (db *Database) OnUpdateExpiration(sid string, newExpires time.Duration) error {
+	res, err := db.DB.Exec(db.queries.updateSession, expiresAt(newExpires), sid)
+	if err != nil {
+		db.logger.Error(err)
+		return err
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return sessions.ErrNotFound
+	}
+
+	return nil
+}
+
+func versionKey(sid, key string) string {
+	return sid + "\x00" + key
+}
+
+// Set sets a key value of a specific session.
+// The "ttl" is the session's one, the "immutable" is ignored.
+//
+// It returns `ErrConflict` if the value was read by this instance
+// and another instance modified it since then.
+func (db *Database) Set(sid string, key string, value interface{}, _ time.Duration, _ bool) error {
+	valueBytes, err := sessions.DefaultTranscoder.Marshal(value)
+	if err != nil {
+		db.logger.Error(err)
+		return err
+	}
+
+	vkey := versionKey(sid, key)
+	if v, ok := db.versions.Load(vkey); ok {
+		version := v.(int64)
+		res, err := db.DB.Exec(db.queries.updateValue, valueBytes, sid, key, version)
+		if err != nil {
+			db.logger.Error(err)
+			return err
+		}
+
+		if n, err := res.RowsAffected(); err == nil && n == 0 {
+			db.versions.Delete(vkey)
+			db.logger.Debugf("[sessionsdb.sql.Set] %s: %s: %v", sid, key, ErrConflict)
+			return ErrConflict
+		}
+
+		db.versions.Store(vkey, version+1)
+		return nil
+	}
+
+	if _, err = db.DB.Exec(db.queries.upsertValue, sid, key, valueBytes); err != nil {
+		db.logger.Error(err)
+	}
+
+	return err
+}
+
+// Get retrieves a session value based on the key.
+func (db *Database) Get(sid string, key string) (value interface{}) {
+	if err := db.Decode(sid, key, &value); err == nil {
+		return value
+	}
+
+	return nil
+}
+
+// Decode binds the "outPtr" to the value associated to the provided "key".
+func (db *Database) Decode(sid, key string, outPtr interface{}) error {
+	var (
+		valueBytes []byte
+		version    int64
+	)
+
+	err := db.DB.QueryRow(db.queries.selectValue, sid, key).Scan(&valueBytes, &version)
+	if err != nil {
+		if err != stdsql.ErrNoRows {
+			db.logger.Error(err)
+		}
+
+		return err
+	}
+
+	db.versions.Store(versionKey(sid, key), version)
+	return sessions.DefaultTranscoder.Unmarshal(valueBytes, outPtr)
+}
+
+// Visit loops through all session keys and values.
+func (db *Database) Visit(sid string, cb func(key string, value interface{})) error {
+	rows, err := db.DB.Query(db.queries.selectValues, sid)
+	if err != nil {
+		db.logger.Error(err)
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			key        string
+			valueBytes []byte
+			version    int64
+			value      interface{}
+		)
+
+		if err = rows.Scan(&key, &valueBytes, &version); err != nil {
+			db.logger.Errorf("[sessionsdb.sql.Visit] %v", err)
+			return err
+		}
+
+		if err = sessions.DefaultTranscoder.Unmarshal(valueBytes, &value); err != nil {
+			db.logger.Errorf("[sessionsdb.sql.Visit] %v", err)
+			return err
+		}
+
+		db.versions.Store(versionKey(sid, key), version)
+		cb(key, value)
+	}
+
+	return rows.Err()
+}
+
+// Len returns the length of the session's entries (keys).
+func (db *Database) Len(sid string) (n int) {
+	if err := db.DB.QueryRow(db.queries.countValues, sid).Scan(&n); err != nil {
+		db.logger.Error(err)
+	}
+
+	return
+}
+
+// Delete removes a session key value based on its key.
+func (db *Database) Delete(sid string, key string) (deleted bool) {
+	db.versions.Delete(versionKey(sid, key))
+
+	res, err := db.DB.Exec(db.queries.deleteValue, sid, key)
+	if err != nil {
+		db.logger.Error(err)
+		return false
+	}
+
+	n, err := res.RowsAffected()
+	return err == nil && n > 0
+}
+
+// Clear removes all session key values but it keeps the session entry.
+func (db *Database) Clear(sid string) error {
+	db.forgetVersions(sid)
+
+	if _, err := db.DB.Exec(db.queries.deleteValues, sid); err != nil {
+		db.logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// Release destroys the session, it clears and removes the session entry,
+// session manager will create a new session ID on the next request after this call.
+func (db *Database) Release(sid string) error {
+	db.forgetVersions(sid)
+
+	tx, err := db.DB.Begin()
+	if err != nil {
+		db.logger.Error(err)
+		return err
+	}
+
+	if _, err = tx.Exec(db.queries.deleteValues, sid); err == nil {
+		_, err = tx.Exec(db.queries.deleteSession, sid)
+	}
+
+	if err != nil {
+		tx.Rollback()
+		db.logger.Warnf("Database.Release: %s: %v", sid, err)
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (db *Database) forgetVersions(sid string) {
+	prefix := versionKey(sid, "")
+	db.versions.Range(func(key, _ interface{}) bool {
+		if k := key.(string); len(k) >= len(prefix) && k[:len(prefix)] == prefix {
+			db.versions.Delete(key)
+		}
+
+		return true
+	})
+}
+
+// Close stops the background cleanup and closes the database connection.
+func (db *Database) Close() (err error) {
+	db.closeOnce.Do(func() {
+		close(db.gcStop)
+		err = db.DB.Close()
+	})
+
+	return
+}
@@ -0,0 +1,121 @@
+package sql_test
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12/sessions"
+	sessionsql "github.com/kataras/iris/v12/sessions/sessiondb/sql"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func newTestDatabase(t *testing.T) (*sessionsql.Database, sqlmock.Sqlmock) {
+	t.Helper()
+
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := sessionsql.New(conn, sessionsql.Config{GCInterval: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		mock.ExpectClose()
+		db.Close()
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	return db, mock
+}
+
+func encode(t *testing.T, value interface{}) []byte {
+	t.Helper()
+
+	b, err := sessions.DefaultTranscoder.Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return b
+}
+
+func TestDatabaseOptimisticSet(t *testing.T) {
+	db, mock := newTestDatabase(t)
+
+	var (
+		selectValue = regexp.QuoteMeta("SELECT value, version FROM iris_sessions_values WHERE sid = $1 AND name = $2")
+		updateValue = regexp.QuoteMeta("UPDATE iris_sessions_values SET value = $1, version = version + 1 WHERE sid = $2 AND name = $3 AND version = $4")
+		upsertValue = regexp.QuoteMeta("INSERT INTO iris_sessions_values (sid, name, value, version) VALUES ($1, $2, $3, 1)")
+	)
+
+	// Read at version 3, write as version 4.
+	mock.ExpectQuery(selectValue).WithArgs("sid", "counter").
+		WillReturnRows(sqlmock.NewRows([]string{"value", "version"}).AddRow(encode(t, 1), 3))
+	mock.ExpectExec(updateValue).WithArgs(encode(t, 2), "sid", "counter", 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	// The next write expects the version 4 which was modified by another instance.
+	mock.ExpectExec(updateValue).WithArgs(encode(t, 3), "sid", "counter", 4).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	// The version of the conflict is forgotten, the next write is not conditional.
+	mock.ExpectExec(upsertValue).WithArgs("sid", "counter", encode(t, 3)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	var counter int
+	if err := db.Decode("sid", "counter", &counter); err != nil || counter != 1 {
+		t.Fatalf("expected value: 1 but got: %d (%v)", counter, err)
+	}
+
+	if err := db.Set("sid", "counter", 2, 0, false); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := db.Set("sid", "counter", 3, 0, false); err != sessionsql.ErrConflict {
+		t.Fatalf("expected ErrConflict but got: %v", err)
+	}
+
+	if err := db.Set("sid", "counter", 3, 0, false); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDatabaseGC(t *testing.T) {
+	db, mock := newTestDatabase(t)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM iris_sessions_values WHERE sid IN (SELECT sid FROM iris_sessions WHERE expires_at IS NOT NULL AND expires_at < $1)")).
+		WithArgs(sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM iris_sessions WHERE expires_at IS NOT NULL AND expires_at < $1")).
+		WithArgs(sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	if err := db.GC(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDatabaseAcquireExpired(t *testing.T) {
+	db, mock := newTestDatabase(t)
+
+	// The expired but not collected yet session is released and created again.
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT expires_at FROM iris_sessions WHERE sid = $1")).WithArgs("sid").
+		WillReturnRows(sqlmock.NewRows([]string{"expires_at"}).AddRow(time.Now().Add(-time.Minute)))
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM iris_sessions_values WHERE sid = $1")).WithArgs("sid").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM iris_sessions WHERE sid = $1")).WithArgs("sid").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO iris_sessions (sid, expires_at) VALUES ($1, $2) ON CONFLICT (sid) DO NOTHING")).
+		WithArgs("sid", sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if lt := db.Acquire("sid", time.Hour); !lt.Time.IsZero() {
+		t.Fatalf("expected a new session's lifetime but got: %v", lt.Time)
+	}
+}
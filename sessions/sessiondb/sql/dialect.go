@@ -0,0 +1,144 @@
+package sql
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Dialect describes the SQL flavor of the database,
+// see the `Postgres` and `MySQL` package-level variables.
+type Dialect struct {
+	// Name is the name of the dialect, e.g. "postgres".
+	Name string
+	// Placeholder returns the bind parameter of the n-th (starting from 1) argument,
+	// e.g. "$1" for Postgres and "?" for MySQL.
+	Placeholder func(n int) string
+	// BlobType is the column type of the encoded session values.
+	BlobType string
+	// TimeType is the column type of the expiration time.
+	TimeType string
+	// Upsert is the clause appended to the INSERT statement of a session value
+	// which updates the value and increments its version on a key conflict.
+	Upsert string
+	// InsertIgnore is the clause appended to the INSERT statement of a session entry
+	// which ignores a key conflict.
+	InsertIgnore string
+	// InsertIgnorePrefix is the INSERT statement's prefix of a session entry
+	// which ignores a key conflict, MySQL only.
+	InsertIgnorePrefix string
+	// CreateIndex is the statement which creates the expiration index
+	// of the sessions table, if the dialect supports "IF NOT EXISTS".
+	CreateIndex string
+	// InlineIndex is the expiration index definition
+	// of the sessions table's CREATE statement, otherwise.
+	InlineIndex string
+}
+
+var (
+	// Postgres is the PostgreSQL dialect.
+	Postgres = Dialect{
+		Name: "postgres",
+		Placeholder: func(n int) string {
+			return "$" + strconv.Itoa(n)
+		},
+		BlobType:     "BYTEA",
+		TimeType:     "TIMESTAMP WITH TIME ZONE",
+		Upsert:       " ON CONFLICT (sid, name) DO UPDATE SET value = EXCLUDED.value, version = {table}.version + 1",
+		InsertIgnore: " ON CONFLICT (sid) DO NOTHING",
+		CreateIndex:  "CREATE INDEX IF NOT EXISTS {table}_expires_at_idx ON {table} (expires_at)",
+	}
+	// MySQL is the MySQL (and MariaDB) dialect.
+	// Note that the connection requires the parseTime=true parameter.
+	MySQL = Dialect{
+		Name: "mysql",
+		Placeholder: func(int) string {
+			return "?"
+		},
+		BlobType:           "LONGBLOB",
+		TimeType:           "DATETIME(6)",
+		Upsert:             " ON DUPLICATE KEY UPDATE value = VALUES(value), version = version + 1",
+		InsertIgnorePrefix: "INSERT IGNORE",
+		InlineIndex:        ", INDEX expires_at_idx (expires_at)",
+	}
+)
+
+// bind replaces the "?" bind parameters of the "query"
+// with the dialect's placeholders.
+func (d Dialect) bind(query string) string {
+	var (
+		b strings.Builder
+		n int
+	)
+
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString(d.Placeholder(n))
+			continue
+		}
+
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+// queries holds the dialect-specific statements of a `Database`.
+type queries struct {
+	createSessions string
+	createValues   string
+	createIndex    string
+
+	selectSession string
+	insertSession string
+	updateSession string
+	deleteSession string
+	upsertValue   string
+	updateValue   string
+	selectValue   string
+	selectValues  string
+	countValues   string
+	deleteValue   string
+	deleteValues  string
+	gcValues      string
+	gcSessions    string
+}
+
+func newQueries(d Dialect, table string) queries {
+	valuesTable := table + "_values"
+
+	insertSession := "INSERT"
+	if d.InsertIgnorePrefix != "" {
+		insertSession = d.InsertIgnorePrefix
+	}
+	insertSession += " INTO " + table + " (sid, expires_at) VALUES (?, ?)" + d.InsertIgnore
+
+	return queries{
+		createSessions: "CREATE TABLE IF NOT EXISTS " + table + " (" +
+			"sid VARCHAR(255) NOT NULL PRIMARY KEY, " +
+			"expires_at " + d.TimeType + " NULL" + d.InlineIndex + ")",
+		createValues: "CREATE TABLE IF NOT EXISTS " + valuesTable + " (" +
+			"sid VARCHAR(255) NOT NULL, " +
+			"name VARCHAR(255) NOT NULL, " +
+			"value " + d.BlobType + " NOT NULL, " +
+			"version BIGINT NOT NULL DEFAULT 1, " +
+			"PRIMARY KEY (sid, name))",
+		createIndex: strings.ReplaceAll(d.CreateIndex, "{table}", table),
+
+		selectSession: d.bind("SELECT expires_at FROM " + table + " WHERE sid = ?"),
+		insertSession: d.bind(insertSession),
+		updateSession: d.bind("UPDATE " + table + " SET expires_at = ? WHERE sid = ?"),
+		deleteSession: d.bind("DELETE FROM " + table + " WHERE sid = ?"),
+		upsertValue: d.bind("INSERT INTO " + valuesTable + " (sid, name, value, version) VALUES (?, ?, ?, 1)" +
+			strings.ReplaceAll(d.Upsert, "{table}", valuesTable)),
+		updateValue:  d.bind("UPDATE " + valuesTable + " SET value = ?, version = version + 1 WHERE sid = ? AND name = ? AND version = ?"),
+		selectValue:  d.bind("SELECT value, version FROM " + valuesTable + " WHERE sid = ? AND name = ?"),
+		selectValues: d.bind("SELECT name, value, version FROM " + valuesTable + " WHERE sid = ?"),
+		countValues:  d.bind("SELECT COUNT(*) FROM " + valuesTable + " WHERE sid = ?"),
+		deleteValue:  d.bind("DELETE FROM " + valuesTable + " WHERE sid = ? AND name = ?"),
+		deleteValues: d.bind("DELETE FROM " + valuesTable + " WHERE sid = ?"),
+		gcValues: d.bind("DELETE FROM " + valuesTable + " WHERE sid IN (SELECT sid FROM " + table +
+			" WHERE expires_at IS NOT NULL AND expires_at < ?)"),
+		gcSessions: d.bind("DELETE FROM " + table + " WHERE expires_at IS NOT NULL AND expires_at < ?"),
+	}
+}
@@ -22,6 +22,11 @@ type Sessions struct {
 	provider *provider
 
 	cookieOptions []context.CookieOption // options added on each session cookie action.
+
+	locker    Locker // see `Config.Lock`.
+	memLocker *memLocker
+
+	cookieStore *CookieStore // see `UseDatabase`.
 }
 
 // New returns a new fast, feature-rich sessions manager
@@ -41,21 +46,32 @@ func New(cfg Config) *Sessions {
 		cookieOptions = append(cookieOptions, context.CookieEncoding(cfg.Encoding, cfg.Cookie))
 	}
 
+	memLocker := newMemLocker()
 	return &Sessions{
 		cookieOptions: cookieOptions,
 		config:        cfg.Validate(),
 		provider:      newProvider(),
+		locker:        memLocker,
+		memLocker:     memLocker,
 	}
 }
 
 // UseDatabase adds a session database to the manager's provider,
-// a session db doesn't have write access
+// a session db doesn't have write access.
+// If the database implements the `Locker` interface
+// then it's used to lock the sessions, see `Config.Lock`.
 func (s *Sessions) UseDatabase(db Database) {
 	db.SetLogger(s.config.Logger) // inject the logger.
 	host.RegisterOnInterrupt(func() {
 		db.Close()
 	})
 	s.provider.RegisterDatabase(db)
+
+	if locker, ok := db.(Locker); ok {
+		s.UseLocker(locker)
+	}
+
+	s.cookieStore, _ = db.(*CookieStore)
 }
 
 // GetCookieOptions returns the cookie options registered
@@ -66,11 +82,16 @@ func (s *Sessions) GetCookieOptions() []context.CookieOption {
 
 // updateCookie gains the ability of updating the session browser cookie to any method which wants to update it
 func (s *Sessions) updateCookie(ctx *context.Context, sid string, expires time.Duration, options ...context.CookieOption) {
+	s.upsertCookie(ctx, s.newCookie(ctx, s.config.Cookie, sid, expires), options)
+}
+
+// newCookie returns a new session cookie which expires after "expires".
+func (s *Sessions) newCookie(ctx *context.Context, name, value string, expires time.Duration) *http.Cookie {
 	cookie := &http.Cookie{}
 
 	// The RFC makes no mention of encoding url value, so here I think to encode both sessionid key and the value using the safe(to put and to use as cookie) url-encoding
-	cookie.Name = s.config.Cookie
-	cookie.Value = sid
+	cookie.Name = name
+	cookie.Value = value
 	cookie.Path = "/"
 	cookie.HttpOnly = true
 
@@ -81,12 +102,25 @@ func (s *Sessions) updateCookie(ctx *context.Context, sid string, expires time.D
 		if expires == 0 { // unlimited life
 			cookie.Expires = context.CookieExpireUnlimited
 		} else { // > 0
-			cookie.Expires = time.Now().Add(expires)
+			cookie.Expires = ctx.Now().Add(expires)
 		}
-		cookie.MaxAge = int(time.Until(cookie.Expires).Seconds())
+		cookie.MaxAge = int(cookie.Expires.Sub(ctx.Now()).Seconds())
+	}
+
+	return cookie
+}
+
+// extraCookieOptions returns the options of the cookies which accompany the session's one,
+// e.g. the data cookies of a `CookieStore` and the remember-me cookie,
+// they share its domain and security.
+func (s *Sessions) extraCookieOptions(options []context.CookieOption) []context.CookieOption {
+	opts := make([]context.CookieOption, 0, len(s.cookieOptions)+len(options)+1)
+	opts = append(opts, s.cookieOptions...)
+	if !s.config.DisableSubdomainPersistence {
+		opts = append(opts, context.CookieAllowSubdomains())
 	}
 
-	s.upsertCookie(ctx, cookie, options)
+	return append(opts, options...)
 }
 
 func (s *Sessions) upsertCookie(ctx *context.Context, cookie *http.Cookie, cookieOptions []context.CookieOption) {
@@ -118,23 +152,38 @@ func (s *Sessions) getCookie(ctx *context.Context, cookieOptions []context.Cooki
 func (s *Sessions) Start(ctx *context.Context, cookieOptions ...context.CookieOption) *Session {
 	cookieValue := s.getCookie(ctx, cookieOptions)
 
-	if cookieValue == "" { // cookie doesn't exist, let's generate a session and set a cookie.
-		sid := s.config.SessionIDGenerator(ctx)
+	// The request's clock (see `Context.Now`) is used to set and check the expiration.
+	now := ctx.Now()
+	expires := s.lifetimeOf(now, now)
 
-		sess := s.provider.Init(s, sid, s.config.Expires)
-		// n := s.provider.db.Len(sid)
-		// fmt.Printf("db.Len(%s) = %d\n", sid, n)
-		// if n > 0 {
-		// 	s.provider.db.Visit(sid, func(key string, value interface{}) {
-		// 		fmt.Printf("%s=%s\n", key, value)
-		// 	})
-		// }
-		s.updateCookie(ctx, sid, s.config.Expires, cookieOptions...)
+	if cookieValue != "" {
+		sess := s.provider.Read(s, cookieValue, expires, now)
+		if !sess.Lifetime.HasExpiredAt(now) && !s.hasReachedMaxLifetime(sess, now) {
+			s.slide(ctx, sess, cookieOptions)
+			return sess
+		}
 
-		return sess
+		// The request's clock (see `Context.Now`) is after the session's expiration,
+		// the expiration timer did not fire yet, e.g. on tests with a mock clock.
+		s.provider.fireExpire(cookieValue, s.expireReason(sess, now))
+		s.provider.Destroy(cookieValue)
 	}
 
-	return s.provider.Read(s, cookieValue, s.config.Expires)
+	// cookie doesn't exist (or expired), let's generate a session and set a cookie.
+	sid := s.config.SessionIDGenerator(ctx)
+
+	sess := s.provider.Init(s, sid, expires, now)
+	s.provider.fireCreate(sid)
+	// n := s.provider.db.Len(sid)
+	// fmt.Printf("db.Len(%s) = %d\n", sid, n)
+	// if n > 0 {
+	// 	s.provider.db.Visit(sid, func(key string, value interface{}) {
+	// 		fmt.Printf("%s=%s\n", key, value)
+	// 	})
+	// }
+	s.updateCookie(ctx, sid, s.cookieLifetime(expires), cookieOptions...)
+
+	return sess
 }
 
 const sessionContextKey = "iris.session"
@@ -145,9 +194,32 @@ const sessionContextKey = "iris.session"
 // Call `Handler()` once per sessions manager.
 func (s *Sessions) Handler(requestOptions ...context.CookieOption) context.Handler {
 	return func(ctx *context.Context) {
+		var requestSid string
+		if s.cookieStore != nil {
+			requestSid = s.getCookie(ctx, requestOptions)
+			s.cookieStore.load(ctx, s, requestSid, requestOptions)
+		}
+
 		session := s.Start(ctx, requestOptions...) // this cookie's end-developer's custom options.
 
+		if s.cookieStore != nil {
+			defer s.cookieStore.attach(ctx, requestSid, session, requestOptions)()
+		}
+
+		if s.config.Lock {
+			unlock, err := s.lock(session.ID())
+			if err != nil {
+				s.config.Logger.Debugf("sessions: lock: %s: %v", session.ID(), err)
+				ctx.StopWithError(http.StatusServiceUnavailable, err)
+				return
+			}
+			defer unlock()
+		}
+
 		ctx.Values().Set(sessionContextKey, session)
+		if session.HasFlash() {
+			ctx.ViewData(s.config.FlashViewDataKey, Flashes{session: session})
+		}
 		ctx.Next()
 	}
 }
@@ -195,7 +267,7 @@ func (s *Sessions) UpdateExpiration(ctx *context.Context, expires time.Duration,
 	}
 
 	// we should also allow it to expire when the browser closed
-	err := s.provider.UpdateExpiration(cookieValue, expires)
+	err := s.provider.UpdateExpiration(cookieValue, expires, ctx.Now())
 	if err == nil || expires == -1 {
 		s.updateCookie(ctx, cookieValue, expires, cookieOptions...)
 	}
@@ -217,6 +289,38 @@ func (s *Sessions) OnDestroy(listeners ...DestroyListener) {
 	}
 }
 
+// CreateListener is the form of a create listener.
+// Look `OnCreate` for more.
+type CreateListener func(sid string)
+
+// OnCreate registers one or more create listeners.
+// A create listener is fired when a new session is started for a client,
+// e.g. to maintain a registry of the online users.
+// Note that if a create listener is blocking, then the session manager will delay respectfully,
+// use a goroutine inside the listener to avoid that behavior.
+func (s *Sessions) OnCreate(listeners ...CreateListener) {
+	for _, ln := range listeners {
+		s.provider.registerCreateListener(ln)
+	}
+}
+
+// UpdateListener is the form of an update listener.
+// Look `OnUpdate` for more.
+type UpdateListener func(sid string, key string, value interface{})
+
+// OnUpdate registers one or more update listeners.
+// An update listener is fired when a session value is stored (`Set`, `SetImmutable`, `Increment`...),
+// with a nil "value" when the "key" is deleted (`Delete`)
+// and with an empty "key" when all the values are removed (`Clear`), e.g. to keep an audit trail.
+// Flash messages do not fire the update listeners.
+// Note that if an update listener is blocking, then the session manager will delay respectfully,
+// use a goroutine inside the listener to avoid that behavior.
+func (s *Sessions) OnUpdate(listeners ...UpdateListener) {
+	for _, ln := range listeners {
+		s.provider.registerUpdateListener(ln)
+	}
+}
+
 // Destroy removes the session data, the associated cookie
 // and the Context's session value.
 // Next calls of `sessions.Get` will occur to a nil Session,
@@ -1,6 +1,11 @@
 package sessions_test
 
 import (
+	"fmt"
+	"net/http"
+	"os"
+	"reflect"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -324,3 +329,645 @@ func TestSessionsUpdateExpirationConcurrently(t *testing.T) {
 	tt.Status(httptest.StatusOK).Body().Equal(id)
 	tt.Cookie(cookieName).MaxAge().InRange(29*time.Minute, 30*time.Minute)
 }
+
+func TestSessionsSlidingExpiration(t *testing.T) {
+	cookieName := "mycustomsessionid"
+	sess := sessions.New(sessions.Config{
+		Cookie:      cookieName,
+		Expires:     500 * time.Millisecond,
+		Sliding:     true,
+		MaxLifetime: 1500 * time.Millisecond,
+	})
+
+	expired := make(chan sessions.ExpireReason, 2)
+	sess.OnExpire(func(sid string, reason sessions.ExpireReason) {
+		expired <- reason
+	})
+
+	app := iris.New()
+	app.Use(sess.Handler())
+	app.Get("/set", func(ctx iris.Context) {
+		sessions.Get(ctx).Set("logged", true)
+	})
+	app.Get("/get", func(ctx iris.Context) {
+		ctx.Writef("%v", sessions.Get(ctx).GetBooleanDefault("logged", false))
+	})
+
+	// The cookie is sent manually, its sub-second expiration
+	// would be rounded by the client's cookie jar.
+	e := httptest.New(t, app, httptest.URL("http://example.com"))
+	sid := e.GET("/set").Expect().Status(httptest.StatusOK).Cookie(cookieName).Value().Raw()
+
+	// Active sessions are renewed, even after their initial expiration.
+	for i := 0; i < 4; i++ {
+		time.Sleep(300 * time.Millisecond)
+		tt := e.GET("/get").WithCookie(cookieName, sid).Expect().Status(httptest.StatusOK)
+		tt.Body().Equal("true")
+		tt.Cookie(cookieName).Value().Equal(sid)
+	}
+
+	// But not after their maximum lifetime.
+	select {
+	case reason := <-expired:
+		if reason != sessions.ExpireAbsolute {
+			t.Fatalf("expected an absolute expiration but got: %s", reason)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected an absolute expiration")
+	}
+	e.GET("/get").WithCookie(cookieName, sid).Expect().Status(httptest.StatusOK).Body().Equal("false")
+
+	// Idle sessions expire.
+	select {
+	case reason := <-expired:
+		if reason != sessions.ExpireIdle {
+			t.Fatalf("expected an idle expiration but got: %s", reason)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected an idle expiration")
+	}
+}
+
+func TestFlashMessagesDiscardUnread(t *testing.T) {
+	sess := sessions.New(sessions.Config{Cookie: "mycustomsessionid", DiscardUnreadFlashes: true})
+
+	app := iris.New()
+	app.Use(sess.Handler())
+	app.Post("/save", func(ctx iris.Context) {
+		sessions.Get(ctx).SetFlash("success", "Data saved!")
+		ctx.Redirect("/page", iris.StatusSeeOther)
+	})
+	app.Get("/page", func(ctx iris.Context) {
+		flashes, ok := ctx.GetViewData()[sessions.DefaultFlashViewDataKey].(sessions.Flashes)
+		if !ok {
+			ctx.WriteString("none")
+			return
+		}
+
+		ctx.WriteString(flashes.GetString("success"))
+	})
+	app.Post("/notify", func(ctx iris.Context) {
+		sessions.Get(ctx).SetFlash("success", "Data saved!")
+	})
+	app.Get("/other", func(ctx iris.Context) {
+		ctx.Writef("%v", sessions.Get(ctx).HasFlash())
+	})
+
+	e := httptest.New(t, app, httptest.URL("http://example.com"))
+
+	// Post-redirect-get, the message is exposed to the views of the redirected page only.
+	e.POST("/save").Expect().Status(httptest.StatusOK).Body().Equal("Data saved!")
+	e.GET("/page").Expect().Status(httptest.StatusOK).Body().Equal("none")
+
+	// An unread message survives exactly one subsequent request.
+	e.POST("/notify").Expect().Status(httptest.StatusOK)
+	e.GET("/other").Expect().Status(httptest.StatusOK).Body().Equal("true")
+	e.GET("/other").Expect().Status(httptest.StatusOK).Body().Equal("false")
+}
+
+func TestSessionsRegenerate(t *testing.T) {
+	cookieName := "mycustomsessionid"
+	sess := sessions.New(sessions.Config{Cookie: cookieName, Expires: time.Minute})
+
+	app := iris.New()
+	app.Use(sess.Handler())
+	app.Use(func(ctx iris.Context) {
+		if username := ctx.GetHeader("X-User"); username != "" {
+			ctx.SetUser(&context.SimpleUser{ID: username, Username: username})
+		}
+		ctx.Next()
+	})
+	app.Use(sess.RegenerateOnAuth())
+	app.Get("/set", func(ctx iris.Context) {
+		sessions.Get(ctx).Set("cart", "book")
+	})
+	app.Get("/get", func(ctx iris.Context) {
+		s := sessions.Get(ctx)
+		ctx.Writef("%s:%s", s.ID(), s.GetString("cart"))
+	})
+
+	e := httptest.New(t, app, httptest.URL("http://example.com"))
+
+	anonymousSid := e.GET("/set").Expect().Status(httptest.StatusOK).Cookie(cookieName).Value().Raw()
+
+	// The session ID changes on login, the values are kept.
+	tt := e.GET("/get").WithCookie(cookieName, anonymousSid).WithHeader("X-User", "kataras").Expect().Status(httptest.StatusOK)
+	userSid := tt.Cookie(cookieName).Value().NotEqual(anonymousSid).Raw()
+	tt.Body().Equal(userSid + ":book")
+
+	// The same user keeps the same session ID.
+	e.GET("/get").WithCookie(cookieName, userSid).WithHeader("X-User", "kataras").Expect().
+		Status(httptest.StatusOK).Body().Equal(userSid + ":book")
+
+	// The old session ID is invalidated.
+	e.GET("/get").WithCookie(cookieName, anonymousSid).Expect().
+		Status(httptest.StatusOK).Body().NotContains("book")
+
+	// The session ID changes on logout too.
+	tt = e.GET("/get").WithCookie(cookieName, userSid).Expect().Status(httptest.StatusOK)
+	tt.Cookie(cookieName).Value().NotEqual(userSid)
+	tt.Body().Contains(":book")
+}
+
+func TestSessionsCookieStoreConcurrentRequests(t *testing.T) {
+	cookieName := "mycustomsessionid"
+	codec, err := context.NewCookieCodec(context.CookieKey{Hash: []byte("hash-key")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sess := sessions.New(sessions.Config{Cookie: cookieName})
+	sess.UseDatabase(sessions.NewCookieStore(codec))
+
+	started, proceed := make(chan struct{}), make(chan struct{})
+	app := iris.New()
+	app.Use(sess.Handler())
+	app.Get("/set", func(ctx iris.Context) {
+		sessions.Get(ctx).Set("value", ctx.URLParam("value"))
+	})
+	app.Get("/wait/set", func(ctx iris.Context) {
+		close(started)
+		<-proceed
+		sessions.Get(ctx).Set("value", ctx.URLParam("value"))
+	})
+	app.Get("/get", func(ctx iris.Context) {
+		ctx.WriteString(sessions.Get(ctx).GetString("value"))
+	})
+
+	e := httptest.New(t, app, httptest.URL("http://example.com"))
+	cookies := make(map[string]string)
+	for _, c := range e.GET("/set").WithQuery("value", "first").Expect().Status(httptest.StatusOK).Raw().Cookies() {
+		cookies[c.Name] = c.Value
+	}
+
+	done := make(chan *http.Response)
+	go func() {
+		done <- e.GET("/wait/set").WithQuery("value", "second").WithCookies(cookies).Expect().Raw()
+	}()
+
+	// A request of the same session is bound and released while the first one is served.
+	<-started
+	e.GET("/get").WithCookies(cookies).Expect().Status(httptest.StatusOK).Body().Equal("first")
+	close(proceed)
+
+	r := <-done
+	for _, c := range r.Cookies() {
+		cookies[c.Name] = c.Value
+	}
+	if _, ok := cookies[cookieName+"_data"]; !ok || len(r.Cookies()) == 0 {
+		t.Fatalf("expected the changes to be written to the request which made them but got: %v", r.Cookies())
+	}
+
+	e.GET("/get").WithCookies(cookies).Expect().Status(httptest.StatusOK).Body().Equal("second")
+}
+
+func TestSessionsLock(t *testing.T) {
+	cookieName := "mycustomsessionid"
+	sess := sessions.New(sessions.Config{
+		Cookie:      cookieName,
+		Lock:        true,
+		LockTimeout: 300 * time.Millisecond,
+	})
+
+	app := iris.New()
+	app.Use(sess.Handler())
+	app.Get("/increment", func(ctx iris.Context) {
+		s := sessions.Get(ctx)
+		n := s.GetIntDefault("n", 0)
+		time.Sleep(10 * time.Millisecond) // read-modify-write.
+		s.Set("n", n+1)
+	})
+	app.Get("/get", func(ctx iris.Context) {
+		ctx.Writef("%d", sessions.Get(ctx).GetIntDefault("n", 0))
+	})
+	app.Get("/slow", func(ctx iris.Context) {
+		time.Sleep(time.Second)
+	})
+
+	e := httptest.New(t, app, httptest.URL("http://example.com"))
+	sid := e.GET("/get").Expect().Status(httptest.StatusOK).Cookie(cookieName).Value().Raw()
+
+	// Concurrent requests of the same session do not lose updates.
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			e.GET("/increment").WithCookie(cookieName, sid).Expect().Status(httptest.StatusOK)
+		}()
+	}
+	wg.Wait()
+
+	e.GET("/get").WithCookie(cookieName, sid).Expect().Status(httptest.StatusOK).Body().Equal("10")
+
+	// A request which waits more than the lock timeout is stopped.
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		e.GET("/slow").WithCookie(cookieName, sid).Expect().Status(httptest.StatusOK)
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	e.GET("/get").WithCookie(cookieName, sid).Expect().Status(httptest.StatusServiceUnavailable)
+	wg.Wait()
+}
+
+func TestSessionsEvents(t *testing.T) {
+	cookieName := "mycustomsessionid"
+	sess := sessions.New(sessions.Config{Cookie: cookieName})
+
+	var (
+		mu      sync.Mutex
+		events  []string
+		created string
+	)
+	record := func(format string, args ...interface{}) {
+		mu.Lock()
+		events = append(events, fmt.Sprintf(format, args...))
+		mu.Unlock()
+	}
+
+	sess.OnCreate(func(sid string) {
+		created = sid
+		record("create")
+	})
+	sess.OnUpdate(func(sid, key string, value interface{}) {
+		if sid != created {
+			t.Errorf("expected update of session: %q but got: %q", created, sid)
+		}
+		record("update %s=%v", key, value)
+	})
+	sess.OnDestroy(func(sid string) {
+		record("destroy")
+	})
+
+	app := iris.New()
+	app.Use(sess.Handler())
+	app.Get("/set", func(ctx iris.Context) {
+		s := sessions.Get(ctx)
+		s.Set("name", "iris")
+		s.Increment("visits", 1)
+	})
+	app.Get("/delete", func(ctx iris.Context) {
+		s := sessions.Get(ctx)
+		s.Delete("name")
+		s.Delete("missing")
+		s.Clear()
+	})
+	app.Get("/destroy", func(ctx iris.Context) {
+		sessions.Get(ctx).Man.Destroy(ctx)
+	})
+
+	e := httptest.New(t, app, httptest.URL("http://example.com"))
+	sid := e.GET("/set").Expect().Status(httptest.StatusOK).Cookie(cookieName).Value().Raw()
+	e.GET("/delete").WithCookie(cookieName, sid).Expect().Status(httptest.StatusOK)
+	e.GET("/destroy").WithCookie(cookieName, sid).Expect().Status(httptest.StatusOK)
+
+	expected := []string{
+		"create",
+		"update name=iris",
+		"update visits=1",
+		"update name=<nil>",
+		"update =<nil>",
+		"destroy",
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if !reflect.DeepEqual(events, expected) {
+		t.Fatalf("expected events:\n%q\nbut got:\n%q", expected, events)
+	}
+}
+
+func TestSessionsCookieStore(t *testing.T) {
+	cookieName := "mycustomsessionid"
+	codec, err := context.NewCookieCodec(context.CookieKey{Hash: []byte("hash-key"), Block: []byte("0123456789abcdef")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	app := iris.New()
+	// Two session managers share only the codec's keys,
+	// like two instances of a stateless deployment.
+	for _, instance := range []string{"/1", "/2"} {
+		sess := sessions.New(sessions.Config{Cookie: cookieName})
+		sess.UseDatabase(sessions.NewCookieStore(codec))
+
+		p := app.Party(instance, sess.Handler())
+		p.Get("/set", func(ctx iris.Context) {
+			sessions.Get(ctx).Set("value", ctx.URLParam("value"))
+		})
+		p.Get("/get", func(ctx iris.Context) {
+			ctx.WriteString(sessions.Get(ctx).GetString("value"))
+		})
+		p.Get("/destroy", func(ctx iris.Context) {
+			sessions.Get(ctx).Man.Destroy(ctx)
+		})
+	}
+
+	e := httptest.New(t, app, httptest.URL("http://example.com"))
+
+	large := strings.Repeat("iris", sessions.DefaultCookieChunkSize)
+	r := e.GET("/1/set").WithQuery("value", large).Expect().Status(httptest.StatusOK).Raw()
+	cookies := make(map[string]string)
+	chunks := 0
+	for _, c := range r.Cookies() {
+		cookies[c.Name] = c.Value
+		if strings.HasPrefix(c.Name, cookieName+"_data") {
+			chunks++
+		}
+	}
+	for _, name := range []string{cookieName, cookieName + "_data", cookieName + "_data_1", cookieName + "_data_2"} {
+		if _, ok := cookies[name]; !ok {
+			t.Fatalf("expected cookie: %s but got: %v", name, cookies)
+		}
+	}
+
+	// The session values are read from the cookies by any instance.
+	e.GET("/2/get").Expect().Status(httptest.StatusOK).Body().Equal(large)
+
+	// Tampered data are ignored.
+	cookies[cookieName+"_data_1"] = cookies[cookieName+"_data_2"]
+	httptest.New(t, app, httptest.URL("http://example.com")).GET("/1/get").WithCookies(cookies).
+		Expect().Status(httptest.StatusOK).Body().Empty()
+
+	// A smaller value removes the unused data cookies.
+	r = e.GET("/2/set").WithQuery("value", "small").Expect().Status(httptest.StatusOK).Raw()
+	removed := 0
+	for _, c := range r.Cookies() {
+		if c.MaxAge < 0 {
+			removed++
+		}
+	}
+	if expected := chunks - 1; removed != expected {
+		t.Fatalf("expected %d removed data cookies but got %d", expected, removed)
+	}
+	e.GET("/1/get").Expect().Status(httptest.StatusOK).Body().Equal("small")
+
+	e.GET("/1/destroy").Expect().Status(httptest.StatusOK)
+	e.GET("/2/get").Expect().Status(httptest.StatusOK).Body().Empty()
+}
+
+func TestSessionsMockClock(t *testing.T) {
+	// A clock in the past and a clock in the future of the real time.
+	for _, start := range []time.Time{
+		time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC),
+	} {
+		clock := iris.NewMockClock(start)
+		sess := sessions.New(sessions.Config{Cookie: "mycustomsessionid", Expires: time.Hour})
+
+		app := iris.New()
+		app.SetClock(clock)
+		app.Use(sess.Handler())
+		app.Get("/set", func(ctx iris.Context) {
+			sessions.Get(ctx).Set("value", "set")
+		})
+		app.Get("/get", func(ctx iris.Context) {
+			ctx.WriteString(sessions.Get(ctx).GetString("value"))
+		})
+
+		e := httptest.New(t, app, httptest.URL("http://example.com"))
+		e.GET("/set").Expect().Status(httptest.StatusOK)
+
+		clock.Advance(30 * time.Minute)
+		e.GET("/get").Expect().Status(httptest.StatusOK).Body().Equal("set")
+
+		clock.Advance(time.Hour)
+		e.GET("/get").Expect().Status(httptest.StatusOK).Body().Empty()
+	}
+}
+
+func TestSessionsRememberMe(t *testing.T) {
+	clock := iris.NewMockClock(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC))
+	sess := sessions.New(sessions.Config{Cookie: "mycustomsessionid"})
+
+	var stolen []string
+	remember := sess.RememberMe(sessions.RememberConfig{
+		OnTheft: func(ctx iris.Context, userID string) {
+			stolen = append(stolen, userID)
+		},
+	})
+
+	app := iris.New()
+	app.SetClock(clock)
+	app.Use(sess.Handler(), remember.Handler)
+	app.Get("/login", func(ctx iris.Context) {
+		if err := remember.Remember(ctx, ctx.URLParam("user")); err != nil {
+			ctx.StopWithError(iris.StatusInternalServerError, err)
+		}
+	})
+	app.Get("/logout", func(ctx iris.Context) {
+		remember.Forget(ctx)
+	})
+	app.Get("/me", func(ctx iris.Context) {
+		ctx.WriteString(remember.UserID(ctx))
+	})
+
+	// each request of a new client starts a new session.
+	me := func(token string) (userID, newToken string) {
+		e := httptest.New(t, app, httptest.URL("http://example.com"))
+		r := e.GET("/me").WithCookie(sessions.DefaultRememberCookie, token).Expect().Status(httptest.StatusOK)
+		for _, c := range r.Raw().Cookies() {
+			if c.Name == sessions.DefaultRememberCookie {
+				newToken = c.Value
+			}
+		}
+		return r.Body().Raw(), newToken
+	}
+	expectUser := func(token, expected string) {
+		t.Helper()
+		if userID, _ := me(token); userID != expected {
+			t.Fatalf("expected user: %q but got: %q", expected, userID)
+		}
+	}
+
+	e := httptest.New(t, app, httptest.URL("http://example.com"))
+	token1 := e.GET("/login").WithQuery("user", "kataras").Expect().Status(httptest.StatusOK).
+		Cookie(sessions.DefaultRememberCookie).Value().Raw()
+	e.GET("/me").Expect().Status(httptest.StatusOK).Body().Equal("kataras")
+
+	// The token is rotated on use.
+	userID, token2 := me(token1)
+	if userID != "kataras" || token2 == "" || token2 == token1 {
+		t.Fatalf("expected a rotated token of user: kataras but got: %q of user: %q", token2, userID)
+	}
+
+	// The previous token is accepted by concurrent requests.
+	expectUser(token1, "kataras")
+
+	// A reused token invalidates the series.
+	clock.Advance(sessions.DefaultRememberRotationGrace)
+	expectUser(token1, "")
+	if expected := []string{"kataras"}; !reflect.DeepEqual(stolen, expected) {
+		t.Fatalf("expected theft of: %v but got: %v", expected, stolen)
+	}
+	expectUser(token2, "")
+
+	// Logout forgets the series.
+	token3 := e.GET("/login").WithQuery("user", "kataras").Expect().Status(httptest.StatusOK).
+		Cookie(sessions.DefaultRememberCookie).Value().Raw()
+	e.GET("/logout").Expect().Status(httptest.StatusOK)
+	e.GET("/me").Expect().Status(httptest.StatusOK).Body().Empty()
+	expectUser(token3, "")
+
+	// The series expire.
+	token4 := e.GET("/login").WithQuery("user", "kataras").Expect().Status(httptest.StatusOK).
+		Cookie(sessions.DefaultRememberCookie).Value().Raw()
+	clock.Advance(sessions.DefaultRememberExpires)
+	expectUser(token4, "")
+}
+
+// slowRememberStore delays the reads, so concurrent requests overlap.
+type slowRememberStore struct {
+	mu     sync.Mutex
+	tokens map[string]sessions.RememberToken
+}
+
+func (s *slowRememberStore) Get(series string) (sessions.RememberToken, error) {
+	time.Sleep(20 * time.Millisecond)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	token, ok := s.tokens[series]
+	if !ok {
+		return token, sessions.ErrNotFound
+	}
+	return token, nil
+}
+
+func (s *slowRememberStore) Set(token sessions.RememberToken) error {
+	s.mu.Lock()
+	s.tokens[token.Series] = token
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *slowRememberStore) Rotate(token sessions.RememberToken, oldTokenHash string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.tokens[token.Series].TokenHash != oldTokenHash {
+		return sessions.ErrRememberRotated
+	}
+	s.tokens[token.Series] = token
+	return nil
+}
+
+func (s *slowRememberStore) Delete(series string) error {
+	s.mu.Lock()
+	delete(s.tokens, series)
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *slowRememberStore) DeleteUser(userID string) error {
+	s.mu.Lock()
+	for series, token := range s.tokens {
+		if token.UserID == userID {
+			delete(s.tokens, series)
+		}
+	}
+	s.mu.Unlock()
+	return nil
+}
+
+func TestSessionsRememberMeConcurrentRotation(t *testing.T) {
+	clock := iris.NewMockClock(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC))
+	sess := sessions.New(sessions.Config{Cookie: "mycustomsessionid"})
+
+	var stolen []string
+	remember := sess.RememberMe(sessions.RememberConfig{
+		Store: &slowRememberStore{tokens: make(map[string]sessions.RememberToken)},
+		OnTheft: func(ctx iris.Context, userID string) {
+			stolen = append(stolen, userID)
+		},
+	})
+
+	app := iris.New()
+	app.SetClock(clock)
+	app.Use(sess.Handler(), remember.Handler)
+	app.Get("/login", func(ctx iris.Context) {
+		remember.Remember(ctx, ctx.URLParam("user"))
+	})
+	app.Get("/me", func(ctx iris.Context) {
+		ctx.WriteString(remember.UserID(ctx))
+	})
+
+	token := httptest.New(t, app, httptest.URL("http://example.com")).GET("/login").WithQuery("user", "kataras").
+		Expect().Status(httptest.StatusOK).Cookie(sessions.DefaultRememberCookie).Value().Raw()
+
+	// Concurrent requests of the same token, each one of a new session.
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		tokens []string
+	)
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			e := httptest.New(t, app, httptest.URL("http://example.com"))
+			r := e.GET("/me").WithCookie(sessions.DefaultRememberCookie, token).Expect().Status(httptest.StatusOK)
+			r.Body().Equal("kataras")
+			for _, c := range r.Raw().Cookies() {
+				if c.Name == sessions.DefaultRememberCookie {
+					mu.Lock()
+					tokens = append(tokens, c.Value)
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// The token is rotated once, the rest of the requests log in without a rotation.
+	if len(tokens) != 1 {
+		t.Fatalf("expected a single rotation but got: %d", len(tokens))
+	}
+
+	clock.Advance(sessions.DefaultRememberRotationGrace)
+	httptest.New(t, app, httptest.URL("http://example.com")).GET("/me").
+		WithCookie(sessions.DefaultRememberCookie, tokens[0]).Expect().Status(httptest.StatusOK).Body().Equal("kataras")
+	if len(stolen) > 0 {
+		t.Fatalf("expected no theft but got: %v", stolen)
+	}
+}
+
+func TestSessionsWorkspace(t *testing.T) {
+	defer func(dir string) { context.WorkspaceDir = dir }(context.WorkspaceDir)
+	context.WorkspaceDir = t.TempDir()
+
+	sess := sessions.New(sessions.Config{Cookie: "mycustomsessionid"})
+
+	app := iris.New()
+	app.Use(sess.Handler())
+	app.Get("/write", func(ctx iris.Context) {
+		ws, err := sessions.Get(ctx).Workspace()
+		if err != nil {
+			ctx.StopWithError(iris.StatusInternalServerError, err)
+			return
+		}
+
+		if err = ws.WriteFile("report.txt", []byte("report")); err != nil {
+			ctx.StopWithError(iris.StatusInternalServerError, err)
+			return
+		}
+
+		ctx.WriteString(ws.Dir())
+	})
+	app.Get("/read", func(ctx iris.Context) {
+		ws, _ := sessions.Get(ctx).Workspace()
+		ctx.ServeFile(ws.Path("report.txt"))
+	})
+	app.Get("/destroy", func(ctx iris.Context) {
+		sess.Destroy(ctx)
+	})
+
+	e := httptest.New(t, app, httptest.URL("http://example.com"))
+	dir := e.GET("/write").Expect().Status(httptest.StatusOK).Body().Raw()
+	e.GET("/read").Expect().Status(httptest.StatusOK).Body().Equal("report")
+
+	e.GET("/destroy").Expect().Status(httptest.StatusOK)
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Fatalf("expected workspace: %s to be removed on destroy", dir)
+	}
+}
@@ -59,6 +59,7 @@ var (
 	// You can also implement your own `sessions.Transcoder` and use it,
 	// i.e: a transcoder which will allow(on Marshal: return its byte representation and nil error)
 	// or dissalow(on Marshal: return non nil error) certain types.
+	// See `NewEncryptedTranscoder` to encrypt the values at rest.
 	//
 	// sessions.DefaultTranscoder = sessions.GobTranscoder{}
 	DefaultTranscoder Transcoder = defaultTranscoder{}
@@ -0,0 +1,43 @@
+package sessions
+
+import (
+	"github.com/kataras/iris/v12/context"
+)
+
+// Workspace returns the session's workspace, a temporary directory
+// which is created on the first call and it's kept across the requests of the session,
+// e.g. to process a multi-step upload or to keep the generated reports of a user.
+// It's removed, with all of its files, when the session is destroyed or expired.
+// Its quota defaults to the `context.WorkspaceQuota`.
+//
+// Note that the workspace lives on the file system of the current process,
+// the sessions of a `CookieStore` remove their workspace at the end of each request.
+// See `Context.Workspace` for a request-bound workspace.
+func (s *Session) Workspace() (*context.Workspace, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.workspace == nil {
+		w, err := context.NewWorkspace()
+		if err != nil {
+			return nil, err
+		}
+
+		s.workspace = w
+	}
+
+	return s.workspace, nil
+}
+
+func (s *Session) removeWorkspace() {
+	s.mu.Lock()
+	w := s.workspace
+	s.workspace = nil
+	s.mu.Unlock()
+
+	if w != nil {
+		if err := w.Remove(); err != nil {
+			s.Man.config.Logger.Debugf("sessions: remove workspace: %s: %v", s.sid, err)
+		}
+	}
+}
@@ -34,7 +34,8 @@ func (s *AceEngine) SetIndent(indent string) *AceEngine {
 // Usage:
 // Ace("./views", ".ace") or
 // Ace(iris.Dir("./views"), ".ace") or
-// Ace(AssetFile(), ".ace") for embedded data.
+// Ace(AssetFile(), ".ace") for embedded data or
+// Ace(embedFS, ".ace") for an embed.FS (or any fs.FS).
 func Ace(fs interface{}, extension string) *AceEngine {
 	s := &AceEngine{HTMLEngine: HTML(fs, extension), indent: ""}
 	s.name = "Ace"
@@ -22,6 +22,7 @@ type AmberEngine struct {
 	rootDir   string
 	extension string
 	reload    bool
+	watcher   *templateWatcher // created by Load when reload is true.
 	//
 	rmu           sync.RWMutex // locks for `ExecuteWiter` when `reload` is true.
 	templateCache map[string]*template.Template
@@ -43,7 +44,8 @@ var amberOnce = new(uint32)
 // Usage:
 // Amber("./views", ".amber") or
 // Amber(iris.Dir("./views"), ".amber") or
-// Amber(AssetFile(), ".amber") for embedded data.
+// Amber(AssetFile(), ".amber") for embedded data or
+// Amber(embedFS, ".amber") for an embed.FS (or any fs.FS).
 func Amber(fs interface{}, extension string) *AmberEngine {
 	if atomic.LoadUint32(amberOnce) > 0 {
 		panic("Amber: cannot be registered twice as its internal implementation share the same template functions across instances.")
@@ -99,12 +101,15 @@ func (s *AmberEngine) Ext() string {
 	return s.extension
 }
 
-// Reload if set to true the templates are reloading on each render,
+// Reload if set to true the template files are watched for changes,
 // use it when you're in development and you're boring of restarting
 // the whole app when you edit a template file.
+// Each render checks the modification time of the template files
+// and re-parses only the changed ones and the templates which depend on them
+// (e.g. through an "extends" or "import" statement), removed files are removed from the cache.
 //
-// Note that if `true` is passed then only one `View -> ExecuteWriter` will be render each time,
-// no concurrent access across clients, use it only on development status.
+// Note that the template files are checked on each `View -> ExecuteWriter`,
+// use it only on development status.
 // It's good to be used side by side with the https://github.com/kataras/rizla reloader for go source files.
 func (s *AmberEngine) Reload(developmentMode bool) *AmberEngine {
 	s.reload = developmentMode
@@ -133,7 +138,7 @@ func (s *AmberEngine) SetPrettyPrint(pretty bool) *AmberEngine {
 // Note that, if you use more than one amber engine, the functions are shared.
 func (s *AmberEngine) AddFunc(funcName string, funcBody interface{}) {
 	s.rmu.Lock()
-	amber.FuncMap[funcName] = funcBody
+	amber.FuncMap[funcName] = bindContextFunc(funcBody, nil) // the request-aware functions are not supported.
 	s.rmu.Unlock()
 }
 
@@ -142,7 +147,7 @@ func (s *AmberEngine) AddFunc(funcName string, funcBody interface{}) {
 //
 // Returns an error if something bad happens, user is responsible to catch it.
 func (s *AmberEngine) Load() error {
-	return walk(s.fs, s.rootDir, func(path string, info os.FileInfo, err error) error {
+	err := walk(s.fs, s.rootDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
@@ -168,6 +173,20 @@ func (s *AmberEngine) Load() error {
 		}
 		return nil
 	})
+	if err == nil && s.reload {
+		s.watcher = newTemplateWatcher(s.fs, s.rootDir, s.extension)
+	}
+
+	return err
+}
+
+// reloadChanged re-parses the changed templates, see `Reload`.
+func (s *AmberEngine) reloadChanged() error {
+	return s.watcher.reload(s.ParseTemplate, func(name string) {
+		s.rmu.Lock()
+		delete(s.templateCache, strings.TrimPrefix(name, "/"))
+		s.rmu.Unlock()
+	})
 }
 
 // ParseTemplate adds a custom template from text.
@@ -243,8 +262,8 @@ func (s *AmberEngine) fromCache(relativeName string) *template.Template {
 // layout here is useless.
 func (s *AmberEngine) ExecuteWriter(w io.Writer, filename string, layout string, bindingData interface{}) error {
 	// re-parse the templates if reload is enabled.
-	if s.reload {
-		if err := s.Load(); err != nil {
+	if s.reload && s.watcher != nil {
+		if err := s.reloadChanged(); err != nil {
 			return err
 		}
 	}
@@ -3,6 +3,7 @@ package view
 import (
 	"html/template"
 	"io"
+	"io/fs"
 
 	"github.com/kataras/blocks"
 )
@@ -43,9 +44,15 @@ func WrapBlocks(v *blocks.Blocks) *BlocksEngine {
 // Usage:
 // Blocks("./views", ".html") or
 // Blocks(iris.Dir("./views"), ".html") or
-// Blocks(AssetFile(), ".html") for embedded data.
-func Blocks(fs interface{}, extension string) *BlocksEngine {
-	return WrapBlocks(blocks.New(fs).Extension(extension))
+// Blocks(AssetFile(), ".html") for embedded data or
+// Blocks(embedFS, ".html") for an embed.FS (or any fs.FS).
+func Blocks(fsOrDir interface{}, extension string) *BlocksEngine {
+	if _, ok := fsOrDir.(fs.FS); ok {
+		// the blocks package accepts only a directory or an http.FileSystem.
+		fsOrDir = getFS(fsOrDir)
+	}
+
+	return WrapBlocks(blocks.New(fsOrDir).Extension(extension))
 }
 
 // Name returns the blocks engine's name.
@@ -82,7 +89,8 @@ func (s *BlocksEngine) Ext() string {
 // - urlpath func(routeName string, args ...string) string
 // - tr func(lang, key string, args ...interface{}) string
 func (s *BlocksEngine) AddFunc(funcName string, funcBody interface{}) {
-	s.Engine.Funcs(template.FuncMap{funcName: funcBody})
+	// the request-aware functions are not supported.
+	s.Engine.Funcs(template.FuncMap{funcName: bindContextFunc(funcBody, nil)})
 }
 
 // AddLayoutFunc adds a template function for templates that are marked as layouts.
@@ -2,11 +2,13 @@ package view
 
 import (
 	"bytes"
+	"fmt"
 	"io"
 	"net/http"
 	"os"
 	stdPath "path"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"sync"
 
@@ -43,6 +45,9 @@ type (
 	// Please see the Parser documentation on how to use the parser.
 	// See `RegisterTag` for more information about writing a tag as well.
 	TagParser = pongo2.TagParser
+	// TemplateLoader type alias for pongo2.TemplateLoader,
+	// see `DjangoEngine.AddLoader`.
+	TemplateLoader = pongo2.TemplateLoader
 )
 
 // AsValue converts any given value to a pongo2.Value
@@ -88,6 +93,41 @@ func (l *tDjangoAssetLoader) Get(path string) (io.Reader, error) {
 	return bytes.NewReader(res), nil
 }
 
+// DjangoFSLoader returns a pongo2 template loader which reads the templates
+// from the "fsOrDir" file system, e.g. an embed.FS, and its "rootDir" directory,
+// see `DjangoEngine.AddLoader`.
+func DjangoFSLoader(fsOrDir interface{}, rootDir string) TemplateLoader {
+	if rootDir == "" {
+		rootDir = "/"
+	}
+
+	return &tDjangoAssetLoader{fs: getFS(fsOrDir), rootDir: filepath.ToSlash(rootDir)}
+}
+
+// DjangoMapLoader is a pongo2 template loader which reads the templates
+// from memory, the key is the template's name and the value its contents,
+// see `DjangoEngine.AddLoader`.
+type DjangoMapLoader map[string]string
+
+// Abs calculates the path to a given template.
+func (l DjangoMapLoader) Abs(base, name string) string {
+	if stdPath.IsAbs(name) {
+		return name
+	}
+
+	return stdPath.Join("/", name)
+}
+
+// Get returns an io.Reader where the template's content can be read from.
+func (l DjangoMapLoader) Get(path string) (io.Reader, error) {
+	contents, ok := l[strings.TrimPrefix(path, "/")]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", path, os.ErrNotExist)
+	}
+
+	return strings.NewReader(contents), nil
+}
+
 // DjangoEngine contains the django view engine structure.
 type DjangoEngine struct {
 	fs http.FileSystem
@@ -95,12 +135,21 @@ type DjangoEngine struct {
 	rootDir   string
 	extension string
 	reload    bool
+	watcher   *templateWatcher // created by Load when reload is true.
 	//
 	rmu sync.RWMutex // locks for filters, globals and `ExecuteWiter` when `reload` is true.
 	// filters for pongo2, map[name of the filter] the filter function . The filters are auto register
 	filters map[string]FilterFunction
 	// globals share context fields between templates.
-	globals       map[string]interface{}
+	globals map[string]interface{}
+	// contextFuncs are the request-aware functions, bound to the request's context on render.
+	contextFuncs map[string]interface{}
+	// contextGlobals return the per-request globals, see `ContextGlobals`.
+	contextGlobals []func(*context.Context) map[string]interface{}
+	// tags are the custom tags, registered on Load, see `AddTag`.
+	tags map[string]TagParser
+	// loaders are the custom template loaders, after the engine's file system one.
+	loaders       []TemplateLoader
 	Set           *pongo2.TemplateSet
 	templateCache map[string]*pongo2.Template
 }
@@ -116,7 +165,8 @@ var (
 // Usage:
 // Django("./views", ".html") or
 // Django(iris.Dir("./views"), ".html") or
-// Django(AssetFile(), ".html") for embedded data.
+// Django(AssetFile(), ".html") for embedded data or
+// Django(embedFS, ".html") for an embed.FS (or any fs.FS).
 func Django(fs interface{}, extension string) *DjangoEngine {
 	s := &DjangoEngine{
 		fs:            getFS(fs),
@@ -148,12 +198,15 @@ func (s *DjangoEngine) Ext() string {
 	return s.extension
 }
 
-// Reload if set to true the templates are reloading on each render,
+// Reload if set to true the template files are watched for changes,
 // use it when you're in development and you're boring of restarting
 // the whole app when you edit a template file.
+// Each render checks the modification time of the template files
+// and re-parses only the changed ones and the templates which depend on them
+// (e.g. through an "extends" or "include" tag), removed files are removed from the cache.
 //
-// Note that if `true` is passed then only one `View -> ExecuteWriter` will be render each time,
-// no concurrent access across clients, use it only on development status.
+// Note that the template files are checked on each `View -> ExecuteWriter`,
+// use it only on development status.
 // It's good to be used side by side with the https://github.com/kataras/rizla reloader for go source files.
 func (s *DjangoEngine) Reload(developmentMode bool) *DjangoEngine {
 	s.reload = developmentMode
@@ -165,10 +218,111 @@ func (s *DjangoEngine) Reload(developmentMode bool) *DjangoEngine {
 // - url func(routeName string, args ...string) string
 // - urlpath func(routeName string, args ...string) string
 // - render func(fullPartialName string) (template.HTML, error).
+//
+// A function which accepts at least one argument and returns a value
+// (and optionally an error) is registered as a filter too, unless a builtin filter
+// with the same name exists. The filter's input is the first argument
+// and its parameter, if any, the second one, e.g.
+// {{ urlpath("user", 42) }} and {{ "user"|urlpath:42 }}.
+// Note that the pongo2 filters are shared across all the django engines.
+//
+// A function which accepts an `iris.Context` as its first input argument
+// receives the context of the request which the template is rendered for
+// and it's not registered as a filter, e.g. {{ csrfField()|safe }}.
 func (s *DjangoEngine) AddFunc(funcName string, funcBody interface{}) {
+	if isContextFunc(funcBody) {
+		s.rmu.Lock()
+		if s.contextFuncs == nil {
+			s.contextFuncs = make(map[string]interface{})
+		}
+		s.contextFuncs[funcName] = funcBody
+		s.globals[funcName] = bindContextFunc(funcBody, nil)
+		s.rmu.Unlock()
+		return
+	}
+
 	s.rmu.Lock()
 	s.globals[funcName] = funcBody
 	s.rmu.Unlock()
+
+	if filter, ok := funcFilter(funcName, funcBody); ok {
+		if _, isFuncFilter := djangoFuncFilters.Load(funcName); isFuncFilter {
+			pongo2.ReplaceFilter(funcName, filter)
+		} else if !pongo2.FilterExists(funcName) {
+			djangoFuncFilters.Store(funcName, struct{}{})
+			pongo2.RegisterFilter(funcName, filter)
+		}
+	}
+}
+
+// djangoFuncFilters keeps the names of the filters registered through `AddFunc`.
+var djangoFuncFilters sync.Map
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// funcFilter adapts a template function to a pongo2 filter.
+func funcFilter(funcName string, funcBody interface{}) (FilterFunction, bool) {
+	fn := reflect.ValueOf(funcBody)
+	typ := fn.Type()
+	if typ.Kind() != reflect.Func || typ.NumIn() == 0 || typ.NumOut() == 0 || typ.NumOut() > 2 ||
+		(typ.NumOut() == 2 && typ.Out(1) != errorType) {
+		return nil, false
+	}
+
+	filter := func(in *Value, param *Value) (*Value, *Error) {
+		values := []interface{}{in.Interface()}
+		if param != nil && !param.IsNil() && (typ.NumIn() > 1 || typ.IsVariadic()) {
+			values = append(values, param.Interface())
+		}
+
+		if len(values) < typ.NumIn() && !(typ.IsVariadic() && len(values) == typ.NumIn()-1) {
+			return nil, &Error{Sender: "filter:" + funcName, OrigError: fmt.Errorf("expected %d arguments but got %d", typ.NumIn(), len(values))}
+		}
+
+		args := make([]reflect.Value, len(values))
+		for i, value := range values {
+			argType := typ.In(i)
+			if typ.IsVariadic() && i >= typ.NumIn()-1 {
+				argType = typ.In(typ.NumIn() - 1).Elem()
+			}
+
+			arg, err := filterArg(value, argType)
+			if err != nil {
+				return nil, &Error{Sender: "filter:" + funcName, OrigError: err}
+			}
+			args[i] = arg
+		}
+
+		out := fn.Call(args)
+		if len(out) == 2 && !out[1].IsNil() {
+			return nil, &Error{Sender: "filter:" + funcName, OrigError: out[1].Interface().(error)}
+		}
+
+		return AsValue(out[0].Interface()), nil
+	}
+
+	return filter, true
+}
+
+func filterArg(value interface{}, typ reflect.Type) (reflect.Value, error) {
+	if value == nil {
+		return reflect.Zero(typ), nil
+	}
+
+	v := reflect.ValueOf(value)
+	if v.Type().AssignableTo(typ) {
+		return v, nil
+	}
+
+	if typ.Kind() == reflect.String { // e.g. an int of a route parameter, not a rune.
+		return reflect.ValueOf(fmt.Sprint(value)).Convert(typ), nil
+	}
+
+	if v.Type().ConvertibleTo(typ) {
+		return v.Convert(typ), nil
+	}
+
+	return reflect.Value{}, fmt.Errorf("can not use %T as %s", value, typ)
 }
 
 // AddFilter registers a new filter. If there's already a filter with the same
@@ -207,12 +361,94 @@ func (s *DjangoEngine) RegisterTag(tagName string, fn TagParser) error {
 	return pongo2.RegisterTag(tagName, fn)
 }
 
+// AddTag adds a custom tag, registered on `Load`, e.g. {% your_tag_name some "arguments" 123 %}.
+// A tag with the same name, registered by an engine before, is replaced.
+// Note that the pongo2 tags are shared across all the django engines.
+//
+// See `RegisterTag` to register a tag immediately.
+func (s *DjangoEngine) AddTag(tagName string, fn TagParser) *DjangoEngine {
+	s.rmu.Lock()
+	if s.tags == nil {
+		s.tags = make(map[string]TagParser)
+	}
+	s.tags[tagName] = fn
+	s.rmu.Unlock()
+
+	return s
+}
+
+// djangoTags keeps the names of the tags registered through `AddTag`.
+var djangoTags sync.Map
+
+func (s *DjangoEngine) registerTags() error {
+	s.rmu.RLock()
+	defer s.rmu.RUnlock()
+
+	for tagName, fn := range s.tags {
+		if _, isEngineTag := djangoTags.Load(tagName); isEngineTag {
+			if err := pongo2.ReplaceTag(tagName, fn); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := pongo2.RegisterTag(tagName, fn); err != nil {
+			return err // a builtin tag.
+		}
+		djangoTags.Store(tagName, struct{}{})
+	}
+
+	return nil
+}
+
+// AddLoader adds template loaders, e.g. a `DjangoFSLoader` or a `DjangoMapLoader`.
+// The templates are resolved through the engine's file system first
+// and then through the loaders, by order, on extends, include and import tags
+// and on renders of templates which are missing from the engine's file system.
+//
+// It should be called before `Load`.
+func (s *DjangoEngine) AddLoader(loaders ...TemplateLoader) *DjangoEngine {
+	s.rmu.Lock()
+	s.loaders = append(s.loaders, loaders...)
+	s.rmu.Unlock()
+
+	return s
+}
+
+// ContextGlobals adds a function which returns the globals of a request,
+// e.g. the flash messages, the current user and the CSRF token,
+// merged into the data of every template rendered for that request.
+// The view data override the globals with the same name.
+//
+// Usage:
+//  django.ContextGlobals(func(ctx iris.Context) map[string]interface{} {
+//      return map[string]interface{}{
+//          "user":  ctx.User(),
+//          "flash": sessions.Get(ctx).GetFlashes(),
+//      }
+//  })
+func (s *DjangoEngine) ContextGlobals(fn func(ctx *context.Context) map[string]interface{}) *DjangoEngine {
+	s.rmu.Lock()
+	s.contextGlobals = append(s.contextGlobals, fn)
+	s.rmu.Unlock()
+
+	return s
+}
+
 // Load parses the templates to the engine.
-// It is responsible to add the necessary global functions.
+// It is responsible to add the necessary global functions and the custom tags.
 //
 // Returns an error if something bad happens, user is responsible to catch it.
 func (s *DjangoEngine) Load() error {
-	return walk(s.fs, s.rootDir, func(path string, info os.FileInfo, err error) error {
+	if err := s.registerTags(); err != nil {
+		return err
+	}
+
+	s.rmu.Lock()
+	s.initSet() // the custom loaders may hold all the templates.
+	s.rmu.Unlock()
+
+	err := walk(s.fs, s.rootDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
@@ -234,6 +470,27 @@ func (s *DjangoEngine) Load() error {
 
 		return s.ParseTemplate(path, contents)
 	})
+	if err == nil && s.reload {
+		s.watcher = newTemplateWatcher(s.fs, s.rootDir, s.extension)
+	}
+
+	return err
+}
+
+// reloadChanged re-parses the changed templates, see `Reload`.
+func (s *DjangoEngine) reloadChanged() error {
+	return s.watcher.reload(func(name string, contents []byte) error {
+		s.rmu.Lock()
+		if s.Set != nil {
+			s.Set.CleanCache() // the extended and included templates are cached by the pongo2's set.
+		}
+		s.rmu.Unlock()
+		return s.ParseTemplate(name, contents)
+	}, func(name string) {
+		s.rmu.Lock()
+		delete(s.templateCache, strings.TrimPrefix(name, "/"))
+		s.rmu.Unlock()
+	})
 }
 
 // ParseTemplate adds a custom template from text.
@@ -255,7 +512,8 @@ func (s *DjangoEngine) ParseTemplate(name string, contents []byte) error {
 
 func (s *DjangoEngine) initSet() { // protected by the caller.
 	if s.Set == nil {
-		s.Set = pongo2.NewSet("", &tDjangoAssetLoader{fs: s.fs, rootDir: s.rootDir})
+		loaders := append([]TemplateLoader{&tDjangoAssetLoader{fs: s.fs, rootDir: s.rootDir}}, s.loaders...)
+		s.Set = pongo2.NewSet("", loaders...)
 		s.Set.Globals = getPongoContext(s.globals)
 	}
 }
@@ -281,6 +539,36 @@ func getPongoContext(templateData interface{}) pongo2.Context {
 	}
 }
 
+// withContext returns a copy of the "data" with the request-aware functions
+// bound to the "ctx" (see `AddFunc`) and the request's globals (see `ContextGlobals`).
+func (s *DjangoEngine) withContext(data pongo2.Context, ctx *context.Context) pongo2.Context {
+	if ctx == nil {
+		return data
+	}
+
+	s.rmu.RLock()
+	defer s.rmu.RUnlock()
+
+	if len(s.contextFuncs) == 0 && len(s.contextGlobals) == 0 {
+		return data
+	}
+
+	newData := make(pongo2.Context, len(data)+len(s.contextFuncs))
+	for funcName, funcBody := range s.contextFuncs {
+		newData[funcName] = bindContextFunc(funcBody, ctx)
+	}
+	for _, globals := range s.contextGlobals {
+		for key, value := range globals(ctx) {
+			newData[key] = value
+		}
+	}
+	for key, value := range data {
+		newData[key] = value
+	}
+
+	return newData
+}
+
 func (s *DjangoEngine) fromCache(relativeName string) *pongo2.Template {
 	if s.reload {
 		s.rmu.RLock()
@@ -290,21 +578,37 @@ func (s *DjangoEngine) fromCache(relativeName string) *pongo2.Template {
 	if tmpl, ok := s.templateCache[relativeName]; ok {
 		return tmpl
 	}
+
+	if len(s.loaders) > 0 && s.Set != nil {
+		// a template of the custom loaders, cached by the pongo2's set.
+		if tmpl, err := s.Set.FromCache(relativeName); err == nil {
+			return tmpl
+		}
+	}
+
 	return nil
 }
 
 // ExecuteWriter executes a templates and write its results to the w writer
 // layout here is useless.
+// The output is buffered, so a render error does not write a partial result,
+// unless the writer is a `context.ViewStreamWriter`.
 func (s *DjangoEngine) ExecuteWriter(w io.Writer, filename string, _ string, bindingData interface{}) error {
 	// re-parse the templates if reload is enabled.
-	if s.reload {
-		if err := s.Load(); err != nil {
+	if s.reload && s.watcher != nil {
+		if err := s.reloadChanged(); err != nil {
 			return err
 		}
 	}
 
 	if tmpl := s.fromCache(filename); tmpl != nil {
-		return tmpl.ExecuteWriter(getPongoContext(bindingData), w)
+		data := s.withContext(getPongoContext(bindingData), viewContext(w))
+		if _, ok := w.(*context.ViewStreamWriter); ok {
+			// write directly to the response, see `Context.ViewStream`.
+			return tmpl.ExecuteWriterUnbuffered(data, w)
+		}
+
+		return tmpl.ExecuteWriter(data, w)
 	}
 
 	return ErrNotExist{filename, false, bindingData}
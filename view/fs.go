@@ -2,6 +2,7 @@ package view
 
 import (
 	"fmt"
+	"io/fs"
 	"io/ioutil"
 	"net/http"
 	"path"
@@ -23,6 +24,7 @@ func walk(fs http.FileSystem, root string, walkFn filepath.WalkFunc) error {
 			return fmt.Errorf("%s: %w", fullpath, err)
 		}
 		stat, err := f.Stat()
+		f.Close()
 		err = walkFn(fullpath, stat, err)
 		if err != nil {
 			if err != filepath.SkipDir {
@@ -85,7 +87,10 @@ func asset(fs http.FileSystem, name string) ([]byte, error) {
 	return contents, err
 }
 
-func getFS(fsOrDir interface{}) (fs http.FileSystem) {
+// getFS returns the http.FileSystem of a
+// directory (string), an http.FileSystem (e.g. go-bindata's AssetFile())
+// or an fs.FS (e.g. embed.FS).
+func getFS(fsOrDir interface{}) (fileSystem http.FileSystem) {
 	if fsOrDir == nil {
 		return noOpFS{}
 	}
@@ -93,14 +98,16 @@ func getFS(fsOrDir interface{}) (fs http.FileSystem) {
 	switch v := fsOrDir.(type) {
 	case string:
 		if v == "" {
-			fs = noOpFS{}
+			fileSystem = noOpFS{}
 		} else {
-			fs = httpDirWrapper{http.Dir(v)}
+			fileSystem = httpDirWrapper{http.Dir(v)}
 		}
 	case http.FileSystem:
-		fs = v
+		fileSystem = v
+	case fs.FS:
+		fileSystem = ioFSWrapper{http.FS(v)}
 	default:
-		panic(fmt.Errorf(`unexpected "fsOrDir" argument type of %T (string or http.FileSystem)`, v))
+		panic(fmt.Errorf(`unexpected "fsOrDir" argument type of %T (string or http.FileSystem or fs.FS)`, v))
 	}
 
 	return
@@ -110,11 +117,6 @@ type noOpFS struct{}
 
 func (fs noOpFS) Open(name string) (http.File, error) { return nil, nil }
 
-func isNoOpFS(fs http.FileSystem) bool {
-	_, ok := fs.(noOpFS)
-	return ok
-}
-
 // fixes: "invalid character in file path"
 // on amber engine (it uses the virtual fs directly
 // and it uses filepath instead of the path package...).
@@ -125,3 +127,14 @@ type httpDirWrapper struct {
 func (fs httpDirWrapper) Open(name string) (http.File, error) {
 	return fs.Dir.Open(filepath.ToSlash(name))
 }
+
+// ioFSWrapper cleans the names before they are passed to an fs.FS,
+// which accepts only unrooted, slash-separated, paths.
+// The leading slash is removed by the http.FS itself.
+type ioFSWrapper struct {
+	http.FileSystem
+}
+
+func (fs ioFSWrapper) Open(name string) (http.File, error) {
+	return fs.FileSystem.Open(path.Clean("/" + filepath.ToSlash(name)))
+}
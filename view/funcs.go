@@ -0,0 +1,124 @@
+package view
+
+import (
+	"html/template"
+	"io"
+	"reflect"
+
+	"github.com/kataras/iris/v12/context"
+)
+
+var contextType = reflect.TypeOf((*context.Context)(nil))
+
+var (
+	// CSRFTokenContextKey is the request value key of the CSRF token,
+	// a CSRF middleware should set the request's token there, see `CSRFField`.
+	CSRFTokenContextKey = "iris.csrf.token"
+	// CSRFFieldName is the name of the hidden form field, see `CSRFField`.
+	CSRFFieldName = "csrf.token"
+)
+
+// CSRFField returns the hidden form field of the request's CSRF token,
+// the token is read from the `CSRFTokenContextKey` request value.
+// It's registered as the "csrfField" template function on `iris.Application.Build`, e.g.
+//  <form method="POST">{{ csrfField }} [...]</form>
+func CSRFField(ctx *context.Context) template.HTML {
+	if ctx == nil {
+		return ""
+	}
+
+	token := ctx.Values().GetString(CSRFTokenContextKey)
+	if token == "" {
+		return ""
+	}
+
+	return template.HTML(`<input type="hidden" name="` + template.HTMLEscapeString(CSRFFieldName) +
+		`" value="` + template.HTMLEscapeString(token) + `">`)
+}
+
+// Flush sends the output rendered so far to the client, when the template
+// is streamed through the `Context.ViewStream`, otherwise it does nothing.
+// It's registered as the "flush" template function on `iris.Application.Build`, e.g.
+//  <header>...</header>{{ flush }}<main>...</main>
+func Flush(ctx *context.Context) template.HTML {
+	if ctx != nil {
+		if w := ctx.GetViewStreamWriter(); w != nil {
+			w.Flush()
+		}
+	}
+
+	return ""
+}
+
+// SanitizeHTML returns the "html" sanitized by the given "policy" name, see `context.HTMLSanitizers`.
+// It's registered as the "sanitize" template function on `iris.Application.Build`, e.g.
+//  {{ sanitize .Comment }} or {{ sanitize .Comment "comments" }}
+func SanitizeHTML(html string, policy ...string) template.HTML {
+	return template.HTML(context.SanitizeHTML(html, policy...))
+}
+
+// isContextFunc reports whether "funcBody" is a request-aware template function,
+// a function which accepts the current `*context.Context` as its first input argument,
+// e.g. func(ctx iris.Context, key string, args ...interface{}) string.
+func isContextFunc(funcBody interface{}) bool {
+	typ := reflect.TypeOf(funcBody)
+	return typ != nil && typ.Kind() == reflect.Func && typ.NumIn() > 0 && typ.In(0) == contextType
+}
+
+// bindContextFunc returns a function which calls the request-aware "funcBody"
+// with the "ctx" as its first input argument,
+// the "ctx" is nil when the template is not rendered for a request (or by an engine which does not support them).
+// It returns the "funcBody" as it's if it's not a request-aware function.
+func bindContextFunc(funcBody interface{}, ctx *context.Context) interface{} {
+	if !isContextFunc(funcBody) {
+		return funcBody
+	}
+
+	fn := reflect.ValueOf(funcBody)
+	typ := fn.Type()
+
+	in := make([]reflect.Type, typ.NumIn()-1)
+	for i := range in {
+		in[i] = typ.In(i + 1)
+	}
+
+	out := make([]reflect.Type, typ.NumOut())
+	for i := range out {
+		out[i] = typ.Out(i)
+	}
+
+	ctxValue := reflect.ValueOf(ctx)
+	bound := reflect.MakeFunc(reflect.FuncOf(in, out, typ.IsVariadic()), func(args []reflect.Value) []reflect.Value {
+		args = append([]reflect.Value{ctxValue}, args...)
+		if typ.IsVariadic() {
+			return fn.CallSlice(args)
+		}
+
+		return fn.Call(args)
+	})
+
+	return bound.Interface()
+}
+
+// bindContextFuncs returns the request-aware "funcs" bound to the "ctx", see `bindContextFunc`.
+func bindContextFuncs(funcs map[string]interface{}, ctx *context.Context) map[string]interface{} {
+	bound := make(map[string]interface{}, len(funcs))
+	for funcName, funcBody := range funcs {
+		bound[funcName] = bindContextFunc(funcBody, ctx)
+	}
+
+	return bound
+}
+
+// viewContext returns the request's context of the "w" writer
+// which a template is rendered to, if any.
+func viewContext(w io.Writer) *context.Context {
+	switch v := w.(type) {
+	case *context.Context:
+		return v
+	case *context.ViewStreamWriter:
+		return v.Context()
+	default:
+		return nil
+	}
+}
@@ -22,7 +22,8 @@ type HandlebarsEngine struct {
 	// Not used anymore.
 	// assetFn   func(name string) ([]byte, error) // for embedded, in combination with directory & extension
 	// namesFn   func() []string                   // for embedded, in combination with directory & extension
-	reload bool // if true, each time the ExecuteWriter is called the templates will be reloaded.
+	reload  bool             // if true, each time the ExecuteWriter is called the changed templates will be reloaded.
+	watcher *templateWatcher // created by Load when reload is true.
 	// parser configuration
 	layout        string
 	rmu           sync.RWMutex
@@ -41,7 +42,8 @@ var (
 // Usage:
 // Handlebars("./views", ".html") or
 // Handlebars(iris.Dir("./views"), ".html") or
-// Handlebars(AssetFile(), ".html") for embedded data.
+// Handlebars(AssetFile(), ".html") for embedded data or
+// Handlebars(embedFS, ".html") for an embed.FS (or any fs.FS).
 func Handlebars(fs interface{}, extension string) *HandlebarsEngine {
 	s := &HandlebarsEngine{
 		fs:            getFS(fs),
@@ -81,12 +83,15 @@ func (s *HandlebarsEngine) Ext() string {
 	return s.extension
 }
 
-// Reload if set to true the templates are reloading on each render,
+// Reload if set to true the template files are watched for changes,
 // use it when you're in development and you're boring of restarting
 // the whole app when you edit a template file.
+// Each render checks the modification time of the template files
+// and re-parses only the changed ones and the templates which depend on them
+// (e.g. through a partial), removed files are removed from the cache.
 //
-// Note that if `true` is passed then only one `View -> ExecuteWriter` will be render each time,
-// no concurrent access across clients, use it only on development status.
+// Note that the template files are checked on each `View -> ExecuteWriter`,
+// use it only on development status.
 // It's good to be used side by side with the https://github.com/kataras/rizla reloader for go source files.
 func (s *HandlebarsEngine) Reload(developmentMode bool) *HandlebarsEngine {
 	s.reload = developmentMode
@@ -109,7 +114,7 @@ func (s *HandlebarsEngine) Layout(layoutFile string) *HandlebarsEngine {
 // - render func(fullPartialName string) (raymond.HTML, error).
 func (s *HandlebarsEngine) AddFunc(funcName string, funcBody interface{}) {
 	s.rmu.Lock()
-	s.funcs[funcName] = funcBody
+	s.funcs[funcName] = bindContextFunc(funcBody, nil) // the request-aware functions are not supported.
 	s.rmu.Unlock()
 }
 
@@ -125,7 +130,7 @@ func (s *HandlebarsEngine) AddGlobalFunc(funcName string, funcBody interface{})
 //
 // Returns an error if something bad happens, user is responsible to catch it.
 func (s *HandlebarsEngine) Load() error {
-	return walk(s.fs, s.rootDir, func(path string, info os.FileInfo, _ error) error {
+	err := walk(s.fs, s.rootDir, func(path string, info os.FileInfo, _ error) error {
 		if info == nil || info.IsDir() {
 			return nil
 		}
@@ -142,6 +147,22 @@ func (s *HandlebarsEngine) Load() error {
 		}
 		return s.ParseTemplate(path, string(contents), nil)
 	})
+	if err == nil && s.reload {
+		s.watcher = newTemplateWatcher(s.fs, s.rootDir, s.extension)
+	}
+
+	return err
+}
+
+// reloadChanged re-parses the changed templates, see `Reload`.
+func (s *HandlebarsEngine) reloadChanged() error {
+	return s.watcher.reload(func(name string, contents []byte) error {
+		return s.ParseTemplate(name, string(contents), nil)
+	}, func(name string) {
+		s.rmu.Lock()
+		delete(s.templateCache, strings.TrimPrefix(name, "/"))
+		s.rmu.Unlock()
+	})
 }
 
 // ParseTemplate adds a custom template from text.
@@ -190,8 +211,8 @@ func (s *HandlebarsEngine) executeTemplateBuf(name string, binding interface{})
 // ExecuteWriter executes a template and writes its result to the w writer.
 func (s *HandlebarsEngine) ExecuteWriter(w io.Writer, filename string, layout string, bindingData interface{}) error {
 	// re-parse the templates if reload is enabled.
-	if s.reload {
-		if err := s.Load(); err != nil {
+	if s.reload && s.watcher != nil {
+		if err := s.reloadChanged(); err != nil {
 			return err
 		}
 	}
@@ -10,6 +10,8 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+
+	"github.com/kataras/iris/v12/context"
 )
 
 // HTMLEngine contains the html view engine structure.
@@ -20,9 +22,11 @@ type HTMLEngine struct {
 	// files configuration
 	rootDir   string
 	extension string
-	// if true, each time the ExecuteWriter is called the templates will be reloaded,
+	// if true, each time the ExecuteWriter is called the templates will be reloaded
+	// if any of the template files was changed,
 	// each ExecuteWriter waits to be finished before writing to a new one.
-	reload bool
+	reload  bool
+	watcher *templateWatcher // created by Load when reload is true.
 	// parser configuration
 	options     []string // (text) template options
 	left        string
@@ -80,7 +84,8 @@ var emptyFuncs = template.FuncMap{
 // Usage:
 // HTML("./views", ".html") or
 // HTML(iris.Dir("./views"), ".html") or
-// HTML(AssetFile(), ".html") for embedded data.
+// HTML(AssetFile(), ".html") for embedded data or
+// HTML(embedFS, ".html") for an embed.FS (or any fs.FS).
 func HTML(fs interface{}, extension string) *HTMLEngine {
 	s := &HTMLEngine{
 		name:        "HTML",
@@ -119,9 +124,12 @@ func (s *HTMLEngine) Ext() string {
 	return s.extension
 }
 
-// Reload if set to true the templates are reloading on each render,
+// Reload if set to true the template files are watched for changes,
 // use it when you're in development and you're boring of restarting
 // the whole app when you edit a template file.
+// Each render checks the modification time of the template files
+// and, on a change, all the templates are reloaded, as the html/template package
+// does not allow to redefine a template which was already executed.
 //
 // Note that if `true` is passed then only one `View -> ExecuteWriter` will be render each time,
 // no concurrent access across clients, use it only on development status.
@@ -200,7 +208,12 @@ func (s *HTMLEngine) AddLayoutFunc(funcName string, funcBody interface{}) *HTMLE
 // - url func(routeName string, args ...string) string
 // - urlpath func(routeName string, args ...string) string
 // - render func(fullPartialName string) (template.HTML, error).
-// - tr func(lang, key string, args ...interface{}) string
+// - tr func(key string, args ...interface{}) string
+//
+// A function which accepts an `iris.Context` as its first input argument
+// receives the context of the request which the template is rendered for, e.g.
+//  AddFunc("user", func(ctx iris.Context) string { return ctx.User().GetUsername() })
+// and it's called as {{ user }}, the context is nil outside of a request.
 func (s *HTMLEngine) AddFunc(funcName string, funcBody interface{}) {
 	s.rmu.Lock()
 	s.funcs[funcName] = funcBody
@@ -237,7 +250,15 @@ func (s *HTMLEngine) Load() error {
 	s.rmu.Lock()
 	defer s.rmu.Unlock()
 
-	return s.load()
+	if err := s.load(); err != nil {
+		return err
+	}
+
+	if s.reload {
+		s.watcher = newTemplateWatcher(s.fs, s.rootDir, s.extension)
+	}
+
+	return nil
 }
 
 func (s *HTMLEngine) load() error {
@@ -311,7 +332,7 @@ func (s *HTMLEngine) parseTemplate(name string, contents []byte, funcs template.
 		text = string(contents)
 	}
 
-	tmpl.Funcs(emptyFuncs).Funcs(s.funcs)
+	tmpl.Funcs(emptyFuncs).Funcs(bindContextFuncs(s.funcs, nil))
 	if len(funcs) > 0 {
 		tmpl.Funcs(funcs) // custom for this template.
 	}
@@ -340,11 +361,17 @@ func (s *HTMLEngine) executeTemplateBuf(name string, binding interface{}) (strin
 	return result, err
 }
 
-func (s *HTMLEngine) layoutFuncsFor(lt *template.Template, name string, binding interface{}) {
+func (s *HTMLEngine) layoutFuncsFor(w io.Writer, lt *template.Template, name string, binding interface{}) {
 	s.runtimeFuncsFor(lt, name, binding)
 
 	funcs := template.FuncMap{
 		"yield": func() (template.HTML, error) {
+			if sw, ok := w.(*context.ViewStreamWriter); ok {
+				// send the layout's head and stream the page, see `Context.ViewStream`.
+				sw.Flush()
+				return "", s.Templates.ExecuteTemplate(sw, name, binding)
+			}
+
 			result, err := s.executeTemplateBuf(name, binding)
 			// Return safe HTML here since we are rendering our own template.
 			return template.HTML(result), err
@@ -403,26 +430,63 @@ func (s *HTMLEngine) runtimeFuncsFor(t *template.Template, name string, binding
 	t.Funcs(funcs)
 }
 
+// contextFuncs returns the request-aware template functions bound to the "ctx", see `AddFunc`.
+func (s *HTMLEngine) contextFuncs(ctx *context.Context) template.FuncMap {
+	if ctx == nil {
+		return nil
+	}
+
+	s.rmu.RLock()
+	defer s.rmu.RUnlock()
+
+	var funcs template.FuncMap
+	for funcName, funcBody := range s.funcs {
+		if !isContextFunc(funcBody) {
+			continue
+		}
+
+		if funcs == nil {
+			funcs = make(template.FuncMap)
+		}
+		funcs[funcName] = bindContextFunc(funcBody, ctx)
+	}
+
+	return funcs
+}
+
 // ExecuteWriter executes a template and writes its result to the w writer.
 func (s *HTMLEngine) ExecuteWriter(w io.Writer, name string, layout string, bindingData interface{}) error {
+	ctxFuncs := s.contextFuncs(viewContext(w))
+
 	// re-parse the templates if reload is enabled.
-	if s.reload {
+	if s.reload && s.watcher != nil {
 		s.rmu.Lock()
 		defer s.rmu.Unlock()
 
-		s.Templates = nil
-		// we lose the templates parsed manually, so store them when it's called
-		// in order for load to take care of them too.
-
-		if err := s.load(); err != nil {
+		affected, removed, err := s.watcher.changes()
+		if err != nil {
 			return err
 		}
+
+		if len(affected) > 0 || len(removed) > 0 {
+			s.Templates = nil
+			// we lose the templates parsed manually, so store them when it's called
+			// in order for load to take care of them too.
+
+			if err = s.load(); err != nil {
+				s.watcher.forget("") // retry on the next render.
+				return err
+			}
+		}
 	}
 
 	t := s.Templates.Lookup(name)
 	if t == nil {
 		return ErrNotExist{name, false, bindingData}
 	}
+	if len(ctxFuncs) > 0 {
+		t.Funcs(ctxFuncs) // the functions are shared by all templates.
+	}
 	s.runtimeFuncsFor(t, name, bindingData)
 
 	if layout = getLayout(layout, s.layout); layout != "" {
@@ -431,7 +495,7 @@ func (s *HTMLEngine) ExecuteWriter(w io.Writer, name string, layout string, bind
 			return ErrNotExist{layout, true, bindingData}
 		}
 
-		s.layoutFuncsFor(lt, name, bindingData)
+		s.layoutFuncsFor(w, lt, name, bindingData)
 		return lt.Execute(w, bindingData)
 	}
 
@@ -5,6 +5,7 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"path"
 	"path/filepath"
 	"reflect"
 	"strings"
@@ -23,19 +24,25 @@ type JetEngine struct {
 	rootDir     string
 	extension   string
 	left, right string
+	layout      string
 
 	loader jet.Loader
 
 	developmentMode bool
+	watcher         *templateWatcher // created by Load when developmentMode is true.
 
 	// The Set is the `*jet.Set`, exported to offer any custom capabilities that jet users may want.
 	// Available after `Load`.
 	Set *jet.Set
 	mu  sync.Mutex
+	// the templates cache of the Set, see `ParseTemplate`.
+	cache *jetCache
 
 	// Note that global vars and functions are set in a single spot on the jet parser.
 	// If AddFunc or AddVar called before `Load` then these will be set here to be used via `Load` and clear.
 	vars map[string]interface{}
+	// contextFuncs are the request-aware functions, bound to the request's context on render.
+	contextFuncs map[string]interface{}
 
 	jetDataContextKey string
 }
@@ -59,7 +66,8 @@ var jetExtensions = [...]string{
 // Usage:
 // Jet("./views", ".jet") or
 // Jet(iris.Dir("./views"), ".jet") or
-// Jet(AssetFile(), ".jet") for embedded data.
+// Jet(AssetFile(), ".jet") for embedded data or
+// Jet(embedFS, ".jet") for an embed.FS (or any fs.FS).
 func Jet(fs interface{}, extension string) *JetEngine {
 	extOK := false
 	for _, ext := range jetExtensions {
@@ -78,6 +86,7 @@ func Jet(fs interface{}, extension string) *JetEngine {
 		rootDir:           "/",
 		extension:         extension,
 		loader:            &jetLoader{fs: getFS(fs)},
+		cache:             new(jetCache),
 		jetDataContextKey: "_jet",
 	}
 
@@ -117,12 +126,42 @@ func (s *JetEngine) Delims(left, right string) *JetEngine {
 	return s
 }
 
+// Layout sets the layout template file which inside should use
+// the {{ embed() }} func to embed the main template file,
+// the "yield" is a reserved keyword of the jet parser.
+// The layout receives the same data and runtime variables as the main template.
+// The jet's own {{ extends }} and {{ block }} statements can still be used by the templates.
+//
+// Example: Jet("./views", ".jet").Layout("layouts/main.jet")
+//
+// Note: Layout can be changed for a specific call
+// action with the option: "layout" on the iris' context.Render function.
+func (s *JetEngine) Layout(layoutFile string) *JetEngine {
+	s.layout = layoutFile
+	return s
+}
+
 // JetArguments is a type alias of `jet.Arguments`,
 // can be used on `AddFunc$funcBody`.
 type JetArguments = jet.Arguments
 
 // AddFunc should adds a global function to the jet template set.
+// The "funcBody" can be a `jet.Func`, a func(JetArguments) reflect.Value
+// or any other Go function.
+// A function which accepts an `iris.Context` as its first input argument
+// receives the context of the request which the template is rendered for.
 func (s *JetEngine) AddFunc(funcName string, funcBody interface{}) {
+	if isContextFunc(funcBody) {
+		s.mu.Lock()
+		if s.contextFuncs == nil {
+			s.contextFuncs = make(map[string]interface{})
+		}
+		s.contextFuncs[funcName] = funcBody
+		s.mu.Unlock()
+
+		funcBody = bindContextFunc(funcBody, nil)
+	}
+
 	// if something like "urlpath" is registered.
 	if generalFunc, ok := funcBody.(func(string, ...interface{}) string); ok {
 		// jet, unlike others does not accept a func(string, ...interface{}) string,
@@ -159,7 +198,14 @@ func (s *JetEngine) AddFunc(funcName string, funcBody interface{}) {
 	if jetFunc, ok := funcBody.(jet.Func); !ok {
 		alternativeJetFunc, ok := funcBody.(func(JetArguments) reflect.Value)
 		if !ok {
-			panic(fmt.Sprintf("JetEngine.AddFunc: funcBody argument is not a type of func(JetArguments) reflect.Value. Got %T instead", funcBody))
+			// any other Go function, e.g. strings.ToUpper,
+			// is called through reflection by the jet runtime.
+			if reflect.TypeOf(funcBody).Kind() != reflect.Func {
+				panic(fmt.Sprintf("JetEngine.AddFunc: funcBody argument is not a function. Got %T instead", funcBody))
+			}
+
+			s.AddVar(funcName, funcBody)
+			return
 		}
 
 		s.AddVar(funcName, jet.Func(alternativeJetFunc))
@@ -180,12 +226,16 @@ func (s *JetEngine) AddVar(key string, value interface{}) {
 	}
 }
 
-// Reload if setted to true the templates are reloading on each render,
+// Reload if set to true the template files are watched for changes,
 // use it when you're in development and you're boring of restarting
 // the whole app when you edit a template file.
+// Each render checks the modification time of the template files
+// and re-parses only the changed ones and the templates which depend on them
+// (e.g. through an "extends", "import" or "include" statement), removed files are removed from the cache.
 //
-// Note that if `true` is passed then only one `View -> ExecuteWriter` will be render each time,
-// not safe concurrent access across clients, use it only on development state.
+// Note that the template files are checked on each `View -> ExecuteWriter`,
+// use it only on development status.
+// It's good to be used side by side with the https://github.com/kataras/rizla reloader for go source files.
 func (s *JetEngine) Reload(developmentMode bool) *JetEngine {
 	s.developmentMode = developmentMode
 	return s
@@ -211,13 +261,38 @@ func (l *jetLoader) Open(name string) (io.ReadCloser, error) {
 
 // Exists checks if the template name exists by walking the list of template paths.
 func (l *jetLoader) Exists(name string) bool {
-	_, err := l.fs.Open(name)
-	return err == nil
+	f, err := l.fs.Open(name)
+	if err != nil || f == nil { // a nil file is returned by the no-op file system.
+		return false
+	}
+
+	f.Close()
+	return true
+}
+
+// jetCache is the templates cache of the jet Set,
+// it's kept by the engine so the templates of `ParseTemplate` are cached too.
+type jetCache struct {
+	m sync.Map
+}
+
+var _ jet.Cache = (*jetCache)(nil)
+
+func (c *jetCache) Get(templatePath string) *jet.Template {
+	if t, ok := c.m.Load(templatePath); ok {
+		return t.(*jet.Template)
+	}
+
+	return nil
+}
+
+func (c *jetCache) Put(templatePath string, t *jet.Template) {
+	c.m.Store(templatePath, t)
 }
 
 // Load should load the templates from a physical system directory or by an embedded one (assets/go-bindata).
 func (s *JetEngine) Load() error {
-	return walk(s.fs, s.rootDir, func(path string, info os.FileInfo, err error) error {
+	err := walk(s.fs, s.rootDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
@@ -239,6 +314,20 @@ func (s *JetEngine) Load() error {
 
 		return s.ParseTemplate(path, string(buf))
 	})
+	if err == nil && s.developmentMode {
+		s.watcher = newTemplateWatcher(s.fs, s.rootDir, s.extension)
+	}
+
+	return err
+}
+
+// reloadChanged re-parses the changed templates, see `Reload`.
+func (s *JetEngine) reloadChanged() error {
+	return s.watcher.reload(func(name string, contents []byte) error {
+		return s.ParseTemplate(name, string(contents))
+	}, func(name string) {
+		s.cache.m.Delete(path.Join("/", filepath.ToSlash(name)))
+	})
 }
 
 // ParseTemplate accepts a name and contnets to parse and cache a template.
@@ -246,8 +335,14 @@ func (s *JetEngine) Load() error {
 func (s *JetEngine) ParseTemplate(name string, contents string) error {
 	s.initSet()
 
-	_, err := s.Set.Parse(name, contents)
-	return err
+	t, err := s.Set.Parse(name, contents)
+	if err != nil {
+		return err
+	}
+
+	// the jet's Set.Parse does not cache the template, the `ExecuteWriter` would load it again.
+	s.cache.Put(path.Join("/", filepath.ToSlash(name)), t)
+	return nil
 }
 
 func (s *JetEngine) initSet() {
@@ -255,12 +350,7 @@ func (s *JetEngine) initSet() {
 	if s.Set == nil {
 		var opts = []jet.Option{
 			jet.WithDelims(s.left, s.right),
-		}
-		if s.developmentMode && !isNoOpFS(s.fs) {
-			// this check is made to avoid jet's fs lookup on noOp fs (nil passed by the developer).
-			// This can be produced when nil fs passed
-			// and only `ParseTemplate` is used.
-			opts = append(opts, jet.InDevelopmentMode())
+			jet.WithCache(s.cache),
 		}
 
 		s.Set = jet.NewSet(s.loader, opts...)
@@ -319,6 +409,13 @@ func (s *JetEngine) AddRuntimeVars(ctx *context.Context, vars JetRuntimeVars) {
 
 // ExecuteWriter should execute a template by its filename with an optional layout and bindingData.
 func (s *JetEngine) ExecuteWriter(w io.Writer, filename string, layout string, bindingData interface{}) error {
+	// re-parse the changed templates if reload is enabled.
+	if s.developmentMode && s.watcher != nil {
+		if err := s.reloadChanged(); err != nil {
+			return err
+		}
+	}
+
 	tmpl, err := s.Set.GetTemplate(filename)
 	if err != nil {
 		return err
@@ -350,14 +447,18 @@ func (s *JetEngine) ExecuteWriter(w io.Writer, filename string, layout string, b
 		}
 	}
 
-	if bindingData == nil {
-		return tmpl.Execute(w, vars, nil)
-	}
-
 	if vars == nil {
 		vars = make(JetRuntimeVars)
 	}
 
+	if ctx := viewContext(w); ctx != nil {
+		s.mu.Lock()
+		for funcName, funcBody := range s.contextFuncs {
+			vars.Set(funcName, bindContextFunc(funcBody, ctx))
+		}
+		s.mu.Unlock()
+	}
+
 	/* fixed on jet v4.0.0, so no need of this:
 	if m, ok := bindingData.(context.Map); ok {
 		var jetData interface{}
@@ -379,5 +480,33 @@ func (s *JetEngine) ExecuteWriter(w io.Writer, filename string, layout string, b
 		}
 	}*/
 
+	if layout = getLayout(layout, s.layout); layout != "" {
+		lt, err := s.Set.GetTemplate(layout)
+		if err != nil {
+			return err
+		}
+
+		return lt.Execute(w, jetLayoutVars(tmpl, vars, bindingData), bindingData)
+	}
+
 	return tmpl.Execute(w, vars, bindingData)
 }
+
+// jetLayoutVars returns a copy of the "vars" with the layout's "embed" function,
+// which renders the "tmpl" main template in place.
+func jetLayoutVars(tmpl *jet.Template, vars JetRuntimeVars, bindingData interface{}) JetRuntimeVars {
+	layoutVars := make(JetRuntimeVars, len(vars)+1)
+	for key, value := range vars {
+		layoutVars[key] = value
+	}
+
+	layoutVars.SetFunc("embed", func(JetArguments) reflect.Value {
+		return reflect.ValueOf(jet.RendererFunc(func(r *JetRuntime) {
+			if err := tmpl.Execute(r.Writer, vars, bindingData); err != nil {
+				panic(err) // recovered by the layout's execution.
+			}
+		}))
+	})
+
+	return layoutVars
+}
@@ -0,0 +1,437 @@
+package view
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	texttemplate "text/template"
+
+	"github.com/BurntSushi/toml"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/russross/blackfriday/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// MarkdownEngine contains the markdown view engine structure.
+type MarkdownEngine struct {
+	fs http.FileSystem
+	// files configuration
+	rootDir         string
+	extension       string
+	layoutExtension string
+	layout          string
+	reload          bool
+	watcher         *templateWatcher // created by Load when reload is true.
+
+	extensions blackfriday.Extensions
+	sanitizer  func([]byte) []byte
+
+	rmu     sync.RWMutex // locks for pages, layouts and funcs.
+	pages   map[string]*markdownPage
+	layouts map[string]*template.Template // never executed, cloned on each render.
+	funcs   map[string]interface{}
+	bufPool *sync.Pool
+}
+
+// markdownPage is a parsed markdown template.
+type markdownPage struct {
+	frontMatter map[string]interface{}
+	// tmpl is nil when the page has no template actions,
+	// then the compiled (and sanitized) HTML is cached.
+	tmpl *texttemplate.Template
+	html []byte
+}
+
+var (
+	_ Engine       = (*MarkdownEngine)(nil)
+	_ EngineFuncer = (*MarkdownEngine)(nil)
+)
+
+// DefaultMarkdownSanitizer is the default sanitizer of the markdown engine,
+// it allows the HTML elements and attributes of user generated content,
+// see `MarkdownEngine.Sanitizer`.
+func DefaultMarkdownSanitizer(html []byte) []byte {
+	return markdownPolicy.SanitizeBytes(html)
+}
+
+var markdownPolicy = bluemonday.UGCPolicy()
+
+// Markdown creates and returns a new markdown view engine,
+// it renders markdown files to HTML, e.g. the pages of a docs or a blog section.
+// The given "extension" MUST begin with a dot, e.g. ".md".
+//
+// A markdown page can start with a front matter, YAML between "---" lines
+// or TOML between "+++" lines. Its values are merged with the view data
+// (the view data override them) and they can be read through the `FrontMatter` method.
+// The page's body is a text/template, e.g. {{ .title }}, before it's converted to HTML.
+// The output is sanitized, see `Sanitizer`. The pages without template actions
+// are converted once and their HTML is cached.
+//
+// Layouts are html/template files with the ".html" extension (see `LayoutExt`),
+// the {{ yield }} renders the page. The front matter's "layout" key
+// overrides the engine's layout.
+//
+// Usage:
+// Markdown("./docs", ".md") or
+// Markdown(iris.Dir("./docs"), ".md") or
+// Markdown(AssetFile(), ".md") for embedded data or
+// Markdown(embedFS, ".md") for an embed.FS (or any fs.FS).
+func Markdown(fs interface{}, extension string) *MarkdownEngine {
+	s := &MarkdownEngine{
+		fs:              getFS(fs),
+		rootDir:         "/",
+		extension:       extension,
+		layoutExtension: ".html",
+		extensions:      blackfriday.CommonExtensions | blackfriday.AutoHeadingIDs,
+		sanitizer:       DefaultMarkdownSanitizer,
+		pages:           make(map[string]*markdownPage),
+		layouts:         make(map[string]*template.Template),
+		funcs:           make(map[string]interface{}),
+		bufPool: &sync.Pool{New: func() interface{} {
+			return new(bytes.Buffer)
+		}},
+	}
+
+	return s
+}
+
+// RootDir sets the directory to be used as a starting point
+// to load templates from the provided file system.
+func (s *MarkdownEngine) RootDir(root string) *MarkdownEngine {
+	s.rootDir = filepath.ToSlash(root)
+	return s
+}
+
+// Name returns the markdown engine's name.
+func (s *MarkdownEngine) Name() string {
+	return "Markdown"
+}
+
+// Ext returns the file extension which this view engine is responsible to render.
+// If the filename extension on ExecuteWriter is empty then this is appended.
+func (s *MarkdownEngine) Ext() string {
+	return s.extension
+}
+
+// LayoutExt sets the file extension of the html/template layouts.
+// Defaults to ".html".
+func (s *MarkdownEngine) LayoutExt(extension string) *MarkdownEngine {
+	s.layoutExtension = extension
+	return s
+}
+
+// Layout sets the layout template file which should use
+// the {{ yield }} func to yield the markdown page.
+func (s *MarkdownEngine) Layout(layoutFile string) *MarkdownEngine {
+	s.layout = layoutFile
+	return s
+}
+
+// Extensions sets the markdown (blackfriday) parser's extensions.
+// Defaults to the common extensions plus the auto heading IDs one.
+func (s *MarkdownEngine) Extensions(extensions blackfriday.Extensions) *MarkdownEngine {
+	s.extensions = extensions
+	return s
+}
+
+// Sanitizer sets the function which sanitizes the HTML of the markdown pages,
+// e.g. bluemonday.StrictPolicy().SanitizeBytes.
+// Pass nil to disable the sanitization, only for trusted pages.
+// Defaults to the `DefaultMarkdownSanitizer`.
+//
+// Note that the layouts are not sanitized.
+func (s *MarkdownEngine) Sanitizer(sanitizer func([]byte) []byte) *MarkdownEngine {
+	s.sanitizer = sanitizer
+	return s
+}
+
+// Reload if set to true the template files are watched for changes,
+// use it when you're in development and you're boring of restarting
+// the whole app when you edit a page.
+// Each render checks the modification time of the files
+// and re-parses only the changed ones and the templates which depend on them,
+// removed files are removed from the cache.
+//
+// Note that the files are checked on each `View -> ExecuteWriter`,
+// use it only on development status.
+func (s *MarkdownEngine) Reload(developmentMode bool) *MarkdownEngine {
+	s.reload = developmentMode
+	return s
+}
+
+// AddFunc adds the function to the pages' and the layouts' function map.
+// It is legal to overwrite elements of the default layout actions:
+// - yield func() (template.HTML, error).
+//
+// Note that the functions should be added before `Load`.
+func (s *MarkdownEngine) AddFunc(funcName string, funcBody interface{}) {
+	s.rmu.Lock()
+	s.funcs[funcName] = bindContextFunc(funcBody, nil) // the request-aware functions are not supported.
+	s.rmu.Unlock()
+}
+
+// Load parses the markdown pages and the layouts.
+//
+// Returns an error if something bad happens, user is responsible to catch it.
+func (s *MarkdownEngine) Load() error {
+	err := walk(s.fs, s.rootDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info == nil || info.IsDir() || !s.isTemplate(path) {
+			return nil
+		}
+
+		contents, err := asset(s.fs, path)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+
+		return s.ParseTemplate(path, contents)
+	})
+	if err == nil && s.reload {
+		// the layouts have a different extension than the pages.
+		s.watcher = newTemplateWatcher(s.fs, s.rootDir, "")
+	}
+
+	return err
+}
+
+func (s *MarkdownEngine) isTemplate(name string) bool {
+	return strings.HasSuffix(name, s.extension) ||
+		(s.layoutExtension != "" && strings.HasSuffix(name, s.layoutExtension))
+}
+
+// reloadChanged re-parses the changed pages and layouts, see `Reload`.
+func (s *MarkdownEngine) reloadChanged() error {
+	return s.watcher.reload(func(name string, contents []byte) error {
+		if !s.isTemplate(name) {
+			return nil
+		}
+
+		return s.ParseTemplate(name, contents)
+	}, func(name string) {
+		name = strings.TrimPrefix(name, "/")
+		s.rmu.Lock()
+		delete(s.pages, name)
+		delete(s.layouts, name)
+		s.rmu.Unlock()
+	})
+}
+
+// ParseTemplate adds a custom page (or a layout, based on its extension) from text.
+func (s *MarkdownEngine) ParseTemplate(name string, contents []byte) error {
+	name = strings.TrimPrefix(name, "/")
+
+	s.rmu.Lock()
+	defer s.rmu.Unlock()
+
+	if s.layoutExtension != "" && strings.HasSuffix(name, s.layoutExtension) {
+		tmpl, err := template.New(name).Funcs(template.FuncMap{
+			"yield": func() (template.HTML, error) { return "", nil },
+		}).Funcs(s.funcs).Parse(string(contents))
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+
+		s.layouts[name] = tmpl
+		return nil
+	}
+
+	frontMatter, body, err := parseFrontMatter(contents)
+	if err != nil {
+		return fmt.Errorf("%s: front matter: %w", name, err)
+	}
+
+	page := &markdownPage{frontMatter: frontMatter}
+	if bytes.Contains(body, []byte("{{")) {
+		page.tmpl, err = texttemplate.New(name).Funcs(s.funcs).Parse(string(body))
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	} else {
+		page.html = s.toHTML(body)
+	}
+
+	s.pages[name] = page
+	return nil
+}
+
+func (s *MarkdownEngine) toHTML(markdown []byte) []byte {
+	html := blackfriday.Run(markdown, blackfriday.WithExtensions(s.extensions))
+	if s.sanitizer != nil {
+		html = s.sanitizer(html)
+	}
+
+	return html
+}
+
+// FrontMatter returns the front matter of the "name" markdown page.
+func (s *MarkdownEngine) FrontMatter(name string) (map[string]interface{}, bool) {
+	s.rmu.RLock()
+	page, ok := s.pages[strings.TrimPrefix(name, "/")]
+	s.rmu.RUnlock()
+	if !ok {
+		return nil, false
+	}
+
+	frontMatter := make(map[string]interface{}, len(page.frontMatter))
+	for k, v := range page.frontMatter {
+		frontMatter[k] = v
+	}
+
+	return frontMatter, true
+}
+
+var (
+	yamlFrontMatterDelim = []byte("---")
+	tomlFrontMatterDelim = []byte("+++")
+)
+
+// parseFrontMatter splits the front matter from the markdown body.
+func parseFrontMatter(contents []byte) (map[string]interface{}, []byte, error) {
+	var (
+		delim     []byte
+		unmarshal func([]byte, interface{}) error
+	)
+
+	switch {
+	case bytes.HasPrefix(contents, yamlFrontMatterDelim):
+		delim, unmarshal = yamlFrontMatterDelim, yaml.Unmarshal
+	case bytes.HasPrefix(contents, tomlFrontMatterDelim):
+		delim, unmarshal = tomlFrontMatterDelim, toml.Unmarshal
+	default:
+		return nil, contents, nil
+	}
+
+	rest := bytes.TrimLeft(contents[len(delim):], " \t")
+	if len(rest) > 0 && rest[0] == '\r' {
+		rest = rest[1:]
+	}
+	if len(rest) == 0 || rest[0] != '\n' {
+		return nil, contents, nil // e.g. a horizontal rule followed by text.
+	}
+
+	end := bytes.Index(rest, append([]byte("\n"), delim...))
+	if end == -1 {
+		return nil, nil, fmt.Errorf("missing closing %q", delim)
+	}
+
+	frontMatter := make(map[string]interface{})
+	if err := unmarshal(rest[:end], &frontMatter); err != nil {
+		return nil, nil, err
+	}
+
+	body := rest[end+1+len(delim):]
+	if i := bytes.IndexByte(body, '\n'); i != -1 && len(bytes.TrimSpace(body[:i])) == 0 {
+		body = body[i+1:]
+	}
+
+	return frontMatter, body, nil
+}
+
+// ExecuteWriter renders the markdown page to the w writer, inside the layout, if any.
+func (s *MarkdownEngine) ExecuteWriter(w io.Writer, filename string, layout string, bindingData interface{}) error {
+	// re-parse the templates if reload is enabled.
+	if s.reload && s.watcher != nil {
+		if err := s.reloadChanged(); err != nil {
+			return err
+		}
+	}
+
+	name := strings.TrimPrefix(filename, "/")
+
+	s.rmu.RLock()
+	page, ok := s.pages[name]
+	s.rmu.RUnlock()
+	if !ok {
+		return ErrNotExist{Name: name, IsLayout: false, Data: bindingData}
+	}
+
+	data := page.data(bindingData)
+
+	html := page.html
+	if page.tmpl != nil {
+		buf := s.bufPool.Get().(*bytes.Buffer)
+		buf.Reset()
+		defer s.bufPool.Put(buf)
+
+		if err := page.tmpl.Execute(buf, data); err != nil {
+			return err
+		}
+
+		html = s.toHTML(buf.Bytes())
+	}
+
+	if layout != NoLayout && layout == "" {
+		if pageLayout, ok := page.frontMatter["layout"].(string); ok {
+			layout = pageLayout
+		}
+	}
+
+	layout = strings.TrimPrefix(getLayout(layout, s.layout), "/")
+	if layout == "" {
+		_, err := w.Write(html)
+		return err
+	}
+
+	lt, err := s.lookupLayout(layout)
+	if err != nil {
+		return err
+	}
+
+	lt = lt.Funcs(template.FuncMap{
+		"yield": func() (template.HTML, error) {
+			return template.HTML(html), nil
+		},
+	})
+
+	return lt.Execute(w, data)
+}
+
+// lookupLayout returns a copy of the "name" layout, the view adds the
+// engine's extension to the layout names, so it's trimmed if necessary.
+func (s *MarkdownEngine) lookupLayout(name string) (*template.Template, error) {
+	s.rmu.RLock()
+	lt, ok := s.layouts[name]
+	if !ok {
+		lt, ok = s.layouts[strings.TrimSuffix(name, s.extension)]
+	}
+	s.rmu.RUnlock()
+
+	if !ok {
+		return nil, ErrNotExist{Name: name, IsLayout: true}
+	}
+
+	return lt.Clone()
+}
+
+// data returns the front matter merged with the "bindingData",
+// if it's a map, otherwise the "bindingData" as it is.
+func (p *markdownPage) data(bindingData interface{}) interface{} {
+	var viewData map[string]interface{}
+	switch v := bindingData.(type) {
+	case nil:
+	case map[string]interface{}:
+		viewData = v
+	default:
+		return bindingData
+	}
+
+	data := make(map[string]interface{}, len(p.frontMatter)+len(viewData))
+	for k, v := range p.frontMatter {
+		data[k] = v
+	}
+	for k, v := range viewData {
+		data[k] = v
+	}
+
+	return data
+}
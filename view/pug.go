@@ -16,7 +16,8 @@ import (
 // Usage:
 // Pug("./views", ".pug") or
 // Pug(iris.Dir("./views"), ".pug") or
-// Pug(AssetFile(), ".pug") for embedded data.
+// Pug(AssetFile(), ".pug") for embedded data or
+// Pug(embedFS, ".pug") for an embed.FS (or any fs.FS).
 //
 // Examples:
 // https://github.com/kataras/iris/tree/master/_examples/view/template_pug_0
@@ -24,15 +24,28 @@ type (
 type ErrNotExist = context.ErrViewNotExist
 
 // View is just a wrapper on top of the registered template engine.
-type View struct{ Engine }
+type View struct {
+	Engine
+
+	// funcs is the registry of the template functions shared by all engines,
+	// see `AddFunc`.
+	funcs map[string]interface{}
+}
 
 // Register registers a view engine.
+// The shared template functions (see `AddFunc`) are added to the new engine.
 func (v *View) Register(e Engine) {
 	if v.Engine != nil {
 		golog.Warnf("Engine already exists, replacing the old %q with the new one %q", v.Engine.Name(), e.Name())
 	}
 
 	v.Engine = e
+
+	if funcer, ok := e.(EngineFuncer); ok {
+		for funcName, funcBody := range v.funcs {
+			funcer.AddFunc(funcName, funcBody)
+		}
+	}
 }
 
 // Registered reports whether an engine was registered.
@@ -64,9 +77,44 @@ func (v *View) ExecuteWriter(w io.Writer, filename string, layout string, bindin
 	return v.Engine.ExecuteWriter(w, filename, layout, bindingData)
 }
 
-// AddFunc adds a function to all registered engines.
+// ToWriter renders the "filename" template with the "bindingData" to the "w" writer,
+// outside of a request, e.g. an e-mail body. The engine's layout is used,
+// unless a "layout" (or `NoLayout`) is given.
+func (v *View) ToWriter(w io.Writer, filename string, bindingData interface{}, layout ...string) error {
+	if !v.Registered() {
+		return fmt.Errorf("No engine is registered")
+	}
+
+	var l string
+	if len(layout) > 0 {
+		l = layout[0]
+	}
+
+	return v.ExecuteWriter(w, filename, l, bindingData)
+}
+
+// ToString works like `ToWriter` but it returns the rendered template as string.
+func (v *View) ToString(filename string, bindingData interface{}, layout ...string) (string, error) {
+	var b strings.Builder
+	if err := v.ToWriter(&b, filename, bindingData, layout...); err != nil {
+		return "", err
+	}
+
+	return b.String(), nil
+}
+
+// AddFunc adds a function to the shared template functions registry
+// and to the registered engine, if it supports functions.
+// The functions are kept when switching engines, a later registered engine
+// receives all of them, e.g. the html/template engine adds them to its FuncMap
+// and the django (pongo2) one to its globals and filters.
 // Each template engine that supports functions has its own AddFunc too.
 func (v *View) AddFunc(funcName string, funcBody interface{}) {
+	if v.funcs == nil {
+		v.funcs = make(map[string]interface{})
+	}
+	v.funcs[funcName] = funcBody
+
 	if !v.Registered() {
 		return
 	}
@@ -76,6 +124,16 @@ func (v *View) AddFunc(funcName string, funcBody interface{}) {
 	}
 }
 
+// Funcs returns a copy of the shared template functions, see `AddFunc`.
+func (v *View) Funcs() map[string]interface{} {
+	funcs := make(map[string]interface{}, len(v.funcs))
+	for funcName, funcBody := range v.funcs {
+		funcs[funcName] = funcBody
+	}
+
+	return funcs
+}
+
 // Load compiles all the registered engines.
 func (v *View) Load() error {
 	if !v.Registered() {
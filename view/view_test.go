@@ -0,0 +1,320 @@
+package view_test
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/httptest"
+	"github.com/kataras/iris/v12/view"
+
+	"github.com/flosch/pongo2/v4"
+)
+
+func TestViewFuncs(t *testing.T) {
+	var v view.View
+	v.AddFunc("shout", strings.ToUpper)
+	v.AddFunc("userpath", func(id int, tab ...string) string {
+		return fmt.Sprintf("/users/%d/%s", id, strings.Join(tab, "/"))
+	})
+
+	html := view.HTML("./", ".html")
+	v.Register(html)
+	if err := html.ParseTemplate("index.html", []byte(`{{ shout .Name }} {{ userpath 42 "posts" }}`), nil); err != nil {
+		t.Fatal(err)
+	}
+	expectView(t, &v, map[string]interface{}{"Name": "iris"}, "IRIS /users/42/posts")
+
+	// The functions are shared with a later registered engine.
+	django := view.Django("./", ".html")
+	v.Register(django)
+	if err := django.ParseTemplate("index.html", []byte(`{{ shout(name) }} {{ name|shout }} {{ 42|userpath:"posts" }}`)); err != nil {
+		t.Fatal(err)
+	}
+	expectView(t, &v, map[string]interface{}{"name": "iris"}, "IRIS IRIS /users/42/posts")
+
+	if len(v.Funcs()) != 2 {
+		t.Fatalf("expected 2 shared functions but got: %v", v.Funcs())
+	}
+}
+
+func TestJetLayout(t *testing.T) {
+	var v view.View
+	v.AddFunc("shout", strings.ToUpper)
+
+	jet := view.Jet(nil, ".jet").Layout("layout.jet")
+	v.Register(jet)
+	templates := map[string]string{
+		"layout.jet":  `<title>{{ .title }}</title>{{ embed() }}`,
+		"layout2.jet": `<main>{{ embed() }}</main>`,
+		"index.jet":   `<b>{{ shout(.name) }}</b>`,
+	}
+	for name, contents := range templates {
+		if err := jet.ParseTemplate(name, contents); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	data := map[string]interface{}{"title": "Home", "name": "iris"}
+	tests := []struct {
+		layout   string
+		expected string
+	}{
+		{"", "<title>Home</title><b>IRIS</b>"},
+		{"layout2.jet", "<main><b>IRIS</b></main>"},
+		{view.NoLayout, "<b>IRIS</b>"},
+	}
+
+	for i, tt := range tests {
+		var b strings.Builder
+		if err := v.ExecuteWriter(&b, "index.jet", tt.layout, data); err != nil {
+			t.Fatalf("[%d] %v", i, err)
+		}
+
+		if got := b.String(); got != tt.expected {
+			t.Fatalf("[%d] expected: %q but got: %q", i, tt.expected, got)
+		}
+	}
+}
+
+func TestViewFS(t *testing.T) {
+	fileSystem := fstest.MapFS{
+		"views/index.html":  {Data: []byte(`<h1>{{ .Title }}</h1>`)},
+		"views/index.jet":   {Data: []byte(`<h1>{{ .Title }}</h1>`)},
+		"views/index.hbs":   {Data: []byte(`<h1>{{ Title }}</h1>`)},
+		"django/index.html": {Data: []byte(`<h1>{{ Title }}</h1>`)},
+	}
+
+	// e.g. //go:embed views
+	views, err := fs.Sub(fileSystem, "views")
+	if err != nil {
+		t.Fatal(err)
+	}
+	djangoViews, err := fs.Sub(fileSystem, "django")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		engine   view.Engine
+		filename string
+	}{
+		{view.HTML(views, ".html"), "index.html"},
+		{view.Django(djangoViews, ".html"), "index.html"},
+		{view.Jet(views, ".jet"), "index.jet"},
+		{view.Handlebars(views, ".hbs"), "index.hbs"},
+		{view.Blocks(fileSystem, ".html").RootDir("views"), "index"},
+	}
+
+	for _, tt := range tests {
+		var v view.View
+		v.Register(tt.engine)
+		if err := v.Load(); err != nil {
+			t.Fatalf("%s: %v", tt.engine.Name(), err)
+		}
+
+		var b strings.Builder
+		if err := v.ExecuteWriter(&b, tt.filename, view.NoLayout, map[string]interface{}{"Title": "fs"}); err != nil {
+			t.Fatalf("%s: %v", tt.engine.Name(), err)
+		}
+
+		if expected, got := "<h1>fs</h1>", strings.TrimSpace(b.String()); expected != got {
+			t.Fatalf("%s: expected: %q but got: %q", tt.engine.Name(), expected, got)
+		}
+	}
+}
+
+func expectView(t *testing.T, v *view.View, data map[string]interface{}, expected string) {
+	t.Helper()
+
+	var b strings.Builder
+	if err := v.ExecuteWriter(&b, "index.html", view.NoLayout, data); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := b.String(); got != expected {
+		t.Fatalf("expected: %q but got: %q", expected, got)
+	}
+}
+
+func TestViewReload(t *testing.T) {
+	tests := []struct {
+		engine   func(fs.FS) view.Engine
+		files    fstest.MapFS
+		filename string
+		expected string
+		// the changed file and the expected result after the change.
+		changed         string
+		contents        string
+		expectedChanged string
+	}{
+		{
+			engine: func(fileSystem fs.FS) view.Engine { return view.HTML(fileSystem, ".html").Reload(true) },
+			files: fstest.MapFS{
+				"index.html": {Data: []byte(`<h1>{{ template "title.html" . }}</h1>`)},
+				"title.html": {Data: []byte(`{{ .Title }}`)},
+			},
+			filename:        "index.html",
+			expected:        "<h1>reload</h1>",
+			changed:         "title.html",
+			contents:        `{{ .Title }} changed`,
+			expectedChanged: "<h1>reload changed</h1>",
+		},
+		{
+			engine: func(fileSystem fs.FS) view.Engine { return view.Django(fileSystem, ".html").Reload(true) },
+			files: fstest.MapFS{
+				"index.html": {Data: []byte(`{% extends "base.html" %}{% block title %}{{ Title }}{% endblock %}`)},
+				"base.html":  {Data: []byte(`<h1>{% block title %}{% endblock %}</h1>`)},
+			},
+			filename:        "index.html",
+			expected:        "<h1>reload</h1>",
+			changed:         "base.html",
+			contents:        `<h2>{% block title %}{% endblock %}</h2>`,
+			expectedChanged: "<h2>reload</h2>",
+		},
+		{
+			engine: func(fileSystem fs.FS) view.Engine { return view.Jet(fileSystem, ".jet").Reload(true) },
+			files: fstest.MapFS{
+				"index.jet": {Data: []byte(`{{ extends "/base.jet" }}{{ block title() }}{{ .Title }}{{ end }}`)},
+				"base.jet":  {Data: []byte(`<h1>{{ yield title() }}</h1>`)},
+			},
+			filename:        "index.jet",
+			expected:        "<h1>reload</h1>",
+			changed:         "base.jet",
+			contents:        `<h2>{{ yield title() }}</h2>`,
+			expectedChanged: "<h2>reload</h2>",
+		},
+	}
+
+	data := map[string]interface{}{"Title": "reload"}
+	expect := func(v *view.View, engine view.Engine, filename, expected string) {
+		t.Helper()
+
+		var b strings.Builder
+		if err := v.ExecuteWriter(&b, filename, view.NoLayout, data); err != nil {
+			t.Fatalf("%s: %v", engine.Name(), err)
+		}
+
+		if got := strings.TrimSpace(b.String()); got != expected {
+			t.Fatalf("%s: expected: %q but got: %q", engine.Name(), expected, got)
+		}
+	}
+
+	for _, tt := range tests {
+		engine := tt.engine(tt.files)
+
+		var v view.View
+		v.Register(engine)
+		if err := v.Load(); err != nil {
+			t.Fatalf("%s: %v", engine.Name(), err)
+		}
+
+		expect(&v, engine, tt.filename, tt.expected)
+
+		tt.files[tt.changed] = &fstest.MapFile{Data: []byte(tt.contents), ModTime: time.Now()}
+		expect(&v, engine, tt.filename, tt.expectedChanged)
+
+		delete(tt.files, tt.filename)
+		if err := v.ExecuteWriter(io.Discard, tt.filename, view.NoLayout, data); err == nil {
+			t.Fatalf("%s: expected an error for the removed template", engine.Name())
+		}
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	var v view.View
+	v.AddFunc("shout", strings.ToUpper)
+
+	md := view.Markdown(fstest.MapFS{
+		"index.md":          {Data: []byte("---\ntitle: Home\n---\n# {{ shout .title }}\n\nHello *{{ .name }}*<script>alert(1)</script>\n")},
+		"about.md":          {Data: []byte("+++\ntitle = \"About\"\nlayout = \"layouts/page.html\"\n+++\nStatic **page**.\n")},
+		"layouts/main.html": {Data: []byte(`<title>{{ .title }}</title>{{ yield }}`)},
+		"layouts/page.html": {Data: []byte(`<main>{{ yield }}</main>`)},
+	}, ".md").Layout("layouts/main.html")
+	v.Register(md)
+	if err := v.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		layout   string
+		data     map[string]interface{}
+		expected string
+	}{
+		{"index", view.NoLayout, map[string]interface{}{"name": "iris"},
+			"<h1 id=\"home\">HOME</h1>\n\n<p>Hello <em>iris</em></p>\n"},
+		{"index", "", map[string]interface{}{"name": "iris", "title": "Override"},
+			"<title>Override</title><h1 id=\"override\">OVERRIDE</h1>\n\n<p>Hello <em>iris</em></p>\n"},
+		{"about", "", nil, "<main><p>Static <strong>page</strong>.</p>\n</main>"},
+	}
+
+	for i, tt := range tests {
+		var b strings.Builder
+		if err := v.ExecuteWriter(&b, tt.name, tt.layout, tt.data); err != nil {
+			t.Fatalf("[%d] %v", i, err)
+		}
+
+		if got := b.String(); got != tt.expected {
+			t.Fatalf("[%d] expected: %q but got: %q", i, tt.expected, got)
+		}
+	}
+
+	if frontMatter, ok := md.FrontMatter("about.md"); !ok || frontMatter["title"] != "About" {
+		t.Fatalf("expected the about page's front matter but got: %v", frontMatter)
+	}
+}
+
+type greetTag struct{ name pongo2.IEvaluator }
+
+func (n *greetTag) Execute(ctx *pongo2.ExecutionContext, w pongo2.TemplateWriter) *pongo2.Error {
+	v, err := n.name.Evaluate(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, werr := w.WriteString("Hello, " + v.String())
+	if werr != nil {
+		return ctx.Error(werr.Error(), nil)
+	}
+	return nil
+}
+
+func TestDjangoLoadersTagsGlobals(t *testing.T) {
+	django := view.Django(fstest.MapFS{
+		"index.html": {Data: []byte(`{% extends "base.html" %}{% block body %}{% greet user %} ({{ page }}){% endblock %}`)},
+	}, ".html")
+	django.AddLoader(view.DjangoMapLoader{
+		"base.html":  `<main>{% block body %}{% endblock %}</main>`,
+		"about.html": `{% greet "about" %}, {{ user }}`,
+	})
+	django.AddTag("greet", func(doc *pongo2.Parser, start *pongo2.Token, arguments *pongo2.Parser) (pongo2.INodeTag, *pongo2.Error) {
+		name, err := arguments.ParseExpression()
+		if err != nil {
+			return nil, err
+		}
+		return &greetTag{name: name}, nil
+	})
+	django.ContextGlobals(func(ctx iris.Context) map[string]interface{} {
+		return map[string]interface{}{"user": ctx.URLParamDefault("user", "guest"), "page": "global"}
+	})
+
+	app := iris.New()
+	app.RegisterView(django)
+	app.Get("/{name}", func(ctx iris.Context) {
+		ctx.ViewData("page", "local")
+		if err := ctx.View(ctx.Params().Get("name")); err != nil {
+			ctx.StopWithError(iris.StatusInternalServerError, err)
+		}
+	})
+
+	e := httptest.New(t, app)
+	e.GET("/index.html").WithQuery("user", "iris").Expect().Status(httptest.StatusOK).
+		Body().Equal("<main>Hello, iris (local)</main>")
+	e.GET("/about.html").Expect().Status(httptest.StatusOK).Body().Equal("Hello, about, guest")
+}
@@ -0,0 +1,180 @@
+package view
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// templateWatcher detects the template files which were changed since its previous check
+// by their modification time and size, see the `Reload` method of the engines.
+type templateWatcher struct {
+	fs        http.FileSystem
+	rootDir   string
+	extension string
+
+	mu     sync.Mutex
+	stamps map[string]templateStamp
+	// the source of the templates, to find the dependents of a changed one.
+	contents map[string][]byte
+}
+
+type templateStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// newTemplateWatcher returns a new templateWatcher
+// which records the current state of the templates.
+func newTemplateWatcher(fs http.FileSystem, rootDir, extension string) *templateWatcher {
+	w := &templateWatcher{
+		fs:        fs,
+		rootDir:   rootDir,
+		extension: extension,
+		contents:  make(map[string][]byte),
+	}
+	w.changes()
+
+	return w
+}
+
+// changes returns the changed (new or modified) templates, followed by the ones
+// which depend on them (e.g. through an "extends" or "include" statement) in order,
+// and the removed templates.
+// A dependent template is one which contains the name of a changed or removed template in its source.
+func (w *templateWatcher) changes() (affected, removed []string, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	current := make(map[string]templateStamp)
+	err = walk(w.fs, w.rootDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info == nil || info.IsDir() {
+			return nil
+		}
+
+		if w.extension != "" && !strings.HasSuffix(path, w.extension) {
+			return nil
+		}
+
+		current[path] = templateStamp{modTime: info.ModTime(), size: info.Size()}
+		return nil
+	})
+	if err != nil {
+		return
+	}
+
+	var changed []string
+	for name, stamp := range current {
+		if prev, ok := w.stamps[name]; ok && prev.modTime.Equal(stamp.modTime) && prev.size == stamp.size {
+			continue
+		}
+
+		contents, err := asset(w.fs, name)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", name, err)
+		}
+
+		w.contents[name] = contents
+		changed = append(changed, name)
+	}
+
+	for name := range w.stamps {
+		if _, ok := current[name]; !ok {
+			delete(w.contents, name)
+			removed = append(removed, name)
+		}
+	}
+
+	primed := w.stamps != nil
+	w.stamps = current
+	if !primed {
+		return nil, nil, nil
+	}
+
+	sort.Strings(changed)
+	sort.Strings(removed)
+	return w.withDependents(changed, removed), removed, nil
+}
+
+func (w *templateWatcher) withDependents(changed, removed []string) []string {
+	names := make([]string, 0, len(w.contents))
+	for name := range w.contents {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	affected := append([]string(nil), changed...)
+	seen := make(map[string]struct{}, len(changed))
+	for _, name := range changed {
+		seen[name] = struct{}{}
+	}
+
+	queue := append(append([]string(nil), changed...), removed...)
+	for len(queue) > 0 {
+		// the templates are referenced with or without their extension.
+		ref := []byte(strings.TrimSuffix(path.Base(queue[0]), w.extension))
+		queue = queue[1:]
+
+		for _, name := range names {
+			if _, ok := seen[name]; ok || !bytes.Contains(w.contents[name], ref) {
+				continue
+			}
+
+			seen[name] = struct{}{}
+			affected = append(affected, name)
+			queue = append(queue, name)
+		}
+	}
+
+	return affected
+}
+
+// forget removes the recorded state of the "name" template,
+// or of all templates if "name" is empty, so it's reported as changed on the next check.
+func (w *templateWatcher) forget(name string) {
+	w.mu.Lock()
+	if name == "" {
+		w.stamps = make(map[string]templateStamp)
+	} else {
+		delete(w.stamps, name)
+	}
+	w.mu.Unlock()
+}
+
+// reload re-parses the affected templates through the "parse" function
+// and removes the deleted ones through the "remove" function.
+// A template which fails to parse is re-parsed on the next check.
+func (w *templateWatcher) reload(parse func(name string, contents []byte) error, remove func(name string)) error {
+	affected, removed, err := w.changes()
+	if err != nil {
+		return err
+	}
+
+	for _, name := range removed {
+		remove(name)
+	}
+
+	for _, name := range affected {
+		contents, err := asset(w.fs, name)
+		if err == nil {
+			err = parse(name, contents)
+		}
+
+		if err != nil {
+			w.forget(name)
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,466 @@
+// Package viewmodel maps domain structs to response shapes (view models),
+// so handlers and controllers can return their domain objects
+// and the response layer decides which fields are exposed, under which names and to whom.
+//
+// The exposure is declared through the "view" struct field tag:
+//  type User struct {
+//      ID       int64  `view:"id"`
+//      Email    string `view:"email,roles=admin|owner"`
+//      Bio      string `view:"bio,omitempty"`
+//      Password string `view:"-"`
+//  }
+// and/or through the `Define` builder, e.g. for types of third-party packages:
+//  viewmodel.Define(User{}).Rename("Email", "email_address").Hide("Password")
+//
+// Fields without a "view" tag fallback to their "json" tag's name.
+// See `Map` and `ResultHandler`.
+package viewmodel
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/hero"
+)
+
+// Field describes how a struct field is exposed.
+type Field struct {
+	// Name is the Go field's name.
+	Name string
+	// Key is the field's name on the response.
+	Key string
+	// Roles, if not empty, limit the field's visibility
+	// to the requests with at least one of these roles.
+	Roles []string
+	// OmitEmpty omits the field when its value is empty.
+	OmitEmpty bool
+	// Hidden never exposes the field.
+	Hidden bool
+
+	index []int
+}
+
+func (f *Field) visible(roles []string) bool {
+	if f.Hidden {
+		return false
+	}
+
+	if len(f.Roles) == 0 {
+		return true
+	}
+
+	for _, role := range roles {
+		for _, allowed := range f.Roles {
+			if role == allowed {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Schema is the view model of a struct type,
+// built once per type from its field tags and modified through its builder methods.
+// See `Define`.
+type Schema struct {
+	typ     reflect.Type
+	defined bool
+
+	mu     sync.RWMutex
+	fields []*Field
+}
+
+var schemas sync.Map // key = reflect.Type, value = *Schema.
+
+// Define returns the schema of the type of "v" (a struct value or pointer to a struct)
+// in order to customize its exposure through the builder methods.
+// Defined types are always mapped by the `ResultHandler`,
+// even if they don't contain any "view" tag.
+//
+// It should be called before the server's start,
+// it panics if "v" is not a struct.
+func Define(v interface{}) *Schema {
+	typ := indirectType(reflect.TypeOf(v))
+	if typ == nil || typ.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("viewmodel: define: expected a struct but got: %T", v))
+	}
+
+	s := schemaOf(typ)
+	s.mu.Lock()
+	s.defined = true
+	s.mu.Unlock()
+	return s
+}
+
+func schemaOf(typ reflect.Type) *Schema {
+	if v, ok := schemas.Load(typ); ok {
+		return v.(*Schema)
+	}
+
+	s := &Schema{typ: typ, fields: appendFields(nil, typ, nil)}
+	v, _ := schemas.LoadOrStore(typ, s)
+	return v.(*Schema)
+}
+
+func appendFields(fields []*Field, typ reflect.Type, index []int) []*Field {
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+
+		tag, ok := f.Tag.Lookup("view")
+		if !ok {
+			tag = f.Tag.Get("json") // its unknown options are ignored.
+		}
+		if tag == "-" {
+			continue
+		}
+
+		parts := strings.Split(tag, ",")
+		fieldIndex := append(append([]int(nil), index...), i)
+		if f.Anonymous && parts[0] == "" && f.Type.Kind() == reflect.Struct {
+			fields = appendFields(fields, f.Type, fieldIndex)
+			continue
+		}
+
+		if f.PkgPath != "" { // unexported.
+			continue
+		}
+
+		field := &Field{Name: f.Name, Key: parts[0], index: fieldIndex}
+		if field.Key == "" {
+			field.Key = f.Name
+		}
+
+		for _, opt := range parts[1:] {
+			switch {
+			case opt == "omitempty":
+				field.OmitEmpty = true
+			case strings.HasPrefix(opt, "roles="):
+				field.Roles = strings.Split(strings.TrimPrefix(opt, "roles="), "|")
+			}
+		}
+
+		fields = append(fields, field)
+	}
+
+	return fields
+}
+
+func (s *Schema) field(name string) *Field {
+	for _, f := range s.fields {
+		if f.Name == name {
+			return f
+		}
+	}
+
+	panic(fmt.Sprintf("viewmodel: unknown field %q of %s", name, s.typ))
+}
+
+func (s *Schema) each(names []string, fn func(f *Field)) *Schema {
+	s.mu.Lock()
+	for _, name := range names {
+		fn(s.field(name))
+	}
+	s.mu.Unlock()
+	return s
+}
+
+// Rename sets the response key of the "field" (Go field name).
+// Returns itself.
+func (s *Schema) Rename(field, key string) *Schema {
+	return s.each([]string{field}, func(f *Field) { f.Key = key })
+}
+
+// Hide never exposes the given fields (Go field names).
+// Returns itself.
+func (s *Schema) Hide(fields ...string) *Schema {
+	return s.each(fields, func(f *Field) { f.Hidden = true })
+}
+
+// Only exposes just the given fields (Go field names), the rest are hidden.
+// Returns itself.
+func (s *Schema) Only(fields ...string) *Schema {
+	s.mu.Lock()
+	for _, f := range s.fields {
+		f.Hidden = true
+	}
+	s.mu.Unlock()
+
+	return s.each(fields, func(f *Field) { f.Hidden = false })
+}
+
+// Roles limits the visibility of the "field" (Go field name)
+// to the requests with at least one of the given "roles".
+// Returns itself.
+func (s *Schema) Roles(field string, roles ...string) *Schema {
+	return s.each([]string{field}, func(f *Field) { f.Roles = roles })
+}
+
+// OmitEmpty omits the given fields (Go field names) when their values are empty.
+// Returns itself.
+func (s *Schema) OmitEmpty(fields ...string) *Schema {
+	return s.each(fields, func(f *Field) { f.OmitEmpty = true })
+}
+
+// Fields returns a copy of the schema's fields.
+func (s *Schema) Fields() []Field {
+	s.mu.RLock()
+	fields := make([]Field, 0, len(s.fields))
+	for _, f := range s.fields {
+		fields = append(fields, *f)
+	}
+	s.mu.RUnlock()
+
+	return fields
+}
+
+// isViewModel reports whether the "typ" was defined or contains a "view" tag.
+func (s *Schema) isViewModel() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.defined {
+		return true
+	}
+
+	for _, f := range s.fields {
+		if _, ok := s.typ.FieldByIndex(f.index).Tag.Lookup("view"); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// KeyValue is an entry of an `Object`.
+type KeyValue struct {
+	Key   string
+	Value interface{}
+}
+
+// Object is the result of a mapped struct value.
+// It keeps the order of the struct fields when it's encoded to JSON.
+type Object []KeyValue
+
+// Get returns the value of the given "key" or nil.
+func (o Object) Get(key string) interface{} {
+	for _, kv := range o {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+
+	return nil
+}
+
+// MarshalJSON completes the json.Marshaler interface.
+func (o Object) MarshalJSON() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	buf.WriteByte('{')
+	for i, kv := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		key, err := json.Marshal(kv.Key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+
+		value, err := json.Marshal(kv.Value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// Map returns the view model of "v" for a request with the given "roles".
+// Structs are converted to `Object` values,
+// slices, arrays and maps of structs to slices and maps of `Object` values.
+// Any other value (e.g. time.Time or a json.Marshaler) is returned as it is.
+func Map(v interface{}, roles ...string) interface{} {
+	return mapValue(reflect.ValueOf(v), roles)
+}
+
+func mapValue(v reflect.Value, roles []string) interface{} {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+
+		if v.Type().Implements(jsonMarshalerType) {
+			return v.Interface()
+		}
+
+		v = v.Elem()
+	}
+
+	if !v.IsValid() {
+		return nil
+	}
+
+	typ := v.Type()
+	if !needsMapping(typ) {
+		return v.Interface()
+	}
+
+	switch typ.Kind() {
+	case reflect.Struct:
+		s := schemaOf(typ)
+		s.mu.RLock()
+		fields := s.fields
+		obj := make(Object, 0, len(fields))
+		for _, f := range fields {
+			if !f.visible(roles) {
+				continue
+			}
+
+			fv := v.FieldByIndex(f.index)
+			if f.OmitEmpty && isEmptyValue(fv) {
+				continue
+			}
+
+			obj = append(obj, KeyValue{Key: f.Key, Value: mapValue(fv, roles)})
+		}
+		s.mu.RUnlock()
+
+		return obj
+	case reflect.Slice, reflect.Array:
+		if typ.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+
+		list := make([]interface{}, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			list = append(list, mapValue(v.Index(i), roles))
+		}
+
+		return list
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+
+		m := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			m[iter.Key().String()] = mapValue(iter.Value(), roles)
+		}
+
+		return m
+	}
+
+	return v.Interface()
+}
+
+// needsMapping reports whether the "typ" is or contains a struct (except time.Time and json.Marshalers).
+func needsMapping(typ reflect.Type) bool {
+	if typ.Implements(jsonMarshalerType) || reflect.PtrTo(typ).Implements(jsonMarshalerType) {
+		return false
+	}
+
+	switch typ.Kind() {
+	case reflect.Struct:
+		return typ != timeType
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return needsMapping(typ.Elem())
+	case reflect.Map:
+		return typ.Key().Kind() == reflect.String && needsMapping(typ.Elem())
+	case reflect.Interface:
+		return true
+	}
+
+	return false
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+
+	return false
+}
+
+func indirectType(typ reflect.Type) reflect.Type {
+	for typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	return typ
+}
+
+// ResultHandler returns a result handler which maps the returned values of handlers and controllers
+// to their view models, see `Map`.
+// Only the defined (see `Define`) struct types and the ones which contain a "view" tag
+// (or slices, arrays and maps of them) are mapped, the rest values are rendered as they are.
+// The "roles" function reports the roles of the current request (e.g. of the authenticated user)
+// and it can be nil.
+//
+// Usage:
+//  app.ConfigureContainer().UseResultHandler(viewmodel.ResultHandler(func(ctx iris.Context) []string {
+//      if u := ctx.User(); u != nil {
+//          roles, _ := u.GetRoles()
+//          return roles
+//      }
+//      return nil
+//  }))
+func ResultHandler(roles func(ctx *context.Context) []string) func(next hero.ResultHandler) hero.ResultHandler {
+	return func(next hero.ResultHandler) hero.ResultHandler {
+		return func(ctx *context.Context, v interface{}) error {
+			if !isViewModelValue(v) {
+				return next(ctx, v)
+			}
+
+			var r []string
+			if roles != nil {
+				r = roles(ctx)
+			}
+
+			return next(ctx, Map(v, r...))
+		}
+	}
+}
+
+func isViewModelValue(v interface{}) bool {
+	typ := reflect.TypeOf(v)
+	for typ != nil {
+		switch typ.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+			typ = typ.Elem()
+		case reflect.Struct:
+			return typ != timeType && schemaOf(typ).isViewModel()
+		default:
+			return false
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,87 @@
+package viewmodel_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/httptest"
+	"github.com/kataras/iris/v12/viewmodel"
+)
+
+type audit struct {
+	CreatedAt time.Time `view:"created_at"`
+	internal  string
+}
+
+type user struct {
+	audit
+	ID       int64    `view:"id"`
+	Email    string   `view:"email,roles=admin|owner"`
+	Bio      string   `view:"bio,omitempty"`
+	Password string   `view:"-"`
+	Friends  []friend `view:"friends,omitempty"`
+}
+
+type friend struct {
+	Name  string `json:"name"`
+	Phone string `json:"phone"`
+}
+
+type plain struct {
+	Name string `json:"name"`
+}
+
+func TestMap(t *testing.T) {
+	viewmodel.Define(friend{}).Hide("Phone")
+
+	u := user{
+		audit:    audit{CreatedAt: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), internal: "x"},
+		ID:       1,
+		Email:    "kataras2006@hotmail.com",
+		Password: "secret",
+		Friends:  []friend{{Name: "makis", Phone: "123"}},
+	}
+
+	obj := viewmodel.Map(&u).(viewmodel.Object)
+	if got := len(obj); got != 3 {
+		t.Fatalf("expected 3 visible fields but got %d: %#+v", got, obj)
+	}
+	if obj.Get("email") != nil || obj.Get("Password") != nil || obj.Get("bio") != nil {
+		t.Fatalf("expected email, password and bio to be hidden: %#+v", obj)
+	}
+
+	b, err := viewmodel.Map(u, "owner").(viewmodel.Object).MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"created_at":"2020-01-01T00:00:00Z","id":1,"email":"kataras2006@hotmail.com","friends":[{"name":"makis"}]}`
+	if got := string(b); got != expected {
+		t.Fatalf("expected:\n%s\nbut got:\n%s", expected, got)
+	}
+}
+
+func TestResultHandler(t *testing.T) {
+	app := iris.New()
+	app.ConfigureContainer().UseResultHandler(viewmodel.ResultHandler(func(ctx iris.Context) []string {
+		return strings.Split(ctx.URLParam("roles"), ",")
+	}))
+	app.ConfigureContainer(func(api *iris.APIContainer) {
+		api.Get("/users", func() []user {
+			return []user{{ID: 1, Email: "a@b.c", Password: "secret"}}
+		})
+		api.Get("/plain", func() plain {
+			return plain{Name: "kataras"}
+		})
+	})
+
+	e := httptest.New(t, app)
+	e.GET("/users").Expect().Status(httptest.StatusOK).
+		JSON().Equal([]interface{}{map[string]interface{}{"created_at": "0001-01-01T00:00:00Z", "id": 1}})
+	e.GET("/users").WithQuery("roles", "user,admin").Expect().Status(httptest.StatusOK).
+		JSON().Equal([]interface{}{map[string]interface{}{"created_at": "0001-01-01T00:00:00Z", "id": 1, "email": "a@b.c"}})
+	e.GET("/plain").Expect().Status(httptest.StatusOK).
+		JSON().Equal(plain{Name: "kataras"})
+}
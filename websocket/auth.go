@@ -0,0 +1,90 @@
+package websocket
+
+import (
+	"net/http"
+
+	"github.com/kataras/iris/v12/context"
+
+	"github.com/kataras/neffos"
+)
+
+// SecureOptions holds the settings of the `SecureHandler`.
+type SecureOptions struct {
+	// Middleware are the handlers which authenticate the request before the handshake,
+	// e.g. a JWT verifier, a basic authentication or a sessions check.
+	// The request is not upgraded if one of them does not call the ctx.Next,
+	// it's rejected with the status code set by the middleware
+	// or 401 Unauthorized if it did not set a failure status code.
+	Middleware context.Handlers
+	// Identity returns the authenticated identity of the request which is injected
+	// into the connection, see the `GetIdentity` and `GetUser` functions.
+	// Defaults to the request's user, see `Context.SetUser`.
+	Identity func(ctx *context.Context) interface{}
+	// IDGenerator generates the connection's ID.
+	// Defaults to the `DefaultIDGenerator`.
+	IDGenerator IDGenerator
+}
+
+// SecureHandler is like the `Handler` but it runs the "opts.Middleware"
+// before the websocket handshake, so the unauthenticated requests are rejected
+// with a proper HTTP status code instead of upgraded,
+// and it injects the authenticated identity into the connection.
+//
+// Usage:
+//  app.Get("/ws", websocket.SecureHandler(server, websocket.SecureOptions{
+//      Middleware: []iris.Handler{verifier.Verify(func() interface{} { return new(UserClaims) })},
+//  }))
+//
+//  server.OnConnect = func(c *websocket.Conn) error {
+//      claims := websocket.GetIdentity(c).(*UserClaims)
+//      [...]
+//  }
+func SecureHandler(s *neffos.Server, opts SecureOptions) context.Handler {
+	idGen := opts.IDGenerator
+	if idGen == nil {
+		idGen = DefaultIDGenerator
+	}
+
+	return func(ctx *context.Context) {
+		if ctx.IsStopped() {
+			return
+		}
+
+		for _, h := range opts.Middleware {
+			if !ctx.Proceed(h) {
+				if !context.StatusCodeNotSuccessful(ctx.GetStatusCode()) {
+					ctx.StatusCode(http.StatusUnauthorized)
+				}
+
+				ctx.StopExecution()
+				return
+			}
+		}
+
+		var identity interface{}
+		if opts.Identity != nil {
+			identity = opts.Identity(ctx)
+		} else {
+			identity = ctx.User()
+		}
+
+		upgrade(ctx, idGen, s, identity)
+	}
+}
+
+// GetIdentity returns the authenticated identity of a server-side websocket connection,
+// see the `SecureHandler` and `Upgrade`.
+func GetIdentity(c *neffos.Conn) interface{} {
+	if sw, ok := c.Socket().(*socketWrapper); ok {
+		return sw.identity
+	}
+
+	return nil
+}
+
+// GetUser returns the authenticated user of a server-side websocket connection,
+// if its identity is a `context.User`, i.e. the request's user (see `Context.SetUser`).
+func GetUser(c *neffos.Conn) context.User {
+	u, _ := GetIdentity(c).(context.User)
+	return u
+}
@@ -0,0 +1,109 @@
+package websocket_test
+
+import (
+	stdContext "context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/middleware/basicauth"
+	"github.com/kataras/iris/v12/websocket"
+
+	gorillaws "github.com/gorilla/websocket"
+)
+
+func TestSecureHandler(t *testing.T) {
+	server := websocket.New(websocket.DefaultGorillaUpgrader, websocket.Events{
+		"whoami": func(nsConn *websocket.NSConn, msg websocket.Message) error {
+			username, err := websocket.GetUser(nsConn.Conn).GetUsername()
+			if err != nil {
+				return err
+			}
+
+			nsConn.Emit("whoami", []byte(username))
+			return nil
+		},
+	})
+
+	connected := make(chan bool, 1)
+	server.OnConnect = func(c *websocket.Conn) error {
+		connected <- websocket.GetUser(c) != nil
+		return nil
+	}
+
+	app := iris.New()
+	app.Get("/ws", websocket.SecureHandler(server, websocket.SecureOptions{
+		Middleware: context.Handlers{basicauth.Default(map[string]string{"admin": "admin"})},
+	}))
+	app.Get("/ws/silent", websocket.SecureHandler(server, websocket.SecureOptions{
+		Middleware: context.Handlers{func(ctx iris.Context) {}},
+	}))
+	if err := app.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewServer(app)
+	defer srv.Close()
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + "/ws"
+
+	ctx, cancel := stdContext.WithTimeout(stdContext.Background(), 5*time.Second)
+	defer cancel()
+
+	for _, tt := range []struct {
+		url    string
+		header http.Header
+	}{
+		{url, nil},
+		{url, http.Header{"Authorization": {"Basic d3Jvbmc6d3Jvbmc="}}}, // wrong:wrong
+		{url + "/silent", nil},
+	} {
+		_, resp, err := gorillaws.DefaultDialer.DialContext(ctx, tt.url, tt.header)
+		if err == nil {
+			t.Fatalf("%s: expected the handshake to be rejected", tt.url)
+		}
+		if resp == nil || resp.StatusCode != http.StatusUnauthorized {
+			t.Fatalf("%s: expected a 401 Unauthorized response but got: %v", tt.url, resp)
+		}
+	}
+
+	select {
+	case <-connected:
+		t.Fatal("expected the rejected requests to not be upgraded")
+	default:
+	}
+
+	received := make(chan string, 1)
+	client, err := websocket.Dial(ctx, websocket.GorillaDialer(&gorillaws.Dialer{}, http.Header{"Authorization": {"Basic YWRtaW46YWRtaW4="}}), url, websocket.Events{
+		"whoami": func(_ *websocket.NSConn, msg websocket.Message) error {
+			received <- string(msg.Body)
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	if ok := <-connected; !ok {
+		t.Fatal("expected the user to be available on connect")
+	}
+
+	nsConn, err := client.Connect(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nsConn.Emit("whoami", nil)
+	select {
+	case username := <-received:
+		if username != "admin" {
+			t.Fatalf("expected the admin user but got: %s", username)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for the identity")
+	}
+}
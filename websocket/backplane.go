@@ -0,0 +1,113 @@
+package websocket
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/kataras/neffos"
+)
+
+// Backplane distributes the broadcasts of the `Rooms` to the rest of the instances
+// of the application, e.g. behind a load balancer, so they reach the connections
+// held by the other instances too, see `Rooms.UseBackplane`.
+//
+// See `NewRedisBackplane` for a Redis pub/sub implementation.
+type Backplane interface {
+	// Publish sends the "payload" to all the instances.
+	Publish(payload []byte) error
+	// Subscribe calls the "receive" on each payload published by any instance,
+	// the payloads of this instance are ignored by the caller.
+	Subscribe(receive func(payload []byte)) error
+	// Close stops the subscription.
+	Close() error
+}
+
+// backplaneMessage is the payload of a `Backplane`.
+type backplaneMessage struct {
+	// Origin is the ID of the instance which published it.
+	Origin string `json:"origin"`
+	// Room is the room of the message, empty for a server-wide broadcast.
+	Room string `json:"room,omitempty"`
+	// Except is the ID of the connection to exclude.
+	Except string `json:"except,omitempty"`
+
+	Namespace string `json:"namespace,omitempty"`
+	NSRoom    string `json:"nsRoom,omitempty"`
+	Event     string `json:"event,omitempty"`
+	Body      []byte `json:"body,omitempty"`
+	To        string `json:"to,omitempty"`
+	IsNative  bool   `json:"isNative,omitempty"`
+	SetBinary bool   `json:"binary,omitempty"`
+}
+
+func (m backplaneMessage) message() neffos.Message {
+	return neffos.Message{
+		Namespace: m.Namespace,
+		Room:      m.NSRoom,
+		Event:     m.Event,
+		Body:      m.Body,
+		To:        m.To,
+		IsNative:  m.IsNative,
+		SetBinary: m.SetBinary,
+	}
+}
+
+// UseBackplane distributes the `Broadcast` and `BroadcastAll` calls
+// to the rest of the instances through the "backplane"
+// and delivers theirs to the connections of this instance.
+// Should be called once, before serve-time.
+//
+// Usage:
+//  client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:6379"})
+//  err := websocket.NewRooms(server).UseBackplane(websocket.NewRedisBackplane(client, ""))
+func (r *Rooms) UseBackplane(backplane Backplane) error {
+	r.origin = uuid.New().String()
+	r.backplane = backplane
+
+	return backplane.Subscribe(r.receive)
+}
+
+// publish sends the "msg" of the "room" to the rest of the instances, if a backplane is used.
+func (r *Rooms) publish(room string, msg neffos.Message, except string) error {
+	if r.backplane == nil {
+		return nil
+	}
+
+	payload, err := json.Marshal(backplaneMessage{
+		Origin:    r.origin,
+		Room:      room,
+		Except:    except,
+		Namespace: msg.Namespace,
+		NSRoom:    msg.Room,
+		Event:     msg.Event,
+		Body:      msg.Body,
+		To:        msg.To,
+		IsNative:  msg.IsNative,
+		SetBinary: msg.SetBinary,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = r.backplane.Publish(payload); err != nil {
+		return fmt.Errorf("websocket: backplane: publish: %w", err)
+	}
+
+	return nil
+}
+
+// receive delivers the messages of the rest of the instances to the connections of this one.
+func (r *Rooms) receive(payload []byte) {
+	var m backplaneMessage
+	if err := json.Unmarshal(payload, &m); err != nil || m.Origin == r.origin {
+		return
+	}
+
+	if m.Room == "" {
+		r.server.Broadcast(nil, m.message())
+		return
+	}
+
+	r.broadcast(m.Room, m.message(), m.Except)
+}
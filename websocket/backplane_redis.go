@@ -0,0 +1,69 @@
+package websocket
+
+import (
+	"context"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// DefaultRedisBackplaneChannel is the default Redis channel of the `RedisBackplane`.
+const DefaultRedisBackplaneChannel = "iris:websocket"
+
+// RedisBackplane is a `Backplane` backed by the Redis pub/sub.
+type RedisBackplane struct {
+	client  redis.UniversalClient
+	channel string
+	pubsub  *redis.PubSub
+}
+
+var _ Backplane = (*RedisBackplane)(nil)
+
+// NewRedisBackplane returns a new Redis `Backplane` which publishes to and subscribes on the "channel",
+// defaults to the `DefaultRedisBackplaneChannel`.
+// The "client" can be a go-redis Client, a Cluster Client or a Failover Client,
+// it's not closed by the backplane.
+//
+// All the instances should use the same Redis server and channel.
+func NewRedisBackplane(client redis.UniversalClient, channel string) *RedisBackplane {
+	if channel == "" {
+		channel = DefaultRedisBackplaneChannel
+	}
+
+	return &RedisBackplane{
+		client:  client,
+		channel: channel,
+	}
+}
+
+// Publish sends the "payload" to the channel.
+func (b *RedisBackplane) Publish(payload []byte) error {
+	return b.client.Publish(context.Background(), b.channel, payload).Err()
+}
+
+// Subscribe subscribes on the channel and calls the "receive" on each payload, on its own goroutine.
+// It waits for the subscription to be confirmed by the Redis server.
+func (b *RedisBackplane) Subscribe(receive func(payload []byte)) error {
+	pubsub := b.client.Subscribe(context.Background(), b.channel)
+	if _, err := pubsub.Receive(context.Background()); err != nil {
+		pubsub.Close()
+		return err
+	}
+
+	b.pubsub = pubsub
+	go func() {
+		for msg := range pubsub.Channel() {
+			receive([]byte(msg.Payload))
+		}
+	}()
+
+	return nil
+}
+
+// Close stops the subscription.
+func (b *RedisBackplane) Close() error {
+	if b.pubsub == nil {
+		return nil
+	}
+
+	return b.pubsub.Close()
+}
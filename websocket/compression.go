@@ -0,0 +1,125 @@
+package websocket
+
+import (
+	"compress/flate"
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/kataras/neffos"
+	"github.com/kataras/neffos/gorilla"
+
+	gorillaws "github.com/gorilla/websocket"
+)
+
+// CompressionOptions holds the permessage-deflate (RFC 7692) compression settings
+// of the `GorillaCompressionUpgrader` and `GorillaCompressionDialer`.
+type CompressionOptions struct {
+	// Level is the flate compression level of the messages,
+	// from flate.HuffmanOnly (-2) to flate.BestCompression (9).
+	// Defaults to flate.BestSpeed (1).
+	Level int
+	// Threshold is the minimum size, in bytes, of a message to be compressed,
+	// the smaller messages are sent uncompressed as the compression
+	// overhead outweighs the bandwidth it saves.
+	// Defaults to 0, all messages are compressed.
+	Threshold int
+}
+
+// DefaultCompressionOptions are the recommended compression settings,
+// fast compression of the messages of 512 bytes or larger, e.g. JSON-heavy event streams.
+var DefaultCompressionOptions = CompressionOptions{
+	Level:     flate.BestSpeed,
+	Threshold: 512,
+}
+
+// GorillaCompressionUpgrader is like the `GorillaUpgrader` but it negotiates
+// the permessage-deflate extension with the clients which support it
+// and compresses the messages based on the "opts".
+// The clients which do not support it are served uncompressed.
+//
+// Usage:
+//  upgrader := websocket.GorillaCompressionUpgrader(gorilla.Upgrader{}, websocket.DefaultCompressionOptions)
+//  server := websocket.New(upgrader, events)
+func GorillaCompressionUpgrader(upgrader gorillaws.Upgrader, opts CompressionOptions) neffos.Upgrader {
+	upgrader.EnableCompression = true
+	upgrade := gorilla.Upgrader(upgrader)
+
+	return func(w http.ResponseWriter, r *http.Request) (neffos.Socket, error) {
+		socket, err := upgrade(w, r)
+		if err != nil {
+			return nil, err
+		}
+
+		return newCompressedSocket(socket, opts)
+	}
+}
+
+// GorillaCompressionDialer is like the `GorillaDialer` but it negotiates
+// the permessage-deflate extension with the server
+// and compresses the messages based on the "opts".
+func GorillaCompressionDialer(dialer *gorillaws.Dialer, requestHeader http.Header, opts CompressionOptions) neffos.Dialer {
+	d := *dialer
+	d.EnableCompression = true
+	dial := gorilla.Dialer(&d, requestHeader)
+
+	return func(ctx context.Context, url string) (neffos.Socket, error) {
+		socket, err := dial(ctx, url)
+		if err != nil {
+			return nil, err
+		}
+
+		return newCompressedSocket(socket, opts)
+	}
+}
+
+// compressedSocket enables the write compression of the gorilla socket's messages
+// based on their size.
+type compressedSocket struct {
+	*gorilla.Socket
+	threshold int
+
+	mu sync.Mutex
+}
+
+func newCompressedSocket(socket neffos.Socket, opts CompressionOptions) (neffos.Socket, error) {
+	s, ok := socket.(*gorilla.Socket)
+	if !ok {
+		return socket, nil
+	}
+
+	level := opts.Level
+	if level == 0 {
+		level = flate.BestSpeed
+	}
+
+	if err := s.UnderlyingConn.SetCompressionLevel(level); err != nil {
+		s.UnderlyingConn.Close()
+		return nil, err
+	}
+
+	return &compressedSocket{Socket: s, threshold: opts.Threshold}, nil
+}
+
+// WriteBinary sends a binary message to the remote connection,
+// compressed if its size reaches the threshold and the extension was negotiated.
+func (s *compressedSocket) WriteBinary(body []byte, timeout time.Duration) error {
+	s.mu.Lock()
+	s.UnderlyingConn.EnableWriteCompression(len(body) >= s.threshold)
+	err := s.Socket.WriteBinary(body, timeout)
+	s.mu.Unlock()
+
+	return err
+}
+
+// WriteText sends a text message to the remote connection,
+// compressed if its size reaches the threshold and the extension was negotiated.
+func (s *compressedSocket) WriteText(body []byte, timeout time.Duration) error {
+	s.mu.Lock()
+	s.UnderlyingConn.EnableWriteCompression(len(body) >= s.threshold)
+	err := s.Socket.WriteText(body, timeout)
+	s.mu.Unlock()
+
+	return err
+}
@@ -0,0 +1,110 @@
+package websocket_test
+
+import (
+	stdContext "context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/websocket"
+
+	gorillaws "github.com/gorilla/websocket"
+)
+
+type countingConn struct {
+	net.Conn
+	read *int64
+}
+
+func (c countingConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	atomic.AddInt64(c.read, int64(n))
+	return n, err
+}
+
+func TestCompression(t *testing.T) {
+	server := websocket.New(websocket.GorillaCompressionUpgrader(gorillaws.Upgrader{}, websocket.DefaultCompressionOptions), websocket.Events{
+		"echo": func(nsConn *websocket.NSConn, msg websocket.Message) error {
+			nsConn.Emit("echo", msg.Body)
+			return nil
+		},
+	})
+
+	app := iris.New()
+	app.Get("/ws", websocket.Handler(server))
+	if err := app.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewServer(app)
+	defer srv.Close()
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + "/ws"
+
+	ctx, cancel := stdContext.WithTimeout(stdContext.Background(), 5*time.Second)
+	defer cancel()
+
+	// The extension is not negotiated with the clients which do not support it.
+	for _, enable := range []bool{true, false} {
+		conn, resp, err := (&gorillaws.Dialer{EnableCompression: enable}).DialContext(ctx, url, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		conn.Close()
+
+		if got := strings.Contains(resp.Header.Get("Sec-Websocket-Extensions"), "permessage-deflate"); got != enable {
+			t.Fatalf("expected the permessage-deflate negotiation to be: %v", enable)
+		}
+	}
+
+	var read int64
+	dialer := &gorillaws.Dialer{
+		NetDialContext: func(ctx stdContext.Context, network, addr string) (net.Conn, error) {
+			conn, err := new(net.Dialer).DialContext(ctx, network, addr)
+			if err != nil {
+				return nil, err
+			}
+
+			return countingConn{Conn: conn, read: &read}, nil
+		},
+	}
+
+	received := make(chan string, 2)
+	client, err := websocket.Dial(ctx, websocket.GorillaCompressionDialer(dialer, make(http.Header), websocket.DefaultCompressionOptions), url, websocket.Events{
+		"echo": func(_ *websocket.NSConn, msg websocket.Message) error {
+			received <- string(msg.Body)
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	nsConn, err := client.Connect(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, body := range []string{"small", strings.Repeat(`{"event":"tick","value":1}`, 400)} {
+		before := atomic.LoadInt64(&read)
+		nsConn.Emit("echo", []byte(body))
+
+		select {
+		case got := <-received:
+			if got != body {
+				t.Fatalf("expected the echo of %d bytes but got %d bytes", len(body), len(got))
+			}
+		case <-time.After(3 * time.Second):
+			t.Fatal("timed out waiting for the echo")
+		}
+
+		if n := atomic.LoadInt64(&read) - before; len(body) > websocket.DefaultCompressionOptions.Threshold && n >= int64(len(body))/4 {
+			t.Fatalf("expected the message of %d bytes to be compressed but %d bytes were read", len(body), n)
+		}
+	}
+}
@@ -0,0 +1,323 @@
+package websocket
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/kataras/neffos"
+)
+
+// Rooms is the server-side registry of the rooms of a websocket server's connections,
+// so the chat and collaboration applications do not have to maintain their own.
+// Unlike the `NSConn.JoinRoom` the rooms are not negotiated with the client,
+// a connection joins and leaves a room immediately and it leaves all its rooms on disconnect.
+//
+// The registry of a server is created through the `NewRooms` before serve-time,
+// the event handlers retrieve it through the `GetRooms`.
+//
+// Usage:
+//  server := websocket.New(websocket.DefaultGorillaUpgrader, websocket.Events{
+//      "join": func(nsConn *websocket.NSConn, msg websocket.Message) error {
+//          websocket.Join(nsConn.Conn, string(msg.Body))
+//          return nil
+//      },
+//      "chat": func(nsConn *websocket.NSConn, msg websocket.Message) error {
+//          websocket.GetRooms(nsConn.Conn.Server()).Broadcast("general", msg, nsConn)
+//          return nil
+//      },
+//  })
+//  websocket.NewRooms(server)
+type Rooms struct {
+	server *neffos.Server
+
+	backplane Backplane
+	origin    string // the ID of this instance on the backplane.
+
+	mu    sync.RWMutex
+	rooms map[string]map[string]*neffos.Conn // room: connection ID: connection.
+	conns map[string]map[string]struct{}     // connection ID: rooms.
+}
+
+var (
+	roomsMu sync.Mutex
+	rooms   = make(map[*neffos.Server]*Rooms)
+)
+
+// NewRooms creates and returns the rooms registry of the websocket "server",
+// it returns the existing one if it's already created.
+//
+// The registry wraps the server's `OnDisconnect` event to remove the connection
+// from its rooms, so it should be called once, after the server's `OnDisconnect` is set
+// and before serve-time. See `GetRooms` and `Rooms.Close` too.
+func NewRooms(server *neffos.Server) *Rooms {
+	roomsMu.Lock()
+	defer roomsMu.Unlock()
+
+	if r, ok := rooms[server]; ok {
+		return r
+	}
+
+	r := &Rooms{
+		server: server,
+		rooms:  make(map[string]map[string]*neffos.Conn),
+		conns:  make(map[string]map[string]struct{}),
+	}
+
+	onDisconnect := server.OnDisconnect
+	server.OnDisconnect = func(c *neffos.Conn) {
+		r.LeaveAll(c)
+		if onDisconnect != nil {
+			onDisconnect(c)
+		}
+	}
+
+	rooms[server] = r
+	return r
+}
+
+// GetRooms returns the rooms registry of the websocket "server",
+// e.g. from an event handler.
+//
+// It panics if the registry was not created through the `NewRooms` before serve-time.
+func GetRooms(server *neffos.Server) *Rooms {
+	roomsMu.Lock()
+	r, ok := rooms[server]
+	roomsMu.Unlock()
+
+	if !ok {
+		panic("websocket: GetRooms: the rooms of the server are not created, call the NewRooms before serve-time")
+	}
+
+	return r
+}
+
+// Close removes the registry of its server, so it can be garbage collected,
+// and closes its backplane, if any. Should be called once, after the server is closed.
+func (r *Rooms) Close() error {
+	roomsMu.Lock()
+	if rooms[r.server] == r {
+		delete(rooms, r.server)
+	}
+	roomsMu.Unlock()
+
+	r.mu.Lock()
+	r.rooms = make(map[string]map[string]*neffos.Conn)
+	r.conns = make(map[string]map[string]struct{})
+	r.mu.Unlock()
+
+	if r.backplane != nil {
+		return r.backplane.Close()
+	}
+
+	return nil
+}
+
+// Join adds the server-side connection "c" to the "room" of its server,
+// see `Rooms.Join`.
+func Join(c *neffos.Conn, room string) {
+	GetRooms(c.Server()).Join(c, room)
+}
+
+// Leave removes the server-side connection "c" from the "room" of its server,
+// see `Rooms.Leave`.
+func Leave(c *neffos.Conn, room string) bool {
+	return GetRooms(c.Server()).Leave(c, room)
+}
+
+// Join adds the connection "c" to the "room".
+func (r *Rooms) Join(c *neffos.Conn, room string) {
+	if c.IsClosed() {
+		return
+	}
+
+	id := c.ID()
+
+	r.mu.Lock()
+	members, ok := r.rooms[room]
+	if !ok {
+		members = make(map[string]*neffos.Conn)
+		r.rooms[room] = members
+	}
+	members[id] = c
+
+	connRooms, ok := r.conns[id]
+	if !ok {
+		connRooms = make(map[string]struct{})
+		r.conns[id] = connRooms
+	}
+	connRooms[room] = struct{}{}
+	r.mu.Unlock()
+}
+
+// Leave removes the connection "c" from the "room"
+// and reports whether it was a member of it.
+func (r *Rooms) Leave(c *neffos.Conn, room string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.leave(c.ID(), room)
+}
+
+func (r *Rooms) leave(id, room string) bool {
+	members, ok := r.rooms[room]
+	if !ok {
+		return false
+	}
+
+	if _, ok = members[id]; !ok {
+		return false
+	}
+
+	delete(members, id)
+	if len(members) == 0 {
+		delete(r.rooms, room)
+	}
+
+	if connRooms, ok := r.conns[id]; ok {
+		delete(connRooms, room)
+		if len(connRooms) == 0 {
+			delete(r.conns, id)
+		}
+	}
+
+	return true
+}
+
+// LeaveAll removes the connection "c" from all of its rooms,
+// it's called automatically on disconnect.
+func (r *Rooms) LeaveAll(c *neffos.Conn) {
+	r.mu.Lock()
+	r.leaveAll(c.ID())
+	r.mu.Unlock()
+}
+
+func (r *Rooms) leaveAll(id string) {
+	for room := range r.conns[id] {
+		r.leave(id, room)
+	}
+}
+
+// Rooms returns the names of the rooms with at least one member, sorted.
+func (r *Rooms) Rooms() []string {
+	r.mu.RLock()
+	names := make([]string, 0, len(r.rooms))
+	for room := range r.rooms {
+		names = append(names, room)
+	}
+	r.mu.RUnlock()
+
+	sort.Strings(names)
+	return names
+}
+
+// ConnRooms returns the names of the rooms of the connection "c", sorted.
+func (r *Rooms) ConnRooms(c *neffos.Conn) []string {
+	r.mu.RLock()
+	connRooms := r.conns[c.ID()]
+	names := make([]string, 0, len(connRooms))
+	for room := range connRooms {
+		names = append(names, room)
+	}
+	r.mu.RUnlock()
+
+	sort.Strings(names)
+	return names
+}
+
+// Members returns the connections of the "room", sorted by their IDs.
+func (r *Rooms) Members(room string) []*neffos.Conn {
+	r.mu.RLock()
+	members := make([]*neffos.Conn, 0, len(r.rooms[room]))
+	for _, c := range r.rooms[room] {
+		members = append(members, c)
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].ID() < members[j].ID()
+	})
+
+	return members
+}
+
+// Len returns the number of the members of the "room".
+func (r *Rooms) Len(room string) int {
+	r.mu.RLock()
+	n := len(r.rooms[room])
+	r.mu.RUnlock()
+	return n
+}
+
+// Broadcast sends the "msg" to the members of the "room",
+// except the "exceptSender" connection, if not nil (a `Conn`, an `NSConn` or a connection ID).
+// The "msg.Namespace" should be filled with a namespace the members are connected to.
+// The closed connections are removed from their rooms.
+//
+// The "msg" is sent to the members of the rest of the instances too,
+// if a backplane is used, see `UseBackplane`.
+// It returns the backplane's publish error, if any.
+func (r *Rooms) Broadcast(room string, msg neffos.Message, exceptSender ...fmt.Stringer) error {
+	except := exceptID(exceptSender)
+	r.broadcast(room, msg, except)
+	return r.publish(room, msg, except)
+}
+
+// BroadcastAll sends the "msg" to all the connections of the server,
+// except the "exceptSender" connection, if not nil, like the `Server.Broadcast` does.
+//
+// The "msg" is sent to the connections of the rest of the instances too,
+// if a backplane is used and the server does not use a `StackExchange` (which distributes it already).
+// It returns the backplane's publish error, if any.
+func (r *Rooms) BroadcastAll(msg neffos.Message, exceptSender ...fmt.Stringer) error {
+	var except fmt.Stringer
+	if len(exceptSender) > 0 {
+		except = exceptSender[0]
+	}
+
+	r.server.Broadcast(except, msg)
+	if r.server.StackExchange != nil {
+		return nil
+	}
+
+	return r.publish("", msg, exceptID(exceptSender))
+}
+
+func exceptID(exceptSender []fmt.Stringer) string {
+	if len(exceptSender) == 0 || exceptSender[0] == nil {
+		return ""
+	}
+
+	switch c := exceptSender[0].(type) {
+	case *neffos.Conn:
+		return c.ID()
+	case *neffos.NSConn:
+		return c.Conn.ID()
+	default:
+		return c.String()
+	}
+}
+
+func (r *Rooms) broadcast(room string, msg neffos.Message, except string) {
+	var closed []string
+	for _, c := range r.Members(room) {
+		if c.IsClosed() {
+			closed = append(closed, c.ID())
+			continue
+		}
+
+		if c.ID() == except {
+			continue
+		}
+
+		c.Write(msg)
+	}
+
+	if len(closed) > 0 {
+		r.mu.Lock()
+		for _, id := range closed {
+			r.leaveAll(id)
+		}
+		r.mu.Unlock()
+	}
+}
@@ -0,0 +1,269 @@
+package websocket_test
+
+import (
+	stdContext "context"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/websocket"
+)
+
+func TestRooms(t *testing.T) {
+	server := websocket.New(websocket.DefaultGorillaUpgrader, websocket.Namespaces{
+		"default": websocket.Events{
+			"join": func(nsConn *websocket.NSConn, msg websocket.Message) error {
+				websocket.Join(nsConn.Conn, string(msg.Body))
+				return nil
+			},
+			"chat": func(nsConn *websocket.NSConn, msg websocket.Message) error {
+				websocket.GetRooms(nsConn.Conn.Server()).Broadcast("general", msg, nsConn)
+				return nil
+			},
+		},
+	})
+	rooms := websocket.NewRooms(server)
+	defer rooms.Close()
+
+	app := iris.New()
+	app.Get("/ws", websocket.Handler(server))
+	if err := app.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewServer(app)
+	defer srv.Close()
+
+	ctx, cancel := stdContext.WithTimeout(stdContext.Background(), 5*time.Second)
+	defer cancel()
+
+	dial := func(room string) (*websocket.NSConn, chan string) {
+		t.Helper()
+
+		received := make(chan string, 10)
+		client, err := websocket.Dial(ctx, websocket.DefaultGorillaDialer, "ws"+strings.TrimPrefix(srv.URL, "http")+"/ws", websocket.Namespaces{
+			"default": websocket.Events{
+				"chat": func(_ *websocket.NSConn, msg websocket.Message) error {
+					received <- string(msg.Body)
+					return nil
+				},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		nsConn, err := client.Connect(ctx, "default")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if room != "" {
+			nsConn.Emit("join", []byte(room))
+		}
+
+		return nsConn, received
+	}
+
+	waitFor := func(desc string, cond func() bool) {
+		t.Helper()
+		for i := 0; i < 100; i++ {
+			if cond() {
+				return
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+		t.Fatalf("timed out waiting for %s", desc)
+	}
+
+	alice, aliceReceived := dial("general")
+	bob, bobReceived := dial("general")
+	_, carolReceived := dial("random")
+
+	waitFor("the members to join", func() bool { return rooms.Len("general") == 2 && rooms.Len("random") == 1 })
+
+	if got := rooms.Rooms(); len(got) != 2 || got[0] != "general" || got[1] != "random" {
+		t.Fatalf("expected the general and random rooms but got: %v", got)
+	}
+
+	alice.Emit("chat", []byte("hello"))
+	select {
+	case msg := <-bobReceived:
+		if msg != "hello" {
+			t.Fatalf("expected hello but got: %s", msg)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for the broadcast")
+	}
+
+	select {
+	case msg := <-aliceReceived:
+		t.Fatalf("expected the sender to be excluded but it received: %s", msg)
+	case msg := <-carolReceived:
+		t.Fatalf("expected the other rooms to be excluded but received: %s", msg)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	bob.Conn.Close()
+	waitFor("the member to leave on disconnect", func() bool { return rooms.Len("general") == 1 })
+
+	if members := rooms.Members("general"); len(members) != 1 || len(rooms.ConnRooms(members[0])) != 1 {
+		t.Fatalf("expected one member of one room but got: %v", members)
+	}
+}
+
+type memoryBackplane struct {
+	mu        sync.Mutex
+	receivers []func([]byte)
+}
+
+func (b *memoryBackplane) Publish(payload []byte) error {
+	b.mu.Lock()
+	receivers := b.receivers
+	b.mu.Unlock()
+
+	for _, receive := range receivers {
+		go receive(payload)
+	}
+
+	return nil
+}
+
+func (b *memoryBackplane) Subscribe(receive func([]byte)) error {
+	b.mu.Lock()
+	b.receivers = append(b.receivers, receive)
+	b.mu.Unlock()
+	return nil
+}
+
+func (b *memoryBackplane) Close() error { return nil }
+
+func TestRoomsBackplane(t *testing.T) {
+	backplane := new(memoryBackplane)
+
+	newInstance := func() (*websocket.Rooms, string) {
+		t.Helper()
+
+		server := websocket.New(websocket.DefaultGorillaUpgrader, websocket.Namespaces{
+			"default": websocket.Events{
+				"join": func(nsConn *websocket.NSConn, msg websocket.Message) error {
+					websocket.Join(nsConn.Conn, string(msg.Body))
+					return nil
+				},
+				"chat": func(nsConn *websocket.NSConn, msg websocket.Message) error {
+					return websocket.GetRooms(nsConn.Conn.Server()).Broadcast("general", msg, nsConn)
+				},
+			},
+		})
+		rooms := websocket.NewRooms(server)
+		t.Cleanup(func() { rooms.Close() })
+		if err := rooms.UseBackplane(backplane); err != nil {
+			t.Fatal(err)
+		}
+
+		app := iris.New()
+		app.Get("/ws", websocket.Handler(server))
+		if err := app.Build(); err != nil {
+			t.Fatal(err)
+		}
+
+		srv := httptest.NewServer(app)
+		t.Cleanup(srv.Close)
+
+		return rooms, "ws" + strings.TrimPrefix(srv.URL, "http") + "/ws"
+	}
+
+	ctx, cancel := stdContext.WithTimeout(stdContext.Background(), 5*time.Second)
+	defer cancel()
+
+	dial := func(url, room string) (*websocket.NSConn, chan string) {
+		t.Helper()
+
+		received := make(chan string, 10)
+		client, err := websocket.Dial(ctx, websocket.DefaultGorillaDialer, url, websocket.Namespaces{
+			"default": websocket.Events{
+				"chat": func(_ *websocket.NSConn, msg websocket.Message) error {
+					received <- string(msg.Body)
+					return nil
+				},
+				"notice": func(_ *websocket.NSConn, msg websocket.Message) error {
+					received <- string(msg.Body)
+					return nil
+				},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		nsConn, err := client.Connect(ctx, "default")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		nsConn.Emit("join", []byte(room))
+		return nsConn, received
+	}
+
+	expect := func(received chan string, expected string) {
+		t.Helper()
+
+		select {
+		case msg := <-received:
+			if msg != expected {
+				t.Fatalf("expected %s but got: %s", expected, msg)
+			}
+		case <-time.After(3 * time.Second):
+			t.Fatalf("timed out waiting for %s", expected)
+		}
+	}
+
+	roomsA, urlA := newInstance()
+	roomsB, urlB := newInstance()
+
+	alice, aliceReceived := dial(urlA, "general")
+	_, bobReceived := dial(urlB, "general")
+	_, carolReceived := dial(urlB, "random")
+
+	for i := 0; i < 100 && (roomsA.Len("general") != 1 || roomsB.Len("general") != 1 || roomsB.Len("random") != 1); i++ {
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	alice.Emit("chat", []byte("hello"))
+	expect(bobReceived, "hello")
+
+	select {
+	case msg := <-aliceReceived:
+		t.Fatalf("expected the sender to be excluded but it received: %s", msg)
+	case msg := <-carolReceived:
+		t.Fatalf("expected the other rooms to be excluded but received: %s", msg)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	if err := roomsA.BroadcastAll(websocket.Message{Namespace: "default", Event: "notice", Body: []byte("maintenance")}); err != nil {
+		t.Fatal(err)
+	}
+	expect(aliceReceived, "maintenance")
+	expect(bobReceived, "maintenance")
+	expect(carolReceived, "maintenance")
+}
+
+func TestGetRoomsWithoutNewRooms(t *testing.T) {
+	defer func() {
+		if v := recover(); v == nil {
+			t.Fatal("expected a panic of the rooms which were not created")
+		}
+	}()
+
+	server := websocket.New(websocket.DefaultGorillaUpgrader, websocket.Events{})
+	rooms := websocket.NewRooms(server)
+	if websocket.GetRooms(server) != rooms {
+		t.Fatal("expected the rooms of the server")
+	}
+
+	rooms.Close()
+	websocket.GetRooms(server)
+}
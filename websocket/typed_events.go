@@ -0,0 +1,205 @@
+package websocket
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/kataras/neffos"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Codec encodes and decodes the payloads of the `TypedEvents`.
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+	// Binary reports whether the encoded payloads should be sent as binary messages.
+	Binary() bool
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+func (jsonCodec) Binary() bool                               { return false }
+
+type msgpackCodec struct{}
+
+func (msgpackCodec) Marshal(v interface{}) ([]byte, error)      { return msgpack.Marshal(v) }
+func (msgpackCodec) Unmarshal(data []byte, v interface{}) error { return msgpack.Unmarshal(data, v) }
+func (msgpackCodec) Binary() bool                               { return true }
+
+var (
+	// JSONCodec is the JSON `Codec`, the payloads are sent as text messages.
+	JSONCodec Codec = jsonCodec{}
+	// MsgpackCodec is the MessagePack `Codec`, the payloads are sent as binary messages.
+	MsgpackCodec Codec = msgpackCodec{}
+)
+
+// DefaultErrorEvent is the default event of the `EventError`s, see `TypedEvents.ErrorEvent`.
+const DefaultErrorEvent = "error"
+
+// EventError is the payload of the error event which is sent back to the client
+// when the payload of a typed event could not be decoded or its handler returned an error.
+type EventError struct {
+	// Event is the event which failed.
+	Event string `json:"event" msgpack:"event"`
+	// Message is the error's text.
+	Message string `json:"message" msgpack:"message"`
+}
+
+// TypedEvents registers events which their payloads are decoded
+// to the handlers' input types, instead of the raw `Message` switches.
+// It completes the `ConnHandler` interface, pass it to the `New` and `Dial` functions.
+//
+// Usage:
+//  events := websocket.NewTypedEvents("orders", websocket.JSONCodec)
+//  events.OnEvent("order.created", func(c *websocket.NSConn, payload OrderCreated) error {
+//      return events.Emit(c, "order.accepted", OrderAccepted{ID: payload.ID})
+//  })
+//
+//  server := websocket.New(websocket.DefaultGorillaUpgrader, events)
+type TypedEvents struct {
+	namespace string
+	codec     Codec
+	events    neffos.Events
+
+	// ErrorEvent is the event of the `EventError`s sent back to the client,
+	// encoded with the codec. Set to empty to disable them,
+	// the error is returned to neffos instead (it sets the Message.Err).
+	// Defaults to the `DefaultErrorEvent`.
+	ErrorEvent string
+}
+
+var _ neffos.ConnHandler = (*TypedEvents)(nil)
+
+// NewTypedEvents returns a new `TypedEvents` of the "namespace",
+// its payloads are encoded and decoded with the "codec",
+// defaults to the `JSONCodec`.
+func NewTypedEvents(namespace string, codec Codec) *TypedEvents {
+	if codec == nil {
+		codec = JSONCodec
+	}
+
+	return &TypedEvents{
+		namespace:  namespace,
+		codec:      codec,
+		events:     make(neffos.Events),
+		ErrorEvent: DefaultErrorEvent,
+	}
+}
+
+// GetNamespaces completes the `ConnHandler` interface.
+func (t *TypedEvents) GetNamespaces() neffos.Namespaces {
+	return neffos.Namespaces{t.namespace: t.events}
+}
+
+// Events returns the underline events of the namespace,
+// e.g. to register the `OnNamespaceConnected` or a raw `MessageHandlerFunc`.
+func (t *TypedEvents) Events() neffos.Events {
+	return t.events
+}
+
+var (
+	connTyp   = reflect.TypeOf((*neffos.Conn)(nil))
+	nsConnTyp = reflect.TypeOf((*neffos.NSConn)(nil))
+	errTyp    = reflect.TypeOf((*error)(nil)).Elem()
+	replyTyp  = reflect.TypeOf(neffos.Reply(nil))
+)
+
+// OnEvent registers the "handler" of the "event".
+// The "handler" should be a function of
+// func(*websocket.NSConn, T) error or func(*websocket.Conn, T) error form,
+// the "T" is the type (or a pointer to the type) of the event's payload.
+//
+// It panics on an invalid handler.
+// Should be called before serve-time.
+func (t *TypedEvents) OnEvent(event string, handler interface{}) *TypedEvents {
+	fn := reflect.ValueOf(handler)
+	typ := fn.Type()
+
+	if typ.Kind() != reflect.Func || typ.NumIn() != 2 || typ.NumOut() != 1 ||
+		(typ.In(0) != connTyp && typ.In(0) != nsConnTyp) || typ.Out(0) != errTyp {
+		panic(fmt.Sprintf("websocket: OnEvent: %s: the handler should be a func(*websocket.NSConn or *websocket.Conn, T) error but got: %s", event, typ))
+	}
+
+	withConn := typ.In(0) == connTyp
+	payloadTyp := typ.In(1)
+	isPtr := payloadTyp.Kind() == reflect.Ptr
+	if isPtr {
+		payloadTyp = payloadTyp.Elem()
+	}
+
+	t.events[event] = func(c *neffos.NSConn, msg neffos.Message) error {
+		payload := reflect.New(payloadTyp)
+		if err := t.codec.Unmarshal(msg.Body, payload.Interface()); err != nil {
+			return t.fail(c, event, fmt.Errorf("invalid payload: %w", err))
+		}
+
+		if !isPtr {
+			payload = payload.Elem()
+		}
+
+		conn := reflect.ValueOf(c)
+		if withConn {
+			conn = reflect.ValueOf(c.Conn)
+		}
+
+		out := fn.Call([]reflect.Value{conn, payload})
+		if err, _ := out[0].Interface().(error); err != nil {
+			return t.fail(c, event, err)
+		}
+
+		return nil
+	}
+
+	return t
+}
+
+// fail sends the error event of the "err" back to the client.
+func (t *TypedEvents) fail(c *neffos.NSConn, event string, err error) error {
+	if t.ErrorEvent == "" {
+		return err
+	}
+
+	switch err.(type) {
+	case neffos.CloseError, *neffos.CloseError:
+		return err
+	}
+
+	if reflect.TypeOf(err) == replyTyp {
+		return err
+	}
+
+	if emitErr := t.Emit(c, t.ErrorEvent, EventError{Event: event, Message: err.Error()}); emitErr != nil {
+		return emitErr
+	}
+
+	return nil
+}
+
+// Emit encodes the "payload" with the codec and sends it to the "event" of the connection's namespace.
+func (t *TypedEvents) Emit(c *neffos.NSConn, event string, payload interface{}) error {
+	body, err := t.codec.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	var ok bool
+	if t.codec.Binary() {
+		ok = c.EmitBinary(event, body)
+	} else {
+		ok = c.Emit(event, body)
+	}
+
+	if !ok {
+		return neffos.ErrWrite
+	}
+
+	return nil
+}
+
+// Decode decodes the "msg" body, e.g. of a typed event's message on the client-side, to the "v" pointer.
+func (t *TypedEvents) Decode(msg neffos.Message, v interface{}) error {
+	return t.codec.Unmarshal(msg.Body, v)
+}
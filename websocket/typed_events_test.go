@@ -0,0 +1,113 @@
+package websocket_test
+
+import (
+	stdContext "context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/websocket"
+)
+
+type orderCreated struct {
+	ID       string `json:"id" msgpack:"id"`
+	Quantity int    `json:"quantity" msgpack:"quantity"`
+}
+
+func TestTypedEvents(t *testing.T) {
+	for name, codec := range map[string]websocket.Codec{"json": websocket.JSONCodec, "msgpack": websocket.MsgpackCodec} {
+		t.Run(name, func(t *testing.T) {
+			testTypedEvents(t, codec)
+		})
+	}
+}
+
+func testTypedEvents(t *testing.T, codec websocket.Codec) {
+	events := websocket.NewTypedEvents("orders", codec)
+	events.OnEvent("order.created", func(c *websocket.NSConn, payload orderCreated) error {
+		if payload.Quantity <= 0 {
+			return errors.New("invalid quantity")
+		}
+
+		return events.Emit(c, "order.accepted", payload)
+	})
+
+	app := iris.New()
+	app.Get("/ws", websocket.Handler(websocket.New(websocket.DefaultGorillaUpgrader, events)))
+	if err := app.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewServer(app)
+	defer srv.Close()
+
+	ctx, cancel := stdContext.WithTimeout(stdContext.Background(), 5*time.Second)
+	defer cancel()
+
+	accepted, failed := make(chan *orderCreated, 1), make(chan websocket.EventError, 2)
+	clientEvents := websocket.NewTypedEvents("orders", codec)
+	clientEvents.OnEvent("order.accepted", func(_ *websocket.Conn, payload *orderCreated) error {
+		accepted <- payload
+		return nil
+	})
+	clientEvents.OnEvent(websocket.DefaultErrorEvent, func(_ *websocket.NSConn, payload websocket.EventError) error {
+		failed <- payload
+		return nil
+	})
+
+	client, err := websocket.Dial(ctx, websocket.DefaultGorillaDialer, "ws"+strings.TrimPrefix(srv.URL, "http")+"/ws", clientEvents)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	nsConn, err := client.Connect(ctx, "orders")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = clientEvents.Emit(nsConn, "order.created", orderCreated{ID: "1", Quantity: 2}); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case payload := <-accepted:
+		if payload.ID != "1" || payload.Quantity != 2 {
+			t.Fatalf("unexpected payload: %#+v", payload)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for the typed event")
+	}
+
+	expectError := func(expected websocket.EventError) {
+		t.Helper()
+
+		select {
+		case got := <-failed:
+			if got.Event != expected.Event || !strings.HasPrefix(got.Message, expected.Message) {
+				t.Fatalf("expected error event: %#+v but got: %#+v", expected, got)
+			}
+		case <-time.After(3 * time.Second):
+			t.Fatal("timed out waiting for the error event")
+		}
+	}
+
+	clientEvents.Emit(nsConn, "order.created", orderCreated{ID: "2"})
+	expectError(websocket.EventError{Event: "order.created", Message: "invalid quantity"})
+
+	nsConn.Emit("order.created", []byte("{"))
+	expectError(websocket.EventError{Event: "order.created", Message: "invalid payload: "})
+}
+
+func TestTypedEventsInvalidHandler(t *testing.T) {
+	defer func() {
+		if v := recover(); v == nil {
+			t.Fatal("expected a panic of the invalid handler")
+		}
+	}()
+
+	websocket.NewTypedEvents("", nil).OnEvent("event", func(payload orderCreated) {})
+}
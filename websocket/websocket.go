@@ -166,12 +166,19 @@ func Handler(s *neffos.Server, IDGenerator ...IDGenerator) context.Handler {
 }
 
 // Upgrade upgrades the request and returns a new websocket Conn.
+// The request's user (see `Context.SetUser`), if any, is kept as the connection's identity,
+// see `GetUser` and `GetIdentity`.
 // Use `Handler` for higher-level implementation instead.
 func Upgrade(ctx *context.Context, idGen IDGenerator, s *neffos.Server) *neffos.Conn {
+	return upgrade(ctx, idGen, s, ctx.User())
+}
+
+func upgrade(ctx *context.Context, idGen IDGenerator, s *neffos.Server, identity interface{}) *neffos.Conn {
 	conn, _ := s.Upgrade(ctx.ResponseWriter(), ctx.Request(), func(socket neffos.Socket) neffos.Socket {
 		return &socketWrapper{
-			Socket: socket,
-			ctx:    ctx,
+			Socket:   socket,
+			ctx:      ctx,
+			identity: identity,
 		}
 	}, wrapIDGenerator(idGen)(ctx))
 
@@ -180,7 +187,8 @@ func Upgrade(ctx *context.Context, idGen IDGenerator, s *neffos.Server) *neffos.
 
 type socketWrapper struct {
 	neffos.Socket
-	ctx *context.Context
+	ctx      *context.Context
+	identity interface{}
 }
 
 // GetContext returns the Iris Context from a websocket connection.
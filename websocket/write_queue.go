@@ -0,0 +1,280 @@
+package websocket
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kataras/neffos"
+)
+
+// SlowConsumerPolicy is the policy of a `WriteQueue`
+// when a connection's queue is full, i.e. its client does not read fast enough.
+type SlowConsumerPolicy uint8
+
+const (
+	// DropOldest drops the oldest queued message of the connection to make room for the new one.
+	DropOldest SlowConsumerPolicy = iota
+	// CloseSlowConsumer closes the connection.
+	CloseSlowConsumer
+	// BlockWithTimeout blocks the writer until there is room for the message
+	// or the `WriteQueueOptions.Timeout` passed, the message is dropped on timeout.
+	BlockWithTimeout
+)
+
+// ErrWriteQueueFull is returned from the socket's writes
+// when a message is not queued because the connection's queue is full, see `SlowConsumerPolicy`.
+var ErrWriteQueueFull = errors.New("websocket: write queue is full")
+
+// WriteQueueOptions holds the settings of a `WriteQueue`.
+type WriteQueueOptions struct {
+	// Size is the maximum number of the queued messages of a connection.
+	// Defaults to 256.
+	Size int
+	// Policy is the policy when a connection's queue is full.
+	// Defaults to DropOldest.
+	Policy SlowConsumerPolicy
+	// Timeout is the maximum time to wait for room in the queue on the BlockWithTimeout policy.
+	// Defaults to 5 seconds.
+	Timeout time.Duration
+}
+
+// WriteQueueMetrics are the metrics of a `WriteQueue`.
+type WriteQueueMetrics struct {
+	// Queued is the total number of the queued messages.
+	Queued uint64 `json:"queued"`
+	// Written is the total number of the messages written to the connections.
+	Written uint64 `json:"written"`
+	// Dropped is the total number of the messages dropped by the DropOldest and BlockWithTimeout policies.
+	Dropped uint64 `json:"dropped"`
+	// Closed is the total number of the connections closed by the CloseSlowConsumer policy.
+	Closed uint64 `json:"closed"`
+	// Pending is the number of the messages currently queued.
+	Pending int64 `json:"pending"`
+}
+
+// WriteQueue bounds the memory a slow client can consume:
+// the messages of each connection are queued, up to a size, and written on its own goroutine,
+// so a stalled client does not block the writer (e.g. a broadcast) and,
+// when its queue is full, its `SlowConsumerPolicy` applies.
+//
+// Usage:
+//  queue := websocket.NewWriteQueue(websocket.WriteQueueOptions{Size: 128, Policy: websocket.CloseSlowConsumer})
+//  server := websocket.New(queue.Upgrader(websocket.DefaultGorillaUpgrader), events)
+//  [...]
+//  metrics := queue.Metrics()
+type WriteQueue struct {
+	opts WriteQueueOptions
+
+	queued  uint64
+	written uint64
+	dropped uint64
+	closed  uint64
+	pending int64
+}
+
+// NewWriteQueue returns a new `WriteQueue`.
+func NewWriteQueue(opts WriteQueueOptions) *WriteQueue {
+	if opts.Size <= 0 {
+		opts.Size = 256
+	}
+
+	if opts.Timeout <= 0 {
+		opts.Timeout = 5 * time.Second
+	}
+
+	return &WriteQueue{opts: opts}
+}
+
+// Upgrader wraps the "upgrader" (e.g. the `DefaultGorillaUpgrader`)
+// to queue the writes of its connections.
+func (q *WriteQueue) Upgrader(upgrader neffos.Upgrader) neffos.Upgrader {
+	return func(w http.ResponseWriter, r *http.Request) (neffos.Socket, error) {
+		socket, err := upgrader(w, r)
+		if err != nil {
+			return nil, err
+		}
+
+		return q.newSocket(socket), nil
+	}
+}
+
+// Metrics returns a snapshot of the queue's metrics.
+func (q *WriteQueue) Metrics() WriteQueueMetrics {
+	return WriteQueueMetrics{
+		Queued:  atomic.LoadUint64(&q.queued),
+		Written: atomic.LoadUint64(&q.written),
+		Dropped: atomic.LoadUint64(&q.dropped),
+		Closed:  atomic.LoadUint64(&q.closed),
+		Pending: atomic.LoadInt64(&q.pending),
+	}
+}
+
+type queuedMessage struct {
+	body    []byte
+	binary  bool
+	timeout time.Duration
+}
+
+// queuedSocket queues the writes of a socket.
+type queuedSocket struct {
+	neffos.Socket
+	q *WriteQueue
+
+	mu        sync.Mutex // protects the DropOldest's dequeue.
+	messages  chan queuedMessage
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func (q *WriteQueue) newSocket(socket neffos.Socket) *queuedSocket {
+	s := &queuedSocket{
+		Socket:   socket,
+		q:        q,
+		messages: make(chan queuedMessage, q.opts.Size),
+		done:     make(chan struct{}),
+	}
+
+	go s.writeLoop()
+	return s
+}
+
+func (s *queuedSocket) writeLoop() {
+	for {
+		select {
+		case <-s.done:
+			// release the pending messages.
+			for {
+				select {
+				case <-s.messages:
+					atomic.AddInt64(&s.q.pending, -1)
+				default:
+					return
+				}
+			}
+		case m := <-s.messages:
+			atomic.AddInt64(&s.q.pending, -1)
+
+			var err error
+			if m.binary {
+				err = s.Socket.WriteBinary(m.body, m.timeout)
+			} else {
+				err = s.Socket.WriteText(m.body, m.timeout)
+			}
+
+			if err != nil {
+				s.close()
+				continue
+			}
+
+			atomic.AddUint64(&s.q.written, 1)
+		}
+	}
+}
+
+func (s *queuedSocket) close() {
+	s.closeOnce.Do(func() {
+		close(s.done)
+		s.Socket.NetConn().Close()
+	})
+}
+
+func (s *queuedSocket) isClosed() bool {
+	select {
+	case <-s.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// NetConn returns the underline net connection,
+// its Close stops the queue too.
+func (s *queuedSocket) NetConn() net.Conn {
+	return &queuedNetConn{Conn: s.Socket.NetConn(), s: s}
+}
+
+type queuedNetConn struct {
+	net.Conn
+	s *queuedSocket
+}
+
+func (c *queuedNetConn) Close() error {
+	c.s.close()
+	return nil
+}
+
+// WriteBinary queues a binary message.
+func (s *queuedSocket) WriteBinary(body []byte, timeout time.Duration) error {
+	return s.enqueue(queuedMessage{body: body, binary: true, timeout: timeout})
+}
+
+// WriteText queues a text message.
+func (s *queuedSocket) WriteText(body []byte, timeout time.Duration) error {
+	return s.enqueue(queuedMessage{body: body, timeout: timeout})
+}
+
+func (s *queuedSocket) enqueue(m queuedMessage) error {
+	if s.isClosed() {
+		return io.EOF // let the connection close itself.
+	}
+
+	if s.push(m) {
+		return nil
+	}
+
+	switch s.q.opts.Policy {
+	case CloseSlowConsumer:
+		atomic.AddUint64(&s.q.closed, 1)
+		s.close()
+		return io.EOF
+	case BlockWithTimeout:
+		timer := time.NewTimer(s.q.opts.Timeout)
+		defer timer.Stop()
+
+		atomic.AddInt64(&s.q.pending, 1)
+		select {
+		case s.messages <- m:
+			atomic.AddUint64(&s.q.queued, 1)
+			return nil
+		case <-s.done:
+			atomic.AddInt64(&s.q.pending, -1)
+			return io.EOF
+		case <-timer.C:
+			atomic.AddInt64(&s.q.pending, -1)
+			atomic.AddUint64(&s.q.dropped, 1)
+			return ErrWriteQueueFull
+		}
+	default: // DropOldest.
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		for !s.push(m) {
+			select {
+			case <-s.messages:
+				atomic.AddInt64(&s.q.pending, -1)
+				atomic.AddUint64(&s.q.dropped, 1)
+			default:
+			}
+		}
+
+		return nil
+	}
+}
+
+// push queues the "m" message if there is room for it.
+func (s *queuedSocket) push(m queuedMessage) bool {
+	atomic.AddInt64(&s.q.pending, 1)
+	select {
+	case s.messages <- m:
+		atomic.AddUint64(&s.q.queued, 1)
+		return true
+	default:
+		atomic.AddInt64(&s.q.pending, -1)
+		return false
+	}
+}
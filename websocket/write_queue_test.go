@@ -0,0 +1,168 @@
+package websocket_test
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12/websocket"
+
+	"github.com/kataras/neffos"
+)
+
+// stalledSocket blocks its writes until it's released.
+type stalledSocket struct {
+	conn    net.Conn
+	release chan struct{}
+
+	mu      sync.Mutex
+	written []string
+}
+
+func newStalledSocket() *stalledSocket {
+	conn, _ := net.Pipe()
+	return &stalledSocket{conn: &closeRecorder{Conn: conn}, release: make(chan struct{})}
+}
+
+type closeRecorder struct {
+	net.Conn
+	closed chan struct{}
+	once   sync.Once
+}
+
+func (c *closeRecorder) Close() error {
+	c.once.Do(func() {
+		if c.closed != nil {
+			close(c.closed)
+		}
+	})
+	return c.Conn.Close()
+}
+
+func (s *stalledSocket) NetConn() net.Conn      { return s.conn }
+func (s *stalledSocket) Request() *http.Request { return nil }
+func (s *stalledSocket) ReadData(time.Duration) ([]byte, neffos.MessageType, error) {
+	return nil, 0, io.EOF
+}
+func (s *stalledSocket) WriteBinary(body []byte, timeout time.Duration) error {
+	return s.WriteText(body, timeout)
+}
+func (s *stalledSocket) WriteText(body []byte, _ time.Duration) error {
+	<-s.release
+	s.mu.Lock()
+	s.written = append(s.written, string(body))
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *stalledSocket) getWritten() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.written...)
+}
+
+func TestWriteQueue(t *testing.T) {
+	newQueue := func(opts websocket.WriteQueueOptions) (*websocket.WriteQueue, *stalledSocket, neffos.Socket) {
+		t.Helper()
+
+		stalled := newStalledSocket()
+		queue := websocket.NewWriteQueue(opts)
+		socket, err := queue.Upgrader(func(http.ResponseWriter, *http.Request) (neffos.Socket, error) {
+			return stalled, nil
+		})(nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The first message is in-flight, the writer is stalled.
+		if err = socket.WriteText([]byte("1"), 0); err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 100 && queue.Metrics().Pending != 0; i++ {
+			time.Sleep(5 * time.Millisecond)
+		}
+
+		return queue, stalled, socket
+	}
+
+	t.Run("drop oldest", func(t *testing.T) {
+		queue, stalled, socket := newQueue(websocket.WriteQueueOptions{Size: 2})
+		for _, body := range []string{"2", "3", "4"} {
+			if err := socket.WriteText([]byte(body), 0); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if m := queue.Metrics(); m.Dropped != 1 || m.Pending != 2 || m.Queued != 4 {
+			t.Fatalf("unexpected metrics: %#+v", m)
+		}
+
+		close(stalled.release)
+		for i := 0; i < 100 && queue.Metrics().Written != 3; i++ {
+			time.Sleep(5 * time.Millisecond)
+		}
+
+		if got := stalled.getWritten(); len(got) != 3 || got[0] != "1" || got[1] != "3" || got[2] != "4" {
+			t.Fatalf("expected the oldest queued message to be dropped but got: %v", got)
+		}
+	})
+
+	t.Run("close", func(t *testing.T) {
+		queue, stalled, socket := newQueue(websocket.WriteQueueOptions{Size: 1, Policy: websocket.CloseSlowConsumer})
+		defer close(stalled.release)
+
+		closed := make(chan struct{})
+		stalled.conn.(*closeRecorder).closed = closed
+
+		if err := socket.WriteText([]byte("2"), 0); err != nil {
+			t.Fatal(err)
+		}
+		if err := socket.WriteText([]byte("3"), 0); err != io.EOF {
+			t.Fatalf("expected io.EOF but got: %v", err)
+		}
+
+		select {
+		case <-closed:
+		case <-time.After(time.Second):
+			t.Fatal("expected the connection to be closed")
+		}
+
+		if m := queue.Metrics(); m.Closed != 1 {
+			t.Fatalf("unexpected metrics: %#+v", m)
+		}
+		if err := socket.WriteText([]byte("4"), 0); err != io.EOF {
+			t.Fatalf("expected io.EOF after close but got: %v", err)
+		}
+	})
+
+	t.Run("block with timeout", func(t *testing.T) {
+		queue, stalled, socket := newQueue(websocket.WriteQueueOptions{Size: 1, Policy: websocket.BlockWithTimeout, Timeout: 50 * time.Millisecond})
+
+		if err := socket.WriteText([]byte("2"), 0); err != nil {
+			t.Fatal(err)
+		}
+
+		start := time.Now()
+		if err := socket.WriteText([]byte("3"), 0); err != websocket.ErrWriteQueueFull {
+			t.Fatalf("expected ErrWriteQueueFull but got: %v", err)
+		}
+		if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+			t.Fatalf("expected the write to block for the timeout but it returned after: %s", elapsed)
+		}
+
+		if m := queue.Metrics(); m.Dropped != 1 || m.Pending != 1 {
+			t.Fatalf("unexpected metrics: %#+v", m)
+		}
+
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			close(stalled.release)
+		}()
+		if err := socket.WriteText([]byte("4"), 0); err != nil {
+			t.Fatalf("expected the write to wait for room but got: %v", err)
+		}
+	})
+}